@@ -3,26 +3,36 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"user-risk-system/pkg/auth"
 	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
 	"user-risk-system/pkg/validator"
 	pb_user "user-risk-system/proto/user"
 )
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	userClient pb_user.UserServiceClient
-	jwtManager *auth.JWTManager
+	userClient     pb_user.UserServiceClient
+	jwtManager     *auth.JWTManager
+	trustedProxies []*net.IPNet // Proxies whose X-Forwarded-For is trusted, see clientInfo
 }
 
 // NewAuthHandler creates a new authentication handler with user service client and JWT manager
-func NewAuthHandler(userClient pb_user.UserServiceClient, jwtManager *auth.JWTManager) *AuthHandler {
+// trustedProxies are the proxies in front of the gateway, requests from anyone else are taken at their remote address
+func NewAuthHandler(userClient pb_user.UserServiceClient, jwtManager *auth.JWTManager, trustedProxies []*net.IPNet) *AuthHandler {
 	return &AuthHandler{
-		userClient: userClient,
-		jwtManager: jwtManager,
+		userClient:     userClient,
+		jwtManager:     jwtManager,
+		trustedProxies: trustedProxies,
 	}
 }
 
@@ -39,6 +49,7 @@ type RegisterRequest struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
 	Phone     string `json:"phone"`
+	Locale    string `json:"locale"`
 }
 
 // AuthResponse represents the response payload for authentication endpoints
@@ -85,26 +96,32 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	grpcReq := &pb_user.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   h.clientInfo(r),
 	}
 
+	// The user service reports failures only via gRPC status, see UserService in user.proto
 	grpcResp, err := h.userClient.Login(ctx, grpcReq)
 	if err != nil {
-		errors.ErrAuthenticationFailed.SendJSON(w)
-		return
-	}
-
-	if grpcResp.Error != "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": grpcResp.Error})
+		switch status.Code(err) {
+		case codes.NotFound, codes.Unauthenticated:
+			// Same response for unknown email and wrong password to avoid account enumeration
+			errors.ErrAuthenticationFailed.SendJSON(w)
+		case codes.PermissionDenied:
+			// The message names the reason, e.g. a risk suspension or a closed account
+			errors.ErrUserInactive.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Login failed").SendJSON(w)
+		}
 		return
 	}
 
 	// Generate JWT token
-	token, err := h.jwtManager.GenerateToken(
+	token, err := h.jwtManager.GenerateSessionToken(
 		grpcResp.User.Id,
 		grpcResp.User.Email,
+		grpcResp.User.OrgId,
 		grpcResp.User.Roles,
+		grpcResp.SessionId,
 	)
 	if err != nil {
 		errors.ErrInternalServerError.WithMessage("Failed to generate token").SendJSON(w)
@@ -120,13 +137,18 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Roles:      grpcResp.User.Roles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
 	}
 
 	response := AuthResponse{
-		User:        user,
-		AccessToken: token,
-		ExpiresAt:   time.Now().Add(24 * time.Hour), // Should match JWT expiry
+		User:         user,
+		AccessToken:  token,
+		RefreshToken: grpcResp.RefreshToken,
+		ExpiresAt:    time.Now().Add(24 * time.Hour), // Should match JWT expiry
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -173,25 +195,26 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
+		Locale:    req.Locale,
+		Client:    h.clientInfo(r),
 	}
 
 	grpcResp, err := h.userClient.Register(ctx, grpcReq)
 	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			errors.ErrEmailExists.SendJSON(w)
+			return
+		}
 		errors.NewAppError("REGISTRATION_FAILED", "Registration failed", "").SendJSON(w)
 		return
 	}
 
-	if grpcResp.Error != "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": grpcResp.Error})
-		return
-	}
-
-	token, err := h.jwtManager.GenerateToken(
+	token, err := h.jwtManager.GenerateSessionToken(
 		grpcResp.User.Id,
 		grpcResp.User.Email,
+		grpcResp.User.OrgId,
 		grpcResp.User.Roles,
+		grpcResp.SessionId,
 	)
 	if err != nil {
 		errors.ErrInvalidToken.SendJSON(w)
@@ -207,13 +230,18 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Roles:      grpcResp.User.Roles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
 	}
 
 	response := AuthResponse{
-		User:        user,
-		AccessToken: token,
-		ExpiresAt:   time.Now().Add(24 * time.Hour),
+		User:         user,
+		AccessToken:  token,
+		RefreshToken: grpcResp.RefreshToken,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -221,7 +249,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// RefreshToken generates a new access token from a valid refresh token
+// RefreshToken generates a new access token from a session refresh token and rotates the refresh token
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req RefreshTokenRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -229,15 +257,47 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newToken, err := h.jwtManager.RefreshToken(req.RefreshToken)
+	if req.RefreshToken == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("Refresh token is required").SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.RefreshSession(ctx, &pb_user.RefreshSessionRequest{
+		RefreshToken: req.RefreshToken,
+		Client:       h.clientInfo(r),
+	})
 	if err != nil {
-		errors.ErrInvalidToken.SendJSON(w)
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.NotFound:
+			errors.ErrInvalidToken.SendJSON(w)
+		case codes.PermissionDenied:
+			// The message names the reason, e.g. a risk suspension or a closed account
+			errors.ErrUserInactive.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to refresh session").SendJSON(w)
+		}
+		return
+	}
+
+	newToken, err := h.jwtManager.GenerateSessionToken(
+		grpcResp.User.Id,
+		grpcResp.User.Email,
+		grpcResp.User.OrgId,
+		grpcResp.User.Roles,
+		grpcResp.SessionId,
+	)
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to generate token").SendJSON(w)
 		return
 	}
 
 	response := map[string]interface{}{
-		"access_token": newToken,
-		"expires_at":   time.Now().Add(24 * time.Hour),
+		"access_token":  newToken,
+		"refresh_token": grpcResp.RefreshToken,
+		"expires_at":    time.Now().Add(24 * time.Hour),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -247,8 +307,12 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // GetProfile retrieves the authenticated user's profile information
 func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	// User info is already in context from middleware
-	userID := r.Context().Value("user_id").(string)
-	userRoles := r.Context().Value("user_roles").([]string)
+	userID, ok := scontext.UserID(r.Context())
+	if !ok {
+		errors.ErrInvalidToken.SendJSON(w)
+		return
+	}
+	userRoles, _ := scontext.Roles(r.Context())
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
@@ -260,11 +324,6 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if grpcResp.Error != "" {
-		errors.ErrInternalServerError.WithMessage(grpcResp.Error).SendJSON(w)
-		return
-	}
-
 	user := &UserResponse{
 		ID:         grpcResp.User.Id,
 		Email:      grpcResp.User.Email,
@@ -274,9 +333,214 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		Roles:      userRoles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
+		RecentLogins:  make([]LoginResponse, 0, len(grpcResp.RecentLogins)),
+	}
+	for _, login := range grpcResp.RecentLogins {
+		user.RecentLogins = append(user.RecentLogins, LoginResponse{
+			IPAddress: login.IpAddress,
+			UserAgent: login.UserAgent,
+			CreatedAt: login.CreatedAt.AsTime(),
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
+
+// SessionResponse represents one of the user's active login sessions
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // Session of the token making the request
+}
+
+// ListSessions returns the authenticated user's active sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.ListSessions(ctx, &pb_user.ListSessionsRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			errors.ErrInvalidToken.SendJSON(w)
+			return
+		}
+		errors.ErrInternalServerError.WithMessage("Failed to list sessions").SendJSON(w)
+		return
+	}
+
+	sessions := make([]SessionResponse, 0, len(grpcResp.Sessions))
+	for _, session := range grpcResp.Sessions {
+		sessions = append(sessions, SessionResponse{
+			ID:         session.Id,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IpAddress,
+			LastSeenAt: session.LastSeenAt.AsTime(),
+			CreatedAt:  session.CreatedAt.AsTime(),
+			ExpiresAt:  session.ExpiresAt.AsTime(),
+			Current:    session.Current,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession ends one of the authenticated user's sessions, its refresh token stops working
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	if sessionID == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("Session ID is required").SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	_, err := h.userClient.RevokeSession(ctx, &pb_user.RevokeSessionRequest{SessionId: sessionID})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrSessionNotFound.SendJSON(w)
+		case codes.Unauthenticated:
+			errors.ErrInvalidToken.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to revoke session").SendJSON(w)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePasswordRequest represents the request payload for changing the authenticated user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangePassword replaces the authenticated user's password, recently used passwords are rejected
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	v := validator.New()
+	v.Required("current_password", req.CurrentPassword).
+		Required("new_password", req.NewPassword).
+		MinLength("new_password", req.NewPassword, 8)
+
+	if !v.IsValid() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": v.Errors(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	_, err := h.userClient.ChangePassword(ctx, &pb_user.ChangePasswordRequest{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			if status.Convert(err).Message() == errors.ErrPasswordReused.Message {
+				errors.ErrPasswordReused.SendJSON(w)
+			} else {
+				errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+			}
+		case codes.Unauthenticated:
+			errors.ErrInvalidPassword.WithMessage("Current password is incorrect").SendJSON(w)
+		case codes.Aborted:
+			errors.ErrConcurrentUpdate.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to change password").SendJSON(w)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Limits match the size of the session user_agent and ip_address columns
+const (
+	maxUserAgentLength = 512
+	maxIPAddressLength = 64
+)
+
+// clientInfo describes the calling device for the session created or refreshed by a request
+func (h *AuthHandler) clientInfo(r *http.Request) *pb_user.ClientInfo {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	ip := h.clientIP(r)
+	if len(ip) > maxIPAddressLength {
+		ip = ip[:maxIPAddressLength]
+	}
+
+	return &pb_user.ClientInfo{
+		UserAgent: userAgent,
+		IpAddress: ip,
+	}
+}
+
+// clientIP returns the request's remote address, or when that is a trusted proxy the address X-Forwarded-For
+// names before the trusted proxies. The header is read from the right, anything left of the first untrusted
+// address may be forged by the client.
+func (h *AuthHandler) clientIP(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	if !h.trustedProxy(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !h.trustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// trustedProxy reports whether ip belongs to one of the trusted proxies.
+func (h *AuthHandler) trustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range h.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/errors"
+	pb_user "user-risk-system/proto/user"
+)
+
+// loginClient fails every login with err.
+type loginClient struct {
+	pb_user.UserServiceClient
+	err error
+}
+
+func (c *loginClient) Login(context.Context, *pb_user.LoginRequest, ...grpc.CallOption) (*pb_user.LoginResponse, error) {
+	return nil, c.err
+}
+
+func TestClientIPTrustsForwardedForFromTrustedProxiesOnly(t *testing.T) {
+	cfg := &config.Config{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}}
+	h := NewAuthHandler(nil, nil, cfg.TrustedProxyNetworks())
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:4000", nil, "203.0.113.7"},
+		{"untrusted client forging the header", "203.0.113.7:4000", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3:4000", []string{"198.51.100.9"}, "198.51.100.9"},
+		{"chain of trusted proxies", "192.0.2.1:4000", []string{"198.51.100.9, 10.0.0.5"}, "198.51.100.9"},
+		{"client prepending a forged hop", "10.1.2.3:4000", []string{"1.2.3.4, 198.51.100.9"}, "198.51.100.9"},
+		{"repeated headers", "10.1.2.3:4000", []string{"1.2.3.4", "198.51.100.9"}, "198.51.100.9"},
+		{"garbage hop", "10.1.2.3:4000", []string{"not-an-ip"}, "10.1.2.3"},
+		{"trusted proxy without header", "10.1.2.3:4000", nil, "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/auth/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := h.clientInfo(r).IpAddress; got != tt.want {
+				t.Errorf("client IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginFailureStatusCodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"unknown email", errors.ErrUserNotFound.GRPCStatus().Err(), http.StatusUnauthorized, errors.ErrAuthenticationFailed.Message},
+		{"invalid password", errors.ErrInvalidPassword.GRPCStatus().Err(), http.StatusUnauthorized, errors.ErrAuthenticationFailed.Message},
+		{"inactive account", errors.ErrUserInactive.GRPCStatus().Err(), http.StatusForbidden, errors.ErrUserInactive.Message},
+		{"suspended account", errors.ErrUserInactive.WithMessage("Account is suspended by an administrator").GRPCStatus().Err(), http.StatusForbidden, "Account is suspended by an administrator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAuthHandler(&loginClient{err: tt.err}, nil, nil)
+
+			w := httptest.NewRecorder()
+			h.Login(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":"user@example.com","password":"password123"}`)))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Error != tt.wantMessage {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sendgrid/sendgrid-go/helpers/eventwebhook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// NotificationHandler manages administrative notification endpoints and provider webhooks
+type NotificationHandler struct {
+	notificationClient pb_notification.NotificationServiceClient
+	jwtManager         *auth.JWTManager // Issues the service token delivery events are forwarded with
+	sendGridPublicKey  *ecdsa.PublicKey // nil disables the SendGrid event webhook
+}
+
+// NewNotificationHandler creates a new notification handler with notification service client
+// and the base64 SendGrid event webhook verification key (empty disables the webhook)
+func NewNotificationHandler(notificationClient pb_notification.NotificationServiceClient, jwtManager *auth.JWTManager, sendGridWebhookKey string) *NotificationHandler {
+	handler := &NotificationHandler{
+		notificationClient: notificationClient,
+		jwtManager:         jwtManager,
+	}
+
+	if sendGridWebhookKey != "" {
+		publicKey, err := eventwebhook.ConvertPublicKeyBase64ToECDSA(sendGridWebhookKey)
+		if err != nil {
+			log.Printf("Invalid SendGrid webhook public key, webhook disabled: %v", err)
+		} else {
+			handler.sendGridPublicKey = publicKey
+		}
+	}
+
+	return handler
+}
+
+// sendGridEvent represents a single event in a SendGrid event webhook payload
+type sendGridEvent struct {
+	Email          string `json:"email"`
+	Timestamp      int64  `json:"timestamp"`
+	Event          string `json:"event"`
+	Reason         string `json:"reason"`
+	SGMessageID    string `json:"sg_message_id"`
+	NotificationID string `json:"notification_id"` // Custom arg set when the email was sent
+}
+
+// BroadcastRequest represents the payload for broadcasting a notification to a user segment
+type BroadcastRequest struct {
+	Segment   string `json:"segment"` // ALL, ROLE, RISK_LEVEL
+	Role      string `json:"role"`
+	RiskLevel string `json:"risk_level"`
+	Type      string `json:"type" validate:"required"`
+	Message   string `json:"message" validate:"required"`
+	Channel   string `json:"channel"`
+}
+
+// BroadcastResponse represents the response for a broadcast request
+type BroadcastResponse struct {
+	BroadcastID    string `json:"broadcast_id,omitempty"`
+	RecipientCount int32  `json:"recipient_count"`
+	Success        bool   `json:"success"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Broadcast enqueues a notification for every user in a segment (admin only)
+func (h *NotificationHandler) Broadcast(w http.ResponseWriter, r *http.Request) {
+	var req BroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	v := validator.New()
+	v.Required("type", req.Type).
+		Required("message", req.Message)
+
+	switch strings.ToUpper(req.Segment) {
+	case "ROLE":
+		v.Required("role", req.Role)
+	case "RISK_LEVEL":
+		v.Required("risk_level", req.RiskLevel)
+	}
+
+	if !v.IsValid() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": v.Errors(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcReq := &pb_notification.BroadcastNotificationRequest{
+		Segment:   req.Segment,
+		Role:      req.Role,
+		RiskLevel: req.RiskLevel,
+		Type:      req.Type,
+		Message:   req.Message,
+		Channel:   req.Channel,
+	}
+
+	grpcResp, err := h.notificationClient.BroadcastNotification(ctx, grpcReq)
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to broadcast notification").WithDetails(err.Error()).SendJSON(w)
+		return
+	}
+
+	response := BroadcastResponse{
+		BroadcastID:    grpcResp.BroadcastId,
+		RecipientCount: grpcResp.RecipientCount,
+		Success:        grpcResp.Success,
+		Error:          grpcResp.Error,
+	}
+
+	statusCode := http.StatusAccepted
+	if grpcResp.Error != "" {
+		statusCode = http.StatusBadRequest
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// TemplatePreviewResponse represents a rendered email template
+type TemplatePreviewResponse struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	HTML     string `json:"html"`
+}
+
+// TemplateInfoResponse describes an email template and the data fields it references
+type TemplateInfoResponse struct {
+	Name    string   `json:"name"`
+	Source  string   `json:"source"` // file or embedded
+	Fields  []string `json:"fields"`
+	Locales []string `json:"locales"`
+}
+
+// ListTemplates returns the available email templates and their data fields (admin only)
+func (h *NotificationHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.ListTemplates(ctx, &pb_notification.ListTemplatesRequest{})
+	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			errors.ErrInsufficientRole.SendJSON(w)
+			return
+		}
+		errors.ErrInternalServerError.WithMessage("Failed to list templates").WithDetails(err.Error()).SendJSON(w)
+		return
+	}
+
+	list := make([]TemplateInfoResponse, 0, len(grpcResp.Templates))
+	for _, info := range grpcResp.Templates {
+		item := TemplateInfoResponse{
+			Name:    info.Name,
+			Source:  info.Source,
+			Fields:  info.Fields,
+			Locales: info.Locales,
+		}
+		if item.Fields == nil {
+			item.Fields = []string{}
+		}
+		if item.Locales == nil {
+			item.Locales = []string{}
+		}
+		list = append(list, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"templates": list,
+	})
+}
+
+// PreviewTemplate renders an email template with sample data from the query string (admin only)
+// every query parameter except locale is a template field, e.g. ?first_name=Ada&risk_level=HIGH
+func (h *NotificationHandler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	grpcReq := &pb_notification.PreviewTemplateRequest{
+		TemplateName: chi.URLParam(r, "name"),
+		SampleData:   make(map[string]string),
+	}
+	for key, values := range r.URL.Query() {
+		if key == "locale" {
+			grpcReq.Locale = values[0]
+			continue
+		}
+		grpcReq.SampleData[key] = values[0]
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.PreviewTemplate(ctx, grpcReq)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrTemplateNotFound.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to preview template").WithDetails(err.Error()).SendJSON(w)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(TemplatePreviewResponse{
+		Template: grpcReq.TemplateName,
+		Locale:   grpcResp.Locale,
+		Subject:  grpcResp.Subject,
+		HTML:     grpcResp.Html,
+	})
+}
+
+// TestSendRequest represents the payload for sending a test message through a provider
+type TestSendRequest struct {
+	Channel   string `json:"channel" validate:"required"` // EMAIL or SMS
+	Recipient string `json:"recipient" validate:"required"`
+}
+
+// TestSendResponse represents the outcome of a test send and the provider's response
+type TestSendResponse struct {
+	Channel           string `json:"channel"`
+	Provider          string `json:"provider"`
+	Success           bool   `json:"success"`
+	Error             string `json:"error,omitempty"`
+	ProviderMessageID string `json:"provider_message_id,omitempty"`
+	StatusCode        int32  `json:"status_code,omitempty"`
+	LatencyMs         int64  `json:"latency_ms"`
+}
+
+// TestSend sends a canned test message through the configured email or SMS provider (admin only)
+// a provider failure is reported with success false, the request itself still succeeds
+func (h *NotificationHandler) TestSend(w http.ResponseWriter, r *http.Request) {
+	var req TestSendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.TestSend(ctx, &pb_notification.TestSendRequest{
+		Channel:   req.Channel,
+		Recipient: req.Recipient,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.ResourceExhausted:
+			errors.ErrRateLimitExceeded.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to send test notification").WithDetails(err.Error()).SendJSON(w)
+		}
+		return
+	}
+
+	result := grpcResp.GetResult()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(TestSendResponse{
+		Channel:           result.GetChannel(),
+		Provider:          result.GetProvider(),
+		Success:           result.GetSuccess(),
+		Error:             result.GetError(),
+		ProviderMessageID: result.GetProviderMessageId(),
+		StatusCode:        result.GetStatusCode(),
+		LatencyMs:         result.GetLatencyMs(),
+	})
+}
+
+// NotificationEventResponse represents one status transition of a notification
+type NotificationEventResponse struct {
+	Status            string    `json:"status"`
+	Channel           string    `json:"channel,omitempty"`
+	Provider          string    `json:"provider,omitempty"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	Details           string    `json:"details,omitempty"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// NotificationStatusResponse represents the current status of a notification and its delivery timeline
+type NotificationStatusResponse struct {
+	NotificationID string                      `json:"notification_id"`
+	Status         string                      `json:"status"`
+	Events         []NotificationEventResponse `json:"events"`
+}
+
+// GetNotificationStatus returns a notification's status with every transition in recorded order (admin only)
+func (h *NotificationHandler) GetNotificationStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.GetNotificationStatus(ctx, &pb_notification.GetNotificationStatusRequest{
+		NotificationId: chi.URLParam(r, "notification_id"),
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrNotificationNotFound.SendJSON(w)
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to get notification status").WithDetails(err.Error()).SendJSON(w)
+		}
+		return
+	}
+
+	response := NotificationStatusResponse{
+		NotificationID: grpcResp.NotificationId,
+		Status:         grpcResp.Status,
+		Events:         make([]NotificationEventResponse, 0, len(grpcResp.Events)),
+	}
+	for _, event := range grpcResp.Events {
+		response.Events = append(response.Events, NotificationEventResponse{
+			Status:            event.Status,
+			Channel:           event.Channel,
+			Provider:          event.Provider,
+			ProviderMessageID: event.ProviderMessageId,
+			Details:           event.Details,
+			OccurredAt:        event.OccurredAt.AsTime(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SuppressionResponse represents a recipient notifications are no longer sent to
+type SuppressionResponse struct {
+	Recipient string    `json:"recipient"`
+	Channel   string    `json:"channel"`
+	Reason    string    `json:"reason"` // BOUNCE, COMPLAINT, MANUAL
+	Details   string    `json:"details,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddSuppressionRequest represents the payload for manually suppressing a recipient
+type AddSuppressionRequest struct {
+	Recipient string `json:"recipient" validate:"required"`
+	Channel   string `json:"channel"` // EMAIL or SMS, derived from the recipient when empty
+	Details   string `json:"details"`
+}
+
+// ListSuppressions returns a page of suppressed recipients, newest first (admin only)
+// supports ?limit (at most 500) and ?offset
+func (h *NotificationHandler) ListSuppressions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	grpcReq := &pb_notification.ListSuppressionsRequest{}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 500 {
+			errors.ErrValidationFailed.WithMessage("limit must be a number between 1 and 500").SendJSON(w)
+			return
+		}
+		grpcReq.Limit = int32(limit)
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			errors.ErrValidationFailed.WithMessage("offset must be a non-negative number").SendJSON(w)
+			return
+		}
+		grpcReq.Offset = int32(offset)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.ListSuppressions(ctx, grpcReq)
+	if err != nil {
+		sendSuppressionError(w, err, "Failed to list suppressions")
+		return
+	}
+
+	list := make([]SuppressionResponse, 0, len(grpcResp.Suppressions))
+	for _, suppression := range grpcResp.Suppressions {
+		list = append(list, suppressionResponse(suppression))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"suppressions": list,
+		"total":        grpcResp.Total,
+	})
+}
+
+// AddSuppression suppresses an email address or phone number (admin only)
+func (h *NotificationHandler) AddSuppression(w http.ResponseWriter, r *http.Request) {
+	var req AddSuppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.AddSuppression(ctx, &pb_notification.AddSuppressionRequest{
+		Recipient: req.Recipient,
+		Channel:   req.Channel,
+		Details:   req.Details,
+	})
+	if err != nil {
+		sendSuppressionError(w, err, "Failed to add suppression")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(suppressionResponse(grpcResp))
+}
+
+// RemoveSuppression lifts the suppression of a recipient (admin only)
+func (h *NotificationHandler) RemoveSuppression(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	_, err := h.notificationClient.RemoveSuppression(ctx, &pb_notification.RemoveSuppressionRequest{
+		Recipient: chi.URLParam(r, "recipient"),
+	})
+	if err != nil {
+		sendSuppressionError(w, err, "Failed to remove suppression")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendSuppressionError writes the gateway error of a failed suppression call
+func sendSuppressionError(w http.ResponseWriter, err error, message string) {
+	switch status.Code(err) {
+	case codes.NotFound:
+		errors.ErrSuppressionNotFound.SendJSON(w)
+	case codes.InvalidArgument:
+		errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+	case codes.PermissionDenied:
+		errors.ErrInsufficientRole.SendJSON(w)
+	case codes.Unimplemented:
+		errors.NewAppError("SUPPRESSIONS_DISABLED", "Suppression list is not enabled", "").SendJSON(w)
+	default:
+		errors.ErrInternalServerError.WithMessage(message).WithDetails(err.Error()).SendJSON(w)
+	}
+}
+
+// suppressionResponse converts a suppression from the notification service
+func suppressionResponse(suppression *pb_notification.Suppression) SuppressionResponse {
+	return SuppressionResponse{
+		Recipient: suppression.Recipient,
+		Channel:   suppression.Channel,
+		Reason:    suppression.Reason,
+		Details:   suppression.Details,
+		CreatedBy: suppression.CreatedBy,
+		CreatedAt: time.Unix(suppression.CreatedAt, 0).UTC(),
+	}
+}
+
+// SendGridWebhook ingests signed SendGrid delivery events (delivered, bounce, open, click, spamreport)
+// and forwards them to the notification service to update notification status
+func (h *NotificationHandler) SendGridWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.sendGridPublicKey == nil {
+		errors.NewAppError("WEBHOOK_DISABLED", "SendGrid webhook verification is not configured", "").SendJSON(w)
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	signature := r.Header.Get(eventwebhook.VerificationHTTPHeader)
+	timestamp := r.Header.Get(eventwebhook.TimestampHTTPHeader)
+	valid, err := eventwebhook.VerifySignature(h.sendGridPublicKey, payload, signature, timestamp)
+	if err != nil || !valid {
+		errors.ErrAuthenticationFailed.WithMessage("Invalid webhook signature").SendJSON(w)
+		return
+	}
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	grpcReq := &pb_notification.RecordDeliveryEventsRequest{
+		Provider: "SENDGRID",
+	}
+	for _, event := range events {
+		grpcReq.Events = append(grpcReq.Events, &pb_notification.DeliveryEvent{
+			NotificationId:    event.NotificationID,
+			Event:             event.Event,
+			Email:             event.Email,
+			Timestamp:         event.Timestamp,
+			Reason:            event.Reason,
+			ProviderMessageId: event.SGMessageID,
+		})
+	}
+
+	// Delivery events suppress recipients, the notification service only accepts them from another service
+	ctx, err := h.jwtManager.ServiceContext(r.Context(), "api-gateway")
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to record delivery events").WithDetails(err.Error()).SendJSON(w)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.notificationClient.RecordDeliveryEvents(ctx, grpcReq)
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to record delivery events").WithDetails(err.Error()).SendJSON(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]int32{
+		"processed": grpcResp.Processed,
+		"ignored":   grpcResp.Ignored,
+	})
+}
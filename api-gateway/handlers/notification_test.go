@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/eventwebhook"
+	"google.golang.org/grpc"
+
+	"user-risk-system/pkg/auth"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// deliveryClient records the RecordDeliveryEvents calls of the gateway.
+type deliveryClient struct {
+	pb_notification.NotificationServiceClient
+	req   *pb_notification.RecordDeliveryEventsRequest
+	token string
+}
+
+func (c *deliveryClient) RecordDeliveryEvents(ctx context.Context, req *pb_notification.RecordDeliveryEventsRequest, _ ...grpc.CallOption) (*pb_notification.RecordDeliveryEventsResponse, error) {
+	c.req = req
+	c.token, _ = auth.TokenFromContext(ctx)
+	return &pb_notification.RecordDeliveryEventsResponse{Processed: int32(len(req.Events))}, nil
+}
+
+// signedWebhook returns a SendGrid webhook request of payload signed with key.
+func signedWebhook(t *testing.T, key *ecdsa.PrivateKey, payload []byte) *http.Request {
+	t.Helper()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	digest := sha256.Sum256(append([]byte(timestamp), payload...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/notifications/webhooks/sendgrid", bytes.NewReader(payload))
+	r.Header.Set(eventwebhook.VerificationHTTPHeader, base64.StdEncoding.EncodeToString(signature))
+	r.Header.Set(eventwebhook.TimestampHTTPHeader, timestamp)
+	return r
+}
+
+func TestSendGridWebhook(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwtManager := auth.NewJWTManager("test-secret", time.Hour, "user-risk-system")
+	payload := []byte(`[{"email":"bounced@example.com","timestamp":1700000000,"event":"bounce","reason":"550 no such user","notification_id":"n-1"}]`)
+
+	t.Run("signed events are forwarded with a service token", func(t *testing.T) {
+		client := &deliveryClient{}
+		h := NewNotificationHandler(client, jwtManager, base64.StdEncoding.EncodeToString(der))
+
+		w := httptest.NewRecorder()
+		h.SendGridWebhook(w, signedWebhook(t, key, payload))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		if client.req == nil || len(client.req.Events) != 1 || client.req.Events[0].Event != "bounce" || client.req.Events[0].NotificationId != "n-1" {
+			t.Fatalf("forwarded request = %v", client.req)
+		}
+		claims, err := jwtManager.ValidateToken(client.token)
+		if err != nil {
+			t.Fatalf("forwarded token is invalid: %v", err)
+		}
+		if !claims.HasRole(auth.RoleService) {
+			t.Errorf("forwarded token roles = %v, want service", claims.Roles)
+		}
+	})
+
+	t.Run("unsigned events are rejected", func(t *testing.T) {
+		client := &deliveryClient{}
+		h := NewNotificationHandler(client, jwtManager, base64.StdEncoding.EncodeToString(der))
+
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		w := httptest.NewRecorder()
+		h.SendGridWebhook(w, signedWebhook(t, other, payload))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", w.Code)
+		}
+		if client.req != nil {
+			t.Error("events signed with another key were forwarded")
+		}
+	})
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"user-risk-system/pkg/auth"
 	"user-risk-system/pkg/errors"
 	"user-risk-system/pkg/validator"
 	pb_risk "user-risk-system/proto/risk"
@@ -37,6 +42,10 @@ type CreateRiskRuleRequest struct {
 	IsActive      bool    `json:"is_active"`
 	Confidence    float64 `json:"confidence" validate:"min=0,max=1"`
 	ExpiresInDays int32   `json:"expires_in_days"`
+	Priority      int32   `json:"priority" validate:"min=0,max=1000"`
+
+	ExampleMatch   string `json:"example_match,omitempty"`    // Optional input the rule must match
+	ExampleNoMatch string `json:"example_no_match,omitempty"` // Optional input the rule must not match
 }
 
 // CreateRiskRuleResponse represents the response for risk rule creation
@@ -46,6 +55,26 @@ type CreateRiskRuleResponse struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// CreateRiskRulesRequest represents the payload for creating several risk rules at once
+type CreateRiskRulesRequest struct {
+	Rules   []CreateRiskRuleRequest `json:"rules"`
+	Partial bool                    `json:"partial"` // create the valid rules and report the rest instead of failing the batch
+}
+
+// RiskRuleResult reports the outcome for one rule of a bulk creation
+type RiskRuleResult struct {
+	Index   int32  `json:"index"`
+	RuleID  string `json:"rule_id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CreateRiskRulesResponse represents the response for bulk risk rule creation
+type CreateRiskRulesResponse struct {
+	Results []RiskRuleResult `json:"results"`
+	Created int32            `json:"created"`
+}
+
 // CheckRiskRequest represents the payload for risk assessment
 type CheckRiskRequest struct {
 	UserID    string `json:"user_id" validate:"required"`
@@ -62,6 +91,8 @@ type CheckRiskResponse struct {
 	RiskLevel string   `json:"risk_level"`
 	Reason    string   `json:"reason"`
 	Flags     []string `json:"flags"`
+	DryRun    bool     `json:"dry_run,omitempty"`
+	CheckID   string   `json:"check_id,omitempty"`
 	Error     string   `json:"error,omitempty"`
 }
 
@@ -75,6 +106,10 @@ type UpdateRiskRuleRequest struct {
 	IsActive      bool    `json:"is_active"`
 	Confidence    float64 `json:"confidence" validate:"min=0,max=1"`
 	ExpiresInDays int32   `json:"expires_in_days"`
+	Priority      int32   `json:"priority" validate:"min=0,max=1000"`
+
+	ExampleMatch   string `json:"example_match,omitempty"`    // Optional input the rule must match
+	ExampleNoMatch string `json:"example_no_match,omitempty"` // Optional input the rule must not match
 }
 
 // UpdateRiskRuleResponse represents the response for risk rule updates
@@ -83,6 +118,71 @@ type UpdateRiskRuleResponse struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// RiskRuleResponse represents a risk rule with RFC3339 timestamps
+type RiskRuleResponse struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Category       string     `json:"category"`
+	Value          string     `json:"value"`
+	Score          int32      `json:"score"`
+	IsActive       bool       `json:"is_active"`
+	Source         string     `json:"source"`
+	Confidence     float64    `json:"confidence"`
+	Priority       int32      `json:"priority"`
+	ExampleMatch   string     `json:"example_match,omitempty"`
+	ExampleNoMatch string     `json:"example_no_match,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ExpiresAt      *time.Time `json:"expires_at"` // null when the rule never expires
+}
+
+// RecheckUsersRequest represents the cohort filter for re-running risk checks
+type RecheckUsersRequest struct {
+	EmailDomain     string `json:"email_domain,omitempty"`
+	Role            string `json:"role,omitempty"`
+	IncludeInactive bool   `json:"include_inactive,omitempty"`
+	Limit           int32  `json:"limit,omitempty"`
+	Offset          int32  `json:"offset,omitempty"`
+}
+
+// RecheckUsersResponse represents the progress of a cohort recheck
+type RecheckUsersResponse struct {
+	Checked           int32    `json:"checked"`
+	Risky             int32    `json:"risky"`
+	NewlyRisky        int32    `json:"newly_risky"`
+	NewlyRiskyUserIDs []string `json:"newly_risky_user_ids"`
+	Failed            int32    `json:"failed"`
+	NextOffset        int32    `json:"next_offset"`
+	Done              bool     `json:"done"`
+}
+
+// RiskCheckRecordResponse represents a stored risk check in a user's history
+type RiskCheckRecordResponse struct {
+	CheckID      string    `json:"check_id"`
+	IsRisky      bool      `json:"is_risky"`
+	RiskLevel    string    `json:"risk_level"`
+	TotalScore   int32     `json:"total_score"`
+	Reason       string    `json:"reason"`
+	Flags        []string  `json:"flags"`
+	CheckedAt    time.Time `json:"checked_at"`
+	SampleWeight int32     `json:"sample_weight"`
+}
+
+// RiskHistoryResponse represents a page of a user's risk history
+type RiskHistoryResponse struct {
+	Checks     []RiskCheckRecordResponse `json:"checks"`
+	NextCursor string                    `json:"next_cursor"` // Empty on the last page
+}
+
+// ListRiskRulesResponse represents a page of risk rules
+type ListRiskRulesResponse struct {
+	Rules      []RiskRuleResponse `json:"rules"`
+	TotalCount int32              `json:"total_count"`
+	Page       int32              `json:"page"`
+	PageSize   int32              `json:"page_size"`
+}
+
 // CreateRiskRule creates a new risk rule (admin only)
 func (h *RiskHandler) CreateRiskRule(w http.ResponseWriter, r *http.Request) {
 	var req CreateRiskRuleRequest
@@ -94,10 +194,20 @@ func (h *RiskHandler) CreateRiskRule(w http.ResponseWriter, r *http.Request) {
 	v := validator.New()
 	v.Required("name", req.Name).
 		Required("type", req.Type).
-		Required("category", req.Category).
-		Required("value", req.Value).
-		Min("score", float64(req.Score), 1).
-		Max("score", float64(req.Score), 1000)
+		Required("category", req.Category)
+
+	if req.Type != "DISPOSABLE_EMAIL" {
+		v.Required("value", req.Value)
+	}
+	if req.Type == "PATTERN_MATCH" {
+		v.Regex("value", req.Value)
+	}
+
+	v.Min("score", float64(req.Score), 1).
+		Max("score", float64(req.Score), 1000).
+		Min("priority", float64(req.Priority), 0).
+		Max("priority", float64(req.Priority), 1000).
+		Min("expires_in_days", float64(req.ExpiresInDays), 0)
 
 	if req.Confidence != 0 {
 		v.Min("confidence", req.Confidence, 0).Max("confidence", req.Confidence, 1)
@@ -125,11 +235,20 @@ func (h *RiskHandler) CreateRiskRule(w http.ResponseWriter, r *http.Request) {
 		IsActive:      req.IsActive,
 		Confidence:    req.Confidence,
 		ExpiresInDays: req.ExpiresInDays,
+		Priority:      req.Priority,
+
+		ExampleMatch:   req.ExampleMatch,
+		ExampleNoMatch: req.ExampleNoMatch,
 	}
 
 	grpcResp, err := h.riskAdminClient.CreateRiskRule(ctx, grpcReq)
 	if err != nil {
-		errors.ErrInternalServerError.WithMessage("Failed to create risk rule").WithDetails(err.Error()).SendJSON(w)
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to create risk rule").WithDetails(err.Error()).SendJSON(w)
+		}
 		return
 	}
 
@@ -149,6 +268,73 @@ func (h *RiskHandler) CreateRiskRule(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// CreateRiskRules creates a batch of risk rules (admin only)
+// the batch is all-or-nothing unless partial is set, which answers 207 when some rules failed
+func (h *RiskHandler) CreateRiskRules(w http.ResponseWriter, r *http.Request) {
+	var req CreateRiskRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcReq := &pb_risk.CreateRiskRulesRequest{
+		Rules:   make([]*pb_risk.CreateRiskRuleRequest, len(req.Rules)),
+		Partial: req.Partial,
+	}
+	for i, rule := range req.Rules {
+		grpcReq.Rules[i] = &pb_risk.CreateRiskRuleRequest{
+			Name:          rule.Name,
+			Type:          rule.Type,
+			Category:      rule.Category,
+			Value:         rule.Value,
+			Score:         rule.Score,
+			IsActive:      rule.IsActive,
+			Confidence:    rule.Confidence,
+			ExpiresInDays: rule.ExpiresInDays,
+			Priority:      rule.Priority,
+
+			ExampleMatch:   rule.ExampleMatch,
+			ExampleNoMatch: rule.ExampleNoMatch,
+		}
+	}
+
+	grpcResp, err := h.riskAdminClient.CreateRiskRules(ctx, grpcReq)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to create risk rules").SendJSON(w)
+		}
+		return
+	}
+
+	response := CreateRiskRulesResponse{
+		Results: make([]RiskRuleResult, len(grpcResp.Results)),
+		Created: grpcResp.Created,
+	}
+	for i, result := range grpcResp.Results {
+		response.Results[i] = RiskRuleResult{
+			Index:   result.Index,
+			RuleID:  result.RuleId,
+			Success: result.Success,
+			Error:   result.Error,
+		}
+	}
+
+	statusCode := http.StatusCreated
+	if int(grpcResp.Created) < len(grpcResp.Results) {
+		statusCode = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
 // UpdateRiskRule modifies an existing risk rule (admin only)
 func (h *RiskHandler) UpdateRiskRule(w http.ResponseWriter, r *http.Request) {
 	ruleID := chi.URLParam(r, "id")
@@ -166,10 +352,20 @@ func (h *RiskHandler) UpdateRiskRule(w http.ResponseWriter, r *http.Request) {
 	v := validator.New()
 	v.Required("name", req.Name).
 		Required("type", req.Type).
-		Required("category", req.Category).
-		Required("value", req.Value).
-		Min("score", float64(req.Score), 1).
-		Max("score", float64(req.Score), 1000)
+		Required("category", req.Category)
+
+	if req.Type != "DISPOSABLE_EMAIL" {
+		v.Required("value", req.Value)
+	}
+	if req.Type == "PATTERN_MATCH" {
+		v.Regex("value", req.Value)
+	}
+
+	v.Min("score", float64(req.Score), 1).
+		Max("score", float64(req.Score), 1000).
+		Min("priority", float64(req.Priority), 0).
+		Max("priority", float64(req.Priority), 1000).
+		Min("expires_in_days", float64(req.ExpiresInDays), 0)
 
 	if req.Confidence != 0 {
 		v.Min("confidence", req.Confidence, 0).Max("confidence", req.Confidence, 1)
@@ -198,11 +394,20 @@ func (h *RiskHandler) UpdateRiskRule(w http.ResponseWriter, r *http.Request) {
 		IsActive:      req.IsActive,
 		Confidence:    req.Confidence,
 		ExpiresInDays: req.ExpiresInDays,
+		Priority:      req.Priority,
+
+		ExampleMatch:   req.ExampleMatch,
+		ExampleNoMatch: req.ExampleNoMatch,
 	}
 
 	grpcResp, err := h.riskAdminClient.UpdateRiskRule(ctx, grpcReq)
 	if err != nil {
-		errors.ErrInternalServerError.WithMessage("Failed to update risk rule").SendJSON(w)
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to update risk rule").SendJSON(w)
+		}
 		return
 	}
 
@@ -222,7 +427,18 @@ func (h *RiskHandler) UpdateRiskRule(w http.ResponseWriter, r *http.Request) {
 }
 
 // CheckRisk evaluates user data against risk rules
+// ?dry_run=true returns the same result without storing it for analytics
 func (h *RiskHandler) CheckRisk(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			errors.ErrValidationFailed.WithMessage("dry_run must be true or false").SendJSON(w)
+			return
+		}
+		dryRun = parsed
+	}
+
 	var req CheckRiskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errors.ErrInvalidJSON.SendJSON(w)
@@ -259,6 +475,8 @@ func (h *RiskHandler) CheckRisk(w http.ResponseWriter, r *http.Request) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
+		OrgId:     auth.OrgID(r.Context()), // Always the caller's organization, never client supplied
+		DryRun:    dryRun,
 	}
 
 	grpcResp, err := h.riskClient.CheckRisk(ctx, grpcReq)
@@ -278,6 +496,8 @@ func (h *RiskHandler) CheckRisk(w http.ResponseWriter, r *http.Request) {
 		RiskLevel: grpcResp.RiskLevel,
 		Reason:    grpcResp.Reason,
 		Flags:     grpcResp.Flags,
+		DryRun:    grpcResp.DryRun,
+		CheckID:   grpcResp.CheckId,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -340,6 +560,300 @@ func (h *RiskHandler) ListRiskRules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rules := make([]RiskRuleResponse, 0, len(grpcResp.Rules))
+	for _, rule := range grpcResp.Rules {
+		rules = append(rules, RiskRuleResponse{
+			ID:             rule.Id,
+			Name:           rule.Name,
+			Type:           rule.Type,
+			Category:       rule.Category,
+			Value:          rule.Value,
+			Score:          rule.Score,
+			IsActive:       rule.IsActive,
+			Source:         rule.Source,
+			Confidence:     rule.Confidence,
+			Priority:       rule.Priority,
+			ExampleMatch:   rule.ExampleMatch,
+			ExampleNoMatch: rule.ExampleNoMatch,
+			CreatedAt:      rule.CreatedAt.AsTime(),
+			UpdatedAt:      rule.UpdatedAt.AsTime(),
+			ExpiresAt:      optionalTime(rule.ExpiresAt),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListRiskRulesResponse{
+		Rules:      rules,
+		TotalCount: grpcResp.TotalCount,
+		Page:       grpcResp.Page,
+		PageSize:   grpcResp.PageSize,
+	})
+}
+
+// optionalTime converts a timestamp that may be unset, so it encodes as null rather than the unix epoch
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// UpdateDisposableDomainsRequest represents the payload for editing the disposable domain list
+type UpdateDisposableDomainsRequest struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// ListDisposableDomains returns the disposable email domains flagged by DISPOSABLE_EMAIL rules (admin only)
+func (h *RiskHandler) ListDisposableDomains(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.ListDisposableDomains(ctx, &pb_risk.ListDisposableDomainsRequest{})
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to list disposable domains").SendJSON(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"domains":    grpcResp.Domains,
+		"is_default": grpcResp.IsDefault,
+	})
+}
+
+// UpdateDisposableDomains adds and removes disposable email domains (admin only)
+func (h *RiskHandler) UpdateDisposableDomains(w http.ResponseWriter, r *http.Request) {
+	var req UpdateDisposableDomainsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.UpdateDisposableDomains(ctx, &pb_risk.UpdateDisposableDomainsRequest{
+		Add:    req.Add,
+		Remove: req.Remove,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to update disposable domains").SendJSON(w)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"count": grpcResp.Count,
+	})
+}
+
+// GetCacheStats reports the risk engine rule cache state (admin only)
+func (h *RiskHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.GetCacheStats(ctx, &pb_risk.GetCacheStatsRequest{})
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to get rule cache stats").SendJSON(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"last_updated": optionalTime(grpcResp.LastUpdated),
+		"age_seconds":  grpcResp.AgeSeconds,
+		"ttl_seconds":  grpcResp.TtlSeconds,
+		"rule_counts":  grpcResp.RuleCounts,
+	})
+}
+
+// InvalidateCache forces the risk engine to reload rules on the next check (admin only)
+func (h *RiskHandler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.InvalidateCache(ctx, &pb_risk.InvalidateCacheRequest{})
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to invalidate rule cache").SendJSON(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": grpcResp.Success,
+	})
+}
+
+// RecheckUsers re-runs risk checks for the users matching a filter (admin only)
+// a call handles at most limit users within the request timeout, resume with next_offset until done
+func (h *RiskHandler) RecheckUsers(w http.ResponseWriter, r *http.Request) {
+	var req RecheckUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	v := validator.New()
+	v.Domain("email_domain", req.EmailDomain).
+		Min("limit", float64(req.Limit), 0).
+		Max("limit", float64(req.Limit), 1000).
+		Min("offset", float64(req.Offset), 0)
+
+	if !v.IsValid() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": v.Errors(),
+		})
+		return
+	}
+
+	// Stays below the server write timeout, the engine returns its progress shortly before this deadline
+	ctx, cancel := context.WithTimeout(r.Context(), 13*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.RecheckUsers(ctx, &pb_risk.RecheckUsersRequest{
+		EmailDomain:     req.EmailDomain,
+		Role:            req.Role,
+		IncludeInactive: req.IncludeInactive,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to recheck users").WithDetails(err.Error()).SendJSON(w)
+		}
+		return
+	}
+
+	newlyRisky := grpcResp.NewlyRiskyUserIds
+	if newlyRisky == nil {
+		newlyRisky = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RecheckUsersResponse{
+		Checked:           grpcResp.Checked,
+		Risky:             grpcResp.Risky,
+		NewlyRisky:        grpcResp.NewlyRisky,
+		NewlyRiskyUserIDs: newlyRisky,
+		Failed:            grpcResp.Failed,
+		NextOffset:        grpcResp.NextOffset,
+		Done:              grpcResp.Done,
+	})
+}
+
+// GetRiskHistory returns a page of a user's stored risk checks (admin only)
+// supports ?limit, ?cursor, ?risk_level, ?since and ?until (RFC3339) and ?sort=newest|oldest
+func (h *RiskHandler) GetRiskHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	grpcReq := &pb_risk.GetRiskHistoryRequest{
+		UserId:    chi.URLParam(r, "user_id"),
+		Cursor:    query.Get("cursor"),
+		RiskLevel: query.Get("risk_level"),
+		Sort:      query.Get("sort"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 100 {
+			errors.ErrValidationFailed.WithMessage("limit must be a number between 1 and 100").SendJSON(w)
+			return
+		}
+		grpcReq.Limit = int32(limit)
+	}
+	for param, target := range map[string]**timestamppb.Timestamp{"since": &grpcReq.Since, "until": &grpcReq.Until} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			errors.ErrValidationFailed.WithMessage(param + " must be an RFC3339 timestamp").SendJSON(w)
+			return
+		}
+		*target = timestamppb.New(t)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.GetRiskHistory(ctx, grpcReq)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to get risk history").SendJSON(w)
+		}
+		return
+	}
+
+	checks := make([]RiskCheckRecordResponse, 0, len(grpcResp.Checks))
+	for _, check := range grpcResp.Checks {
+		flags := check.Flags
+		if flags == nil {
+			flags = []string{}
+		}
+		checks = append(checks, RiskCheckRecordResponse{
+			CheckID:      check.CheckId,
+			IsRisky:      check.IsRisky,
+			RiskLevel:    check.RiskLevel,
+			TotalScore:   check.TotalScore,
+			Reason:       check.Reason,
+			Flags:        flags,
+			CheckedAt:    check.CheckedAt.AsTime(),
+			SampleWeight: check.SampleWeight,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RiskHistoryResponse{
+		Checks:     checks,
+		NextCursor: grpcResp.NextCursor,
+	})
+}
+
+// ListFeatureFlags reports the risk engine feature flag rollout, optionally for one user (admin only)
+func (h *RiskHandler) ListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.riskAdminClient.ListFeatureFlags(ctx, &pb_risk.ListFeatureFlagsRequest{
+		UserId: r.URL.Query().Get("user_id"),
+	})
+	if err != nil {
+		errors.ErrInternalServerError.WithMessage("Failed to list feature flags").SendJSON(w)
+		return
+	}
+
+	flags := make([]map[string]interface{}, 0, len(grpcResp.Flags))
+	for _, flag := range grpcResp.Flags {
+		allowlist := flag.Allowlist
+		if allowlist == nil {
+			allowlist = []string{}
+		}
+		flags = append(flags, map[string]interface{}{
+			"name":       flag.Name,
+			"percentage": flag.Percentage,
+			"allowlist":  allowlist,
+			"enabled":    flag.Enabled,
+		})
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(grpcResp)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flags": flags,
+	})
 }
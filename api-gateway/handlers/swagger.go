@@ -164,6 +164,13 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						},
 						"400": map[string]interface{}{
 							"description": "Invalid input data",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ValidationErrorResponse",
+									},
+								},
+							},
 						},
 					},
 				},
@@ -172,7 +179,7 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 				"post": map[string]interface{}{
 					"tags":        []string{"Authentication"},
 					"summary":     "Refresh JWT token",
-					"description": "Refresh an expired JWT token",
+					"description": "Exchange the refresh token from login or registration for a new access token. The refresh token is rotated, use the one in the response next time",
 					"requestBody": map[string]interface{}{
 						"required": true,
 						"content": map[string]interface{}{
@@ -203,6 +210,50 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 					},
 				},
 			},
+			"/auth/email/confirm": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"Authentication"},
+					"summary":     "Confirm email change",
+					"description": "Swap in the new email using the token sent to it and mark it verified",
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"token"},
+									"properties": map[string]interface{}{
+										"token": map[string]interface{}{
+											"type": "string",
+										},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Email changed successfully",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/User",
+									},
+								},
+							},
+						},
+						"401": map[string]interface{}{
+							"description": "Invalid token",
+						},
+						"409": map[string]interface{}{
+							"description": "Email already in use",
+						},
+						"410": map[string]interface{}{
+							"description": "Email change request has expired",
+						},
+					},
+				},
+			},
 			"/profile": map[string]interface{}{
 				"get": map[string]interface{}{
 					"tags":        []string{"User Profile"},
@@ -228,14 +279,85 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 					},
 				},
 			},
+			"/profile/sessions": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags":        []string{"User Profile"},
+					"summary":     "List sessions",
+					"description": "List the authenticated user's active login sessions, the session of the calling token is marked current",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Active sessions",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"type": "object",
+										"properties": map[string]interface{}{
+											"sessions": map[string]interface{}{
+												"type": "array",
+												"items": map[string]interface{}{
+													"$ref": "#/components/schemas/Session",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/profile/sessions/{id}": map[string]interface{}{
+				"delete": map[string]interface{}{
+					"tags":        []string{"User Profile"},
+					"summary":     "Revoke session",
+					"description": "Revoke one of the authenticated user's sessions so its refresh token stops working. Access tokens already issued stay valid until they expire",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"required":    true,
+							"description": "Session ID",
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"204": map[string]interface{}{
+							"description": "Session revoked",
+						},
+						"404": map[string]interface{}{
+							"description": "Session not found",
+						},
+					},
+				},
+			},
 			"/users": map[string]interface{}{
 				"get": map[string]interface{}{
 					"tags":        []string{"User Management"},
 					"summary":     "List users (Admin only)",
-					"description": "Get a list of all users - requires admin role",
+					"description": "Get a list of all users - requires admin role. With ?email= returns the single matching user or 404",
 					"security": []map[string]interface{}{
 						{"bearerAuth": []string{}},
 					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "email",
+							"in":          "query",
+							"required":    false,
+							"description": "Look up a single user by email address",
+							"schema": map[string]interface{}{
+								"type":   "string",
+								"format": "email",
+							},
+						},
+					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
 							"description": "List of users",
@@ -253,6 +375,9 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"403": map[string]interface{}{
 							"description": "Forbidden - Admin role required",
 						},
+						"404": map[string]interface{}{
+							"description": "No user with the given email",
+						},
 					},
 				},
 				"post": map[string]interface{}{
@@ -327,7 +452,7 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 				"put": map[string]interface{}{
 					"tags":        []string{"User Management"},
 					"summary":     "Update user",
-					"description": "Update user information - users can update their own data, admins can update any",
+					"description": "Update user information - users can update their own data, admins can update any. Omitted fields are left unchanged and an empty phone clears it. Also available as PATCH.",
 					"security": []map[string]interface{}{
 						{"bearerAuth": []string{}},
 					},
@@ -342,38 +467,401 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 							},
 						},
 					},
-					"requestBody": map[string]interface{}{
-						"required": true,
-						"content": map[string]interface{}{
-							"application/json": map[string]interface{}{
-								"schema": map[string]interface{}{
-									"$ref": "#/components/schemas/UserUpdate",
-								},
-							},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"$ref": "#/components/schemas/UserUpdate",
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "User updated successfully",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/User",
+									},
+								},
+							},
+						},
+						"404": map[string]interface{}{
+							"description": "User not found",
+						},
+					},
+				},
+			},
+			"/users/{id}/email": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"User Management"},
+					"summary":     "Request email change",
+					"description": "Send a verification token to the new address - the current email stays active until the change is confirmed within 24 hours",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"required":    true,
+							"description": "User ID",
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"new_email"},
+									"properties": map[string]interface{}{
+										"new_email": map[string]interface{}{
+											"type":   "string",
+											"format": "email",
+										},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"202": map[string]interface{}{
+							"description": "Verification sent to the new email address",
+						},
+						"409": map[string]interface{}{
+							"description": "Email already in use",
+						},
+					},
+				},
+			},
+			"/users/{id}/check-and-notify": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"User Management"},
+					"summary":     "Check risk and notify",
+					"description": "Run a risk check on a user and, when risky, send the risk alert before responding (Admin only). Notification failures don't fail the call, they are reported per channel",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"required":    true,
+							"description": "User ID",
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Risk check and notification outcome",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/CheckAndNotifyResponse",
+									},
+								},
+							},
+						},
+						"403": map[string]interface{}{
+							"description": "Admin access required",
+						},
+						"404": map[string]interface{}{
+							"description": "User not found",
+						},
+					},
+				},
+			},
+			"/risk/check": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"Risk Assessment"},
+					"summary":     "Check user risk",
+					"description": "Perform risk assessment for authenticated user",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "dry_run",
+							"in":          "query",
+							"required":    false,
+							"description": "Compute the result without storing it for analytics",
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"$ref": "#/components/schemas/RiskCheckRequest",
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Risk assessment completed",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/RiskCheckResponse",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Validation failed",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ValidationErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/risk/rules": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "List risk rules (Admin only)",
+					"description": "Get all risk rules - requires admin role",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "List of risk rules",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"type": "object",
+										"properties": map[string]interface{}{
+											"rules": map[string]interface{}{
+												"type": "array",
+												"items": map[string]interface{}{
+													"$ref": "#/components/schemas/RiskRule",
+												},
+											},
+											"total_count": map[string]interface{}{
+												"type": "integer",
+											},
+											"page": map[string]interface{}{
+												"type": "integer",
+											},
+											"page_size": map[string]interface{}{
+												"type": "integer",
+											},
+										},
+									},
+								},
+							},
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
+					},
+				},
+				"post": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "Create risk rule (Admin only)",
+					"description": "Create a new risk rule - requires admin role",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"$ref": "#/components/schemas/RiskRuleCreate",
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"201": map[string]interface{}{
+							"description": "Risk rule created successfully",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/RiskRule",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Validation failed",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ValidationErrorResponse",
+									},
+								},
+							},
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
+					},
+				},
+			},
+			"/risk/rules/bulk": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "Create risk rules in bulk (Admin only)",
+					"description": "Create up to 100 risk rules in one request. The batch is all-or-nothing unless partial is true, in which case valid rules are created and failures are reported per rule",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"rules"},
+									"properties": map[string]interface{}{
+										"rules": map[string]interface{}{
+											"type": "array",
+											"items": map[string]interface{}{
+												"$ref": "#/components/schemas/RiskRuleCreate",
+											},
+										},
+										"partial": map[string]interface{}{
+											"type":    "boolean",
+											"default": false,
+										},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"201": map[string]interface{}{
+							"description": "All risk rules created",
+						},
+						"207": map[string]interface{}{
+							"description": "Partial mode - some rules failed, see per-rule results",
+						},
+						"400": map[string]interface{}{
+							"description": "Validation failed - no rules were created",
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
+					},
+				},
+			},
+			"/risk/disposable-domains": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "List disposable email domains (Admin only)",
+					"description": "Domains flagged by DISPOSABLE_EMAIL rules. is_default is true while the embedded list is in use",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Disposable domain list",
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
+					},
+				},
+				"patch": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "Update disposable email domains (Admin only)",
+					"description": "Add and remove domains. The first update starts from the embedded list, changes apply to rules immediately",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"add": map[string]interface{}{
+											"type":  "array",
+											"items": map[string]interface{}{"type": "string"},
+										},
+										"remove": map[string]interface{}{
+											"type":  "array",
+											"items": map[string]interface{}{"type": "string"},
+										},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Updated, returns the new list size",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid domain or empty update",
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
+					},
+				},
+			},
+			"/risk/cache": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "Get rule cache stats (Admin only)",
+					"description": "Last refresh time, age, TTL and cached rule counts per category. last_updated is null until the next check after an invalidation",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Rule cache stats",
+						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
 						},
 					},
+				},
+			},
+			"/risk/cache/invalidate": map[string]interface{}{
+				"post": map[string]interface{}{
+					"tags":        []string{"Risk Management"},
+					"summary":     "Invalidate rule cache (Admin only)",
+					"description": "Marks the rule cache stale so the next risk check reloads rules from the database",
+					"security": []map[string]interface{}{
+						{"bearerAuth": []string{}},
+					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "User updated successfully",
-							"content": map[string]interface{}{
-								"application/json": map[string]interface{}{
-									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/User",
-									},
-								},
-							},
+							"description": "Cache invalidated",
 						},
-						"404": map[string]interface{}{
-							"description": "User not found",
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
 						},
 					},
 				},
 			},
-			"/risk/check": map[string]interface{}{
+			"/risk/recheck": map[string]interface{}{
 				"post": map[string]interface{}{
-					"tags":        []string{"Risk Assessment"},
-					"summary":     "Check user risk",
-					"description": "Perform risk assessment for authenticated user",
+					"tags":        []string{"Risk Management"},
+					"summary":     "Recheck users (Admin only)",
+					"description": "Re-run risk checks for the organization's users matching the filter, e.g. after adding rules. Checks are rate limited by RECHECK_RATE_PER_SECOND and stored like any other check. A call stops after limit users or before the request times out, pass next_offset as offset until done is true",
 					"security": []map[string]interface{}{
 						{"bearerAuth": []string{}},
 					},
@@ -382,79 +870,185 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"content": map[string]interface{}{
 							"application/json": map[string]interface{}{
 								"schema": map[string]interface{}{
-									"$ref": "#/components/schemas/RiskCheckRequest",
+									"type": "object",
+									"properties": map[string]interface{}{
+										"email_domain": map[string]interface{}{
+											"type":        "string",
+											"description": "Only users with an email on this domain",
+										},
+										"role": map[string]interface{}{
+											"type":        "string",
+											"description": "Only users with this role",
+										},
+										"include_inactive": map[string]interface{}{
+											"type":        "boolean",
+											"description": "Also recheck deactivated users",
+										},
+										"limit": map[string]interface{}{
+											"type":        "integer",
+											"minimum":     0,
+											"maximum":     1000,
+											"description": "Users checked per call, defaults to 100",
+										},
+										"offset": map[string]interface{}{
+											"type":        "integer",
+											"minimum":     0,
+											"description": "next_offset of the previous call",
+										},
+									},
 								},
 							},
 						},
 					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "Risk assessment completed",
+							"description": "Recheck progress",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/RiskCheckResponse",
+										"$ref": "#/components/schemas/RecheckUsersResponse",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid filter",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ValidationErrorResponse",
 									},
 								},
 							},
 						},
+						"403": map[string]interface{}{
+							"description": "Forbidden - Admin role required",
+						},
 					},
 				},
 			},
-			"/risk/rules": map[string]interface{}{
+			"/risk/history/{user_id}": map[string]interface{}{
 				"get": map[string]interface{}{
 					"tags":        []string{"Risk Management"},
-					"summary":     "List risk rules (Admin only)",
-					"description": "Get all risk rules - requires admin role",
+					"summary":     "Get risk history (Admin only)",
+					"description": "Stored risk checks of a user in the organization, newest first by default. Pass next_cursor back as cursor for the next page, it is empty on the last one",
 					"security": []map[string]interface{}{
 						{"bearerAuth": []string{}},
 					},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "user_id",
+							"in":          "path",
+							"required":    true,
+							"description": "User whose checks are listed",
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"required":    false,
+							"description": "Checks per page, defaults to 20",
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"minimum": 1,
+								"maximum": 100,
+							},
+						},
+						{
+							"name":        "cursor",
+							"in":          "query",
+							"required":    false,
+							"description": "next_cursor of the previous page",
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "risk_level",
+							"in":          "query",
+							"required":    false,
+							"description": "Only checks at this level",
+							"schema": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"MINIMAL", "LOW", "MEDIUM", "HIGH", "CRITICAL"},
+							},
+						},
+						{
+							"name":        "since",
+							"in":          "query",
+							"required":    false,
+							"description": "Only checks at or after this time",
+							"schema": map[string]interface{}{
+								"type":   "string",
+								"format": "date-time",
+							},
+						},
+						{
+							"name":        "until",
+							"in":          "query",
+							"required":    false,
+							"description": "Only checks before this time",
+							"schema": map[string]interface{}{
+								"type":   "string",
+								"format": "date-time",
+							},
+						},
+						{
+							"name":        "sort",
+							"in":          "query",
+							"required":    false,
+							"description": "Order by check time",
+							"schema": map[string]interface{}{
+								"type":    "string",
+								"enum":    []string{"newest", "oldest"},
+								"default": "newest",
+							},
+						},
+					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "List of risk rules",
+							"description": "A page of risk checks",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
-										"type": "array",
-										"items": map[string]interface{}{
-											"$ref": "#/components/schemas/RiskRule",
-										},
+										"$ref": "#/components/schemas/RiskHistoryResponse",
 									},
 								},
 							},
 						},
+						"400": map[string]interface{}{
+							"description": "Invalid filter or cursor",
+						},
 						"403": map[string]interface{}{
 							"description": "Forbidden - Admin role required",
 						},
 					},
 				},
-				"post": map[string]interface{}{
+			},
+			"/risk/features": map[string]interface{}{
+				"get": map[string]interface{}{
 					"tags":        []string{"Risk Management"},
-					"summary":     "Create risk rule (Admin only)",
-					"description": "Create a new risk rule - requires admin role",
+					"summary":     "List feature flags (Admin only)",
+					"description": "Read-only rollout state of the risk engine feature flags. With user_id each flag also reports whether that user is bucketed into it",
 					"security": []map[string]interface{}{
 						{"bearerAuth": []string{}},
 					},
-					"requestBody": map[string]interface{}{
-						"required": true,
-						"content": map[string]interface{}{
-							"application/json": map[string]interface{}{
-								"schema": map[string]interface{}{
-									"$ref": "#/components/schemas/RiskRuleCreate",
-								},
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "user_id",
+							"in":          "query",
+							"required":    false,
+							"description": "Evaluate every flag for this user",
+							"schema": map[string]interface{}{
+								"type": "string",
 							},
 						},
 					},
 					"responses": map[string]interface{}{
-						"201": map[string]interface{}{
-							"description": "Risk rule created successfully",
-							"content": map[string]interface{}{
-								"application/json": map[string]interface{}{
-									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/RiskRule",
-									},
-								},
-							},
+						"200": map[string]interface{}{
+							"description": "Feature flags",
 						},
 						"403": map[string]interface{}{
 							"description": "Forbidden - Admin role required",
@@ -568,6 +1162,10 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"last_name": map[string]interface{}{
 							"type": "string",
 						},
+						"locale": map[string]interface{}{
+							"type":        "string",
+							"description": "Preferred notification language (e.g. en, es), defaults to en",
+						},
 						"role": map[string]interface{}{
 							"type": "string",
 							"enum": []string{"user", "admin"},
@@ -596,6 +1194,10 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"last_name": map[string]interface{}{
 							"type": "string",
 						},
+						"locale": map[string]interface{}{
+							"type":        "string",
+							"description": "Preferred notification language (e.g. en, es), defaults to en",
+						},
 					},
 				},
 				"UserUpdate": map[string]interface{}{
@@ -607,6 +1209,10 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"last_name": map[string]interface{}{
 							"type": "string",
 						},
+						"locale": map[string]interface{}{
+							"type":        "string",
+							"description": "Preferred notification language (e.g. en, es), defaults to en",
+						},
 					},
 				},
 				"AuthResponse": map[string]interface{}{
@@ -626,6 +1232,35 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						},
 					},
 				},
+				"Session": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id": map[string]interface{}{
+							"type": "string",
+						},
+						"user_agent": map[string]interface{}{
+							"type": "string",
+						},
+						"ip_address": map[string]interface{}{
+							"type": "string",
+						},
+						"last_seen_at": map[string]interface{}{
+							"type":   "string",
+							"format": "date-time",
+						},
+						"created_at": map[string]interface{}{
+							"type":   "string",
+							"format": "date-time",
+						},
+						"expires_at": map[string]interface{}{
+							"type":   "string",
+							"format": "date-time",
+						},
+						"current": map[string]interface{}{
+							"type": "boolean",
+						},
+					},
+				},
 				"RiskCheckRequest": map[string]interface{}{
 					"type":     "object",
 					"required": []string{"transaction_amount", "transaction_type"},
@@ -664,6 +1299,171 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 								"type": "string",
 							},
 						},
+						"dry_run": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Present and true when the result was not stored",
+						},
+					},
+				},
+				"CheckAndNotifyResponse": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"check_id": map[string]interface{}{
+							"type": "string",
+						},
+						"is_risky": map[string]interface{}{
+							"type": "boolean",
+						},
+						"risk_level": map[string]interface{}{
+							"type": "string",
+						},
+						"reason": map[string]interface{}{
+							"type": "string",
+						},
+						"flags": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						"notified": map[string]interface{}{
+							"type":        "boolean",
+							"description": "True when the alert was delivered on every channel",
+						},
+						"notifications": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"channel": map[string]interface{}{
+										"type": "string",
+									},
+									"success": map[string]interface{}{
+										"type": "boolean",
+									},
+									"error": map[string]interface{}{
+										"type": "string",
+									},
+									"provider": map[string]interface{}{
+										"type": "string",
+									},
+								},
+							},
+						},
+						"notification_error": map[string]interface{}{
+							"type":        "string",
+							"description": "Why no notification was sent, e.g. the notification service was unreachable or throttled the alert",
+						},
+					},
+				},
+				"RecheckUsersResponse": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"checked": map[string]interface{}{
+							"type": "integer",
+						},
+						"risky": map[string]interface{}{
+							"type": "integer",
+						},
+						"newly_risky": map[string]interface{}{
+							"type":        "integer",
+							"description": "Users risky now but not on their previous check, or never checked before",
+						},
+						"newly_risky_user_ids": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						"failed": map[string]interface{}{
+							"type":        "integer",
+							"description": "Users whose check failed, resuming does not retry them",
+						},
+						"next_offset": map[string]interface{}{
+							"type":        "integer",
+							"description": "Offset to resume from",
+						},
+						"done": map[string]interface{}{
+							"type":        "boolean",
+							"description": "True once every user was listed",
+						},
+					},
+				},
+				"RiskHistoryResponse": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"checks": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"check_id": map[string]interface{}{
+										"type": "string",
+									},
+									"is_risky": map[string]interface{}{
+										"type": "boolean",
+									},
+									"risk_level": map[string]interface{}{
+										"type": "string",
+									},
+									"total_score": map[string]interface{}{
+										"type": "integer",
+									},
+									"reason": map[string]interface{}{
+										"type": "string",
+									},
+									"flags": map[string]interface{}{
+										"type": "array",
+										"items": map[string]interface{}{
+											"type": "string",
+										},
+									},
+									"checked_at": map[string]interface{}{
+										"type":   "string",
+										"format": "date-time",
+									},
+									"sample_weight": map[string]interface{}{
+										"type":        "integer",
+										"description": "Checks this stored check stands for under ANALYTICS_SAMPLE_RATE",
+									},
+								},
+							},
+						},
+						"next_cursor": map[string]interface{}{
+							"type":        "string",
+							"description": "Cursor of the next page, empty on the last page",
+						},
+					},
+				},
+				"ValidationError": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"field": map[string]interface{}{
+							"type": "string",
+						},
+						"code": map[string]interface{}{
+							"type":        "string",
+							"description": "Machine-readable rule that failed",
+							"enum":        []string{"REQUIRED", "INVALID_EMAIL", "INVALID_DOMAIN", "INVALID_PHONE", "INVALID_REGEX", "TOO_SHORT", "TOO_SMALL", "TOO_LARGE", "INVALID_VALUE"},
+						},
+						"message": map[string]interface{}{
+							"type":        "string",
+							"description": "Human-readable explanation, not meant for parsing",
+						},
+					},
+				},
+				"ValidationErrorResponse": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error": map[string]interface{}{
+							"type": "string",
+						},
+						"validation_errors": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"$ref": "#/components/schemas/ValidationError",
+							},
+						},
 					},
 				},
 				"RiskRule": map[string]interface{}{
@@ -684,6 +1484,20 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"threshold": map[string]interface{}{
 							"type": "number",
 						},
+						"priority": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     0,
+							"maximum":     1000,
+							"description": "Higher priority rules are evaluated first within a category",
+						},
+						"example_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must match, checked on create and update",
+						},
+						"example_no_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must not match, checked on create and update",
+						},
 						"action": map[string]interface{}{
 							"type": "string",
 						},
@@ -694,6 +1508,16 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 							"type":   "string",
 							"format": "date-time",
 						},
+						"updated_at": map[string]interface{}{
+							"type":   "string",
+							"format": "date-time",
+						},
+						"expires_at": map[string]interface{}{
+							"type":        "string",
+							"format":      "date-time",
+							"nullable":    true,
+							"description": "Null when the rule never expires",
+						},
 					},
 				},
 				"RiskRuleCreate": map[string]interface{}{
@@ -712,6 +1536,25 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"threshold": map[string]interface{}{
 							"type": "number",
 						},
+						"priority": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     0,
+							"maximum":     1000,
+							"description": "Higher priority rules are evaluated first within a category",
+						},
+						"expires_in_days": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     0,
+							"description": "Days until the rule expires, 0 (default) keeps it permanently. Capped by RULE_MAX_EXPIRES_IN_DAYS (365 by default)",
+						},
+						"example_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must match, checked on create and update",
+						},
+						"example_no_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must not match, checked on create and update",
+						},
 						"action": map[string]interface{}{
 							"type": "string",
 						},
@@ -729,6 +1572,25 @@ func (h *SwaggerHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request)
 						"threshold": map[string]interface{}{
 							"type": "number",
 						},
+						"priority": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     0,
+							"maximum":     1000,
+							"description": "Higher priority rules are evaluated first within a category",
+						},
+						"expires_in_days": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     0,
+							"description": "Days until the rule expires, 0 (default) keeps it permanently. Capped by RULE_MAX_EXPIRES_IN_DAYS (365 by default)",
+						},
+						"example_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must match, checked on create and update",
+						},
+						"example_no_match": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional input the rule must not match, checked on create and update",
+						},
 						"action": map[string]interface{}{
 							"type": "string",
 						},
@@ -3,10 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"user-risk-system/pkg/errors"
 	"user-risk-system/pkg/validator"
@@ -31,18 +35,19 @@ type CreateUserRequest struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
 	Phone     string `json:"phone"`
+	Locale    string `json:"locale"`
+
+	Roles []string `json:"roles,omitempty"` // Defaults to the configured default role
 }
 
 // CreateUserResponse represents the response for user creation
 type CreateUserResponse struct {
-	User  *UserResponse `json:"user,omitempty"`
-	Error string        `json:"error,omitempty"`
+	User *UserResponse `json:"user,omitempty"`
 }
 
 // GetUserResponse represents the response for user retrieval
 type GetUserResponse struct {
-	User  *UserResponse `json:"user,omitempty"`
-	Error string        `json:"error,omitempty"`
+	User *UserResponse `json:"user,omitempty"`
 }
 
 // UserResponse represents the standard user data response structure
@@ -55,7 +60,19 @@ type UserResponse struct {
 	Roles      []string  `json:"roles"`
 	IsActive   bool      `json:"is_active"`
 	IsVerified bool      `json:"is_verified"`
+	Locale     string    `json:"locale"`
+	OrgID      string    `json:"org_id"`
 	CreatedAt  time.Time `json:"created_at"`
+
+	AccountStatus string          `json:"account_status"`          // Why is_active is false, e.g. SUSPENDED_RISK
+	RecentLogins  []LoginResponse `json:"recent_logins,omitempty"` // Newest first, only on the profile
+}
+
+// LoginResponse represents one successful login of the user
+type LoginResponse struct {
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // CreateUser creates a new user account (admin only)
@@ -94,19 +111,22 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
+		Locale:    req.Locale,
+		Roles:     req.Roles,
 	}
 
 	grpcResp, err := h.userClient.CreateUser(ctx, grpcReq)
 	if err != nil {
-		errors.ErrInternalServerError.WithMessage("Failed to create user").SendJSON(w)
-		return
-	}
-
-	if grpcResp.Error != "" {
-		response := CreateUserResponse{Error: grpcResp.Error}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			errors.ErrEmailExists.SendJSON(w)
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to create user").SendJSON(w)
+		}
 		return
 	}
 
@@ -120,7 +140,11 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Roles:      grpcResp.User.Roles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
 	}
 
 	response := CreateUserResponse{User: user}
@@ -143,15 +167,14 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	grpcReq := &pb_user.GetUserRequest{Id: userID}
 	grpcResp, err := h.userClient.GetUser(ctx, grpcReq)
 	if err != nil {
-		errors.ErrInternalServerError.WithMessage("Failed to get user").SendJSON(w)
-		return
-	}
-
-	if grpcResp.Error != "" {
-		response := GetUserResponse{Error: grpcResp.Error}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(response)
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to get user").SendJSON(w)
+		}
 		return
 	}
 
@@ -164,7 +187,11 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		Roles:      grpcResp.User.Roles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
 	}
 
 	response := GetUserResponse{User: user}
@@ -180,26 +207,39 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pointers distinguish omitted fields (nil, left unchanged) from fields set to empty
 	var updateReq struct {
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		Phone     string `json:"phone"`
+		FirstName *string `json:"first_name"`
+		LastName  *string `json:"last_name"`
+		Phone     *string `json:"phone"`
+		Locale    *string `json:"locale"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&updateReq); err != nil {
+	// Decoded twice, as json leaves a null field nil just like an omitted one
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &updateReq); err != nil || json.Unmarshal(body, &fields) != nil {
 		errors.ErrInvalidJSON.SendJSON(w)
 		return
 	}
+	// An explicit null phone clears it, like an empty one
+	if _, ok := fields["phone"]; ok && updateReq.Phone == nil {
+		updateReq.Phone = new(string)
+	}
 
 	v := validator.New()
-	if updateReq.FirstName != "" {
-		v.MinLength("first_name", updateReq.FirstName, 2)
+	if updateReq.FirstName != nil {
+		v.MinLength("first_name", *updateReq.FirstName, 2)
 	}
-	if updateReq.LastName != "" {
-		v.MinLength("last_name", updateReq.LastName, 2)
+	if updateReq.LastName != nil {
+		v.MinLength("last_name", *updateReq.LastName, 2)
 	}
-	if updateReq.Phone != "" {
-		v.Phone("phone", updateReq.Phone)
+	if updateReq.Phone != nil && *updateReq.Phone != "" {
+		v.Phone("phone", *updateReq.Phone)
 	}
 
 	if !v.IsValid() {
@@ -220,18 +260,132 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		FirstName: updateReq.FirstName,
 		LastName:  updateReq.LastName,
 		Phone:     updateReq.Phone,
+		Locale:    updateReq.Locale,
 	}
 
 	grpcResp, err := h.userClient.UpdateUser(ctx, grpcReq)
 	if err != nil {
+		if status.Code(err) == codes.Aborted {
+			errors.ErrConcurrentUpdate.SendJSON(w)
+			return
+		}
 		errors.ErrInternalServerError.WithMessage("Failed to update user").SendJSON(w)
 		return
 	}
 
-	if grpcResp.Error != "" {
+	user := &UserResponse{
+		ID:         grpcResp.User.Id,
+		Email:      grpcResp.User.Email,
+		FirstName:  grpcResp.User.FirstName,
+		LastName:   grpcResp.User.LastName,
+		Phone:      grpcResp.User.Phone,
+		Roles:      grpcResp.User.Roles,
+		IsActive:   grpcResp.User.IsActive,
+		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
+		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// RequestEmailChange sends a verification token to a new email address, the current email stays active until confirmed
+func (h *UserHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("User ID is required").SendJSON(w)
+		return
+	}
+
+	var req struct {
+		NewEmail string `json:"new_email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	v := validator.New()
+	v.Required("new_email", req.NewEmail).
+		Email("new_email", req.NewEmail)
+
+	if !v.IsValid() {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": grpcResp.Error})
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": v.Errors(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.RequestEmailChange(ctx, &pb_user.RequestEmailChangeRequest{
+		Id:       userID,
+		NewEmail: req.NewEmail,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			errors.ErrEmailExists.SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to request email change").SendJSON(w)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Verification sent to the new email address",
+		"expires_at": grpcResp.ExpiresAt.AsTime(),
+	})
+}
+
+// ConfirmEmailChange applies a pending email change using the token sent to the new address
+func (h *UserHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	if req.Token == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("Token is required").SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.ConfirmEmailChange(ctx, &pb_user.ConfirmEmailChangeRequest{Token: req.Token})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated:
+			errors.ErrInvalidToken.SendJSON(w)
+		case codes.FailedPrecondition:
+			errors.ErrEmailChangeExpired.SendJSON(w)
+		case codes.AlreadyExists:
+			errors.ErrEmailExists.SendJSON(w)
+		case codes.Aborted:
+			errors.ErrConcurrentUpdate.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to confirm email change").SendJSON(w)
+		}
 		return
 	}
 
@@ -244,15 +398,239 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		Roles:      grpcResp.User.Roles,
 		IsActive:   grpcResp.User.IsActive,
 		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
 		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
 
-// ListUsers retrieves all users (admin only)
+// CheckAndNotifyResponse represents the combined outcome of a synchronous risk check and alert
+type CheckAndNotifyResponse struct {
+	CheckID           string               `json:"check_id"`
+	IsRisky           bool                 `json:"is_risky"`
+	RiskLevel         string               `json:"risk_level"`
+	Reason            string               `json:"reason"`
+	Flags             []string             `json:"flags"`
+	Notified          bool                 `json:"notified"`
+	Notifications     []NotificationResult `json:"notifications"`
+	NotificationError string               `json:"notification_error,omitempty"`
+}
+
+// NotificationResult represents the delivery outcome on a single channel
+type NotificationResult struct {
+	Channel  string `json:"channel"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// CheckAndNotify runs a risk check on a user and sends the risk alert before responding (admin only)
+func (h *UserHandler) CheckAndNotify(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("User ID is required").SendJSON(w)
+		return
+	}
+
+	// Covers the risk check and every notification channel, so it is longer than a plain lookup
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.CheckAndNotify(ctx, &pb_user.CheckAndNotifyRequest{UserId: userID})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to check and notify user").SendJSON(w)
+		}
+		return
+	}
+
+	response := CheckAndNotifyResponse{
+		CheckID:           grpcResp.CheckId,
+		IsRisky:           grpcResp.IsRisky,
+		RiskLevel:         grpcResp.RiskLevel,
+		Reason:            grpcResp.Reason,
+		Flags:             grpcResp.Flags,
+		Notified:          grpcResp.Notified,
+		Notifications:     make([]NotificationResult, 0, len(grpcResp.Notifications)),
+		NotificationError: grpcResp.NotificationError,
+	}
+	if response.Flags == nil {
+		response.Flags = []string{}
+	}
+	for _, result := range grpcResp.Notifications {
+		response.Notifications = append(response.Notifications, NotificationResult{
+			Channel:  result.Channel,
+			Success:  result.Success,
+			Error:    result.Error,
+			Provider: result.Provider,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetAccountStatusRequest represents the payload for changing a user's account status
+type SetAccountStatusRequest struct {
+	Status string `json:"status"` // ACTIVE, SUSPENDED_ADMIN or CLOSED
+	Reason string `json:"reason"`
+}
+
+// SetAccountStatus suspends, closes or reactivates a user (admin only)
+func (h *UserHandler) SetAccountStatus(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("User ID is required").SendJSON(w)
+		return
+	}
+
+	var req SetAccountStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.SetAccountStatus(ctx, &pb_user.SetAccountStatusRequest{
+		UserId: userID,
+		Status: strings.ToUpper(req.Status),
+		Reason: req.Reason,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.InvalidArgument:
+			errors.ErrValidationFailed.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		case codes.FailedPrecondition:
+			errors.ErrInvalidStatusTransition.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.Aborted:
+			errors.ErrConcurrentUpdate.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to set account status").SendJSON(w)
+		}
+		return
+	}
+
+	user := &UserResponse{
+		ID:         grpcResp.User.Id,
+		Email:      grpcResp.User.Email,
+		FirstName:  grpcResp.User.FirstName,
+		LastName:   grpcResp.User.LastName,
+		Phone:      grpcResp.User.Phone,
+		Roles:      grpcResp.User.Roles,
+		IsActive:   grpcResp.User.IsActive,
+		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
+		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetUserResponse{User: user})
+}
+
+// ReactivateUserRequest represents the payload for reactivating a risk-suspended user
+type ReactivateUserRequest struct {
+	Recheck bool   `json:"recheck"` // Run a risk check first, a user still at CRITICAL risk stays suspended
+	Reason  string `json:"reason"`
+}
+
+// ReactivateUserResponse represents the reactivated user and the risk check run before
+type ReactivateUserResponse struct {
+	User      *UserResponse `json:"user"`
+	CheckID   string        `json:"check_id,omitempty"`
+	RiskLevel string        `json:"risk_level,omitempty"`
+	Notified  bool          `json:"notified"`
+}
+
+// ReactivateUser lifts the risk suspension of a user after review (admin only)
+func (h *UserHandler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		errors.ErrMissingRequiredFileds.WithMessage("User ID is required").SendJSON(w)
+		return
+	}
+
+	var req ReactivateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.ErrInvalidJSON.SendJSON(w)
+		return
+	}
+
+	// Covers the optional risk check and the notification
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.ReactivateUser(ctx, &pb_user.ReactivateUserRequest{
+		UserId:  userID,
+		Recheck: req.Recheck,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		case codes.FailedPrecondition:
+			errors.ErrInvalidStatusTransition.WithMessage(status.Convert(err).Message()).SendJSON(w)
+		case codes.Aborted:
+			errors.ErrConcurrentUpdate.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to reactivate user").SendJSON(w)
+		}
+		return
+	}
+
+	user := &UserResponse{
+		ID:         grpcResp.User.Id,
+		Email:      grpcResp.User.Email,
+		FirstName:  grpcResp.User.FirstName,
+		LastName:   grpcResp.User.LastName,
+		Phone:      grpcResp.User.Phone,
+		Roles:      grpcResp.User.Roles,
+		IsActive:   grpcResp.User.IsActive,
+		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
+		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReactivateUserResponse{
+		User:      user,
+		CheckID:   grpcResp.CheckId,
+		RiskLevel: grpcResp.RiskLevel,
+		Notified:  grpcResp.Notified,
+	})
+}
+
+// ListUsers retrieves all users (admin only), or a single user when filtered by ?email=
 func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("email") {
+		h.getUserByEmail(w, r, r.URL.Query().Get("email"))
+		return
+	}
+
 	// todo: call a ListUsers gRPC method
 	// For now, return a placeholder response
 	w.Header().Set("Content-Type", "application/json")
@@ -262,6 +640,59 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// getUserByEmail looks up a single user by email address (admin only)
+func (h *UserHandler) getUserByEmail(w http.ResponseWriter, r *http.Request, email string) {
+	v := validator.New()
+	v.Required("email", email).
+		Email("email", email)
+
+	if !v.IsValid() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": v.Errors(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.userClient.GetUserByEmail(ctx, &pb_user.GetUserByEmailRequest{Email: email})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			errors.ErrUserNotFound.SendJSON(w)
+		case codes.PermissionDenied:
+			errors.ErrInsufficientRole.SendJSON(w)
+		default:
+			errors.ErrInternalServerError.WithMessage("Failed to get user").SendJSON(w)
+		}
+		return
+	}
+
+	user := &UserResponse{
+		ID:         grpcResp.User.Id,
+		Email:      grpcResp.User.Email,
+		FirstName:  grpcResp.User.FirstName,
+		LastName:   grpcResp.User.LastName,
+		Phone:      grpcResp.User.Phone,
+		Roles:      grpcResp.User.Roles,
+		IsActive:   grpcResp.User.IsActive,
+		IsVerified: grpcResp.User.IsVerified,
+		Locale:     grpcResp.User.Locale,
+		OrgID:      grpcResp.User.OrgId,
+		CreatedAt:  grpcResp.User.CreatedAt.AsTime(),
+
+		AccountStatus: grpcResp.User.AccountStatus,
+	}
+
+	response := GetUserResponse{User: user}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // HealthCheck returns the service health status
 func (h *UserHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := map[string]string{
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+
+	pb_user "user-risk-system/proto/user"
+)
+
+// updateClient records the UpdateUser calls of the gateway.
+type updateClient struct {
+	pb_user.UserServiceClient
+	req *pb_user.UpdateUserRequest
+}
+
+func (c *updateClient) UpdateUser(_ context.Context, req *pb_user.UpdateUserRequest, _ ...grpc.CallOption) (*pb_user.UpdateUserResponse, error) {
+	c.req = req
+	phone := "+15550100"
+	if req.Phone != nil {
+		phone = *req.Phone
+	}
+	return &pb_user.UpdateUserResponse{User: &pb_user.User{Id: req.Id, Phone: phone}}, nil
+}
+
+func TestUpdateUserPhone(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantPhone  *string
+	}{
+		{"omitted phone is left unchanged", `{"first_name":"Jane"}`, http.StatusOK, nil},
+		{"empty phone clears it", `{"phone":""}`, http.StatusOK, new(string)},
+		{"null phone clears it", `{"phone":null}`, http.StatusOK, new(string)},
+		{"new phone is set", `{"phone":"+15550199"}`, http.StatusOK, stringPtr("+15550199")},
+		{"invalid phone is rejected", `{"phone":"call me"}`, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &updateClient{}
+			router := chi.NewRouter()
+			router.Patch("/api/v1/users/{id}", NewUserHandler(client).UpdateUser)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/api/v1/users/user-1", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if client.req != nil {
+					t.Errorf("forwarded a rejected update %v", client.req)
+				}
+				return
+			}
+			if client.req == nil || client.req.Id != "user-1" {
+				t.Fatalf("forwarded %v, want an update of user-1", client.req)
+			}
+			switch got := client.req.Phone; {
+			case tt.wantPhone == nil && got != nil:
+				t.Errorf("phone = %q, want it left unchanged", *got)
+			case tt.wantPhone != nil && (got == nil || *got != *tt.wantPhone):
+				t.Errorf("phone = %v, want %q", got, *tt.wantPhone)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -14,14 +14,17 @@ import (
 	"user-risk-system/api-gateway/handlers"
 	"user-risk-system/api-gateway/middleware"
 	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/cache"
+	"user-risk-system/pkg/client"
+	"user-risk-system/pkg/clock"
 	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/health"
+	"user-risk-system/pkg/lifecycle"
 	"user-risk-system/pkg/logger"
-	pb_risk "user-risk-system/proto/risk"
-	pb_user "user-risk-system/proto/user"
 )
 
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Require("JWT_SECRET"))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -31,39 +34,70 @@ func main() {
 		Format:      "json",
 		ServiceName: "api-gateway",
 		Environment: cfg.Environment,
+		MaskPII:     cfg.LogMaskPII,
 	}
 	appLogger := logger.New(logConfig)
+	cfg.LogStartupReport(appLogger)
+	cfg.Settings().WatchSignals(context.Background(), appLogger)
+
+	// Resources registered with the lifecycle are closed in reverse order when main returns
+	lc := lifecycle.New(appLogger)
+	defer lc.Shutdown()
 
 	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer)
 	authMiddleware := auth.NewAuthMiddleware(jwtManager)
 
-	// gRPC connection with interceptor to user service
-	userConn, err := auth.NewAuthenticatedGRPCConnection(cfg.UserServiceURL)
-	if err != nil {
-		appLogger.Fatalf(
-			"Failed to connect to user service", err,
-			"service_url", cfg.UserServiceURL,
-		)
+	// gRPC clients forwarding the caller's JWT to downstream services
+	var userClient *client.UserClient
+	if err := lc.Connect("user-service", func() (err error) {
+		userClient, err = client.NewUserClient(cfg.UserServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		appLogger.Fatalf("Failed to connect to user service at %s: %v", cfg.UserServiceURL, err)
 	}
-	defer userConn.Close()
+	lc.OnShutdown("user-service client", userClient.Close)
 
-	// gRPC connection to risk service
-	riskConn, err := auth.NewAuthenticatedGRPCConnection(cfg.RiskServiceURL)
-	if err != nil {
-		appLogger.Fatalf(
-			"Failed to connect to risk service", err,
-			"service_url", cfg.RiskServiceURL,
-		)
+	var riskClient *client.RiskClient
+	if err := lc.Connect("risk-engine", func() (err error) {
+		riskClient, err = client.NewRiskClient(cfg.RiskServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		appLogger.Fatalf("Failed to connect to risk service at %s: %v", cfg.RiskServiceURL, err)
 	}
-	defer riskConn.Close()
+	lc.OnShutdown("risk-engine client", riskClient.Close)
 
-	userClient := pb_user.NewUserServiceClient(userConn)
-	riskClient := pb_risk.NewRiskServiceClient(riskConn)
-	riskAdminClient := pb_risk.NewRiskAdminServiceClient(riskConn)
+	var notificationClient *client.NotificationClient
+	if err := lc.Connect("notification-service", func() (err error) {
+		notificationClient, err = client.NewNotificationClient(cfg.NotificationServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		appLogger.Fatalf("Failed to connect to notification service at %s: %v", cfg.NotificationServiceURL, err)
+	}
+	lc.OnShutdown("notification-service client", notificationClient.Close)
+
+	// Optionally wait for downstream services before accepting traffic
+	if err := health.WaitForDependencies(context.Background(), cfg.StartupWaitTimeout, cfg.StartupWaitInterval, appLogger,
+		health.GRPCDependency("user-service", userClient.Conn()),
+		health.GRPCDependency("risk-engine", riskClient.Conn()),
+		health.GRPCDependency("notification-service", notificationClient.Conn()),
+	); err != nil {
+		appLogger.Fatalf("Dependencies not ready: %v", err)
+	}
+
+	// Rate limit counters, shared through Redis when REDIS_URL is set
+	var rateLimitStore cache.Cache
+	if err := lc.Connect("cache", func() (err error) {
+		rateLimitStore, err = cache.New(cfg.RedisURL)
+		return err
+	}); err != nil {
+		appLogger.Fatalf("Failed to set up cache: %v", err)
+	}
+	lc.OnShutdown("cache", rateLimitStore.Close)
 
 	userHandler := handlers.NewUserHandler(userClient)
-	riskHandler := handlers.NewRiskHandler(riskClient, riskAdminClient)
-	authHandler := handlers.NewAuthHandler(userClient, jwtManager)
+	riskHandler := handlers.NewRiskHandler(riskClient, riskClient.Admin)
+	authHandler := handlers.NewAuthHandler(userClient, jwtManager, cfg.TrustedProxyNetworks())
+	notificationHandler := handlers.NewNotificationHandler(notificationClient, jwtManager, cfg.SendGridWebhookPublicKey)
 	swaggerHandler := handlers.NewSwaggerHandler()
 
 	r := chi.NewRouter()
@@ -75,6 +109,14 @@ func main() {
 	r.Use(middleware.CORSMiddleware(middleware.LoggerMiddlewareConfig{
 		AllowedOrigins: cfg.AllowedOrigins,
 	}))
+	r.Use(middleware.CompressionMiddleware(middleware.DefaultCompressionMinSize))
+	if cfg.RequestTimeout > 0 {
+		r.Use(middleware.TimeoutMiddleware(cfg.RequestTimeout))
+	}
+	r.Use(middleware.RequireJSONMiddleware)
+	if cfg.RateLimitEnabled {
+		r.Use(middleware.NewRateLimitMiddleware(rateLimitStore, cfg.Settings(), clock.Real{}, appLogger, "/api/v1/health"))
+	}
 
 	// API Documentation routes
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +134,26 @@ func main() {
 			r.Post("/login", authHandler.Login)
 			r.Post("/register", authHandler.Register)
 			r.Post("/refresh", authHandler.RefreshToken)
+			r.Post("/email/confirm", userHandler.ConfirmEmailChange) // Authenticated by the emailed token
+		})
+
+		// Notification routes
+		r.Route("/notifications", func(r chi.Router) {
+			// Provider webhooks (public, verified by provider signature)
+			r.Post("/webhooks/sendgrid", notificationHandler.SendGridWebhook)
+
+			// Admin only notification management
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.HTTPMiddleware)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/broadcast", notificationHandler.Broadcast)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/test", notificationHandler.TestSend)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/templates", notificationHandler.ListTemplates)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/templates/{name}/preview", notificationHandler.PreviewTemplate)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/suppressions", notificationHandler.ListSuppressions)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/suppressions", notificationHandler.AddSuppression)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Delete("/suppressions/{recipient}", notificationHandler.RemoveSuppression)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/{notification_id}", notificationHandler.GetNotificationStatus)
+			})
 		})
 
 		// Protected routes group (authentication required)
@@ -99,7 +161,10 @@ func main() {
 			r.Use(authMiddleware.HTTPMiddleware)
 
 			// User profile routes
-			r.Get("/profile", authHandler.GetProfile)
+			r.With(middleware.ETagMiddleware).Get("/profile", authHandler.GetProfile)
+			r.Get("/profile/sessions", authHandler.ListSessions)
+			r.Delete("/profile/sessions/{id}", authHandler.RevokeSession)
+			r.Post("/profile/password", authHandler.ChangePassword)
 
 			// User management routes
 			r.Route("/users", func(r chi.Router) {
@@ -110,6 +175,11 @@ func main() {
 				// User can access their own data, admin can access any
 				r.Get("/{id}", userHandler.GetUser)
 				r.Put("/{id}", userHandler.UpdateUser)
+				r.Patch("/{id}", userHandler.UpdateUser)
+				r.Post("/{id}/email", userHandler.RequestEmailChange)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/{id}/check-and-notify", userHandler.CheckAndNotify)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Put("/{id}/status", userHandler.SetAccountStatus)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/{id}/reactivate", userHandler.ReactivateUser)
 			})
 
 			// Risk management routes
@@ -119,19 +189,33 @@ func main() {
 
 				// Admin only risk rule management
 				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/rules", riskHandler.CreateRiskRule)
-				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/rules", riskHandler.ListRiskRules)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/rules/bulk", riskHandler.CreateRiskRules)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin), middleware.ETagMiddleware).Get("/rules", riskHandler.ListRiskRules)
 				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Put("/rules/{id}", riskHandler.UpdateRiskRule)
 				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Delete("/rules/{id}", riskHandler.DeleteRiskRule)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/disposable-domains", riskHandler.ListDisposableDomains)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Patch("/disposable-domains", riskHandler.UpdateDisposableDomains)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/cache", riskHandler.GetCacheStats)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/cache/invalidate", riskHandler.InvalidateCache)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/features", riskHandler.ListFeatureFlags)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Post("/recheck", riskHandler.RecheckUsers)
+				r.With(authMiddleware.RequireRole(auth.RoleAdmin)).Get("/history/{user_id}", riskHandler.GetRiskHistory)
 			})
 		})
 	})
 
-	port := cfg.Port
+	// Leave room to write the 504 of a request that reached REQUEST_TIMEOUT
+	writeTimeout := 15 * time.Second
+	if cfg.RequestTimeout+5*time.Second > writeTimeout {
+		writeTimeout = cfg.RequestTimeout + 5*time.Second
+	}
+
+	port := cfg.Ports.Gateway
 	srv := &http.Server{
 		Addr:         ":" + port,
 		Handler:      r,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		WriteTimeout: writeTimeout,
 		IdleTimeout:  60 * time.Second,
 	}
 
@@ -146,6 +230,16 @@ func main() {
 				"GET /api/v1/users",
 				"POST /api/v1/risk/check",
 				"POST /api/v1/risk/rules",
+				"POST /api/v1/risk/rules/bulk",
+				"POST /api/v1/notifications/broadcast",
+				"POST /api/v1/notifications/test",
+				"GET /api/v1/notifications/templates",
+				"GET /api/v1/notifications/templates/{name}/preview",
+				"GET /api/v1/notifications/suppressions",
+				"POST /api/v1/notifications/suppressions",
+				"DELETE /api/v1/notifications/suppressions/{recipient}",
+				"GET /api/v1/notifications/{notification_id}",
+				"POST /api/v1/notifications/webhooks/sendgrid",
 			},
 		)
 
@@ -154,19 +248,16 @@ func main() {
 			os.Exit(1)
 		}
 	}()
+	lc.OnShutdown("http server", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	})
+	lc.Started("port", port)
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 
 	appLogger.Info("Shutting down API Gateway...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		appLogger.Fatalf("Server forced to shutdown: %v", err)
-	} else {
-		appLogger.Info("API Gateway shutdown complete")
-	}
 }
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	const minSize = 64
+	large := strings.Repeat("a", minSize)
+	small := strings.Repeat("a", minSize-1)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantGzip       bool
+	}{
+		{"at the minimum size", "gzip", "application/json", large, true},
+		{"below the minimum size", "gzip", "application/json", small, false},
+		{"detected content type", "gzip, deflate", "", large, true},
+		{"already compressed type", "gzip", "image/png", large, false},
+		{"gzip refused with q=0", "gzip;q=0, identity", "application/json", large, false},
+		{"gzip refused with q=0.0", "gzip; q=0.0", "application/json", large, false},
+		{"gzip with a weight", "br;q=1.0, gzip;q=0.5", "application/json", large, true},
+		{"no Accept-Encoding", "", "application/json", large, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CompressionMiddleware(minSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(http.StatusCreated)
+				// Written in two parts, so the decision is made once the buffered parts reach the threshold
+				io.WriteString(w, tt.body[:10])
+				io.WriteString(w, tt.body[10:])
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/risk/rules", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != http.StatusCreated {
+				t.Errorf("status = %d, want the handler's %d", w.Code, http.StatusCreated)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if got := w.Header().Get("Content-Encoding") == "gzip"; got != tt.wantGzip {
+				t.Fatalf("gzipped = %v, want %v", got, tt.wantGzip)
+			}
+
+			body := w.Body.String()
+			if tt.wantGzip {
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("body is not gzip: %v", err)
+				}
+				decoded, err := io.ReadAll(gz)
+				if err != nil {
+					t.Fatalf("failed to decompress body: %v", err)
+				}
+				body = string(decoded)
+			}
+			if body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// etagResponseWriter buffers the response body so its ETag can be computed before sending.
+type etagResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// WriteHeader captures the status until the body is complete
+func (w *etagResponseWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+}
+
+// Write buffers the body
+func (w *etagResponseWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+// ETagMiddleware adds a strong ETag (hash of the body) to successful GET responses and
+// answers 304 Not Modified when the request's If-None-Match matches it.
+// CompressionMiddleware makes the ETag weak for clients accepting gzip, matching ignores the W/ prefix.
+func ETagMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ew := &etagResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(ew, r)
+
+		if ew.statusCode == 0 {
+			ew.statusCode = http.StatusOK
+		}
+		if ew.statusCode != http.StatusOK {
+			w.WriteHeader(ew.statusCode)
+			w.Write(ew.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(ew.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "private, no-cache")
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(ew.body.Bytes())
+	})
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
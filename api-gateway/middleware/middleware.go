@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"mime"
 	"net/http"
 	"time"
+	"user-risk-system/pkg/errors"
 	"user-risk-system/pkg/logger"
 )
 
@@ -84,3 +86,24 @@ func CORSMiddleware(config LoggerMiddlewareConfig) func(http.Handler) http.Handl
 		})
 	}
 }
+
+// RequireJSONMiddleware rejects POST, PUT and PATCH requests with a body whose Content-Type is not
+// application/json with 415 Unsupported Media Type. Requests without a body, e.g. action POSTs like
+// cache invalidation, and other methods, including OPTIONS preflights, pass through.
+func RequireJSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+				break
+			}
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				errors.ErrUnsupportedMediaType.SendJSON(w)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
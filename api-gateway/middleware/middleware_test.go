@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireJSONMiddleware(t *testing.T) {
+	handler := RequireJSONMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		want        int
+	}{
+		{"json post", http.MethodPost, `{"a":1}`, "application/json", http.StatusNoContent},
+		{"json with charset", http.MethodPut, `{"a":1}`, "application/json; charset=utf-8", http.StatusNoContent},
+		{"form post", http.MethodPost, "a=1", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"body without content type", http.MethodPatch, `{"a":1}`, "", http.StatusUnsupportedMediaType},
+		{"empty post", http.MethodPost, "", "", http.StatusNoContent},
+		{"empty post with other type", http.MethodPost, "", "text/plain", http.StatusNoContent},
+		{"get", http.MethodGet, "", "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r *http.Request
+			if tt.body == "" {
+				r = httptest.NewRequest(tt.method, "/api/v1/admin/cache/invalidate", nil)
+			} else {
+				r = httptest.NewRequest(tt.method, "/api/v1/admin/cache/invalidate", strings.NewReader(tt.body))
+			}
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagMiddleware(t *testing.T) {
+	const body = `{"rules":[]}`
+	handler := ETagMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(body))
+	}))
+	serve := func(method, path, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	first := serve(http.MethodGet, "/rules", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != body {
+		t.Fatalf("GET = %d %q, want the handler's response", first.Code, first.Body.String())
+	}
+	if !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) || len(etag) < 3 {
+		t.Fatalf("ETag = %q, want a strong quoted ETag", etag)
+	}
+	if got := serve(http.MethodGet, "/rules", "").Header().Get("ETag"); got != etag {
+		t.Errorf("ETag of the same body = %q, want %q", got, etag)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		wantCode    int
+		wantBody    string
+		wantETag    bool
+	}{
+		{"matching If-None-Match", http.MethodGet, "/rules", etag, http.StatusNotModified, "", true},
+		{"one of several candidates", http.MethodGet, "/rules", `"other", ` + etag, http.StatusNotModified, "", true},
+		{"weak form of the ETag", http.MethodGet, "/rules", "W/" + etag, http.StatusNotModified, "", true},
+		{"stale If-None-Match", http.MethodGet, "/rules", `"stale"`, http.StatusOK, body, true},
+		{"non-200 passes through", http.MethodGet, "/missing", etag, http.StatusNotFound, body, false},
+		{"non-GET passes through", http.MethodPost, "/rules", etag, http.StatusOK, body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.method, tt.path, tt.ifNoneMatch)
+			if w.Code != tt.wantCode || w.Body.String() != tt.wantBody {
+				t.Errorf("response = %d %q, want %d %q", w.Code, w.Body.String(), tt.wantCode, tt.wantBody)
+			}
+			if got := w.Header().Get("ETag"); (got != "") != tt.wantETag || (tt.wantETag && got != etag) {
+				t.Errorf("ETag = %q, want set %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestETagIsWeakForGzipClients(t *testing.T) {
+	body := strings.Repeat(`{"name":"rule"},`, 200)
+	handler := CompressionMiddleware(DefaultCompressionMinSize)(ETagMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})))
+	serve := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/rules", nil)
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	plain := serve("", "")
+	gzipped := serve("gzip", "")
+	if gzipped.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want a gzipped response", gzipped.Header().Get("Content-Encoding"))
+	}
+	strong, weak := plain.Header().Get("ETag"), gzipped.Header().Get("ETag")
+	if strings.HasPrefix(strong, "W/") || weak != "W/"+strong {
+		t.Fatalf("ETags = %q uncompressed and %q gzipped, want a strong one and its weak form", strong, weak)
+	}
+
+	notModified := serve("gzip", weak)
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("revalidation = %d with %d bytes, want an empty 304", notModified.Code, notModified.Body.Len())
+	}
+	if got := notModified.Header().Get("ETag"); got != weak {
+		t.Errorf("304 ETag = %q, want the ETag of the 200 %q", got, weak)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/cache"
+	"user-risk-system/pkg/clock"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+)
+
+func TestRateLimitWindowsFollowClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 10, 0, time.UTC))
+	settings := config.NewSettings(config.Reloadable{RateLimitRequests: 2, RateLimitWindow: time.Minute})
+	handler := NewRateLimitMiddleware(cache.NewMemory(), settings, fake, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+
+	request := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+		r.RemoteAddr = "203.0.113.7:51234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := request(); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d status = %d, want it within the limit", i+1, w.Code)
+		}
+	}
+	w := request()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request status = %d, want 429", w.Code)
+	}
+	// 50s remain in the window aligned to the minute
+	if got := w.Header().Get("Retry-After"); got != "51" {
+		t.Errorf("Retry-After = %s, want 51", got)
+	}
+
+	fake.Advance(49 * time.Second)
+	if w := request(); w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d before the window ends, want 429", w.Code)
+	}
+	fake.Advance(time.Second)
+	if w := request(); w.Code != http.StatusNoContent || w.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("status = %d, remaining = %s in the next window, want a fresh limit", w.Code, w.Header().Get("X-RateLimit-Remaining"))
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"user-risk-system/pkg/errors"
+)
+
+// timeoutWriter buffers the response of a request running under TimeoutMiddleware,
+// so nothing reaches the client once the request has timed out.
+type timeoutWriter struct {
+	mu         sync.Mutex
+	header     http.Header
+	buf        bytes.Buffer
+	statusCode int
+	timedOut   bool
+}
+
+// Header returns the buffered headers, copied to the response once the handler finishes
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// Write buffers p, failing with http.ErrHandlerTimeout once the request timed out
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.statusCode == 0 {
+		tw.statusCode = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+// WriteHeader records the first status code written
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.statusCode != 0 {
+		return
+	}
+	tw.statusCode = code
+}
+
+// TimeoutMiddleware bounds every request's context by timeout and answers 504 when a handler
+// hasn't responded by then. Handlers may still use shorter timeouts of their own. Responses are
+// buffered until the handler returns, so it belongs inside the logging and compression middleware.
+// a panicking handler panics again in the serving goroutine, as without the middleware.
+func TimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				for key, values := range tw.header {
+					w.Header()[key] = values
+				}
+				if tw.statusCode == 0 {
+					tw.statusCode = http.StatusOK
+				}
+				w.WriteHeader(tw.statusCode)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				if ctx.Err() == context.DeadlineExceeded {
+					errors.ErrRequestTimeout.SendJSON(w)
+				}
+				// Otherwise the client went away and nobody reads the response
+			}
+		})
+	}
+}
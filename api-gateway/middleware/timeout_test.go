@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/errors"
+)
+
+func TestTimeoutMiddlewareAnswersSlowHandlers(t *testing.T) {
+	wrote := make(chan error, 1)
+	handler := TimeoutMiddleware(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("X-Late", "true")
+		_, err := w.Write([]byte("too late"))
+		wrote <- err
+	}))
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("slow request answered after %v, want about the timeout", elapsed)
+	}
+	if w.Code != http.StatusGatewayTimeout || !strings.Contains(w.Body.String(), errors.ErrRequestTimeout.Message) {
+		t.Errorf("response = %d %s, want a 504 with ErrRequestTimeout", w.Code, w.Body.String())
+	}
+
+	if err := <-wrote; err != http.ErrHandlerTimeout {
+		t.Errorf("late write error = %v, want http.ErrHandlerTimeout", err)
+	}
+	if w.Header().Get("X-Late") != "" || strings.Contains(w.Body.String(), "too late") {
+		t.Error("the handler's late response reached the client")
+	}
+}
+
+func TestTimeoutMiddlewarePassesFastResponses(t *testing.T) {
+	handler := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Errorf("request deadline = %v, %v, want one within the timeout", deadline, ok)
+		}
+		// Handlers can shorten the bound further
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/users", nil))
+	if w.Code != http.StatusCreated || w.Body.String() != `{"ok":true}` || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("response = %d %v %s, want the handler's", w.Code, w.Header(), w.Body.String())
+	}
+}
+
+func TestTimeoutMiddlewareInsideCompression(t *testing.T) {
+	body := strings.Repeat(`{"field":"value"},`, 200)
+	handler := CompressionMiddleware(DefaultCompressionMinSize)(TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want the buffered response compressed", w.Header().Get("Content-Encoding"))
+	}
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	if decoded, _ := io.ReadAll(reader); string(decoded) != body {
+		t.Errorf("decoded body has %d bytes, want the %d written", len(decoded), len(body))
+	}
+}
+
+func TestTimeoutMiddlewareRepanicsForRecovery(t *testing.T) {
+	handler := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler bug")
+	}))
+
+	defer func() {
+		if p := recover(); p != "handler bug" {
+			t.Errorf("recovered %v, want the handler's panic in the serving goroutine", p)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
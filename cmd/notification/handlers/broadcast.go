@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+)
+
+// Broadcast segment constants select which users receive a broadcast.
+const (
+	SegmentAll       = "ALL"        // Every user
+	SegmentRole      = "ROLE"       // Users holding the requested role
+	SegmentRiskLevel = "RISK_LEVEL" // Users whose latest risk check has the requested level
+)
+
+// broadcastPageSize is the number of users fetched per ListUsers call.
+const broadcastPageSize = 500
+
+// BroadcastNotification enqueues one notification per user in the requested segment.
+// admin-only (enforced by the gRPC interceptor); recipients are resolved synchronously and
+// enqueued in the background at the configured rate to avoid provider throttling. Shutdown waits
+// for accepted broadcasts to finish enqueuing, see WaitForBroadcasts.
+func (h *NotificationHandler) BroadcastNotification(ctx context.Context, req *pb_notification.BroadcastNotificationRequest) (*pb_notification.BroadcastNotificationResponse, error) {
+	segment := strings.ToUpper(req.Segment)
+	if segment == "" {
+		segment = SegmentAll
+	}
+
+	if errs := h.validateBroadcast(segment, req); len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + strings.Join(errs, ", ")).GRPCStatus().Err()
+	}
+
+	recipients, err := h.resolveSegment(ctx, segment, req)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to resolve broadcast recipients", err, "segment", segment)
+		return &pb_notification.BroadcastNotificationResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	broadcastID := uuid.New().String()
+	adminID, _ := scontext.UserID(ctx)
+	adminEmail, _ := scontext.Email(ctx)
+
+	h.logger.InfoCtx(ctx, "Broadcast notification accepted",
+		"audit", true,
+		"broadcast_id", broadcastID,
+		"admin_id", adminID,
+		"admin_email", adminEmail,
+		"segment", segment,
+		"role", req.Role,
+		"risk_level", req.RiskLevel,
+		"type", req.Type,
+		"recipient_count", len(recipients),
+	)
+
+	h.broadcasts.Add(1)
+	go func() {
+		defer h.broadcasts.Done()
+		h.enqueueBroadcast(broadcastID, req, recipients)
+	}()
+
+	return &pb_notification.BroadcastNotificationResponse{
+		BroadcastId:    broadcastID,
+		RecipientCount: int32(len(recipients)),
+		Success:        true,
+	}, nil
+}
+
+// validateBroadcast checks the request has content and the filter its segment requires.
+func (h *NotificationHandler) validateBroadcast(segment string, req *pb_notification.BroadcastNotificationRequest) []string {
+	var errs []string
+	if req.Type == "" {
+		errs = append(errs, "type: is required")
+	}
+	if req.Message == "" {
+		errs = append(errs, "message: is required")
+	}
+
+	switch segment {
+	case SegmentAll:
+	case SegmentRole:
+		if req.Role == "" {
+			errs = append(errs, "role: is required for ROLE segment")
+		}
+	case SegmentRiskLevel:
+		if req.RiskLevel == "" {
+			errs = append(errs, "risk_level: is required for RISK_LEVEL segment")
+		}
+	default:
+		errs = append(errs, "segment: must be one of ALL, ROLE, RISK_LEVEL")
+	}
+	return errs
+}
+
+// resolveSegment pages through the user service and keeps users matching the segment.
+// the caller's JWT in ctx is forwarded so the admin-only ListUsers call is authorized.
+func (h *NotificationHandler) resolveSegment(ctx context.Context, segment string, req *pb_notification.BroadcastNotificationRequest) ([]*pb_user.User, error) {
+	var riskyUsers map[string]bool
+	if segment == SegmentRiskLevel {
+		resp, err := h.riskAdminClient.ListUsersByRiskLevel(ctx, &pb_risk.ListUsersByRiskLevelRequest{
+			RiskLevel: req.RiskLevel,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		riskyUsers = make(map[string]bool, len(resp.UserIds))
+		for _, id := range resp.UserIds {
+			riskyUsers[id] = true
+		}
+	}
+
+	var recipients []*pb_user.User
+	for offset := 0; ; offset += broadcastPageSize {
+		resp, err := h.userClient.ListUsers(ctx, &pb_user.ListUsersRequest{
+			Limit:  broadcastPageSize,
+			Offset: int32(offset),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range resp.Users {
+			if !user.IsActive {
+				continue
+			}
+			if segment == SegmentRole && !hasRole(user.Roles, req.Role) {
+				continue
+			}
+			if segment == SegmentRiskLevel && !riskyUsers[user.Id] {
+				continue
+			}
+			recipients = append(recipients, user)
+		}
+
+		if len(resp.Users) < broadcastPageSize {
+			break
+		}
+	}
+
+	return recipients, nil
+}
+
+// enqueueBroadcast publishes one notifications-queue message per recipient, rate limited
+// by BROADCAST_RATE_PER_SECOND, and records an audit entry once all messages are enqueued.
+func (h *NotificationHandler) enqueueBroadcast(broadcastID string, req *pb_notification.BroadcastNotificationRequest, recipients []*pb_user.User) {
+	rate := h.config.Settings().Current().BroadcastRatePerSecond
+	if rate <= 0 {
+		rate = 1
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	enqueued, failed := 0, 0
+	for _, user := range recipients {
+		<-ticker.C
+
+		msg := notification_models.NotificationMessage{
+			ID:      uuid.New().String(),
+			UserID:  user.Id,
+			Type:    req.Type,
+			Message: req.Message,
+			Channel: req.Channel,
+			Email:   user.Email,
+			Phone:   user.Phone,
+			Locale:  user.Locale,
+			OrgID:   user.OrgId,
+		}
+
+		h.recordCreated(ctx, &notification_models.Notification{
+			ID:        msg.ID,
+			UserID:    msg.UserID,
+			Type:      msg.Type,
+			Channel:   msg.Channel,
+			CreatedAt: time.Now(),
+		})
+		if err := h.messageQueue.Publish("notifications", msg); err != nil {
+			h.logger.Error("Failed to enqueue broadcast notification", err,
+				"broadcast_id", broadcastID,
+				"user_id", user.Id,
+			)
+			h.recordTransition(ctx, &notification_models.NotificationEvent{
+				NotificationID: msg.ID,
+				Status:         notification_models.NotificationStatusFailed,
+				Details:        "failed to enqueue: " + err.Error(),
+			})
+			failed++
+			continue
+		}
+		h.recordTransition(ctx, &notification_models.NotificationEvent{
+			NotificationID: msg.ID,
+			Status:         notification_models.NotificationStatusQueued,
+		})
+		enqueued++
+	}
+
+	h.logger.Info("Broadcast notification enqueued",
+		"audit", true,
+		"broadcast_id", broadcastID,
+		"enqueued", enqueued,
+		"failed", failed,
+	)
+}
+
+// hasRole reports whether roles contains the given role, ignoring case.
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
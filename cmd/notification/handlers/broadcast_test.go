@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"user-risk-system/pkg/messaging"
+	pb_notification "user-risk-system/proto/notification"
+	pb_user "user-risk-system/proto/user"
+)
+
+// listingUsers answers ListUsers with a fixed set of users, in pages.
+type listingUsers struct {
+	pb_user.UserServiceClient
+	users []*pb_user.User
+}
+
+func (l listingUsers) ListUsers(_ context.Context, req *pb_user.ListUsersRequest, _ ...grpc.CallOption) (*pb_user.ListUsersResponse, error) {
+	start := min(int(req.Offset), len(l.users))
+	end := min(start+int(req.Limit), len(l.users))
+	return &pb_user.ListUsersResponse{Users: l.users[start:end]}, nil
+}
+
+func TestWaitForBroadcastsWaitsForEveryRecipient(t *testing.T) {
+	t.Setenv("BROADCAST_RATE_PER_SECOND", "100")
+
+	users := make([]*pb_user.User, 10)
+	for i := range users {
+		users[i] = &pb_user.User{Id: fmt.Sprintf("user-%d", i), Email: fmt.Sprintf("user%d@example.com", i), IsActive: true}
+	}
+	h := newTestSendHandler(t)
+	h.userClient = listingUsers{users: users}
+
+	resp, err := h.BroadcastNotification(context.Background(), &pb_notification.BroadcastNotificationRequest{Type: "ANNOUNCEMENT", Message: "Maintenance tonight"})
+	if err != nil || !resp.Success {
+		t.Fatalf("BroadcastNotification() = %+v, %v", resp, err)
+	}
+
+	h.WaitForBroadcasts()
+	if got := len(h.messageQueue.(*messaging.InMemory).Published("notifications")); got != len(users) {
+		t.Errorf("enqueued %d notifications before WaitForBroadcasts returned, want %d", got, len(users))
+	}
+}
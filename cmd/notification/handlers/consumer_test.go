@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/pkg/messaging"
+)
+
+// blockingEmail signals every send it starts and finishes it once released.
+type blockingEmail struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingEmail) SendEmail(providers.EmailRecipients, string, string, map[string]interface{}, ...providers.Attachment) (providers.SendResult, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return providers.SendResult{}, nil
+}
+
+func (p *blockingEmail) GetProviderName() string { return "BLOCKING" }
+
+func TestConsumersStopOnCancelAfterInFlightMessages(t *testing.T) {
+	h := newTestSendHandler(t)
+	email := &blockingEmail{started: make(chan struct{}, 1), release: make(chan struct{})}
+	h.emailProvider = email
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.StartMessageConsumer(ctx)
+
+	err := h.messageQueue.Publish("notifications", notification_models.NotificationMessage{
+		UserID:  "user-1",
+		Type:    "ANNOUNCEMENT",
+		Message: "Maintenance tonight",
+		Channel: notification_models.ChannelEmail,
+		Email:   "user@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	select {
+	case <-email.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("consumer never handled the message")
+	}
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		h.WaitForConsumers()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("consumers stopped before the in-flight message was handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(email.release)
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("consumers did not stop after the context was cancelled")
+	}
+}
+
+func TestRecipientlessMessageIsRejectedUnsent(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+	}{
+		{"email without address", notification_models.ChannelEmail},
+		{"sms without phone", notification_models.ChannelSMS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			email, sms := &recordingEmail{}, &recordingSMS{}
+			h.emailProvider, h.smsProvider = email, sms
+
+			data, err := json.Marshal(notification_models.NotificationMessage{
+				UserID:  "user-1",
+				Type:    "ANNOUNCEMENT",
+				Message: "Maintenance tonight",
+				Channel: tt.channel,
+			})
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+
+			err = h.handleNotificationEvent(data)
+			var rejectErr *messaging.RejectError
+			if !errors.As(err, &rejectErr) {
+				t.Fatalf("handleNotificationEvent() error = %v, want a RejectError", err)
+			}
+			if len(email.bodies) != 0 || len(sms.numbers) != 0 {
+				t.Errorf("rejected message was sent: %d emails, %d SMS", len(email.bodies), len(sms.numbers))
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/pkg/errors"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// RecordDeliveryEvents applies provider delivery events (delivered, bounce, open, ...) to notifications.
+// events without a notification ID, with an unknown event name or, with status tracking, of a notification
+// that isn't stored are counted as ignored. Each status is logged, stored as the notification's status and
+// appended to its timeline, bounced and spam reported recipients are added to the suppression list. Only other services may call it, the gateway
+// forwards provider webhooks once their signature is verified.
+func (h *NotificationHandler) RecordDeliveryEvents(ctx context.Context, req *pb_notification.RecordDeliveryEventsRequest) (*pb_notification.RecordDeliveryEventsResponse, error) {
+	var processed, ignored int32
+
+	for _, event := range req.Events {
+		status, ok := deliveryStatus(event.Event)
+		if !ok || event.NotificationId == "" {
+			ignored++
+			continue
+		}
+		if h.notifications != nil {
+			record, err := h.notifications.Get(event.NotificationId)
+			if err != nil {
+				// Fail the batch so the provider retries it rather than losing bounces
+				h.logger.ErrorCtx(ctx, "Failed to load notification of delivery event", err, "notification_id", event.NotificationId)
+				return nil, errors.ErrInternalServerError.WithMessage("Failed to record delivery events").GRPCStatus().Err()
+			}
+			if record == nil {
+				h.logger.WarnCtx(ctx, "Ignoring delivery event of unknown notification",
+					"notification_id", event.NotificationId,
+					"provider", req.Provider,
+					"event", event.Event,
+				)
+				ignored++
+				continue
+			}
+		}
+
+		fields := []any{
+			"notification_id", event.NotificationId,
+			"provider", req.Provider,
+			"event", event.Event,
+			"status", status,
+			"provider_message_id", event.ProviderMessageId,
+			"occurred_at", time.Unix(event.Timestamp, 0).UTC(),
+		}
+		if event.Reason != "" {
+			fields = append(fields, "reason", event.Reason)
+		}
+
+		if status == notification_models.NotificationStatusBounced || status == notification_models.NotificationStatusFailed {
+			h.logger.WarnCtx(ctx, "Notification delivery failed", fields...)
+		} else {
+			h.logger.InfoCtx(ctx, "Notification status updated", fields...)
+		}
+
+		occurredAt := time.Now()
+		if event.Timestamp > 0 {
+			occurredAt = time.Unix(event.Timestamp, 0)
+		}
+		h.recordTransition(ctx, &notification_models.NotificationEvent{
+			NotificationID:    event.NotificationId,
+			Status:            status,
+			Channel:           notification_models.ChannelEmail,
+			Provider:          req.Provider,
+			ProviderMessageID: event.ProviderMessageId,
+			Details:           event.Reason,
+			OccurredAt:        occurredAt,
+		})
+
+		switch status {
+		case notification_models.NotificationStatusBounced:
+			h.suppressFromEvent(ctx, event, notification_models.SuppressionReasonBounce)
+		case notification_models.NotificationStatusComplained:
+			h.suppressFromEvent(ctx, event, notification_models.SuppressionReasonComplaint)
+		}
+		processed++
+	}
+
+	return &pb_notification.RecordDeliveryEventsResponse{
+		Processed: processed,
+		Ignored:   ignored,
+	}, nil
+}
+
+// deliveryStatus maps a SendGrid event name to a notification status.
+func deliveryStatus(event string) (string, bool) {
+	switch strings.ToLower(event) {
+	case "delivered":
+		return notification_models.NotificationStatusDelivered, true
+	case "bounce":
+		return notification_models.NotificationStatusBounced, true
+	case "dropped":
+		return notification_models.NotificationStatusFailed, true
+	case "open":
+		return notification_models.NotificationStatusOpened, true
+	case "click":
+		return notification_models.NotificationStatusClicked, true
+	case "spamreport":
+		return notification_models.NotificationStatusComplained, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,81 @@
+package handlers_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"user-risk-system/cmd/notification/handlers"
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/repository"
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/testutil"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// newTrackingHandler returns a notification handler storing statuses, timelines and suppressions in SQLite.
+func newTrackingHandler(t *testing.T) (*handlers.NotificationHandler, *repository.SuppressionRepository) {
+	t.Helper()
+
+	cfg := &config.Config{EmailProvider: "SIMULATE", SMSProvider: "SIMULATE"}
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	db := testutil.NewSQLiteDB(t, notification_models.AutoMigrate)
+
+	h := handlers.NewNotificationHandler(messaging.NewInMemory(), nil, nil, cfg, templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg)), log)
+	suppressions := repository.NewSuppressionRepository(db)
+	h.EnableSuppressions(suppressions)
+	h.EnableTimeline(repository.NewEventRepository(db))
+	h.EnableStatusTracking(repository.NewNotificationRepository(db))
+	return h, suppressions
+}
+
+func TestRecordDeliveryEventsStoresStatus(t *testing.T) {
+	h, suppressions := newTrackingHandler(t)
+	ctx := context.Background()
+
+	sent, err := h.SendNotification(ctx, &pb_notification.SendNotificationRequest{
+		UserId:   "user-1",
+		Type:     notification_models.NotificationTypeUserCreated,
+		Message:  "Welcome",
+		Email:    "bounced@example.com",
+		Channels: []string{notification_models.ChannelEmail},
+	})
+	if err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if sent.NotificationId == "" {
+		t.Fatal("SendNotification() returned no notification ID")
+	}
+
+	resp, err := h.RecordDeliveryEvents(ctx, &pb_notification.RecordDeliveryEventsRequest{
+		Provider: notification_models.ProviderSendGrid,
+		Events: []*pb_notification.DeliveryEvent{
+			{NotificationId: sent.NotificationId, Event: "bounce", Email: "bounced@example.com", Reason: "550 no such user", Timestamp: 1700000000},
+			{NotificationId: "never-sent", Event: "bounce", Email: "victim@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RecordDeliveryEvents() error = %v", err)
+	}
+	if resp.Processed != 1 || resp.Ignored != 1 {
+		t.Errorf("processed = %d, ignored = %d, want 1 and 1", resp.Processed, resp.Ignored)
+	}
+
+	status, err := h.GetNotificationStatus(ctx, &pb_notification.GetNotificationStatusRequest{NotificationId: sent.NotificationId})
+	if err != nil {
+		t.Fatalf("GetNotificationStatus() error = %v", err)
+	}
+	if status.Status != notification_models.NotificationStatusBounced {
+		t.Errorf("stored status = %s, want %s", status.Status, notification_models.NotificationStatusBounced)
+	}
+
+	if suppression, err := suppressions.Lookup("bounced@example.com"); err != nil || suppression == nil {
+		t.Errorf("bounced recipient not suppressed: %v", err)
+	}
+	if suppression, err := suppressions.Lookup("victim@example.com"); err != nil || suppression != nil {
+		t.Errorf("recipient of an unknown notification suppressed: %v, %v", suppression, err)
+	}
+}
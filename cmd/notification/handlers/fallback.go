@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// defaultChannels returns the channels of a notification that didn't request any, and whether they
+// form a fallback chain. NOTIFICATION_FALLBACK chains take precedence over the type defaults.
+func (h *NotificationHandler) defaultChannels(ctx context.Context, notificationType, phone string) ([]string, bool) {
+	if chain := h.config.Settings().Current().NotificationFallback[notificationType]; len(chain) > 0 {
+		return h.dropUnreachableSMS(ctx, phone, chain), true
+	}
+	return h.dropUnreachableSMS(ctx, phone, h.determineChannels(notificationType)), false
+}
+
+// deliver sends notification on channels in order, returning one result per channel attempted.
+// a fallback chain stops at the first channel that succeeds, otherwise every channel is attempted.
+func (h *NotificationHandler) deliver(ctx context.Context, notification *notification_models.Notification, channels []string, fallback bool) []*pb_notification.ChannelResult {
+	results := make([]*pb_notification.ChannelResult, 0, len(channels))
+
+	for i, channel := range channels {
+		notification.Channel = channel
+		notification.Provider = ""
+		recordSendResult(notification, providers.SendResult{})
+		result := &pb_notification.ChannelResult{Channel: channel, Success: true}
+		err := h.sendNotificationByChannel(ctx, notification)
+		h.recordSendAttempt(ctx, notification, err)
+		if err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to send notification", err,
+				"channel", channel,
+				"notification_id", notification.ID,
+			)
+			result.Success = false
+			result.Error = err.Error()
+			if fallback && i < len(channels)-1 {
+				h.logger.InfoCtx(ctx, "Falling back to next notification channel",
+					"failed_channel", channel,
+					"next_channel", channels[i+1],
+					"notification_id", notification.ID,
+				)
+			}
+		}
+		result.Provider = notification.Provider
+		result.ProviderMessageId = notification.ProviderMessageID
+		result.StatusCode = int32(notification.ProviderStatusCode)
+		result.LatencyMs = notification.ProviderLatency.Milliseconds()
+		if notification.Provider != "" {
+			h.logger.InfoCtx(ctx, "Provider call completed",
+				"channel", channel,
+				"provider", notification.Provider,
+				"provider_message_id", notification.ProviderMessageID,
+				"status_code", notification.ProviderStatusCode,
+				"latency_ms", result.LatencyMs,
+				"success", result.Success,
+				"notification_id", notification.ID,
+			)
+		}
+		results = append(results, result)
+
+		if fallback && result.Success {
+			break
+		}
+	}
+
+	return results
+}
+
+// deliveryOutcome summarizes the results of deliver: whether the notification counts as sent,
+// the channel that delivered a fallback chain and the last error. A fallback chain succeeds when
+// any channel did, otherwise every channel must have succeeded.
+func deliveryOutcome(results []*pb_notification.ChannelResult, fallback bool) (bool, string, string) {
+	success := !fallback
+	delivered, errorMsg := "", ""
+	for _, result := range results {
+		if result.Success {
+			if fallback {
+				success = true
+				delivered = result.Channel
+			}
+			continue
+		}
+		errorMsg = result.Error
+		if !fallback {
+			success = false
+		}
+	}
+	if fallback && success {
+		errorMsg = ""
+	} else if fallback && errorMsg == "" {
+		errorMsg = "no channel of the fallback chain could be attempted"
+	}
+	return success, delivered, errorMsg
+}
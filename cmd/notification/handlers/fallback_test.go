@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"reflect"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// failingSMS rejects every SMS.
+type failingSMS struct{}
+
+func (failingSMS) SendSMS(string, string) (providers.SendResult, error) {
+	return providers.SendResult{}, stderrors.New("carrier unavailable")
+}
+
+func (failingSMS) GetProviderName() string { return "FAILING" }
+
+func sendAlert(t *testing.T, h *NotificationHandler, notificationType string) *pb_notification.SendNotificationResponse {
+	t.Helper()
+	resp, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+		UserId:    "user-1",
+		Type:      notificationType,
+		Message:   "Risk detected",
+		Email:     "user@example.com",
+		Phone:     "+15550100",
+		PushToken: "push-token",
+	})
+	if err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	return resp
+}
+
+func attemptedChannels(resp *pb_notification.SendNotificationResponse) []string {
+	var channels []string
+	for _, result := range resp.ChannelResults {
+		channels = append(channels, result.Channel)
+	}
+	return channels
+}
+
+func TestFallbackChainAdvancesOnFailureAndStopsOnSuccess(t *testing.T) {
+	t.Setenv("NOTIFICATION_FALLBACK", "CRITICAL_RISK_ALERT=PUSH|SMS|EMAIL")
+
+	tests := []struct {
+		name          string
+		sms           providers.SMSProvider
+		emailFails    bool
+		wantAttempted []string
+		wantDelivered string
+		wantSuccess   bool
+	}{
+		{"stops at the first success", &recordingSMS{}, false, []string{"PUSH", "SMS"}, "SMS", true},
+		{"advances past failures", failingSMS{}, false, []string{"PUSH", "SMS", "EMAIL"}, "EMAIL", true},
+		{"every channel fails", failingSMS{}, true, []string{"PUSH", "SMS", "EMAIL"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			email := &recordingEmail{}
+			h.pushProvider, h.smsProvider, h.emailProvider = nil, tt.sms, email
+			if tt.emailFails {
+				h.emailProvider = nil
+			}
+
+			resp := sendAlert(t, h, notification_models.NotificationTypeCriticalRisk)
+			if got := attemptedChannels(resp); !reflect.DeepEqual(got, tt.wantAttempted) {
+				t.Errorf("attempted channels = %v, want %v", got, tt.wantAttempted)
+			}
+			if resp.DeliveredChannel != tt.wantDelivered || resp.Success != tt.wantSuccess {
+				t.Errorf("delivered = %q, success = %v, want %q, %v", resp.DeliveredChannel, resp.Success, tt.wantDelivered, tt.wantSuccess)
+			}
+			if tt.wantDelivered != "EMAIL" && len(email.bodies) != 0 {
+				t.Errorf("sent %d emails after an earlier channel delivered", len(email.bodies))
+			}
+			if !tt.wantSuccess && resp.Error == "" {
+				t.Error("failed chain reported no error")
+			}
+		})
+	}
+}
+
+func TestWithoutFallbackEveryChannelIsAttempted(t *testing.T) {
+	h := newTestSendHandler(t)
+	email := &recordingEmail{}
+	h.pushProvider, h.smsProvider, h.emailProvider = nil, &recordingSMS{}, email
+
+	resp := sendAlert(t, h, notification_models.NotificationTypeRiskDetected)
+	if got, want := attemptedChannels(resp), []string{"EMAIL", "SMS", "PUSH"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("attempted channels = %v, want %v", got, want)
+	}
+	if resp.Success || resp.DeliveredChannel != "" {
+		t.Errorf("success = %v, delivered = %q, want a failure without a delivered channel when push fails", resp.Success, resp.DeliveredChannel)
+	}
+	if len(email.bodies) != 1 {
+		t.Errorf("sent %d emails, want 1", len(email.bodies))
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/models"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// recordingEmail keeps the recipients, subject and body of every email it sends.
+type recordingEmail struct {
+	mu         sync.Mutex
+	recipients []providers.EmailRecipients
+	subjects   []string
+	bodies     []string
+}
+
+func (p *recordingEmail) SendEmail(recipients providers.EmailRecipients, subject, body string, _ map[string]interface{}, _ ...providers.Attachment) (providers.SendResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recipients = append(p.recipients, recipients)
+	p.subjects = append(p.subjects, subject)
+	p.bodies = append(p.bodies, body)
+	return providers.SendResult{}, nil
+}
+
+func (p *recordingEmail) GetProviderName() string { return "RECORDING" }
+
+func TestWelcomeEmailGreetsFirstName(t *testing.T) {
+	cfg := &config.Config{EmailProvider: "SIMULATE", SMSProvider: "SIMULATE"}
+	h := NewNotificationHandler(messaging.NewInMemory(), nil, nil, cfg, templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg)), logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	email := &recordingEmail{}
+	h.emailProvider = email
+
+	_, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+		UserId:    "user-1",
+		Type:      notification_models.NotificationTypeUserCreated,
+		Message:   "Welcome! Your account has been created successfully.",
+		Email:     "mary.ann@example.com",
+		FirstName: "Mary Ann",
+		Channels:  []string{notification_models.ChannelEmail},
+	})
+	if err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	if len(email.bodies) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(email.bodies))
+	}
+	if !strings.Contains(email.bodies[0], "Hi Mary Ann,") {
+		t.Errorf("welcome email does not greet the first name:\n%s", email.bodies[0])
+	}
+}
+
+// withRiskAlertTemplate loads a risk_alert template from disk that renders the recipient fields.
+func withRiskAlertTemplate(t *testing.T, h *NotificationHandler) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"risk_alert.html":    "Hi {{.FirstName}}, {{.RiskLevel}} risk: {{.Reason}}",
+		"risk_alert.es.html": "Hola {{.FirstName}}, riesgo {{.RiskLevel}}: {{.Reason}}",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+	}
+	h.templateManager = templates.NewEmailTemplateManager(dir, templates.BaseDataFromConfig(h.config))
+}
+
+func TestRiskAlertEmailGreetsFirstNameWithEventLevel(t *testing.T) {
+	h := newTestSendHandler(t)
+	withRiskAlertTemplate(t, h)
+	email := &recordingEmail{}
+	h.emailProvider = email
+
+	data, err := json.Marshal(models.RiskDetectedEvent{
+		EventMeta: models.NewEventMeta(),
+		UserID:    "user-1",
+		Email:     "mary.ann@example.com",
+		FirstName: "Mary Ann",
+		RiskLevel: "CRITICAL",
+		Reason:    "Blocked domain",
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := h.handleRiskDetectedEvent(data); err != nil {
+		t.Fatalf("handleRiskDetectedEvent() error = %v", err)
+	}
+
+	if len(email.bodies) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(email.bodies))
+	}
+	if !strings.HasPrefix(email.bodies[0], "Hi Mary Ann, CRITICAL risk:") {
+		t.Errorf("risk alert email does not greet the first name with the event level:\n%s", email.bodies[0])
+	}
+}
+
+func TestRiskAlertEmailUsesEventLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		locale      string
+		wantBody    string
+		wantSubject string
+	}{
+		{"localized", "es-MX", "Hola Ana, riesgo HIGH:", "Alerta de seguridad - Riesgo HIGH detectado"},
+		{"unsupported locale", "fr", "Hi Ana, HIGH risk:", "Security Alert - HIGH Risk Detected"},
+		{"missing locale", "", "Hi Ana, HIGH risk:", "Security Alert - HIGH Risk Detected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			withRiskAlertTemplate(t, h)
+			email := &recordingEmail{}
+			h.emailProvider = email
+
+			data, err := json.Marshal(models.RiskDetectedEvent{
+				EventMeta: models.NewEventMeta(),
+				UserID:    "user-1",
+				Email:     "ana@example.com",
+				FirstName: "Ana",
+				Locale:    tt.locale,
+				RiskLevel: "HIGH",
+				Reason:    "Blocked domain",
+			})
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if err := h.handleRiskDetectedEvent(data); err != nil {
+				t.Fatalf("handleRiskDetectedEvent() error = %v", err)
+			}
+
+			if len(email.bodies) != 1 {
+				t.Fatalf("sent %d emails, want 1", len(email.bodies))
+			}
+			if !strings.HasPrefix(email.bodies[0], tt.wantBody) {
+				t.Errorf("body = %q, want the %q template", email.bodies[0], tt.wantBody)
+			}
+			if !strings.Contains(email.subjects[0], tt.wantSubject) {
+				t.Errorf("subject = %q, want %q", email.subjects[0], tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestEmailSubjectFollowsNotificationType(t *testing.T) {
+	tests := []struct {
+		notificationType string
+		metadata         map[string]string
+		locale           string
+		want             string
+	}{
+		{notification_models.NotificationTypeUserCreated, nil, "", "Welcome to Acme, Jane!"},
+		{notification_models.NotificationTypeUserCreated, nil, "es", "¡Bienvenido a Acme, Jane!"},
+		{notification_models.NotificationTypeRiskDetected, map[string]string{"risk_level": "MEDIUM"}, "", "\U0001F6A8 Security Alert - MEDIUM Risk Detected"},
+		{notification_models.NotificationTypeCriticalRisk, nil, "", "\U0001F6A8 Security Alert - HIGH Risk Detected"},
+		{notification_models.NotificationTypeEmailChange, map[string]string{"verification_token": "token"}, "", "Confirm Your New Email Address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notificationType+"/"+tt.locale, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			email := &recordingEmail{}
+			h.emailProvider = email
+
+			_, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+				UserId:    "user-1",
+				Type:      tt.notificationType,
+				Message:   "Blocked domain",
+				Email:     "jane@example.com",
+				FirstName: "Jane",
+				Locale:    tt.locale,
+				Metadata:  tt.metadata,
+				Channels:  []string{notification_models.ChannelEmail},
+			})
+			if err != nil {
+				t.Fatalf("SendNotification() error = %v", err)
+			}
+			if len(email.subjects) != 1 || email.subjects[0] != tt.want {
+				t.Errorf("subjects = %q, want %q", email.subjects, tt.want)
+			}
+		})
+	}
+}
@@ -4,47 +4,72 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
 	notification_models "user-risk-system/cmd/notification/models"
 	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/cmd/notification/repository"
 	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/auth"
 	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/errors"
 	"user-risk-system/pkg/logger"
 	"user-risk-system/pkg/messaging"
 	"user-risk-system/pkg/models"
+	"user-risk-system/pkg/scontext"
 	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
 )
 
 // NotificationHandler orchestrates notification delivery across multiple channels and providers.
 // implements both gRPC services and message queue consumers for flexible notification processing.
 type NotificationHandler struct {
 	pb_notification.UnimplementedNotificationServiceServer
-	messageQueue    *messaging.RabbitMQ
+	messageQueue    messaging.Messaging
+	userClient      pb_user.UserServiceClient      // Resolves broadcast recipients
+	riskAdminClient pb_risk.RiskAdminServiceClient // Resolves risk-level broadcast segments
 	config          *config.Config
 	emailProvider   providers.EmailProvider
 	smsProvider     providers.SMSProvider
 	pushProvider    providers.PushProvider
+	webhookProvider providers.WebhookProvider // nil when WEBHOOK_URL/WEBHOOK_SECRET are not set
+	alertProvider   providers.AlertProvider   // nil when SLACK_WEBHOOK_URL is not set
 	templateManager *templates.EmailTemplateManager
 	logger          *logger.Logger
+	consumers       sync.WaitGroup // Tracks running queue consumers
+	broadcasts      sync.WaitGroup // Tracks broadcasts still being enqueued in the background
+	throttle        *throttle      // Per-user, per-type send counters, see throttled
+
+	suppressions *repository.SuppressionRepository // nil until EnableSuppressions, nothing is suppressed then
+	events       *repository.EventRepository       // nil until EnableTimeline, no transitions are recorded then
+
+	notifications *repository.NotificationRepository // nil until EnableStatusTracking, no status is stored then
 }
 
 // NewNotificationHandler creates a new notification handler with the provided dependencies.
 // initializes all notification providers based on configuration settings.
 func NewNotificationHandler(
-	messageQueue *messaging.RabbitMQ,
+	messageQueue messaging.Messaging,
+	userClient pb_user.UserServiceClient,
+	riskAdminClient pb_risk.RiskAdminServiceClient,
 	cfg *config.Config,
 	templateManager *templates.EmailTemplateManager,
 	appLogger *logger.Logger,
 ) *NotificationHandler {
 	handler := &NotificationHandler{
 		messageQueue:    messageQueue,
+		userClient:      userClient,
+		riskAdminClient: riskAdminClient,
 		config:          cfg,
 		templateManager: templateManager,
 		logger:          appLogger,
+		throttle:        newThrottle(),
 	}
 
 	handler.initializeProviders()
@@ -62,14 +87,15 @@ func (h *NotificationHandler) initializeProviders() {
 				h.config.SendGridAPIKey,
 				h.config.SendGridFromEmail,
 				h.config.SendGridFromName,
+				h.logger,
 			)
 			h.logger.Info("Email provider initialized: SendGrid")
 		} else {
-			h.logger.Warn("SendGrid API key not configured, falling back to simulation")
-			h.emailProvider = providers.NewSimulateEmailProvider()
+			h.simulationFallback("SendGrid API key not configured")
+			h.emailProvider = providers.NewSimulateEmailProvider(h.logger)
 		}
 	default:
-		h.emailProvider = providers.NewSimulateEmailProvider()
+		h.emailProvider = providers.NewSimulateEmailProvider(h.logger)
 		h.logger.Info("Email provider initialized: Simulate")
 	}
 
@@ -81,35 +107,101 @@ func (h *NotificationHandler) initializeProviders() {
 				h.config.TwilioAccountSID,
 				h.config.TwilioAuthToken,
 				h.config.TwilioFromNumber,
+				h.logger,
 			)
 			if twilioProvider != nil {
 				h.smsProvider = twilioProvider
 				h.logger.Info("SMS provider initialized: Twilio")
 			} else {
-				h.smsProvider = providers.NewSimulateSMSProvider()
-				h.logger.Warn("Twilio not configured properly, using simulation")
+				h.smsProvider = providers.NewSimulateSMSProvider(h.logger)
+				h.simulationFallback("Twilio not configured properly")
 			}
 		} else {
-			h.smsProvider = providers.NewSimulateSMSProvider()
-			h.logger.Warn("Twilio credentials not configured, using simulation")
+			h.smsProvider = providers.NewSimulateSMSProvider(h.logger)
+			h.simulationFallback("Twilio credentials not configured")
 		}
 	default:
-		h.smsProvider = providers.NewSimulateSMSProvider()
+		h.smsProvider = providers.NewSimulateSMSProvider(h.logger)
 		h.logger.Info("SMS provider initialized: Simulate")
 	}
 
 	// Push Provider (always simulate for now)
-	h.pushProvider = providers.NewSimulatePushProvider()
+	h.pushProvider = providers.NewSimulatePushProvider(h.logger)
 	h.logger.Info("Push provider: Simulate")
+
+	// Webhook Provider (optional)
+	if webhookProvider := providers.NewWebhookProvider(
+		h.config.WebhookURL,
+		h.config.WebhookSecret,
+		h.config.WebhookSecrets,
+		h.config.WebhookTimeout,
+	); webhookProvider != nil {
+		h.webhookProvider = webhookProvider
+		h.logger.Info("Webhook provider initialized", "url", h.config.WebhookURL)
+	}
+
+	// Admin alert Provider (optional)
+	if slackProvider := providers.NewSlackProvider(h.config.SlackWebhookURL); slackProvider != nil {
+		h.alertProvider = slackProvider
+		h.logger.Info("Alert provider initialized: Slack")
+	} else {
+		h.logger.Info("Alert provider not configured, admin alerts use email only")
+	}
+
+	h.ensureProviders()
+}
+
+// simulationFallback logs that a selected provider is unconfigured and simulated instead.
+// config validation refuses to start in production, so there it can only be reached with a config
+// that skipped validation and is logged as an error, as nothing of the channel is really sent.
+func (h *NotificationHandler) simulationFallback(problem string) {
+	if h.config.IsProduction() {
+		h.logger.Error("Falling back to simulation in production, notifications are not delivered", fmt.Errorf("%s", problem))
+		return
+	}
+	h.logger.Warn(problem + ", falling back to simulation")
+}
+
+// ensureProviders replaces required providers a constructor left nil with simulation, and clears
+// optional ones holding a typed nil so their not-configured checks apply. Sends then can't nil-panic.
+func (h *NotificationHandler) ensureProviders() {
+	if isNilProvider(h.emailProvider) {
+		h.logger.Warn("Email provider is nil, falling back to simulation")
+		h.emailProvider = providers.NewSimulateEmailProvider(h.logger)
+	}
+	if isNilProvider(h.smsProvider) {
+		h.logger.Warn("SMS provider is nil, falling back to simulation")
+		h.smsProvider = providers.NewSimulateSMSProvider(h.logger)
+	}
+	if isNilProvider(h.pushProvider) {
+		h.logger.Warn("Push provider is nil, falling back to simulation")
+		h.pushProvider = providers.NewSimulatePushProvider(h.logger)
+	}
+	if isNilProvider(h.webhookProvider) {
+		h.webhookProvider = nil
+	}
+	if isNilProvider(h.alertProvider) {
+		h.alertProvider = nil
+	}
+}
+
+// isNilProvider returns true if provider is nil or an interface holding a nil pointer.
+func isNilProvider(provider interface{}) bool {
+	if provider == nil {
+		return true
+	}
+	v := reflect.ValueOf(provider)
+	return v.Kind() == reflect.Ptr && v.IsNil()
 }
 
 // SendNotification handles synchronous gRPC notification requests from other services.
-// determines appropriate channels based on notification type and sends via all relevant providers.
+// honors explicitly requested channels, otherwise determines them from the notification type.
 func (h *NotificationHandler) SendNotification(ctx context.Context, req *pb_notification.SendNotificationRequest) (*pb_notification.SendNotificationResponse, error) {
 	h.logger.InfoCtx(ctx, "Sending notification",
 		"type", req.Type,
 		"user_id", req.UserId,
 		"email", req.Email,
+		"check_id", req.CheckId,
 	)
 
 	notification := &notification_models.Notification{
@@ -118,27 +210,49 @@ func (h *NotificationHandler) SendNotification(ctx context.Context, req *pb_noti
 		Type:      req.Type,
 		Message:   req.Message,
 		Email:     req.Email,
+		FirstName: req.FirstName,
+		OrgID:     notificationOrg(ctx, req.OrgId),
+		Phone:     req.Phone,
+		PushToken: req.PushToken,
+		Locale:    req.Locale,
+		Metadata:  req.Metadata,
+		CheckID:   req.CheckId,
 		Channel:   notification_models.ChannelEmail, // Default to email
 		Status:    notification_models.NotificationStatusPending,
 		CreatedAt: time.Now(),
 	}
 
-	channels := h.determineChannels(req.Type)
-	success := true
-	var errorMsg string
+	channels, fallback := h.defaultChannels(ctx, req.Type, notification.Phone)
 
-	for _, channel := range channels {
-		notification.Channel = channel
-		if err := h.sendNotificationByChannel(ctx, notification); err != nil {
-			h.logger.ErrorCtx(ctx, "Failed to send notification", err,
-				"channel", channel,
-				"notification_id", notification.ID,
+	// Explicit channels replace the type defaults and must each have a recipient
+	if len(req.Channels) > 0 {
+		channels, fallback = nil, false
+		for _, channel := range req.Channels {
+			channels = append(channels, h.resolveChannels(strings.ToUpper(channel), req.Type)...)
+		}
+
+		if errs := notification.ValidateRecipients(channels); len(errs) > 0 {
+			h.logger.WarnCtx(ctx, "Invalid notification recipients",
+				"channels", channels,
+				"validation_errors", errs.Error(),
 			)
-			success = false
-			errorMsg = err.Error()
+			return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
 		}
 	}
 
+	h.recordCreated(ctx, notification)
+	if h.throttled(ctx, notification) {
+		return &pb_notification.SendNotificationResponse{
+			Success:        false,
+			Error:          "notification throttled",
+			Throttled:      true,
+			NotificationId: notification.ID,
+		}, nil
+	}
+
+	results := h.deliver(ctx, notification, channels, fallback)
+	success, delivered, errorMsg := deliveryOutcome(results, fallback)
+
 	if success {
 		now := time.Now()
 		notification.Status = notification_models.NotificationStatusSent
@@ -146,6 +260,7 @@ func (h *NotificationHandler) SendNotification(ctx context.Context, req *pb_noti
 		h.logger.InfoCtx(ctx, "Notification sent successfully",
 			"notification_id", notification.ID,
 			"channels", channels,
+			"delivered_channel", delivered,
 		)
 	} else {
 		notification.Status = notification_models.NotificationStatusFailed
@@ -153,8 +268,11 @@ func (h *NotificationHandler) SendNotification(ctx context.Context, req *pb_noti
 	}
 
 	return &pb_notification.SendNotificationResponse{
-		Success: success,
-		Error:   errorMsg,
+		Success:          success,
+		Error:            errorMsg,
+		ChannelResults:   results,
+		DeliveredChannel: delivered,
+		NotificationId:   notification.ID,
 	}, nil
 }
 
@@ -166,23 +284,53 @@ func (h *NotificationHandler) determineChannels(notificationType string) []strin
 		return []string{notification_models.ChannelEmail}
 	case notification_models.NotificationTypeRiskDetected:
 		// High priority - send via multiple channels
-		return []string{
+		channels := []string{
 			notification_models.ChannelEmail,
 			notification_models.ChannelSMS,
 			notification_models.ChannelPush,
 		}
+		if h.webhookProvider != nil {
+			channels = append(channels, notification_models.ChannelWebhook)
+		}
+		return channels
 	case notification_models.NotificationTypePasswordReset:
 		return []string{notification_models.ChannelEmail, notification_models.ChannelSMS}
 	case notification_models.NotificationTypeLoginAlert:
 		return []string{notification_models.ChannelEmail, notification_models.ChannelPush}
+	case notification_models.NotificationTypeCriticalRisk:
+		// Admin alerts go to on-call chat in addition to email
+		if h.alertProvider != nil {
+			return []string{notification_models.ChannelEmail, notification_models.ChannelSlack}
+		}
+		return []string{notification_models.ChannelEmail}
 	default:
 		return []string{notification_models.ChannelEmail}
 	}
 }
 
+// notificationOrg returns the organization a notification sent by the caller of ctx belongs to.
+// services name it in the request, anyone else only sends for their own organization.
+func notificationOrg(ctx context.Context, requested string) string {
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.HasRole(auth.RoleService) {
+		return auth.OrgOrDefault(requested)
+	}
+	return auth.OrgID(ctx)
+}
+
 // sendNotificationByChannel routes notifications to the appropriate provider based on channel type.
-// acts as a dispatcher between channel types and their respective implementations.
-func (h *NotificationHandler) sendNotificationByChannel(ctx context.Context, notification *notification_models.Notification) error {
+// acts as a dispatcher between channel types and their respective implementations. A panicking
+// provider fails the send instead of taking down the caller, e.g. a queue consumer goroutine.
+func (h *NotificationHandler) sendNotificationByChannel(ctx context.Context, notification *notification_models.Notification) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%s provider panicked: %v", notification.Channel, p)
+			h.logger.ErrorCtx(ctx, "Notification provider panicked", err,
+				"channel", notification.Channel,
+				"notification_id", notification.ID,
+			)
+		}
+	}()
+
 	switch notification.Channel {
 	case notification_models.ChannelEmail:
 		return h.sendEmailNotification(ctx, notification)
@@ -190,6 +338,10 @@ func (h *NotificationHandler) sendNotificationByChannel(ctx context.Context, not
 		return h.sendSMSNotification(ctx, notification)
 	case notification_models.ChannelPush:
 		return h.sendPushNotification(ctx, notification)
+	case notification_models.ChannelWebhook:
+		return h.sendWebhookNotification(ctx, notification)
+	case notification_models.ChannelSlack:
+		return h.sendSlackNotification(ctx, notification)
 	default:
 		return fmt.Errorf("unsupported notification channel: %s", notification.Channel)
 	}
@@ -198,35 +350,66 @@ func (h *NotificationHandler) sendNotificationByChannel(ctx context.Context, not
 // sendEmailNotification handles email delivery using configured email providers.
 // selects appropriate templates and renders them with notification data.
 func (h *NotificationHandler) sendEmailNotification(ctx context.Context, notification *notification_models.Notification) error {
+	if isNilProvider(h.emailProvider) {
+		return fmt.Errorf("email provider not configured")
+	}
+	if err := h.suppressed(ctx, notification.Email); err != nil {
+		return err
+	}
+
 	templateData := templates.EmailTemplateData{
 		UserID:    notification.UserID,
 		Email:     notification.Email,
-		FirstName: extractFirstName(notification.Message),
+		FirstName: notification.FirstName,
+		Locale:    notification.Locale,
+	}
+	if templateData.FirstName == "" {
+		templateData.FirstName = "User"
 	}
 
 	var templateName string
 	switch notification.Type {
 	case notification_models.NotificationTypeUserCreated:
 		templateName = "welcome"
-	case notification_models.NotificationTypeRiskDetected:
+	case notification_models.NotificationTypeRiskDetected, notification_models.NotificationTypeCriticalRisk:
 		templateName = "risk_alert"
 		templateData.Reason = notification.Message
-		templateData.RiskLevel = "HIGH" // Should be extracted from message
+		templateData.RiskLevel = notification.Metadata["risk_level"]
+		if templateData.RiskLevel == "" {
+			templateData.RiskLevel = "HIGH"
+		}
+	case notification_models.NotificationTypeEmailChange:
+		templateName = "email_change"
+		templateData.VerificationToken = notification.Metadata["verification_token"]
+	case notification_models.NotificationTypeTest:
+		templateName = testTemplate
 	default:
 		templateName = "welcome"
 	}
 
-	subject, htmlBody, err := h.templateManager.RenderTemplate(templateName, templateData)
-	if err != nil {
+	var subject, htmlBody string
+	var err error
+	if templateName == testTemplate {
+		subject, htmlBody = h.testEmail()
+	} else if subject, htmlBody, err = h.templateManager.RenderTemplate(templateName, templateData); err != nil {
 		return err
 	}
 
 	notification.Provider = h.emailProvider.GetProviderName()
 
-	err = h.emailProvider.SendEmail(notification.Email, subject, htmlBody, map[string]interface{}{
-		"template": templateName,
-		"user_id":  notification.UserID,
+	recipients := providers.EmailRecipients{
+		To:      notification.Email,
+		CC:      h.config.EmailCC[notification.Type],
+		BCC:     h.config.EmailBCC[notification.Type],
+		ReplyTo: h.config.EmailReplyTo[notification.Type],
+	}
+
+	result, err := h.emailProvider.SendEmail(recipients, subject, htmlBody, map[string]interface{}{
+		"template":        templateName,
+		"user_id":         notification.UserID,
+		"notification_id": notification.ID,
 	})
+	recordSendResult(notification, result)
 
 	if err != nil {
 		h.logger.ErrorCtx(ctx, "Email sending failed", err,
@@ -248,16 +431,30 @@ func (h *NotificationHandler) sendEmailNotification(ctx context.Context, notific
 // sendSMSNotification handles SMS delivery using configured SMS providers.
 // formats messages appropriately for SMS length constraints.
 func (h *NotificationHandler) sendSMSNotification(ctx context.Context, notification *notification_models.Notification) error {
-	message := h.getSMSMessage(notification.Type, notification.Message)
+	if isNilProvider(h.smsProvider) {
+		return fmt.Errorf("sms provider not configured")
+	}
+	if !notification_models.IsE164(notification.Phone) {
+		return fmt.Errorf("no valid E.164 phone number for SMS")
+	}
+	if err := h.suppressed(ctx, notification.Phone); err != nil {
+		return err
+	}
+	message := h.getSMSMessage(notification.Type, notification.Message, notification.Locale)
 
 	notification.Provider = h.smsProvider.GetProviderName()
-	return h.smsProvider.SendSMS(notification.Phone, message)
+	result, err := h.smsProvider.SendSMS(notification.Phone, message)
+	recordSendResult(notification, result)
+	return err
 }
 
 // sendPushNotification handles push notification delivery using configured push providers.
 // formats titles and messages with additional metadata for mobile apps.
 func (h *NotificationHandler) sendPushNotification(ctx context.Context, notification *notification_models.Notification) error {
-	title := h.getPushTitle(notification.Type)
+	if isNilProvider(h.pushProvider) {
+		return fmt.Errorf("push provider not configured")
+	}
+	title := h.getPushTitle(notification.Type, notification.Locale)
 	message := notification.Message
 
 	data := map[string]interface{}{
@@ -265,35 +462,82 @@ func (h *NotificationHandler) sendPushNotification(ctx context.Context, notifica
 		"user_id": notification.UserID,
 	}
 
+	// Prefer an explicit device token over the user-level target
+	target := notification.UserID
+	if notification.PushToken != "" {
+		target = notification.PushToken
+	}
+
 	notification.Provider = h.pushProvider.GetProviderName()
-	return h.pushProvider.SendPush(notification.UserID, title, message, data)
+	result, err := h.pushProvider.SendPush(target, title, message, data)
+	recordSendResult(notification, result)
+	return err
 }
 
-// getEmailSubject generates email subject lines based on notification type.
-// includes emojis and urgency indicators for better user experience.
-func (h *NotificationHandler) getEmailSubject(notificationType string) string {
-	switch notificationType {
-	case notification_models.NotificationTypeUserCreated:
-		return "Welcome to User Risk Management System!"
-	case notification_models.NotificationTypeRiskDetected:
-		return "🚨 Security Alert - Risk Detected"
-	case notification_models.NotificationTypePasswordReset:
-		return "Password Reset Request"
-	case notification_models.NotificationTypeLoginAlert:
-		return "🔐 New Login Alert"
-	default:
-		return "Notification from User Risk System"
+// recordSendResult stores the provider result of the last send on notification.
+func recordSendResult(notification *notification_models.Notification, result providers.SendResult) {
+	notification.ProviderMessageID = result.ProviderMessageID
+	notification.ProviderStatusCode = result.StatusCode
+	notification.ProviderLatency = result.Latency
+}
+
+// sendWebhookNotification delivers the notification to the configured webhook endpoint.
+// the payload is signed by the provider so integrators can verify its origin.
+func (h *NotificationHandler) sendWebhookNotification(ctx context.Context, notification *notification_models.Notification) error {
+	if isNilProvider(h.webhookProvider) {
+		return fmt.Errorf("webhook provider not configured")
 	}
+
+	payload := map[string]interface{}{
+		"id":         notification.ID,
+		"type":       notification.Type,
+		"user_id":    notification.UserID,
+		"org_id":     notification.OrgID,
+		"email":      notification.Email,
+		"message":    notification.Message,
+		"created_at": notification.CreatedAt,
+	}
+	if notification.CheckID != "" {
+		payload["check_id"] = notification.CheckID
+	}
+
+	notification.Provider = h.webhookProvider.GetProviderName()
+	return h.webhookProvider.SendWebhook(notification.OrgID, notification.Type, payload)
+}
+
+// sendSlackNotification posts admin alerts to the configured chat provider.
+// risk details are taken from the notification metadata set by the caller.
+func (h *NotificationHandler) sendSlackNotification(ctx context.Context, notification *notification_models.Notification) error {
+	if isNilProvider(h.alertProvider) {
+		return fmt.Errorf("alert provider not configured")
+	}
+
+	alert := providers.Alert{
+		Title:     "Critical Risk Alert",
+		RiskLevel: notification.Metadata["risk_level"],
+		UserID:    notification.Metadata["subject_user_id"],
+		UserEmail: notification.Metadata["subject_email"],
+		Reason:    notification.Metadata["reason"],
+	}
+	if flags := notification.Metadata["flags"]; flags != "" {
+		alert.Flags = strings.Split(flags, ",")
+	}
+	if alert.Reason == "" {
+		alert.Reason = notification.Message
+	}
+
+	notification.Provider = h.alertProvider.GetProviderName()
+	return h.alertProvider.SendAlert(alert)
 }
 
 // getSMSMessage formats messages for SMS delivery with length constraints.
 // truncates long messages and adds context-appropriate prefixes.
-func (h *NotificationHandler) getSMSMessage(notificationType, message string) string {
+func (h *NotificationHandler) getSMSMessage(notificationType, message, locale string) string {
 	switch notificationType {
 	case notification_models.NotificationTypeRiskDetected:
-		return fmt.Sprintf("🚨 SECURITY ALERT: %s Please check your email for details.", message)
+		return templates.Translate(locale, "sms.risk_detected", message)
 	case notification_models.NotificationTypePasswordReset:
-		return fmt.Sprintf("Password reset requested. %s", message)
+		return templates.Translate(locale, "sms.password_reset", message)
 	default:
 		// Truncate long messages for SMS
 		if len(message) > 140 {
@@ -305,46 +549,57 @@ func (h *NotificationHandler) getSMSMessage(notificationType, message string) st
 
 // getPushTitle generates concise titles for push notifications based on type.
 // Titles are optimized for mobile notification display constraints.
-func (h *NotificationHandler) getPushTitle(notificationType string) string {
+func (h *NotificationHandler) getPushTitle(notificationType, locale string) string {
 	switch notificationType {
 	case notification_models.NotificationTypeRiskDetected:
-		return "Security Alert"
+		return templates.Translate(locale, "push.risk_detected")
 	case notification_models.NotificationTypeLoginAlert:
-		return "New Login"
+		return templates.Translate(locale, "push.login_alert")
 	default:
-		return "Notification"
+		return templates.Translate(locale, "push.default")
 	}
 }
 
 // StartMessageConsumer initializes all message queue consumers for asynchronous processing.
-func (h *NotificationHandler) StartMessageConsumer() {
-	go func() {
-		h.logger.Info("Starting user.created queue consumer...")
-		err := h.messageQueue.Consume("user.created", h.handleUserCreatedEvent)
-		if err != nil {
-			h.logger.Error("Error consuming user.created queue", err)
-		}
-	}()
+// consumers run until ctx is cancelled; use WaitForConsumers to block until they have drained.
+func (h *NotificationHandler) StartMessageConsumer(ctx context.Context) {
+	h.startConsumer(ctx, "user.created", h.handleUserCreatedEvent)
 
 	// Consume risk detected events
-	go func() {
-		h.logger.Info("Starting risk.detected queue consumer...")
-		err := h.messageQueue.Consume("risk.detected", h.handleRiskDetectedEvent)
-		if err != nil {
-			h.logger.Error("Error consuming risk.detected queue", err)
-		}
-	}()
+	h.startConsumer(ctx, "risk.detected", h.handleRiskDetectedEvent)
+
+	// Consume risk level changes, forwarded to the webhook when one is configured
+	h.startConsumer(ctx, models.EventRiskLevelChanged, h.handleRiskLevelChangedEvent)
 
 	// Consume direct notification requests
+	h.startConsumer(ctx, "notifications", h.handleNotificationEvent)
+}
+
+// startConsumer runs a single queue consumer in its own goroutine tracked by the consumer wait group.
+func (h *NotificationHandler) startConsumer(ctx context.Context, queue string, handler func([]byte) error) {
+	h.consumers.Add(1)
 	go func() {
-		h.logger.Info("Starting notifications queue consumer...")
-		err := h.messageQueue.Consume("notifications", h.handleNotificationEvent)
-		if err != nil {
-			h.logger.Error("Error consuming notifications queue", err)
+		defer h.consumers.Done()
+		h.logger.Info(fmt.Sprintf("Starting %s queue consumer...", queue))
+		if err := h.messageQueue.Consume(ctx, queue, handler); err != nil {
+			h.logger.Error(fmt.Sprintf("Error consuming %s queue", queue), err)
+			return
 		}
+		h.logger.Info(fmt.Sprintf("Stopped %s queue consumer", queue))
 	}()
 }
 
+// WaitForConsumers blocks until every consumer started by StartMessageConsumer has returned.
+func (h *NotificationHandler) WaitForConsumers() {
+	h.consumers.Wait()
+}
+
+// WaitForBroadcasts blocks until every accepted broadcast has enqueued all of its notifications.
+// call it once the gRPC server stopped accepting broadcasts and before the message queue is closed.
+func (h *NotificationHandler) WaitForBroadcasts() {
+	h.broadcasts.Wait()
+}
+
 // handleUserCreatedEvent processes user registration events from the message queue.
 func (h *NotificationHandler) handleUserCreatedEvent(data []byte) error {
 	var event models.UserCreatedEvent
@@ -352,10 +607,27 @@ func (h *NotificationHandler) handleUserCreatedEvent(data []byte) error {
 		return fmt.Errorf("failed to unmarshal user created event: %w", err)
 	}
 
-	h.logger.Info("Processing user created event",
-		"user_id", event.UserID,
-		"email", event.Email,
-	)
+	version, err := event.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("cannot process user created event: %w", err)
+	}
+
+	switch version {
+	case models.EventVersionV1:
+		h.logger.Info("Processing user created event",
+			"user_id", event.UserID,
+			"email", event.Email,
+			"event_version", version,
+		)
+	case models.EventVersionV2:
+		h.logger.Info("Processing user created event",
+			"user_id", event.UserID,
+			"email", event.Email,
+			"event_version", version,
+			"event_id", event.EventID,
+			"emitted_at", event.EmittedAt,
+		)
+	}
 
 	notification := &notification_models.Notification{
 		ID:        uuid.New().String(),
@@ -363,14 +635,20 @@ func (h *NotificationHandler) handleUserCreatedEvent(data []byte) error {
 		Type:      notification_models.NotificationTypeUserCreated,
 		Message:   fmt.Sprintf("Welcome %s %s! Your account has been created successfully.", event.FirstName, event.LastName),
 		Email:     event.Email,
+		FirstName: event.FirstName,
+		Phone:     event.Phone,
+		Locale:    event.Locale,
+		Channel:   notification_models.ChannelEmail,
 		Status:    notification_models.NotificationStatusPending,
 		CreatedAt: time.Now(),
 	}
 
-	ctx := context.WithValue(context.Background(), "user_id", event.UserID)
-	ctx = context.WithValue(ctx, "user_email", event.Email)
+	ctx := scontext.New(context.Background()).WithUserID(event.UserID).WithUserEmail(event.Email).Build()
 
-	if err := h.sendEmailNotification(ctx, notification); err != nil {
+	h.recordCreated(ctx, notification)
+	err = h.sendEmailNotification(ctx, notification)
+	h.recordSendAttempt(ctx, notification, err)
+	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to send welcome email", err,
 			"notification_id", notification.ID,
 		)
@@ -397,10 +675,28 @@ func (h *NotificationHandler) handleRiskDetectedEvent(data []byte) error {
 		return fmt.Errorf("failed to unmarshal risk detected event: %w", err)
 	}
 
-	h.logger.Info("Processing risk detected event",
-		"user_id", event.UserID,
-		"risk_level", event.RiskLevel,
-	)
+	version, err := event.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("cannot process risk detected event: %w", err)
+	}
+
+	switch version {
+	case models.EventVersionV1:
+		h.logger.Info("Processing risk detected event",
+			"user_id", event.UserID,
+			"risk_level", event.RiskLevel,
+			"event_version", version,
+		)
+	case models.EventVersionV2:
+		h.logger.Info("Processing risk detected event",
+			"user_id", event.UserID,
+			"risk_level", event.RiskLevel,
+			"event_version", version,
+			"event_id", event.EventID,
+			"emitted_at", event.EmittedAt,
+			"check_id", event.CheckID,
+		)
+	}
 
 	notification := &notification_models.Notification{
 		ID:        uuid.New().String(),
@@ -408,34 +704,35 @@ func (h *NotificationHandler) handleRiskDetectedEvent(data []byte) error {
 		Type:      notification_models.NotificationTypeRiskDetected,
 		Message:   fmt.Sprintf("Risk Alert: %s (Level: %s, Flags: %s)", event.Reason, event.RiskLevel, strings.Join(event.Flags, ", ")),
 		Email:     event.Email,
+		FirstName: event.FirstName,
+		OrgID:     auth.OrgOrDefault(event.OrgID),
+		Phone:     event.Phone,
+		Locale:    event.Locale,
+		Metadata:  map[string]string{"risk_level": event.RiskLevel},
+		CheckID:   event.CheckID,
 		Status:    notification_models.NotificationStatusPending,
 		CreatedAt: time.Now(),
 	}
 
-	ctx := context.WithValue(context.Background(), "user_id", event.UserID)
-	ctx = context.WithValue(ctx, "user_email", event.Email)
+	ctx := scontext.New(context.Background()).WithUserID(event.UserID).WithUserEmail(event.Email).Build()
 
-	channels := h.determineChannels(notification.Type)
-	success := true
-
-	for _, channel := range channels {
-		notification.Channel = channel
-		if err := h.sendNotificationByChannel(ctx, notification); err != nil {
-			h.logger.ErrorCtx(ctx, "Failed to send notification", err,
-				"channel", channel,
-				"notification_id", notification.ID,
-			)
-			success = false
-		}
+	h.recordCreated(ctx, notification)
+	if h.throttled(ctx, notification) {
+		return nil
 	}
 
+	channels, fallback := h.defaultChannels(ctx, notification.Type, notification.Phone)
+	success, delivered, _ := deliveryOutcome(h.deliver(ctx, notification, channels, fallback), fallback)
+
 	if success {
 		now := time.Now()
 		notification.Status = notification_models.NotificationStatusSent
 		notification.SentAt = &now
 		h.logger.InfoCtx(ctx, "Risk alert notifications sent successfully",
 			"notification_id", notification.ID,
+			"check_id", notification.CheckID,
 			"channels", channels,
+			"delivered_channel", delivered,
 		)
 	} else {
 		notification.Status = notification_models.NotificationStatusFailed
@@ -444,31 +741,106 @@ func (h *NotificationHandler) handleRiskDetectedEvent(data []byte) error {
 	return nil
 }
 
+// handleRiskLevelChangedEvent forwards risk level changes to the configured webhook.
+// the event is only logged when no webhook is configured.
+func (h *NotificationHandler) handleRiskLevelChangedEvent(data []byte) error {
+	var event models.RiskLevelChangedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal risk level changed event: %w", err)
+	}
+
+	if _, err := event.SchemaVersion(); err != nil {
+		return fmt.Errorf("cannot process risk level changed event: %w", err)
+	}
+
+	ctx := scontext.New(context.Background()).WithUserID(event.UserID).Build()
+	h.logger.InfoCtx(ctx, "Processing risk level changed event",
+		"user_id", event.UserID,
+		"check_id", event.CheckID,
+		"old_level", event.OldLevel,
+		"new_level", event.NewLevel,
+		"event_id", event.EventID,
+	)
+
+	if h.webhookProvider == nil {
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"event_id":   event.EventID,
+		"user_id":    event.UserID,
+		"org_id":     event.OrgID,
+		"check_id":   event.CheckID,
+		"old_level":  event.OldLevel,
+		"new_level":  event.NewLevel,
+		"changed_at": event.ChangedAt,
+	}
+	if err := h.webhookProvider.SendWebhook(auth.OrgOrDefault(event.OrgID), models.EventRiskLevelChanged, payload); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to deliver risk level change webhook", err, "event_id", event.EventID)
+		return err
+	}
+	return nil
+}
+
 // handleNotificationEvent processes direct notification requests from the message queue.
-// handles generic notifications that don't fit into specific event categories.
+// validates the inbound message contract and rejects malformed messages to the dead-letter queue.
 func (h *NotificationHandler) handleNotificationEvent(data []byte) error {
-	var notification notification_models.Notification
-	if err := json.Unmarshal(data, &notification); err != nil {
-		return fmt.Errorf("failed to unmarshal notification event: %w", err)
+	var msg notification_models.NotificationMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return messaging.Reject("malformed notification message: %v", err)
+	}
+
+	channels, fallback := h.resolveChannels(msg.Channel, msg.Type), false
+	if msg.Channel == "" {
+		channels, fallback = h.defaultChannels(context.Background(), msg.Type, msg.Phone)
+	}
+	if errs := msg.Validate(channels); len(errs) > 0 {
+		h.logger.Warn("Invalid notification message",
+			"notification_id", msg.ID,
+			"type", msg.Type,
+			"validation_errors", errs.Error(),
+		)
+		return messaging.Reject("invalid notification message: %s", errs.Error())
+	}
+
+	notification := &notification_models.Notification{
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		Type:      msg.Type,
+		Message:   msg.Message,
+		Email:     msg.Email,
+		OrgID:     auth.OrgOrDefault(msg.OrgID),
+		Phone:     msg.Phone,
+		PushToken: msg.PushToken,
+		Locale:    msg.Locale,
+		Status:    notification_models.NotificationStatusPending,
+		CreatedAt: time.Now(),
+	}
+	created := notification.ID == "" // Otherwise the producer, e.g. a broadcast, recorded the creation
+	if created {
+		notification.ID = uuid.New().String()
 	}
 
 	h.logger.Info("Processing direct notification",
 		"notification_id", notification.ID,
 		"type", notification.Type,
+		"channels", channels,
 	)
 
-	ctx := context.WithValue(context.Background(), "user_id", notification.UserID)
-	if notification.Email != "" {
-		ctx = context.WithValue(ctx, "user_email", notification.Email)
+	ctx := scontext.New(context.Background()).WithUserID(notification.UserID).WithUserEmail(notification.Email).Build()
+
+	if created {
+		h.recordCreated(ctx, notification)
+	}
+	if h.throttled(ctx, notification) {
+		return nil
 	}
 
-	if err := h.sendNotificationByChannel(ctx, &notification); err != nil {
-		h.logger.ErrorCtx(ctx, "Failed to send notification", err,
-			"notification_id", notification.ID,
-		)
+	success, delivered, errorMsg := deliveryOutcome(h.deliver(ctx, notification, channels, fallback), fallback)
+	if !success {
 		notification.Status = notification_models.NotificationStatusFailed
-		notification.Error = err.Error()
-		return err
+		notification.Error = errorMsg
+		return fmt.Errorf("failed to send notification: %s", errorMsg)
 	}
 
 	now := time.Now()
@@ -476,18 +848,46 @@ func (h *NotificationHandler) handleNotificationEvent(data []byte) error {
 	notification.SentAt = &now
 	h.logger.InfoCtx(ctx, "Direct notification sent successfully",
 		"notification_id", notification.ID,
+		"delivered_channel", delivered,
 	)
 
 	return nil
 }
 
-// extractFirstName parses user names from welcome messages for personalization.
-func extractFirstName(message string) string {
-	if strings.Contains(message, "Welcome") {
-		parts := strings.Split(message, " ")
-		if len(parts) > 1 {
-			return parts[1]
+// dropUnreachableSMS removes SMS from the type default channels when phone isn't an E.164 number.
+// explicitly requested SMS is validated instead, so the caller gets an error rather than a skip.
+func (h *NotificationHandler) dropUnreachableSMS(ctx context.Context, phone string, channels []string) []string {
+	if notification_models.IsE164(phone) {
+		return channels
+	}
+	kept := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		if channel == notification_models.ChannelSMS {
+			h.logger.InfoCtx(ctx, "Skipping SMS, recipient has no valid E.164 phone number", "has_phone", phone != "")
+			continue
 		}
+		kept = append(kept, channel)
+	}
+	return kept
+}
+
+// resolveChannels returns the delivery channels for an inbound message.
+// an empty channel defaults from the notification type and ALL expands to every channel.
+func (h *NotificationHandler) resolveChannels(channel, notificationType string) []string {
+	switch channel {
+	case "":
+		return h.determineChannels(notificationType)
+	case notification_models.ChannelAll:
+		channels := []string{
+			notification_models.ChannelEmail,
+			notification_models.ChannelSMS,
+			notification_models.ChannelPush,
+		}
+		if h.webhookProvider != nil {
+			channels = append(channels, notification_models.ChannelWebhook)
+		}
+		return channels
+	default:
+		return []string{channel}
 	}
-	return "User"
 }
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+)
+
+func TestEnsureProvidersReplacesNilProviders(t *testing.T) {
+	h := newTestSendHandler(t)
+	h.emailProvider = nil
+	h.smsProvider = (*providers.TwilioProvider)(nil)
+	h.pushProvider = nil
+	h.webhookProvider = (*providers.HTTPWebhookProvider)(nil)
+	h.alertProvider = (*providers.SlackProvider)(nil)
+
+	h.ensureProviders()
+
+	for channel, provider := range map[string]interface{ GetProviderName() string }{
+		"email": h.emailProvider,
+		"sms":   h.smsProvider,
+		"push":  h.pushProvider,
+	} {
+		if isNilProvider(provider) || !strings.HasPrefix(provider.GetProviderName(), "SIMULATE") {
+			t.Errorf("%s provider = %#v, want the simulator", channel, provider)
+		}
+	}
+	if h.webhookProvider != nil || h.alertProvider != nil {
+		t.Errorf("optional providers = %#v, %#v, want typed nils cleared", h.webhookProvider, h.alertProvider)
+	}
+}
+
+func TestSendWithNilSMSProviderFails(t *testing.T) {
+	for name, provider := range map[string]providers.SMSProvider{
+		"nil":       nil,
+		"typed nil": (*providers.TwilioProvider)(nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			h.smsProvider = provider
+
+			notification := &notification_models.Notification{ID: "n-1", Channel: notification_models.ChannelSMS, Phone: "+15551234567", Message: "hello"}
+			err := h.sendNotificationByChannel(context.Background(), notification)
+			if err == nil || !strings.Contains(err.Error(), "not configured") {
+				t.Errorf("sendNotificationByChannel() error = %v, want sms provider not configured", err)
+			}
+		})
+	}
+}
+
+func TestSimulationFallbackAlarmsInProduction(t *testing.T) {
+	for environment, wantLevel := range map[string]string{"production": `"level":"ERROR"`, "development": `"level":"WARN"`} {
+		t.Run(environment, func(t *testing.T) {
+			var out bytes.Buffer
+			cfg := &config.Config{Environment: environment, EmailProvider: "SENDGRID", SMSProvider: "SIMULATE"}
+			h := NewNotificationHandler(messaging.NewInMemory(), nil, nil, cfg, templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg)),
+				logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &out}))
+
+			if h.emailProvider.GetProviderName() != "SIMULATE_EMAIL" {
+				t.Errorf("email provider = %s, want the simulation fallback", h.emailProvider.GetProviderName())
+			}
+			var fallback string
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.Contains(line, "SendGrid API key not configured") {
+					fallback = line
+				}
+			}
+			if !strings.Contains(fallback, wantLevel) {
+				t.Errorf("fallback log = %q, want %s", fallback, wantLevel)
+			}
+		})
+	}
+}
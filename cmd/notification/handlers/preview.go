@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// previewFields are the sample_data keys accepted by PreviewTemplate, each setting one template field.
+var previewFields = map[string]func(data *templates.EmailTemplateData, value string){
+	"user_id":            func(data *templates.EmailTemplateData, value string) { data.UserID = value },
+	"first_name":         func(data *templates.EmailTemplateData, value string) { data.FirstName = value },
+	"last_name":          func(data *templates.EmailTemplateData, value string) { data.LastName = value },
+	"email":              func(data *templates.EmailTemplateData, value string) { data.Email = value },
+	"reason":             func(data *templates.EmailTemplateData, value string) { data.Reason = value },
+	"risk_level":         func(data *templates.EmailTemplateData, value string) { data.RiskLevel = strings.ToUpper(value) },
+	"verification_token": func(data *templates.EmailTemplateData, value string) { data.VerificationToken = value },
+	"flags": func(data *templates.EmailTemplateData, value string) {
+		for _, flag := range strings.Split(value, ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				data.Flags = append(data.Flags, flag)
+			}
+		}
+	},
+}
+
+// PreviewTemplate renders an email template with sample data for template authors, nothing is sent.
+// unknown templates are NotFound, unknown or malformed sample fields are InvalidArgument.
+func (h *NotificationHandler) PreviewTemplate(ctx context.Context, req *pb_notification.PreviewTemplateRequest) (*pb_notification.PreviewTemplateResponse, error) {
+	errs := validator.New().
+		Required("template_name", req.TemplateName).
+		Email("email", req.SampleData["email"]).
+		Errors()
+	if req.TemplateName != "" && !h.templateManager.HasTemplate(req.TemplateName) {
+		return nil, errors.ErrTemplateNotFound.WithMessage("Template not found: " + req.TemplateName).GRPCStatus().Err()
+	}
+
+	keys := make([]string, 0, len(req.SampleData))
+	for key := range req.SampleData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := previewFields[key]; !ok {
+			errs = append(errs, validator.ValidationError{Field: key, Code: validator.CodeInvalidValue, Message: "is not a template field"})
+		}
+	}
+	if level := req.SampleData["risk_level"]; level != "" && !validRiskLevel(level) {
+		errs = append(errs, validator.ValidationError{Field: "risk_level", Code: validator.CodeInvalidValue, Message: "must be one of MINIMAL, LOW, MEDIUM, HIGH, CRITICAL"})
+	}
+	if len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	data := templates.EmailTemplateData{Locale: req.Locale}
+	for key, value := range req.SampleData {
+		previewFields[key](&data, value)
+	}
+
+	subject, html, err := h.templateManager.RenderTemplate(req.TemplateName, data)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to render template preview", err, "template", req.TemplateName)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to render template").GRPCStatus().Err()
+	}
+
+	return &pb_notification.PreviewTemplateResponse{
+		Subject: subject,
+		Html:    html,
+		Locale:  templates.NormalizeLocale(req.Locale),
+	}, nil
+}
+
+// ListTemplates returns the loaded email templates with the fields each references.
+func (h *NotificationHandler) ListTemplates(ctx context.Context, req *pb_notification.ListTemplatesRequest) (*pb_notification.ListTemplatesResponse, error) {
+	resp := &pb_notification.ListTemplatesResponse{}
+	for _, info := range h.templateManager.ListTemplates() {
+		resp.Templates = append(resp.Templates, &pb_notification.TemplateInfo{
+			Name:    info.Name,
+			Source:  info.Source,
+			Fields:  info.Fields,
+			Locales: info.Locales,
+		})
+	}
+	return resp, nil
+}
+
+// validRiskLevel returns true if level is one of the risk engine's risk levels.
+func validRiskLevel(level string) bool {
+	switch strings.ToUpper(level) {
+	case "MINIMAL", "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/cmd/notification/templates"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+func TestPreviewTemplateRendersWelcome(t *testing.T) {
+	h := newTestSendHandler(t)
+
+	resp, err := h.PreviewTemplate(context.Background(), &pb_notification.PreviewTemplateRequest{
+		TemplateName: "welcome",
+		SampleData:   map[string]string{"first_name": "Ada", "email": "ada@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("PreviewTemplate() error = %v", err)
+	}
+	if !strings.Contains(resp.Subject, "Ada") {
+		t.Errorf("subject = %q, want the sample first name", resp.Subject)
+	}
+	for _, want := range []string{"Welcome to Acme", "Hi Ada,", "ada@example.com"} {
+		if !strings.Contains(resp.Html, want) {
+			t.Errorf("html does not contain %q", want)
+		}
+	}
+	if resp.Locale != "en" {
+		t.Errorf("locale = %q, want the default en", resp.Locale)
+	}
+}
+
+func TestPreviewTemplateRejectsBadRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		sampleData map[string]string
+		want       codes.Code
+	}{
+		{"missing template name", "", nil, codes.InvalidArgument},
+		{"unknown template", "no_such_template", nil, codes.NotFound},
+		{"unknown sample field", "welcome", map[string]string{"favourite_colour": "blue"}, codes.InvalidArgument},
+		{"malformed email", "welcome", map[string]string{"email": "not-an-email"}, codes.InvalidArgument},
+		{"unknown risk level", "risk_alert", map[string]string{"risk_level": "SEVERE"}, codes.InvalidArgument},
+	}
+
+	h := newTestSendHandler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.PreviewTemplate(context.Background(), &pb_notification.PreviewTemplateRequest{TemplateName: tt.template, SampleData: tt.sampleData})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("PreviewTemplate() code = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestListTemplatesReportsFields(t *testing.T) {
+	h := newTestSendHandler(t)
+
+	resp, err := h.ListTemplates(context.Background(), &pb_notification.ListTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	listed := make(map[string]*pb_notification.TemplateInfo)
+	for _, info := range resp.Templates {
+		listed[info.Name] = info
+	}
+
+	for name, fields := range map[string][]string{
+		"welcome":    {"email", "first_name"},
+		"risk_alert": {"flags", "reason", "risk_level"},
+	} {
+		info, ok := listed[name]
+		if !ok {
+			t.Errorf("%s is not listed", name)
+			continue
+		}
+		if !reflect.DeepEqual(info.Fields, fields) {
+			t.Errorf("%s fields = %v, want %v without the manager's own fields", name, info.Fields, fields)
+		}
+		if info.Source != templates.SourceEmbedded {
+			t.Errorf("%s source = %q, want %q without a templates directory", name, info.Source, templates.SourceEmbedded)
+		}
+	}
+}
+
+func TestListTemplatesReportsFileTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for file, content := range map[string]string{
+		"welcome.html":    `<p>Hello {{.FirstName}} {{.LastName}}, sign in at {{.LoginURL}}</p>`,
+		"welcome.es.html": `<p>Hola {{.FirstName}}</p>`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := newTestSendHandler(t)
+	h.templateManager = templates.NewEmailTemplateManager(dir, templates.BaseData{})
+
+	resp, err := h.ListTemplates(context.Background(), &pb_notification.ListTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	for _, info := range resp.Templates {
+		if info.Name != "welcome" {
+			continue
+		}
+		if info.Source != templates.SourceFile || !reflect.DeepEqual(info.Fields, []string{"first_name", "last_name"}) || !reflect.DeepEqual(info.Locales, []string{"es"}) {
+			t.Errorf("welcome = %+v, want the file's fields and its es variant", info)
+		}
+		return
+	}
+	t.Error("welcome is not listed")
+}
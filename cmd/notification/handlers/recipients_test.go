@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+func TestEmailRecipientsFollowNotificationType(t *testing.T) {
+	h := newTestSendHandler(t)
+	h.config.EmailCC = map[string][]string{notification_models.NotificationTypeCriticalRisk: {"security@example.com", "soc@example.com"}}
+	h.config.EmailBCC = map[string][]string{notification_models.NotificationTypeCriticalRisk: {"audit@example.com"}}
+	h.config.EmailReplyTo = map[string]string{notification_models.NotificationTypeCriticalRisk: "support@example.com"}
+	email := &recordingEmail{}
+	h.emailProvider = email
+
+	for _, notificationType := range []string{notification_models.NotificationTypeCriticalRisk, notification_models.NotificationTypeUserCreated} {
+		_, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+			UserId:   "user-1",
+			Type:     notificationType,
+			Message:  "Risk detected",
+			Email:    "user@example.com",
+			Channels: []string{notification_models.ChannelEmail},
+		})
+		if err != nil {
+			t.Fatalf("SendNotification(%s) error = %v", notificationType, err)
+		}
+	}
+
+	want := []providers.EmailRecipients{
+		{To: "user@example.com", CC: []string{"security@example.com", "soc@example.com"}, BCC: []string{"audit@example.com"}, ReplyTo: "support@example.com"},
+		{To: "user@example.com"},
+	}
+	if !reflect.DeepEqual(email.recipients, want) {
+		t.Errorf("recipients = %+v, want %+v", email.recipients, want)
+	}
+}
+
+func TestExplicitChannelsReplaceTypeDefaults(t *testing.T) {
+	tests := []struct {
+		name             string
+		notificationType string
+		channels         []string
+		want             []string
+		wantEmails       int
+		wantSMS          int
+	}{
+		{"type defaults", notification_models.NotificationTypeRiskDetected, nil, []string{notification_models.ChannelEmail, notification_models.ChannelSMS, notification_models.ChannelPush}, 1, 1},
+		{"narrower than the defaults", notification_models.NotificationTypeRiskDetected, []string{"sms"}, []string{notification_models.ChannelSMS}, 0, 1},
+		{"outside the defaults", notification_models.NotificationTypeUserCreated, []string{"email", "sms"}, []string{notification_models.ChannelEmail, notification_models.ChannelSMS}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			email, sms := &recordingEmail{}, &recordingSMS{}
+			h.emailProvider, h.smsProvider = email, sms
+
+			resp, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+				UserId:   "user-1",
+				Type:     tt.notificationType,
+				Message:  "Risk detected",
+				Email:    "user@example.com",
+				Phone:    "+15550100",
+				Channels: tt.channels,
+			})
+			if err != nil {
+				t.Fatalf("SendNotification() error = %v", err)
+			}
+
+			var got []string
+			for _, result := range resp.ChannelResults {
+				got = append(got, result.Channel)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("channels = %v, want %v", got, tt.want)
+			}
+			if len(email.recipients) != tt.wantEmails || len(sms.numbers) != tt.wantSMS {
+				t.Errorf("sent %d emails and %d SMS, want %d and %d", len(email.recipients), len(sms.numbers), tt.wantEmails, tt.wantSMS)
+			}
+		})
+	}
+}
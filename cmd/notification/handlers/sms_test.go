@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/pkg/models"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// recordingSMS keeps the number of every SMS it sends and answers with smsResult.
+type recordingSMS struct {
+	mu      sync.Mutex
+	numbers []string
+}
+
+func (p *recordingSMS) SendSMS(to, _ string) (providers.SendResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.numbers = append(p.numbers, to)
+	return smsResult, nil
+}
+
+func (p *recordingSMS) GetProviderName() string { return "RECORDING" }
+
+var smsResult = providers.SendResult{ProviderMessageID: "SM123", StatusCode: 201, Latency: 40 * time.Millisecond}
+
+func TestRiskAlertSMSRequiresE164Phone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  []string
+	}{
+		{"no phone", "", nil},
+		{"malformed phone", "555-0100", nil},
+		{"E.164 phone", "+15550100", []string{"+15550100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			email, sms := &recordingEmail{}, &recordingSMS{}
+			h.emailProvider, h.smsProvider = email, sms
+
+			data, err := json.Marshal(models.RiskDetectedEvent{
+				EventMeta: models.NewEventMeta(),
+				UserID:    "user-1",
+				Email:     "user@example.com",
+				Phone:     tt.phone,
+				RiskLevel: "HIGH",
+				Reason:    "Disposable email domain",
+			})
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if err := h.handleRiskDetectedEvent(data); err != nil {
+				t.Fatalf("handleRiskDetectedEvent() error = %v", err)
+			}
+
+			if len(sms.numbers) != len(tt.want) || (len(tt.want) > 0 && sms.numbers[0] != tt.want[0]) {
+				t.Errorf("SMS sent to %v, want %v", sms.numbers, tt.want)
+			}
+			if len(email.bodies) != 1 {
+				t.Errorf("sent %d emails, want the alert email whatever the phone", len(email.bodies))
+			}
+		})
+	}
+}
+
+func TestExplicitSMSWithoutPhoneFailsValidation(t *testing.T) {
+	h := newTestSendHandler(t)
+	sms := &recordingSMS{}
+	h.smsProvider = sms
+
+	_, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+		UserId:   "user-1",
+		Type:     notification_models.NotificationTypeRiskDetected,
+		Message:  "Risk detected",
+		Email:    "user@example.com",
+		Channels: []string{notification_models.ChannelSMS},
+	})
+	if err == nil {
+		t.Fatal("SendNotification() accepted SMS without a phone number")
+	}
+	if len(sms.numbers) != 0 {
+		t.Errorf("SMS sent to %v, want none", sms.numbers)
+	}
+}
+
+func TestChannelResultCarriesProviderResult(t *testing.T) {
+	h := newTestSendHandler(t)
+	h.smsProvider = &recordingSMS{}
+
+	resp, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+		UserId:   "user-1",
+		Type:     notification_models.NotificationTypeRiskDetected,
+		Message:  "Risk detected",
+		Phone:    "+15550100",
+		Channels: []string{notification_models.ChannelSMS},
+	})
+	if err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if len(resp.ChannelResults) != 1 {
+		t.Fatalf("channel results = %v, want one", resp.ChannelResults)
+	}
+	result := resp.ChannelResults[0]
+	if result.Provider != "RECORDING" || result.ProviderMessageId != "SM123" || result.StatusCode != 201 || result.LatencyMs != 40 {
+		t.Errorf("channel result = %+v, want the provider's SendResult", result)
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/repository"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_notification "user-risk-system/proto/notification"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSuppressionLimit = 50  // Suppressions per ListSuppressions page when no limit is given
+	maxSuppressionLimit     = 500 // Upper bound of a ListSuppressions limit
+)
+
+// EnableSuppressions skips email and SMS sends to recipients on the suppression list of suppressions,
+// records bounces and spam complaints into it, and serves the suppression admin methods.
+func (h *NotificationHandler) EnableSuppressions(suppressions *repository.SuppressionRepository) {
+	h.suppressions = suppressions
+}
+
+// suppressed returns an error if recipient is on the suppression list, logging why it is skipped.
+// a failed lookup doesn't block the send, a missed bounce costs less than a lost notification.
+func (h *NotificationHandler) suppressed(ctx context.Context, recipient string) error {
+	if h.suppressions == nil || recipient == "" {
+		return nil
+	}
+
+	suppression, err := h.suppressions.Lookup(recipient)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to check suppression list, sending anyway", err)
+		return nil
+	}
+	if suppression == nil {
+		return nil
+	}
+
+	h.logger.WarnCtx(ctx, "Skipping suppressed recipient",
+		"channel", suppression.Channel,
+		"reason", suppression.Reason,
+		"suppressed_at", suppression.CreatedAt,
+	)
+	return fmt.Errorf("recipient is suppressed (%s)", suppression.Reason)
+}
+
+// suppressFromEvent adds the recipient of a bounce or spam complaint event to the suppression list.
+func (h *NotificationHandler) suppressFromEvent(ctx context.Context, event *pb_notification.DeliveryEvent, reason string) {
+	if h.suppressions == nil || event.Email == "" {
+		return
+	}
+
+	err := h.suppressions.Add(&notification_models.Suppression{
+		Recipient: event.Email,
+		Channel:   notification_models.ChannelEmail,
+		Reason:    reason,
+		Details:   event.Reason,
+	})
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to suppress recipient", err, "notification_id", event.NotificationId, "reason", reason)
+		return
+	}
+	h.logger.InfoCtx(ctx, "Recipient suppressed", "notification_id", event.NotificationId, "reason", reason)
+}
+
+// AddSuppression manually suppresses an email address or phone number via gRPC.
+func (h *NotificationHandler) AddSuppression(ctx context.Context, req *pb_notification.AddSuppressionRequest) (*pb_notification.Suppression, error) {
+	if h.suppressions == nil {
+		return nil, status.Error(codes.Unimplemented, "suppression list is not enabled")
+	}
+
+	channel := strings.ToUpper(req.Channel)
+	if channel == "" {
+		channel = notification_models.ChannelEmail
+		if strings.HasPrefix(req.Recipient, "+") {
+			channel = notification_models.ChannelSMS
+		}
+	}
+
+	v := validator.New().Required("recipient", req.Recipient)
+	if channel == notification_models.ChannelEmail {
+		v.Email("recipient", req.Recipient)
+	}
+	errs := v.Errors()
+	switch channel {
+	case notification_models.ChannelEmail:
+	case notification_models.ChannelSMS:
+		if req.Recipient != "" && !notification_models.IsE164(req.Recipient) {
+			errs = append(errs, validator.ValidationError{Field: "recipient", Code: validator.CodeInvalidPhone, Message: "must be an E.164 phone number"})
+		}
+	default:
+		errs = append(errs, validator.ValidationError{Field: "channel", Code: validator.CodeInvalidValue, Message: "must be EMAIL or SMS"})
+	}
+	if len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	suppression := &notification_models.Suppression{
+		Recipient: req.Recipient,
+		Channel:   channel,
+		Reason:    notification_models.SuppressionReasonManual,
+		Details:   req.Details,
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		suppression.CreatedBy = claims.UserID
+	}
+	if err := h.suppressions.Add(suppression); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to add suppression", err)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to add suppression").GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Recipient suppressed", "audit", true, "channel", channel, "reason", suppression.Reason)
+
+	return suppressionToProto(suppression), nil
+}
+
+// RemoveSuppression lifts the suppression of a recipient via gRPC, NotFound if it isn't suppressed.
+func (h *NotificationHandler) RemoveSuppression(ctx context.Context, req *pb_notification.RemoveSuppressionRequest) (*pb_notification.RemoveSuppressionResponse, error) {
+	if h.suppressions == nil {
+		return nil, status.Error(codes.Unimplemented, "suppression list is not enabled")
+	}
+	if errs := validator.New().Required("recipient", req.Recipient).Errors(); len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	removed, err := h.suppressions.Remove(req.Recipient)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to remove suppression", err)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to remove suppression").GRPCStatus().Err()
+	}
+	if !removed {
+		return nil, errors.ErrSuppressionNotFound.GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Recipient suppression removed", "audit", true)
+
+	return &pb_notification.RemoveSuppressionResponse{}, nil
+}
+
+// ListSuppressions returns a page of suppressed recipients, newest first.
+func (h *NotificationHandler) ListSuppressions(ctx context.Context, req *pb_notification.ListSuppressionsRequest) (*pb_notification.ListSuppressionsResponse, error) {
+	if h.suppressions == nil {
+		return nil, status.Error(codes.Unimplemented, "suppression list is not enabled")
+	}
+	if errs := validator.New().Min("offset", float64(req.Offset), 0).Errors(); len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxSuppressionLimit {
+		limit = defaultSuppressionLimit
+	}
+
+	suppressions, total, err := h.suppressions.List(limit, int(req.Offset))
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list suppressions", err)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to list suppressions").GRPCStatus().Err()
+	}
+
+	resp := &pb_notification.ListSuppressionsResponse{Total: total}
+	for i := range suppressions {
+		resp.Suppressions = append(resp.Suppressions, suppressionToProto(&suppressions[i]))
+	}
+	return resp, nil
+}
+
+// suppressionToProto converts a stored suppression to its gRPC message.
+func suppressionToProto(suppression *notification_models.Suppression) *pb_notification.Suppression {
+	return &pb_notification.Suppression{
+		Recipient: suppression.Recipient,
+		Channel:   suppression.Channel,
+		Reason:    suppression.Reason,
+		Details:   suppression.Details,
+		CreatedBy: suppression.CreatedBy,
+		CreatedAt: suppression.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_notification "user-risk-system/proto/notification"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testSendLimit  = 5                // Test sends per admin per testSendWindow
+	testSendWindow = 10 * time.Minute // Window of testSendLimit
+
+	testTemplate  = "test" // Template name logged for test emails, their content isn't templated
+	testEmailBody = "<p>This is a test notification sent to verify the email provider configuration. No action is needed.</p>"
+)
+
+// TestSend sends a canned test message through the configured provider of a channel, verifying its credentials end to end.
+// the provider outcome is reported in the result, sends are rate limited per admin and suppressed recipients refused.
+// it goes through the regular channel dispatch, so an unconfigured or panicking provider fails the result.
+func (h *NotificationHandler) TestSend(ctx context.Context, req *pb_notification.TestSendRequest) (*pb_notification.TestSendResponse, error) {
+	channel := strings.ToUpper(req.Channel)
+	errs := validator.New().
+		Required("channel", req.Channel).
+		Required("recipient", req.Recipient).
+		Errors()
+	switch channel {
+	case "":
+	case notification_models.ChannelEmail:
+		errs = append(errs, validator.New().Email("recipient", req.Recipient).Errors()...)
+	case notification_models.ChannelSMS:
+		if req.Recipient != "" && !notification_models.IsE164(req.Recipient) {
+			errs = append(errs, validator.ValidationError{Field: "recipient", Code: validator.CodeInvalidPhone, Message: "must be an E.164 phone number"})
+		}
+	default:
+		errs = append(errs, validator.ValidationError{Field: "channel", Code: validator.CodeInvalidValue, Message: "must be EMAIL or SMS"})
+	}
+	if len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	admin := ""
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		admin = claims.UserID
+	}
+	if ok, _ := h.throttle.allow("test_send|"+admin, testSendLimit, testSendWindow, time.Now()); !ok {
+		h.logger.WarnCtx(ctx, "Test send rate limited", "channel", channel)
+		return nil, errors.ErrRateLimitExceeded.WithMessage("Too many test sends, try again later").GRPCStatus().Err()
+	}
+
+	if err := h.suppressed(ctx, req.Recipient); err != nil {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: recipient: " + err.Error()).GRPCStatus().Err()
+	}
+
+	notification := &notification_models.Notification{
+		ID:        uuid.New().String(),
+		UserID:    admin,
+		Type:      notification_models.NotificationTypeTest,
+		Message:   h.config.TemplateCompanyName + " test message, no action is needed.",
+		Channel:   channel,
+		CreatedAt: time.Now(),
+	}
+	if channel == notification_models.ChannelEmail {
+		notification.Email = req.Recipient
+	} else {
+		notification.Phone = req.Recipient
+	}
+
+	result := &pb_notification.ChannelResult{Channel: channel, Success: true}
+	if err := h.sendNotificationByChannel(ctx, notification); err != nil {
+		result.Success = false
+		result.Error = err.Error()
+	}
+	result.Provider = notification.Provider
+	result.ProviderMessageId = notification.ProviderMessageID
+	result.StatusCode = int32(notification.ProviderStatusCode)
+	result.LatencyMs = notification.ProviderLatency.Milliseconds()
+
+	h.logger.InfoCtx(ctx, "Test notification sent",
+		"audit", true,
+		"channel", channel,
+		"provider", result.Provider,
+		"success", result.Success,
+		"provider_message_id", result.ProviderMessageId,
+		"status_code", result.StatusCode,
+		"latency_ms", result.LatencyMs,
+	)
+
+	return &pb_notification.TestSendResponse{Result: result}, nil
+}
+
+// testEmail returns the subject and body of a test email.
+func (h *NotificationHandler) testEmail() (string, string) {
+	return "Test notification from " + h.config.TemplateCompanyName, testEmailBody
+}
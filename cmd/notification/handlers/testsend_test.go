@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+// panickingSMS panics on every send.
+type panickingSMS struct{}
+
+func (panickingSMS) SendSMS(string, string) (providers.SendResult, error) { panic("provider bug") }
+
+func (panickingSMS) GetProviderName() string { return "PANICKING" }
+
+func newTestSendHandler(t *testing.T) *NotificationHandler {
+	t.Helper()
+	cfg := &config.Config{EmailProvider: "SIMULATE", SMSProvider: "SIMULATE", TemplateCompanyName: "Acme"}
+	return NewNotificationHandler(messaging.NewInMemory(), nil, nil, cfg, templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg)), logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+}
+
+func TestTestSendEmailUsesChannelDispatch(t *testing.T) {
+	h := newTestSendHandler(t)
+	email := &recordingEmail{}
+	h.emailProvider = email
+
+	resp, err := h.TestSend(context.Background(), &pb_notification.TestSendRequest{Channel: "email", Recipient: "admin@example.com"})
+	if err != nil {
+		t.Fatalf("TestSend() error = %v", err)
+	}
+	if !resp.Result.Success || resp.Result.Provider != "RECORDING" {
+		t.Errorf("TestSend() result = %+v, want success through RECORDING", resp.Result)
+	}
+	if len(email.bodies) != 1 || email.bodies[0] != testEmailBody {
+		t.Errorf("sent bodies = %q, want the test email body", email.bodies)
+	}
+}
+
+func TestTestSendReportsProviderFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  providers.SMSProvider
+		wantError string
+	}{
+		{"panicking provider", panickingSMS{}, "panicked"},
+		{"nil provider", nil, "not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			h.smsProvider = tt.provider
+
+			resp, err := h.TestSend(context.Background(), &pb_notification.TestSendRequest{Channel: notification_models.ChannelSMS, Recipient: "+15551234567"})
+			if err != nil {
+				t.Fatalf("TestSend() error = %v", err)
+			}
+			if resp.Result.Success || !strings.Contains(resp.Result.Error, tt.wantError) {
+				t.Errorf("TestSend() result = %+v, want a failure containing %q", resp.Result, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+)
+
+// throttle counts notifications per user and type in fixed windows.
+// counters of expired windows are swept lazily so idle users don't accumulate.
+type throttle struct {
+	mu        sync.Mutex
+	windows   map[string]*throttleWindow // user_id + type -> current window
+	lastSweep time.Time
+}
+
+// throttleWindow holds the sends counted in one window.
+type throttleWindow struct {
+	start time.Time
+	sent  int
+}
+
+// newThrottle creates an empty throttle.
+func newThrottle() *throttle {
+	return &throttle{windows: make(map[string]*throttleWindow)}
+}
+
+// allow records a send for key and reports whether it stays within limit sends per window.
+// the count returned is the number of sends attempted in the current window, including this one.
+func (t *throttle) allow(key string, limit int, window time.Duration, now time.Time) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastSweep) >= window {
+		for k, w := range t.windows {
+			if now.Sub(w.start) >= window {
+				delete(t.windows, k)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	w, ok := t.windows[key]
+	if !ok || now.Sub(w.start) >= window {
+		w = &throttleWindow{start: now}
+		t.windows[key] = w
+	}
+	w.sent++
+	return w.sent <= limit, w.sent
+}
+
+// throttled returns true if the notification exceeds its user's limit for the type and must be dropped.
+// notifications about a CRITICAL risk level (metadata risk_level) use their own limit, where 0 exempts them,
+// whatever their type. The suppression is logged.
+func (h *NotificationHandler) throttled(ctx context.Context, notification *notification_models.Notification) bool {
+	settings := h.config.Settings().Current()
+	limit := settings.NotificationThrottleLimit
+	if strings.EqualFold(notification.Metadata["risk_level"], "CRITICAL") {
+		limit = settings.NotificationThrottleCriticalLimit
+	}
+	if limit <= 0 || notification.UserID == "" {
+		return false
+	}
+
+	ok, attempts := h.throttle.allow(notification.UserID+"|"+notification.Type, limit, settings.NotificationThrottleWindow, time.Now())
+	if ok {
+		return false
+	}
+
+	h.logger.WarnCtx(ctx, "Notification throttled",
+		"notification_id", notification.ID,
+		"type", notification.Type,
+		"user_id", notification.UserID,
+		"check_id", notification.CheckID,
+		"attempts_in_window", attempts,
+		"limit", limit,
+		"window", settings.NotificationThrottleWindow.String(),
+	)
+	h.recordTransition(ctx, &notification_models.NotificationEvent{
+		NotificationID: notification.ID,
+		Status:         notification_models.NotificationStatusFailed,
+		Details:        "notification throttled",
+	})
+	return true
+}
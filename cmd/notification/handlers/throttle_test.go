@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/pkg/models"
+	pb_notification "user-risk-system/proto/notification"
+)
+
+func TestThrottledUsesCriticalLimitForCriticalRiskLevel(t *testing.T) {
+	t.Setenv("NOTIFICATION_THROTTLE_LIMIT", "1")
+	t.Setenv("NOTIFICATION_THROTTLE_CRITICAL_LIMIT", "0")
+
+	tests := []struct {
+		name          string
+		notification  notification_models.Notification
+		wantThrottled []bool
+	}{
+		{
+			name:          "critical level of a regular type is exempt",
+			notification:  notification_models.Notification{UserID: "user-1", Type: notification_models.NotificationTypeRiskDetected, Metadata: map[string]string{"risk_level": "CRITICAL"}},
+			wantThrottled: []bool{false, false, false},
+		},
+		{
+			name:          "critical alert type of a high level is limited",
+			notification:  notification_models.Notification{UserID: "user-1", Type: notification_models.NotificationTypeCriticalRisk, Metadata: map[string]string{"risk_level": "HIGH"}},
+			wantThrottled: []bool{false, true},
+		},
+		{
+			name:          "no risk level is limited",
+			notification:  notification_models.Notification{UserID: "user-1", Type: notification_models.NotificationTypeLoginAlert},
+			wantThrottled: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			for i, want := range tt.wantThrottled {
+				notification := tt.notification
+				if got := h.throttled(context.Background(), &notification); got != want {
+					t.Errorf("send %d: throttled() = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCriticalRiskAlertsUseCriticalLimit(t *testing.T) {
+	t.Setenv("NOTIFICATION_THROTTLE_LIMIT", "1")
+	t.Setenv("NOTIFICATION_THROTTLE_CRITICAL_LIMIT", "3")
+
+	for _, tt := range []struct {
+		level         string
+		wantThrottled []bool
+	}{
+		{"CRITICAL", []bool{false, false, false, true}},
+		{"HIGH", []bool{false, true}},
+	} {
+		t.Run(tt.level, func(t *testing.T) {
+			h := newTestSendHandler(t)
+			h.emailProvider = &recordingEmail{}
+			for i, want := range tt.wantThrottled {
+				resp, err := h.SendNotification(context.Background(), &pb_notification.SendNotificationRequest{
+					UserId:   "user-1",
+					Type:     notification_models.NotificationTypeRiskDetected,
+					Message:  "Risk detected",
+					Email:    "user@example.com",
+					Channels: []string{notification_models.ChannelEmail},
+					Metadata: map[string]string{"risk_level": tt.level},
+				})
+				if err != nil {
+					t.Fatalf("SendNotification() error = %v", err)
+				}
+				if resp.Throttled != want {
+					t.Errorf("send %d: throttled = %v, want %v", i+1, resp.Throttled, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRiskDetectedEventCarriesRiskLevelToThrottle(t *testing.T) {
+	t.Setenv("NOTIFICATION_THROTTLE_LIMIT", "1")
+	t.Setenv("NOTIFICATION_THROTTLE_CRITICAL_LIMIT", "0")
+	h := newTestSendHandler(t)
+	email := &recordingEmail{}
+	h.emailProvider = email
+
+	data, err := json.Marshal(models.RiskDetectedEvent{EventMeta: models.NewEventMeta(), UserID: "user-1", Email: "user@example.com", RiskLevel: "CRITICAL", Reason: "Blocked domain"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := h.handleRiskDetectedEvent(data); err != nil {
+			t.Fatalf("handleRiskDetectedEvent() error = %v", err)
+		}
+	}
+	if len(email.bodies) != 3 {
+		t.Errorf("sent %d alerts, want every CRITICAL alert exempt from the regular limit", len(email.bodies))
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/repository"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_notification "user-risk-system/proto/notification"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EnableTimeline records the status transitions of every notification into events and serves
+// them through GetNotificationStatus.
+func (h *NotificationHandler) EnableTimeline(events *repository.EventRepository) {
+	h.events = events
+}
+
+// EnableStatusTracking stores every notification with its current status into notifications,
+// updated by sends and provider delivery events. Delivery events of notifications not stored are ignored.
+func (h *NotificationHandler) EnableStatusTracking(notifications *repository.NotificationRepository) {
+	h.notifications = notifications
+}
+
+// recordTransition appends a status transition of a notification to its timeline and stores it as its current status.
+// a failed write is logged and doesn't affect delivery, the status is for support and providers only.
+func (h *NotificationHandler) recordTransition(ctx context.Context, event *notification_models.NotificationEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if h.events != nil {
+		if err := h.events.Append(event); err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to record notification transition", err,
+				"notification_id", event.NotificationID,
+				"status", event.Status,
+			)
+		}
+	}
+	if h.notifications != nil && event.Status != notification_models.NotificationStatusCreated {
+		if _, err := h.notifications.UpdateStatus(event); err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to store notification status", err,
+				"notification_id", event.NotificationID,
+				"status", event.Status,
+			)
+		}
+	}
+}
+
+// recordCreated stores a new notification and appends its CREATED transition, at its creation time.
+func (h *NotificationHandler) recordCreated(ctx context.Context, notification *notification_models.Notification) {
+	if h.notifications != nil {
+		err := h.notifications.Create(&notification_models.NotificationRecord{
+			ID:        notification.ID,
+			UserID:    notification.UserID,
+			Type:      notification.Type,
+			Channel:   notification.Channel,
+			Status:    notification_models.NotificationStatusCreated,
+			CheckID:   notification.CheckID,
+			CreatedAt: notification.CreatedAt,
+			UpdatedAt: notification.CreatedAt,
+		})
+		if err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to store notification", err, "notification_id", notification.ID)
+		}
+	}
+	h.recordTransition(ctx, &notification_models.NotificationEvent{
+		NotificationID: notification.ID,
+		Status:         notification_models.NotificationStatusCreated,
+		OccurredAt:     notification.CreatedAt,
+	})
+}
+
+// recordSendAttempt appends the SENT or FAILED transition of a send on the notification's current channel.
+func (h *NotificationHandler) recordSendAttempt(ctx context.Context, notification *notification_models.Notification, err error) {
+	event := &notification_models.NotificationEvent{
+		NotificationID:    notification.ID,
+		Status:            notification_models.NotificationStatusSent,
+		Channel:           notification.Channel,
+		Provider:          notification.Provider,
+		ProviderMessageID: notification.ProviderMessageID,
+	}
+	if err != nil {
+		event.Status = notification_models.NotificationStatusFailed
+		event.Details = err.Error()
+	}
+	h.recordTransition(ctx, event)
+}
+
+// GetNotificationStatus returns the current status of a notification with its full delivery timeline.
+// the status is the stored one when status tracking is enabled, the latest transition otherwise.
+func (h *NotificationHandler) GetNotificationStatus(ctx context.Context, req *pb_notification.GetNotificationStatusRequest) (*pb_notification.GetNotificationStatusResponse, error) {
+	if h.events == nil {
+		return nil, status.Error(codes.Unimplemented, "notification timeline is not enabled")
+	}
+	if errs := validator.New().Required("notification_id", req.NotificationId).Errors(); len(errs) > 0 {
+		return nil, errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+	}
+
+	events, err := h.events.ListByNotification(req.NotificationId)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to load notification timeline", err, "notification_id", req.NotificationId)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to load notification status").GRPCStatus().Err()
+	}
+	if len(events) == 0 {
+		return nil, errors.ErrNotificationNotFound.GRPCStatus().Err()
+	}
+
+	resp := &pb_notification.GetNotificationStatusResponse{
+		NotificationId: req.NotificationId,
+		Status:         events[len(events)-1].Status,
+	}
+	if h.notifications != nil {
+		record, err := h.notifications.Get(req.NotificationId)
+		if err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to load notification", err, "notification_id", req.NotificationId)
+			return nil, errors.ErrInternalServerError.WithMessage("Failed to load notification status").GRPCStatus().Err()
+		}
+		if record != nil {
+			resp.Status = record.Status
+		}
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &pb_notification.NotificationEvent{
+			Status:            event.Status,
+			Channel:           event.Channel,
+			Provider:          event.Provider,
+			ProviderMessageId: event.ProviderMessageID,
+			Details:           event.Details,
+			OccurredAt:        timestamppb.New(event.OccurredAt),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/repository"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/utils"
+	pb_notification "user-risk-system/proto/notification"
+	pb_user "user-risk-system/proto/user"
+)
+
+// newTimelineHandler returns a test handler recording timelines and statuses in SQLite, sending email through email.
+func newTimelineHandler(t *testing.T, email *recordingEmail) *NotificationHandler {
+	t.Helper()
+	db, err := utils.SetupDatabase(":memory:", &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)}, &config.Config{},
+		logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	if err != nil {
+		t.Fatalf("SetupDatabase() error = %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := notification_models.AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+
+	h := newTestSendHandler(t)
+	h.emailProvider = email
+	h.EnableTimeline(repository.NewEventRepository(db))
+	h.EnableStatusTracking(repository.NewNotificationRepository(db))
+	return h
+}
+
+func TestTimelineRecordsTransitionsInOrder(t *testing.T) {
+	t.Setenv("BROADCAST_RATE_PER_SECOND", "100")
+	email := &recordingEmail{}
+	h := newTimelineHandler(t, email)
+	h.userClient = listingUsers{users: []*pb_user.User{{Id: "user-1", Email: "user@example.com", IsActive: true}}}
+	ctx := context.Background()
+
+	if _, err := h.BroadcastNotification(ctx, &pb_notification.BroadcastNotificationRequest{Type: "ANNOUNCEMENT", Message: "Maintenance tonight", Channel: notification_models.ChannelEmail}); err != nil {
+		t.Fatalf("BroadcastNotification() error = %v", err)
+	}
+	h.WaitForBroadcasts()
+	published := h.messageQueue.(*messaging.InMemory).Published("notifications")
+	if len(published) != 1 {
+		t.Fatalf("enqueued %d notifications, want 1", len(published))
+	}
+	var msg notification_models.NotificationMessage
+	if err := json.Unmarshal(published[0], &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := h.handleNotificationEvent(published[0]); err != nil {
+		t.Fatalf("handleNotificationEvent() error = %v", err)
+	}
+	if len(email.bodies) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(email.bodies))
+	}
+
+	sentAt := time.Now().Unix()
+	_, err := h.RecordDeliveryEvents(ctx, &pb_notification.RecordDeliveryEventsRequest{
+		Provider: notification_models.ProviderSendGrid,
+		Events: []*pb_notification.DeliveryEvent{
+			{NotificationId: msg.ID, Event: "delivered", Email: "user@example.com", Timestamp: sentAt + 1},
+			{NotificationId: msg.ID, Event: "bounce", Email: "user@example.com", Reason: "mailbox full", Timestamp: sentAt + 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RecordDeliveryEvents() error = %v", err)
+	}
+
+	status, err := h.GetNotificationStatus(ctx, &pb_notification.GetNotificationStatusRequest{NotificationId: msg.ID})
+	if err != nil {
+		t.Fatalf("GetNotificationStatus() error = %v", err)
+	}
+	var statuses []string
+	for _, event := range status.Events {
+		statuses = append(statuses, event.Status)
+	}
+	want := []string{
+		notification_models.NotificationStatusCreated,
+		notification_models.NotificationStatusQueued,
+		notification_models.NotificationStatusSent,
+		notification_models.NotificationStatusDelivered,
+		notification_models.NotificationStatusBounced,
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("timeline = %v, want %v", statuses, want)
+	}
+	if status.Status != notification_models.NotificationStatusBounced {
+		t.Errorf("status = %s, want the latest transition", status.Status)
+	}
+	if sent := status.Events[2]; sent.Channel != notification_models.ChannelEmail || sent.Provider != "RECORDING" {
+		t.Errorf("SENT transition = %+v, want the email channel and provider", sent)
+	}
+	if bounced := status.Events[4]; bounced.Details != "mailbox full" || bounced.OccurredAt.AsTime().Unix() != sentAt+2 {
+		t.Errorf("BOUNCED transition = %+v, want the provider's reason and timestamp", bounced)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"os"
@@ -8,80 +9,214 @@ import (
 	"syscall"
 
 	"google.golang.org/grpc"
+	"gorm.io/gorm"
 
 	"user-risk-system/cmd/notification/handlers"
+	"user-risk-system/cmd/notification/models"
+	"user-risk-system/cmd/notification/repository"
 	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/client"
 	"user-risk-system/pkg/config"
 	"user-risk-system/pkg/health"
+	"user-risk-system/pkg/lifecycle"
 	"user-risk-system/pkg/logger"
 	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/migrate"
+	"user-risk-system/pkg/utils"
 	pb_notification "user-risk-system/proto/notification"
 )
 
 // main initializes and starts the notification service with both gRPC and message queue consumers.
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Require("RABBITMQ_URL", "JWT_SECRET"))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	logConfig := logger.LogConfig{
-		Level:       "info",
+		Level:       cfg.LogLevel,
 		Format:      "json",
 		ServiceName: cfg.ServiceName,
 		Environment: cfg.Environment,
+		MaskPII:     cfg.LogMaskPII,
 	}
 	nl := logger.New(logConfig)
+	cfg.LogStartupReport(nl)
+	cfg.Settings().WatchSignals(context.Background(), nl)
+
+	// Resources registered with the lifecycle are closed in reverse order when main returns
+	lc := lifecycle.New(nl)
+	defer lc.Shutdown()
 
 	nl.Info("Starting Notification Service...")
-	nl.Info("Email Provider: %s", cfg.EmailProvider)
-	nl.Info("SMS Provider: %s", cfg.SMSProvider)
-	nl.Info("Push Provider: %s", cfg.PushProvider)
+	nl.Info("Notification providers",
+		"email_provider", cfg.EmailProvider,
+		"sms_provider", cfg.SMSProvider,
+		"push_provider", cfg.PushProvider,
+	)
+
+	// Database holding the suppression list and notification timelines
+	var db *gorm.DB
+	if err := lc.Connect("database", func() (err error) {
+		db, err = utils.SetupDatabase(cfg.NotificationDBURL, &gorm.Config{}, cfg, nl)
+		return err
+	}); err != nil {
+		nl.Fatalf("Failed to setup database: %v", err)
+	}
 
-	rabbitMQ, err := messaging.NewRabbitMQ(cfg.RabbitMQURL)
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		nl.Fatalf("Failed to get underlying SQL DB: %v", err)
+	}
+	lc.OnShutdown("database", sqlDB.Close)
+
+	// Schema migrations, `notification migrate [up|down [steps]|status]` runs them and exits
+	migrator := migrate.New(db, "notification-service", models.Migrations, nl)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrator.RunCommand(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			nl.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+	if cfg.DatabaseAutoMigrate {
+		nl.Warn("Running gorm auto-migration instead of versioned migrations, intended for development only")
+		if err := models.AutoMigrate(db); err != nil {
+			nl.Fatalf("Failed to run auto-migration: %v", err)
+		}
+	} else {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			nl.Fatalf("Failed to run migrations: %v", err)
+		}
+		nl.Info("Notification database migrations completed successfully", "applied", applied)
 	}
-	defer rabbitMQ.Close()
 
-	queues := []string{"user.created", "risk.detected", "notifications"}
+	// gRPC clients used to resolve broadcast recipients, forwarding the admin's JWT
+	var userClient *client.UserClient
+	if err := lc.Connect("user-service", func() (err error) {
+		userClient, err = client.NewUserClient(cfg.UserServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		nl.Fatalf("Failed to connect to user service at %s: %v", cfg.UserServiceURL, err)
+	}
+	lc.OnShutdown("user-service client", userClient.Close)
+
+	var riskClient *client.RiskClient
+	if err := lc.Connect("risk-engine", func() (err error) {
+		riskClient, err = client.NewRiskClient(cfg.RiskServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		nl.Fatalf("Failed to connect to risk service at %s: %v", cfg.RiskServiceURL, err)
+	}
+	lc.OnShutdown("risk-engine client", riskClient.Close)
+
+	// Optionally wait for dependencies before consuming and serving.
+	// The user service is not awaited, it waits on this service itself.
+	dependencies := []health.Dependency{health.GRPCDependency("risk-engine", riskClient.Conn())}
+	if cfg.MessagingRequired {
+		dependencies = append(dependencies, health.RabbitMQDependency(cfg.RabbitMQURL))
+	}
+	if err := health.WaitForDependencies(context.Background(), cfg.StartupWaitTimeout, cfg.StartupWaitInterval, nl, dependencies...); err != nil {
+		nl.Fatalf("Dependencies not ready: %v", err)
+	}
+
+	// RabbitMQ connection, retried in the background unless MESSAGING_REQUIRED is set
+	rabbitMQ := messaging.NewResilient(cfg.RabbitMQURL, messaging.OfflineMode(cfg.MessagingOfflineMode), cfg.MessagingReconnectInterval, nl)
+	if err := lc.Connect("rabbitmq", rabbitMQ.Connect); err != nil {
+		if cfg.MessagingRequired {
+			nl.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		}
+		nl.Warn("Starting without RabbitMQ, connecting in the background", "offline_mode", cfg.MessagingOfflineMode)
+	}
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	go rabbitMQ.Run(brokerCtx)
+	lc.OnShutdown("rabbitmq", func() error {
+		stopBroker()
+		return rabbitMQ.Close()
+	})
+
+	queues := []string{"user.created", "risk.detected", "risk.level_changed", "notifications"}
 	for _, queue := range queues {
 		if err := rabbitMQ.DeclareQueue(queue); err != nil {
 			nl.Fatalf("Failed to declare queue %s: %v", queue, err)
 		}
 	}
 
-	templ := templates.NewEmailTemplateManager(cfg.TemplatesDirectoryPath)
+	templ := templates.NewEmailTemplateManager(cfg.TemplatesDirectoryPath, templates.BaseDataFromConfig(cfg))
 
 	// Create notification handler
-	notificationHandler := handlers.NewNotificationHandler(rabbitMQ, cfg, templ, nl)
+	notificationHandler := handlers.NewNotificationHandler(
+		rabbitMQ,
+		userClient,
+		riskClient.Admin,
+		cfg,
+		templ,
+		nl,
+	)
+	notificationHandler.EnableSuppressions(repository.NewSuppressionRepository(db))
+	notificationHandler.EnableTimeline(repository.NewEventRepository(db))
+	notificationHandler.EnableStatusTracking(repository.NewNotificationRepository(db))
 
 	// Start message consumers for asynchronous processing
-	notificationHandler.StartMessageConsumer()
+	consumerCtx, stopConsumers := context.WithCancel(context.Background())
+	notificationHandler.StartMessageConsumer(consumerCtx)
+
+	// Stop consuming and let in-flight messages finish before the connection is closed
+	lc.OnShutdown("message consumers", func() error {
+		stopConsumers()
+		notificationHandler.WaitForConsumers()
+		return nil
+	})
+
+	// Finish enqueuing accepted broadcasts once the gRPC server stopped, before consumers and the connection go away
+	lc.OnShutdown("broadcasts", func() error {
+		notificationHandler.WaitForBroadcasts()
+		return nil
+	})
 
 	// Create gRPC server for synchronous processing
-	lis, err := net.Listen("tcp", ":"+cfg.Port)
+	lis, err := net.Listen("tcp", ":"+cfg.Ports.NotificationGRPC)
 	if err != nil {
 		nl.Fatalf("Failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	// Only admin methods and provider delivery events are authenticated, internal SendNotification calls stay open.
+	// delivery events suppress recipients, only the gateway may forward them after verifying the provider signature
+	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer)
+	authMiddleware := auth.NewAuthMiddleware(jwtManager)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor(nl), authMiddleware.GRPCProtectMethods(map[string][]auth.UserRole{
+			"/notification.NotificationService/BroadcastNotification": {auth.RoleAdmin},
+			"/notification.NotificationService/PreviewTemplate":       {auth.RoleAdmin},
+			"/notification.NotificationService/ListTemplates":         {auth.RoleAdmin},
+			"/notification.NotificationService/AddSuppression":        {auth.RoleAdmin},
+			"/notification.NotificationService/RemoveSuppression":     {auth.RoleAdmin},
+			"/notification.NotificationService/ListSuppressions":      {auth.RoleAdmin},
+			"/notification.NotificationService/TestSend":              {auth.RoleAdmin},
+			"/notification.NotificationService/GetNotificationStatus": {auth.RoleAdmin},
+			"/notification.NotificationService/RecordDeliveryEvents":  {auth.RoleService},
+		})),
+	)
 	pb_notification.RegisterNotificationServiceServer(s, notificationHandler)
 
 	// Health service
 	health.RegisterHealthServiceWithDefaults(s, "notification.NotificationService")
 
 	go func() {
-		nl.Info("Notification service starting on port %s...", cfg.Port)
 		if err := s.Serve(lis); err != nil {
 			nl.Fatalf("Failed to serve: %v", err)
 		}
 	}()
+	lc.OnShutdown("grpc server", func() error {
+		s.GracefulStop()
+		return nil
+	})
+	lc.Started("port", cfg.Ports.NotificationGRPC)
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 
 	nl.Warn("Shutting down notification service...")
-	s.GracefulStop()
 }
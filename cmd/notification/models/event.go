@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// NotificationEvent is one status transition of a notification, appended and never updated.
+// the events of a notification in the order they were recorded form its delivery timeline.
+type NotificationEvent struct {
+	ID                uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	NotificationID    string    `json:"notification_id" gorm:"type:varchar(64);not null;index"`
+	Status            string    `json:"status" gorm:"type:varchar(20);not null"` // CREATED, QUEUED, SENT, FAILED, DELIVERED, ...
+	Channel           string    `json:"channel,omitempty" gorm:"type:varchar(20)"`
+	Provider          string    `json:"provider,omitempty" gorm:"type:varchar(50)"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty" gorm:"type:varchar(255)"`
+	Details           string    `json:"details,omitempty" gorm:"type:text"` // Send error or provider bounce reason
+	OccurredAt        time.Time `json:"occurred_at" gorm:"not null"`
+}
+
+func (NotificationEvent) TableName() string {
+	return "notification_events"
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/pkg/migrate"
+)
+
+// AutoMigrate creates or updates the notification database schema with gorm.
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Suppression{}, &NotificationEvent{}, &NotificationRecord{})
+}
+
+// Migrations are the versioned schema changes of the notification database, applied in order.
+// each one works on frozen snapshots of the tables it touches, so it keeps creating the same
+// schema when the models change.
+var Migrations = []migrate.Migration{
+	{
+		ID: "0001_initial",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&suppressionV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&suppressionV1{})
+		},
+	},
+	{
+		ID: "0002_notification_events",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&notificationEventV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notificationEventV2{})
+		},
+	},
+	{
+		ID: "0003_notifications",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&notificationRecordV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notificationRecordV3{})
+		},
+	},
+	{
+		ID: "0004_notification_check_id",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&notificationRecordV4{}, "CheckID") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&notificationRecordV4{}, "CheckID")
+		},
+		Down: func(tx *gorm.DB) error {
+			// gorm's SQLite migrator rebuilds the table to drop a column and loses its indexes
+			return tx.Exec("ALTER TABLE notifications DROP COLUMN check_id").Error
+		},
+	},
+}
+
+// suppressionV1 is the notification_suppressions table as created by 0001_initial.
+type suppressionV1 struct {
+	Recipient string `gorm:"primaryKey;type:varchar(320)"`
+	Channel   string `gorm:"type:varchar(20);not null"`
+	Reason    string `gorm:"type:varchar(20);not null"`
+	Details   string `gorm:"type:text"`
+	CreatedBy string `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+func (suppressionV1) TableName() string {
+	return "notification_suppressions"
+}
+
+// notificationEventV2 is the notification_events table as created by 0002_notification_events.
+type notificationEventV2 struct {
+	ID                uint      `gorm:"primaryKey;autoIncrement"`
+	NotificationID    string    `gorm:"type:varchar(64);not null;index"`
+	Status            string    `gorm:"type:varchar(20);not null"`
+	Channel           string    `gorm:"type:varchar(20)"`
+	Provider          string    `gorm:"type:varchar(50)"`
+	ProviderMessageID string    `gorm:"type:varchar(255)"`
+	Details           string    `gorm:"type:text"`
+	OccurredAt        time.Time `gorm:"not null"`
+}
+
+func (notificationEventV2) TableName() string {
+	return "notification_events"
+}
+
+// notificationRecordV3 is the notifications table as created by 0003_notifications.
+type notificationRecordV3 struct {
+	ID                string    `gorm:"primaryKey;type:varchar(64)"`
+	UserID            string    `gorm:"type:varchar(64);index:idx_notifications_user_id"`
+	Type              string    `gorm:"type:varchar(50)"`
+	Channel           string    `gorm:"type:varchar(20)"`
+	Status            string    `gorm:"type:varchar(20);not null"`
+	Provider          string    `gorm:"type:varchar(50)"`
+	ProviderMessageID string    `gorm:"type:varchar(255)"`
+	Details           string    `gorm:"type:text"`
+	CreatedAt         time.Time `gorm:"not null"`
+	UpdatedAt         time.Time `gorm:"not null"`
+}
+
+func (notificationRecordV3) TableName() string {
+	return "notifications"
+}
+
+// notificationRecordV4 is the column 0004_notification_check_id adds to notifications.
+type notificationRecordV4 struct {
+	CheckID string `gorm:"type:varchar(64)"`
+}
+
+func (notificationRecordV4) TableName() string {
+	return "notifications"
+}
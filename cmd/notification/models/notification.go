@@ -1,6 +1,19 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"time"
+
+	"user-risk-system/pkg/validator"
+)
+
+// e164Pattern matches an E.164 phone number, a + followed by up to 15 digits.
+var e164Pattern = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+
+// IsE164 returns true if phone is an E.164 number SMS providers can deliver to.
+func IsE164(phone string) bool {
+	return e164Pattern.MatchString(phone)
+}
 
 // Notification represents a notification message that can be sent through various channels.
 // tracks the message content, delivery status, and metadata about sending attempts.
@@ -10,13 +23,24 @@ type Notification struct {
 	Type      string     `json:"type"`
 	Message   string     `json:"message"`
 	Email     string     `json:"email"`
+	FirstName string     `json:"first_name,omitempty"` // Recipient first name, greets them in emails
+	OrgID     string     `json:"org_id,omitempty"`     // Organization of the notification, selects its webhook secret
 	Phone     string     `json:"phone,omitempty"`
-	Channel   string     `json:"channel"`            // EMAIL, SMS, PUSH, ALL
-	Status    string     `json:"status"`             // PENDING, SENT, FAILED
+	PushToken string     `json:"push_token,omitempty"`
+	Locale    string     `json:"locale,omitempty"`   // Recipient language, defaults to en
+	Channel   string     `json:"channel"`            // EMAIL, SMS, PUSH, WEBHOOK, SLACK, ALL
+	Status    string     `json:"status"`             // PENDING, SENT, FAILED, DELIVERED, BOUNCED, OPENED, CLICKED, COMPLAINED
 	Provider  string     `json:"provider,omitempty"` // SIMULATE, SENDGRID, TWILIO, etc.
 	SentAt    *time.Time `json:"sent_at,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	Error     string     `json:"error,omitempty"`
+	CheckID   string     `json:"check_id,omitempty"` // Risk check that triggered the notification, links an alert to its evaluation
+
+	Metadata map[string]string `json:"metadata,omitempty"` // Structured context, e.g. risk_level, reason, flags
+
+	ProviderMessageID  string        `json:"provider_message_id,omitempty"`  // ID the provider assigned on the last send
+	ProviderStatusCode int           `json:"provider_status_code,omitempty"` // Provider API status code of the last send
+	ProviderLatency    time.Duration `json:"provider_latency,omitempty"`     // Duration of the last provider call
 }
 
 // Notification type constants define the different types of notifications supported by the system.
@@ -25,20 +49,114 @@ const (
 	NotificationTypeRiskDetected  = "RISK_DETECTED"
 	NotificationTypePasswordReset = "PASSWORD_RESET"
 	NotificationTypeLoginAlert    = "LOGIN_ALERT"
+	NotificationTypeCriticalRisk  = "CRITICAL_RISK_ALERT"
+	NotificationTypeEmailChange   = "EMAIL_CHANGE_VERIFICATION"
+	NotificationTypeTest          = "TEST_NOTIFICATION" // Canned admin test send, see TestSend
 
 	NotificationStatusPending = "PENDING"
 	NotificationStatusSent    = "SENT"
 	NotificationStatusFailed  = "FAILED"
 
+	// Timeline statuses recorded before a send
+	NotificationStatusCreated = "CREATED"
+	NotificationStatusQueued  = "QUEUED" // Published to the notifications queue, e.g. by a broadcast
+
+	// Delivery statuses reported by provider webhooks
+	NotificationStatusDelivered  = "DELIVERED"
+	NotificationStatusBounced    = "BOUNCED"
+	NotificationStatusOpened     = "OPENED"
+	NotificationStatusClicked    = "CLICKED"
+	NotificationStatusComplained = "COMPLAINED" // Recipient reported the email as spam
+
 	// Notification channels
-	ChannelEmail = "EMAIL"
-	ChannelSMS   = "SMS"
-	ChannelPush  = "PUSH"
-	ChannelAll   = "ALL"
+	ChannelEmail   = "EMAIL"
+	ChannelSMS     = "SMS"
+	ChannelPush    = "PUSH"
+	ChannelWebhook = "WEBHOOK"
+	ChannelSlack   = "SLACK"
+	ChannelAll     = "ALL"
 
 	// Providers
 	ProviderSimulate = "SIMULATE"
 	ProviderSendGrid = "SENDGRID"
 	ProviderTwilio   = "TWILIO"
 	ProviderFirebase = "FIREBASE"
+	ProviderWebhook  = "WEBHOOK"
+	ProviderSlack    = "SLACK"
 )
+
+// NotificationMessage is the inbound message contract for the notifications queue.
+// Channel is optional and defaults from Type; each channel requires its own recipient.
+type NotificationMessage struct {
+	ID      string `json:"id"`
+	UserID  string `json:"user_id"`           // Recipient for PUSH
+	Type    string `json:"type"`              // Required notification type
+	Message string `json:"message"`           // Required message body
+	Channel string `json:"channel,omitempty"` // EMAIL, SMS, PUSH, WEBHOOK, SLACK, ALL or empty for the type default
+	Email   string `json:"email,omitempty"`   // Recipient for EMAIL
+	Phone   string `json:"phone,omitempty"`   // Recipient for SMS
+
+	PushToken string `json:"push_token,omitempty"` // Device token for PUSH, falls back to user_id
+	Locale    string `json:"locale,omitempty"`     // Recipient language, defaults to en
+	OrgID     string `json:"org_id,omitempty"`     // Organization of the notification, the default one when empty
+}
+
+// Validate checks the message against the contract for the resolved delivery channels.
+// returns one validation error per missing or malformed field.
+func (m *NotificationMessage) Validate(channels []string) validator.ValidationErrors {
+	errs := validator.New().
+		Required("type", m.Type).
+		Required("message", m.Message).
+		Errors()
+
+	recipients := Notification{
+		UserID:    m.UserID,
+		Email:     m.Email,
+		Phone:     m.Phone,
+		PushToken: m.PushToken,
+	}
+	return append(errs, recipients.ValidateRecipients(channels)...)
+}
+
+// ValidateRecipients checks that a valid recipient exists for each delivery channel.
+// unknown channels are reported once under the channel field.
+func (n *Notification) ValidateRecipients(channels []string) validator.ValidationErrors {
+	v := validator.New()
+
+	validChannels := true
+	smsPhone := false
+	for _, channel := range channels {
+		switch channel {
+		case ChannelEmail:
+			v.Required("email", n.Email).Email("email", n.Email)
+		case ChannelSMS:
+			v.Required("phone", n.Phone)
+			smsPhone = true
+		case ChannelPush:
+			if n.PushToken == "" {
+				v.Required("user_id", n.UserID)
+			}
+		case ChannelWebhook, ChannelSlack:
+			// Delivered to the configured endpoint, no per-message recipient
+		default:
+			validChannels = false
+		}
+	}
+
+	errs := v.Errors()
+	if smsPhone && n.Phone != "" && !IsE164(n.Phone) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "phone",
+			Code:    validator.CodeInvalidPhone,
+			Message: "must be an E.164 phone number, e.g. +15551234567",
+		})
+	}
+	if !validChannels {
+		errs = append(errs, validator.ValidationError{
+			Field:   "channel",
+			Code:    validator.CodeInvalidValue,
+			Message: "must be one of EMAIL, SMS, PUSH, WEBHOOK, SLACK, ALL",
+		})
+	}
+	return errs
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NotificationRecord is the stored state of a notification, its status kept current by sends and
+// provider delivery events. Message content and recipients aren't stored, the timeline has the history.
+type NotificationRecord struct {
+	ID                string    `json:"id" gorm:"primaryKey;type:varchar(64)"`
+	UserID            string    `json:"user_id,omitempty" gorm:"type:varchar(64);index"`
+	Type              string    `json:"type" gorm:"type:varchar(50)"`
+	Channel           string    `json:"channel,omitempty" gorm:"type:varchar(20)"`
+	Status            string    `json:"status" gorm:"type:varchar(20);not null"` // Latest status, see NotificationEvent
+	Provider          string    `json:"provider,omitempty" gorm:"type:varchar(50)"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty" gorm:"type:varchar(255)"`
+	Details           string    `json:"details,omitempty" gorm:"type:text"`         // Send error or provider bounce reason of the latest status
+	CheckID           string    `json:"check_id,omitempty" gorm:"type:varchar(64)"` // Risk check that triggered the notification, empty for other notifications
+	CreatedAt         time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"not null"`
+}
+
+func (NotificationRecord) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Suppression reasons
+const (
+	SuppressionReasonBounce    = "BOUNCE"
+	SuppressionReasonComplaint = "COMPLAINT"
+	SuppressionReasonManual    = "MANUAL"
+)
+
+// Suppression is a recipient email address or phone number no notification is sent to.
+// recorded from provider bounce and spam complaint events, or added by an admin.
+type Suppression struct {
+	Recipient string    `json:"recipient" gorm:"primaryKey;type:varchar(320)"`
+	Channel   string    `json:"channel" gorm:"type:varchar(20);not null"` // EMAIL or SMS
+	Reason    string    `json:"reason" gorm:"type:varchar(20);not null"`  // BOUNCE, COMPLAINT, MANUAL
+	Details   string    `json:"details,omitempty" gorm:"type:text"`       // Provider bounce reason or admin note
+	CreatedBy string    `json:"created_by,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (Suppression) TableName() string {
+	return "notification_suppressions"
+}
+
+// NormalizeRecipient returns the form recipients are stored and looked up in.
+// email addresses are case insensitive in practice, phone numbers are kept as given.
+func NormalizeRecipient(recipient string) string {
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
@@ -1,22 +1,71 @@
 package providers
 
+import "time"
+
 // EmailProvider defines the interface for sending email notifications.
 // Implementations can use different email services like SendGrid, AWS SES, etc.
 type EmailProvider interface {
-	SendEmail(to, subject, body string, templateData map[string]interface{}) error
+	SendEmail(recipients EmailRecipients, subject, body string, templateData map[string]interface{}, attachments ...Attachment) (SendResult, error)
 	GetProviderName() string
 }
 
+// SendResult describes a provider call for metrics and delivery records.
+// providers fill in what they know, also when the call failed.
+type SendResult struct {
+	ProviderMessageID string        // ID the provider assigned to the message, e.g. a Twilio SID
+	StatusCode        int           // Status code of the provider API response, 0 when there was none
+	Latency           time.Duration // Duration of the provider call
+}
+
+// EmailRecipients holds the addresses of an email, only To is required.
+type EmailRecipients struct {
+	To      string
+	CC      []string // Copied recipients, visible to everyone
+	BCC     []string // Blind copies, hidden from the other recipients
+	ReplyTo string   // Address replies go to instead of the sender
+}
+
+// Attachment represents a file attached to an email.
+type Attachment struct {
+	Filename    string // File name shown to the recipient, e.g. "risk-report.pdf"
+	ContentType string // MIME type, e.g. "application/pdf"
+	Content     []byte // Raw file contents
+}
+
 // SMSProvider defines the interface for sending SMS notifications.
 // Implementations can use different SMS services like Twilio, AWS SNS, etc.
 type SMSProvider interface {
-	SendSMS(to, message string) error
+	SendSMS(to, message string) (SendResult, error)
 	GetProviderName() string
 }
 
 // PushProvider defines the interface for sending push notifications.
 // Implementations can use different push services like Firebase, APNs, etc.
 type PushProvider interface {
-	SendPush(userID, title, message string, data map[string]interface{}) error
+	SendPush(userID, title, message string, data map[string]interface{}) (SendResult, error)
 	GetProviderName() string
 }
+
+// WebhookProvider defines the interface for delivering notifications to HTTP endpoints.
+// Implementations sign payloads with the secret of their organization so receivers can verify they originate from this service.
+type WebhookProvider interface {
+	SendWebhook(orgID, eventType string, payload map[string]interface{}) error
+	GetProviderName() string
+}
+
+// AlertProvider defines the interface for posting operational alerts to team chat tools.
+// Implementations can use different services like Slack, Microsoft Teams, etc.
+type AlertProvider interface {
+	SendAlert(alert Alert) error
+	GetProviderName() string
+}
+
+// Alert holds the structured details of an admin alert for chat formatting.
+type Alert struct {
+	Title     string   // Short headline of the alert
+	RiskLevel string   // Risk severity level (LOW, MEDIUM, HIGH, CRITICAL)
+	UserID    string   // Identifier of the affected user
+	UserEmail string   // Email of the affected user, masked by providers
+	Reason    string   // Primary reason for the alert
+	Flags     []string // Risk flags that were triggered
+}
@@ -1,8 +1,11 @@
 package providers
 
 import (
+	"encoding/base64"
 	"fmt"
-	"log"
+	"strings"
+	"time"
+	"user-risk-system/pkg/logger"
 
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
@@ -14,41 +17,95 @@ type SendGridProvider struct {
 	apiKey    string
 	fromEmail string
 	fromName  string
+	logger    *logger.Logger
 }
 
 // NewSendGridProvider creates a new SendGrid email provider with the given credentials.
 // requires an API key, sender email address, and sender name.
-func NewSendGridProvider(apiKey, fromEmail, fromName string) *SendGridProvider {
+func NewSendGridProvider(apiKey, fromEmail, fromName string, logger *logger.Logger) *SendGridProvider {
 	return &SendGridProvider{
 		apiKey:    apiKey,
 		fromEmail: fromEmail,
 		fromName:  fromName,
+		logger:    logger,
 	}
 }
 
 // SendEmail sends an email using the SendGrid API.
 // validates the API key and handles error responses from the SendGrid service.
-func (p *SendGridProvider) SendEmail(to, subject, body string, templateData map[string]interface{}) error {
+// the result carries the X-Message-Id SendGrid assigned, which its event webhooks report as sg_message_id.
+func (p *SendGridProvider) SendEmail(recipients EmailRecipients, subject, body string, templateData map[string]interface{}, attachments ...Attachment) (SendResult, error) {
 	if p.apiKey == "" {
-		return fmt.Errorf("SendGrid API key not configured")
+		return SendResult{}, fmt.Errorf("SendGrid API key not configured")
 	}
 
-	from := mail.NewEmail(p.fromName, p.fromEmail)
-	toEmail := mail.NewEmail("", to)
-	message := mail.NewSingleEmail(from, subject, toEmail, body, body)
+	message := p.buildMessage(recipients, subject, body, templateData, attachments)
 
 	client := sendgrid.NewSendClient(p.apiKey)
+	start := time.Now()
 	response, err := client.Send(message)
+	result := SendResult{Latency: time.Since(start)}
 	if err != nil {
-		return fmt.Errorf("failed to send email via SendGrid: %w", err)
+		return result, fmt.Errorf("failed to send email via SendGrid: %w", err)
 	}
 
+	result.StatusCode = response.StatusCode
+	if ids := response.Headers["X-Message-Id"]; len(ids) > 0 {
+		result.ProviderMessageID = ids[0]
+	}
 	if response.StatusCode >= 400 {
-		return fmt.Errorf("SendGrid API error: %d - %s", response.StatusCode, response.Body)
+		return result, fmt.Errorf("SendGrid API error: %d - %s", response.StatusCode, response.Body)
+	}
+
+	p.logger.Info("[SENDGRID] Email sent", "to_email", recipients.To, "provider_message_id", result.ProviderMessageID)
+	return result, nil
+}
+
+// buildMessage assembles the SendGrid v3 message of an email.
+// CC and BCC addresses already receiving the email are dropped, SendGrid rejects duplicates within a personalization.
+// attachments are sent base64-encoded as required by the SendGrid v3 API.
+func (p *SendGridProvider) buildMessage(recipients EmailRecipients, subject, body string, templateData map[string]interface{}, attachments []Attachment) *mail.SGMailV3 {
+	message := mail.NewV3Mail()
+	message.SetFrom(mail.NewEmail(p.fromName, p.fromEmail))
+	message.Subject = subject
+	message.AddContent(mail.NewContent("text/plain", body), mail.NewContent("text/html", body))
+
+	seen := map[string]bool{strings.ToLower(recipients.To): true}
+	personalization := mail.NewPersonalization()
+	personalization.AddTos(mail.NewEmail("", recipients.To))
+	for _, address := range recipients.CC {
+		if !seen[strings.ToLower(address)] {
+			seen[strings.ToLower(address)] = true
+			personalization.AddCCs(mail.NewEmail("", address))
+		}
+	}
+	for _, address := range recipients.BCC {
+		if !seen[strings.ToLower(address)] {
+			seen[strings.ToLower(address)] = true
+			personalization.AddBCCs(mail.NewEmail("", address))
+		}
+	}
+	message.AddPersonalizations(personalization)
+
+	if recipients.ReplyTo != "" {
+		message.SetReplyTo(mail.NewEmail("", recipients.ReplyTo))
+	}
+
+	// Custom args are echoed back in event webhooks to correlate delivery events
+	if notificationID, ok := templateData["notification_id"].(string); ok && notificationID != "" {
+		message.SetCustomArg("notification_id", notificationID)
+	}
+
+	for _, attachment := range attachments {
+		a := mail.NewAttachment()
+		a.SetFilename(attachment.Filename)
+		a.SetType(attachment.ContentType)
+		a.SetContent(base64.StdEncoding.EncodeToString(attachment.Content))
+		a.SetDisposition("attachment")
+		message.AddAttachment(a)
 	}
 
-	log.Printf("[SENDGRID] Email sent successfully to %s", to)
-	return nil
+	return message
 }
 
 // GetProviderName returns the name of this email provider for logging and identification.
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"encoding/base64"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+
+	"user-risk-system/pkg/logger"
+)
+
+func addresses(emails []*mail.Email) []string {
+	var out []string
+	for _, email := range emails {
+		out = append(out, email.Address)
+	}
+	return out
+}
+
+func TestSendGridMessageCarriesCCAndBCC(t *testing.T) {
+	p := NewSendGridProvider("key", "noreply@example.com", "Risk System", logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+
+	message := p.buildMessage(EmailRecipients{
+		To:      "user@example.com",
+		CC:      []string{"security@example.com", "USER@example.com", "soc@example.com"},
+		BCC:     []string{"audit@example.com", "Security@example.com"},
+		ReplyTo: "support@example.com",
+	}, "Alert", "body", map[string]interface{}{"notification_id": "n-1"}, nil)
+
+	if len(message.Personalizations) != 1 {
+		t.Fatalf("personalizations = %d, want 1", len(message.Personalizations))
+	}
+	personalization := message.Personalizations[0]
+	for field, tt := range map[string]struct {
+		got  []*mail.Email
+		want []string
+	}{
+		"To":  {personalization.To, []string{"user@example.com"}},
+		"CC":  {personalization.CC, []string{"security@example.com", "soc@example.com"}},
+		"BCC": {personalization.BCC, []string{"audit@example.com"}},
+	} {
+		if got := addresses(tt.got); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v without duplicates", field, got, tt.want)
+		}
+	}
+	if message.ReplyTo == nil || message.ReplyTo.Address != "support@example.com" {
+		t.Errorf("ReplyTo = %+v, want support@example.com", message.ReplyTo)
+	}
+	if message.CustomArgs["notification_id"] != "n-1" {
+		t.Errorf("custom args = %v, want the notification ID", message.CustomArgs)
+	}
+}
+
+func TestSendGridMessageEncodesAttachments(t *testing.T) {
+	p := NewSendGridProvider("key", "noreply@example.com", "Risk System", logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+
+	message := p.buildMessage(EmailRecipients{To: "user@example.com"}, "Risk report", "body", nil, []Attachment{
+		{Filename: "risk-report.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4 report")},
+	})
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("attachments = %d, want 1", len(message.Attachments))
+	}
+	attachment := message.Attachments[0]
+	if attachment.Filename != "risk-report.pdf" || attachment.Type != "application/pdf" || attachment.Disposition != "attachment" {
+		t.Errorf("attachment = %+v, want risk-report.pdf as an application/pdf attachment", attachment)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("%PDF-1.4 report")); attachment.Content != want {
+		t.Errorf("content = %q, want base64 %q", attachment.Content, want)
+	}
+}
@@ -2,40 +2,60 @@ package providers
 
 import (
 	"fmt"
-	"log"
 	"math/rand"
+	"net/http"
 	"time"
+	"user-risk-system/pkg/logger"
+
+	"github.com/google/uuid"
 )
 
 // SimulateEmailProvider simulates email sending for testing and development.
 // logs email details without actually sending them, with configurable failure rates.
-type SimulateEmailProvider struct{}
+type SimulateEmailProvider struct {
+	logger *logger.Logger
+}
 
 // NewSimulateEmailProvider creates a new email simulation provider.
-func NewSimulateEmailProvider() *SimulateEmailProvider {
-	return &SimulateEmailProvider{}
+func NewSimulateEmailProvider(logger *logger.Logger) *SimulateEmailProvider {
+	return &SimulateEmailProvider{logger: logger}
 }
 
 // SendEmail simulates sending an email with random delays and occasional failures.
-// logs all email details and includes a 5% simulated failure rate for testing.
-func (p *SimulateEmailProvider) SendEmail(to, subject, body string, templateData map[string]interface{}) error {
-	log.Printf("📧 [SIMULATE] Sending Email")
-	log.Printf("   To: %s", to)
-	log.Printf("   Subject: %s", subject)
-	log.Printf("   Body: %s", body)
-	if len(templateData) > 0 {
-		log.Printf("   Template Data: %+v", templateData)
+// logs the email without its body or attachment contents, recipients go under email keys so LOG_MASK_PII masks them.
+// includes a 5% simulated failure rate for testing.
+func (p *SimulateEmailProvider) SendEmail(recipients EmailRecipients, subject, body string, templateData map[string]interface{}, attachments ...Attachment) (SendResult, error) {
+	args := []any{
+		"to_email", recipients.To,
+		"subject", subject,
+		"body_bytes", len(body),
+		"template_fields", len(templateData),
+		"attachments", len(attachments),
+	}
+	for i, attachment := range attachments {
+		args = append(args, fmt.Sprintf("attachment_%d_name", i), attachment.Filename, fmt.Sprintf("attachment_%d_bytes", i), len(attachment.Content))
+	}
+	for i, address := range recipients.CC {
+		args = append(args, fmt.Sprintf("cc_%d_email", i), address)
+	}
+	for i, address := range recipients.BCC {
+		args = append(args, fmt.Sprintf("bcc_%d_email", i), address)
+	}
+	if recipients.ReplyTo != "" {
+		args = append(args, "reply_to_email", recipients.ReplyTo)
 	}
+	p.logger.Info("[SIMULATE] Sending email", args...)
 
-	time.Sleep(time.Duration(100+rand.Intn(200)) * time.Millisecond)
+	result := simulateCall(time.Duration(100+rand.Intn(200)) * time.Millisecond)
 
 	// (5% failure rate)
 	if rand.Intn(100) < 5 {
-		return fmt.Errorf("simulated email delivery failure")
+		result.StatusCode = http.StatusServiceUnavailable
+		return result, fmt.Errorf("simulated email delivery failure")
 	}
 
-	log.Printf("   ✅ Email sent successfully (simulated)")
-	return nil
+	p.logger.Info("[SIMULATE] Email sent", "to_email", recipients.To, "provider_message_id", result.ProviderMessageID)
+	return result, nil
 }
 
 // GetProviderName returns the name of this simulation provider.
@@ -44,29 +64,30 @@ func (p *SimulateEmailProvider) GetProviderName() string {
 }
 
 // SimulateSMSProvider simulates SMS sending for testing and development.
-type SimulateSMSProvider struct{}
+type SimulateSMSProvider struct {
+	logger *logger.Logger
+}
 
 // NewSimulateSMSProvider creates a new SMS simulation provider.
-func NewSimulateSMSProvider() *SimulateSMSProvider {
-	return &SimulateSMSProvider{}
+func NewSimulateSMSProvider(logger *logger.Logger) *SimulateSMSProvider {
+	return &SimulateSMSProvider{logger: logger}
 }
 
 // SendSMS simulates sending an SMS with random delays and occasional failures.
-// logs all SMS details and includes a 3% simulated failure rate for testing.
-func (p *SimulateSMSProvider) SendSMS(to, message string) error {
-	log.Printf("📱 [SIMULATE] Sending SMS")
-	log.Printf("   To: %s", to)
-	log.Printf("   Message: %s", message)
+// logs the number under a phone key and the message length only, with a 3% simulated failure rate.
+func (p *SimulateSMSProvider) SendSMS(to, message string) (SendResult, error) {
+	p.logger.Info("[SIMULATE] Sending SMS", "to_phone", to, "message_bytes", len(message))
 
-	time.Sleep(time.Duration(50+rand.Intn(100)) * time.Millisecond)
+	result := simulateCall(time.Duration(50+rand.Intn(100)) * time.Millisecond)
 
 	// (3% failure rate)
 	if rand.Intn(100) < 3 {
-		return fmt.Errorf("simulated SMS delivery failure")
+		result.StatusCode = http.StatusServiceUnavailable
+		return result, fmt.Errorf("simulated SMS delivery failure")
 	}
 
-	log.Printf("   ✅ SMS sent successfully (simulated)")
-	return nil
+	p.logger.Info("[SIMULATE] SMS sent", "to_phone", to, "provider_message_id", result.ProviderMessageID)
+	return result, nil
 }
 
 // GetProviderName returns the name of this SMS simulation provider.
@@ -76,36 +97,50 @@ func (p *SimulateSMSProvider) GetProviderName() string {
 
 // SimulatePushProvider simulates push notifications for testing and development.
 // logs push notification details without actually sending them.
-type SimulatePushProvider struct{}
+type SimulatePushProvider struct {
+	logger *logger.Logger
+}
 
 // NewSimulatePushProvider creates a new push notification simulation provider.
-func NewSimulatePushProvider() *SimulatePushProvider {
-	return &SimulatePushProvider{}
+func NewSimulatePushProvider(logger *logger.Logger) *SimulatePushProvider {
+	return &SimulatePushProvider{logger: logger}
 }
 
 // SendPush simulates sending a push notification with random delays and occasional failures.
-// logs all push notification details and includes a 2% simulated failure rate.
-func (p *SimulatePushProvider) SendPush(userID, title, message string, data map[string]interface{}) error {
-	log.Printf("🔔 [SIMULATE] Sending Push Notification")
-	log.Printf("   User ID: %s", userID)
-	log.Printf("   Title: %s", title)
-	log.Printf("   Message: %s", message)
-	if len(data) > 0 {
-		log.Printf("   Data: %+v", data)
-	}
+// logs the title and sizes only, the message and data may carry personal details. 2% simulated failure rate.
+func (p *SimulatePushProvider) SendPush(userID, title, message string, data map[string]interface{}) (SendResult, error) {
+	p.logger.Info("[SIMULATE] Sending push notification",
+		"user_id", userID,
+		"title", title,
+		"message_bytes", len(message),
+		"data_fields", len(data),
+	)
 
-	time.Sleep(time.Duration(30+rand.Intn(70)) * time.Millisecond)
+	result := simulateCall(time.Duration(30+rand.Intn(70)) * time.Millisecond)
 
 	// (2% failure rate)
 	if rand.Intn(100) < 2 {
-		return fmt.Errorf("simulated push notification delivery failure")
+		result.StatusCode = http.StatusServiceUnavailable
+		return result, fmt.Errorf("simulated push notification delivery failure")
 	}
 
-	log.Printf("   ✅ Push notification sent successfully (simulated)")
-	return nil
+	p.logger.Info("[SIMULATE] Push notification sent", "user_id", userID, "provider_message_id", result.ProviderMessageID)
+	return result, nil
 }
 
 // GetProviderName returns the name of this push notification simulation provider.
 func (p *SimulatePushProvider) GetProviderName() string {
 	return "SIMULATE_PUSH"
 }
+
+// simulateCall waits delay like a provider API would and returns the result of an accepted call.
+// simulated message IDs are prefixed sim- so they can't be mistaken for real provider IDs.
+func simulateCall(delay time.Duration) SendResult {
+	start := time.Now()
+	time.Sleep(delay)
+	return SendResult{
+		ProviderMessageID: "sim-" + uuid.New().String(),
+		StatusCode:        http.StatusAccepted,
+		Latency:           time.Since(start),
+	}
+}
@@ -0,0 +1,104 @@
+package providers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/pkg/logger"
+)
+
+func TestSimulateProvidersMaskPII(t *testing.T) {
+	var out bytes.Buffer
+	log := logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &out, MaskPII: true})
+
+	recipients := providers.EmailRecipients{
+		To:      "jane.doe@example.com",
+		CC:      []string{"carbon@example.com"},
+		BCC:     []string{"blind@example.com"},
+		ReplyTo: "support@example.com",
+	}
+	// Delivery fails at random, only the log output matters here
+	_, _ = providers.NewSimulateEmailProvider(log).SendEmail(recipients, "Welcome", "Hi Jane, your code is 123456", map[string]interface{}{"code": "123456"})
+	_, _ = providers.NewSimulateSMSProvider(log).SendSMS("+15551234567", "Your code is 123456")
+	_, _ = providers.NewSimulatePushProvider(log).SendPush("user-1", "Login", "Your code is 123456", map[string]interface{}{"code": "123456"})
+
+	logged := out.String()
+	for _, raw := range []string{"jane.doe@", "carbon@", "blind@", "support@", "+15551234567", "123456"} {
+		if strings.Contains(logged, raw) {
+			t.Errorf("log output contains %q:\n%s", raw, logged)
+		}
+	}
+	for _, masked := range []string{"ja***@example.com", "ca***@example.com", "***67"} {
+		if !strings.Contains(logged, masked) {
+			t.Errorf("log output misses masked %q:\n%s", masked, logged)
+		}
+	}
+}
+
+func TestSimulateProvidersReturnPopulatedResult(t *testing.T) {
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	senders := map[string]struct {
+		send     func() (providers.SendResult, error)
+		minDelay time.Duration
+	}{
+		"email": {func() (providers.SendResult, error) {
+			return providers.NewSimulateEmailProvider(log).SendEmail(providers.EmailRecipients{To: "user@example.com"}, "Welcome", "Hi", nil)
+		}, 100 * time.Millisecond},
+		"sms": {func() (providers.SendResult, error) {
+			return providers.NewSimulateSMSProvider(log).SendSMS("+15551234567", "Hi")
+		}, 50 * time.Millisecond},
+		"push": {func() (providers.SendResult, error) {
+			return providers.NewSimulatePushProvider(log).SendPush("user-1", "Login", "Hi", nil)
+		}, 30 * time.Millisecond},
+	}
+
+	for name, sender := range senders {
+		t.Run(name, func(t *testing.T) {
+			// Sends fail at random, retry until one is accepted
+			for attempt := 0; attempt < 20; attempt++ {
+				result, err := sender.send()
+				if !strings.HasPrefix(result.ProviderMessageID, "sim-") || result.Latency < sender.minDelay {
+					t.Fatalf("result = %+v, want a sim- message ID and at least %v latency", result, sender.minDelay)
+				}
+				if err != nil {
+					if result.StatusCode != http.StatusServiceUnavailable {
+						t.Fatalf("failed send status = %d, want 503", result.StatusCode)
+					}
+					continue
+				}
+				if result.StatusCode != http.StatusAccepted {
+					t.Errorf("status = %d, want 202", result.StatusCode)
+				}
+				return
+			}
+			t.Fatal("no simulated send succeeded")
+		})
+	}
+}
+
+func TestSimulateEmailLogsAttachments(t *testing.T) {
+	var out bytes.Buffer
+	log := logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &out})
+	attachments := []providers.Attachment{
+		{Filename: "risk-report.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4 report")},
+		{Filename: "checks.csv", ContentType: "text/csv", Content: []byte("check_id\n")},
+	}
+
+	// Delivery fails at random, only the log output matters here
+	_, _ = providers.NewSimulateEmailProvider(log).SendEmail(providers.EmailRecipients{To: "user@example.com"}, "Risk report", "Attached", nil, attachments...)
+
+	logged := out.String()
+	for _, want := range []string{`"attachments":2`, `"attachment_0_name":"risk-report.pdf"`, `"attachment_0_bytes":15`, `"attachment_1_name":"checks.csv"`, `"attachment_1_bytes":9`} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log output misses %s:\n%s", want, logged)
+		}
+	}
+	if strings.Contains(logged, "%PDF") {
+		t.Errorf("log output contains attachment contents:\n%s", logged)
+	}
+}
@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"user-risk-system/pkg/pii"
+)
+
+// SlackProvider implements the AlertProvider interface using a Slack incoming webhook.
+// formats alerts as Block Kit messages for on-call channels.
+type SlackProvider struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackProvider creates a new Slack alert provider posting to the given incoming webhook.
+// Returns nil if the webhook URL is not configured, so alerts fall back to email only.
+func NewSlackProvider(webhookURL string) *SlackProvider {
+	if webhookURL == "" {
+		log.Printf("Slack webhook URL not configured, admin alerts will use email only")
+		return nil
+	}
+
+	return &SlackProvider{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendAlert posts the alert to Slack as a Block Kit message.
+func (p *SlackProvider) SendAlert(alert Alert) error {
+	body, err := json.Marshal(BuildSlackMessage(alert))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+
+	resp, err := p.client.Post(p.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send Slack alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Slack webhook error: %d", resp.StatusCode)
+	}
+
+	log.Printf("[SLACK] Alert posted: %s", alert.Title)
+	return nil
+}
+
+// GetProviderName returns the name of this alert provider for logging and identification.
+func (p *SlackProvider) GetProviderName() string {
+	return "SLACK"
+}
+
+// BuildSlackMessage converts an alert into a Slack Block Kit payload.
+// the user's email is masked so PII is not spread into chat history.
+func BuildSlackMessage(alert Alert) map[string]interface{} {
+	user := pii.MaskEmail(alert.UserEmail)
+	if alert.UserID != "" {
+		user = fmt.Sprintf("%s (%s)", user, alert.UserID)
+	}
+
+	flags := "none"
+	if len(alert.Flags) > 0 {
+		flags = "`" + strings.Join(alert.Flags, "`, `") + "`"
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{
+				"type": "plain_text",
+				"text": alert.Title,
+			},
+		},
+		{
+			"type": "section",
+			"fields": []map[string]interface{}{
+				{"type": "mrkdwn", "text": "*Risk Level:*\n" + alert.RiskLevel},
+				{"type": "mrkdwn", "text": "*User:*\n" + user},
+			},
+		},
+		{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": "*Reason:*\n" + alert.Reason,
+			},
+		},
+		{
+			"type": "context",
+			"elements": []map[string]interface{}{
+				{"type": "mrkdwn", "text": "*Flags:* " + flags},
+			},
+		},
+	}
+
+	return map[string]interface{}{
+		"text":   fmt.Sprintf("%s: %s risk for %s", alert.Title, alert.RiskLevel, user),
+		"blocks": blocks,
+	}
+}
@@ -0,0 +1,99 @@
+package providers_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"user-risk-system/cmd/notification/providers"
+	"user-risk-system/pkg/pii"
+)
+
+// slackMessage is the part of a Block Kit payload the tests inspect.
+type slackMessage struct {
+	Text   string `json:"text"`
+	Blocks []struct {
+		Type string `json:"type"`
+		Text struct {
+			Text string `json:"text"`
+		} `json:"text"`
+		Fields []struct {
+			Text string `json:"text"`
+		} `json:"fields"`
+		Elements []struct {
+			Text string `json:"text"`
+		} `json:"elements"`
+	} `json:"blocks"`
+}
+
+func TestSlackAlertPostsCriticalRiskBlocks(t *testing.T) {
+	var raw []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		raw, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	alert := providers.Alert{
+		Title:     "Critical risk detected",
+		RiskLevel: "CRITICAL",
+		UserID:    "user-1",
+		UserEmail: "jane.doe@example.com",
+		Reason:    "Email domain is blacklisted",
+		Flags:     []string{"DOMAIN_BLACKLIST", "DISPOSABLE_EMAIL"},
+	}
+	if err := providers.NewSlackProvider(server.URL).SendAlert(alert); err != nil {
+		t.Fatalf("SendAlert() error = %v", err)
+	}
+
+	if strings.Contains(string(raw), alert.UserEmail) {
+		t.Errorf("payload contains the unmasked email: %s", raw)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	user := pii.MaskEmail(alert.UserEmail) + " (user-1)"
+	if len(msg.Blocks) != 4 {
+		t.Fatalf("blocks = %d, want header, fields, reason and flags", len(msg.Blocks))
+	}
+	if msg.Blocks[0].Type != "header" || msg.Blocks[0].Text.Text != alert.Title {
+		t.Errorf("header block = %+v", msg.Blocks[0])
+	}
+	if fields := msg.Blocks[1].Fields; len(fields) != 2 || fields[0].Text != "*Risk Level:*\nCRITICAL" || fields[1].Text != "*User:*\n"+user {
+		t.Errorf("fields block = %+v", fields)
+	}
+	if msg.Blocks[2].Text.Text != "*Reason:*\n"+alert.Reason {
+		t.Errorf("reason block = %q", msg.Blocks[2].Text.Text)
+	}
+	if elements := msg.Blocks[3].Elements; len(elements) != 1 || elements[0].Text != "*Flags:* `DOMAIN_BLACKLIST`, `DISPOSABLE_EMAIL`" {
+		t.Errorf("flags block = %+v", elements)
+	}
+	if msg.Text != "Critical risk detected: CRITICAL risk for "+user {
+		t.Errorf("fallback text = %q", msg.Text)
+	}
+}
+
+func TestSlackAlertReportsWebhookErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := providers.NewSlackProvider(server.URL).SendAlert(providers.Alert{Title: "Critical risk detected"}); err == nil {
+		t.Error("SendAlert() succeeded on a 403 response")
+	}
+}
+
+func TestNewSlackProviderDisabledWithoutURL(t *testing.T) {
+	if provider := providers.NewSlackProvider(""); provider != nil {
+		t.Error("NewSlackProvider() without a webhook URL returned a provider")
+	}
+}
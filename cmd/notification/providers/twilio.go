@@ -1,10 +1,14 @@
 package providers
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
+	"time"
+	"user-risk-system/pkg/logger"
 
 	"github.com/twilio/twilio-go"
+	"github.com/twilio/twilio-go/client"
 	api "github.com/twilio/twilio-go/rest/api/v2010"
 )
 
@@ -13,13 +17,14 @@ import (
 type TwilioProvider struct {
 	client     *twilio.RestClient
 	fromNumber string
+	logger     *logger.Logger
 }
 
 // NewTwilioProvider creates a new Twilio SMS provider with the given credentials.
 // Returns nil if credentials are not properly configured, allowing fallback to simulation.
-func NewTwilioProvider(accountSid, authToken, fromNumber string) *TwilioProvider {
+func NewTwilioProvider(accountSid, authToken, fromNumber string, logger *logger.Logger) *TwilioProvider {
 	if accountSid == "" || authToken == "" {
-		log.Printf("Twilio credentials not configured, will fall back to simulation")
+		logger.Warn("Twilio credentials not configured, will fall back to simulation")
 		return nil
 	}
 
@@ -31,14 +36,15 @@ func NewTwilioProvider(accountSid, authToken, fromNumber string) *TwilioProvider
 	return &TwilioProvider{
 		client:     client,
 		fromNumber: fromNumber,
+		logger:     logger,
 	}
 }
 
 // SendSMS sends an SMS message using the Twilio API.
-// validates the client configuration and handles API errors.
-func (p *TwilioProvider) SendSMS(to, message string) error {
+// validates the client configuration and handles API errors, the result carries the message SID.
+func (p *TwilioProvider) SendSMS(to, message string) (SendResult, error) {
 	if p.client == nil {
-		return fmt.Errorf("Twilio client not configured")
+		return SendResult{}, fmt.Errorf("Twilio client not configured")
 	}
 
 	params := &api.CreateMessageParams{}
@@ -46,13 +52,25 @@ func (p *TwilioProvider) SendSMS(to, message string) error {
 	params.SetFrom(p.fromNumber)
 	params.SetBody(message)
 
+	start := time.Now()
 	resp, err := p.client.Api.CreateMessage(params)
+	result := SendResult{Latency: time.Since(start)}
 	if err != nil {
-		return fmt.Errorf("failed to send SMS via Twilio: %w", err)
+		var restErr *client.TwilioRestError
+		if errors.As(err, &restErr) {
+			result.StatusCode = restErr.Status
+		}
+		return result, fmt.Errorf("failed to send SMS via Twilio: %w", err)
 	}
 
-	log.Printf("[TWILIO] SMS sent successfully to %s (SID: %s)", to, *resp.Sid)
-	return nil
+	// Twilio answers a created message with 201, the client doesn't expose the response itself
+	result.StatusCode = http.StatusCreated
+	if resp.Sid != nil {
+		result.ProviderMessageID = *resp.Sid
+	}
+
+	p.logger.Info("[TWILIO] SMS sent", "to_phone", to, "provider_message_id", result.ProviderMessageID)
+	return result, nil
 }
 
 // GetProviderName returns the name of this SMS provider for logging and identification.
@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook request headers sent with every delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature" // sha256=<hex HMAC of "<timestamp>.<body>">
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Unix seconds used in the signature
+	WebhookEventHeader     = "X-Webhook-Event"     // Notification type of the payload
+)
+
+// HTTPWebhookProvider implements the WebhookProvider interface by POSTing signed JSON payloads.
+// retries transient failures (network errors and 5xx responses) with linear backoff.
+type HTTPWebhookProvider struct {
+	url        string
+	secret     string            // Signs payloads of organizations without their own secret
+	orgSecrets map[string]string // Secret per organization ID
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewWebhookProvider creates a new webhook provider posting to the given URL.
+// payloads are signed with the secret of their organization in orgSecrets, or secret for other organizations.
+// Returns nil if the URL or every secret is not configured, so the channel stays disabled.
+func NewWebhookProvider(url, secret string, orgSecrets map[string]string, timeout time.Duration) *HTTPWebhookProvider {
+	if url == "" || (secret == "" && len(orgSecrets) == 0) {
+		log.Printf("Webhook URL or secret not configured, webhook channel disabled")
+		return nil
+	}
+
+	return &HTTPWebhookProvider{
+		url:        url,
+		secret:     secret,
+		orgSecrets: orgSecrets,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: 3,
+		backoff:    500 * time.Millisecond,
+	}
+}
+
+// SendWebhook POSTs the payload as JSON with an HMAC-SHA256 signature header using the secret of orgID.
+// non-retryable 4xx responses fail immediately without further attempts.
+func (p *HTTPWebhookProvider) SendWebhook(orgID, eventType string, payload map[string]interface{}) error {
+	secret := p.secretFor(orgID)
+	if secret == "" {
+		return fmt.Errorf("no webhook secret configured for organization %s", orgID)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= p.maxRetries; attempt++ {
+		retry, err := p.deliver(secret, eventType, body)
+		if err == nil {
+			log.Printf("[WEBHOOK] %s delivered to %s", eventType, p.url)
+			return nil
+		}
+
+		lastErr = err
+		if !retry {
+			break
+		}
+		if attempt < p.maxRetries {
+			time.Sleep(p.backoff * time.Duration(attempt))
+		}
+	}
+
+	return fmt.Errorf("failed to deliver webhook: %w", lastErr)
+}
+
+// secretFor returns the signing secret of orgID, the shared secret when it has none of its own.
+func (p *HTTPWebhookProvider) secretFor(orgID string) string {
+	if secret, ok := p.orgSecrets[orgID]; ok {
+		return secret
+	}
+	return p.secret
+}
+
+// deliver performs a single POST signed with secret and reports whether a failure is retryable.
+func (p *HTTPWebhookProvider) deliver(secret, eventType string, body []byte) (bool, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(secret, timestamp, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// GetProviderName returns the name of this webhook provider for logging and identification.
+func (p *HTTPWebhookProvider) GetProviderName() string {
+	return "WEBHOOK"
+}
+
+// SignWebhookPayload computes the hex HMAC-SHA256 of "<timestamp>.<body>" with the secret.
+// receivers recompute it to verify both the payload and the timestamp were not tampered with.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,104 @@
+package providers_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"user-risk-system/cmd/notification/providers"
+)
+
+// webhookReceiver records the deliveries made to it, answering each with status.
+type webhookReceiver struct {
+	mu         sync.Mutex
+	status     int
+	deliveries []*http.Request
+	bodies     [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.deliveries = append(r.deliveries, req)
+	r.bodies = append(r.bodies, body)
+	r.mu.Unlock()
+	w.WriteHeader(r.status)
+}
+
+func TestWebhookSignsWithOrganizationSecret(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	provider := providers.NewWebhookProvider(server.URL, "shared-secret", map[string]string{"acme": "acme-secret"}, time.Second)
+
+	tests := []struct {
+		orgID  string
+		secret string
+	}{
+		{"acme", "acme-secret"},
+		{"default", "shared-secret"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.orgID, func(t *testing.T) {
+			payload := map[string]interface{}{"user_id": "user-1", "org_id": tt.orgID}
+			if err := provider.SendWebhook(tt.orgID, "RISK_DETECTED", payload); err != nil {
+				t.Fatalf("SendWebhook() error = %v", err)
+			}
+
+			req, body := receiver.deliveries[i], receiver.bodies[i]
+			if got := req.Header.Get(providers.WebhookEventHeader); got != "RISK_DETECTED" {
+				t.Errorf("event header = %q, want RISK_DETECTED", got)
+			}
+			want := "sha256=" + providers.SignWebhookPayload(tt.secret, req.Header.Get(providers.WebhookTimestampHeader), body)
+			if got := req.Header.Get(providers.WebhookSignatureHeader); got != want {
+				t.Errorf("signature = %q, want %q signed with %s", got, want, tt.secret)
+			}
+
+			var received map[string]interface{}
+			if err := json.Unmarshal(body, &received); err != nil || received["org_id"] != tt.orgID {
+				t.Errorf("payload = %s, want org_id %s", body, tt.orgID)
+			}
+		})
+	}
+}
+
+func TestWebhookWithoutSecretForOrganization(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	provider := providers.NewWebhookProvider(server.URL, "", map[string]string{"acme": "acme-secret"}, time.Second)
+	err := provider.SendWebhook("other", "RISK_DETECTED", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "other") {
+		t.Fatalf("SendWebhook() error = %v, want one naming the organization", err)
+	}
+	if len(receiver.deliveries) != 0 {
+		t.Errorf("delivered %d unsigned webhooks", len(receiver.deliveries))
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	receiver := &webhookReceiver{status: http.StatusBadRequest}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	provider := providers.NewWebhookProvider(server.URL, "shared-secret", nil, time.Second)
+	if err := provider.SendWebhook("default", "RISK_DETECTED", map[string]interface{}{}); err == nil {
+		t.Fatal("SendWebhook() succeeded on a 400 response")
+	}
+	if len(receiver.deliveries) != 1 {
+		t.Errorf("deliveries = %d, want 1", len(receiver.deliveries))
+	}
+}
+
+func TestNewWebhookProviderDisabledWithoutSecrets(t *testing.T) {
+	if provider := providers.NewWebhookProvider("https://hooks.example.com", "", nil, time.Second); provider != nil {
+		t.Error("NewWebhookProvider() without secrets returned a provider")
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/cmd/notification/models"
+)
+
+// EventRepository provides database operations for notification status transitions.
+type EventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository creates a new event repository with the provided database connection.
+func NewEventRepository(db *gorm.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Append records a status transition, events are never updated or deleted.
+func (r *EventRepository) Append(event *models.NotificationEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to append notification event: %w", err)
+	}
+	return nil
+}
+
+// ListByNotification returns the events of a notification in the order they were recorded.
+func (r *EventRepository) ListByNotification(notificationID string) ([]models.NotificationEvent, error) {
+	var events []models.NotificationEvent
+	if err := r.db.Where("notification_id = ?", notificationID).Order("id").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notification events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"user-risk-system/cmd/notification/models"
+)
+
+// NotificationRepository provides database operations for the stored status of notifications.
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository with the provided database connection.
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores a new notification, a notification already stored is left as it is.
+func (r *NotificationRepository) Create(record *models.NotificationRecord) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+	return nil
+}
+
+// Get returns the stored notification, or nil if there is none with that ID.
+func (r *NotificationRepository) Get(id string) (*models.NotificationRecord, error) {
+	var records []models.NotificationRecord
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load notification: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0], nil
+}
+
+// UpdateStatus sets the current status of a notification from a status transition,
+// returning false if the notification isn't stored.
+func (r *NotificationRepository) UpdateStatus(event *models.NotificationEvent) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     event.Status,
+		"details":    event.Details,
+		"updated_at": time.Now(),
+	}
+	if event.Channel != "" {
+		updates["channel"] = event.Channel
+	}
+	if event.Provider != "" {
+		updates["provider"] = event.Provider
+	}
+	if event.ProviderMessageID != "" {
+		updates["provider_message_id"] = event.ProviderMessageID
+	}
+
+	result := r.db.Model(&models.NotificationRecord{}).Where("id = ?", event.NotificationID).Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update notification status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"user-risk-system/cmd/notification/models"
+)
+
+// SuppressionRepository provides database operations for the notification suppression list.
+type SuppressionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuppressionRepository creates a new suppression repository with the provided database connection.
+func NewSuppressionRepository(db *gorm.DB) *SuppressionRepository {
+	return &SuppressionRepository{db: db}
+}
+
+// Lookup returns the suppression of recipient, or nil if recipient isn't suppressed.
+func (r *SuppressionRepository) Lookup(recipient string) (*models.Suppression, error) {
+	// Find rather than First, most recipients aren't suppressed and that isn't worth a not found log
+	var suppressions []models.Suppression
+	err := r.db.Where("recipient = ?", models.NormalizeRecipient(recipient)).Limit(1).Find(&suppressions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up suppression: %w", err)
+	}
+	if len(suppressions) == 0 {
+		return nil, nil
+	}
+	return &suppressions[0], nil
+}
+
+// Add suppresses a recipient, replacing the reason and details of an existing suppression.
+func (r *SuppressionRepository) Add(suppression *models.Suppression) error {
+	suppression.Recipient = models.NormalizeRecipient(suppression.Recipient)
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipient"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channel", "reason", "details", "created_by"}),
+	}).Create(suppression).Error
+	if err != nil {
+		return fmt.Errorf("failed to add suppression: %w", err)
+	}
+	return nil
+}
+
+// Remove lifts the suppression of recipient, returning false if it wasn't suppressed.
+func (r *SuppressionRepository) Remove(recipient string) (bool, error) {
+	result := r.db.Where("recipient = ?", models.NormalizeRecipient(recipient)).Delete(&models.Suppression{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove suppression: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List returns a page of suppressions, newest first, with the total number suppressed.
+func (r *SuppressionRepository) List(limit, offset int) ([]models.Suppression, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.Suppression{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count suppressions: %w", err)
+	}
+
+	var suppressions []models.Suppression
+	err := r.db.Order("created_at DESC, recipient").Limit(limit).Offset(offset).Find(&suppressions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list suppressions: %w", err)
+	}
+	return suppressions, total, nil
+}
@@ -1,5 +1,11 @@
 // Package templates provides email template management and rendering functionality.
 // supports both file-based and embedded templates with dynamic data substitution.
+//
+// Templates are html/template templates, so every field is escaped for the context it is rendered
+// in, HTML text, attributes and URLs alike. Fields are plain strings and templates get no functions
+// that mark a value as safe HTML, so user-derived values such as FirstName, Email, Reason and Flags
+// always render as text. Subjects are plain text with control characters removed, so a value can't
+// break the subject header.
 package templates
 
 import (
@@ -7,6 +13,10 @@ import (
 	"fmt"
 	"html/template"
 	"path/filepath"
+	"strings"
+	"unicode"
+
+	"user-risk-system/pkg/config"
 )
 
 // EmailTemplate represents an email template with subject and body content.
@@ -28,24 +38,44 @@ type EmailTemplateData struct {
 	Reason      string
 	RiskLevel   string
 	Flags       []string
+	Locale      string // Recipient language, selects localized templates and subjects
+
+	VerifyEmailURL    string
+	VerificationToken string // Email change token, appended to VerifyEmailURL
+}
+
+// BaseData is the branding and links rendered into every template, overriding any caller values.
+type BaseData struct {
+	CompanyName    string
+	SupportURL     string
+	LoginURL       string
+	VerifyEmailURL string
+}
+
+// BaseDataFromConfig returns the base data configured by the TEMPLATE_* settings.
+func BaseDataFromConfig(cfg *config.Config) BaseData {
+	return BaseData{
+		CompanyName:    cfg.TemplateCompanyName,
+		SupportURL:     cfg.TemplateSupportURL,
+		LoginURL:       cfg.TemplateLoginURL,
+		VerifyEmailURL: cfg.TemplateVerifyEmailURL,
+	}
 }
 
 // EmailTemplateManager handles email template loading, caching, and rendering.
 // supports both file-based templates and embedded fallback templates.
 type EmailTemplateManager struct {
 	templates map[string]*template.Template
-	baseData  EmailTemplateData
+	info      map[string]TemplateInfo // Source and fields of every template key, collected on load
+	baseData  BaseData
 }
 
-// NewEmailTemplateManager creates a new template manager with the specified template directory.
-func NewEmailTemplateManager(templateDir string) *EmailTemplateManager {
+// NewEmailTemplateManager creates a new template manager with the specified template directory and base data.
+func NewEmailTemplateManager(templateDir string, baseData BaseData) *EmailTemplateManager {
 	manager := &EmailTemplateManager{
 		templates: make(map[string]*template.Template),
-		baseData: EmailTemplateData{
-			CompanyName: "User Risk Management System",
-			SupportURL:  "https://support.unkn0wnroot.com",
-			LoginURL:    "https://app.unkn0wnroot.com/login",
-		},
+		info:      make(map[string]TemplateInfo),
+		baseData:  baseData,
 	}
 
 	manager.loadTemplates(templateDir)
@@ -53,12 +83,14 @@ func NewEmailTemplateManager(templateDir string) *EmailTemplateManager {
 }
 
 // loadTemplates loads email templates from files or falls back to embedded templates.
+// localized variants named <template>.<locale>.html (e.g. welcome.es.html) are loaded alongside.
 func (m *EmailTemplateManager) loadTemplates(templateDir string) {
 	templates := map[string]string{
 		"welcome":        "welcome.html",
 		"risk_alert":     "risk_alert.html",
 		"password_reset": "password_reset.html",
 		"login_alert":    "login_alert.html",
+		"email_change":   "email_change.html",
 	}
 
 	for name, filename := range templates {
@@ -66,20 +98,46 @@ func (m *EmailTemplateManager) loadTemplates(templateDir string) {
 		tmpl, err := template.ParseFiles(path)
 		if err != nil {
 			// Fallback to embedded templates if files not found
-			m.templates[name] = m.getEmbeddedTemplate(name)
+			m.register(name, m.getEmbeddedTemplate(name), SourceEmbedded)
 		} else {
-			m.templates[name] = tmpl
+			m.register(name, tmpl, SourceFile)
+		}
+
+		base := strings.TrimSuffix(filename, ".html")
+		localized, _ := filepath.Glob(filepath.Join(templateDir, base+".*.html"))
+		for _, localizedPath := range localized {
+			locale := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(localizedPath), base+"."), ".html")
+			if tmpl, err := template.ParseFiles(localizedPath); err == nil {
+				m.register(localizedKey(name, NormalizeLocale(locale)), tmpl, SourceFile)
+			}
 		}
 	}
 }
 
+// HasTemplate returns true if a template named templateName is loaded, in any locale.
+func (m *EmailTemplateManager) HasTemplate(templateName string) bool {
+	_, exists := m.templates[templateName]
+	return exists
+}
+
+// localizedKey returns the template map key for a localized template variant.
+func localizedKey(name, locale string) string {
+	return name + "." + locale
+}
+
 // RenderTemplate renders an email template with the provided data.
+// uses the template variant for data.Locale when available, otherwise the default template.
 func (m *EmailTemplateManager) RenderTemplate(templateName string, data EmailTemplateData) (string, string, error) {
 	data.CompanyName = m.baseData.CompanyName
 	data.SupportURL = m.baseData.SupportURL
 	data.LoginURL = m.baseData.LoginURL
+	data.VerifyEmailURL = m.baseData.VerifyEmailURL
+	data.Locale = NormalizeLocale(data.Locale)
 
-	tmpl, exists := m.templates[templateName]
+	tmpl, exists := m.templates[localizedKey(templateName, data.Locale)]
+	if !exists {
+		tmpl, exists = m.templates[templateName]
+	}
 	if !exists {
 		return "", "", fmt.Errorf("template not found: %s", templateName)
 	}
@@ -91,22 +149,44 @@ func (m *EmailTemplateManager) RenderTemplate(templateName string, data EmailTem
 		return "", "", fmt.Errorf("failed to render HTML template: %w", err)
 	}
 
-	return m.getSubject(templateName, data), htmlBuf.String(), nil
+	return m.Subject(templateName, data), htmlBuf.String(), nil
 }
 
-// getSubject generates the email subject line based on template type and data.
-func (m *EmailTemplateManager) getSubject(templateName string, data EmailTemplateData) string {
-	subjects := map[string]string{
-		"welcome":        fmt.Sprintf("Welcome to %s, %s!", data.CompanyName, data.FirstName),
-		"risk_alert":     fmt.Sprintf("🚨 Security Alert - %s Risk Detected", data.RiskLevel),
-		"password_reset": "Password Reset Request",
-		"login_alert":    "🔐 New Login to Your Account",
+// Subject generates the localized email subject line based on template type and data.
+// single source of truth for subjects, backed by the message catalog in i18n.go.
+func (m *EmailTemplateManager) Subject(templateName string, data EmailTemplateData) string {
+	if data.CompanyName == "" {
+		data.CompanyName = m.baseData.CompanyName
 	}
+	return plainText(m.subject(templateName, data))
+}
 
-	if subject, exists := subjects[templateName]; exists {
-		return subject
+// subject formats the subject line of templateName from the message catalog.
+func (m *EmailTemplateManager) subject(templateName string, data EmailTemplateData) string {
+	switch templateName {
+	case "welcome":
+		return Translate(data.Locale, "subject.welcome", data.CompanyName, data.FirstName)
+	case "risk_alert":
+		return Translate(data.Locale, "subject.risk_alert", data.RiskLevel)
+	case "password_reset":
+		return Translate(data.Locale, "subject.password_reset")
+	case "login_alert":
+		return Translate(data.Locale, "subject.login_alert")
+	case "email_change":
+		return Translate(data.Locale, "subject.email_change")
+	default:
+		return Translate(data.Locale, "subject.default", data.CompanyName)
 	}
-	return "Notification from " + data.CompanyName
+}
+
+// plainText replaces control characters such as CR and LF in s with spaces.
+func plainText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
 }
 
 // getEmbeddedTemplate returns hardcoded HTML templates as fallbacks.
@@ -160,6 +240,26 @@ func (m *EmailTemplateManager) getEmbeddedTemplate(name string) *template.Templa
 		<p>Security Team<br>{{.CompanyName}}</p>
 	</div>
 </body>
+</html>`,
+
+		"email_change": `
+<!DOCTYPE html>
+<html>
+<head><title>Confirm Your Email</title></head>
+<body style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto;">
+	<div style="background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); padding: 20px; text-align: center;">
+		<h1 style="color: white; margin: 0;">Confirm Your New Email</h1>
+	</div>
+	<div style="padding: 30px;">
+		<p>We received a request to change the email address of your {{.CompanyName}} account to <strong>{{.Email}}</strong>.</p>
+		<p>Your current address stays active until you confirm. This link expires in 24 hours.</p>
+		<div style="text-align: center; margin: 30px 0;">
+			<a href="{{.VerifyEmailURL}}?token={{.VerificationToken}}" style="background: #667eea; color: white; padding: 12px 30px; text-decoration: none; border-radius: 5px; display: inline-block;">Confirm Email</a>
+		</div>
+		<p>If you didn't request this change, you can ignore this email.</p>
+		<p>Best regards,<br>The {{.CompanyName}} Team</p>
+	</div>
+</body>
 </html>`,
 	}
 
@@ -0,0 +1,90 @@
+package templates_test
+
+import (
+	"strings"
+	"testing"
+
+	"user-risk-system/cmd/notification/templates"
+	"user-risk-system/pkg/config"
+)
+
+func TestRenderTemplateUsesConfiguredBaseData(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("TEMPLATE_COMPANY_NAME", "Staging Corp")
+	t.Setenv("TEMPLATE_SUPPORT_URL", "https://support.staging.example")
+	t.Setenv("TEMPLATE_LOGIN_URL", "https://app.staging.example/login")
+	t.Setenv("TEMPLATE_VERIFY_EMAIL_URL", "https://app.staging.example/verify")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manager := templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg))
+
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"welcome", []string{"Staging Corp", `href="https://app.staging.example/login"`, `href="https://support.staging.example"`}},
+		{"risk_alert", []string{"Staging Corp", `href="https://app.staging.example/login"`}},
+		{"email_change", []string{"Staging Corp", `href="https://app.staging.example/verify?token=token-1"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			// Caller values are overridden by the base data
+			subject, body, err := manager.RenderTemplate(tt.template, templates.EmailTemplateData{
+				FirstName:         "Ada",
+				CompanyName:       "Caller Corp",
+				LoginURL:          "https://caller.example/login",
+				VerificationToken: "token-1",
+			})
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body misses %s:\n%s", want, body)
+				}
+			}
+			if strings.Contains(body, "caller.example") || strings.Contains(body, "Caller Corp") || strings.Contains(subject, "Caller Corp") {
+				t.Errorf("caller base data rendered instead of the configured one:\n%s\n%s", subject, body)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateEscapesUserValues(t *testing.T) {
+	manager := templates.NewEmailTemplateManager("", templates.BaseData{CompanyName: "Acme", LoginURL: "https://app.example/login", VerifyEmailURL: "https://app.example/verify"})
+	const script = `<script>alert(1)</script>`
+	data := templates.EmailTemplateData{
+		FirstName:         script,
+		Email:             `eve+<img src=x onerror=alert(1)>@example.com`,
+		Reason:            "Name contains " + script,
+		RiskLevel:         "HIGH\r\nBcc: victim@example.com",
+		Flags:             []string{script},
+		VerificationToken: `"><script>alert(1)</script>`,
+	}
+
+	for _, name := range []string{"welcome", "risk_alert", "email_change"} {
+		t.Run(name, func(t *testing.T) {
+			subject, body, err := manager.RenderTemplate(name, data)
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			if strings.Contains(body, "<script>") || strings.Contains(body, "<img") {
+				t.Errorf("body renders user markup unescaped:\n%s", body)
+			}
+			if strings.ContainsAny(subject, "\r\n") {
+				t.Errorf("subject = %q, want control characters removed", subject)
+			}
+		})
+	}
+
+	_, body, err := manager.RenderTemplate("welcome", data)
+	if err != nil {
+		t.Fatalf("RenderTemplate() error = %v", err)
+	}
+	if !strings.Contains(body, "Hi &lt;script&gt;alert(1)&lt;/script&gt;,") {
+		t.Errorf("welcome body doesn't show the name as text:\n%s", body)
+	}
+}
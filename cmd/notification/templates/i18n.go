@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLocale is used when a recipient has no locale or an unsupported one.
+const DefaultLocale = "en"
+
+// catalog holds localized subject lines and SMS texts keyed by locale and message key.
+// format verbs must match across locales since callers pass the same arguments.
+var catalog = map[string]map[string]string{
+	"en": {
+		"subject.welcome":        "Welcome to %s, %s!",
+		"subject.risk_alert":     "\U0001F6A8 Security Alert - %s Risk Detected",
+		"subject.password_reset": "Password Reset Request",
+		"subject.login_alert":    "\U0001F510 New Login to Your Account",
+		"subject.email_change":   "Confirm Your New Email Address",
+		"subject.default":        "Notification from %s",
+		"sms.risk_detected":      "\U0001F6A8 SECURITY ALERT: %s Please check your email for details.",
+		"sms.password_reset":     "Password reset requested. %s",
+		"push.risk_detected":     "Security Alert",
+		"push.login_alert":       "New Login",
+		"push.default":           "Notification",
+	},
+	"es": {
+		"subject.welcome":        "¡Bienvenido a %s, %s!",
+		"subject.risk_alert":     "\U0001F6A8 Alerta de seguridad - Riesgo %s detectado",
+		"subject.password_reset": "Solicitud de restablecimiento de contraseña",
+		"subject.login_alert":    "\U0001F510 Nuevo inicio de sesión en tu cuenta",
+		"subject.email_change":   "Confirma tu nueva dirección de correo",
+		"subject.default":        "Notificación de %s",
+		"sms.risk_detected":      "\U0001F6A8 ALERTA DE SEGURIDAD: %s Revisa tu correo para más detalles.",
+		"sms.password_reset":     "Se solicitó restablecer la contraseña. %s",
+		"push.risk_detected":     "Alerta de seguridad",
+		"push.login_alert":       "Nuevo inicio de sesión",
+		"push.default":           "Notificación",
+	},
+}
+
+// NormalizeLocale maps a locale such as "es-MX" or "ES_es" to a supported language code.
+// returns DefaultLocale when the language has no catalog.
+func NormalizeLocale(locale string) string {
+	lang := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalog[lang]; ok {
+		return lang
+	}
+	return DefaultLocale
+}
+
+// Translate returns the localized message for key formatted with args.
+// falls back to the default locale, then to the key itself when no entry exists.
+func Translate(locale, key string, args ...interface{}) string {
+	format, ok := catalog[NormalizeLocale(locale)][key]
+	if !ok {
+		if format, ok = catalog[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,135 @@
+package templates
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+	"text/template/parse"
+	"unicode"
+)
+
+// Template sources reported by ListTemplates.
+const (
+	SourceFile     = "file"     // Parsed from the templates directory
+	SourceEmbedded = "embedded" // Built-in fallback, no file was found
+)
+
+// managedFields are set by the manager on every render, so callers never supply them.
+var managedFields = map[string]bool{
+	"CompanyName":    true,
+	"SupportURL":     true,
+	"LoginURL":       true,
+	"VerifyEmailURL": true,
+	"Locale":         true,
+}
+
+// TemplateInfo describes a loaded email template for integrators.
+type TemplateInfo struct {
+	Name    string
+	Source  string   // SourceFile or SourceEmbedded
+	Fields  []string // Data fields the template references, snake_case and sorted
+	Locales []string // Localized variants loaded besides the default template
+}
+
+// ListTemplates returns every template with its source, fields and localized variants, sorted by name.
+// the source and fields are those of the default template.
+func (m *EmailTemplateManager) ListTemplates() []TemplateInfo {
+	var list []TemplateInfo
+	for key, info := range m.info {
+		if strings.Contains(key, ".") {
+			continue
+		}
+		for other := range m.info {
+			if locale, ok := strings.CutPrefix(other, key+"."); ok {
+				info.Locales = append(info.Locales, locale)
+			}
+		}
+		sort.Strings(info.Locales)
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// register stores tmpl under key with its source and the fields it references.
+// fields are collected here because html/template rewrites the parse tree on first execution.
+func (m *EmailTemplateManager) register(key string, tmpl *template.Template, source string) {
+	m.templates[key] = tmpl
+
+	found := make(map[string]bool)
+	if tmpl.Tree != nil {
+		collectFields(tmpl.Tree.Root, found)
+	}
+	fields := make([]string, 0, len(found))
+	for field := range found {
+		if !managedFields[field] {
+			fields = append(fields, snakeCase(field))
+		}
+	}
+	sort.Strings(fields)
+
+	name, _, _ := strings.Cut(key, ".")
+	m.info[key] = TemplateInfo{Name: name, Source: source, Fields: fields}
+}
+
+// collectFields adds the top-level data fields referenced under node to found.
+// range and with bodies are skipped, dot refers to something else inside them.
+func collectFields(node parse.Node, found map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, found)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, found)
+	case *parse.IfNode:
+		collectFields(n.Pipe, found)
+		collectFields(n.List, found)
+		collectFields(n.ElseList, found)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, found)
+		collectFields(n.ElseList, found)
+	case *parse.WithNode:
+		collectFields(n.Pipe, found)
+		collectFields(n.ElseList, found)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, found)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, found)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, found)
+		}
+	case *parse.FieldNode:
+		found[n.Ident[0]] = true
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			found[n.Ident[1]] = true
+		}
+	}
+}
+
+// snakeCase converts a Go field name to snake_case, keeping acronyms together, e.g. VerifyEmailURL to verify_email_url.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
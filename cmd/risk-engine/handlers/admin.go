@@ -2,56 +2,69 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 	"user-risk-system/cmd/risk-engine/models"
-	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/features"
 	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/validator"
 	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
 
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// maxBulkRules caps how many rules a single CreateRiskRules call may carry.
+const maxBulkRules = 100
+
 // RiskAdminHandler manages risk rules through administrative gRPC endpoints.
+// every method acts on the organization of the calling admin's token.
 type RiskAdminHandler struct {
 	pb_risk.UnimplementedRiskAdminServiceServer
-	riskRepo   *repository.RiskRepository
+	riskRepo   RiskRepository
 	logger     *logger.Logger
 	riskEngine RiskEngineService
+	settings   *config.Settings        // Source of the reloadable rule expiry limit
+	analytics  *services.RiskAnalytics // Serves risk history
+
+	users  pb_user.UserServiceClient // Lists users for RecheckUsers, nil until EnableRecheck
+	checks *RiskHandler              // Runs and stores the checks of RecheckUsers
 }
 
 type RiskEngineService interface {
-	InvalidateCache()
+	InvalidateCache(orgID string)
+	GetCacheStats(orgID string) services.CacheStats
+	MatchesInput(rule models.RiskRule, input string) (bool, error)
+	FeatureFlags() []features.Flag
 }
 
-// NewRiskAdminHandler creates a new administrative handler with repository, logger, and risk engine dependencies.
-func NewRiskAdminHandler(riskRepo *repository.RiskRepository, logger *logger.Logger, riskEngine RiskEngineService) *RiskAdminHandler {
+// NewRiskAdminHandler creates a new administrative handler with repository, logger, risk engine, settings and analytics dependencies.
+func NewRiskAdminHandler(riskRepo RiskRepository, logger *logger.Logger, riskEngine RiskEngineService, settings *config.Settings, analytics *services.RiskAnalytics) *RiskAdminHandler {
 	return &RiskAdminHandler{
 		riskRepo:   riskRepo,
 		logger:     logger,
 		riskEngine: riskEngine,
+		settings:   settings,
+		analytics:  analytics,
 	}
 }
 
 // CreateRiskRule adds a new risk rule to the system via gRPC.
 // validates the request and creates a rule with optional expiration.
 func (h *RiskAdminHandler) CreateRiskRule(ctx context.Context, req *pb_risk.CreateRiskRuleRequest) (*pb_risk.CreateRiskRuleResponse, error) {
-	rule := &models.RiskRule{
-		ID:         uuid.New().String(),
-		Name:       req.Name,
-		Type:       req.Type,
-		Category:   req.Category,
-		Value:      req.Value,
-		Score:      int(req.Score),
-		IsActive:   req.IsActive,
-		Source:     "MANUAL",
-		Confidence: req.Confidence,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	errs := validateRule(req.Name, req.Type, req.Category, req.Value, req.Score, req.Priority, req.Confidence)
+	if errs = append(errs, h.validateExpiry(req.ExpiresInDays)...); len(errs) > 0 {
+		return nil, invalidArgument(errs)
 	}
 
-	if req.ExpiresInDays > 0 {
-		expiresAt := time.Now().AddDate(0, 0, int(req.ExpiresInDays))
-		rule.ExpiresAt = &expiresAt
+	rule := newManualRule(auth.OrgID(ctx), req)
+	if errs := validateExamples(h.riskEngine, rule); len(errs) > 0 {
+		return nil, invalidArgument(errs)
 	}
 
 	if err := h.riskRepo.CreateRule(rule); err != nil {
@@ -60,7 +73,7 @@ func (h *RiskAdminHandler) CreateRiskRule(ctx context.Context, req *pb_risk.Crea
 	}
 
 	// Invalidate cache to ensure new rule is immediately available
-	h.riskEngine.InvalidateCache()
+	h.riskEngine.InvalidateCache(rule.OrgID)
 
 	h.logger.InfoCtx(ctx, "Risk rule created", "rule_id", rule.ID, "name", rule.Name)
 
@@ -70,11 +83,136 @@ func (h *RiskAdminHandler) CreateRiskRule(ctx context.Context, req *pb_risk.Crea
 	}, nil
 }
 
+// CreateRiskRules adds a batch of risk rules via gRPC.
+// all rules are validated and created in one transaction unless partial is set, in which case
+// each valid rule is created on its own and failures are reported per rule.
+func (h *RiskAdminHandler) CreateRiskRules(ctx context.Context, req *pb_risk.CreateRiskRulesRequest) (*pb_risk.CreateRiskRulesResponse, error) {
+	if len(req.Rules) == 0 || len(req.Rules) > maxBulkRules {
+		return nil, invalidArgument(validator.ValidationErrors{{
+			Field:   "rules",
+			Code:    validator.CodeInvalidValue,
+			Message: fmt.Sprintf("must contain between 1 and %d rules", maxBulkRules),
+		}})
+	}
+
+	results := make([]*pb_risk.RiskRuleResult, len(req.Rules))
+	rules := make([]*models.RiskRule, len(req.Rules)) // nil where the rule failed validation
+	var invalid validator.ValidationErrors
+
+	for i, r := range req.Rules {
+		results[i] = &pb_risk.RiskRuleResult{Index: int32(i)}
+		errs := validateRule(r.Name, r.Type, r.Category, r.Value, r.Score, r.Priority, r.Confidence)
+		if errs = append(errs, h.validateExpiry(r.ExpiresInDays)...); len(errs) > 0 {
+			results[i].Error = errs.Error()
+			for _, e := range errs {
+				invalid = append(invalid, validator.ValidationError{Field: fmt.Sprintf("rules[%d].%s", i, e.Field), Code: e.Code, Message: e.Message})
+			}
+			continue
+		}
+
+		rule := newManualRule(auth.OrgID(ctx), r)
+		if errs := validateExamples(h.riskEngine, rule); len(errs) > 0 {
+			results[i].Error = errs.Error()
+			for _, e := range errs {
+				invalid = append(invalid, validator.ValidationError{Field: fmt.Sprintf("rules[%d].%s", i, e.Field), Code: e.Code, Message: e.Message})
+			}
+			continue
+		}
+
+		rules[i] = rule
+		results[i].RuleId = rule.ID
+	}
+
+	created := 0
+	if !req.Partial {
+		if len(invalid) > 0 {
+			return nil, invalidArgument(invalid)
+		}
+		if err := h.riskRepo.CreateRules(rules); err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to create risk rules", err, "count", len(rules))
+			return nil, err
+		}
+		for _, result := range results {
+			result.Success = true
+		}
+		created = len(rules)
+	} else {
+		for i, rule := range rules {
+			if rule == nil {
+				continue
+			}
+			if err := h.riskRepo.CreateRule(rule); err != nil {
+				h.logger.ErrorCtx(ctx, "Failed to create risk rule", err, "index", i)
+				results[i].RuleId = ""
+				results[i].Error = "failed to create risk rule"
+				continue
+			}
+			results[i].Success = true
+			created++
+		}
+	}
+
+	if created > 0 {
+		h.riskEngine.InvalidateCache(auth.OrgID(ctx))
+	}
+
+	h.logger.InfoCtx(ctx, "Risk rules created", "created", created, "requested", len(req.Rules), "partial", req.Partial)
+
+	return &pb_risk.CreateRiskRulesResponse{
+		Results: results,
+		Created: int32(created),
+	}, nil
+}
+
+// newManualRule builds an admin-created rule of orgID from the request, applying its optional expiration.
+func newManualRule(orgID string, req *pb_risk.CreateRiskRuleRequest) *models.RiskRule {
+	rule := &models.RiskRule{
+		ID:         uuid.New().String(),
+		OrgID:      orgID,
+		Name:       req.Name,
+		Type:       req.Type,
+		Category:   req.Category,
+		Value:      req.Value,
+		Score:      int(req.Score),
+		IsActive:   req.IsActive,
+		Source:     "MANUAL",
+		Confidence: req.Confidence,
+		Priority:   int(req.Priority),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+
+		ExampleMatch:   req.ExampleMatch,
+		ExampleNoMatch: req.ExampleNoMatch,
+	}
+
+	rule.ExpiresAt = expiresAt(req.ExpiresInDays)
+	return rule
+}
+
+// expiresAt returns the expiry of a rule that expires in days, or nil for 0 (permanent).
+func expiresAt(days int32) *time.Time {
+	if days <= 0 {
+		return nil
+	}
+	t := time.Now().AddDate(0, 0, int(days))
+	return &t
+}
+
 // UpdateRiskRule modifies an existing risk rule via gRPC.
 // updates all rule fields except ID and creation timestamp.
 func (h *RiskAdminHandler) UpdateRiskRule(ctx context.Context, req *pb_risk.UpdateRiskRuleRequest) (*pb_risk.UpdateRiskRuleResponse, error) {
+	errs := validateRule(req.Name, req.Type, req.Category, req.Value, req.Score, req.Priority, req.Confidence)
+	errs = append(errs, h.validateExpiry(req.ExpiresInDays)...)
+	if req.RuleId == "" {
+		errs = append(errs, validator.ValidationError{Field: "rule_id", Code: validator.CodeRequired, Message: "is required"})
+	}
+	if len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+
 	rule := &models.RiskRule{
 		ID:         req.RuleId,
+		OrgID:      auth.OrgID(ctx),
 		Name:       req.Name,
 		Type:       req.Type,
 		Category:   req.Category,
@@ -83,7 +221,16 @@ func (h *RiskAdminHandler) UpdateRiskRule(ctx context.Context, req *pb_risk.Upda
 		IsActive:   req.IsActive,
 		Source:     "MANUAL",
 		Confidence: req.Confidence,
+		Priority:   int(req.Priority),
+		ExpiresAt:  expiresAt(req.ExpiresInDays),
 		UpdatedAt:  time.Now(),
+
+		ExampleMatch:   req.ExampleMatch,
+		ExampleNoMatch: req.ExampleNoMatch,
+	}
+
+	if errs := validateExamples(h.riskEngine, rule); len(errs) > 0 {
+		return nil, invalidArgument(errs)
 	}
 
 	if err := h.riskRepo.UpdateRule(rule); err != nil {
@@ -91,7 +238,7 @@ func (h *RiskAdminHandler) UpdateRiskRule(ctx context.Context, req *pb_risk.Upda
 		return nil, err
 	}
 
-	h.riskEngine.InvalidateCache()
+	h.riskEngine.InvalidateCache(rule.OrgID)
 
 	h.logger.InfoCtx(ctx, "Risk rule updated", "rule_id", rule.ID)
 
@@ -103,7 +250,7 @@ func (h *RiskAdminHandler) UpdateRiskRule(ctx context.Context, req *pb_risk.Upda
 // ListRiskRules retrieves all active risk rules via gRPC.
 // returns rules with their current configuration and metadata.
 func (h *RiskAdminHandler) ListRiskRules(ctx context.Context, req *pb_risk.ListRiskRulesRequest) (*pb_risk.ListRiskRulesResponse, error) {
-	rules, err := h.riskRepo.GetActiveRules()
+	rules, err := h.riskRepo.GetActiveRules(auth.OrgID(ctx))
 	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to list risk rules", err)
 		return nil, err
@@ -120,11 +267,15 @@ func (h *RiskAdminHandler) ListRiskRules(ctx context.Context, req *pb_risk.ListR
 			Score:      int32(rule.Score),
 			IsActive:   rule.IsActive,
 			Confidence: rule.Confidence,
-			CreatedAt:  rule.CreatedAt.Unix(),
-			UpdatedAt:  rule.UpdatedAt.Unix(),
+			Priority:   int32(rule.Priority),
+			CreatedAt:  timestamppb.New(rule.CreatedAt),
+			UpdatedAt:  timestamppb.New(rule.UpdatedAt),
+
+			ExampleMatch:   rule.ExampleMatch,
+			ExampleNoMatch: rule.ExampleNoMatch,
 		}
 		if rule.ExpiresAt != nil {
-			pbRule.ExpiresAt = rule.ExpiresAt.Unix()
+			pbRule.ExpiresAt = timestamppb.New(*rule.ExpiresAt)
 		}
 		pbRules = append(pbRules, pbRule)
 	}
@@ -135,12 +286,13 @@ func (h *RiskAdminHandler) ListRiskRules(ctx context.Context, req *pb_risk.ListR
 // DeleteRiskRule permanently removes a risk rule from the system via gRPC.
 // performs a hard delete and returns an error if the rule doesn't exist.
 func (h *RiskAdminHandler) DeleteRiskRule(ctx context.Context, req *pb_risk.DeleteRiskRuleRequest) (*pb_risk.DeleteRiskRuleResponse, error) {
-	if err := h.riskRepo.DeleteRule(req.RuleId); err != nil {
+	orgID := auth.OrgID(ctx)
+	if err := h.riskRepo.DeleteRule(orgID, req.RuleId); err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to delete risk rule", err)
 		return nil, err
 	}
 
-	h.riskEngine.InvalidateCache()
+	h.riskEngine.InvalidateCache(orgID)
 
 	h.logger.InfoCtx(ctx, "Risk rule deleted", "rule_id", req.RuleId)
 
@@ -148,3 +300,25 @@ func (h *RiskAdminHandler) DeleteRiskRule(ctx context.Context, req *pb_risk.Dele
 		Success: true,
 	}, nil
 }
+
+// ListUsersByRiskLevel returns the users whose latest risk check matches the requested level.
+// used by the notification service to resolve risk-level broadcast segments.
+func (h *RiskAdminHandler) ListUsersByRiskLevel(ctx context.Context, req *pb_risk.ListUsersByRiskLevelRequest) (*pb_risk.ListUsersByRiskLevelResponse, error) {
+	if req.RiskLevel == "" {
+		return &pb_risk.ListUsersByRiskLevelResponse{
+			Success: false,
+			Error:   "risk_level is required",
+		}, nil
+	}
+
+	userIDs, err := h.riskRepo.GetUserIDsByLatestRiskLevel(auth.OrgID(ctx), strings.ToUpper(req.RiskLevel))
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list users by risk level", err, "risk_level", req.RiskLevel)
+		return nil, err
+	}
+
+	return &pb_risk.ListUsersByRiskLevelResponse{
+		UserIds: userIDs,
+		Success: true,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"user-risk-system/pkg/auth"
+	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetCacheStats reports when the caller organization's rule cache was last refreshed and what it holds.
+func (h *RiskAdminHandler) GetCacheStats(ctx context.Context, req *pb_risk.GetCacheStatsRequest) (*pb_risk.GetCacheStatsResponse, error) {
+	stats := h.riskEngine.GetCacheStats(auth.OrgID(ctx))
+
+	resp := &pb_risk.GetCacheStatsResponse{
+		AgeSeconds: stats.Age.Seconds(),
+		TtlSeconds: stats.TTL.Seconds(),
+		RuleCounts: make(map[string]int32, len(stats.RuleCounts)),
+	}
+	if !stats.LastUpdated.IsZero() {
+		resp.LastUpdated = timestamppb.New(stats.LastUpdated)
+	}
+	for category, count := range stats.RuleCounts {
+		resp.RuleCounts[category] = int32(count)
+	}
+	return resp, nil
+}
+
+// InvalidateCache marks the caller organization's rule cache stale so its next risk check reloads rules.
+func (h *RiskAdminHandler) InvalidateCache(ctx context.Context, req *pb_risk.InvalidateCacheRequest) (*pb_risk.InvalidateCacheResponse, error) {
+	h.riskEngine.InvalidateCache(auth.OrgID(ctx))
+
+	h.logger.InfoCtx(ctx, "Risk rule cache invalidated by admin")
+
+	return &pb_risk.InvalidateCacheResponse{Success: true}, nil
+}
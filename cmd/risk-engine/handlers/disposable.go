@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/validator"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// ListDisposableDomains returns the disposable email domains DISPOSABLE_EMAIL rules flag.
+func (h *RiskAdminHandler) ListDisposableDomains(ctx context.Context, req *pb_risk.ListDisposableDomainsRequest) (*pb_risk.ListDisposableDomainsResponse, error) {
+	domains, isDefault, err := h.disposableDomains()
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list disposable domains", err)
+		return nil, err
+	}
+
+	return &pb_risk.ListDisposableDomainsResponse{
+		Domains:   domains.List(),
+		IsDefault: isDefault,
+	}, nil
+}
+
+// UpdateDisposableDomains adds and removes disposable email domains via gRPC.
+// the first update copies the embedded list into the database so edits build on it.
+func (h *RiskAdminHandler) UpdateDisposableDomains(ctx context.Context, req *pb_risk.UpdateDisposableDomainsRequest) (*pb_risk.UpdateDisposableDomainsResponse, error) {
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return nil, invalidArgument(validator.ValidationErrors{{
+			Field:   "add",
+			Code:    validator.CodeRequired,
+			Message: "add or remove must list at least one domain",
+		}})
+	}
+
+	v := validator.New()
+	for i, domain := range req.Add {
+		v.Domain(fmt.Sprintf("add[%d]", i), services.NormalizeDomain(domain))
+	}
+	if !v.IsValid() {
+		return nil, invalidArgument(v.Errors())
+	}
+
+	current, _, err := h.disposableDomains()
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to load disposable domains", err)
+		return nil, err
+	}
+
+	set := make(map[string]struct{})
+	for _, domain := range current.List() {
+		set[domain] = struct{}{}
+	}
+	for _, domain := range req.Add {
+		set[services.NormalizeDomain(domain)] = struct{}{}
+	}
+	for _, domain := range req.Remove {
+		delete(set, services.NormalizeDomain(domain))
+	}
+
+	domains := make([]string, 0, len(set))
+	for domain := range set {
+		domains = append(domains, domain)
+	}
+
+	if err := h.riskRepo.ReplaceDisposableDomains(domains); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to store disposable domains", err)
+		return nil, err
+	}
+
+	// The list is shared by all organizations, reloading any one of them refreshes it for everyone
+	h.riskEngine.InvalidateCache(auth.OrgID(ctx))
+
+	h.logger.InfoCtx(ctx, "Disposable domains updated",
+		"added", len(req.Add),
+		"removed", len(req.Remove),
+		"count", len(domains),
+	)
+
+	return &pb_risk.UpdateDisposableDomainsResponse{
+		Count: int32(len(domains)),
+	}, nil
+}
+
+// disposableDomains loads the stored list, falling back to the embedded one when none is stored.
+func (h *RiskAdminHandler) disposableDomains() (*services.DisposableDomains, bool, error) {
+	stored, err := h.riskRepo.GetDisposableDomains()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(stored) == 0 {
+		return services.NewDisposableDomains(services.DefaultDisposableDomains()), true, nil
+	}
+	return services.NewDisposableDomains(stored), false, nil
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// ListFeatureFlags reports the rollout state of the risk engine's feature flags.
+// with a user ID each flag also says whether that user is bucketed into it.
+func (h *RiskAdminHandler) ListFeatureFlags(ctx context.Context, req *pb_risk.ListFeatureFlagsRequest) (*pb_risk.ListFeatureFlagsResponse, error) {
+	flags := h.riskEngine.FeatureFlags()
+
+	resp := &pb_risk.ListFeatureFlagsResponse{Flags: make([]*pb_risk.FeatureFlag, 0, len(flags))}
+	for _, flag := range flags {
+		resp.Flags = append(resp.Flags, &pb_risk.FeatureFlag{
+			Name:       flag.Name,
+			Percentage: int32(flag.Percentage),
+			Allowlist:  flag.Allowlist,
+			Enabled:    req.UserId != "" && flag.Enabled(req.UserId),
+		})
+	}
+	return resp, nil
+}
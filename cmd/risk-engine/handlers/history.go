@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/validator"
+	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyRiskLevels are the levels GetRiskHistory can filter on.
+var historyRiskLevels = map[string]bool{"MINIMAL": true, "LOW": true, "MEDIUM": true, "HIGH": true, "CRITICAL": true}
+
+// GetRiskHistory returns one page of a user's stored risk checks in the caller's organization.
+// defaults to the 20 most recent checks, continue with next_cursor until it comes back empty.
+func (h *RiskAdminHandler) GetRiskHistory(ctx context.Context, req *pb_risk.GetRiskHistoryRequest) (*pb_risk.GetRiskHistoryResponse, error) {
+	riskLevel := strings.ToUpper(req.RiskLevel)
+	sort := strings.ToLower(req.Sort)
+
+	v := validator.New()
+	v.Required("user_id", req.UserId).
+		Min("limit", float64(req.Limit), 0).
+		Max("limit", float64(req.Limit), services.MaxHistoryLimit)
+	errs := v.Errors()
+	if riskLevel != "" && !historyRiskLevels[riskLevel] {
+		errs = append(errs, validator.ValidationError{Field: "risk_level", Code: validator.CodeInvalidValue, Message: "must be one of MINIMAL, LOW, MEDIUM, HIGH, CRITICAL"})
+	}
+	if sort != "" && sort != "newest" && sort != "oldest" {
+		errs = append(errs, validator.ValidationError{Field: "sort", Code: validator.CodeInvalidValue, Message: "must be newest or oldest"})
+	}
+	if req.Since != nil && req.Until != nil && !req.Since.AsTime().Before(req.Until.AsTime()) {
+		errs = append(errs, validator.ValidationError{Field: "until", Code: validator.CodeInvalidValue, Message: "must be after since"})
+	}
+	if len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+
+	filter := services.RiskHistoryFilter{
+		RiskLevel:   riskLevel,
+		OldestFirst: sort == "oldest",
+		Cursor:      req.Cursor,
+		Limit:       int(req.Limit),
+	}
+	if req.Since != nil {
+		filter.Since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		filter.Until = req.Until.AsTime()
+	}
+
+	results, next, err := h.analytics.GetRiskHistory(ctx, auth.OrgID(ctx), req.UserId, filter)
+	if errors.Is(err, services.ErrInvalidHistoryCursor) {
+		return nil, invalidArgument(validator.ValidationErrors{{Field: "cursor", Code: validator.CodeInvalidValue, Message: err.Error()}})
+	}
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to get risk history", err, "subject_user_id", req.UserId)
+		return nil, status.Error(codes.Internal, "failed to get risk history")
+	}
+
+	resp := &pb_risk.GetRiskHistoryResponse{
+		Checks:     make([]*pb_risk.RiskCheckRecord, 0, len(results)),
+		NextCursor: next,
+	}
+	for _, result := range results {
+		flags := make([]string, len(result.Flags))
+		for i, flag := range result.Flags {
+			flags[i] = flag.Flag
+		}
+		resp.Checks = append(resp.Checks, &pb_risk.RiskCheckRecord{
+			CheckId:      result.CheckID,
+			IsRisky:      result.IsRisky,
+			RiskLevel:    result.RiskLevel,
+			TotalScore:   int32(result.TotalScore),
+			Reason:       result.Reason,
+			Flags:        flags,
+			CheckedAt:    timestamppb.New(result.CheckedAt),
+			SampleWeight: int32(result.SampleWeight),
+		})
+	}
+
+	return resp, nil
+}
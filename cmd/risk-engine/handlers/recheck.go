@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/validator"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	recheckPageSize       = 100             // Users requested from the user service per ListUsers call
+	defaultRecheckLimit   = 100             // Users checked per RecheckUsers call when no limit is given
+	maxRecheckLimit       = 1000            // Upper bound of a RecheckUsers limit
+	recheckDeadlineMargin = 2 * time.Second // Time left before the deadline at which a call stops and returns its progress
+)
+
+// EnableRecheck lets RecheckUsers list users through the user service and check them like CheckRisk does.
+func (h *RiskAdminHandler) EnableRecheck(users pb_user.UserServiceClient, checks *RiskHandler) {
+	h.users = users
+	h.checks = checks
+}
+
+// RecheckUsers re-runs risk checks for the caller's organization users matching the filter.
+// checks are rate limited by RECHECK_RATE_PER_SECOND and stored like any other check, so a user
+// is newly risky when their previous stored check wasn't. A call returns its progress when it
+// reaches limit or nears its deadline, pass next_offset back as offset to resume.
+func (h *RiskAdminHandler) RecheckUsers(ctx context.Context, req *pb_risk.RecheckUsersRequest) (*pb_risk.RecheckUsersResponse, error) {
+	if h.users == nil || h.checks == nil {
+		return nil, status.Error(codes.Unimplemented, "user recheck is not enabled")
+	}
+	errs := validator.New().
+		Domain("email_domain", req.EmailDomain).
+		Min("offset", float64(req.Offset), 0).
+		Errors()
+	if len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxRecheckLimit {
+		limit = defaultRecheckLimit
+	}
+
+	rate := h.settings.Current().RecheckRatePerSecond
+	if rate <= 0 {
+		rate = 1
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	orgID := auth.OrgID(ctx)
+	resp := &pb_risk.RecheckUsersResponse{NextOffset: req.Offset}
+
+	for resp.Checked+resp.Failed < int32(limit) {
+		page, err := h.users.ListUsers(ctx, &pb_user.ListUsersRequest{
+			Limit:  recheckPageSize,
+			Offset: resp.NextOffset,
+		})
+		if err != nil {
+			if resp.Checked+resp.Failed > 0 {
+				h.logger.ErrorCtx(ctx, "Failed to list users for recheck, returning progress", err, "next_offset", resp.NextOffset)
+				break
+			}
+			h.logger.ErrorCtx(ctx, "Failed to list users for recheck", err)
+			return nil, status.Error(codes.Unavailable, "failed to list users")
+		}
+
+		candidates := make([]*pb_user.User, 0, len(page.Users))
+		for _, user := range page.Users {
+			if matchesRecheckFilter(user, req) {
+				candidates = append(candidates, user)
+			}
+		}
+
+		ids := make([]string, len(candidates))
+		for i, user := range candidates {
+			ids[i] = user.Id
+		}
+		previous, err := h.riskRepo.GetLatestRiskyByUser(orgID, ids)
+		if err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to load previous risk checks for recheck", err)
+			return nil, status.Error(codes.Internal, "failed to load previous risk checks")
+		}
+
+		stopped := false
+		for _, user := range page.Users {
+			if resp.Checked+resp.Failed >= int32(limit) || nearDeadline(ctx) {
+				stopped = true
+				break
+			}
+			resp.NextOffset++
+			if !matchesRecheckFilter(user, req) {
+				continue
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+
+			result, err := h.checks.evaluate(ctx, recheckRequest(user))
+			if err != nil {
+				h.logger.ErrorCtx(ctx, "Recheck failed for user", err, "subject_user_id", user.Id)
+				resp.Failed++
+				continue
+			}
+
+			resp.Checked++
+			if result.IsRisky {
+				resp.Risky++
+				if !previous[user.Id] {
+					resp.NewlyRisky++
+					resp.NewlyRiskyUserIds = append(resp.NewlyRiskyUserIds, user.Id)
+				}
+			}
+		}
+
+		if stopped {
+			break
+		}
+		if len(page.Users) < recheckPageSize {
+			resp.Done = true
+			break
+		}
+	}
+
+	h.logger.InfoCtx(ctx, "Users rechecked",
+		"audit", true,
+		"checked", resp.Checked,
+		"risky", resp.Risky,
+		"newly_risky", resp.NewlyRisky,
+		"failed", resp.Failed,
+		"next_offset", resp.NextOffset,
+		"done", resp.Done,
+	)
+
+	return resp, nil
+}
+
+// matchesRecheckFilter returns true if user is selected by the filter of req.
+func matchesRecheckFilter(user *pb_user.User, req *pb_risk.RecheckUsersRequest) bool {
+	if !user.IsActive && !req.IncludeInactive {
+		return false
+	}
+	if req.EmailDomain != "" {
+		at := strings.LastIndex(user.Email, "@")
+		if at < 0 || !strings.EqualFold(user.Email[at+1:], req.EmailDomain) {
+			return false
+		}
+	}
+	if req.Role != "" {
+		for _, role := range user.Roles {
+			if strings.EqualFold(role, req.Role) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// recheckRequest builds the risk check of a listed user, scoped to the user's organization.
+func recheckRequest(user *pb_user.User) *pb_risk.RiskCheckRequest {
+	return &pb_risk.RiskCheckRequest{
+		UserId:    user.Id,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Phone:     user.Phone,
+		OrgId:     user.OrgId,
+	}
+}
+
+// nearDeadline returns true if ctx ends within recheckDeadlineMargin, leaving time to return progress.
+func nearDeadline(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < recheckDeadlineMargin
+}
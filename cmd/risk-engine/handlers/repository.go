@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+)
+
+// RiskRepository is the storage the risk admin handlers need, implemented by repository.RiskRepository.
+type RiskRepository interface {
+	GetActiveRules(orgID string) ([]models.RiskRule, error)
+	CreateRule(rule *models.RiskRule) error
+	CreateRules(rules []*models.RiskRule) error
+	UpdateRule(rule *models.RiskRule) error
+	DeleteRule(orgID, id string) error
+
+	GetUserIDsByLatestRiskLevel(orgID, riskLevel string) ([]string, error)
+	GetLatestRiskyByUser(orgID string, userIDs []string) (map[string]bool, error)
+
+	GetDisposableDomains() ([]string, error)
+	ReplaceDisposableDomains(domains []string) error
+}
+
+var _ RiskRepository = (*repository.RiskRepository)(nil)
@@ -2,40 +2,108 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
+	"user-risk-system/cmd/risk-engine/models"
 	"user-risk-system/cmd/risk-engine/services"
 	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/scontext"
 	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// maxPendingResults bounds analytics writes in flight, streams wait for a slot once it is reached
+// so a fast client can't pile up unbounded goroutines behind a slow database.
+const maxPendingResults = 64
+
 // RiskHandler processes risk evaluation requests via gRPC.
 // coordinates between the risk engine for evaluation and analytics for reporting.
 type RiskHandler struct {
 	pb_risk.UnimplementedRiskServiceServer
-	riskEngine *services.RiskEngine    // Does the actual risk checking
-	analytics  *services.RiskAnalytics // Stores results for reporting
-	logger     *logger.Logger
+	riskEngine   *services.RiskEngine          // Does the actual risk checking
+	analytics    *services.RiskAnalytics       // Stores results for reporting
+	retries      *services.AnalyticsRetryQueue // Retries analytics writes that failed
+	logger       *logger.Logger
+	pendingSlots chan struct{} // Semaphore limiting in-flight analytics writes
 }
 
 // NewRiskHandler creates a new risk handler with the required dependencies.
 func NewRiskHandler(
 	riskEngine *services.RiskEngine,
 	analytics *services.RiskAnalytics,
+	retries *services.AnalyticsRetryQueue,
 	logger *logger.Logger,
 ) *RiskHandler {
 	return &RiskHandler{
-		riskEngine: riskEngine,
-		analytics:  analytics,
-		logger:     logger,
+		riskEngine:   riskEngine,
+		analytics:    analytics,
+		retries:      retries,
+		logger:       logger,
+		pendingSlots: make(chan struct{}, maxPendingResults),
 	}
 }
 
 // CheckRisk evaluates user data against configured risk rules via gRPC.
 func (h *RiskHandler) CheckRisk(ctx context.Context, req *pb_risk.RiskCheckRequest) (*pb_risk.RiskCheckResponse, error) {
-	ctx = context.WithValue(ctx, "user_id", req.UserId)
-	ctx = context.WithValue(ctx, "user_email", req.Email)
+	if errs := validateRiskCheck(req); len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+	if err := scopeToCaller(ctx, req); err != nil {
+		return nil, err
+	}
+	return h.evaluate(ctx, req)
+}
+
+// StreamCheckRisk evaluates a stream of requests, sending one response per request in order.
+// requests are handled one at a time so a slow reader stalls Recv through gRPC flow control,
+// and the stream ends cleanly when the client closes its side or cancels.
+func (h *RiskHandler) StreamCheckRisk(stream pb_risk.RiskService_StreamCheckRiskServer) error {
+	ctx := stream.Context()
+	checked := 0
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			h.logger.InfoCtx(ctx, "Risk check stream completed", "checked", checked)
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				h.logger.InfoCtx(ctx, "Risk check stream cancelled", "checked", checked)
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return err
+		}
+
+		if errs := validateRiskCheck(req); len(errs) > 0 {
+			h.logger.InfoCtx(ctx, "Rejected invalid risk check in stream", "checked", checked)
+			return invalidArgument(errs)
+		}
+		if err := scopeToCaller(ctx, req); err != nil {
+			return err
+		}
 
-	h.logger.InfoCtx(ctx, "Checking risk for user", "user_id", req.UserId, "email", req.Email)
+		// The stream interceptor only sees metadata, each message carries its own user
+		resp, err := h.evaluate(scontext.FromMessage(ctx, req), req)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "risk check failed for user %s: %v", req.UserId, err)
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		checked++
+	}
+}
+
+// evaluate runs a single risk check and schedules its result for analytics, subject to sampling.
+// the user's latest risk level is stored for sampled out checks too.
+// dry runs compute the same result but leave analytics untouched.
+func (h *RiskHandler) evaluate(ctx context.Context, req *pb_risk.RiskCheckRequest) (*pb_risk.RiskCheckResponse, error) {
+	h.logger.InfoCtx(ctx, "Checking risk for user", "user_id", req.UserId, "email", req.Email, "dry_run", req.DryRun)
 
 	result, err := h.riskEngine.CheckRisk(ctx, req)
 	if err != nil {
@@ -43,14 +111,6 @@ func (h *RiskHandler) CheckRisk(ctx context.Context, req *pb_risk.RiskCheckReque
 		return nil, err
 	}
 
-	go func() {
-		analyticsCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err := h.analytics.StoreRiskResult(analyticsCtx, result); err != nil {
-			h.logger.Error("Failed to store risk result for analytics", err)
-		}
-	}()
-
 	flagStrings := make([]string, len(result.Flags))
 	for i, flag := range result.Flags {
 		flagStrings[i] = flag.Flag
@@ -62,11 +122,14 @@ func (h *RiskHandler) CheckRisk(ctx context.Context, req *pb_risk.RiskCheckReque
 		RiskLevel: result.RiskLevel,
 		Reason:    result.Reason,
 		Flags:     flagStrings,
+		DryRun:    req.DryRun,
+		CheckId:   result.CheckID,
 	}
 
 	if result.IsRisky {
 		h.logger.InfoCtx(ctx, "RISK DETECTED for user",
 			"user_id", req.UserId,
+			"check_id", result.CheckID,
 			"risk_level", result.RiskLevel,
 			"reason", result.Reason,
 			"flags", flagStrings,
@@ -75,5 +138,42 @@ func (h *RiskHandler) CheckRisk(ctx context.Context, req *pb_risk.RiskCheckReque
 		h.logger.InfoCtx(ctx, "No risk detected for user", "user_id", req.UserId)
 	}
 
+	// Scheduled last, the write owns result from here on
+	if !req.DryRun {
+		h.analytics.Sample(result)
+		if err := h.storeResultAsync(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+
 	return response, nil
 }
+
+// storeResultAsync persists result for analytics in the background, a failed write is queued for retry.
+// waits for a free slot when maxPendingResults writes are in flight, returning early if ctx ends.
+func (h *RiskHandler) storeResultAsync(ctx context.Context, result *models.RiskCheckResult) error {
+	select {
+	case h.pendingSlots <- struct{}{}:
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	go func() {
+		defer func() { <-h.pendingSlots }()
+
+		analyticsCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := h.analytics.StoreRiskResult(analyticsCtx, result); err != nil {
+			h.retries.Enqueue(result, err)
+		}
+	}()
+	return nil
+}
+
+// WaitForPendingResults blocks until every analytics write in flight has finished or been queued for retry.
+// new checks must no longer arrive, i.e. the gRPC server is stopped.
+func (h *RiskHandler) WaitForPendingResults() {
+	for i := 0; i < cap(h.pendingSlots); i++ {
+		h.pendingSlots <- struct{}{}
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/validator"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// ruleTypes lists the rule types the risk engine evaluates for each category.
+var ruleTypes = map[string][]string{
+	"EMAIL": {"EMAIL_BLACKLIST", "PATTERN_MATCH", "DOMAIN_BLACKLIST", "CONTAINS", "CONFUSABLE_MATCH", "DISPOSABLE_EMAIL"},
+	"NAME":  {"NAME_BLACKLIST", "PATTERN_MATCH", "CONTAINS", "FIRST_NAME_BLACKLIST", "LAST_NAME_BLACKLIST", "CONFUSABLE_MATCH"},
+	"PHONE": {"PHONE_BLACKLIST", "PATTERN_MATCH", "PREFIX_BLACKLIST"},
+}
+
+// validateRule applies the rule constraints enforced by the gateway to fields received over gRPC.
+// the gateway is not the only client, so handlers can't assume requests were already checked.
+func validateRule(name, ruleType, category, value string, score, priority int32, confidence float64) validator.ValidationErrors {
+	v := validator.New()
+	v.Required("name", name).
+		Required("type", ruleType).
+		Required("category", category)
+
+	// DISPOSABLE_EMAIL rules match against the disposable domain list instead of their value
+	if ruleType != "DISPOSABLE_EMAIL" {
+		v.Required("value", value)
+	}
+	// PATTERN_MATCH values are compiled on every check, a broken pattern would never match
+	if ruleType == "PATTERN_MATCH" {
+		v.Regex("value", value)
+	}
+
+	v.Min("score", float64(score), 1).
+		Max("score", float64(score), 1000).
+		Min("priority", float64(priority), 0).
+		Max("priority", float64(priority), 1000)
+
+	if confidence != 0 {
+		v.Min("confidence", confidence, 0).Max("confidence", confidence, 1)
+	}
+
+	// An unknown type would be stored and then fail to evaluate on every check
+	errs := v.Errors()
+	if types, ok := ruleTypes[category]; !ok && category != "" {
+		errs = append(errs, validator.ValidationError{Field: "category", Code: validator.CodeInvalidValue, Message: "must be one of EMAIL, NAME, PHONE"})
+	} else if ok && ruleType != "" && !slices.Contains(types, ruleType) {
+		errs = append(errs, validator.ValidationError{Field: "type", Code: validator.CodeInvalidValue, Message: fmt.Sprintf("must be one of %s for %s rules", strings.Join(types, ", "), category)})
+	}
+	return errs
+}
+
+// validateExpiry checks expires_in_days is 0 (permanent) or within the configured maximum.
+func (h *RiskAdminHandler) validateExpiry(days int32) validator.ValidationErrors {
+	return validator.New().
+		Min("expires_in_days", float64(days), 0).
+		Max("expires_in_days", float64(days), float64(h.settings.Current().RuleMaxExpiresInDays)).
+		Errors()
+}
+
+// validateExamples runs rule against its optional examples, so a pattern that can't match what its
+// author intended is rejected when it is written instead of silently never firing.
+func validateExamples(engine RiskEngineService, rule *models.RiskRule) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+	check := func(field, example string, want bool) {
+		if example == "" {
+			return
+		}
+		matched, err := engine.MatchesInput(*rule, example)
+		switch {
+		case err != nil:
+			errs = append(errs, validator.ValidationError{Field: "value", Code: validator.CodeInvalidValue, Message: err.Error()})
+		case matched && !want:
+			errs = append(errs, validator.ValidationError{Field: field, Code: validator.CodeInvalidValue, Message: "rule matches this example but should not"})
+		case !matched && want:
+			errs = append(errs, validator.ValidationError{Field: field, Code: validator.CodeInvalidValue, Message: "rule does not match this example"})
+		}
+	}
+
+	check("example_match", rule.ExampleMatch, true)
+	if len(errs) == 0 {
+		check("example_no_match", rule.ExampleNoMatch, false)
+	}
+	return errs
+}
+
+// validateRiskCheck checks a risk check request has the user fields rules are evaluated against.
+func validateRiskCheck(req *pb_risk.RiskCheckRequest) validator.ValidationErrors {
+	v := validator.New()
+	v.Required("user_id", req.UserId).
+		Required("email", req.Email).
+		Email("email", req.Email).
+		Required("first_name", req.FirstName).
+		Required("last_name", req.LastName).
+		Phone("phone", req.Phone)
+	return v.Errors()
+}
+
+// scopeToCaller sets the organization of a risk check from the authenticated caller.
+// service tokens may name the organization of the checked user, users and admins only check their own.
+func scopeToCaller(ctx context.Context, req *pb_risk.RiskCheckRequest) error {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+	if claims.HasAnyRole(auth.RoleService) {
+		req.OrgId = auth.OrgOrDefault(req.OrgId)
+		return nil
+	}
+
+	orgID := auth.OrgID(ctx)
+	if req.OrgId != "" && req.OrgId != orgID {
+		return errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+	req.OrgId = orgID
+	return nil
+}
+
+// invalidArgument converts validation errors into an InvalidArgument gRPC status error.
+func invalidArgument(errs validator.ValidationErrors) error {
+	return errors.ErrValidationFailed.WithMessage("Validation failed: " + errs.Error()).GRPCStatus().Err()
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -12,9 +17,19 @@ import (
 	"user-risk-system/cmd/risk-engine/models"
 	"user-risk-system/cmd/risk-engine/repository"
 	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/cache"
+	"user-risk-system/pkg/client"
 	"user-risk-system/pkg/config"
 	"user-risk-system/pkg/health"
+	"user-risk-system/pkg/lifecycle"
 	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/migrate"
+	events "user-risk-system/pkg/models"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/scheduler"
+	"user-risk-system/pkg/scontext"
 	"user-risk-system/pkg/utils"
 	pb_risk "user-risk-system/proto/risk"
 )
@@ -27,59 +42,179 @@ type riskConfig struct {
 
 // main initializes and starts the risk engine service with gRPC endpoints.
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Require("RISK_DATABASE_URL", "RABBITMQ_URL", "JWT_SECRET"))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	rcfg := &riskConfig{
 		DatabaseURL: cfg.RiskDatabaseURL,
-		Port:        ":" + cfg.Port,
+		Port:        ":" + cfg.Ports.RiskGRPC,
 	}
 
 	// log
 	logConfig := logger.LogConfig{
-		Level:       "info",
+		Level:       cfg.LogLevel,
 		Format:      "json",
 		ServiceName: cfg.ServiceName,
 		Environment: cfg.Environment,
+		MaskPII:     cfg.LogMaskPII,
 	}
 
 	rl := logger.New(logConfig)
+	cfg.LogStartupReport(rl)
+	cfg.Settings().WatchSignals(context.Background(), rl)
+
+	// Resources registered with the lifecycle are closed in reverse order when main returns
+	lc := lifecycle.New(rl)
+	defer lc.Shutdown()
 
 	// databse
-	db, err := utils.SetupDatabase(rcfg.DatabaseURL, &gorm.Config{}, cfg, rl)
-	if err != nil {
+	var db *gorm.DB
+	if err := lc.Connect("database", func() (err error) {
+		db, err = utils.SetupDatabase(rcfg.DatabaseURL, &gorm.Config{}, cfg, rl)
+		return err
+	}); err != nil {
 		rl.Fatalf("Failed to setup database: %v", err)
 	}
 
-	// Run auto-migration for user models
-	rl.Info("Running risk engine database migration...")
-	if err := models.AutoMigrate(db); err != nil {
-		rl.Fatalf("Failed to run auto-migration: %v", err)
-	}
-	rl.Info("Risk engine database migration completed successfully")
-
 	sqlDB, err := db.DB()
 	if err != nil {
 		rl.Fatalf("Failed to get underlying SQL DB: %v", err)
 	}
-	defer sqlDB.Close()
+	lc.OnShutdown("database", sqlDB.Close)
+
+	// Schema migrations, `risk-engine migrate [up|down [steps]|status]` runs them and exits
+	migrator := migrate.New(db, "risk-engine", models.Migrations, rl)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrator.RunCommand(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			rl.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+	if cfg.DatabaseAutoMigrate {
+		rl.Warn("Running gorm auto-migration instead of versioned migrations, intended for development only")
+		if err := models.AutoMigrate(db); err != nil {
+			rl.Fatalf("Failed to run auto-migration: %v", err)
+		}
+	} else {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			rl.Fatalf("Failed to run migrations: %v", err)
+		}
+		rl.Info("Risk engine database migrations completed successfully", "applied", applied)
+	}
+
+	// Optional read replica for analytics queries, writes stay on the primary
+	var replicaDB *gorm.DB
+	if cfg.RiskReplicaURL != "" {
+		if err := lc.Connect("read replica", func() (err error) {
+			replicaDB, err = utils.SetupDatabase(cfg.RiskReplicaURL, &gorm.Config{}, cfg, rl)
+			return err
+		}); err != nil {
+			rl.Fatalf("Failed to setup read replica: %v", err)
+		}
+		replicaSQL, err := replicaDB.DB()
+		if err != nil {
+			rl.Fatalf("Failed to get underlying replica SQL DB: %v", err)
+		}
+		lc.OnShutdown("read replica", replicaSQL.Close)
+	}
+
+	// Optionally wait for RabbitMQ when required, risk level change events are relayed to it from the outbox
+	if cfg.MessagingRequired {
+		if err := health.WaitForDependencies(context.Background(), cfg.StartupWaitTimeout, cfg.StartupWaitInterval, rl,
+			health.RabbitMQDependency(cfg.RabbitMQURL),
+		); err != nil {
+			rl.Fatalf("Dependencies not ready: %v", err)
+		}
+	}
+
+	// RabbitMQ connection, retried in the background unless MESSAGING_REQUIRED is set
+	rabbitMQ := messaging.NewResilient(cfg.RabbitMQURL, messaging.OfflineMode(cfg.MessagingOfflineMode), cfg.MessagingReconnectInterval, rl)
+	if err := lc.Connect("rabbitmq", rabbitMQ.Connect); err != nil {
+		if cfg.MessagingRequired {
+			rl.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		}
+		rl.Warn("Starting without RabbitMQ, connecting in the background", "offline_mode", cfg.MessagingOfflineMode)
+	}
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	go rabbitMQ.Run(brokerCtx)
+	lc.OnShutdown("rabbitmq", func() error {
+		stopBroker()
+		return rabbitMQ.Close()
+	})
+
+	if err := rabbitMQ.DeclareQueue(events.EventRiskLevelChanged); err != nil {
+		rl.Fatalf("Failed to declare queue %s: %v", events.EventRiskLevelChanged, err)
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	go outbox.NewRelay(db, rabbitMQ.Strict(), rl, cfg.OutboxPollInterval).Run(relayCtx)
+	lc.OnShutdown("outbox relay", func() error {
+		stopRelay()
+		return nil
+	})
+
+	// Periodic jobs, singletons run on one replica at a time
+	jobs := scheduler.New(scheduler.NewDBLocker(db, rl), rl)
+	if err := jobs.Register(outbox.PurgeJob(db, cfg.OutboxRetention, rl)); err != nil {
+		rl.Fatalf("Failed to register job: %v", err)
+	}
+	jobs.Start(context.Background())
+	lc.OnShutdown("scheduler", func() error {
+		jobs.Stop()
+		return nil
+	})
 
 	rl.Info("Risk engine configuration",
-		"database_url", utils.MaskPassword(rcfg.DatabaseURL),
+		"database_url", cfg.Redact().RiskDatabaseURL,
+		"replica_url", cfg.Redact().RiskReplicaURL,
 		"port", rcfg.Port)
 
 	// Initialize repositories
 	riskRepo := repository.NewRiskRepository(db)
 
 	// Initialize services
-	riskEngine := services.NewRiskEngine(riskRepo, rl)
-	riskAnalytics := services.NewRiskAnalytics(db, rl)
+	riskEngine := services.NewRiskEngine(riskRepo, cfg.Settings(), cfg.RiskFlagFormat, rl)
+	if cfg.MXCheckEnabled {
+		riskEngine.EnableMXCheck(services.NewMXChecker(net.DefaultResolver, cfg.MXCheckTimeout, cfg.MXCacheTTL, rl), cfg.NoMXScore)
+		rl.Info("MX deliverability check enabled", "timeout", cfg.MXCheckTimeout.String(), "cache_ttl", cfg.MXCacheTTL.String())
+	}
+	if cfg.RedisURL != "" {
+		var shared cache.Cache
+		if err := lc.Connect("redis", func() (err error) {
+			shared, err = cache.New(cfg.RedisURL)
+			return err
+		}); err != nil {
+			rl.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		lc.OnShutdown("redis", shared.Close)
+		if err := riskEngine.EnableSharedInvalidation(context.Background(), shared); err != nil {
+			rl.Fatalf("Failed to subscribe to rule cache invalidations: %v", err)
+		}
+		rl.Info("Rule cache invalidations shared through Redis")
+	}
+	riskAnalytics := services.NewRiskAnalytics(db, replicaDB, cfg.Settings(), rl)
 
 	// Initialize handlers
-	riskHandler := handlers.NewRiskHandler(riskEngine, riskAnalytics, rl)
-	riskAdminHandler := handlers.NewRiskAdminHandler(riskRepo, rl, riskEngine)
+	analyticsRetries := services.NewAnalyticsRetryQueue(riskAnalytics, rl)
+	retryCtx, stopRetries := context.WithCancel(context.Background())
+	go analyticsRetries.Run(retryCtx)
+
+	riskHandler := handlers.NewRiskHandler(riskEngine, riskAnalytics, analyticsRetries, rl)
+	riskAdminHandler := handlers.NewRiskAdminHandler(riskRepo, rl, riskEngine, cfg.Settings(), riskAnalytics)
+
+	// RecheckUsers lists users through the user service, forwarding the admin's JWT
+	var userClient *client.UserClient
+	if err := lc.Connect("user-service", func() (err error) {
+		userClient, err = client.NewUserClient(cfg.UserServiceURL, client.Config{})
+		return err
+	}); err != nil {
+		rl.Fatalf("Failed to connect to user service at %s: %v", cfg.UserServiceURL, err)
+	}
+	lc.OnShutdown("user-service client", userClient.Close)
+	riskAdminHandler.EnableRecheck(userClient, riskHandler)
 
 	// Create gRPC server
 	lis, err := net.Listen("tcp", rcfg.Port)
@@ -87,7 +222,21 @@ func main() {
 		rl.Fatalf("Failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	// Admin methods need an admin token, it also carries the organization they act on.
+	// risk checks take the organization from the caller's token, only services may name another one.
+	protectedMethods := make(map[string][]auth.UserRole)
+	for _, method := range pb_risk.RiskAdminService_ServiceDesc.Methods {
+		protectedMethods["/"+pb_risk.RiskAdminService_ServiceDesc.ServiceName+"/"+method.MethodName] = []auth.UserRole{auth.RoleAdmin}
+	}
+	protectedMethods["/risk.RiskService/CheckRisk"] = auth.AllRoles
+	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer)
+	authMiddleware := auth.NewAuthMiddleware(jwtManager)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), logger.UnaryServerInterceptor(rl), authMiddleware.GRPCProtectMethods(protectedMethods)),
+		grpc.ChainStreamInterceptor(scontext.StreamServerInterceptor(), authMiddleware.GRPCProtectStreams(map[string][]auth.UserRole{
+			"/risk.RiskService/StreamCheckRisk": auth.AllRoles,
+		})),
+	)
 
 	// Register services
 	pb_risk.RegisterRiskServiceServer(s, riskHandler)
@@ -101,10 +250,40 @@ func main() {
 			{Name: "risk.RiskAdminService", Status: grpc_health_v1.HealthCheckResponse_SERVING},
 		},
 	}
-	health.RegisterHealthService(s, healthConfig)
+	healthServer := health.RegisterHealthService(s, healthConfig)
 
-	rl.Info("Risk service starting", "port", rcfg.Port)
-	if err := s.Serve(lis); err != nil {
-		rl.Fatalf("Failed to serve: %v", err)
-	}
+	// An empty or stale rule cache makes every user look low risk, report it instead of serving silently
+	go healthServer.Monitor(context.Background(), "rule_cache", cfg.RuleCacheHealthInterval, rl,
+		func(ctx context.Context) error { return riskEngine.CheckCacheHealth() },
+		"risk.RiskService",
+	)
+
+	// Writes still in flight may fail and land in the retry queue, so wait for them before the last flush.
+	// registered before the server, which is closed first so no new checks arrive meanwhile.
+	lc.OnShutdown("analytics writes", func() error {
+		riskHandler.WaitForPendingResults()
+		stopRetries()
+		flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		analyticsRetries.Flush(flushCtx)
+		rl.Info("Analytics writes flushed", "analytics_writes_dropped", analyticsRetries.Dropped())
+		return nil
+	})
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			rl.Fatalf("Failed to serve: %v", err)
+		}
+	}()
+	lc.OnShutdown("grpc server", func() error {
+		s.GracefulStop()
+		return nil
+	})
+	lc.Started("port", rcfg.Port)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+
+	rl.Warn("Shutting down risk service...")
 }
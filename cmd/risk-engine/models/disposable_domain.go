@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DisposableDomain is an admin-managed disposable email domain used by DISPOSABLE_EMAIL rules.
+// while the table is empty the engine falls back to its embedded list.
+type DisposableDomain struct {
+	Domain    string    `json:"domain" gorm:"primaryKey;type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (DisposableDomain) TableName() string {
+	return "disposable_domains"
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/testutil"
+)
+
+// seedChecks stores flagged risk checks for several organizations and users over the last days
+// and analyzes the tables, so the planner chooses indexes as it would on real data.
+func seedChecks(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	now := time.Now().UTC()
+
+	var results []models.RiskCheckResult
+	var flags []models.RiskCheckFlag
+	for i := 0; i < 2000; i++ {
+		checkID := fmt.Sprintf("check-%d", i)
+		results = append(results, models.RiskCheckResult{
+			CheckID:   checkID,
+			UserID:    fmt.Sprintf("user-%d", i%200),
+			OrgID:     fmt.Sprintf("org-%d", i%10),
+			IsRisky:   i%3 == 0,
+			RiskLevel: "LOW",
+			CheckedAt: now.Add(-time.Duration(i) * time.Hour),
+		})
+		flags = append(flags, models.RiskCheckFlag{CheckID: checkID, Flag: fmt.Sprintf("EMAIL_CONTAINS_%d", i%20)})
+	}
+	if err := db.CreateInBatches(results, 200).Error; err != nil {
+		t.Fatalf("failed to seed results: %v", err)
+	}
+	if err := db.CreateInBatches(flags, 200).Error; err != nil {
+		t.Fatalf("failed to seed flags: %v", err)
+	}
+	if err := db.Exec("ANALYZE").Error; err != nil {
+		t.Fatalf("ANALYZE: %v", err)
+	}
+}
+
+// queryPlan returns the SQLite query plan of query, one step per line.
+func queryPlan(t *testing.T, db *gorm.DB, query string, args ...interface{}) string {
+	t.Helper()
+	var steps []struct {
+		Detail string `gorm:"column:detail"`
+	}
+	if err := db.Raw("EXPLAIN QUERY PLAN "+query, args...).Scan(&steps).Error; err != nil {
+		t.Fatalf("EXPLAIN QUERY PLAN: %v", err)
+	}
+	details := make([]string, len(steps))
+	for i, step := range steps {
+		details[i] = step.Detail
+	}
+	return strings.Join(details, "\n")
+}
+
+func TestAnalyticsQueriesUseIndexes(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	seedChecks(t, db)
+	since := time.Now().UTC().AddDate(0, 0, -7)
+
+	tests := []struct {
+		name    string
+		query   string
+		args    []interface{}
+		indexes []string
+	}{
+		{
+			name:    "stats and trend by organization and date",
+			query:   "SELECT DATE(checked_at) AS date, COUNT(*) FROM risk_check_results WHERE org_id = ? AND checked_at >= ? GROUP BY DATE(checked_at)",
+			args:    []interface{}{"org-1", since},
+			indexes: []string{"idx_risk_check_results_org_checked"},
+		},
+		{
+			name:    "top flags joined on check_id",
+			query:   "SELECT rcf.flag, SUM(rcr.sample_weight) FROM risk_check_flags rcf JOIN risk_check_results rcr ON rcf.check_id = rcr.check_id WHERE rcr.org_id = ? AND rcr.checked_at >= ? GROUP BY rcf.flag",
+			args:    []interface{}{"org-1", since},
+			indexes: []string{"idx_risk_check_results_org_checked", "idx_risk_check_flags_check_id"},
+		},
+		{
+			name:    "user history ordered by check time",
+			query:   "SELECT * FROM risk_check_results WHERE user_id = ? AND checked_at >= ? ORDER BY checked_at DESC, id DESC LIMIT 20",
+			args:    []interface{}{"user-1", since},
+			indexes: []string{"idx_risk_check_results_user_checked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := queryPlan(t, db, tt.query, tt.args...)
+			for _, index := range tt.indexes {
+				if !strings.Contains(plan, index) {
+					t.Errorf("plan does not use %s:\n%s", index, plan)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/pkg/migrate"
+)
+
+// Migrations are the versioned schema changes of the risk database, applied in order.
+// the initial migration adopts the schema AutoMigrate created so far, later changes get their own.
+// each one works on frozen snapshots of the tables it touches, so it keeps creating the same
+// schema when the models change.
+var Migrations = []migrate.Migration{
+	{
+		ID: "0001_initial",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&riskRuleV1{},
+				&riskCheckResultV1{},
+				&riskCheckFlagV1{},
+				&riskCheckRuleMatchV1{},
+				&disposableDomainV1{},
+				&outboxMessageV1{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&outboxMessageV1{},
+				&disposableDomainV1{},
+				&riskCheckRuleMatchV1{},
+				&riskCheckFlagV1{},
+				&riskCheckResultV1{},
+				&riskRuleV1{},
+			)
+		},
+	},
+	{
+		// Stats and trends filter results by org_id and checked_at, flags and rule matches join on check_id
+		ID: "0002_analytics_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, index := range analyticsIndexes {
+				if err := tx.Exec("CREATE INDEX IF NOT EXISTS " + index.name + " ON " + index.table + " (" + index.columns + ")").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, index := range analyticsIndexes {
+				if err := tx.Exec("DROP INDEX IF EXISTS " + index.name).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// Latest risk level per user, kept also for checks sampled out of analytics
+		ID: "0003_user_risk_levels",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&userRiskLevelV3{}); err != nil {
+				return err
+			}
+			return tx.Exec(`INSERT INTO user_risk_levels (org_id, user_id, check_id, risk_level, is_risky, checked_at)
+				SELECT r.org_id, r.user_id, r.check_id, COALESCE(r.risk_level, ''), r.is_risky, r.checked_at
+				FROM risk_check_results r
+				JOIN (SELECT org_id, user_id, MAX(checked_at) AS checked_at FROM risk_check_results GROUP BY org_id, user_id) latest
+				ON latest.org_id = r.org_id AND latest.user_id = r.user_id AND latest.checked_at = r.checked_at
+				ON CONFLICT DO NOTHING`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userRiskLevelV3{})
+		},
+	},
+}
+
+// analyticsIndexes are the indexes analytics queries rely on, created by 0002_analytics_indexes.
+var analyticsIndexes = []struct {
+	name    string
+	table   string
+	columns string
+}{
+	{"idx_risk_check_results_org_checked", "risk_check_results", "org_id, checked_at"},
+	{"idx_risk_check_results_user_checked", "risk_check_results", "user_id, checked_at"},
+	{"idx_risk_check_flags_check_id", "risk_check_flags", "check_id"},
+	{"idx_risk_check_rule_matches_check_id", "risk_check_rule_matches", "check_id"},
+}
+
+// riskRuleV1 is the risk_rules table as created by 0001_initial.
+type riskRuleV1 struct {
+	ID             string  `gorm:"primaryKey;type:varchar(255)"`
+	OrgID          string  `gorm:"type:varchar(255);not null;default:'default';index"`
+	Name           string  `gorm:"type:varchar(255);not null"`
+	Type           string  `gorm:"type:varchar(100);not null"`
+	Category       string  `gorm:"type:varchar(100);not null"`
+	Value          string  `gorm:"type:text;not null"`
+	Score          int     `gorm:"not null"`
+	IsActive       bool    `gorm:"default:true"`
+	Source         string  `gorm:"type:varchar(100);not null"`
+	Confidence     float64 `gorm:"type:decimal(3,2);default:1.0"`
+	Priority       int     `gorm:"default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ExpiresAt      *time.Time `gorm:"index"`
+	ExampleMatch   string     `gorm:"type:text"`
+	ExampleNoMatch string     `gorm:"type:text"`
+}
+
+func (riskRuleV1) TableName() string {
+	return "risk_rules"
+}
+
+// riskCheckResultV1 is the risk_check_results table as created by 0001_initial.
+type riskCheckResultV1 struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	CheckID      string    `gorm:"uniqueIndex;type:varchar(255);not null"`
+	UserID       string    `gorm:"type:varchar(255);not null;index"`
+	OrgID        string    `gorm:"type:varchar(255);not null;default:'default';index"`
+	IsRisky      bool      `gorm:"default:false;index"`
+	RiskLevel    string    `gorm:"type:varchar(50)"`
+	TotalScore   int       `gorm:"default:0"`
+	Reason       string    `gorm:"type:text"`
+	CheckedAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	SampleWeight int                    `gorm:"not null;default:1"`
+	Flags        []riskCheckFlagV1      `gorm:"foreignKey:CheckID;references:CheckID"`
+	MatchedRules []riskCheckRuleMatchV1 `gorm:"foreignKey:CheckID;references:CheckID"`
+}
+
+func (riskCheckResultV1) TableName() string {
+	return "risk_check_results"
+}
+
+// riskCheckFlagV1 is the risk_check_flags table as created by 0001_initial.
+type riskCheckFlagV1 struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	CheckID   string `gorm:"type:varchar(255);not null"`
+	RuleID    string `gorm:"type:varchar(255);index"`
+	Flag      string `gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time
+}
+
+func (riskCheckFlagV1) TableName() string {
+	return "risk_check_flags"
+}
+
+// riskCheckRuleMatchV1 is the risk_check_rule_matches table as created by 0001_initial.
+type riskCheckRuleMatchV1 struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	CheckID    string `gorm:"type:varchar(255);not null"`
+	RuleID     string `gorm:"type:varchar(255);not null"`
+	RuleName   string `gorm:"type:varchar(255);not null"`
+	ScoreAdded int    `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+func (riskCheckRuleMatchV1) TableName() string {
+	return "risk_check_rule_matches"
+}
+
+// disposableDomainV1 is the disposable_domains table as created by 0001_initial.
+type disposableDomainV1 struct {
+	Domain    string `gorm:"primaryKey;type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+func (disposableDomainV1) TableName() string {
+	return "disposable_domains"
+}
+
+// outboxMessageV1 is the outbox table as created by 0001_initial.
+type outboxMessageV1 struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	Queue     string     `gorm:"type:varchar(255);not null"`
+	Payload   []byte     `gorm:"not null"`
+	Attempts  int        `gorm:"default:0"`
+	LastError string     `gorm:"type:text"`
+	CreatedAt time.Time  `gorm:"index"`
+	SentAt    *time.Time `gorm:"index"`
+}
+
+func (outboxMessageV1) TableName() string {
+	return "outbox"
+}
+
+// userRiskLevelV3 is the user_risk_levels table as created by 0003_user_risk_levels.
+type userRiskLevelV3 struct {
+	OrgID     string    `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string    `gorm:"primaryKey;type:varchar(255)"`
+	CheckID   string    `gorm:"type:varchar(255);not null"`
+	RiskLevel string    `gorm:"type:varchar(50);not null;index"`
+	IsRisky   bool      `gorm:"not null;default:false"`
+	CheckedAt time.Time `gorm:"not null"`
+}
+
+func (userRiskLevelV3) TableName() string {
+	return "user_risk_levels"
+}
@@ -4,12 +4,15 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"user-risk-system/pkg/outbox"
 )
 
 // RiskRule represents a configurable rule for risk evaluation.
 // Rules define patterns, scores, and conditions for identifying risky user data.
 type RiskRule struct {
 	ID         string     `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	OrgID      string     `json:"org_id" gorm:"type:varchar(255);not null;default:'default';index"` // Organization the rule applies to
 	Name       string     `json:"name" gorm:"type:varchar(255);not null"`
 	Type       string     `json:"type" gorm:"type:varchar(100);not null"`     // EMAIL_BLACKLIST, NAME_BLACKLIST, PATTERN_MATCH
 	Category   string     `json:"category" gorm:"type:varchar(100);not null"` // EMAIL, NAME, PHONE
@@ -18,9 +21,13 @@ type RiskRule struct {
 	IsActive   bool       `json:"is_active" gorm:"default:true"`
 	Source     string     `json:"source" gorm:"type:varchar(100);not null"`        // MANUAL, EXTERNAL_API, ML_MODEL
 	Confidence float64    `json:"confidence" gorm:"type:decimal(3,2);default:1.0"` // 0.0 to 1.0
+	Priority   int        `json:"priority" gorm:"default:0"`                       // Higher priority rules are evaluated first within a category
 	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 	ExpiresAt  *time.Time `json:"expires_at" gorm:"index"` // For temporary rules
+
+	ExampleMatch   string `json:"example_match" gorm:"type:text"`    // Optional input the rule must match, checked on create and update
+	ExampleNoMatch string `json:"example_no_match" gorm:"type:text"` // Optional input the rule must not match
 }
 
 func (RiskRule) TableName() string {
@@ -32,15 +39,18 @@ func (RiskRule) TableName() string {
 type RiskCheckResult struct {
 	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	CheckID    string    `json:"check_id" gorm:"uniqueIndex;type:varchar(255);not null"`
-	UserID     string    `json:"user_id" gorm:"type:varchar(255);not null;index"`
+	UserID     string    `json:"user_id" gorm:"type:varchar(255);not null;index;index:idx_risk_check_results_user_checked,priority:1"`
+	OrgID      string    `json:"org_id" gorm:"type:varchar(255);not null;default:'default';index;index:idx_risk_check_results_org_checked,priority:1"` // Organization whose rules were applied
 	IsRisky    bool      `json:"is_risky" gorm:"default:false;index"`
 	RiskLevel  string    `json:"risk_level" gorm:"type:varchar(50)"` // LOW, MEDIUM, HIGH, CRITICAL
 	TotalScore int       `json:"total_score" gorm:"default:0"`
 	Reason     string    `json:"reason" gorm:"type:text"`
-	CheckedAt  time.Time `json:"checked_at" gorm:"index"`
+	CheckedAt  time.Time `json:"checked_at" gorm:"index;index:idx_risk_check_results_user_checked,priority:2;index:idx_risk_check_results_org_checked,priority:2"` // With user_id backs history paging, with org_id stats and trends
 	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SampleWeight int                  `json:"sample_weight" gorm:"not null;default:1"` // Checks this result stands for, N when kept as 1 in N sampled non-risky checks
+	SampledOut   bool                 `json:"-" gorm:"-"`                              // Not stored, only the user's latest risk level is kept
 	Flags        []RiskCheckFlag      `json:"flags" gorm:"foreignKey:CheckID;references:CheckID"`
 	MatchedRules []RiskCheckRuleMatch `json:"matched_rules" gorm:"foreignKey:CheckID;references:CheckID"`
 }
@@ -54,6 +64,7 @@ func (RiskCheckResult) TableName() string {
 type RiskCheckFlag struct {
 	ID      uint   `json:"id" gorm:"primaryKey;autoIncrement"`
 	CheckID string `json:"check_id" gorm:"type:varchar(255);not null;index"`
+	RuleID  string `json:"rule_id" gorm:"type:varchar(255);index"` // Rule that produced the flag, empty for checks stored before it was recorded
 	Flag    string `json:"flag" gorm:"type:varchar(255);not null"`
 
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -86,5 +97,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&RiskCheckResult{},
 		&RiskCheckFlag{},
 		&RiskCheckRuleMatch{},
+		&DisposableDomain{},
+		&UserRiskLevel{},
+		&outbox.Message{},
 	)
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserRiskLevel is the outcome of a user's latest risk check in an organization.
+// it is kept for every check, also when ANALYTICS_SAMPLE_RATE leaves the result itself unstored,
+// so level changes and latest-level queries never compare against an older check.
+type UserRiskLevel struct {
+	OrgID     string    `json:"org_id" gorm:"primaryKey;type:varchar(255)"`
+	UserID    string    `json:"user_id" gorm:"primaryKey;type:varchar(255)"`
+	CheckID   string    `json:"check_id" gorm:"type:varchar(255);not null"`
+	RiskLevel string    `json:"risk_level" gorm:"type:varchar(50);not null;index"`
+	IsRisky   bool      `json:"is_risky" gorm:"not null;default:false"`
+	CheckedAt time.Time `json:"checked_at" gorm:"not null"`
+}
+
+func (UserRiskLevel) TableName() string {
+	return "user_risk_levels"
+}
@@ -18,13 +18,13 @@ func NewRiskRepository(db *gorm.DB) *RiskRepository {
 	return &RiskRepository{db: db}
 }
 
-// GetActiveRules retrieves all active, non-expired risk rules ordered by score.
+// GetActiveRules retrieves an organization's active, non-expired risk rules ordered by score.
 // filters out inactive rules and those past their expiration date.
-func (r *RiskRepository) GetActiveRules() ([]models.RiskRule, error) {
+func (r *RiskRepository) GetActiveRules(orgID string) ([]models.RiskRule, error) {
 	var rules []models.RiskRule
 
-	result := r.db.Where("is_active = ? AND (expires_at IS NULL OR expires_at > ?)",
-		true, time.Now()).
+	result := r.db.Where("org_id = ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)",
+		orgID, true, time.Now()).
 		Order("score DESC").
 		Find(&rules)
 
@@ -35,14 +35,14 @@ func (r *RiskRepository) GetActiveRules() ([]models.RiskRule, error) {
 	return rules, nil
 }
 
-// GetRulesByCategory retrieves active risk rules for a specific category.
-// Categories include EMAIL, NAME, PHONE for targeted rule evaluation.
-func (r *RiskRepository) GetRulesByCategory(category string) ([]models.RiskRule, error) {
+// GetRulesByCategory retrieves an organization's active risk rules for a category in evaluation order.
+// Categories include EMAIL, NAME, PHONE, rules are ordered by priority and then score.
+func (r *RiskRepository) GetRulesByCategory(orgID, category string) ([]models.RiskRule, error) {
 	var rules []models.RiskRule
 
-	result := r.db.Where("category = ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)",
-		category, true, time.Now()).
-		Order("score DESC").
+	result := r.db.Where("org_id = ? AND category = ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)",
+		orgID, category, true, time.Now()).
+		Order("priority DESC, score DESC").
 		Find(&rules)
 
 	if result.Error != nil {
@@ -52,6 +52,22 @@ func (r *RiskRepository) GetRulesByCategory(category string) ([]models.RiskRule,
 	return rules, nil
 }
 
+// CountActiveRules counts an organization's active, non-expired risk rules in the given categories.
+func (r *RiskRepository) CountActiveRules(orgID string, categories []string) (int64, error) {
+	var count int64
+
+	result := r.db.Model(&models.RiskRule{}).
+		Where("org_id = ? AND category IN ? AND is_active = ? AND (expires_at IS NULL OR expires_at > ?)",
+			orgID, categories, true, time.Now()).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count risk rules: %w", result.Error)
+	}
+
+	return count, nil
+}
+
 // CreateRule inserts a new risk rule into the database.
 // automatically sets creation and update timestamps.
 func (r *RiskRepository) CreateRule(rule *models.RiskRule) error {
@@ -66,24 +82,48 @@ func (r *RiskRepository) CreateRule(rule *models.RiskRule) error {
 	return nil
 }
 
-// UpdateRule modifies an existing risk rule in the database.
-// updates the modification timestamp automatically.
+// CreateRules inserts several risk rules in a single transaction.
+// either every rule is stored or, on the first failure, none are.
+func (r *RiskRepository) CreateRules(rules []*models.RiskRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for i, rule := range rules {
+			rule.CreatedAt = now
+			rule.UpdatedAt = now
+			if err := tx.Create(rule).Error; err != nil {
+				return fmt.Errorf("failed to create risk rule %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateRule modifies an existing risk rule of rule.OrgID in the database.
+// updates the modification timestamp automatically, a rule of another organization is reported as not found.
 func (r *RiskRepository) UpdateRule(rule *models.RiskRule) error {
 	rule.UpdatedAt = time.Now()
 
-	result := r.db.Save(rule)
+	result := r.db.Model(rule).
+		Where("org_id = ?", rule.OrgID).
+		Select("*").
+		Omit("CreatedAt").
+		Updates(rule)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update risk rule: %w", result.Error)
 	}
 
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("risk rule not found: %s", rule.ID)
+	}
+
 	return nil
 }
 
-// GetRuleByID retrieves a specific risk rule by its unique identifier.
-func (r *RiskRepository) GetRuleByID(id string) (*models.RiskRule, error) {
+// GetRuleByID retrieves one of an organization's risk rules by its unique identifier.
+func (r *RiskRepository) GetRuleByID(orgID, id string) (*models.RiskRule, error) {
 	var rule models.RiskRule
 
-	result := r.db.Where("id = ?", id).First(&rule)
+	result := r.db.Where("id = ? AND org_id = ?", id, orgID).First(&rule)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("risk rule not found: %s", id)
@@ -94,9 +134,9 @@ func (r *RiskRepository) GetRuleByID(id string) (*models.RiskRule, error) {
 	return &rule, nil
 }
 
-// DeleteRule permanently removes a risk rule from the database.
-func (r *RiskRepository) DeleteRule(id string) error {
-	result := r.db.Delete(&models.RiskRule{}, "id = ?", id)
+// DeleteRule permanently removes one of an organization's risk rules from the database.
+func (r *RiskRepository) DeleteRule(orgID, id string) error {
+	result := r.db.Delete(&models.RiskRule{}, "id = ? AND org_id = ?", id, orgID)
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete risk rule: %w", result.Error)
 	}
@@ -110,9 +150,9 @@ func (r *RiskRepository) DeleteRule(id string) error {
 
 // DeactivateRule marks a risk rule as inactive without deleting it.
 // This provides a soft delete mechanism for rule management.
-func (r *RiskRepository) DeactivateRule(id string) error {
+func (r *RiskRepository) DeactivateRule(orgID, id string) error {
 	result := r.db.Model(&models.RiskRule{}).
-		Where("id = ?", id).
+		Where("id = ? AND org_id = ?", id, orgID).
 		Update("is_active", false)
 
 	if result.Error != nil {
@@ -125,3 +165,76 @@ func (r *RiskRepository) DeactivateRule(id string) error {
 
 	return nil
 }
+
+// GetUserIDsByLatestRiskLevel retrieves an organization's users whose most recent risk check has the given level.
+// older checks are ignored so users who have since improved or worsened are not matched.
+func (r *RiskRepository) GetUserIDsByLatestRiskLevel(orgID, riskLevel string) ([]string, error) {
+	var userIDs []string
+
+	result := r.db.Model(&models.UserRiskLevel{}).
+		Where("org_id = ? AND risk_level = ?", orgID, riskLevel).
+		Pluck("user_id", &userIDs)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query users by risk level: %w", result.Error)
+	}
+
+	return userIDs, nil
+}
+
+// GetLatestRiskyByUser reports for each of the given users whether their most recent risk check was risky.
+// users never checked in the organization are absent from the result.
+func (r *RiskRepository) GetLatestRiskyByUser(orgID string, userIDs []string) (map[string]bool, error) {
+	latestRisky := make(map[string]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return latestRisky, nil
+	}
+
+	var rows []models.UserRiskLevel
+	result := r.db.Select("user_id, is_risky").
+		Where("org_id = ? AND user_id IN ?", orgID, userIDs).
+		Find(&rows)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query latest risk checks: %w", result.Error)
+	}
+
+	for _, row := range rows {
+		latestRisky[row.UserID] = row.IsRisky
+	}
+	return latestRisky, nil
+}
+
+// GetDisposableDomains returns the stored disposable email domains.
+// an empty result means no list has been stored and the embedded default applies.
+func (r *RiskRepository) GetDisposableDomains() ([]string, error) {
+	var domains []string
+
+	result := r.db.Model(&models.DisposableDomain{}).Order("domain").Pluck("domain", &domains)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query disposable domains: %w", result.Error)
+	}
+
+	return domains, nil
+}
+
+// ReplaceDisposableDomains stores domains as the complete disposable domain list in one transaction.
+func (r *RiskRepository) ReplaceDisposableDomains(domains []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.DisposableDomain{}).Error; err != nil {
+			return fmt.Errorf("failed to clear disposable domains: %w", err)
+		}
+		if len(domains) == 0 {
+			return nil
+		}
+
+		rows := make([]models.DisposableDomain, len(domains))
+		for i, domain := range domains {
+			rows[i] = models.DisposableDomain{Domain: domain}
+		}
+		if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+			return fmt.Errorf("failed to store disposable domains: %w", err)
+		}
+		return nil
+	})
+}
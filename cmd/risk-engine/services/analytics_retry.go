@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/logger"
+)
+
+const (
+	maxQueuedResults    = 1000             // Failed writes held for retry, newer failures are dropped beyond it
+	maxStoreAttempts    = 8                // Attempts per result, including the one that queued it
+	retryInitialDelay   = time.Second      // Delay before the first retry, doubled after every failure
+	retryMaxDelay       = time.Minute      // Upper bound of the retry delay
+	retryPollInterval   = time.Second      // How often the queue looks for due retries
+	retryAttemptTimeout = 10 * time.Second // Timeout of a single store attempt
+)
+
+// AnalyticsRetryQueue keeps risk results whose analytics write failed and retries them with backoff.
+// the queue is bounded and in memory, results that don't fit or run out of attempts are counted as dropped.
+type AnalyticsRetryQueue struct {
+	analytics *RiskAnalytics
+	logger    *logger.Logger
+	mu        sync.Mutex
+	pending   []*queuedResult
+	dropped   atomic.Uint64 // Results given up on since startup
+}
+
+// queuedResult is a failed write waiting for its next attempt.
+type queuedResult struct {
+	result   *models.RiskCheckResult
+	attempts int
+	next     time.Time
+}
+
+// NewAnalyticsRetryQueue creates an empty retry queue storing through analytics.
+func NewAnalyticsRetryQueue(analytics *RiskAnalytics, logger *logger.Logger) *AnalyticsRetryQueue {
+	return &AnalyticsRetryQueue{analytics: analytics, logger: logger}
+}
+
+// Enqueue schedules a retry of result after its first write failed with err.
+func (q *AnalyticsRetryQueue) Enqueue(result *models.RiskCheckResult, err error) {
+	q.mu.Lock()
+	full := len(q.pending) >= maxQueuedResults
+	if !full {
+		q.pending = append(q.pending, &queuedResult{result: result, attempts: 1, next: time.Now().Add(retryInitialDelay)})
+	}
+	queued := len(q.pending)
+	q.mu.Unlock()
+
+	if full {
+		q.drop(result, err, "retry queue full")
+		return
+	}
+	q.logger.Warn("Failed to store risk result for analytics, will retry",
+		"check_id", result.CheckID,
+		"error", err.Error(),
+		"queued", queued,
+	)
+}
+
+// Run retries due results until ctx is cancelled.
+func (q *AnalyticsRetryQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.retryDue(ctx, time.Now())
+		}
+	}
+}
+
+// Flush makes one last attempt for every queued result, typically on shutdown.
+// results still failing are dropped, as nothing would retry them afterwards.
+func (q *AnalyticsRetryQueue) Flush(ctx context.Context) {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	stored := 0
+	for _, item := range pending {
+		if err := q.store(ctx, item.result); err != nil {
+			q.drop(item.result, err, "shutdown")
+			continue
+		}
+		stored++
+	}
+	q.logger.Info("Flushed queued analytics writes", "stored", stored, "dropped", len(pending)-stored)
+}
+
+// Dropped returns the number of results given up on since startup.
+func (q *AnalyticsRetryQueue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// retryDue attempts every result whose backoff has elapsed, requeueing the ones that fail again.
+// attempts run outside the lock so new failures can be queued meanwhile.
+func (q *AnalyticsRetryQueue) retryDue(ctx context.Context, now time.Time) {
+	q.mu.Lock()
+	var due []*queuedResult
+	waiting := q.pending[:0]
+	for _, item := range q.pending {
+		if now.Before(item.next) {
+			waiting = append(waiting, item)
+		} else {
+			due = append(due, item)
+		}
+	}
+	q.pending = waiting
+	q.mu.Unlock()
+
+	for _, item := range due {
+		if ctx.Err() != nil {
+			q.requeue(item)
+			continue
+		}
+
+		err := q.store(ctx, item.result)
+		if err == nil {
+			q.logger.Info("Stored risk result for analytics after retry", "check_id", item.result.CheckID, "attempts", item.attempts+1)
+			continue
+		}
+
+		item.attempts++
+		if item.attempts >= maxStoreAttempts {
+			q.drop(item.result, err, "attempts exhausted")
+			continue
+		}
+		item.next = time.Now().Add(retryDelay(item.attempts))
+		q.requeue(item)
+	}
+}
+
+// requeue puts item back in the queue for its next attempt.
+func (q *AnalyticsRetryQueue) requeue(item *queuedResult) {
+	q.mu.Lock()
+	q.pending = append(q.pending, item)
+	q.mu.Unlock()
+}
+
+// store writes result once, treating a result already in the database as stored.
+// an earlier attempt may have committed even though it reported an error, e.g. on a timeout.
+func (q *AnalyticsRetryQueue) store(ctx context.Context, result *models.RiskCheckResult) error {
+	storeCtx, cancel := context.WithTimeout(ctx, retryAttemptTimeout)
+	defer cancel()
+
+	if stored, err := q.analytics.ResultStored(storeCtx, result.CheckID); err == nil && stored {
+		return nil
+	}
+	return q.analytics.StoreRiskResult(storeCtx, result)
+}
+
+// drop gives up on result and logs it with the running total.
+func (q *AnalyticsRetryQueue) drop(result *models.RiskCheckResult, err error, reason string) {
+	total := q.dropped.Add(1)
+	q.logger.Error("Dropped risk result for analytics", err,
+		"check_id", result.CheckID,
+		"reason", reason,
+		"analytics_writes_dropped", total,
+	)
+}
+
+// retryDelay returns the backoff after the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := retryInitialDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
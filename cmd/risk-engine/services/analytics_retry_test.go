@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+)
+
+// failWrites makes every insert into risk_check_results fail while the returned flag is set.
+func failWrites(t *testing.T, db *gorm.DB) *atomic.Bool {
+	t.Helper()
+	var failing atomic.Bool
+	failing.Store(true)
+	err := db.Callback().Create().Before("gorm:create").Register("test:fail_writes", func(tx *gorm.DB) {
+		if failing.Load() && tx.Statement.Table == "risk_check_results" {
+			tx.AddError(errors.New("database unavailable"))
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	return &failing
+}
+
+func newRetryQueue(t *testing.T) (*gorm.DB, *services.RiskAnalytics, *services.AnalyticsRetryQueue) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	analytics := services.NewRiskAnalytics(db, nil, config.NewSettings(config.Reloadable{}), log)
+	return db, analytics, services.NewAnalyticsRetryQueue(analytics, log)
+}
+
+func newResult(checkID string) *models.RiskCheckResult {
+	return &models.RiskCheckResult{CheckID: checkID, UserID: "user-1", OrgID: "default", RiskLevel: "LOW", CheckedAt: time.Now().UTC()}
+}
+
+func resultStored(t *testing.T, analytics *services.RiskAnalytics, checkID string) bool {
+	t.Helper()
+	stored, err := analytics.ResultStored(context.Background(), checkID)
+	if err != nil {
+		t.Fatalf("ResultStored() error = %v", err)
+	}
+	return stored
+}
+
+func TestRetryQueueStoresAfterTransientFailure(t *testing.T) {
+	db, analytics, queue := newRetryQueue(t)
+	failing := failWrites(t, db)
+
+	result := newResult("check-1")
+	err := analytics.StoreRiskResult(context.Background(), result)
+	if err == nil {
+		t.Fatal("StoreRiskResult() succeeded, want the injected failure")
+	}
+	queue.Enqueue(result, err)
+	failing.Store(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	testutil.Eventually(t, func() bool { return resultStored(t, analytics, "check-1") }, "retried write of check-1")
+	if got := queue.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestRetryQueueFlushOnShutdown(t *testing.T) {
+	db, analytics, queue := newRetryQueue(t)
+	failing := failWrites(t, db)
+
+	for _, checkID := range []string{"check-1", "check-2"} {
+		queue.Enqueue(newResult(checkID), errors.New("database unavailable"))
+	}
+	failing.Store(false)
+	queue.Flush(context.Background())
+
+	for _, checkID := range []string{"check-1", "check-2"} {
+		if !resultStored(t, analytics, checkID) {
+			t.Errorf("%s not stored by Flush", checkID)
+		}
+	}
+
+	// Results still failing on shutdown have nothing left to retry them
+	failing.Store(true)
+	queue.Enqueue(newResult("check-3"), errors.New("database unavailable"))
+	queue.Flush(context.Background())
+	if got := queue.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want the result that failed during Flush counted", got)
+	}
+	queue.Flush(context.Background())
+	if got := queue.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d after a second Flush, want the queue emptied", got)
+	}
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/clock"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+func TestRuleCacheAndExpiryFollowClock(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	repo := repository.NewRiskRepository(db)
+	fake := clock.NewFake(time.Now())
+	expiresAt := fake.Now().Add(time.Hour)
+	blocked := models.RiskRule{ID: "r1", OrgID: "default", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 20, Confidence: 1, IsActive: true, ExpiresAt: &expiresAt}
+	if err := repo.CreateRule(&blocked); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+
+	engine := services.NewRiskEngine(repo, config.NewSettings(config.Reloadable{RuleCacheTTL: 5 * time.Minute}), "rule_id", logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	engine.SetClock(fake)
+	score := func() int {
+		t.Helper()
+		result, err := engine.CheckRisk(context.Background(), &pb_risk.RiskCheckRequest{
+			UserId: "user-1", Email: "someone@blocked.example", FirstName: "Test", LastName: "User", OrgId: "default",
+		})
+		if err != nil {
+			t.Fatalf("CheckRisk() error = %v", err)
+		}
+		return result.TotalScore
+	}
+
+	if got := score(); got != 20 {
+		t.Fatalf("score = %d, want the blocked domain rule", got)
+	}
+	name := models.RiskRule{ID: "r2", OrgID: "default", Name: "Test name", Category: "NAME", Type: "CONTAINS", Value: "test", Score: 10, Confidence: 1, IsActive: true}
+	if err := repo.CreateRule(&name); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+
+	fake.Advance(5*time.Minute - time.Second)
+	if got := score(); got != 20 {
+		t.Errorf("score = %d within the cache TTL, want the cached rules only", got)
+	}
+	if stats := engine.GetCacheStats("default"); stats.Age != 5*time.Minute-time.Second {
+		t.Errorf("cache age = %v, want the time the clock advanced", stats.Age)
+	}
+
+	fake.Advance(time.Second)
+	if got := score(); got != 30 {
+		t.Errorf("score = %d once the TTL passed, want the refreshed rules", got)
+	}
+	if stats := engine.GetCacheStats("default"); stats.Age != 0 || !stats.LastUpdated.Equal(fake.Now()) {
+		t.Errorf("cache stats = %+v after the refresh, want it loaded now", stats)
+	}
+
+	fake.Advance(time.Hour)
+	if got := score(); got != 10 {
+		t.Errorf("score = %d past the rule's expiry, want the expired rule left out", got)
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import "strings"
+
+// confusables maps characters commonly used to impersonate Latin letters to the letters they imitate.
+// it is a curated subset of the Unicode confusables table (UTS #39) covering Cyrillic, Greek and
+// ASCII lookalikes, applied after lowercasing so only lowercase sources are listed.
+var confusables = map[rune]string{
+	// Cyrillic
+	'а': "a", 'с': "c", 'ԁ': "d", 'е': "e", 'һ': "h", 'і': "i", 'ј': "j", 'ӏ': "l",
+	'о': "o", 'р': "p", 'ԛ': "q", 'ѕ': "s", 'ԝ': "w", 'х': "x", 'у': "y",
+
+	// Greek
+	'α': "a", 'ι': "i", 'ν': "v", 'ο': "o", 'ρ': "p",
+
+	// Latin and ASCII lookalikes
+	'ɑ': "a", 'ɡ': "g", 'ı': "i", 'ɩ': "i", 'ⅼ': "l", '|': "l", '1': "l", '0': "o", 'm': "rn",
+}
+
+// confusableSkeleton reduces s to the form shared by all strings that look alike,
+// so "раypal" with Cyrillic "р" and "а" and "paypal" have the same skeleton.
+// diacritics are folded first, the skeleton is only for comparison and never shown.
+func confusableSkeleton(s string) string {
+	var b strings.Builder
+	for _, r := range foldDiacritics(strings.ToLower(s)) {
+		if mapped, ok := confusables[r]; ok {
+			b.WriteString(mapped)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// confusableMatch reports whether input contains a disguised copy of value.
+// plain occurrences of value are ignored, those are what CONTAINS rules are for.
+func confusableMatch(input, value string) bool {
+	value = strings.ToLower(value)
+	if value == "" || strings.Contains(strings.ToLower(input), value) {
+		return false
+	}
+	return strings.Contains(confusableSkeleton(input), confusableSkeleton(value))
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed disposable_domains.txt
+var embeddedDisposableDomains string
+
+// DefaultDisposableDomains returns the embedded list of disposable email domains.
+// it is used until an admin stores a list of their own.
+func DefaultDisposableDomains() []string {
+	var domains []string
+	for _, line := range strings.Split(embeddedDisposableDomains, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	return domains
+}
+
+// DisposableDomains is a concurrency-safe set of disposable email domains.
+type DisposableDomains struct {
+	mu      sync.RWMutex
+	domains map[string]struct{}
+}
+
+// NewDisposableDomains creates a set holding the given domains.
+func NewDisposableDomains(domains []string) *DisposableDomains {
+	d := &DisposableDomains{}
+	d.Replace(domains)
+	return d
+}
+
+// Replace swaps the whole set, so a refresh never exposes a partially loaded list.
+func (d *DisposableDomains) Replace(domains []string) {
+	set := make(map[string]struct{}, len(domains))
+	for _, domain := range domains {
+		if domain = NormalizeDomain(domain); domain != "" {
+			set[domain] = struct{}{}
+		}
+	}
+
+	d.mu.Lock()
+	d.domains = set
+	d.mu.Unlock()
+}
+
+// Contains reports whether domain or any of its parent domains is disposable,
+// so "inbox.mailinator.com" matches a "mailinator.com" entry.
+func (d *DisposableDomains) Contains(domain string) bool {
+	domain = NormalizeDomain(domain)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for domain != "" {
+		if _, ok := d.domains[domain]; ok {
+			return true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+	return false
+}
+
+// List returns the domains in the set in sorted order.
+func (d *DisposableDomains) List() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	domains := make([]string, 0, len(d.domains))
+	for domain := range d.domains {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// NormalizeDomain lowercases a domain and strips surrounding whitespace and dots.
+func NormalizeDomain(domain string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
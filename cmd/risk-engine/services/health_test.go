@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/clock"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+func TestCacheHealthDegradesAfterFailedRefresh(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	repo := repository.NewRiskRepository(db)
+	rule := models.RiskRule{ID: "r1", OrgID: "default", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 20, Confidence: 1, IsActive: true}
+	if err := repo.CreateRule(&rule); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+
+	const ttl = 5 * time.Minute
+	engine := services.NewRiskEngine(repo, config.NewSettings(config.Reloadable{RuleCacheTTL: ttl}), services.FlagFormatRuleID, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	fake := clock.NewFake(time.Now())
+	engine.SetClock(fake)
+	check := func() error {
+		_, err := engine.CheckRisk(context.Background(), &pb_risk.RiskCheckRequest{UserId: "user-1", Email: "someone@blocked.example", OrgId: "default"})
+		return err
+	}
+	// Renaming the rules table makes every refresh fail until it is renamed back
+	rename := func(from, to string) {
+		t.Helper()
+		if err := db.Exec("ALTER TABLE " + from + " RENAME TO " + to).Error; err != nil {
+			t.Fatalf("failed to rename %s: %v", from, err)
+		}
+	}
+
+	if err := check(); err != nil {
+		t.Fatalf("CheckRisk() error = %v", err)
+	}
+	if err := engine.CheckCacheHealth(); err != nil {
+		t.Fatalf("CheckCacheHealth() with fresh rules = %v, want healthy", err)
+	}
+
+	rename("risk_rules", "risk_rules_offline")
+	fake.Advance(ttl)
+	if err := check(); err == nil {
+		t.Fatal("CheckRisk() succeeded without a rules table")
+	}
+	if err := engine.CheckCacheHealth(); err != nil {
+		t.Errorf("CheckCacheHealth() after one failed refresh = %v, want the recent cache tolerated", err)
+	}
+
+	fake.Advance(2*ttl + time.Second)
+	if err := check(); err == nil {
+		t.Fatal("CheckRisk() succeeded without a rules table")
+	}
+	if err := engine.CheckCacheHealth(); err == nil || !strings.Contains(err.Error(), "org default") {
+		t.Errorf("CheckCacheHealth() with a stale cache = %v, want the failing organization reported", err)
+	}
+
+	rename("risk_rules_offline", "risk_rules")
+	if err := check(); err != nil {
+		t.Fatalf("CheckRisk() after recovery error = %v", err)
+	}
+	if err := engine.CheckCacheHealth(); err != nil {
+		t.Errorf("CheckCacheHealth() after a successful refresh = %v, want healthy", err)
+	}
+}
+
+func TestCacheHealthDegradesWhenCachedRulesAreEmpty(t *testing.T) {
+	repo := repository.NewRiskRepository(testutil.NewSQLiteDB(t, models.AutoMigrate))
+	engine := services.NewRiskEngine(repo, config.NewSettings(config.Reloadable{RuleCacheTTL: time.Hour}), services.FlagFormatRuleID, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+
+	if _, err := engine.CheckRisk(context.Background(), &pb_risk.RiskCheckRequest{UserId: "user-1", Email: "someone@example.com", OrgId: "default"}); err != nil {
+		t.Fatalf("CheckRisk() error = %v", err)
+	}
+	if err := engine.CheckCacheHealth(); err != nil {
+		t.Fatalf("CheckCacheHealth() for an organization without rules = %v, want healthy", err)
+	}
+
+	// Stored after the cache was loaded, e.g. by another replica
+	rule := models.RiskRule{ID: "r1", OrgID: "default", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 20, Confidence: 1, IsActive: true}
+	if err := repo.CreateRule(&rule); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if err := engine.CheckCacheHealth(); err == nil || !strings.Contains(err.Error(), "1 rules are active") {
+		t.Errorf("CheckCacheHealth() with an empty cache = %v, want the active rules reported", err)
+	}
+}
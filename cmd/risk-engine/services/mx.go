@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"user-risk-system/pkg/logger"
+)
+
+// maxMXCacheEntries bounds the MX cache, expired entries are pruned once it is reached.
+const maxMXCacheEntries = 10000
+
+// MXResolver looks up mail exchangers for a domain, *net.Resolver satisfies it.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// mxEntry is a cached lookup result for one domain.
+type mxEntry struct {
+	hasMX     bool
+	checkedAt time.Time
+}
+
+// MXChecker reports whether email domains can receive mail, based on cached MX lookups.
+// lookups run in the background: a domain seen for the first time is reported as deliverable
+// and the result is used from the next check on, so DNS never delays a risk check.
+type MXChecker struct {
+	resolver MXResolver
+	timeout  time.Duration
+	ttl      time.Duration
+	logger   *logger.Logger
+
+	mu       sync.Mutex
+	cache    map[string]mxEntry
+	inflight map[string]bool
+}
+
+// NewMXChecker creates a checker bounding each lookup by timeout and caching results for ttl.
+func NewMXChecker(resolver MXResolver, timeout, ttl time.Duration, logger *logger.Logger) *MXChecker {
+	return &MXChecker{
+		resolver: resolver,
+		timeout:  timeout,
+		ttl:      ttl,
+		logger:   logger,
+		cache:    make(map[string]mxEntry),
+		inflight: make(map[string]bool),
+	}
+}
+
+// HasNoMX reports whether the domain is known to have no MX records.
+// it returns false while the domain is unknown or its entry expired, scheduling a lookup instead.
+func (c *MXChecker) HasNoMX(domain string) bool {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[domain]
+	if !ok || time.Since(entry.checkedAt) >= c.ttl {
+		c.scheduleLookup(domain)
+	}
+	return ok && !entry.hasMX
+}
+
+// scheduleLookup starts a background lookup unless one is already running, c.mu must be held.
+func (c *MXChecker) scheduleLookup(domain string) {
+	if c.inflight[domain] {
+		return
+	}
+	c.inflight[domain] = true
+
+	go func() {
+		hasMX, err := c.lookup(domain)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.inflight, domain)
+
+		if err != nil {
+			// Timeouts and server failures say nothing about the domain, keep the previous answer
+			c.logger.Warn("MX lookup failed", "domain", domain, "error", err.Error())
+			return
+		}
+		if len(c.cache) >= maxMXCacheEntries {
+			c.pruneExpired()
+			if len(c.cache) >= maxMXCacheEntries {
+				return
+			}
+		}
+		c.cache[domain] = mxEntry{hasMX: hasMX, checkedAt: time.Now()}
+	}()
+}
+
+// lookup resolves the domain's MX records, a domain that does not exist has none.
+func (c *MXChecker) lookup(domain string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	records, err := c.resolver.LookupMX(ctx, domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(records) > 0, nil
+}
+
+// pruneExpired drops cache entries older than the TTL, c.mu must be held.
+func (c *MXChecker) pruneExpired() {
+	for domain, entry := range c.cache {
+		if time.Since(entry.checkedAt) >= c.ttl {
+			delete(c.cache, domain)
+		}
+	}
+}
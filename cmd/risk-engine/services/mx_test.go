@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// stubResolver answers MX lookups from a fixed table and counts them per domain.
+type stubResolver struct {
+	mu      sync.Mutex
+	records map[string][]*net.MX
+	errs    map[string]error
+	lookups map[string]int
+}
+
+func newStubResolver() *stubResolver {
+	return &stubResolver{
+		records: map[string][]*net.MX{
+			"mail.example": {{Host: "mx.mail.example.", Pref: 10}},
+		},
+		errs: map[string]error{
+			"nxdomain.example": &net.DNSError{Err: "no such host", Name: "nxdomain.example", IsNotFound: true},
+			"flaky.example":    &net.DNSError{Err: "server misbehaving", Name: "flaky.example", IsTemporary: true},
+		},
+		lookups: make(map[string]int),
+	}
+}
+
+func (r *stubResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups[name]++
+	return r.records[name], r.errs[name]
+}
+
+func (r *stubResolver) lookupCount(domain string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lookups[domain]
+}
+
+func newMXChecker(resolver *stubResolver, ttl time.Duration) *services.MXChecker {
+	return services.NewMXChecker(resolver, time.Second, ttl, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+}
+
+func TestMXCheckerReportsDomainsWithoutMX(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"mail.example", false},
+		{"nomx.example", true},
+		{"nxdomain.example", true},
+		{"flaky.example", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			resolver := newStubResolver()
+			checker := newMXChecker(resolver, time.Hour)
+
+			if checker.HasNoMX(tt.domain) {
+				t.Fatal("HasNoMX() = true before the domain was looked up")
+			}
+			testutil.Eventually(t, func() bool { return resolver.lookupCount(tt.domain) == 1 }, "MX lookup of %s", tt.domain)
+			// The lookup stores its answer right after it returns
+			time.Sleep(50 * time.Millisecond)
+
+			if got := checker.HasNoMX(tt.domain); got != tt.want {
+				t.Errorf("HasNoMX() = %v after the lookup, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMXCheckerCachesPerDomainUntilTTL(t *testing.T) {
+	resolver := newStubResolver()
+	checker := newMXChecker(resolver, 200*time.Millisecond)
+
+	checker.HasNoMX("NoMX.Example")
+	testutil.Eventually(t, func() bool { return checker.HasNoMX("nomx.example") }, "cached no-MX answer")
+	for i := 0; i < 10; i++ {
+		checker.HasNoMX("nomx.example")
+	}
+	if got := resolver.lookupCount("nomx.example"); got != 1 {
+		t.Fatalf("lookups = %d within the TTL, want 1", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if !checker.HasNoMX("nomx.example") {
+		t.Error("HasNoMX() dropped the expired answer before the new lookup finished")
+	}
+	testutil.Eventually(t, func() bool { return resolver.lookupCount("nomx.example") == 2 }, "lookup after the TTL")
+}
+
+func TestCheckRiskFlagsDomainWithoutMX(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	engine := services.NewRiskEngine(repository.NewRiskRepository(db), config.NewSettings(config.Reloadable{}), "rule_id", log)
+	resolver := newStubResolver()
+	engine.EnableMXCheck(newMXChecker(resolver, time.Hour), 40)
+
+	check := func() *models.RiskCheckResult {
+		result, err := engine.CheckRisk(context.Background(), &pb_risk.RiskCheckRequest{
+			UserId: "user-1", Email: "someone@nomx.example", FirstName: "Some", LastName: "One",
+		})
+		if err != nil {
+			t.Fatalf("CheckRisk() error = %v", err)
+		}
+		return result
+	}
+	hasFlag := func(result *models.RiskCheckResult) bool {
+		for _, flag := range result.Flags {
+			if flag.Flag == services.FlagNoMX {
+				return true
+			}
+		}
+		return false
+	}
+
+	if first := check(); hasFlag(first) || first.TotalScore != 0 {
+		t.Fatalf("first check flags = %v, score = %d, want the lookup not to block it", first.Flags, first.TotalScore)
+	}
+	testutil.Eventually(t, func() bool { return hasFlag(check()) }, "%s flag once the lookup finished", services.FlagNoMX)
+	if result := check(); result.TotalScore != 40 {
+		t.Errorf("score = %d, want the configured no-MX score", result.TotalScore)
+	}
+	if got := resolver.lookupCount("nomx.example"); got != 1 {
+		t.Errorf("lookups = %d, want one cached lookup", got)
+	}
+}
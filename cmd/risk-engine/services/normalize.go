@@ -0,0 +1,31 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeName lowercases and trims a name for comparison, folding diacritics when fold is set.
+func normalizeName(name string, fold bool) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if fold {
+		name = foldDiacritics(name)
+	}
+	return name
+}
+
+// foldDiacritics applies compatibility decomposition (NFKD) and drops the combining marks it produces.
+// "José" becomes "Jose" and full-width "Ｊｏｓｅ" becomes "Jose", while letters with no decomposition
+// such as "ø", "ł" or "ß" are distinct letters rather than accented ones and are kept as is.
+func foldDiacritics(s string) string {
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return folded
+}
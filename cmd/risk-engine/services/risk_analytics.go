@@ -2,29 +2,65 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/config"
 	"user-risk-system/pkg/logger"
+	events "user-risk-system/pkg/models"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RiskAnalytics provides statistical analysis and reporting for risk assessments.
 // stores risk check results and generates analytics data for monitoring and reporting.
 type RiskAnalytics struct {
-	db     *gorm.DB
-	logger *logger.Logger
+	db       *gorm.DB // Primary, used for writes
+	readDB   *gorm.DB // Read replica for analytics and history, the primary when none is configured
+	settings *config.Settings
+	logger   *logger.Logger
+	nonRisky atomic.Uint64 // Non-risky checks seen, drives ANALYTICS_SAMPLE_RATE
 }
 
 // NewRiskAnalytics creates a new analytics service with database and logger dependencies.
-func NewRiskAnalytics(db *gorm.DB, logger *logger.Logger) *RiskAnalytics {
+// read-only queries go to readDB; pass nil to run them on the primary.
+func NewRiskAnalytics(db, readDB *gorm.DB, settings *config.Settings, logger *logger.Logger) *RiskAnalytics {
+	if readDB == nil {
+		readDB = db
+	}
 	return &RiskAnalytics{
-		db:     db,
-		logger: logger,
+		db:       db,
+		readDB:   readDB,
+		settings: settings,
+		logger:   logger,
 	}
 }
 
+// Sample decides whether result is stored for analytics and sets the number of checks it stands for.
+// risky checks are always kept, of the non-risky ones every ANALYTICS_SAMPLE_RATE-th is kept with that weight.
+// the others are marked SampledOut, StoreRiskResult then only records the user's latest risk level.
+func (ra *RiskAnalytics) Sample(result *models.RiskCheckResult) {
+	result.SampleWeight = 1
+	result.SampledOut = false
+	rate := ra.settings.Current().AnalyticsSampleRate
+	if result.IsRisky || rate <= 1 {
+		return
+	}
+	if (ra.nonRisky.Add(1)-1)%uint64(rate) != 0 {
+		result.SampledOut = true
+		return
+	}
+	result.SampleWeight = rate
+}
+
 // RiskStats represents aggregated risk assessment statistics.
 // includes counts, rates, scores, and trend data for reporting dashboards.
 type RiskStats struct {
@@ -39,8 +75,9 @@ type RiskStats struct {
 // FlagCount represents the frequency of specific risk flags.
 // tracks how often particular risk indicators are triggered.
 type FlagCount struct {
-	Flag  string `json:"flag"`
-	Count int64  `json:"count"`
+	Flag     string `json:"flag"`
+	RuleName string `json:"rule_name,omitempty"` // Set when every occurrence of the flag came from one rule
+	Count    int64  `json:"count"`
 }
 
 // TrendPoint represents risk assessment data for a specific date.
@@ -51,26 +88,26 @@ type TrendPoint struct {
 	TotalCount int64     `json:"total_count"`
 }
 
-// GetRiskStats computes comprehensive risk statistics for the specified number of days.
+// GetRiskStats computes an organization's risk statistics for the specified number of days.
 // includes total checks, risk rates, average scores, top flags, and trend data.
-func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, days int) (*RiskStats, error) {
+func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, orgID string, days int) (*RiskStats, error) {
 	stats := &RiskStats{}
 	since := time.Now().AddDate(0, 0, -days)
 
-	// Get total checks and risky users
+	// Get total checks and risky users, sampled non-risky checks count with their weight
 	var result struct {
 		TotalChecks  int64   `gorm:"column:total_checks"`
 		RiskyUsers   int64   `gorm:"column:risky_users"`
 		AvgRiskScore float64 `gorm:"column:avg_risk_score"`
 	}
 
-	err := ra.db.WithContext(ctx).Model(&models.RiskCheckResult{}).
+	err := ra.readDB.WithContext(ctx).Model(&models.RiskCheckResult{}).
 		Select(`
-			COUNT(*) as total_checks,
+			COALESCE(SUM(sample_weight), 0) as total_checks,
 			COUNT(CASE WHEN is_risky = true THEN 1 END) as risky_users,
-			COALESCE(AVG(total_score), 0) as avg_risk_score
+			COALESCE(CAST(SUM(total_score * sample_weight) AS FLOAT) / NULLIF(SUM(sample_weight), 0), 0) as avg_risk_score
 		`).
-		Where("checked_at >= ?", since).
+		Where("org_id = ? AND checked_at >= ?", orgID, since).
 		Scan(&result).Error
 
 	if err != nil {
@@ -85,17 +122,19 @@ func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, days int) (*RiskStats
 		stats.RiskRate = float64(stats.RiskyUsers) / float64(stats.TotalChecks)
 	}
 
-	// Get top flags
+	// Get top flags, naming the rule when the flag format ties the flag to a single rule
 	var flagResults []struct {
-		Flag  string `gorm:"column:flag"`
-		Count int64  `gorm:"column:count"`
+		Flag     string `gorm:"column:flag"`
+		RuleName string `gorm:"column:rule_name"`
+		Count    int64  `gorm:"column:count"`
 	}
 
-	err = ra.db.WithContext(ctx).
+	err = ra.readDB.WithContext(ctx).
 		Table("risk_check_flags rcf").
-		Select("rcf.flag, COUNT(*) as count").
+		Select("rcf.flag, CASE WHEN COUNT(DISTINCT rcf.rule_id) = 1 THEN COALESCE(MAX(rr.name), '') ELSE '' END as rule_name, SUM(rcr.sample_weight) as count").
 		Joins("JOIN risk_check_results rcr ON rcf.check_id = rcr.check_id").
-		Where("rcr.checked_at >= ?", since).
+		Joins("LEFT JOIN risk_rules rr ON rr.id = rcf.rule_id").
+		Where("rcr.org_id = ? AND rcr.checked_at >= ?", orgID, since).
 		Group("rcf.flag").
 		Order("count DESC").
 		Limit(10).
@@ -107,8 +146,9 @@ func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, days int) (*RiskStats
 
 	for _, flag := range flagResults {
 		stats.TopFlags = append(stats.TopFlags, FlagCount{
-			Flag:  flag.Flag,
-			Count: flag.Count,
+			Flag:     flag.Flag,
+			RuleName: flag.RuleName,
+			Count:    flag.Count,
 		})
 	}
 
@@ -119,13 +159,13 @@ func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, days int) (*RiskStats
 		TotalCount int64     `gorm:"column:total_count"`
 	}
 
-	err = ra.db.WithContext(ctx).Model(&models.RiskCheckResult{}).
+	err = ra.readDB.WithContext(ctx).Model(&models.RiskCheckResult{}).
 		Select(`
 			DATE(checked_at) as date,
 			COUNT(CASE WHEN is_risky = true THEN 1 END) as risk_count,
-			COUNT(*) as total_count
+			SUM(sample_weight) as total_count
 		`).
-		Where("checked_at >= ?", since).
+		Where("org_id = ? AND checked_at >= ?", orgID, since).
 		Group("DATE(checked_at)").
 		Order("date").
 		Scan(&trendResults).Error
@@ -146,73 +186,234 @@ func (ra *RiskAnalytics) GetRiskStats(ctx context.Context, days int) (*RiskStats
 }
 
 // StoreRiskResult persists a risk check result with associated flags and rule matches.
+// a change from the user's previous stored level is published through the outbox in the same transaction.
 func (ra *RiskAnalytics) StoreRiskResult(ctx context.Context, result *models.RiskCheckResult) error {
-	tx := ra.db.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
-	}
-	defer tx.Rollback()
-
-	if err := tx.Create(result).Error; err != nil {
-		return fmt.Errorf("failed to create risk result: %w", err)
-	}
+	return utils.WithTransaction(ctx, ra.db, func(tx *gorm.DB) error {
+		var previous []models.UserRiskLevel
+		if err := tx.Where("org_id = ? AND user_id = ?", result.OrgID, result.UserID).
+			Limit(1).
+			Find(&previous).Error; err != nil {
+			return fmt.Errorf("failed to load previous risk level: %w", err)
+		}
+		// A retried or delayed write must not replace the level of a newer check
+		current := len(previous) == 0 || (previous[0].CheckID != result.CheckID && !previous[0].CheckedAt.After(result.CheckedAt))
+		if current {
+			level := models.UserRiskLevel{
+				OrgID:     result.OrgID,
+				UserID:    result.UserID,
+				CheckID:   result.CheckID,
+				RiskLevel: result.RiskLevel,
+				IsRisky:   result.IsRisky,
+				CheckedAt: result.CheckedAt,
+			}
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&level).Error; err != nil {
+				return fmt.Errorf("failed to store risk level: %w", err)
+			}
+		}
 
-	if len(result.Flags) > 0 {
-		for i := range result.Flags {
-			// Ensure CheckID is set for each flag
-			result.Flags[i].CheckID = result.CheckID
+		if result.SampledOut {
+			return ra.enqueueLevelChange(ctx, tx, current, previous, result)
 		}
 
-		if err := tx.Create(&result.Flags).Error; err != nil {
-			ra.logger.ErrorCtx(ctx, "Failed to insert flags", err)
-			return fmt.Errorf("failed to insert flags: %w", err)
+		// Flags and rule matches are inserted below, saving them as associations too would duplicate them
+		if err := tx.Omit(clause.Associations).Create(result).Error; err != nil {
+			return fmt.Errorf("failed to create risk result: %w", err)
 		}
-	}
 
-	if len(result.MatchedRules) > 0 {
-		for i := range result.MatchedRules {
-			// Ensure CheckID is set for each rule match
-			result.MatchedRules[i].CheckID = result.CheckID
+		if len(result.Flags) > 0 {
+			for i := range result.Flags {
+				// Ensure CheckID is set for each flag
+				result.Flags[i].CheckID = result.CheckID
+			}
+
+			if err := tx.Create(&result.Flags).Error; err != nil {
+				ra.logger.ErrorCtx(ctx, "Failed to insert flags", err)
+				return fmt.Errorf("failed to insert flags: %w", err)
+			}
 		}
 
-		if err := tx.Create(&result.MatchedRules).Error; err != nil {
-			ra.logger.ErrorCtx(ctx, "Failed to insert rule matches", err)
-			return fmt.Errorf("failed to insert rule matches: %w", err)
+		if len(result.MatchedRules) > 0 {
+			for i := range result.MatchedRules {
+				// Ensure CheckID is set for each rule match
+				result.MatchedRules[i].CheckID = result.CheckID
+			}
+
+			if err := tx.Create(&result.MatchedRules).Error; err != nil {
+				ra.logger.ErrorCtx(ctx, "Failed to insert rule matches", err)
+				return fmt.Errorf("failed to insert rule matches: %w", err)
+			}
 		}
+
+		return ra.enqueueLevelChange(ctx, tx, current, previous, result)
+	})
+}
+
+// enqueueLevelChange publishes risk.level_changed through the outbox of tx when result moved the user
+// off the previous level under RISK_LEVEL_EVENTS. only the user's current check is compared.
+func (ra *RiskAnalytics) enqueueLevelChange(ctx context.Context, tx *gorm.DB, current bool, previous []models.UserRiskLevel, result *models.RiskCheckResult) error {
+	if !current || len(previous) == 0 || !ra.publishLevelChange(previous[0].RiskLevel, result.RiskLevel) {
+		return nil
 	}
 
-	return tx.Commit().Error
+	event := events.RiskLevelChangedEvent{
+		EventMeta: events.NewEventMeta(),
+		UserID:    result.UserID,
+		OrgID:     result.OrgID,
+		CheckID:   result.CheckID,
+		OldLevel:  previous[0].RiskLevel,
+		NewLevel:  result.RiskLevel,
+		ChangedAt: result.CheckedAt,
+	}
+	if err := outbox.Enqueue(tx, events.EventRiskLevelChanged, event); err != nil {
+		return err
+	}
+	ra.logger.InfoCtx(ctx, "Risk level changed",
+		"user_id", result.UserID,
+		"check_id", result.CheckID,
+		"old_level", event.OldLevel,
+		"new_level", event.NewLevel,
+	)
+	return nil
 }
 
-// GetRiskHistory retrieves historical risk assessments for a specific user.
-// includes associated flags and rule matches, ordered by most recent first.
-func (ra *RiskAnalytics) GetRiskHistory(ctx context.Context, userID string, limit int) ([]models.RiskCheckResult, error) {
-	var results []models.RiskCheckResult
+// riskLevelRank orders risk levels from least to most severe.
+var riskLevelRank = map[string]int{"MINIMAL": 0, "LOW": 1, "MEDIUM": 2, "HIGH": 3, "CRITICAL": 4}
+
+// publishLevelChange returns true if moving from oldLevel to newLevel is published under RISK_LEVEL_EVENTS.
+func (ra *RiskAnalytics) publishLevelChange(oldLevel, newLevel string) bool {
+	if oldLevel == newLevel {
+		return false
+	}
+	switch ra.settings.Current().RiskLevelEvents {
+	case config.RiskLevelEventsAny:
+		return true
+	case config.RiskLevelEventsIncrease:
+		return riskLevelRank[newLevel] > riskLevelRank[oldLevel]
+	default:
+		return false
+	}
+}
+
+// ResultStored returns true if the result of checkID is already persisted.
+// reads the primary, a replica may lag behind a write that just committed.
+func (ra *RiskAnalytics) ResultStored(ctx context.Context, checkID string) (bool, error) {
+	var count int64
+	err := ra.db.WithContext(ctx).Model(&models.RiskCheckResult{}).Where("check_id = ?", checkID).Count(&count).Error
+	return count > 0, err
+}
 
-	err := ra.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+// Risk history page sizes.
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+// RiskHistoryFilter selects and orders one page of a user's risk history.
+type RiskHistoryFilter struct {
+	RiskLevel   string    // Only checks at this level, empty for all
+	Since       time.Time // Only checks at or after this time, zero for unbounded
+	Until       time.Time // Only checks before this time, zero for unbounded
+	OldestFirst bool      // Ascending instead of the default most recent first
+	Cursor      string    // Position after the previous page, from its next cursor
+	Limit       int       // Page size, DefaultHistoryLimit when 0
+}
+
+// ErrInvalidHistoryCursor is returned for a cursor that wasn't produced by GetRiskHistory.
+var ErrInvalidHistoryCursor = errors.New("invalid history cursor")
+
+// GetRiskHistory retrieves one page of historical risk assessments for a specific user of an organization.
+// includes associated flags and rule matches. Pages are keyed on (checked_at, id) so they stay on the
+// user_id/checked_at index and don't shift while new checks arrive; the returned cursor is empty on the last page.
+func (ra *RiskAnalytics) GetRiskHistory(ctx context.Context, orgID, userID string, filter RiskHistoryFilter) ([]models.RiskCheckResult, string, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if limit > MaxHistoryLimit {
+		limit = MaxHistoryLimit
+	}
+
+	query := ra.readDB.WithContext(ctx).Where("user_id = ? AND org_id = ?", userID, orgID)
+	if filter.RiskLevel != "" {
+		query = query.Where("risk_level = ?", filter.RiskLevel)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("checked_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("checked_at < ?", filter.Until)
+	}
+
+	order, after := "checked_at DESC, id DESC", "(checked_at, id) < (?, ?)"
+	if filter.OldestFirst {
+		order, after = "checked_at ASC, id ASC", "(checked_at, id) > (?, ?)"
+	}
+	if filter.Cursor != "" {
+		checkedAt, id, err := decodeHistoryCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where(after, checkedAt, id)
+	}
+
+	var results []models.RiskCheckResult
+	err := query.
 		Preload("Flags").
 		Preload("MatchedRules").
-		Order("checked_at DESC").
-		Limit(limit).
+		Order(order).
+		Limit(limit + 1).
 		Find(&results).Error
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get risk history: %w", err)
+		return nil, "", fmt.Errorf("failed to get risk history: %w", err)
 	}
 
-	return results, nil
+	next := ""
+	if len(results) > limit {
+		results = results[:limit]
+		last := results[limit-1]
+		next = encodeHistoryCursor(last.CheckedAt, last.ID)
+	}
+
+	return results, next, nil
+}
+
+// encodeHistoryCursor returns the opaque cursor pointing after the check at checkedAt with id.
+func encodeHistoryCursor(checkedAt time.Time, id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", checkedAt.UnixNano(), id)))
+}
+
+// decodeHistoryCursor parses a cursor produced by encodeHistoryCursor.
+func decodeHistoryCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidHistoryCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidHistoryCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidHistoryCursor
+	}
+	i, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidHistoryCursor
+	}
+	// In UTC like stored check times, a local time wouldn't compare correctly where times are stored as text
+	return time.Unix(0, n).UTC(), uint(i), nil
 }
 
 // GetRiskSummaryByDateRange gets aggregated risk data for a specific date range.
 // provides summary statistics for custom time periods defined by start and end dates.
-func (ra *RiskAnalytics) GetRiskSummaryByDateRange(ctx context.Context, startDate, endDate time.Time) (*RiskStats, error) {
-	return ra.getRiskStatsInRange(ctx, startDate, endDate)
+func (ra *RiskAnalytics) GetRiskSummaryByDateRange(ctx context.Context, orgID string, startDate, endDate time.Time) (*RiskStats, error) {
+	return ra.getRiskStatsInRange(ctx, orgID, startDate, endDate)
 }
 
 // getRiskStatsInRange computes risk statistics for a custom date range.
 // internal helper method for date-bounded analytics queries.
-func (ra *RiskAnalytics) getRiskStatsInRange(ctx context.Context, startDate, endDate time.Time) (*RiskStats, error) {
+func (ra *RiskAnalytics) getRiskStatsInRange(ctx context.Context, orgID string, startDate, endDate time.Time) (*RiskStats, error) {
 	stats := &RiskStats{}
 
 	// Get basic stats for the date range
@@ -222,13 +423,13 @@ func (ra *RiskAnalytics) getRiskStatsInRange(ctx context.Context, startDate, end
 		AvgRiskScore float64 `gorm:"column:avg_risk_score"`
 	}
 
-	err := ra.db.WithContext(ctx).Model(&models.RiskCheckResult{}).
+	err := ra.readDB.WithContext(ctx).Model(&models.RiskCheckResult{}).
 		Select(`
-			COUNT(*) as total_checks,
+			COALESCE(SUM(sample_weight), 0) as total_checks,
 			COUNT(CASE WHEN is_risky = true THEN 1 END) as risky_users,
-			COALESCE(AVG(total_score), 0) as avg_risk_score
+			COALESCE(CAST(SUM(total_score * sample_weight) AS FLOAT) / NULLIF(SUM(sample_weight), 0), 0) as avg_risk_score
 		`).
-		Where("checked_at BETWEEN ? AND ?", startDate, endDate).
+		Where("org_id = ? AND checked_at BETWEEN ? AND ?", orgID, startDate, endDate).
 		Scan(&result).Error
 
 	if err != nil {
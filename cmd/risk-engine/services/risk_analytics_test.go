@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	events "user-risk-system/pkg/models"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/testutil"
+)
+
+func TestStoreRiskResultKeepsLevelOfSampledOutChecks(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	settings := config.NewSettings(config.Reloadable{
+		AnalyticsSampleRate: 2,
+		RiskLevelEvents:     config.RiskLevelEventsIncrease,
+	})
+	analytics := services.NewRiskAnalytics(db, nil, settings, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	repo := repository.NewRiskRepository(db)
+
+	start := time.Now().Add(-time.Hour)
+	checks := []struct {
+		level      string
+		risky      bool
+		sampledOut bool
+	}{
+		{"MINIMAL", false, false},
+		{"HIGH", true, false},
+		{"MINIMAL", false, true},
+		{"HIGH", true, false},
+	}
+	for i, c := range checks {
+		result := &models.RiskCheckResult{
+			CheckID:   fmt.Sprintf("check-%d", i),
+			UserID:    "user-1",
+			OrgID:     "default",
+			IsRisky:   c.risky,
+			RiskLevel: c.level,
+			CheckedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		analytics.Sample(result)
+		if result.SampledOut != c.sampledOut {
+			t.Fatalf("check %d: SampledOut = %v, want %v", i, result.SampledOut, c.sampledOut)
+		}
+		if err := analytics.StoreRiskResult(context.Background(), result); err != nil {
+			t.Fatalf("check %d: StoreRiskResult() error = %v", i, err)
+		}
+
+		if c.sampledOut {
+			latest, err := repo.GetLatestRiskyByUser("default", []string{"user-1"})
+			if err != nil {
+				t.Fatalf("GetLatestRiskyByUser() error = %v", err)
+			}
+			if latest["user-1"] {
+				t.Errorf("latest risky after sampled out MINIMAL check = true, want false")
+			}
+		}
+	}
+
+	var stored int64
+	db.Model(&models.RiskCheckResult{}).Count(&stored)
+	if stored != 3 {
+		t.Errorf("stored results = %d, want 3", stored)
+	}
+
+	var changed int64
+	db.Model(&outbox.Message{}).Where("queue = ?", events.EventRiskLevelChanged).Count(&changed)
+	if changed != 2 {
+		t.Errorf("level_changed events = %d, want 2", changed)
+	}
+
+	// A retried write of an older check leaves the latest level alone
+	retry := &models.RiskCheckResult{CheckID: "check-2", UserID: "user-1", OrgID: "default", RiskLevel: "MINIMAL", CheckedAt: start.Add(2 * time.Minute), SampledOut: true}
+	if err := analytics.StoreRiskResult(context.Background(), retry); err != nil {
+		t.Fatalf("StoreRiskResult() retry error = %v", err)
+	}
+	high, err := repo.GetUserIDsByLatestRiskLevel("default", "HIGH")
+	if err != nil {
+		t.Fatalf("GetUserIDsByLatestRiskLevel() error = %v", err)
+	}
+	if len(high) != 1 || high[0] != "user-1" {
+		t.Errorf("users on HIGH = %v, want [user-1]", high)
+	}
+}
+
+func TestReadsUseReplicaWhenConfigured(t *testing.T) {
+	primary := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	replica := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	settings := config.NewSettings(config.Reloadable{AnalyticsSampleRate: 1, RiskLevelEvents: config.RiskLevelEventsOff})
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	ctx := context.Background()
+
+	stored := &models.RiskCheckResult{CheckID: "check-primary", UserID: "user-1", OrgID: "default", RiskLevel: "HIGH", IsRisky: true, CheckedAt: time.Now()}
+	replicated := &models.RiskCheckResult{CheckID: "check-replica", UserID: "user-1", OrgID: "default", RiskLevel: "LOW", CheckedAt: time.Now()}
+	if err := replica.Create(replicated).Error; err != nil {
+		t.Fatalf("seed replica: %v", err)
+	}
+	writer := services.NewRiskAnalytics(primary, replica, settings, log)
+	writer.Sample(stored)
+	if err := writer.StoreRiskResult(ctx, stored); err != nil {
+		t.Fatalf("StoreRiskResult() error = %v", err)
+	}
+	var onPrimary int64
+	primary.Model(&models.RiskCheckResult{}).Where("check_id = ?", "check-primary").Count(&onPrimary)
+	if onPrimary != 1 {
+		t.Fatalf("primary holds %d copies of the stored check, want the write to go to the primary", onPrimary)
+	}
+
+	tests := []struct {
+		name        string
+		readDB      *gorm.DB
+		wantHistory string
+	}{
+		{"replica configured", replica, "check-replica"},
+		{"no replica", nil, "check-primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analytics := services.NewRiskAnalytics(primary, tt.readDB, settings, log)
+			if ok, err := analytics.ResultStored(ctx, "check-primary"); err != nil || !ok {
+				t.Errorf("ResultStored() = %v, %v, want the primary to be read", ok, err)
+			}
+
+			history, _, err := analytics.GetRiskHistory(ctx, "default", "user-1", services.RiskHistoryFilter{})
+			if err != nil {
+				t.Fatalf("GetRiskHistory() error = %v", err)
+			}
+			if len(history) != 1 || history[0].CheckID != tt.wantHistory {
+				t.Errorf("history = %+v, want only %s", history, tt.wantHistory)
+			}
+		})
+	}
+}
+
+func TestRiskHistoryPagesAcrossEqualTimestamps(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	analytics := services.NewRiskAnalytics(db, nil, config.NewSettings(config.Reloadable{AnalyticsSampleRate: 1}), logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+	ctx := context.Background()
+
+	// Three checks share a timestamp, so only the ID orders them
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	checks := []struct {
+		id    string
+		level string
+		at    time.Time
+	}{
+		{"c1", "LOW", start},
+		{"c2", "HIGH", start.Add(time.Minute)},
+		{"c3", "LOW", start.Add(time.Minute)},
+		{"c4", "HIGH", start.Add(time.Minute)},
+		{"c5", "LOW", start.Add(2 * time.Minute)},
+	}
+	for _, c := range checks {
+		result := &models.RiskCheckResult{CheckID: c.id, UserID: "user-1", OrgID: "default", RiskLevel: c.level, CheckedAt: c.at}
+		if err := db.Create(result).Error; err != nil {
+			t.Fatalf("seed %s: %v", c.id, err)
+		}
+	}
+	other := &models.RiskCheckResult{CheckID: "other-org", UserID: "user-1", OrgID: "acme", RiskLevel: "LOW", CheckedAt: start.Add(time.Minute)}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("seed other organization: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter services.RiskHistoryFilter
+		want   [][]string
+	}{
+		{"most recent first", services.RiskHistoryFilter{Limit: 2}, [][]string{{"c5", "c4"}, {"c3", "c2"}, {"c1"}}},
+		{"oldest first", services.RiskHistoryFilter{Limit: 2, OldestFirst: true}, [][]string{{"c1", "c2"}, {"c3", "c4"}, {"c5"}}},
+		{"risk level", services.RiskHistoryFilter{Limit: 2, RiskLevel: "LOW"}, [][]string{{"c5", "c3"}, {"c1"}}},
+		{"time window", services.RiskHistoryFilter{Limit: 2, Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute)}, [][]string{{"c4", "c3"}, {"c2"}}},
+		{"exact last page", services.RiskHistoryFilter{Limit: 5}, [][]string{{"c5", "c4", "c3", "c2", "c1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			var pages [][]string
+			for page := 0; page <= len(tt.want); page++ {
+				results, next, err := analytics.GetRiskHistory(ctx, "default", "user-1", filter)
+				if err != nil {
+					t.Fatalf("GetRiskHistory() page %d error = %v", page, err)
+				}
+				ids := []string{}
+				for _, result := range results {
+					ids = append(ids, result.CheckID)
+				}
+				pages = append(pages, ids)
+				if next == "" {
+					break
+				}
+				filter.Cursor = next
+			}
+			if !reflect.DeepEqual(pages, tt.want) {
+				t.Errorf("pages = %v, want %v", pages, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := analytics.GetRiskHistory(ctx, "default", "user-1", services.RiskHistoryFilter{Cursor: "not-a-cursor"}); !errors.Is(err, services.ErrInvalidHistoryCursor) {
+		t.Errorf("GetRiskHistory() with a malformed cursor error = %v, want ErrInvalidHistoryCursor", err)
+	}
+}
@@ -2,14 +2,23 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 	"user-risk-system/cmd/risk-engine/models"
 	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/cache"
+	"user-risk-system/pkg/clock"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/features"
 	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/pii"
 	pb_risk "user-risk-system/proto/risk"
 
 	"github.com/google/uuid"
@@ -20,109 +29,210 @@ import (
 type RiskEngine struct {
 	riskRepo   *repository.RiskRepository
 	logger     *logger.Logger
-	ruleCache  map[string][]models.RiskRule // Cache rules by category
-	cacheTime  time.Time
-	cacheTTL   time.Duration
+	ruleCache  map[string]*orgRules // Cached rules keyed by organization ID
+	settings   *config.Settings     // Source of the reloadable cache TTL and risk thresholds
+	flagFormat string               // How flags identify the matched rule, see FlagFormatType and friends
+	disposable *DisposableDomains   // Domains DISPOSABLE_EMAIL rules flag, refreshed with the rule cache
+	mxChecker  *MXChecker           // Optional deliverability signal, nil when disabled
+	noMXScore  int                  // Score added when the email domain has no MX records
+	shared     cache.Cache          // Optional cache broadcasting invalidations to other replicas, nil when disabled
+	instanceID string               // Tells this instance's own invalidations apart on the shared channel
 	cacheMutex sync.RWMutex
+
+	refreshFailures map[string]time.Time // First failed refresh per organization since its last successful one
+	clock           clock.Clock          // Source of the current time for cache ages and rule expiry
+}
+
+// orgRules holds one organization's cached rules.
+type orgRules struct {
+	byCategory map[string][]models.RiskRule
+	loadedAt   time.Time
 }
 
-// NewRiskEngine creates a new risk engine with repository and logger dependencies.
-func NewRiskEngine(riskRepo *repository.RiskRepository, logger *logger.Logger) *RiskEngine {
+// RuleCategories are the rule categories the cache loads and checks evaluate.
+var RuleCategories = []string{"EMAIL", "NAME", "PHONE"}
+
+// ruleInvalidationChannel carries "<instance ID>|<org ID>" for every rule cache invalidation.
+const ruleInvalidationChannel = "risk:rules:invalidate"
+
+// staleCacheFactor is how many TTLs a failing organization's cache may age before health degrades.
+const staleCacheFactor = 3
+
+// Flag formats select what a risk flag carries after its CATEGORY_TYPE prefix.
+const (
+	FlagFormatType     = "type"      // EMAIL_PATTERN_MATCH, rules of the same type share a flag
+	FlagFormatRuleID   = "rule_id"   // EMAIL_PATTERN_MATCH:<rule id>, stable across renames
+	FlagFormatRuleName = "rule_name" // EMAIL_PATTERN_MATCH:<RULE_NAME>, readable but changes with the name
+)
+
+// FlagNoMX marks an email whose domain has no MX records, it is not tied to a rule.
+const FlagNoMX = "EMAIL_NO_MX"
+
+// Feature flags gating risk logic under gradual rollout, evaluated per check by user ID.
+const (
+	FeatureDiminishingScoring = "diminishing_scoring" // Each further match adds half of what the previous one did
+	FeatureDedupFlagScores    = "dedup_flag_scores"   // RISK_DEDUP_FLAG_SCORES for the flag's users only
+)
+
+// KnownFeatures lists the feature flags the engine evaluates.
+var KnownFeatures = []string{FeatureDiminishingScoring, FeatureDedupFlagScores}
+
+// NewRiskEngine creates a new risk engine with repository, settings and logger dependencies.
+// flagFormat is one of the FlagFormat constants and should stay fixed, changing it splits flag analytics.
+func NewRiskEngine(riskRepo *repository.RiskRepository, settings *config.Settings, flagFormat string, logger *logger.Logger) *RiskEngine {
 	return &RiskEngine{
-		riskRepo:  riskRepo,
-		logger:    logger,
-		ruleCache: make(map[string][]models.RiskRule),
-		cacheTTL:  5 * time.Minute, // Cache rules for 5 minutes
+		riskRepo:   riskRepo,
+		logger:     logger,
+		ruleCache:  make(map[string]*orgRules),
+		settings:   settings,
+		flagFormat: flagFormat,
+		disposable: NewDisposableDomains(DefaultDisposableDomains()),
+
+		refreshFailures: make(map[string]time.Time),
+		clock:           clock.Real{},
 	}
 }
 
-// CheckRisk evaluates user data against all active risk rules.
+// SetClock makes the engine age its cache and expire rules against c instead of the system clock.
+func (re *RiskEngine) SetClock(c clock.Clock) {
+	re.clock = c
+}
+
+// EnableMXCheck adds the no-MX-records signal to every check, scoring it with score.
+func (re *RiskEngine) EnableMXCheck(checker *MXChecker, score int) {
+	re.mxChecker = checker
+	re.noMXScore = score
+}
+
+// EnableSharedInvalidation publishes every InvalidateCache through shared so all replicas drop the
+// organization's rules, and drops the rules other replicas invalidate until ctx is cancelled.
+func (re *RiskEngine) EnableSharedInvalidation(ctx context.Context, shared cache.Cache) error {
+	re.instanceID = uuid.New().String()
+	err := shared.Subscribe(ctx, ruleInvalidationChannel, func(message string) {
+		instanceID, orgID, ok := strings.Cut(message, "|")
+		if !ok || instanceID == re.instanceID {
+			return
+		}
+		re.dropCache(orgID)
+		re.logger.Info("Rule cache invalidated by another instance", "org_id", orgID)
+	})
+	if err != nil {
+		return err
+	}
+	re.shared = shared
+	return nil
+}
+
+// CheckRisk evaluates user data against the active risk rules of the request's organization.
 // returns a comprehensive risk assessment with flags, scores, and matched rules.
 func (re *RiskEngine) CheckRisk(ctx context.Context, req *pb_risk.RiskCheckRequest) (*models.RiskCheckResult, error) {
+	orgID := auth.OrgOrDefault(req.OrgId)
+
 	result := &models.RiskCheckResult{
 		CheckID:      generateCheckID(),
 		UserID:       req.UserId,
+		OrgID:        orgID,
 		IsRisky:      false,
 		RiskLevel:    "MINIMAL",
 		TotalScore:   0,
 		Reason:       "No risk factors detected",
 		Flags:        []models.RiskCheckFlag{},
 		MatchedRules: []models.RiskCheckRuleMatch{},
-		CheckedAt:    time.Now().UTC(),
+		CheckedAt:    re.clock.Now().UTC(),
 	}
 
 	// Refresh rules cache if needed
-	if err := re.refreshRulesCache(ctx); err != nil {
+	if err := re.refreshRulesCache(ctx, orgID); err != nil {
 		re.logger.ErrorCtx(ctx, "Failed to refresh rules cache", err)
 		return result, fmt.Errorf("failed to refresh rules cache: %w", err)
 	}
 
-	var flagStrings []string
 	var matchedRules []models.RiskRule
 
-	// Check email risks
-	emailScore, emailFlags, emailRules := re.checkEmailRisk(ctx, req.Email)
-	result.TotalScore += emailScore
-	flagStrings = append(flagStrings, emailFlags...)
-	matchedRules = append(matchedRules, emailRules...)
+	// Check email, name and phone risks
+	matchedRules = append(matchedRules, re.checkEmailRisk(ctx, orgID, req.Email)...)
+	matchedRules = append(matchedRules, re.checkNameRisk(ctx, orgID, req.FirstName, req.LastName)...)
+	matchedRules = append(matchedRules, re.checkPhoneRisk(ctx, orgID, req.Phone)...)
 
-	// Check name risks
-	nameScore, nameFlags, nameRules := re.checkNameRisk(ctx, req.FirstName, req.LastName)
-	result.TotalScore += nameScore
-	flagStrings = append(flagStrings, nameFlags...)
-	matchedRules = append(matchedRules, nameRules...)
+	contributions := re.scoreMatches(matchedRules, req.UserId)
+	for _, score := range contributions {
+		result.TotalScore += score
+	}
 
-	// Check phone risks
-	phoneScore, phoneFlags, phoneRules := re.checkPhoneRisk(ctx, req.Phone)
-	result.TotalScore += phoneScore
-	flagStrings = append(flagStrings, phoneFlags...)
-	matchedRules = append(matchedRules, phoneRules...)
+	// Deliverability is answered from cache, unknown domains are looked up for later checks
+	noMX := re.mxChecker != nil && re.mxChecker.HasNoMX(extractDomain(strings.ToLower(strings.TrimSpace(req.Email))))
+	if noMX {
+		result.TotalScore += re.noMXScore
+	}
 
 	// Determine risk level based on total score
 	result.RiskLevel, result.IsRisky = re.calculateRiskLevel(result.TotalScore)
 
-	for _, flagStr := range flagStrings {
+	// Each flag is reported once per check, the first rule producing it is recorded
+	flagStrings := make([]string, 0, len(matchedRules))
+	seenFlags := make(map[string]bool, len(matchedRules))
+	for _, rule := range matchedRules {
+		flagStr := re.flagFor(rule)
+		if seenFlags[flagStr] {
+			continue
+		}
+		seenFlags[flagStr] = true
+		flagStrings = append(flagStrings, flagStr)
 		result.Flags = append(result.Flags, models.RiskCheckFlag{
 			CheckID: result.CheckID,
+			RuleID:  rule.ID,
 			Flag:    flagStr,
 		})
 	}
+	if noMX {
+		flagStrings = append(flagStrings, FlagNoMX)
+		result.Flags = append(result.Flags, models.RiskCheckFlag{
+			CheckID: result.CheckID,
+			Flag:    FlagNoMX,
+		})
+	}
 
-	if len(matchedRules) > 0 {
-		reasons := make([]string, 0, len(matchedRules))
-		for _, rule := range matchedRules {
-			adjustedScore := int(float64(rule.Score) * rule.Confidence)
-
+	if len(matchedRules) > 0 || noMX {
+		reasons := make([]string, 0, len(matchedRules)+1)
+		for i, rule := range matchedRules {
 			result.MatchedRules = append(result.MatchedRules, models.RiskCheckRuleMatch{
 				CheckID:    result.CheckID,
 				RuleID:     rule.ID,
 				RuleName:   rule.Name,
-				ScoreAdded: adjustedScore,
+				ScoreAdded: contributions[i],
 			})
 
 			// Build reason string
-			reasons = append(reasons, fmt.Sprintf("%s (score: %d)", rule.Name, adjustedScore))
+			reasons = append(reasons, fmt.Sprintf("%s (score: %d)", rule.Name, contributions[i]))
+		}
+		if noMX {
+			reasons = append(reasons, fmt.Sprintf("Email domain has no MX records (score: %d)", re.noMXScore))
 		}
 		result.Reason = strings.Join(reasons, "; ")
 	}
 
 	re.logger.InfoCtx(ctx, "Risk check completed",
 		"user_id", req.UserId,
-		"email", maskEmail(req.Email),
+		"org_id", orgID,
+		"email", pii.MaskEmail(req.Email),
 		"total_score", result.TotalScore,
 		"risk_level", result.RiskLevel,
 		"is_risky", result.IsRisky,
 		"matched_rules", len(matchedRules),
+		"matched_categories", strings.Join(matchedCategories(matchedRules), ","),
 		"flags", strings.Join(flagStrings, ","),
+		"features", strings.Join(re.EnabledFeatures(req.UserId), ","),
 	)
 
 	return result, nil
 }
 
-// refreshRulesCache updates the in-memory rule cache when expired.
-func (re *RiskEngine) refreshRulesCache(ctx context.Context) error {
+// refreshRulesCache updates the in-memory rule cache of an organization when expired.
+// failures are remembered until the next successful refresh, see CheckCacheHealth.
+func (re *RiskEngine) refreshRulesCache(ctx context.Context, orgID string) (err error) {
 	re.cacheMutex.RLock()
-	cacheExpired := time.Since(re.cacheTime) >= re.cacheTTL
+	cached, ok := re.ruleCache[orgID]
+	cacheExpired := !ok || re.clock.Now().Sub(cached.loadedAt) >= re.settings.Current().RuleCacheTTL
 	re.cacheMutex.RUnlock()
 
 	if !cacheExpired {
@@ -131,47 +241,58 @@ func (re *RiskEngine) refreshRulesCache(ctx context.Context) error {
 
 	re.cacheMutex.Lock()
 	defer re.cacheMutex.Unlock()
+	defer func() {
+		if err == nil {
+			delete(re.refreshFailures, orgID)
+		} else if _, failing := re.refreshFailures[orgID]; !failing {
+			re.refreshFailures[orgID] = re.clock.Now()
+		}
+	}()
 
-	re.logger.InfoCtx(ctx, "Refreshing risk rules cache")
+	re.logger.InfoCtx(ctx, "Refreshing risk rules cache", "org_id", orgID)
 
 	newCache := make(map[string][]models.RiskRule)
 
 	// Load rules by category
-	categories := []string{"EMAIL", "NAME", "PHONE"}
-	for _, category := range categories {
-		rules, err := re.riskRepo.GetRulesByCategory(category)
+	for _, category := range RuleCategories {
+		rules, err := re.riskRepo.GetRulesByCategory(orgID, category)
 		if err != nil {
 			return fmt.Errorf("failed to load %s rules: %w", category, err)
 		}
 		newCache[category] = rules
 	}
 
-	re.ruleCache = newCache
-	re.cacheTime = time.Now()
+	disposable, err := re.riskRepo.GetDisposableDomains()
+	if err != nil {
+		return fmt.Errorf("failed to load disposable domains: %w", err)
+	}
+	if len(disposable) == 0 {
+		disposable = DefaultDisposableDomains()
+	}
+	re.disposable.Replace(disposable)
+
+	re.ruleCache[orgID] = &orgRules{byCategory: newCache, loadedAt: re.clock.Now()}
 
 	re.logger.InfoCtx(ctx, "Risk rules cache refreshed",
-		"email_rules", len(re.ruleCache["EMAIL"]),
-		"name_rules", len(re.ruleCache["NAME"]),
-		"phone_rules", len(re.ruleCache["PHONE"]),
-		"total_rules", len(re.ruleCache["EMAIL"])+len(re.ruleCache["NAME"])+len(re.ruleCache["PHONE"]),
+		"org_id", orgID,
+		"email_rules", len(newCache["EMAIL"]),
+		"name_rules", len(newCache["NAME"]),
+		"phone_rules", len(newCache["PHONE"]),
+		"total_rules", len(newCache["EMAIL"])+len(newCache["NAME"])+len(newCache["PHONE"]),
+		"disposable_domains", len(disposable),
 	)
 
 	return nil
 }
 
 // checkEmailRisk evaluates email addresses against email-specific risk rules.
-// returns the total score, flags, and matched rules for the email.
-func (re *RiskEngine) checkEmailRisk(ctx context.Context, email string) (int, []string, []models.RiskRule) {
-	var totalScore int
-	var flags []string
+// returns the rules the email matched.
+func (re *RiskEngine) checkEmailRisk(ctx context.Context, orgID, email string) []models.RiskRule {
 	var matchedRules []models.RiskRule
 
 	emailLower := strings.ToLower(strings.TrimSpace(email))
 
-	re.cacheMutex.RLock()
-	rules := make([]models.RiskRule, len(re.ruleCache["EMAIL"]))
-	copy(rules, re.ruleCache["EMAIL"])
-	re.cacheMutex.RUnlock()
+	rules := re.GetCachedRules(orgID, "EMAIL")
 
 	for _, rule := range rules {
 		matched, err := re.evaluateEmailRule(rule, emailLower)
@@ -185,26 +306,20 @@ func (re *RiskEngine) checkEmailRisk(ctx context.Context, email string) (int, []
 		if matched {
 			// Apply confidence scoring
 			adjustedScore := int(float64(rule.Score) * rule.Confidence)
-			totalScore += adjustedScore
-			flags = append(flags, fmt.Sprintf("EMAIL_%s", rule.Type))
+			if stopAt, ok := re.terminalScore(); ok && adjustedScore >= stopAt {
+				re.logTerminalMatch(ctx, rule, adjustedScore)
+				return []models.RiskRule{rule}
+			}
 			matchedRules = append(matchedRules, rule)
-
-			re.logger.InfoCtx(ctx, "Email risk rule matched",
-				"rule_id", rule.ID,
-				"rule_name", rule.Name,
-				"rule_type", rule.Type,
-				"score_added", adjustedScore,
-				"original_score", rule.Score,
-				"confidence", rule.Confidence,
-			)
+			re.logMatch(ctx, rule, adjustedScore)
 		}
 	}
 
-	return totalScore, flags, matchedRules
+	return matchedRules
 }
 
 // evaluateEmailRule determines if an email matches a specific risk rule.
-// supports blacklist, pattern matching, domain filtering, and containment checks.
+// supports blacklist, pattern matching, domain filtering, containment, confusable and disposable checks.
 func (re *RiskEngine) evaluateEmailRule(rule models.RiskRule, emailLower string) (bool, error) {
 	switch rule.Type {
 	case "EMAIL_BLACKLIST":
@@ -220,6 +335,10 @@ func (re *RiskEngine) evaluateEmailRule(rule models.RiskRule, emailLower string)
 		return strings.EqualFold(domain, strings.ToLower(rule.Value)), nil
 	case "CONTAINS":
 		return strings.Contains(emailLower, strings.ToLower(rule.Value)), nil
+	case "CONFUSABLE_MATCH":
+		return confusableMatch(emailLower, rule.Value), nil
+	case "DISPOSABLE_EMAIL":
+		return re.disposable.Contains(extractDomain(emailLower)), nil
 	default:
 		return false, fmt.Errorf("unknown email rule type: %s", rule.Type)
 	}
@@ -227,22 +346,18 @@ func (re *RiskEngine) evaluateEmailRule(rule models.RiskRule, emailLower string)
 
 // checkNameRisk evaluates user names against name-specific risk rules.
 // checks first name, last name, and full name combinations.
-func (re *RiskEngine) checkNameRisk(ctx context.Context, firstName, lastName string) (int, []string, []models.RiskRule) {
-	var totalScore int
-	var flags []string
+func (re *RiskEngine) checkNameRisk(ctx context.Context, orgID, firstName, lastName string) []models.RiskRule {
 	var matchedRules []models.RiskRule
 
-	firstNameLower := strings.ToLower(strings.TrimSpace(firstName))
-	lastNameLower := strings.ToLower(strings.TrimSpace(lastName))
+	normalize := re.settings.Current().NormalizeNames
+	firstNameLower := normalizeName(firstName, normalize)
+	lastNameLower := normalizeName(lastName, normalize)
 	fullName := strings.TrimSpace(firstNameLower + " " + lastNameLower)
 
-	re.cacheMutex.RLock()
-	rules := make([]models.RiskRule, len(re.ruleCache["NAME"]))
-	copy(rules, re.ruleCache["NAME"])
-	re.cacheMutex.RUnlock()
+	rules := re.GetCachedRules(orgID, "NAME")
 
 	for _, rule := range rules {
-		matched, err := re.evaluateNameRule(rule, firstNameLower, lastNameLower, fullName)
+		matched, err := re.evaluateNameRule(rule, firstNameLower, lastNameLower, fullName, normalize)
 		if err != nil {
 			re.logger.WarnCtx(ctx, "Failed to evaluate name rule",
 				"rule_id", rule.ID,
@@ -252,28 +367,27 @@ func (re *RiskEngine) checkNameRisk(ctx context.Context, firstName, lastName str
 
 		if matched {
 			adjustedScore := int(float64(rule.Score) * rule.Confidence)
-			totalScore += adjustedScore
-			flags = append(flags, fmt.Sprintf("NAME_%s", rule.Type))
+			if stopAt, ok := re.terminalScore(); ok && adjustedScore >= stopAt {
+				re.logTerminalMatch(ctx, rule, adjustedScore)
+				return []models.RiskRule{rule}
+			}
 			matchedRules = append(matchedRules, rule)
-
-			re.logger.InfoCtx(ctx, "Name risk rule matched",
-				"rule_id", rule.ID,
-				"rule_name", rule.Name,
-				"rule_type", rule.Type,
-				"score_added", adjustedScore,
-			)
+			re.logMatch(ctx, rule, adjustedScore)
 		}
 	}
 
-	return totalScore, flags, matchedRules
+	return matchedRules
 }
 
 // evaluateNameRule determines if a name matches a specific risk rule.
-// supports blacklists, pattern matching, and containment checks for names.
-func (re *RiskEngine) evaluateNameRule(rule models.RiskRule, firstNameLower, lastNameLower, fullName string) (bool, error) {
+// supports blacklists, pattern matching, containment and confusable checks for names.
+// names arrive already normalized, normalize applies the same folding to the rule value.
+func (re *RiskEngine) evaluateNameRule(rule models.RiskRule, firstNameLower, lastNameLower, fullName string, normalize bool) (bool, error) {
+	value := normalizeName(rule.Value, normalize)
+
 	switch rule.Type {
 	case "NAME_BLACKLIST":
-		return strings.EqualFold(fullName, strings.ToLower(rule.Value)), nil
+		return strings.EqualFold(fullName, value), nil
 	case "PATTERN_MATCH":
 		matched, err := regexp.MatchString(rule.Value, fullName)
 		if err != nil {
@@ -281,11 +395,13 @@ func (re *RiskEngine) evaluateNameRule(rule models.RiskRule, firstNameLower, las
 		}
 		return matched, nil
 	case "CONTAINS":
-		return strings.Contains(fullName, strings.ToLower(rule.Value)), nil
+		return strings.Contains(fullName, value), nil
 	case "FIRST_NAME_BLACKLIST":
-		return strings.EqualFold(firstNameLower, strings.ToLower(rule.Value)), nil
+		return strings.EqualFold(firstNameLower, value), nil
 	case "LAST_NAME_BLACKLIST":
-		return strings.EqualFold(lastNameLower, strings.ToLower(rule.Value)), nil
+		return strings.EqualFold(lastNameLower, value), nil
+	case "CONFUSABLE_MATCH":
+		return confusableMatch(fullName, rule.Value), nil
 	default:
 		return false, fmt.Errorf("unknown name rule type: %s", rule.Type)
 	}
@@ -293,18 +409,13 @@ func (re *RiskEngine) evaluateNameRule(rule models.RiskRule, firstNameLower, las
 
 // checkPhoneRisk evaluates phone numbers against phone-specific risk rules.
 // normalizes phone numbers and checks against various rule types.
-func (re *RiskEngine) checkPhoneRisk(ctx context.Context, phone string) (int, []string, []models.RiskRule) {
-	var totalScore int
-	var flags []string
+func (re *RiskEngine) checkPhoneRisk(ctx context.Context, orgID, phone string) []models.RiskRule {
 	var matchedRules []models.RiskRule
 
 	// Normalize phone number (remove spaces, dashes, etc.)
 	normalizedPhone := normalizePhoneNumber(phone)
 
-	re.cacheMutex.RLock()
-	rules := make([]models.RiskRule, len(re.ruleCache["PHONE"]))
-	copy(rules, re.ruleCache["PHONE"])
-	re.cacheMutex.RUnlock()
+	rules := re.GetCachedRules(orgID, "PHONE")
 
 	for _, rule := range rules {
 		matched, err := re.evaluatePhoneRule(rule, normalizedPhone)
@@ -317,20 +428,16 @@ func (re *RiskEngine) checkPhoneRisk(ctx context.Context, phone string) (int, []
 
 		if matched {
 			adjustedScore := int(float64(rule.Score) * rule.Confidence)
-			totalScore += adjustedScore
-			flags = append(flags, fmt.Sprintf("PHONE_%s", rule.Type))
+			if stopAt, ok := re.terminalScore(); ok && adjustedScore >= stopAt {
+				re.logTerminalMatch(ctx, rule, adjustedScore)
+				return []models.RiskRule{rule}
+			}
 			matchedRules = append(matchedRules, rule)
-
-			re.logger.InfoCtx(ctx, "Phone risk rule matched",
-				"rule_id", rule.ID,
-				"rule_name", rule.Name,
-				"rule_type", rule.Type,
-				"score_added", adjustedScore,
-			)
+			re.logMatch(ctx, rule, adjustedScore)
 		}
 	}
 
-	return totalScore, flags, matchedRules
+	return matchedRules
 }
 
 // evaluatePhoneRule determines if a phone number matches a specific risk rule.
@@ -352,63 +459,309 @@ func (re *RiskEngine) evaluatePhoneRule(rule models.RiskRule, normalizedPhone st
 	}
 }
 
+// MatchesInput reports whether rule matches a single raw input, prepared the way CheckRisk prepares it.
+// NAME inputs are read as "first last" and split on the first space.
+func (re *RiskEngine) MatchesInput(rule models.RiskRule, input string) (bool, error) {
+	switch rule.Category {
+	case "EMAIL":
+		return re.evaluateEmailRule(rule, strings.ToLower(strings.TrimSpace(input)))
+	case "NAME":
+		normalize := re.settings.Current().NormalizeNames
+		first, last, _ := strings.Cut(strings.TrimSpace(input), " ")
+		firstNameLower := normalizeName(first, normalize)
+		lastNameLower := normalizeName(last, normalize)
+		fullName := strings.TrimSpace(firstNameLower + " " + lastNameLower)
+		return re.evaluateNameRule(rule, firstNameLower, lastNameLower, fullName, normalize)
+	case "PHONE":
+		return re.evaluatePhoneRule(rule, normalizePhoneNumber(input))
+	default:
+		return false, fmt.Errorf("unknown rule category: %s", rule.Category)
+	}
+}
+
+// scoreMatches returns how much each matched rule adds to the total score, in the same order.
+// by default that is the rule's confidence-adjusted score. With DedupFlagScores only the highest
+// scoring rule per category and type counts, whatever the flag format, and with CategoryScoreCap each category stops adding once its
+// matches reach the cap, so correlated rules can't inflate the score. Users in the
+// diminishing_scoring rollout get each further match at half the score of the one before.
+func (re *RiskEngine) scoreMatches(rules []models.RiskRule, userID string) []int {
+	settings := re.settings.Current()
+	dedup := settings.DedupFlagScores || settings.FeatureFlags.Enabled(FeatureDedupFlagScores, userID)
+	contributions := make([]int, len(rules))
+
+	best := make(map[string]int) // dedup key -> index of its highest scoring rule
+	for i, rule := range rules {
+		contributions[i] = int(float64(rule.Score) * rule.Confidence)
+		if !dedup {
+			continue
+		}
+		key := dedupKey(rule)
+		if j, ok := best[key]; !ok || contributions[i] > contributions[j] {
+			best[key] = i
+		}
+	}
+
+	if dedup {
+		for i, rule := range rules {
+			if best[dedupKey(rule)] != i {
+				contributions[i] = 0
+			}
+		}
+	}
+
+	if settings.FeatureFlags.Enabled(FeatureDiminishingScoring, userID) {
+		ranked := make([]int, 0, len(rules))
+		for i := range rules {
+			if contributions[i] > 0 {
+				ranked = append(ranked, i)
+			}
+		}
+		sort.SliceStable(ranked, func(a, b int) bool { return contributions[ranked[a]] > contributions[ranked[b]] })
+		for rank, i := range ranked {
+			contributions[i] >>= rank
+		}
+	}
+
+	used := make(map[string]int) // category -> score added so far
+	for i, rule := range rules {
+		if settings.CategoryScoreCap > 0 {
+			contributions[i] = min(contributions[i], settings.CategoryScoreCap-used[rule.Category])
+		}
+		used[rule.Category] += contributions[i]
+	}
+	return contributions
+}
+
+// terminalScore returns the adjusted score at which a single match ends evaluation of its category.
+// ok is false when short-circuiting is disabled and every rule is evaluated.
+func (re *RiskEngine) terminalScore() (int, bool) {
+	settings := re.settings.Current()
+	return settings.RiskThresholds.Critical, settings.StopOnCriticalMatch
+}
+
+// logMatch records the details of a single matched rule at debug level.
+// production logs only carry the per-check summary, which keeps rule internals out of them.
+func (re *RiskEngine) logMatch(ctx context.Context, rule models.RiskRule, adjustedScore int) {
+	re.logger.DebugCtx(ctx, "Risk rule matched",
+		"rule_id", rule.ID,
+		"rule_name", rule.Name,
+		"rule_type", rule.Type,
+		"category", rule.Category,
+		"score_added", adjustedScore,
+		"original_score", rule.Score,
+		"confidence", rule.Confidence,
+	)
+}
+
+// logTerminalMatch records a decisive match that dropped the category's other matches.
+func (re *RiskEngine) logTerminalMatch(ctx context.Context, rule models.RiskRule, adjustedScore int) {
+	re.logger.DebugCtx(ctx, "Critical risk rule matched, skipping remaining rules in category",
+		"rule_id", rule.ID,
+		"rule_name", rule.Name,
+		"category", rule.Category,
+		"priority", rule.Priority,
+		"score_added", adjustedScore,
+	)
+}
+
+// flagFor names the flag a matched rule adds to the result according to the configured format.
+func (re *RiskEngine) flagFor(rule models.RiskRule) string {
+	flag := dedupKey(rule)
+	switch re.flagFormat {
+	case FlagFormatRuleID:
+		return flag + ":" + rule.ID
+	case FlagFormatRuleName:
+		return flag + ":" + flagNamePart(rule.Name)
+	default:
+		return flag
+	}
+}
+
+// dedupKey identifies rules raising the same kind of flag, it is the flag of the type format.
+// rule_id and rule_name flags are unique per rule and can't be used to dedup.
+func dedupKey(rule models.RiskRule) string {
+	return rule.Category + "_" + rule.Type
+}
+
 // calculateRiskLevel determines risk level and risky status based on total score.
-// uses predefined thresholds to classify risk from MINIMAL to CRITICAL.
+// uses the configured thresholds to classify risk from MINIMAL to CRITICAL.
 func (re *RiskEngine) calculateRiskLevel(totalScore int) (string, bool) {
+	thresholds := re.settings.Current().RiskThresholds
+
 	switch {
-	case totalScore >= 100:
+	case totalScore >= thresholds.Critical:
 		return "CRITICAL", true
-	case totalScore >= 80:
+	case totalScore >= thresholds.High:
 		return "HIGH", true
-	case totalScore >= 40:
+	case totalScore >= thresholds.Medium:
 		return "MEDIUM", true
-	case totalScore >= 20:
+	case totalScore >= thresholds.Low:
 		return "LOW", false
 	default:
 		return "MINIMAL", false
 	}
 }
 
-// GetCachedRules returns a copy of the currently cached rules for a category.
+// GetCachedRules returns a copy of an organization's currently cached rules for a category.
 // prevents external modification by returning a deep copy of cached rules.
-func (re *RiskEngine) GetCachedRules(category string) []models.RiskRule {
+func (re *RiskEngine) GetCachedRules(orgID, category string) []models.RiskRule {
 	re.cacheMutex.RLock()
 	defer re.cacheMutex.RUnlock()
 
-	rules, exists := re.ruleCache[category]
+	cached, exists := re.ruleCache[orgID]
 	if !exists {
 		return []models.RiskRule{}
 	}
+	rules := cached.byCategory[category]
 
-	// Return a copy to prevent external modification
-	result := make([]models.RiskRule, len(rules))
-	copy(result, rules)
+	// Return a copy to prevent external modification, rules that expired since the cache was loaded are left out
+	now := re.clock.Now()
+	result := make([]models.RiskRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.ExpiresAt == nil || rule.ExpiresAt.After(now) {
+			result = append(result, rule)
+		}
+	}
 	return result
 }
 
-// InvalidateCache forces a cache refresh on the next request.
-// resets the cache timestamp to trigger immediate rule reloading.
-func (re *RiskEngine) InvalidateCache() {
+// InvalidateCache forces a refresh of an organization's rules on its next request.
+// drops the organization's cache entry and, with shared invalidation, the other replicas' entries too.
+// replicas the invalidation doesn't reach refresh when their TTL expires.
+func (re *RiskEngine) InvalidateCache(orgID string) {
+	re.dropCache(orgID)
+
+	if re.shared == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := re.shared.Publish(ctx, ruleInvalidationChannel, re.instanceID+"|"+orgID); err != nil {
+		re.logger.Error("Failed to publish rule cache invalidation, other instances refresh on TTL", err, "org_id", orgID)
+	}
+}
+
+// dropCache removes an organization's cached rules from this instance.
+func (re *RiskEngine) dropCache(orgID string) {
 	re.cacheMutex.Lock()
 	defer re.cacheMutex.Unlock()
 
-	re.cacheTime = time.Time{} // Reset to zero time to force refresh
+	delete(re.ruleCache, orgID)
 }
 
-// GetCacheStats returns information about the current cache state.
+// CacheStats describes the rule cache at a point in time.
+type CacheStats struct {
+	LastUpdated time.Time      // Zero until the first refresh and again after InvalidateCache
+	Age         time.Duration  // Time since LastUpdated, zero when it is unset
+	TTL         time.Duration  // Current RISK_RULE_CACHE_TTL
+	RuleCounts  map[string]int // Cached rules per category
+}
+
+// GetCacheStats returns information about an organization's current cache state.
 // provides metrics about cache age, rule counts, and last update time.
-func (re *RiskEngine) GetCacheStats() map[string]interface{} {
+func (re *RiskEngine) GetCacheStats(orgID string) CacheStats {
 	re.cacheMutex.RLock()
 	defer re.cacheMutex.RUnlock()
 
-	return map[string]interface{}{
-		"cache_age_seconds": time.Since(re.cacheTime).Seconds(),
-		"cache_ttl_seconds": re.cacheTTL.Seconds(),
-		"email_rules_count": len(re.ruleCache["EMAIL"]),
-		"name_rules_count":  len(re.ruleCache["NAME"]),
-		"phone_rules_count": len(re.ruleCache["PHONE"]),
-		"last_updated":      re.cacheTime.Format(time.RFC3339),
+	stats := CacheStats{
+		TTL:        re.settings.Current().RuleCacheTTL,
+		RuleCounts: make(map[string]int),
+	}
+	cached, ok := re.ruleCache[orgID]
+	if !ok {
+		return stats
+	}
+
+	stats.LastUpdated = cached.loadedAt
+	stats.Age = re.clock.Now().Sub(cached.loadedAt)
+	for category, rules := range cached.byCategory {
+		stats.RuleCounts[category] = len(rules)
+	}
+	return stats
+}
+
+// CheckCacheHealth returns an error describing every organization whose rule cache effectively disables risk checks.
+// that is a cache holding no rules while the database has active ones, or a failing refresh that left
+// no cache or one older than staleCacheFactor TTLs. Returns nil when all cached organizations are healthy.
+func (re *RiskEngine) CheckCacheHealth() error {
+	ttl := re.settings.Current().RuleCacheTTL
+	now := re.clock.Now()
+	var problems, empty []string
+
+	re.cacheMutex.RLock()
+	for orgID, failedAt := range re.refreshFailures {
+		cached, ok := re.ruleCache[orgID]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("org %s: rule refresh failing since %s with no cached rules",
+				orgID, failedAt.Format(time.RFC3339)))
+		case now.Sub(cached.loadedAt) > staleCacheFactor*ttl:
+			problems = append(problems, fmt.Sprintf("org %s: rule cache is %s old, refresh failing since %s",
+				orgID, now.Sub(cached.loadedAt).Round(time.Second), failedAt.Format(time.RFC3339)))
+		}
+	}
+	for orgID, cached := range re.ruleCache {
+		total := 0
+		for _, rules := range cached.byCategory {
+			total += len(rules)
+		}
+		if total == 0 {
+			empty = append(empty, orgID)
+		}
+	}
+	re.cacheMutex.RUnlock()
+
+	// Counted outside the lock, a slow database must not block risk checks
+	for _, orgID := range empty {
+		active, err := re.riskRepo.CountActiveRules(orgID, RuleCategories)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("org %s: rule cache is empty and active rules can't be counted: %v", orgID, err))
+		} else if active > 0 {
+			problems = append(problems, fmt.Sprintf("org %s: rule cache is empty but %d rules are active", orgID, active))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// FeatureFlags returns the rollout state of every known feature flag, plus any other configured flag.
+// known flags missing from the configuration are reported at 0 percent.
+func (re *RiskEngine) FeatureFlags() []features.Flag {
+	set := features.Set{}
+	for _, name := range KnownFeatures {
+		set[name] = features.Flag{Name: name}
+	}
+	for name, flag := range re.settings.Current().FeatureFlags {
+		set[name] = flag
+	}
+	return set.List()
+}
+
+// EnabledFeatures returns the names of the known feature flags that are on for userID.
+func (re *RiskEngine) EnabledFeatures(userID string) []string {
+	flags := re.settings.Current().FeatureFlags
+	var enabled []string
+	for _, name := range KnownFeatures {
+		if flags.Enabled(name, userID) {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// matchedCategories returns the distinct categories of the matched rules in match order.
+func matchedCategories(rules []models.RiskRule) []string {
+	var categories []string
+	for _, rule := range rules {
+		if !slices.Contains(categories, rule.Category) {
+			categories = append(categories, rule.Category)
+		}
 	}
+	return categories
 }
 
 // extractDomain extracts the domain portion from an email address.
@@ -433,6 +786,24 @@ func normalizePhoneNumber(phone string) string {
 	return strings.TrimSpace(phone)
 }
 
+// flagNamePart turns a rule name into an upper-case token, e.g. "Temp mail (v2)" becomes TEMP_MAIL_V2.
+func flagNamePart(name string) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.ToUpper(strings.TrimSpace(name)) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if pendingSep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			pendingSep = false
+			continue
+		}
+		pendingSep = true
+	}
+	return b.String()
+}
+
 // isValidEmail performs basic email format validation using regex.
 func isValidEmail(email string) bool {
 	// Basic email validation regex
@@ -440,27 +811,6 @@ func isValidEmail(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
-// maskEmail obscures email addresses for secure logging.
-func maskEmail(email string) string {
-	if email == "" {
-		return ""
-	}
-
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		return "***"
-	}
-
-	username := parts[0]
-	domain := parts[1]
-
-	if len(username) <= 2 {
-		return "***@" + domain
-	}
-
-	return username[:2] + "***@" + domain
-}
-
 // generateCheckID creates a unique identifier for risk check operations.
 func generateCheckID() string {
 	return fmt.Sprintf("check_%d_%s", time.Now().Unix(), strings.ReplaceAll(uuid.New().String()[:8], "-", ""))
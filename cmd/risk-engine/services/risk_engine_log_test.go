@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// logLines decodes the JSON log entries written to buf, keyed by message.
+func logLines(t *testing.T, buf *bytes.Buffer) map[string][]map[string]any {
+	t.Helper()
+	lines := make(map[string][]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		msg, _ := entry["msg"].(string)
+		lines[msg] = append(lines[msg], entry)
+	}
+	buf.Reset()
+	return lines
+}
+
+func TestCheckRiskLogsMatchDetailsOnlyAtDebug(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, models.AutoMigrate)
+	repo := repository.NewRiskRepository(db)
+	for _, rule := range []models.RiskRule{
+		{ID: "r1", OrgID: "default", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 20, Confidence: 1, IsActive: true},
+		{ID: "r2", OrgID: "default", Name: "Test name", Category: "NAME", Type: "CONTAINS", Value: "test", Score: 10, Confidence: 1, IsActive: true},
+	} {
+		if err := repo.CreateRule(&rule); err != nil {
+			t.Fatalf("CreateRule() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	log := logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &buf})
+	engine := services.NewRiskEngine(repo, config.NewSettings(config.Reloadable{}), "rule_id", log)
+	check := func() {
+		_, err := engine.CheckRisk(context.Background(), &pb_risk.RiskCheckRequest{
+			UserId: "user-1", Email: "someone@blocked.example", FirstName: "Test", LastName: "User", OrgId: "default",
+		})
+		if err != nil {
+			t.Fatalf("CheckRisk() error = %v", err)
+		}
+	}
+
+	check()
+	lines := logLines(t, &buf)
+	if got := len(lines["Risk rule matched"]); got != 0 {
+		t.Errorf("%d per-match lines at info, want none", got)
+	}
+	summaries := lines["Risk check completed"]
+	if len(summaries) != 1 {
+		t.Fatalf("%d summary lines at info, want 1", len(summaries))
+	}
+	if summary := summaries[0]; summary["matched_rules"] != float64(2) || summary["matched_categories"] != "EMAIL,NAME" {
+		t.Errorf("summary = %v, want 2 matched rules in EMAIL,NAME", summary)
+	}
+	for _, entries := range lines {
+		for _, entry := range entries {
+			if _, ok := entry["rule_id"]; ok {
+				t.Errorf("rule details logged at info: %v", entry)
+			}
+		}
+	}
+
+	log.SetLevel("debug")
+	check()
+	lines = logLines(t, &buf)
+	matched := lines["Risk rule matched"]
+	if len(matched) != 2 || matched[0]["rule_id"] != "r1" || matched[1]["rule_id"] != "r2" {
+		t.Errorf("per-match lines at debug = %v, want one for each of r1 and r2", matched)
+	}
+	if got := len(lines["Risk check completed"]); got != 1 {
+		t.Errorf("%d summary lines at debug, want 1", got)
+	}
+}
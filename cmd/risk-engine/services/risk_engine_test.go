@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/cache"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/features"
+	"user-risk-system/pkg/logger"
+)
+
+func newScoringEngine(settings config.Reloadable, flagFormat string) *RiskEngine {
+	return NewRiskEngine(nil, config.NewSettings(settings), flagFormat, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+}
+
+func TestScoreMatchesDedupPerCategoryAndType(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "r1", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 40, Confidence: 1},
+		{ID: "r2", Name: "Blocked domain alias", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 60, Confidence: 1},
+		{ID: "r3", Name: "Contains test", Category: "EMAIL", Type: "CONTAINS", Score: 30, Confidence: 1},
+		{ID: "r4", Name: "Blocked name", Category: "NAME", Type: "DOMAIN_BLACKLIST", Score: 20, Confidence: 1},
+	}
+
+	for _, format := range []string{FlagFormatRuleID, FlagFormatRuleName, FlagFormatType} {
+		t.Run(format, func(t *testing.T) {
+			engine := newScoringEngine(config.Reloadable{DedupFlagScores: true}, format)
+
+			got := engine.scoreMatches(rules, "user-1")
+			if want := []int{0, 60, 30, 20}; !reflect.DeepEqual(got, want) {
+				t.Errorf("scoreMatches() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestScoreMatchesCategoryCap(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "r1", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 50, Confidence: 1},
+		{ID: "r2", Category: "EMAIL", Type: "CONTAINS", Score: 40, Confidence: 1},
+		{ID: "r3", Category: "PHONE", Type: "PATTERN_MATCH", Score: 30, Confidence: 1},
+	}
+	engine := newScoringEngine(config.Reloadable{CategoryScoreCap: 60}, FlagFormatRuleID)
+
+	got := engine.scoreMatches(rules, "user-1")
+	if want := []int{50, 10, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreMatches() = %v, want %v", got, want)
+	}
+}
+
+func TestScoreMatchesUncappedByDefault(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "r1", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 50, Confidence: 1},
+		{ID: "r2", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 40, Confidence: 0.5},
+	}
+	engine := newScoringEngine(config.Reloadable{}, FlagFormatRuleID)
+
+	got := engine.scoreMatches(rules, "user-1")
+	if want := []int{50, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreMatches() = %v, want %v", got, want)
+	}
+}
+
+func TestSharedInvalidationDropsOtherReplicasRules(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replicas := make([]*RiskEngine, 2)
+	for i := range replicas {
+		replicas[i] = newScoringEngine(config.Reloadable{}, FlagFormatRuleID)
+		shared := cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+		t.Cleanup(func() { shared.Close() })
+		if err := replicas[i].EnableSharedInvalidation(ctx, shared); err != nil {
+			t.Fatalf("EnableSharedInvalidation() error = %v", err)
+		}
+		replicas[i].ruleCache["default"] = &orgRules{}
+		replicas[i].ruleCache["other"] = &orgRules{}
+	}
+
+	replicas[1].InvalidateCache("default")
+
+	cached := func(re *RiskEngine, orgID string) bool {
+		re.cacheMutex.Lock()
+		defer re.cacheMutex.Unlock()
+		_, ok := re.ruleCache[orgID]
+		return ok
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for cached(replicas[0], "default") {
+		if time.Now().After(deadline) {
+			t.Fatal("invalidation published by one replica didn't reach the other")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cached(replicas[1], "default") {
+		t.Error("invalidating replica kept its own rules")
+	}
+	if !cached(replicas[0], "other") || !cached(replicas[1], "other") {
+		t.Error("invalidation dropped the rules of another organization")
+	}
+}
+
+func TestScoreMatchesDiminishingForAllowlistedUsers(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "r1", Category: "EMAIL", Type: "CONTAINS", Score: 20, Confidence: 1},
+		{ID: "r2", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Score: 80, Confidence: 1},
+		{ID: "r3", Category: "NAME", Type: "CONTAINS", Score: 40, Confidence: 1},
+	}
+	engine := newScoringEngine(config.Reloadable{FeatureFlags: features.Set{
+		FeatureDiminishingScoring: {Name: FeatureDiminishingScoring, Allowlist: []string{"user-1"}},
+	}}, FlagFormatRuleID)
+
+	if got, want := engine.scoreMatches(rules, "user-1"), []int{5, 80, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreMatches() for the allowlisted user = %v, want %v", got, want)
+	}
+	if got, want := engine.scoreMatches(rules, "user-2"), []int{20, 80, 40}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreMatches() outside the rollout = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// thresholds are the default risk level thresholds.
+var thresholds = config.RiskThresholds{Low: 20, Medium: 40, High: 80, Critical: 100}
+
+// newRuleEngine returns an engine with flagFormat flags evaluating rules of the default organization.
+func newRuleEngine(t *testing.T, settings config.Reloadable, flagFormat string, rules ...models.RiskRule) *services.RiskEngine {
+	t.Helper()
+	repo := repository.NewRiskRepository(testutil.NewSQLiteDB(t, models.AutoMigrate))
+	for _, rule := range rules {
+		rule.OrgID, rule.IsActive = "default", true
+		if rule.Confidence == 0 {
+			rule.Confidence = 1
+		}
+		if err := repo.CreateRule(&rule); err != nil {
+			t.Fatalf("CreateRule() error = %v", err)
+		}
+	}
+	return services.NewRiskEngine(repo, config.NewSettings(settings), flagFormat, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+}
+
+// checkRisk runs a check of req in the default organization.
+func checkRisk(t *testing.T, engine *services.RiskEngine, req *pb_risk.RiskCheckRequest) *models.RiskCheckResult {
+	t.Helper()
+	req.UserId, req.OrgId = "user-1", "default"
+	result, err := engine.CheckRisk(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CheckRisk() error = %v", err)
+	}
+	return result
+}
+
+// flags returns the flag names of result in order.
+func flags(result *models.RiskCheckResult) []string {
+	names := make([]string, 0, len(result.Flags))
+	for _, flag := range result.Flags {
+		names = append(names, flag.Flag)
+	}
+	return names
+}
+
+func TestStopOnCriticalMatchSkipsRemainingCategoryRules(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "critical", Name: "Blocked domain", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 100, Priority: 10},
+		{ID: "later", Name: "Contains someone", Category: "EMAIL", Type: "CONTAINS", Value: "someone", Score: 30},
+		{ID: "name", Name: "Test name", Category: "NAME", Type: "CONTAINS", Value: "test", Score: 10},
+	}
+
+	tests := []struct {
+		name      string
+		stop      bool
+		wantScore int
+		wantFlags []string
+	}{
+		{"full evaluation", false, 140, []string{"EMAIL_DOMAIN_BLACKLIST:critical", "EMAIL_CONTAINS:later", "NAME_CONTAINS:name"}},
+		{"short-circuit", true, 110, []string{"EMAIL_DOMAIN_BLACKLIST:critical", "NAME_CONTAINS:name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newRuleEngine(t, config.Reloadable{RiskThresholds: thresholds, StopOnCriticalMatch: tt.stop}, services.FlagFormatRuleID, rules...)
+
+			result := checkRisk(t, engine, &pb_risk.RiskCheckRequest{Email: "someone@blocked.example", FirstName: "Test", LastName: "User"})
+			if result.TotalScore != tt.wantScore || result.RiskLevel != "CRITICAL" {
+				t.Errorf("score = %d at %s, want %d at CRITICAL", result.TotalScore, result.RiskLevel, tt.wantScore)
+			}
+			if got := flags(result); !reflect.DeepEqual(got, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", got, tt.wantFlags)
+			}
+			if len(result.MatchedRules) != len(tt.wantFlags) {
+				t.Errorf("matched %d rules, want %d", len(result.MatchedRules), len(tt.wantFlags))
+			}
+		})
+	}
+}
+
+func TestPatternRulesRaiseDistinctFlags(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "digits", Name: "Numbered test account", Category: "EMAIL", Type: "PATTERN_MATCH", Value: `^test\d+`, Score: 30},
+		{ID: "plus", Name: "Plus addressing", Category: "EMAIL", Type: "PATTERN_MATCH", Value: `\+.*@`, Score: 20},
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{services.FlagFormatRuleID, []string{"EMAIL_PATTERN_MATCH:digits", "EMAIL_PATTERN_MATCH:plus"}},
+		{services.FlagFormatRuleName, []string{"EMAIL_PATTERN_MATCH:NUMBERED_TEST_ACCOUNT", "EMAIL_PATTERN_MATCH:PLUS_ADDRESSING"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			engine := newRuleEngine(t, config.Reloadable{RiskThresholds: thresholds}, tt.format, rules...)
+
+			result := checkRisk(t, engine, &pb_risk.RiskCheckRequest{Email: "test42+promo@example.com"})
+			if got := flags(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flags = %v, want %v", got, tt.want)
+			}
+			if result.TotalScore != 50 {
+				t.Errorf("score = %d, want both rules scored", result.TotalScore)
+			}
+		})
+	}
+}
+
+func TestNameRulesFoldDiacritics(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "jose", Name: "Blocked first name", Category: "NAME", Type: "FIRST_NAME_BLACKLIST", Value: "jose", Score: 40},
+		{ID: "soren", Name: "Blocked first name", Category: "NAME", Type: "FIRST_NAME_BLACKLIST", Value: "soren", Score: 40},
+	}
+
+	tests := []struct {
+		name      string
+		firstName string
+		normalize bool
+		want      []string
+	}{
+		{"accented name", "José", true, []string{"NAME_FIRST_NAME_BLACKLIST:jose"}},
+		{"full-width name", "Ｊｏｓｅ", true, []string{"NAME_FIRST_NAME_BLACKLIST:jose"}},
+		{"distinct letter is not folded", "Søren", true, []string{}},
+		{"folding disabled", "José", false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newRuleEngine(t, config.Reloadable{RiskThresholds: thresholds, NormalizeNames: tt.normalize}, services.FlagFormatRuleID, rules...)
+
+			result := checkRisk(t, engine, &pb_risk.RiskCheckRequest{FirstName: tt.firstName, LastName: "User"})
+			if got := flags(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flags for %q = %v, want %v", tt.firstName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfusableRulesMatchLookalikes(t *testing.T) {
+	rules := []models.RiskRule{
+		{ID: "paypal", Name: "PayPal lookalike", Category: "EMAIL", Type: "CONFUSABLE_MATCH", Value: "paypal", Score: 50},
+		{ID: "microsoft", Name: "Microsoft lookalike", Category: "EMAIL", Type: "CONFUSABLE_MATCH", Value: "microsoft", Score: 50},
+	}
+
+	tests := []struct {
+		name  string
+		email string
+		want  []string
+	}{
+		{"digit for letter", "support@paypa1.com", []string{"EMAIL_CONFUSABLE_MATCH:paypal"}},
+		{"cyrillic letters", "support@раypal.com", []string{"EMAIL_CONFUSABLE_MATCH:paypal"}},
+		{"rn for m", "admin@rnicrosoft.com", []string{"EMAIL_CONFUSABLE_MATCH:microsoft"}},
+		{"plain brand is left to contains rules", "support@paypal.com", []string{}},
+		{"unrelated address", "someone@example.com", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newRuleEngine(t, config.Reloadable{RiskThresholds: thresholds}, services.FlagFormatRuleID, rules...)
+
+			result := checkRisk(t, engine, &pb_risk.RiskCheckRequest{Email: tt.email})
+			if got := flags(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flags for %q = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisposableRuleFlagsOnlyDisposableDomains(t *testing.T) {
+	engine := newRuleEngine(t, config.Reloadable{RiskThresholds: thresholds}, services.FlagFormatRuleID,
+		models.RiskRule{ID: "disposable", Name: "Disposable email", Category: "EMAIL", Type: "DISPOSABLE_EMAIL", Score: 40})
+
+	tests := []struct {
+		name  string
+		email string
+		want  []string
+	}{
+		{"disposable domain", "someone@mailinator.com", []string{"EMAIL_DISPOSABLE_EMAIL:disposable"}},
+		{"subdomain of a disposable domain", "someone@inbox.Mailinator.com", []string{"EMAIL_DISPOSABLE_EMAIL:disposable"}},
+		{"legitimate domain", "someone@example.com", []string{}},
+		{"lookalike suffix", "someone@notmailinator.com", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkRisk(t, engine, &pb_risk.RiskCheckRequest{Email: tt.email})
+			if got := flags(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flags for %q = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/cmd/user/repository"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+// dbCounter makes the name of every in-memory database unique within the test binary.
+var dbCounter atomic.Uint64
+
+// stubRisk answers every risk check with response.
+type stubRisk struct {
+	pb_risk.RiskServiceClient
+	response *pb_risk.RiskCheckResponse
+}
+
+func (s stubRisk) CheckRisk(context.Context, *pb_risk.RiskCheckRequest, ...grpc.CallOption) (*pb_risk.RiskCheckResponse, error) {
+	if s.response == nil {
+		return &pb_risk.RiskCheckResponse{RiskLevel: "MINIMAL"}, nil
+	}
+	return s.response, nil
+}
+
+// recordingNotifier records every notification sent.
+type recordingNotifier struct {
+	pb_notification.NotificationServiceClient
+	mu   sync.Mutex
+	sent []*pb_notification.SendNotificationRequest
+}
+
+func (n *recordingNotifier) SendNotification(_ context.Context, req *pb_notification.SendNotificationRequest, _ ...grpc.CallOption) (*pb_notification.SendNotificationResponse, error) {
+	n.mu.Lock()
+	n.sent = append(n.sent, req)
+	n.mu.Unlock()
+	return &pb_notification.SendNotificationResponse{Success: true}, nil
+}
+
+// Sent returns the notifications sent so far.
+func (n *recordingNotifier) Sent() []*pb_notification.SendNotificationRequest {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*pb_notification.SendNotificationRequest(nil), n.sent...)
+}
+
+// testHandler is a user handler on an in-memory SQLite database.
+type testHandler struct {
+	*UserHandler
+	repo     *repository.UserRepository
+	notifier *recordingNotifier
+}
+
+// newTestHandler returns a user handler whose risk checks answer risk, MINIMAL when nil.
+func newTestHandler(t *testing.T, risk *pb_risk.RiskCheckResponse) *testHandler {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", name, dbCounter.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := user_models.AutoMigrate(db); err != nil {
+		t.Fatalf("failed to migrate sqlite database: %v", err)
+	}
+
+	repo := repository.NewUserRepository(db)
+	notifier := &recordingNotifier{}
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	h := NewUserHandler(repo, stubRisk{response: risk}, notifier, messaging.NewInMemory(), SessionPolicy{}, PasswordPolicy{HistoryDepth: 5}, string(auth.RoleUser), log)
+	return &testHandler{UserHandler: h, repo: repo, notifier: notifier}
+}
+
+// seedUser stores an active user with password123 and roles, user when none are given.
+func (h *testHandler) seedUser(t *testing.T, email string, roles ...string) *user_models.User {
+	t.Helper()
+
+	if len(roles) == 0 {
+		roles = []string{string(auth.RoleUser)}
+	}
+	user := &user_models.User{Email: email, OrgID: auth.DefaultOrgID, FirstName: "Test", LastName: "User", Roles: roles}
+	user.SetAccountStatus(user_models.AccountStatusActive)
+	if err := user.SetPassword("password123"); err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	if err := h.repo.Create(user); err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	return user
+}
+
+// reload returns the stored state of user.
+func (h *testHandler) reload(t *testing.T, user *user_models.User) *user_models.User {
+	t.Helper()
+
+	stored, err := h.repo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	return stored
+}
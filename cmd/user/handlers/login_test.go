@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/status"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	pb_user "user-risk-system/proto/user"
+)
+
+// mockUserRepository keeps users in memory and records the logins and sessions stored, without a database.
+// methods Login doesn't call are left to the embedded nil interface and panic if reached.
+type mockUserRepository struct {
+	UserRepository
+	mu       sync.Mutex
+	users    map[string]*user_models.User // email -> user
+	logins   []*user_models.LoginRecord
+	sessions []*user_models.Session
+}
+
+func (r *mockUserRepository) GetByEmail(email string) (*user_models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[email]
+	if !ok {
+		return nil, stderrors.New("record not found")
+	}
+	return user, nil
+}
+
+func (r *mockUserRepository) RecordLogin(user *user_models.User, record *user_models.LoginRecord, _ int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record.UserID = user.ID
+	r.logins = append(r.logins, record)
+	return nil
+}
+
+func (r *mockUserRepository) CreateSession(session *user_models.Session, _ int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.ID = "session-1"
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+// newMockUser returns a user with password123 in status.
+func newMockUser(t *testing.T, email, status string) *user_models.User {
+	t.Helper()
+	user := &user_models.User{ID: "user-" + email, Email: email, OrgID: auth.DefaultOrgID, FirstName: "Test", LastName: "User", Roles: []string{string(auth.RoleUser)}}
+	user.SetAccountStatus(status)
+	if err := user.SetPassword("password123"); err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	return user
+}
+
+func TestLoginWithMockRepository(t *testing.T) {
+	repo := &mockUserRepository{users: map[string]*user_models.User{}}
+	for _, user := range []*user_models.User{
+		newMockUser(t, "active@example.com", user_models.AccountStatusActive),
+		newMockUser(t, "suspended@example.com", user_models.AccountStatusSuspendedAdmin),
+		newMockUser(t, "closed@example.com", user_models.AccountStatusClosed),
+	} {
+		repo.users[user.Email] = user
+	}
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	h := NewUserHandler(repo, stubRisk{}, &recordingNotifier{}, messaging.NewInMemory(), SessionPolicy{}, PasswordPolicy{}, string(auth.RoleUser), log)
+
+	failures := []struct {
+		name     string
+		email    string
+		password string
+		want     *errors.AppError
+	}{
+		{"unknown email", "nobody@example.com", "password123", errors.ErrUserNotFound},
+		{"wrong password", "active@example.com", "wrong-password", errors.ErrInvalidPassword},
+		{"suspended account", "suspended@example.com", "password123", errors.ErrUserInactive},
+		{"closed account", "closed@example.com", "password123", errors.ErrUserInactive},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Login(context.Background(), &pb_user.LoginRequest{Email: tt.email, Password: tt.password})
+			if got, want := status.Code(err), tt.want.GRPCStatus().Code(); got != want {
+				t.Errorf("Login() code = %v, want %v (%v)", got, want, err)
+			}
+		})
+	}
+	// The gateway tells an inactive account apart from a bad password by the code alone
+	if errors.ErrUserInactive.GRPCStatus().Code() == errors.ErrInvalidPassword.GRPCStatus().Code() {
+		t.Errorf("inactive account and invalid password share code %v", errors.ErrUserInactive.GRPCStatus().Code())
+	}
+	if len(repo.logins) != 0 || len(repo.sessions) != 0 {
+		t.Fatalf("failed logins stored %d logins and %d sessions, want none", len(repo.logins), len(repo.sessions))
+	}
+
+	resp, err := h.Login(context.Background(), &pb_user.LoginRequest{
+		Email:    "active@example.com",
+		Password: "password123",
+		Client:   &pb_user.ClientInfo{IpAddress: "203.0.113.7", UserAgent: "test-agent"},
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if resp.User.Email != "active@example.com" || resp.SessionId != "session-1" || resp.RefreshToken == "" {
+		t.Errorf("Login() = user %s, session %q, refresh token set %v, want the user with a new session",
+			resp.User.Email, resp.SessionId, resp.RefreshToken != "")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if len(repo.logins) != 1 || repo.logins[0].UserID != "user-active@example.com" || repo.logins[0].IPAddress != "203.0.113.7" {
+		t.Errorf("recorded logins = %+v, want one with the client's IP address", repo.logins)
+	}
+	if len(repo.sessions) != 1 || repo.sessions[0].UserAgent != "test-agent" {
+		t.Errorf("created sessions = %+v, want one with the client's user agent", repo.sessions)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_user "user-risk-system/proto/user"
+)
+
+// minPasswordLength matches the length the gateway requires at registration.
+const minPasswordLength = 8
+
+// PasswordPolicy controls which passwords a user may change to.
+type PasswordPolicy struct {
+	HistoryDepth int // Previous passwords that can't be reused, the current one never can
+}
+
+// ChangePassword replaces the caller's password after checking the current one.
+// the new password must differ from the current one and the last HistoryDepth passwords.
+func (h *UserHandler) ChangePassword(ctx context.Context, req *pb_user.ChangePasswordRequest) (*pb_user.ChangePasswordResponse, error) {
+	userID, _ := scontext.UserID(ctx)
+	if userID == "" {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		return nil, errors.ErrValidationFailed.WithMessage("New password must be at least 8 characters").GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+	if !user.CheckPassword(req.CurrentPassword) {
+		h.logger.InfoCtx(ctx, "Password change with wrong current password")
+		return nil, errors.ErrInvalidPassword.GRPCStatus().Err()
+	}
+
+	history, err := h.userRepo.ListPasswordHistory(user.ID, h.passwords.HistoryDepth)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to load password history", err)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to change password").GRPCStatus().Err()
+	}
+	if user.PasswordUsed(req.NewPassword, history) {
+		h.logger.InfoCtx(ctx, "Password change to a recently used password")
+		return nil, errors.ErrPasswordReused.GRPCStatus().Err()
+	}
+
+	previousHash := user.PasswordHash
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to hash password", err)
+		passErr := errors.ErrPasswordHashFailed.WithDetails(err.Error())
+		return nil, passErr.GRPCStatus().Err()
+	}
+	if err := h.userRepo.ChangePassword(user, previousHash, h.passwords.HistoryDepth); err != nil {
+		if err == errors.ErrConcurrentUpdate {
+			return nil, errors.ErrConcurrentUpdate.GRPCStatus().Err()
+		}
+		h.logger.ErrorCtx(ctx, "Failed to change password", err)
+		updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
+		return nil, updateErr.GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Password changed", "audit", true)
+
+	return &pb_user.ChangePasswordResponse{}, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_user "user-risk-system/proto/user"
+)
+
+func TestChangePasswordRejectsRecentPasswords(t *testing.T) {
+	h := newTestHandler(t, nil)
+	h.passwords.HistoryDepth = 2
+	user := h.seedUser(t, "user@example.com")
+	ctx := scontext.New(context.Background()).WithUserAndRoles(user.ID, user.Email, user.Roles).Build()
+
+	current := "password123"
+	change := func(next string) error {
+		_, err := h.ChangePassword(ctx, &pb_user.ChangePasswordRequest{CurrentPassword: current, NewPassword: next})
+		if err == nil {
+			current = next
+		}
+		return err
+	}
+	reused := func(err error) bool {
+		return err != nil && status.Convert(err).Message() == errors.ErrPasswordReused.Message
+	}
+
+	if err := change("password123"); !reused(err) {
+		t.Errorf("change to the current password error = %v, want %s", err, errors.ErrPasswordReused.Message)
+	}
+	for _, next := range []string{"second-pass", "third-pass"} {
+		if err := change(next); err != nil {
+			t.Fatalf("change to never used %s error = %v", next, err)
+		}
+	}
+	for _, previous := range []string{"password123", "second-pass"} {
+		if err := change(previous); !reused(err) {
+			t.Errorf("change back to %s error = %v, want %s", previous, err, errors.ErrPasswordReused.Message)
+		}
+	}
+
+	if err := change("fourth-pass"); err != nil {
+		t.Fatalf("change to never used fourth-pass error = %v", err)
+	}
+	history, err := h.repo.ListPasswordHistory(user.ID, 10)
+	if err != nil {
+		t.Fatalf("ListPasswordHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("kept %d previous passwords, want the history pruned to 2", len(history))
+	}
+	// password123 fell out of the two most recent previous passwords
+	if err := change("password123"); err != nil {
+		t.Errorf("change to a password beyond the history error = %v", err)
+	}
+	if !h.reload(t, user).CheckPassword("password123") {
+		t.Error("stored password is not the last one changed to")
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/cmd/user/repository"
+)
+
+// UserRepository is the storage the user handlers need, implemented by repository.UserRepository.
+type UserRepository interface {
+	CreateWithEvent(user *user_models.User, queue string, event func(*user_models.User) interface{}) error
+	GetByID(id string) (*user_models.User, error)
+	GetByEmail(email string) (*user_models.User, error)
+	Update(user *user_models.User) error
+	List(orgID string, limit, offset int) ([]*user_models.User, error)
+
+	ReplaceEmailChange(change *user_models.EmailChange) error
+	GetEmailChangeByTokenHash(tokenHash string) (*user_models.EmailChange, error)
+	DeleteEmailChange(id string) error
+	ApplyEmailChange(user *user_models.User, change *user_models.EmailChange) error
+
+	CreateSession(session *user_models.Session, maxSessions int) error
+	GetSessionByTokenHash(tokenHash string) (*user_models.Session, error)
+	ListSessions(userID string) ([]*user_models.Session, error)
+	RotateSession(session *user_models.Session, previousHash string) error
+	DeleteSession(userID, sessionID string) error
+
+	RecordLogin(user *user_models.User, record *user_models.LoginRecord, keep int) error
+	ListLogins(userID string, limit int) ([]*user_models.LoginRecord, error)
+
+	ListPasswordHistory(userID string, limit int) ([]*user_models.PasswordHistory, error)
+	ChangePassword(user *user_models.User, previousHash string, keep int) error
+}
+
+var _ UserRepository = (*repository.UserRepository)(nil)
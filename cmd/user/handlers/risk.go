@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+)
+
+// CheckAndNotify runs a risk check on a stored user and, when risky, sends the risk alert before returning.
+// only admins may call it. Notification failures are best-effort: they don't fail the call but are
+// reported per channel, or in notification_error when nothing could be sent.
+func (h *UserHandler) CheckAndNotify(ctx context.Context, req *pb_user.CheckAndNotifyRequest) (*pb_user.CheckAndNotifyResponse, error) {
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(req.UserId)
+	if err != nil || !inCallerOrg(ctx, user) {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+
+	riskResp, err := h.checkRisk(ctx, user)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to check risk for user", err, "subject_user_id", user.ID)
+		return nil, errors.ErrInternalServerError.WithMessage("Risk check failed").GRPCStatus().Err()
+	}
+
+	resp := &pb_user.CheckAndNotifyResponse{
+		CheckId:   riskResp.CheckId,
+		IsRisky:   riskResp.IsRisky,
+		RiskLevel: riskResp.RiskLevel,
+		Reason:    riskResp.Reason,
+		Flags:     riskResp.Flags,
+	}
+	if !riskResp.IsRisky {
+		return resp, nil
+	}
+
+	notifyResp, err := h.notificationClient.SendNotification(ctx, riskNotificationRequest(user, riskResp))
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to send risk notification", err,
+			"subject_user_id", user.ID,
+			"check_id", riskResp.CheckId,
+		)
+		resp.NotificationError = err.Error()
+		return resp, nil
+	}
+
+	resp.Notified = notifyResp.Success
+	if notifyResp.Throttled {
+		resp.NotificationError = notifyResp.Error
+	}
+	for _, result := range notifyResp.ChannelResults {
+		resp.Notifications = append(resp.Notifications, &pb_user.NotificationResult{
+			Channel:  result.Channel,
+			Success:  result.Success,
+			Error:    result.Error,
+			Provider: result.Provider,
+		})
+	}
+
+	h.logger.InfoCtx(ctx, "Risk check and notify completed",
+		"subject_user_id", user.ID,
+		"check_id", riskResp.CheckId,
+		"risk_level", riskResp.RiskLevel,
+		"notified", resp.Notified,
+	)
+
+	return resp, nil
+}
+
+// EnableServiceAuth makes risk checks authenticate as the user service, which may check users of any organization.
+// without it the caller's token is forwarded.
+func (h *UserHandler) EnableServiceAuth(jwtManager *auth.JWTManager) {
+	h.serviceAuth = jwtManager
+}
+
+// checkRisk runs the risk check of a stored user.
+func (h *UserHandler) checkRisk(ctx context.Context, user *user_models.User) (*pb_risk.RiskCheckResponse, error) {
+	if h.serviceAuth != nil {
+		var err error
+		if ctx, err = h.serviceAuth.ServiceContext(ctx, "user-service"); err != nil {
+			return nil, err
+		}
+	}
+	return h.riskClient.CheckRisk(ctx, riskCheckRequest(user))
+}
+
+// riskCheckRequest builds the risk check of a stored user, scoped to the user's organization.
+func riskCheckRequest(user *user_models.User) *pb_risk.RiskCheckRequest {
+	return &pb_risk.RiskCheckRequest{
+		UserId:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Phone:     user.Phone,
+		OrgId:     user.OrgID,
+	}
+}
+
+// riskAction describes what happens to an account at the given risk level.
+func riskAction(riskLevel string) string {
+	switch riskLevel {
+	case "CRITICAL":
+		return "Account flagged for immediate review"
+	case "HIGH":
+		return "Account requires verification"
+	case "MEDIUM":
+		return "Account flagged for monitoring"
+	default:
+		return "Low risk detected"
+	}
+}
+
+// riskNotificationRequest builds the RISK_DETECTED alert sent to a risky user.
+func riskNotificationRequest(user *user_models.User, riskResp *pb_risk.RiskCheckResponse) *pb_notification.SendNotificationRequest {
+	return &pb_notification.SendNotificationRequest{
+		UserId:    user.ID,
+		OrgId:     user.OrgID,
+		Type:      "RISK_DETECTED",
+		Message:   fmt.Sprintf("Risk detected (%s): %s. Action: %s", riskResp.RiskLevel, riskResp.Reason, riskAction(riskResp.RiskLevel)),
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Phone:     user.Phone,
+		Locale:    user.Locale,
+		CheckId:   riskResp.CheckId,
+		Metadata:  map[string]string{"risk_level": riskResp.RiskLevel}, // Selects the critical throttle limit
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/scontext"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+)
+
+func TestCheckAndNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		risk         *pb_risk.RiskCheckResponse
+		wantNotified bool
+	}{
+		{"risky user is alerted", &pb_risk.RiskCheckResponse{IsRisky: true, RiskLevel: "HIGH", Reason: "Blocked domain", CheckId: "check-1"}, true},
+		{"non-risky user is not alerted", &pb_risk.RiskCheckResponse{IsRisky: false, RiskLevel: "LOW", Reason: "Contains test", CheckId: "check-2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, tt.risk)
+			user := h.seedUser(t, "user@example.com")
+			admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+			adminCtx := scontext.New(context.Background()).WithUserAndRoles(admin.ID, admin.Email, admin.Roles).WithOrgID(auth.DefaultOrgID).Build()
+
+			resp, err := h.CheckAndNotify(adminCtx, &pb_user.CheckAndNotifyRequest{UserId: user.ID})
+			if err != nil {
+				t.Fatalf("CheckAndNotify() error = %v", err)
+			}
+			if resp.IsRisky != tt.risk.IsRisky || resp.RiskLevel != tt.risk.RiskLevel || resp.CheckId != tt.risk.CheckId {
+				t.Errorf("CheckAndNotify() = risky %v at %s, check %q, want the risk check's result", resp.IsRisky, resp.RiskLevel, resp.CheckId)
+			}
+			if resp.Notified != tt.wantNotified {
+				t.Errorf("notified = %v, want %v", resp.Notified, tt.wantNotified)
+			}
+
+			sent := h.notifier.Sent()
+			if !tt.wantNotified {
+				if len(sent) != 0 {
+					t.Errorf("sent %d notifications for a non-risky user, want none", len(sent))
+				}
+				return
+			}
+			if len(sent) != 1 || sent[0].Type != "RISK_DETECTED" || sent[0].UserId != user.ID || sent[0].CheckId != tt.risk.CheckId {
+				t.Fatalf("sent %v, want one RISK_DETECTED alert for the user and check", sent)
+			}
+		})
+	}
+}
+
+func TestCheckAndNotifyRequiresAdmin(t *testing.T) {
+	h := newTestHandler(t, &pb_risk.RiskCheckResponse{IsRisky: true, RiskLevel: "HIGH"})
+	user := h.seedUser(t, "user@example.com")
+	userCtx := scontext.New(context.Background()).WithUserAndRoles(user.ID, user.Email, user.Roles).WithOrgID(auth.DefaultOrgID).Build()
+
+	_, err := h.CheckAndNotify(userCtx, &pb_user.CheckAndNotifyRequest{UserId: user.ID})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CheckAndNotify() by a user error = %v, want PermissionDenied", err)
+	}
+	if sent := h.notifier.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d notifications, want none", len(sent))
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	user_models "user-risk-system/cmd/user/models"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+func TestRiskStatusSurvivesConcurrentUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		handle func(h *testHandler, user *user_models.User, resp *pb_risk.RiskCheckResponse)
+		level  string
+		want   string
+	}{
+		{
+			name: "critical",
+			handle: func(h *testHandler, user *user_models.User, resp *pb_risk.RiskCheckResponse) {
+				h.handleCriticalRisk(context.Background(), user, resp)
+			},
+			level: "CRITICAL",
+			want:  user_models.AccountStatusSuspendedRisk,
+		},
+		{
+			name: "high",
+			handle: func(h *testHandler, user *user_models.User, resp *pb_risk.RiskCheckResponse) {
+				h.handleHighRisk(context.Background(), user, resp)
+			},
+			level: "HIGH",
+			want:  user_models.AccountStatusPendingVerification,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, nil)
+			registered := h.seedUser(t, tt.name+"@example.com")
+
+			// The user logs in between registration and the risk result, bumping the version
+			login := h.reload(t, registered)
+			now := time.Now()
+			login.LastLoginAt = &now
+			if err := h.repo.Update(login); err != nil {
+				t.Fatalf("concurrent update failed: %v", err)
+			}
+
+			tt.handle(h, registered, &pb_risk.RiskCheckResponse{UserId: registered.ID, IsRisky: true, RiskLevel: tt.level})
+
+			stored := h.reload(t, registered)
+			if stored.AccountStatus != tt.want {
+				t.Errorf("account status = %s, want %s", stored.AccountStatus, tt.want)
+			}
+			if stored.LastLoginAt == nil {
+				t.Error("the concurrent last login update was overwritten")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/scontext"
+	pb_user "user-risk-system/proto/user"
+)
+
+// waitForWelcomes waits until the background user created handling sent n notifications.
+func (h *testHandler) waitForWelcomes(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(h.notifier.Sent()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("sent %d notifications, want %d", len(h.notifier.Sent()), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCreateUserAssignsRolesForAdmins(t *testing.T) {
+	h := newTestHandler(t, nil)
+	admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+	adminCtx := scontext.New(context.Background()).WithUserAndRoles(admin.ID, admin.Email, admin.Roles).WithOrgID(auth.DefaultOrgID).Build()
+	userCtx := scontext.New(context.Background()).WithUserAndRoles("user-1", "user@example.com", []string{string(auth.RoleUser)}).WithOrgID(auth.DefaultOrgID).Build()
+
+	resp, err := h.CreateUser(adminCtx, &pb_user.CreateUserRequest{Email: "second-admin@example.com", FirstName: "Second", Roles: []string{" Admin", "user", "admin"}})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	stored, err := h.repo.GetByID(resp.User.Id)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if want := []string{"admin", "user"}; !reflect.DeepEqual([]string(stored.Roles), want) {
+		t.Errorf("stored roles = %v, want %v", stored.Roles, want)
+	}
+
+	resp, err = h.CreateUser(adminCtx, &pb_user.CreateUserRequest{Email: "plain@example.com", FirstName: "Plain"})
+	if err != nil {
+		t.Fatalf("CreateUser() without roles error = %v", err)
+	}
+	if !reflect.DeepEqual(resp.User.Roles, []string{string(auth.RoleUser)}) {
+		t.Errorf("roles = %v, want the default role", resp.User.Roles)
+	}
+	h.waitForWelcomes(t, 2)
+
+	rejected := []struct {
+		name  string
+		ctx   context.Context
+		roles []string
+		want  codes.Code
+	}{
+		{"non-admin assigning roles", userCtx, []string{string(auth.RoleAdmin)}, codes.PermissionDenied},
+		{"unknown role", adminCtx, []string{"superuser"}, codes.InvalidArgument},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.CreateUser(tt.ctx, &pb_user.CreateUserRequest{Email: "rejected@example.com", Roles: tt.roles})
+			if status.Code(err) != tt.want {
+				t.Errorf("CreateUser() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if user, _ := h.repo.GetByEmail("rejected@example.com"); user != nil {
+		t.Error("a rejected CreateUser stored the user")
+	}
+}
+
+func TestRegisterAssignsOnlyTheDefaultRole(t *testing.T) {
+	h := newTestHandler(t, nil)
+	h.defaultRole = string(auth.RoleModerator)
+
+	// Even an admin caller can't choose the roles of a self-registration
+	ctx := scontext.New(context.Background()).WithUserAndRoles("admin-1", "admin@example.com", []string{string(auth.RoleAdmin)}).Build()
+	resp, err := h.Register(ctx, &pb_user.RegisterRequest{Email: "self@example.com", Password: "password123", FirstName: "Self", LastName: "Registered"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	h.waitForWelcomes(t, 1)
+
+	stored, err := h.repo.GetByID(resp.User.Id)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if want := []string{string(auth.RoleModerator)}; !reflect.DeepEqual([]string(stored.Roles), want) {
+		t.Errorf("stored roles = %v, want only the configured default %v", stored.Roles, want)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_user "user-risk-system/proto/user"
+)
+
+// RefreshSession exchanges a refresh token for the session's user and a rotated refresh token.
+// the presented token stops working, so a leaked token is only usable until the owner refreshes.
+func (h *UserHandler) RefreshSession(ctx context.Context, req *pb_user.RefreshSessionRequest) (*pb_user.RefreshSessionResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	previousHash := hashToken(req.RefreshToken)
+	session, err := h.userRepo.GetSessionByTokenHash(previousHash)
+	if err != nil {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	ctx = scontext.New(ctx).WithUserID(session.UserID).WithSessionID(session.ID).Build()
+
+	now := time.Now()
+	if session.IsExpired(now) {
+		h.logger.InfoCtx(ctx, "Expired session refresh attempt")
+		h.userRepo.DeleteSession(session.UserID, session.ID)
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+	if !user.IsActive {
+		h.logger.ErrorCtx(ctx, "Inactive user session refresh attempt", nil, "account_status", user.AccountStatus)
+		h.userRepo.DeleteSession(session.UserID, session.ID)
+		return nil, inactiveError(user)
+	}
+
+	refreshToken, tokenHash, err := newToken()
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to generate refresh token", err)
+		return nil, errors.ErrInternalServerError.GRPCStatus().Err()
+	}
+
+	session.RefreshTokenHash = tokenHash
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(h.sessions.TTL)
+	if client := req.Client; client != nil {
+		session.UserAgent = client.UserAgent
+		session.IPAddress = client.IpAddress
+	}
+
+	// A concurrent refresh with the same token already rotated it
+	if err := h.userRepo.RotateSession(session, previousHash); err != nil {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	return &pb_user.RefreshSessionResponse{
+		User:         h.userToProto(user),
+		SessionId:    session.ID,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// ListSessions returns a user's active sessions via gRPC with role-based access control.
+// users can only list their own sessions unless they have admin privileges.
+func (h *UserHandler) ListSessions(ctx context.Context, req *pb_user.ListSessionsRequest) (*pb_user.ListSessionsResponse, error) {
+	userID, err := h.sessionOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := h.userRepo.ListSessions(userID)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list sessions", err)
+		return nil, errors.ErrInternalServerError.GRPCStatus().Err()
+	}
+
+	currentID, _ := scontext.SessionID(ctx)
+	pbSessions := make([]*pb_user.Session, 0, len(sessions))
+	for _, session := range sessions {
+		pbSessions = append(pbSessions, &pb_user.Session{
+			Id:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			LastSeenAt: timestamppb.New(session.LastSeenAt),
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+			Current:    session.ID == currentID,
+		})
+	}
+
+	return &pb_user.ListSessionsResponse{
+		Sessions: pbSessions,
+	}, nil
+}
+
+// RevokeSession deletes a session so its refresh token can no longer be used.
+// users can only revoke their own sessions unless they have admin privileges.
+func (h *UserHandler) RevokeSession(ctx context.Context, req *pb_user.RevokeSessionRequest) (*pb_user.RevokeSessionResponse, error) {
+	userID, err := h.sessionOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userRepo.DeleteSession(userID, req.SessionId); err != nil {
+		return nil, errors.ErrSessionNotFound.GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Session revoked", "revoked_session_id", req.SessionId)
+
+	return &pb_user.RevokeSessionResponse{}, nil
+}
+
+// sessionOwner resolves whose sessions a request targets, defaulting to the caller.
+// returns a permission error when a non-admin targets another user, and not found for users of another organization.
+func (h *UserHandler) sessionOwner(ctx context.Context, requested string) (string, error) {
+	userID, _ := scontext.UserID(ctx)
+
+	if requested == "" || requested == userID {
+		if userID == "" {
+			return "", errors.ErrInvalidToken.GRPCStatus().Err()
+		}
+		return userID, nil
+	}
+
+	if !auth.IsAdmin(ctx) {
+		return "", errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+	// Admins only manage sessions of their own organization
+	target, err := h.userRepo.GetByID(requested)
+	if err != nil || !inCallerOrg(ctx, target) {
+		return "", errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+	return requested, nil
+}
+
+// createSession starts a new session for user and returns it with its refresh token.
+// the repository evicts the oldest sessions when the policy limit is reached.
+func (h *UserHandler) createSession(user *user_models.User, client *pb_user.ClientInfo) (*user_models.Session, string, error) {
+	refreshToken, tokenHash, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &user_models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: tokenHash,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(h.sessions.TTL),
+		CreatedAt:        now,
+	}
+	if client != nil {
+		session.UserAgent = client.UserAgent
+		session.IPAddress = client.IpAddress
+	}
+
+	if err := h.userRepo.CreateSession(session, h.sessions.MaxPerUser); err != nil {
+		return nil, "", err
+	}
+	return session, refreshToken, nil
+}
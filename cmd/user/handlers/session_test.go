@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/scontext"
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListSessionsAdminStaysInOrganization(t *testing.T) {
+	h := newTestHandler(t, nil)
+	target := h.seedUser(t, "target@example.com")
+	admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+
+	adminOf := func(orgID string) context.Context {
+		return scontext.New(context.Background()).
+			WithUserAndRoles(admin.ID, admin.Email, admin.Roles).
+			WithOrgID(orgID).
+			Build()
+	}
+
+	if _, err := h.ListSessions(adminOf(auth.DefaultOrgID), &pb_user.ListSessionsRequest{UserId: target.ID}); err != nil {
+		t.Fatalf("ListSessions() in own organization error = %v", err)
+	}
+	_, err := h.ListSessions(adminOf("other-org"), &pb_user.ListSessionsRequest{UserId: target.ID})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ListSessions() of another organization error = %v, want NotFound", err)
+	}
+}
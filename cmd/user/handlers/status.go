@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	pb_notification "user-risk-system/proto/notification"
+	pb_user "user-risk-system/proto/user"
+)
+
+// SetAccountStatus suspends, closes or reactivates a user via the administrative gRPC endpoint.
+// admins can lift their own suspensions, risk suspensions go through ReactivateUser and closed accounts stay closed.
+func (h *UserHandler) SetAccountStatus(ctx context.Context, req *pb_user.SetAccountStatusRequest) (*pb_user.SetAccountStatusResponse, error) {
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	switch req.Status {
+	case user_models.AccountStatusActive, user_models.AccountStatusSuspendedAdmin, user_models.AccountStatusClosed:
+	default:
+		return nil, errors.ErrValidationFailed.WithMessage("Status must be one of ACTIVE, SUSPENDED_ADMIN, CLOSED").GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(req.UserId)
+	if err != nil || !inCallerOrg(ctx, user) {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+
+	previous := user.AccountStatus
+
+	switch {
+	case previous == user_models.AccountStatusClosed:
+		return nil, errors.ErrInvalidStatusTransition.WithMessage("Account is closed").GRPCStatus().Err()
+	case req.Status == user_models.AccountStatusActive && previous == user_models.AccountStatusSuspendedRisk:
+		return nil, errors.ErrInvalidStatusTransition.WithMessage("Account is suspended by a risk check, reactivate it after a review").GRPCStatus().Err()
+	case req.Status == user_models.AccountStatusActive && previous == user_models.AccountStatusPendingVerification:
+		return nil, errors.ErrInvalidStatusTransition.WithMessage("Account is pending verification").GRPCStatus().Err()
+	}
+
+	if previous != req.Status {
+		user.SetAccountStatus(req.Status)
+		if err := h.userRepo.Update(user); err != nil {
+			if err == errors.ErrConcurrentUpdate {
+				return nil, errors.ErrConcurrentUpdate.GRPCStatus().Err()
+			}
+			h.logger.ErrorCtx(ctx, "Failed to update account status", err)
+			updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
+			return nil, updateErr.GRPCStatus().Err()
+		}
+	}
+
+	h.logger.InfoCtx(ctx, "Account status changed by admin",
+		"audit", true,
+		"subject_user_id", user.ID,
+		"previous_status", previous,
+		"status", user.AccountStatus,
+		"reason", req.Reason,
+	)
+
+	return &pb_user.SetAccountStatusResponse{
+		User: h.userToProto(user),
+	}, nil
+}
+
+// ReactivateUser lifts the risk suspension of a user after an admin review and notifies the user.
+// only SUSPENDED_RISK accounts qualify, with recheck a user whose check is still CRITICAL stays suspended.
+func (h *UserHandler) ReactivateUser(ctx context.Context, req *pb_user.ReactivateUserRequest) (*pb_user.ReactivateUserResponse, error) {
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(req.UserId)
+	if err != nil || !inCallerOrg(ctx, user) {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+	if user.AccountStatus != user_models.AccountStatusSuspendedRisk {
+		return nil, errors.ErrInvalidStatusTransition.WithMessage("Only accounts suspended by a risk check can be reactivated, account is " + user.AccountStatus).GRPCStatus().Err()
+	}
+
+	resp := &pb_user.ReactivateUserResponse{}
+	if req.Recheck {
+		riskResp, err := h.checkRisk(ctx, user)
+		if err != nil {
+			h.logger.ErrorCtx(ctx, "Failed to check risk for reactivation", err, "subject_user_id", user.ID)
+			return nil, errors.ErrInternalServerError.WithMessage("Risk check failed").GRPCStatus().Err()
+		}
+		if riskResp.IsRisky && riskResp.RiskLevel == "CRITICAL" {
+			h.logger.InfoCtx(ctx, "Reactivation refused, user is still at critical risk",
+				"audit", true,
+				"subject_user_id", user.ID,
+				"check_id", riskResp.CheckId,
+			)
+			return nil, errors.ErrInvalidStatusTransition.WithMessage("User is still at CRITICAL risk, check " + riskResp.CheckId).GRPCStatus().Err()
+		}
+		resp.CheckId = riskResp.CheckId
+		resp.RiskLevel = riskResp.RiskLevel
+	}
+
+	user.SetAccountStatus(user_models.AccountStatusActive)
+	if err := h.userRepo.Update(user); err != nil {
+		if err == errors.ErrConcurrentUpdate {
+			return nil, errors.ErrConcurrentUpdate.GRPCStatus().Err()
+		}
+		h.logger.ErrorCtx(ctx, "Failed to reactivate user", err)
+		updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
+		return nil, updateErr.GRPCStatus().Err()
+	}
+	resp.User = h.userToProto(user)
+
+	h.logger.InfoCtx(ctx, "User reactivated after risk review",
+		"audit", true,
+		"subject_user_id", user.ID,
+		"check_id", resp.CheckId,
+		"risk_level", resp.RiskLevel,
+		"reason", req.Reason,
+	)
+
+	// The account is active either way, a failed notification is only logged
+	notifyResp, err := h.notificationClient.SendNotification(ctx, &pb_notification.SendNotificationRequest{
+		UserId:    user.ID,
+		OrgId:     user.OrgID,
+		Type:      "ACCOUNT_REACTIVATED",
+		Message:   "Your account has been reviewed and is active again.",
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Phone:     user.Phone,
+		Locale:    user.Locale,
+	})
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to send reactivation notification", err, "subject_user_id", user.ID)
+	} else {
+		resp.Notified = notifyResp.Success
+	}
+
+	return resp, nil
+}
+
+// inactiveError returns the error of a user whose account status doesn't allow logins, naming the reason.
+func inactiveError(user *user_models.User) error {
+	switch user.AccountStatus {
+	case user_models.AccountStatusSuspendedRisk:
+		return errors.ErrUserInactive.WithMessage("Account is suspended pending a security review").GRPCStatus().Err()
+	case user_models.AccountStatusSuspendedAdmin:
+		return errors.ErrUserInactive.WithMessage("Account is suspended by an administrator").GRPCStatus().Err()
+	case user_models.AccountStatusClosed:
+		return errors.ErrUserInactive.WithMessage("Account is closed").GRPCStatus().Err()
+	default:
+		return errors.ErrUserInactive.GRPCStatus().Err()
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/status"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+)
+
+// suspend stores user in accountStatus.
+func (h *testHandler) suspend(t *testing.T, user *user_models.User, accountStatus string) {
+	t.Helper()
+
+	user.SetAccountStatus(accountStatus)
+	if err := h.repo.Update(user); err != nil {
+		t.Fatalf("failed to suspend user: %v", err)
+	}
+}
+
+func TestReactivatedUserCanLogIn(t *testing.T) {
+	h := newTestHandler(t, &pb_risk.RiskCheckResponse{IsRisky: false, RiskLevel: "LOW", CheckId: "check-1"})
+	user := h.seedUser(t, "user@example.com")
+	admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+	adminCtx := scontext.New(context.Background()).WithUserAndRoles(admin.ID, admin.Email, admin.Roles).WithOrgID(auth.DefaultOrgID).Build()
+	h.suspend(t, user, user_models.AccountStatusSuspendedRisk)
+
+	login := &pb_user.LoginRequest{Email: user.Email, Password: "password123"}
+	if _, err := h.Login(context.Background(), login); status.Code(err) != errors.ErrUserInactive.GRPCStatus().Code() {
+		t.Fatalf("Login() of a suspended user error = %v, want inactive", err)
+	}
+
+	resp, err := h.ReactivateUser(adminCtx, &pb_user.ReactivateUserRequest{UserId: user.ID, Recheck: true})
+	if err != nil {
+		t.Fatalf("ReactivateUser() error = %v", err)
+	}
+	if resp.User.AccountStatus != user_models.AccountStatusActive || !resp.User.IsActive || resp.CheckId != "check-1" {
+		t.Errorf("ReactivateUser() = %s, active %v, check %q, want an active user and the recheck", resp.User.AccountStatus, resp.User.IsActive, resp.CheckId)
+	}
+
+	loginResp, err := h.Login(context.Background(), login)
+	if err != nil {
+		t.Fatalf("Login() after reactivation error = %v", err)
+	}
+	if loginResp.User.Id != user.ID || loginResp.SessionId == "" {
+		t.Errorf("Login() = user %s, session %q, want the user with a new session", loginResp.User.Id, loginResp.SessionId)
+	}
+}
+
+func TestReactivateUserRefusals(t *testing.T) {
+	tests := []struct {
+		name          string
+		risk          *pb_risk.RiskCheckResponse
+		accountStatus string
+		recheck       bool
+	}{
+		{"still at critical risk", &pb_risk.RiskCheckResponse{IsRisky: true, RiskLevel: "CRITICAL", CheckId: "check-1"}, user_models.AccountStatusSuspendedRisk, true},
+		{"suspended by an administrator", nil, user_models.AccountStatusSuspendedAdmin, false},
+		{"closed account", nil, user_models.AccountStatusClosed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, tt.risk)
+			user := h.seedUser(t, "user@example.com")
+			admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+			adminCtx := scontext.New(context.Background()).WithUserAndRoles(admin.ID, admin.Email, admin.Roles).WithOrgID(auth.DefaultOrgID).Build()
+			h.suspend(t, user, tt.accountStatus)
+
+			_, err := h.ReactivateUser(adminCtx, &pb_user.ReactivateUserRequest{UserId: user.ID, Recheck: tt.recheck})
+			if want := errors.ErrInvalidStatusTransition.GRPCStatus().Code(); status.Code(err) != want {
+				t.Fatalf("ReactivateUser() error = %v, want %v", err, want)
+			}
+			if got := h.reload(t, user).AccountStatus; got != tt.accountStatus {
+				t.Errorf("account status = %s, want it left at %s", got, tt.accountStatus)
+			}
+			if _, err := h.Login(context.Background(), &pb_user.LoginRequest{Email: user.Email, Password: "password123"}); err == nil {
+				t.Error("Login() succeeded for an account that was not reactivated")
+			}
+		})
+	}
+}
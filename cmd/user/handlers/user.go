@@ -2,18 +2,22 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	user_models "user-risk-system/cmd/user/models"
-	"user-risk-system/cmd/user/repository"
 	"user-risk-system/pkg/auth"
 	"user-risk-system/pkg/errors"
 	"user-risk-system/pkg/logger"
 	"user-risk-system/pkg/messaging"
 	"user-risk-system/pkg/models"
+	"user-risk-system/pkg/pii"
 	"user-risk-system/pkg/scontext"
 	pb_notification "user-risk-system/proto/notification"
 	pb_risk "user-risk-system/proto/risk"
@@ -24,19 +28,32 @@ import (
 // handles authentication, user management, and orchestrates risk assessment and notifications.
 type UserHandler struct {
 	pb_user.UnimplementedUserServiceServer
-	userRepo           *repository.UserRepository
+	userRepo           UserRepository
 	riskClient         pb_risk.RiskServiceClient
 	notificationClient pb_notification.NotificationServiceClient
-	messageQueue       *messaging.RabbitMQ
+	messageQueue       messaging.Messaging
+	sessions           SessionPolicy
+	passwords          PasswordPolicy
+	defaultRole        string           // Role of new users not given any, never an elevated role
+	serviceAuth        *auth.JWTManager // Signs the service token of risk checks, see EnableServiceAuth
 	logger             *logger.Logger
 }
 
+// SessionPolicy controls the lifetime and number of login sessions per user.
+type SessionPolicy struct {
+	TTL        time.Duration // Refresh token lifetime, extended on every refresh
+	MaxPerUser int           // Oldest sessions are evicted beyond this, 0 is unlimited
+}
+
 // NewUserHandler creates a new user handler with all required dependencies.
 func NewUserHandler(
-	userRepo *repository.UserRepository,
+	userRepo UserRepository,
 	riskClient pb_risk.RiskServiceClient,
 	notificationClient pb_notification.NotificationServiceClient,
-	messageQueue *messaging.RabbitMQ,
+	messageQueue messaging.Messaging,
+	sessions SessionPolicy,
+	passwords PasswordPolicy,
+	defaultRole string,
 	appLogger *logger.Logger,
 ) *UserHandler {
 	return &UserHandler{
@@ -44,14 +61,21 @@ func NewUserHandler(
 		riskClient:         riskClient,
 		notificationClient: notificationClient,
 		messageQueue:       messageQueue,
+		sessions:           sessions,
+		passwords:          passwords,
+		defaultRole:        defaultRole,
 		logger:             appLogger,
 	}
 }
 
+const (
+	loginHistoryPerUser = 20 // Logins kept per user, older ones are removed on login
+	recentLoginsLimit   = 10 // Logins returned by GetUser
+)
+
 // Login authenticates a user with email and password via gRPC.
 // validates credentials, updates login timestamp, and triggers risk assessment.
 func (h *UserHandler) Login(ctx context.Context, req *pb_user.LoginRequest) (*pb_user.LoginResponse, error) {
-	ctx = scontext.New(ctx).WithUserEmail(req.Email).Build()
 	h.logger.InfoCtx(ctx, "Login attempt for email")
 
 	user, err := h.userRepo.GetByEmail(req.Email)
@@ -61,12 +85,8 @@ func (h *UserHandler) Login(ctx context.Context, req *pb_user.LoginRequest) (*pb
 	}
 
 	if !user.IsActive {
-		h.logger.ErrorCtx(ctx, "Inactive user login attempt", nil)
-		inactiveErr := &errors.AppError{
-			Code:    "USER_INACTIVE",
-			Message: "Account is deactivated",
-		}
-		return nil, inactiveErr.GRPCStatus().Err()
+		h.logger.ErrorCtx(ctx, "Inactive user login attempt", nil, "account_status", user.AccountStatus)
+		return nil, inactiveError(user)
 	}
 
 	if !user.CheckPassword(req.Password) {
@@ -74,27 +94,43 @@ func (h *UserHandler) Login(ctx context.Context, req *pb_user.LoginRequest) (*pb
 		return nil, errors.ErrInvalidPassword.GRPCStatus().Err()
 	}
 
-	go h.checkLoginRisk(user)
+	go h.checkLoginRisk(scontext.Detach(ctx), user)
 
 	now := time.Now()
+	record := &user_models.LoginRecord{CreatedAt: now}
+	if req.Client != nil {
+		record.IPAddress = req.Client.IpAddress
+		record.UserAgent = req.Client.UserAgent
+	}
 	user.LastLoginAt = &now
-	if err := h.userRepo.Update(user); err != nil {
+	user.LastLoginIP = record.IPAddress
+	user.LastLoginUserAgent = record.UserAgent
+	if err := h.userRepo.RecordLogin(user, record, loginHistoryPerUser); err != nil {
 		// Don't fail login for this, just log it
-		h.logger.ErrorCtx(ctx, "Failed to update last login time", err)
+		h.logger.ErrorCtx(ctx, "Failed to record login", err)
 	}
 
 	h.logger.InfoCtx(ctx, "Successful login")
 
 	pbUser := h.userToProto(user)
-	return &pb_user.LoginResponse{
+	resp := &pb_user.LoginResponse{
 		User: pbUser,
-	}, nil
+	}
+
+	// Without a session the login still succeeds, the client just can't refresh
+	if session, refreshToken, err := h.createSession(user, req.Client); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to create session", err)
+	} else {
+		resp.SessionId = session.ID
+		resp.RefreshToken = refreshToken
+	}
+
+	return resp, nil
 }
 
 // Register creates a new user account via gRPC with automatic risk assessment.
 // validates uniqueness, hashes passwords, and triggers welcome notifications.
 func (h *UserHandler) Register(ctx context.Context, req *pb_user.RegisterRequest) (*pb_user.RegisterResponse, error) {
-	ctx = scontext.New(ctx).WithUserEmail(req.Email).Build()
 	h.logger.InfoCtx(ctx, "Registration attempt for email")
 
 	existingUser, _ := h.userRepo.GetByEmail(req.Email)
@@ -105,14 +141,16 @@ func (h *UserHandler) Register(ctx context.Context, req *pb_user.RegisterRequest
 
 	user := &user_models.User{
 		Email:      req.Email,
+		OrgID:      auth.DefaultOrgID, // Self-registration always joins the default organization
 		FirstName:  req.FirstName,
 		LastName:   req.LastName,
 		Phone:      req.Phone,
-		Roles:      []string{string(auth.RoleUser)}, // Default role
-		IsActive:   true,
+		Roles:      []string{h.defaultRole}, // Registration can't request roles
+		Locale:     localeOrDefault(req.Locale),
 		IsVerified: false,
 		CreatedAt:  time.Now(),
 	}
+	user.SetAccountStatus(user_models.AccountStatusActive)
 
 	if err := user.SetPassword(req.Password); err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to hash password", err)
@@ -120,7 +158,7 @@ func (h *UserHandler) Register(ctx context.Context, req *pb_user.RegisterRequest
 		return nil, passErr.GRPCStatus().Err()
 	}
 
-	if err := h.userRepo.Create(user); err != nil {
+	if err := h.userRepo.CreateWithEvent(user, "user.created", userCreatedEvent); err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to create user", err)
 		createErr := errors.ErrUserCreateFailed.WithDetails(err.Error())
 		return nil, createErr.GRPCStatus().Err()
@@ -131,12 +169,20 @@ func (h *UserHandler) Register(ctx context.Context, req *pb_user.RegisterRequest
 
 	pbUser := h.userToProto(user)
 
-	go h.handleUserCreatedAsync(user)
-	go h.handleUserCreatedSync(user)
+	go h.handleUserCreatedSync(scontext.Detach(ctx), user)
 
-	return &pb_user.RegisterResponse{
+	resp := &pb_user.RegisterResponse{
 		User: pbUser,
-	}, nil
+	}
+
+	if session, refreshToken, err := h.createSession(user, req.Client); err != nil {
+		h.logger.ErrorCtx(ctxWithUserId, "Failed to create session", err)
+	} else {
+		resp.SessionId = session.ID
+		resp.RefreshToken = refreshToken
+	}
+
+	return resp, nil
 }
 
 // CreateUser creates a new user account via administrative gRPC endpoint.
@@ -145,6 +191,11 @@ func (h *UserHandler) CreateUser(ctx context.Context, req *pb_user.CreateUserReq
 	ctx = scontext.New(ctx).WithUserEmail(req.Email).Build()
 	h.logger.InfoCtx(ctx, "Admin creating user")
 
+	roles, err := h.assignedRoles(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if user already exists
 	existingUser, _ := h.userRepo.GetByEmail(req.Email)
 	if existingUser != nil {
@@ -154,98 +205,150 @@ func (h *UserHandler) CreateUser(ctx context.Context, req *pb_user.CreateUserReq
 
 	user := &user_models.User{
 		Email:     req.Email,
+		OrgID:     auth.OrgID(ctx), // Admins create users in their own organization
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
-		Roles:     []string{string(auth.RoleUser)}, // Default role
-		IsActive:  true,
+		Roles:     roles,
+		Locale:    localeOrDefault(req.Locale),
 		CreatedAt: time.Now(),
 	}
+	user.SetAccountStatus(user_models.AccountStatusActive)
 
-	if err := h.userRepo.Create(user); err != nil {
+	if err := h.userRepo.CreateWithEvent(user, "user.created", userCreatedEvent); err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to create user", err)
 		createErr := errors.ErrUserCreateFailed.WithDetails(err.Error())
 		return nil, createErr.GRPCStatus().Err()
 	}
 
 	ctxWithUserId := scontext.WithUserID(ctx, user.ID).Build()
-	h.logger.InfoCtx(ctxWithUserId, "User created successfully by admin")
+	h.logger.InfoCtx(ctxWithUserId, "User created successfully by admin", "audit", true, "roles", roles)
 
 	pbUser := h.userToProto(user)
 
-	go h.handleUserCreatedAsync(user)
-	go h.handleUserCreatedSync(user)
+	go h.handleUserCreatedSync(scontext.Detach(ctx), user)
 
 	return &pb_user.CreateUserResponse{
 		User: pbUser,
 	}, nil
 }
 
-// GetUser retrieves user information via gRPC with role-based access control.
-// Users can only access their own data unless they have admin privileges.
-func (h *UserHandler) GetUser(ctx context.Context, req *pb_user.GetUserRequest) (*pb_user.GetUserResponse, error) {
-	userID := ctx.Value("user_id").(string)
-	userRoles := ctx.Value("user_roles").([]string)
-
-	isAdmin := false
-	for _, role := range userRoles {
-		if role == string(auth.RoleAdmin) {
-			isAdmin = true
-			break
+// assignedRoles returns the roles of a user created with the requested roles, the default role when none are.
+// only admins may choose roles, and every role must be a known one.
+func (h *UserHandler) assignedRoles(ctx context.Context, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return []string{h.defaultRole}, nil
+	}
+
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.WithMessage("Only admins can assign roles").GRPCStatus().Err()
+	}
+
+	roles := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, role := range requested {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if !auth.KnownRole(role) {
+			return nil, errors.ErrValidationFailed.WithMessage(fmt.Sprintf("Unknown role %q", role)).GRPCStatus().Err()
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
 		}
 	}
+	return roles, nil
+}
 
+// GetUser retrieves user information via gRPC with role-based access control.
+// Users can only access their own data unless they have admin privileges.
+func (h *UserHandler) GetUser(ctx context.Context, req *pb_user.GetUserRequest) (*pb_user.GetUserResponse, error) {
+	userID, _ := scontext.UserID(ctx)
 	// Users can only access their own data unless they're admin
-	if req.Id != userID && !isAdmin {
+	if req.Id != userID && !auth.IsAdmin(ctx) {
 		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
 	}
 
 	user, err := h.userRepo.GetByID(req.Id)
-	if err != nil {
+	if err != nil || !inCallerOrg(ctx, user) {
 		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
 	}
 
 	pbUser := h.userToProto(user)
-	return &pb_user.GetUserResponse{
+	resp := &pb_user.GetUserResponse{
 		User: pbUser,
+	}
+
+	// The user is still returned without their login history
+	logins, err := h.userRepo.ListLogins(user.ID, recentLoginsLimit)
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list recent logins", err)
+	}
+	for _, login := range logins {
+		resp.RecentLogins = append(resp.RecentLogins, &pb_user.LoginRecord{
+			IpAddress: login.IPAddress,
+			UserAgent: login.UserAgent,
+			CreatedAt: timestamppb.New(login.CreatedAt),
+		})
+	}
+
+	return resp, nil
+}
+
+// GetUserByEmail retrieves a user by email address via the administrative gRPC endpoint.
+// admin-only; the email is masked in logs since it identifies the user being looked up.
+func (h *UserHandler) GetUserByEmail(ctx context.Context, req *pb_user.GetUserByEmailRequest) (*pb_user.GetUserByEmailResponse, error) {
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	if req.Email == "" {
+		return nil, errors.ErrValidationFailed.WithMessage("Email is required").GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByEmail(req.Email)
+	if err != nil || !inCallerOrg(ctx, user) {
+		h.logger.InfoCtx(ctx, "User lookup by email found no user", "email", pii.MaskEmail(req.Email))
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "User lookup by email", "email", pii.MaskEmail(req.Email), "found_user_id", user.ID)
+
+	return &pb_user.GetUserByEmailResponse{
+		User: h.userToProto(user),
 	}, nil
 }
 
 // UpdateUser modifies user information via gRPC with role-based access control.
 // Users can only update their own data unless they have admin privileges.
 func (h *UserHandler) UpdateUser(ctx context.Context, req *pb_user.UpdateUserRequest) (*pb_user.UpdateUserResponse, error) {
-	userID := ctx.Value("user_id").(string)
-	userRoles := ctx.Value("user_roles").([]string)
-
-	isAdmin := false
-	for _, role := range userRoles {
-		if role == string(auth.RoleAdmin) {
-			isAdmin = true
-			break
-		}
-	}
-
-	if req.Id != userID && !isAdmin {
+	userID, _ := scontext.UserID(ctx)
+	if req.Id != userID && !auth.IsAdmin(ctx) {
 		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
 	}
 
 	user, err := h.userRepo.GetByID(req.Id)
-	if err != nil {
+	if err != nil || !inCallerOrg(ctx, user) {
 		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
 	}
 
-	// Update user fields
-	if req.FirstName != "" {
-		user.FirstName = req.FirstName
+	// Update only the fields present in the request, names can't be cleared
+	if req.FirstName != nil && *req.FirstName != "" {
+		user.FirstName = *req.FirstName
 	}
-	if req.LastName != "" {
-		user.LastName = req.LastName
+	if req.LastName != nil && *req.LastName != "" {
+		user.LastName = *req.LastName
 	}
-	if req.Phone != "" {
-		user.Phone = req.Phone
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.Locale != nil {
+		user.Locale = localeOrDefault(*req.Locale)
 	}
 
 	if err := h.userRepo.Update(user); err != nil {
+		if err == errors.ErrConcurrentUpdate {
+			return nil, errors.ErrConcurrentUpdate.GRPCStatus().Err()
+		}
 		updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
 		return nil, updateErr.GRPCStatus().Err()
 	}
@@ -257,6 +360,163 @@ func (h *UserHandler) UpdateUser(ctx context.Context, req *pb_user.UpdateUserReq
 	}, nil
 }
 
+// RequestEmailChange starts an email change by sending a verification token to the new address.
+// the current email stays active until ConfirmEmailChange is called with that token.
+func (h *UserHandler) RequestEmailChange(ctx context.Context, req *pb_user.RequestEmailChangeRequest) (*pb_user.RequestEmailChangeResponse, error) {
+	userID, _ := scontext.UserID(ctx)
+	if req.Id != userID && !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(req.Id)
+	if err != nil || !inCallerOrg(ctx, user) {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+
+	if req.NewEmail == "" || req.NewEmail == user.Email {
+		return nil, errors.ErrValidationFailed.WithMessage("New email must differ from the current email").GRPCStatus().Err()
+	}
+
+	existingUser, _ := h.userRepo.GetByEmail(req.NewEmail)
+	if existingUser != nil {
+		h.logger.ErrorCtx(ctx, "Email change to an address already in use", nil)
+		return nil, errors.ErrEmailExists.GRPCStatus().Err()
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to generate email change token", err)
+		return nil, errors.ErrInternalServerError.GRPCStatus().Err()
+	}
+
+	change := &user_models.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  req.NewEmail,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(user_models.EmailChangeTTL),
+	}
+	if err := h.userRepo.ReplaceEmailChange(change); err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to store email change", err)
+		updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
+		return nil, updateErr.GRPCStatus().Err()
+	}
+
+	verificationReq := &pb_notification.SendNotificationRequest{
+		UserId:    user.ID,
+		OrgId:     user.OrgID,
+		Type:      "EMAIL_CHANGE_VERIFICATION",
+		Message:   "Please confirm your new email address.",
+		Email:     req.NewEmail,
+		FirstName: user.FirstName,
+		Locale:    user.Locale,
+		Metadata: map[string]string{
+			"verification_token": token,
+		},
+	}
+
+	resp, err := h.notificationClient.SendNotification(ctx, verificationReq)
+	if err != nil || !resp.Success {
+		h.logger.ErrorCtx(ctx, "Failed to send email change verification", err)
+		h.userRepo.DeleteEmailChange(change.ID)
+		return nil, errors.ErrInternalServerError.WithMessage("Failed to send verification email").GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Email change requested", "expires_at", change.ExpiresAt)
+
+	return &pb_user.RequestEmailChangeResponse{
+		ExpiresAt: timestamppb.New(change.ExpiresAt),
+	}, nil
+}
+
+// ConfirmEmailChange swaps in the new email for the pending change matching the token.
+// the new address is marked verified since the token proves the user received mail there.
+func (h *UserHandler) ConfirmEmailChange(ctx context.Context, req *pb_user.ConfirmEmailChangeRequest) (*pb_user.ConfirmEmailChangeResponse, error) {
+	if req.Token == "" {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	change, err := h.userRepo.GetEmailChangeByTokenHash(hashToken(req.Token))
+	if err != nil {
+		return nil, errors.ErrInvalidToken.GRPCStatus().Err()
+	}
+
+	ctx = scontext.New(ctx).WithUserID(change.UserID).Build()
+
+	if change.IsExpired(time.Now()) {
+		h.logger.InfoCtx(ctx, "Expired email change confirmation")
+		h.userRepo.DeleteEmailChange(change.ID)
+		return nil, errors.ErrEmailChangeExpired.GRPCStatus().Err()
+	}
+
+	// The address may have been taken since the change was requested
+	existingUser, _ := h.userRepo.GetByEmail(change.NewEmail)
+	if existingUser != nil {
+		h.logger.ErrorCtx(ctx, "Email change to an address already in use", nil)
+		h.userRepo.DeleteEmailChange(change.ID)
+		return nil, errors.ErrEmailExists.GRPCStatus().Err()
+	}
+
+	user, err := h.userRepo.GetByID(change.UserID)
+	if err != nil {
+		return nil, errors.ErrUserNotFound.GRPCStatus().Err()
+	}
+
+	user.Email = change.NewEmail
+	user.IsVerified = true
+	if user.AccountStatus == user_models.AccountStatusPendingVerification {
+		user.SetAccountStatus(user_models.AccountStatusActive)
+	}
+
+	if err := h.userRepo.ApplyEmailChange(user, change); err != nil {
+		if err == errors.ErrConcurrentUpdate {
+			return nil, errors.ErrConcurrentUpdate.GRPCStatus().Err()
+		}
+		h.logger.ErrorCtx(ctx, "Failed to apply email change", err)
+		updateErr := errors.ErrUserUpdateFailed.WithDetails(err.Error())
+		return nil, updateErr.GRPCStatus().Err()
+	}
+
+	h.logger.InfoCtx(ctx, "Email change confirmed")
+
+	return &pb_user.ConfirmEmailChangeResponse{
+		User: h.userToProto(user),
+	}, nil
+}
+
+// ListUsers returns a page of users via the administrative gRPC endpoint.
+// admin-only and limited to the caller's organization; used by the notification service to resolve broadcast recipients.
+func (h *UserHandler) ListUsers(ctx context.Context, req *pb_user.ListUsersRequest) (*pb_user.ListUsersResponse, error) {
+	if !auth.IsAdmin(ctx) {
+		return nil, errors.ErrInsufficientRole.GRPCStatus().Err()
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	users, err := h.userRepo.List(auth.OrgID(ctx), limit, int(req.Offset))
+	if err != nil {
+		h.logger.ErrorCtx(ctx, "Failed to list users", err)
+		return nil, errors.ErrInternalServerError.GRPCStatus().Err()
+	}
+
+	pbUsers := make([]*pb_user.User, 0, len(users))
+	for _, user := range users {
+		pbUsers = append(pbUsers, h.userToProto(user))
+	}
+
+	return &pb_user.ListUsersResponse{
+		Users: pbUsers,
+	}, nil
+}
+
+// inCallerOrg reports whether user belongs to the organization of the caller in ctx.
+// users of other organizations are reported as not found so their existence isn't revealed.
+func inCallerOrg(ctx context.Context, user *user_models.User) bool {
+	return user.OrgID == auth.OrgID(ctx)
+}
+
 // userToProto converts a user model to protobuf format for gRPC responses.
 // handles timestamp conversion and excludes sensitive data like password hashes.
 func (h *UserHandler) userToProto(user *user_models.User) *pb_user.User {
@@ -269,7 +529,13 @@ func (h *UserHandler) userToProto(user *user_models.User) *pb_user.User {
 		Roles:      user.Roles,
 		IsActive:   user.IsActive,
 		IsVerified: user.IsVerified,
+		Locale:     user.Locale,
+		OrgId:      user.OrgID,
 		CreatedAt:  timestamppb.New(user.CreatedAt),
+
+		AccountStatus:      user.AccountStatus,
+		LastLoginIp:        user.LastLoginIP,
+		LastLoginUserAgent: user.LastLoginUserAgent,
 	}
 
 	if user.LastLoginAt != nil {
@@ -279,48 +545,67 @@ func (h *UserHandler) userToProto(user *user_models.User) *pb_user.User {
 	return pbUser
 }
 
-// handleUserCreatedAsync publishes user creation events to message queue for asynchronous processing.
-// notifies other services about new user registrations via RabbitMQ.
-func (h *UserHandler) handleUserCreatedAsync(user *user_models.User) {
-	event := models.UserCreatedEvent{
+// userCreatedEvent builds the user creation event stored in the outbox.
+// the outbox relay publishes it to RabbitMQ once the user insert has committed.
+func userCreatedEvent(user *user_models.User) interface{} {
+	return models.UserCreatedEvent{
+		EventMeta: models.NewEventMeta(),
 		UserID:    user.ID,
 		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		Phone:     user.Phone,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt,
 	}
+}
 
-	if err := h.messageQueue.Publish("user.created", event); err != nil {
-		h.logger.Error("Failed to publish user created event", err)
+// localeOrDefault returns the requested locale or the default when none was given.
+func localeOrDefault(locale string) string {
+	if locale == "" {
+		return user_models.DefaultLocale
 	}
+	return locale
 }
 
-// handleUserCreatedSync performs immediate risk assessment and notification sending via gRPC.
-// evaluates new users for risk factors and sends welcome notifications synchronously.
-func (h *UserHandler) handleUserCreatedSync(user *user_models.User) {
-	riskReq := &pb_risk.RiskCheckRequest{
-		UserId:    user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
+// newToken generates a random email change or refresh token and the hash stored for it.
+// only the hash is persisted so a database leak doesn't expose usable tokens.
+func newToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
 	}
+	token := hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+// hashToken returns the hex SHA-256 of a token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	ctx := context.Background()
+// handleUserCreatedSync performs immediate risk assessment and notification sending via gRPC.
+// evaluates new users for risk factors and sends welcome notifications synchronously.
+// ctx must be detached from the request, see scontext.Detach.
+func (h *UserHandler) handleUserCreatedSync(ctx context.Context, user *user_models.User) {
 	ctx = scontext.New(ctx).WithUserAndRoles(user.ID, user.Email, user.Roles).Build()
 
-	riskResp, err := h.riskClient.CheckRisk(ctx, riskReq)
+	riskResp, err := h.checkRisk(ctx, user)
 	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to check risk for user", err)
 		return
 	}
 
 	notificationReq := &pb_notification.SendNotificationRequest{
-		UserId:  user.ID,
-		Type:    "USER_CREATED",
-		Message: "Welcome! Your account has been created successfully.",
-		Email:   user.Email,
+		UserId:    user.ID,
+		OrgId:     user.OrgID,
+		Type:      "USER_CREATED",
+		Message:   "Welcome! Your account has been created successfully.",
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Phone:     user.Phone,
+		Locale:    user.Locale,
 	}
 
 	_, err = h.notificationClient.SendNotification(ctx, notificationReq)
@@ -329,33 +614,21 @@ func (h *UserHandler) handleUserCreatedSync(user *user_models.User) {
 	}
 
 	if riskResp.IsRisky {
-		var action string
+		action := riskAction(riskResp.RiskLevel)
 		switch riskResp.RiskLevel {
 		case "CRITICAL":
-			action = "Account flagged for immediate review"
-			go h.handleCriticalRisk(user, riskResp)
+			go h.handleCriticalRisk(ctx, user, riskResp)
 		case "HIGH":
-			action = "Account requires verification"
-			go h.handleHighRisk(user, riskResp)
-		case "MEDIUM":
-			action = "Account flagged for monitoring"
-		default:
-			action = "Low risk detected"
+			go h.handleHighRisk(ctx, user, riskResp)
 		}
 
-		riskNotificationReq := &pb_notification.SendNotificationRequest{
-			UserId:  user.ID,
-			Type:    "RISK_DETECTED",
-			Message: fmt.Sprintf("Risk detected (%s): %s. Action: %s", riskResp.RiskLevel, riskResp.Reason, action),
-			Email:   user.Email,
-		}
-
-		_, err = h.notificationClient.SendNotification(ctx, riskNotificationReq)
+		_, err = h.notificationClient.SendNotification(ctx, riskNotificationRequest(user, riskResp))
 		if err != nil {
 			h.logger.ErrorCtx(ctx, "Failed to send risk notification", err)
 		}
 
 		h.logger.InfoCtx(ctx, "Risk detected for user",
+			"check_id", riskResp.CheckId,
 			"risk_level", riskResp.RiskLevel,
 			"reason", riskResp.Reason,
 			"flags", riskResp.Flags,
@@ -364,23 +637,53 @@ func (h *UserHandler) handleUserCreatedSync(user *user_models.User) {
 	}
 }
 
+// maxRiskUpdateAttempts bounds the writes of a risk status change that keep losing to concurrent updates.
+const maxRiskUpdateAttempts = 3
+
+// updateRiskStatus applies change to user and stores it. When a concurrent write won the version check,
+// e.g. the last login of a user logging in right after registering, the user is reloaded by ID and
+// change applied again, up to maxRiskUpdateAttempts writes. returns the user as last written.
+func (h *UserHandler) updateRiskStatus(user *user_models.User, change func(*user_models.User)) (*user_models.User, error) {
+	for attempt := 1; ; attempt++ {
+		change(user)
+		err := h.userRepo.Update(user)
+		if err != errors.ErrConcurrentUpdate || attempt == maxRiskUpdateAttempts {
+			return user, err
+		}
+
+		reloaded, err := h.userRepo.GetByID(user.ID)
+		if err != nil {
+			return user, fmt.Errorf("failed to reload user after concurrent update: %w", err)
+		}
+		user = reloaded
+	}
+}
+
 // handleCriticalRisk processes users identified as critical security risks.
 // automatically deactivates accounts and sends admin alerts for immediate attention.
-func (h *UserHandler) handleCriticalRisk(user *user_models.User, riskResp *pb_risk.RiskCheckResponse) {
-	ctx := context.Background()
-	ctx = scontext.New(ctx).WithUserID(user.ID).WithUserEmail(user.Email).Build()
+func (h *UserHandler) handleCriticalRisk(ctx context.Context, user *user_models.User, riskResp *pb_risk.RiskCheckResponse) {
 
-	// Could automatically deactivate account
-	user.IsActive = false
-	if err := h.userRepo.Update(user); err != nil {
+	user, err := h.updateRiskStatus(user, func(user *user_models.User) {
+		user.SetAccountStatus(user_models.AccountStatusSuspendedRisk)
+	})
+	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to deactivate high-risk user", err)
 	}
 
 	adminAlert := &pb_notification.SendNotificationRequest{
 		UserId:  "admin",
+		OrgId:   user.OrgID,
 		Type:    "CRITICAL_RISK_ALERT",
 		Message: fmt.Sprintf("CRITICAL RISK USER: %s (%s) - %s", user.Email, user.ID, riskResp.Reason),
 		Email:   "admin@fakeasfake.com",
+		CheckId: riskResp.CheckId,
+		Metadata: map[string]string{
+			"risk_level":      riskResp.RiskLevel,
+			"reason":          riskResp.Reason,
+			"flags":           strings.Join(riskResp.Flags, ","),
+			"subject_user_id": user.ID,
+			"subject_email":   user.Email,
+		},
 	}
 
 	h.notificationClient.SendNotification(ctx, adminAlert)
@@ -388,21 +691,28 @@ func (h *UserHandler) handleCriticalRisk(user *user_models.User, riskResp *pb_ri
 
 // handleHighRisk processes users identified as high security risks.
 // marks accounts as unverified and triggers email verification workflows.
-func (h *UserHandler) handleHighRisk(user *user_models.User, riskResp *pb_risk.RiskCheckResponse) {
-	ctx := context.Background()
-	ctx = scontext.New(ctx).WithUserID(user.ID).WithUserEmail(user.Email).Build()
+func (h *UserHandler) handleHighRisk(ctx context.Context, user *user_models.User, riskResp *pb_risk.RiskCheckResponse) {
 
-	user.IsVerified = false
-	if err := h.userRepo.Update(user); err != nil {
+	user, err := h.updateRiskStatus(user, func(user *user_models.User) {
+		user.IsVerified = false
+		if user.AccountStatus == user_models.AccountStatusActive {
+			user.SetAccountStatus(user_models.AccountStatusPendingVerification)
+		}
+	})
+	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to update high-risk user verification", err)
 	}
 
 	// Send verification email
 	verificationReq := &pb_notification.SendNotificationRequest{
-		UserId:  user.ID,
-		Type:    "EMAIL_VERIFICATION_REQUIRED",
-		Message: "Please verify your email address to complete your account setup.",
-		Email:   user.Email,
+		UserId:    user.ID,
+		OrgId:     user.OrgID,
+		Type:      "EMAIL_VERIFICATION_REQUIRED",
+		Message:   "Please verify your email address to complete your account setup.",
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Phone:     user.Phone,
+		Locale:    user.Locale,
 	}
 
 	h.notificationClient.SendNotification(ctx, verificationReq)
@@ -410,20 +720,12 @@ func (h *UserHandler) handleHighRisk(user *user_models.User, riskResp *pb_risk.R
 
 // checkLoginRisk evaluates login attempts for suspicious activity patterns.
 // performs risk assessment on login and sends alerts for critical risk scenarios.
-func (h *UserHandler) checkLoginRisk(user *user_models.User) {
-	ctx := context.Background()
+// ctx must be detached from the request, see scontext.Detach.
+func (h *UserHandler) checkLoginRisk(ctx context.Context, user *user_models.User) {
 	ctx = scontext.New(ctx).WithUserID(user.ID).WithUserEmail(user.Email).Build()
 
 	// Could check for suspicious login patterns
-	riskReq := &pb_risk.RiskCheckRequest{
-		UserId:    user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-	}
-
-	riskResp, err := h.riskClient.CheckRisk(ctx, riskReq)
+	riskResp, err := h.checkRisk(ctx, user)
 	if err != nil {
 		h.logger.ErrorCtx(ctx, "Failed to check login risk", err)
 		return
@@ -431,10 +733,14 @@ func (h *UserHandler) checkLoginRisk(user *user_models.User) {
 
 	if riskResp.IsRisky && riskResp.RiskLevel == "CRITICAL" {
 		loginAlert := &pb_notification.SendNotificationRequest{
-			UserId:  user.ID,
-			Type:    "SUSPICIOUS_LOGIN_ALERT",
-			Message: "Suspicious login detected on your account.",
-			Email:   user.Email,
+			UserId:    user.ID,
+			OrgId:     user.OrgID,
+			Type:      "SUSPICIOUS_LOGIN_ALERT",
+			Message:   "Suspicious login detected on your account.",
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			Phone:     user.Phone,
+			Locale:    user.Locale,
 		}
 
 		h.notificationClient.SendNotification(ctx, loginAlert)
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/scontext"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+)
+
+func TestUserCreatedSyncSendsFirstName(t *testing.T) {
+	h := newTestHandler(t, nil)
+	user := h.seedUser(t, "new@example.com")
+
+	h.handleUserCreatedSync(context.Background(), user)
+
+	sent := h.notifier.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d notifications, want the welcome only", len(sent))
+	}
+	if sent[0].FirstName != user.FirstName {
+		t.Errorf("welcome first name = %q, want %q", sent[0].FirstName, user.FirstName)
+	}
+}
+
+func TestRiskNotificationRequestCarriesRiskLevel(t *testing.T) {
+	user := &user_models.User{ID: "user-1", Email: "user@example.com", OrgID: auth.DefaultOrgID}
+	req := riskNotificationRequest(user, &pb_risk.RiskCheckResponse{RiskLevel: "CRITICAL", Reason: "Blocked domain", CheckId: "check-1"})
+	if req.Metadata["risk_level"] != "CRITICAL" || req.CheckId != "check-1" {
+		t.Errorf("request metadata = %v, check ID = %q, want the risk level and check ID of the response", req.Metadata, req.CheckId)
+	}
+}
+
+// requestEmailChange starts a change of user's email to newEmail and returns the token sent to it.
+func (h *testHandler) requestEmailChange(t *testing.T, user *user_models.User, newEmail string) string {
+	t.Helper()
+
+	ctx := scontext.New(context.Background()).WithUserAndRoles(user.ID, user.Email, user.Roles).WithOrgID(user.OrgID).Build()
+	if _, err := h.RequestEmailChange(ctx, &pb_user.RequestEmailChangeRequest{Id: user.ID, NewEmail: newEmail}); err != nil {
+		t.Fatalf("RequestEmailChange() error = %v", err)
+	}
+	sent := h.notifier.Sent()
+	last := sent[len(sent)-1]
+	if last.Email != newEmail || last.Metadata["verification_token"] == "" {
+		t.Fatalf("verification sent to %q with token %q, want a token sent to %q", last.Email, last.Metadata["verification_token"], newEmail)
+	}
+	return last.Metadata["verification_token"]
+}
+
+func TestEmailChangeConfirmSwapsEmail(t *testing.T) {
+	h := newTestHandler(t, nil)
+	user := h.seedUser(t, "old@example.com")
+
+	token := h.requestEmailChange(t, user, "new@example.com")
+	if got := h.reload(t, user).Email; got != "old@example.com" {
+		t.Fatalf("email = %q before confirmation, want the current one", got)
+	}
+
+	if _, err := h.ConfirmEmailChange(context.Background(), &pb_user.ConfirmEmailChangeRequest{Token: token}); err != nil {
+		t.Fatalf("ConfirmEmailChange() error = %v", err)
+	}
+	stored := h.reload(t, user)
+	if stored.Email != "new@example.com" || !stored.IsVerified {
+		t.Errorf("after confirmation email = %q, verified = %v, want the new verified address", stored.Email, stored.IsVerified)
+	}
+
+	// The token is single use
+	_, err := h.ConfirmEmailChange(context.Background(), &pb_user.ConfirmEmailChangeRequest{Token: token})
+	if got, want := status.Code(err), errors.ErrInvalidToken.GRPCStatus().Code(); got != want {
+		t.Errorf("second ConfirmEmailChange() code = %v, want %v", got, want)
+	}
+}
+
+func TestRequestEmailChangeToTakenAddress(t *testing.T) {
+	h := newTestHandler(t, nil)
+	user := h.seedUser(t, "old@example.com")
+	h.seedUser(t, "taken@example.com")
+
+	ctx := scontext.New(context.Background()).WithUserAndRoles(user.ID, user.Email, user.Roles).WithOrgID(user.OrgID).Build()
+	_, err := h.RequestEmailChange(ctx, &pb_user.RequestEmailChangeRequest{Id: user.ID, NewEmail: "taken@example.com"})
+	if got, want := status.Code(err), errors.ErrEmailExists.GRPCStatus().Code(); got != want {
+		t.Errorf("RequestEmailChange() code = %v, want %v", got, want)
+	}
+	if sent := h.notifier.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d verifications for a taken address, want none", len(sent))
+	}
+}
+
+func TestConfirmEmailChangeFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, h *testHandler, token string)
+		want    *errors.AppError
+	}{
+		{
+			name: "expired token",
+			prepare: func(t *testing.T, h *testHandler, token string) {
+				change, err := h.repo.GetEmailChangeByTokenHash(hashToken(token))
+				if err != nil {
+					t.Fatalf("GetEmailChangeByTokenHash() error = %v", err)
+				}
+				change.ExpiresAt = time.Now().Add(-time.Minute)
+				if err := h.repo.ReplaceEmailChange(change); err != nil {
+					t.Fatalf("ReplaceEmailChange() error = %v", err)
+				}
+			},
+			want: errors.ErrEmailChangeExpired,
+		},
+		{
+			name: "address taken after the request",
+			prepare: func(t *testing.T, h *testHandler, _ string) {
+				h.seedUser(t, "new@example.com")
+			},
+			want: errors.ErrEmailExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, nil)
+			user := h.seedUser(t, "old@example.com")
+			token := h.requestEmailChange(t, user, "new@example.com")
+			tt.prepare(t, h, token)
+
+			_, err := h.ConfirmEmailChange(context.Background(), &pb_user.ConfirmEmailChangeRequest{Token: token})
+			if got, want := status.Code(err), tt.want.GRPCStatus().Code(); got != want {
+				t.Errorf("ConfirmEmailChange() code = %v, want %v (%v)", got, want, err)
+			}
+			if got := h.reload(t, user).Email; got != "old@example.com" {
+				t.Errorf("email = %q after a failed confirmation, want the current one", got)
+			}
+			if _, err := h.repo.GetEmailChangeByTokenHash(hashToken(token)); err == nil {
+				t.Error("failed confirmation kept the pending change")
+			}
+		})
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	h := newTestHandler(t, nil)
+	user := h.seedUser(t, "user@example.com")
+	admin := h.seedUser(t, "admin@example.com", string(auth.RoleAdmin))
+
+	tests := []struct {
+		name  string
+		orgID string
+		email string
+		want  *errors.AppError
+	}{
+		{"registered email", auth.DefaultOrgID, user.Email, nil},
+		{"unknown email", auth.DefaultOrgID, "nobody@example.com", errors.ErrUserNotFound},
+		{"user of another organization", "other-org", user.Email, errors.ErrUserNotFound},
+		{"missing email", auth.DefaultOrgID, "", errors.ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := scontext.New(context.Background()).WithUserAndRoles(admin.ID, admin.Email, admin.Roles).WithOrgID(tt.orgID).Build()
+
+			resp, err := h.GetUserByEmail(ctx, &pb_user.GetUserByEmailRequest{Email: tt.email})
+			if tt.want != nil {
+				if want := tt.want.GRPCStatus().Code(); status.Code(err) != want {
+					t.Fatalf("GetUserByEmail(%q) error = %v, want %v", tt.email, err, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetUserByEmail(%q) error = %v", tt.email, err)
+			}
+			if resp.User.Id != user.ID || resp.User.Email != user.Email {
+				t.Errorf("GetUserByEmail(%q) = %s <%s>, want %s", tt.email, resp.User.Id, resp.User.Email, user.ID)
+			}
+		})
+	}
+}
+
+func TestGetUserByEmailRequiresAdmin(t *testing.T) {
+	h := newTestHandler(t, nil)
+	user := h.seedUser(t, "user@example.com")
+	ctx := scontext.New(context.Background()).WithUserAndRoles(user.ID, user.Email, user.Roles).WithOrgID(auth.DefaultOrgID).Build()
+
+	_, err := h.GetUserByEmail(ctx, &pb_user.GetUserByEmailRequest{Email: user.Email})
+	if want := errors.ErrInsufficientRole.GRPCStatus().Code(); status.Code(err) != want {
+		t.Errorf("GetUserByEmail() by a user error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"user-risk-system/cmd/user/repository"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/scheduler"
+)
+
+// tokenGCJob returns the job deleting expired sessions and email change tokens.
+// expired rows are already rejected on use, the job only keeps the tables from growing.
+func tokenGCJob(repo *repository.UserRepository, l *logger.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:      "token_gc",
+		Interval:  time.Hour,
+		Jitter:    5 * time.Minute,
+		Singleton: true,
+		Run: func(ctx context.Context) error {
+			removed, err := repo.DeleteExpired(time.Now())
+			if err != nil {
+				return err
+			}
+			if removed > 0 {
+				l.Info("Deleted expired sessions and email changes", "deleted", removed)
+			}
+			return nil
+		},
+	}
+}
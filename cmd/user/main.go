@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"google.golang.org/grpc"
 	"gorm.io/gorm"
@@ -11,68 +15,122 @@ import (
 	"user-risk-system/cmd/user/models"
 	"user-risk-system/cmd/user/repository"
 	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/client"
 	"user-risk-system/pkg/config"
 	"user-risk-system/pkg/health"
+	"user-risk-system/pkg/lifecycle"
 	"user-risk-system/pkg/logger"
 	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/migrate"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/scheduler"
+	"user-risk-system/pkg/scontext"
 	"user-risk-system/pkg/utils"
-	pb_notification "user-risk-system/proto/notification"
-	pb_risk "user-risk-system/proto/risk"
 	pb_user "user-risk-system/proto/user"
 )
 
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Require("DATABASE_URL", "RABBITMQ_URL", "JWT_SECRET"))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	logConfig := logger.LogConfig{
-		Level:       "info",
+		Level:       cfg.LogLevel,
 		Format:      "json",
 		ServiceName: cfg.ServiceName,
 		Environment: cfg.Environment,
+		MaskPII:     cfg.LogMaskPII,
 	}
 	appLogger := logger.New(logConfig)
+	cfg.LogStartupReport(appLogger)
+	cfg.Settings().WatchSignals(context.Background(), appLogger)
+
+	// Resources registered with the lifecycle are closed in reverse order when main returns
+	lc := lifecycle.New(appLogger)
+	defer lc.Shutdown()
 
 	// Database
-	db, err := utils.SetupDatabase(cfg.DatabaseURL, &gorm.Config{}, cfg, appLogger)
-	if err != nil {
+	var db *gorm.DB
+	if err := lc.Connect("database", func() (err error) {
+		db, err = utils.SetupDatabase(cfg.DatabaseURL, &gorm.Config{}, cfg, appLogger)
+		return err
+	}); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Run auto-migration for user models
-	appLogger.Info("Running user database migration...")
-	if err := models.AutoMigrate(db); err != nil {
-		appLogger.Fatalf("Failed to run migration: %v", err)
-	}
-	appLogger.Info("User database migration completed successfully")
-
 	sdb, err := db.DB()
 	if err != nil {
 		appLogger.Fatalf("Failed to get underlying SQL DB: %v", err)
 	}
-	defer sdb.Close()
+	lc.OnShutdown("database", sdb.Close)
 
-	// gRPC client connections
-	riskConn, err := grpc.Dial(cfg.RiskServiceURL, grpc.WithInsecure())
-	if err != nil {
+	// Schema migrations, `user-service migrate [up|down [steps]|status]` runs them and exits
+	migrator := migrate.New(db, "user-service", models.Migrations, appLogger)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrator.RunCommand(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			appLogger.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+	if cfg.DatabaseAutoMigrate {
+		appLogger.Warn("Running gorm auto-migration instead of versioned migrations, intended for development only")
+		if err := models.AutoMigrate(db); err != nil {
+			appLogger.Fatalf("Failed to run auto-migration: %v", err)
+		}
+	} else {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			appLogger.Fatalf("Failed to run migrations: %v", err)
+		}
+		appLogger.Info("User database migrations completed successfully", "applied", applied)
+	}
+
+	// gRPC clients
+	var riskClient *client.RiskClient
+	if err := lc.Connect("risk-engine", func() (err error) {
+		riskClient, err = client.NewRiskClient(cfg.RiskServiceURL, client.Config{})
+		return err
+	}); err != nil {
 		appLogger.Fatalf("Failed to connect to risk service: %v", err)
 	}
-	defer riskConn.Close()
+	lc.OnShutdown("risk-engine client", riskClient.Close)
 
-	notificationConn, err := grpc.Dial(cfg.NotificationServiceURL, grpc.WithInsecure())
-	if err != nil {
+	var notificationClient *client.NotificationClient
+	if err := lc.Connect("notification-service", func() (err error) {
+		notificationClient, err = client.NewNotificationClient(cfg.NotificationServiceURL, client.Config{})
+		return err
+	}); err != nil {
 		appLogger.Fatalf("Failed to connect to notification service: %v", err)
 	}
-	defer notificationConn.Close()
+	lc.OnShutdown("notification-service client", notificationClient.Close)
 
-	// RabbitMQ connection
-	rabbitMQ, err := messaging.NewRabbitMQ(cfg.RabbitMQURL)
-	if err != nil {
-		appLogger.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	// Optionally wait for dependencies before serving, RabbitMQ only when the service can't start without it
+	dependencies := []health.Dependency{
+		health.GRPCDependency("risk-engine", riskClient.Conn()),
+		health.GRPCDependency("notification-service", notificationClient.Conn()),
+	}
+	if cfg.MessagingRequired {
+		dependencies = append(dependencies, health.RabbitMQDependency(cfg.RabbitMQURL))
+	}
+	if err := health.WaitForDependencies(context.Background(), cfg.StartupWaitTimeout, cfg.StartupWaitInterval, appLogger, dependencies...); err != nil {
+		appLogger.Fatalf("Dependencies not ready: %v", err)
+	}
+
+	// RabbitMQ connection, retried in the background unless MESSAGING_REQUIRED is set
+	rabbitMQ := messaging.NewResilient(cfg.RabbitMQURL, messaging.OfflineMode(cfg.MessagingOfflineMode), cfg.MessagingReconnectInterval, appLogger)
+	if err := lc.Connect("rabbitmq", rabbitMQ.Connect); err != nil {
+		if cfg.MessagingRequired {
+			appLogger.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		}
+		appLogger.Warn("Starting without RabbitMQ, connecting in the background", "offline_mode", cfg.MessagingOfflineMode)
 	}
-	defer rabbitMQ.Close()
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	go rabbitMQ.Run(brokerCtx)
+	lc.OnShutdown("rabbitmq", func() error {
+		stopBroker()
+		return rabbitMQ.Close()
+	})
 
 	// Declare queues
 	queues := []string{"user.created", "risk.detected", "notifications"}
@@ -82,9 +140,14 @@ func main() {
 		}
 	}
 
-	// Create clients
-	riskClient := pb_risk.NewRiskServiceClient(riskConn)
-	notificationClient := pb_notification.NewNotificationServiceClient(notificationConn)
+	// Outbox relay publishes events committed alongside user writes
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relay := outbox.NewRelay(db, rabbitMQ.Strict(), appLogger, cfg.OutboxPollInterval)
+	go relay.Run(relayCtx)
+	lc.OnShutdown("outbox relay", func() error {
+		stopRelay()
+		return nil
+	})
 
 	// Create repository and handler
 	userRepo := repository.NewUserRepository(db)
@@ -93,10 +156,31 @@ func main() {
 		riskClient,
 		notificationClient,
 		rabbitMQ,
+		handlers.SessionPolicy{TTL: cfg.SessionTTL, MaxPerUser: cfg.MaxSessionsPerUser},
+		handlers.PasswordPolicy{HistoryDepth: cfg.PasswordHistoryDepth},
+		cfg.DefaultUserRole,
 		appLogger,
 	)
+	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer)
+	userHandler.EnableServiceAuth(jwtManager)
+
+	// Periodic jobs, singletons run on one replica at a time
+	jobs := scheduler.New(scheduler.NewDBLocker(db, appLogger), appLogger)
+	for _, job := range []scheduler.Job{
+		tokenGCJob(userRepo, appLogger),
+		outbox.PurgeJob(db, cfg.OutboxRetention, appLogger),
+	} {
+		if err := jobs.Register(job); err != nil {
+			appLogger.Fatalf("Failed to register job: %v", err)
+		}
+	}
+	jobs.Start(context.Background())
+	lc.OnShutdown("scheduler", func() error {
+		jobs.Stop()
+		return nil
+	})
 
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", ":"+cfg.Ports.UserGRPC)
 	if err != nil {
 		appLogger.Fatalf("Failed to listen: %v", err)
 	}
@@ -105,14 +189,15 @@ func main() {
 	// if you want to explicitly disable it, you have to set REQUIRE_SERVICE_JWT_FORWARDING to false
 	var s *grpc.Server
 	if cfg.RequireServiceJWTForwarding {
-		jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer)
 		authMiddleware := auth.NewAuthMiddleware(jwtManager)
 		s = grpc.NewServer(
-			grpc.UnaryInterceptor(authMiddleware.GRPCUnaryInterceptor),
+			grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), logger.UnaryServerInterceptor(appLogger), authMiddleware.GRPCUnaryInterceptor),
 		)
 		appLogger.Info("gRPC JWT authentication enabled")
 	} else {
-		s = grpc.NewServer()
+		s = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), logger.UnaryServerInterceptor(appLogger)),
+		)
 		appLogger.Warn("gRPC JWT authentication disabled")
 	}
 
@@ -120,8 +205,20 @@ func main() {
 
 	health.RegisterHealthServiceWithDefaults(s, "user.UserService")
 
-	appLogger.Info("User service starting on port 50051...")
-	if err := s.Serve(lis); err != nil {
-		appLogger.Fatalf("Failed to serve: %v", err)
-	}
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			appLogger.Fatalf("Failed to serve: %v", err)
+		}
+	}()
+	lc.OnShutdown("grpc server", func() error {
+		s.GracefulStop()
+		return nil
+	})
+	lc.Started("port", cfg.Ports.UserGRPC)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+
+	appLogger.Warn("Shutting down user service...")
 }
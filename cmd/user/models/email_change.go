@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// EmailChangeTTL is how long a requested email change can be confirmed.
+const EmailChangeTTL = 24 * time.Hour
+
+// EmailChange represents a pending email address change awaiting confirmation.
+// the user's current email stays active until the new address is confirmed.
+type EmailChange struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"uniqueIndex;not null"` // One pending change per user, a new request replaces it
+	NewEmail  string    `json:"new_email" gorm:"not null"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex;not null"` // SHA-256 of the token sent to the new address
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsExpired reports whether the change can no longer be confirmed.
+func (c *EmailChange) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// LoginRecord is one successful login, the user's login history keeps the most recent ones.
+type LoginRecord struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"index;not null"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(64)"`
+	UserAgent string    `json:"user_agent" gorm:"type:varchar(512)"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+func (LoginRecord) TableName() string {
+	return "login_history"
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/pkg/migrate"
+)
+
+// Migrations are the versioned schema changes of the user database, applied in order.
+// the initial migration adopts the schema AutoMigrate created so far, later changes get their own.
+// each one works on frozen snapshots of the tables it touches, so it keeps creating the same
+// schema when the models change.
+var Migrations = []migrate.Migration{
+	{
+		ID: "0001_initial",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userV1{}, &emailChangeV1{}, &sessionV1{}, &outboxMessageV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&outboxMessageV1{}, &sessionV1{}, &emailChangeV1{}, &userV1{})
+		},
+	},
+	{
+		// Users deactivated before account statuses existed were all suspended by critical risk checks
+		ID: "0002_account_status",
+		Up: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&userV2{}, "AccountStatus") {
+				if err := tx.Migrator().AddColumn(&userV2{}, "AccountStatus"); err != nil {
+					return err
+				}
+			}
+			return tx.Table("users").
+				Where("is_active = ? AND account_status = ?", false, AccountStatusActive).
+				UpdateColumn("account_status", AccountStatusSuspendedRisk).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, "users", "account_status")
+		},
+	},
+	{
+		ID: "0003_login_history",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"LastLoginIP", "LastLoginUserAgent"} {
+				if tx.Migrator().HasColumn(&userV3{}, column) {
+					continue
+				}
+				if err := tx.Migrator().AddColumn(&userV3{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&loginRecordV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&loginRecordV3{}); err != nil {
+				return err
+			}
+			return dropColumns(tx, "users", "last_login_ip", "last_login_user_agent")
+		},
+	},
+	{
+		ID: "0004_password_history",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&passwordHistoryV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&passwordHistoryV4{})
+		},
+	},
+}
+
+// dropColumns drops columns of table with plain ALTER TABLE statements.
+// gorm's SQLite migrator rebuilds the table to drop a column and loses its indexes.
+func dropColumns(tx *gorm.DB, table string, columns ...string) error {
+	for _, column := range columns {
+		if err := tx.Exec("ALTER TABLE " + table + " DROP COLUMN " + column).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userV1 is the users table as created by 0001_initial.
+type userV1 struct {
+	ID           string   `gorm:"primaryKey"`
+	Email        string   `gorm:"uniqueIndex;not null"`
+	OrgID        string   `gorm:"type:varchar(255);not null;default:'default';index"`
+	PasswordHash string   `gorm:"not null"`
+	FirstName    string   `gorm:"not null"`
+	LastName     string   `gorm:"not null"`
+	Phone        string   ``
+	Roles        []string `gorm:"serializer:json"`
+	Locale       string   `gorm:"type:varchar(10);default:'en'"`
+	IsActive     bool     `gorm:"default:true"`
+	IsVerified   bool     `gorm:"default:false"`
+	LastLoginAt  *time.Time
+	Version      int `gorm:"not null;default:1"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (userV1) TableName() string {
+	return "users"
+}
+
+// emailChangeV1 is the email_changes table as created by 0001_initial.
+type emailChangeV1 struct {
+	ID        string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"uniqueIndex;not null"`
+	NewEmail  string    `gorm:"not null"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (emailChangeV1) TableName() string {
+	return "email_changes"
+}
+
+// sessionV1 is the sessions table as created by 0001_initial.
+type sessionV1 struct {
+	ID               string `gorm:"primaryKey"`
+	UserID           string `gorm:"index;not null"`
+	RefreshTokenHash string `gorm:"uniqueIndex;not null"`
+	UserAgent        string `gorm:"type:varchar(512)"`
+	IPAddress        string `gorm:"type:varchar(64)"`
+	LastSeenAt       time.Time
+	ExpiresAt        time.Time `gorm:"index;not null"`
+	CreatedAt        time.Time
+}
+
+func (sessionV1) TableName() string {
+	return "sessions"
+}
+
+// outboxMessageV1 is the outbox table as created by 0001_initial.
+type outboxMessageV1 struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	Queue     string     `gorm:"type:varchar(255);not null"`
+	Payload   []byte     `gorm:"not null"`
+	Attempts  int        `gorm:"default:0"`
+	LastError string     `gorm:"type:text"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index"`
+	SentAt    *time.Time `gorm:"index"`
+}
+
+func (outboxMessageV1) TableName() string {
+	return "outbox"
+}
+
+// userV2 is the column 0002_account_status adds to users.
+type userV2 struct {
+	AccountStatus string `gorm:"type:varchar(32);not null;default:'ACTIVE'"`
+}
+
+func (userV2) TableName() string {
+	return "users"
+}
+
+// userV3 is the columns 0003_login_history adds to users.
+type userV3 struct {
+	LastLoginIP        string `gorm:"type:varchar(64)"`
+	LastLoginUserAgent string `gorm:"type:varchar(512)"`
+}
+
+func (userV3) TableName() string {
+	return "users"
+}
+
+// loginRecordV3 is the login_history table as created by 0003_login_history.
+type loginRecordV3 struct {
+	ID        string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"index;not null"`
+	IPAddress string    `gorm:"type:varchar(64)"`
+	UserAgent string    `gorm:"type:varchar(512)"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (loginRecordV3) TableName() string {
+	return "login_history"
+}
+
+// passwordHistoryV4 is the password_history table as created by 0004_password_history.
+type passwordHistoryV4 struct {
+	ID           string    `gorm:"primaryKey"`
+	UserID       string    `gorm:"index;not null"`
+	PasswordHash string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+func (passwordHistoryV4) TableName() string {
+	return "password_history"
+}
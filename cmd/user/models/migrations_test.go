@@ -0,0 +1,12 @@
+package models_test
+
+import (
+	"testing"
+
+	"user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/testutil"
+)
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	testutil.CheckMigrations(t, models.Migrations, models.AutoMigrate)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHistory is a password the user had before, kept so it can't be reused.
+type PasswordHistory struct {
+	ID           string    `json:"id" gorm:"primaryKey"`
+	UserID       string    `json:"user_id" gorm:"index;not null"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"` // When the password was replaced
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
+
+// PasswordUsed returns true if password is the user's current password or one of history.
+// each hash is compared with bcrypt, so the check costs one comparison per entry.
+func (u *User) PasswordUsed(password string, history []*PasswordHistory) bool {
+	if u.CheckPassword(password) {
+		return true
+	}
+	for _, previous := range history {
+		if bcrypt.CompareHashAndPassword([]byte(previous.PasswordHash), []byte(password)) == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Session represents a login on one device, backed by a single refresh token.
+// revoking the session invalidates its refresh token, issued access tokens expire normally.
+type Session struct {
+	ID               string    `json:"id" gorm:"primaryKey"`
+	UserID           string    `json:"user_id" gorm:"index;not null"`
+	RefreshTokenHash string    `json:"-" gorm:"uniqueIndex;not null"` // SHA-256 of the current refresh token, rotated on refresh
+	UserAgent        string    `json:"user_agent" gorm:"type:varchar(512)"`
+	IPAddress        string    `json:"ip_address" gorm:"type:varchar(64)"`
+	LastSeenAt       time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at" gorm:"index;not null"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// IsExpired reports whether the session's refresh token can no longer be used.
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
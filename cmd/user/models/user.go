@@ -5,6 +5,8 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+
+	"user-risk-system/pkg/outbox"
 )
 
 // User represents a system user with authentication and profile information.
@@ -12,16 +14,40 @@ import (
 type User struct {
 	ID           string     `json:"id" gorm:"primaryKey"`
 	Email        string     `json:"email" gorm:"uniqueIndex;not null"`
-	PasswordHash string     `json:"-" gorm:"not null"` // Never include in JSON
+	OrgID        string     `json:"org_id" gorm:"type:varchar(255);not null;default:'default';index"` // Tenant the user belongs to
+	PasswordHash string     `json:"-" gorm:"not null"`                                                // Never include in JSON
 	FirstName    string     `json:"first_name" gorm:"not null"`
 	LastName     string     `json:"last_name" gorm:"not null"`
 	Phone        string     `json:"phone"`
 	Roles        []string   `json:"roles" gorm:"serializer:json"`
+	Locale       string     `json:"locale" gorm:"type:varchar(10);default:'en'"` // Preferred notification language
 	IsActive     bool       `json:"is_active" gorm:"default:true"`
 	IsVerified   bool       `json:"is_verified" gorm:"default:false"`
 	LastLoginAt  *time.Time `json:"last_login_at"`
+	Version      int        `json:"version" gorm:"not null;default:1"` // Optimistic lock, incremented on every update
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+
+	AccountStatus string `json:"account_status" gorm:"type:varchar(32);not null;default:'ACTIVE'"` // Why the user can or can't log in, IsActive follows it
+
+	// Client of the last successful login, see LoginRecord for the history
+	LastLoginIP        string `json:"last_login_ip" gorm:"type:varchar(64)"`
+	LastLoginUserAgent string `json:"last_login_user_agent" gorm:"type:varchar(512)"`
+}
+
+// Account statuses, only ACTIVE and PENDING_VERIFICATION users may log in.
+const (
+	AccountStatusActive              = "ACTIVE"
+	AccountStatusSuspendedRisk       = "SUSPENDED_RISK"  // Deactivated by a critical risk check
+	AccountStatusSuspendedAdmin      = "SUSPENDED_ADMIN" // Deactivated by an admin
+	AccountStatusPendingVerification = "PENDING_VERIFICATION"
+	AccountStatusClosed              = "CLOSED" // Closed by an admin for good
+)
+
+// SetAccountStatus moves the user to status, keeping IsActive in sync.
+func (u *User) SetAccountStatus(status string) {
+	u.AccountStatus = status
+	u.IsActive = status == AccountStatusActive || status == AccountStatusPendingVerification
 }
 
 // SetPassword securely hashes and stores a user's password using bcrypt.
@@ -69,12 +95,15 @@ func (u *User) RemoveRole(role string) {
 	}
 }
 
+// DefaultLocale is the notification language used when a user has not chosen one.
+const DefaultLocale = "en"
+
 // GetFullName returns the user's complete name by combining first and last names.
 func (u *User) GetFullName() string {
 	return u.FirstName + " " + u.LastName
 }
 
-// AutoMigrate runs GORM auto-migration for user models
+// AutoMigrate runs GORM auto-migration for user models and the event outbox
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(&User{})
+	return db.AutoMigrate(&User{}, &EmailChange{}, &Session{}, &LoginRecord{}, &PasswordHistory{}, &outbox.Message{})
 }
@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/errors"
+	"user-risk-system/pkg/outbox"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -24,6 +28,18 @@ func (r *UserRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
+// CreateWithEvent inserts a new user and enqueues an outbox event in a single transaction.
+// the event is built after the UUID is assigned so it can reference the new user ID.
+func (r *UserRepository) CreateWithEvent(user *models.User, queue string, event func(*models.User) interface{}) error {
+	user.ID = uuid.New().String()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, queue, event(user))
+	})
+}
+
 // GetByID retrieves a user by their unique identifier.
 func (r *UserRepository) GetByID(id string) (*models.User, error) {
 	var user models.User
@@ -44,9 +60,32 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-// Update modifies an existing user record in the database.
+// Update modifies an existing user record in the database using optimistic locking.
+// the write only succeeds if the stored version still matches user.Version, otherwise
+// errors.ErrConcurrentUpdate is returned and the caller should reload and retry.
 func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return updateVersioned(r.db, user)
+}
+
+// updateVersioned performs the optimistic-locking update used by Update on db, which may be a transaction.
+func updateVersioned(db *gorm.DB, user *models.User) error {
+	expected := user.Version
+	user.Version = expected + 1
+
+	result := db.Model(user).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("CreatedAt").
+		Updates(user)
+	if result.Error != nil {
+		user.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		user.Version = expected
+		return errors.ErrConcurrentUpdate
+	}
+	return nil
 }
 
 // Delete permanently removes a user from the database by ID.
@@ -54,9 +93,233 @@ func (r *UserRepository) Delete(id string) error {
 	return r.db.Delete(&models.User{}, "id = ?", id).Error
 }
 
-// List retrieves multiple users with pagination support.
-func (r *UserRepository) List(limit, offset int) ([]*models.User, error) {
+// List retrieves the users of an organization with pagination support.
+// users are ordered by creation so offsets stay stable while new users sign up.
+func (r *UserRepository) List(orgID string, limit, offset int) ([]*models.User, error) {
 	var users []*models.User
-	err := r.db.Limit(limit).Offset(offset).Find(&users).Error
+	err := r.db.Where("org_id = ?", orgID).Order("created_at, id").Limit(limit).Offset(offset).Find(&users).Error
 	return users, err
 }
+
+// ReplaceEmailChange stores a pending email change, discarding any earlier one for the same user.
+func (r *UserRepository) ReplaceEmailChange(change *models.EmailChange) error {
+	change.ID = uuid.New().String()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.EmailChange{}, "user_id = ?", change.UserID).Error; err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// GetEmailChangeByTokenHash retrieves a pending email change by the hash of its token.
+func (r *UserRepository) GetEmailChangeByTokenHash(tokenHash string) (*models.EmailChange, error) {
+	var change models.EmailChange
+	err := r.db.Where("token_hash = ?", tokenHash).First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// DeleteEmailChange removes a pending email change by ID.
+func (r *UserRepository) DeleteEmailChange(id string) error {
+	return r.db.Delete(&models.EmailChange{}, "id = ?", id).Error
+}
+
+// ApplyEmailChange saves the user with its new email and removes the pending change in a single transaction.
+// uses the same optimistic locking as Update.
+func (r *UserRepository) ApplyEmailChange(user *models.User, change *models.EmailChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, user); err != nil {
+			return err
+		}
+		return tx.Delete(&models.EmailChange{}, "id = ?", change.ID).Error
+	})
+}
+
+// RecordLogin saves the user with its last login and adds record to the login history in a single transaction.
+// uses the same optimistic locking as Update, records beyond the newest keep of the user are removed.
+func (r *UserRepository) RecordLogin(user *models.User, record *models.LoginRecord, keep int) error {
+	record.ID = uuid.New().String()
+	record.UserID = user.ID
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, user); err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		var old []models.LoginRecord
+		if err := tx.Select("id").
+			Where("user_id = ?", user.ID).
+			Order("created_at DESC").
+			Offset(keep).
+			Find(&old).Error; err != nil {
+			return err
+		}
+		for _, evicted := range old {
+			if err := tx.Delete(&models.LoginRecord{}, "id = ?", evicted.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListLogins returns up to limit of the user's most recent logins, newest first.
+func (r *UserRepository) ListLogins(userID string, limit int) ([]*models.LoginRecord, error) {
+	var logins []*models.LoginRecord
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logins).Error
+	return logins, err
+}
+
+// ListPasswordHistory returns up to limit of the user's previous passwords, most recently replaced first.
+func (r *UserRepository) ListPasswordHistory(userID string, limit int) ([]*models.PasswordHistory, error) {
+	var history []*models.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&history).Error
+	return history, err
+}
+
+// ChangePassword saves the user with its new password and adds previousHash to the password history in a single transaction.
+// uses the same optimistic locking as Update, history beyond the newest keep of the user is removed.
+func (r *UserRepository) ChangePassword(user *models.User, previousHash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, user); err != nil {
+			return err
+		}
+		if keep > 0 {
+			entry := &models.PasswordHistory{
+				ID:           uuid.New().String(),
+				UserID:       user.ID,
+				PasswordHash: previousHash,
+				CreatedAt:    time.Now(),
+			}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+
+		var old []models.PasswordHistory
+		if err := tx.Select("id").
+			Where("user_id = ?", user.ID).
+			Order("created_at DESC").
+			Offset(keep).
+			Find(&old).Error; err != nil {
+			return err
+		}
+		for _, evicted := range old {
+			if err := tx.Delete(&models.PasswordHistory{}, "id = ?", evicted.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateSession stores a new session, evicting the user's oldest sessions beyond maxSessions.
+// expired sessions are removed first so they don't count against the limit, 0 disables the limit.
+func (r *UserRepository) CreateSession(session *models.Session, maxSessions int) error {
+	session.ID = uuid.New().String()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Session{}, "user_id = ? AND expires_at <= ?", session.UserID, time.Now()).Error; err != nil {
+			return err
+		}
+
+		if maxSessions > 0 {
+			var existing []models.Session
+			if err := tx.Select("id").
+				Where("user_id = ?", session.UserID).
+				Order("created_at DESC").
+				Offset(maxSessions - 1).
+				Find(&existing).Error; err != nil {
+				return err
+			}
+			for _, evicted := range existing {
+				if err := tx.Delete(&models.Session{}, "id = ?", evicted.ID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Create(session).Error
+	})
+}
+
+// GetSessionByTokenHash retrieves a session by the hash of its refresh token.
+func (r *UserRepository) GetSessionByTokenHash(tokenHash string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("refresh_token_hash = ?", tokenHash).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListSessions returns the user's unexpired sessions, most recently used first.
+func (r *UserRepository) ListSessions(userID string) ([]*models.Session, error) {
+	var sessions []*models.Session
+	err := r.db.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RotateSession saves the session's new refresh token hash and activity if its token is still previousHash.
+// returns gorm.ErrRecordNotFound when a concurrent refresh or revocation got there first.
+func (r *UserRepository) RotateSession(session *models.Session, previousHash string) error {
+	result := r.db.Model(session).
+		Where("refresh_token_hash = ?", previousHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": session.RefreshTokenHash,
+			"user_agent":         session.UserAgent,
+			"ip_address":         session.IPAddress,
+			"last_seen_at":       session.LastSeenAt,
+			"expires_at":         session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteSession removes one of the user's sessions, returning gorm.ErrRecordNotFound if it doesn't exist.
+// scoping by user keeps one user from revoking another user's session by ID.
+func (r *UserRepository) DeleteSession(userID, sessionID string) error {
+	result := r.db.Delete(&models.Session{}, "id = ? AND user_id = ?", sessionID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes sessions and pending email changes that expired before now, returning how many were removed.
+func (r *UserRepository) DeleteExpired(now time.Time) (int64, error) {
+	var removed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		sessions := tx.Delete(&models.Session{}, "expires_at <= ?", now)
+		if sessions.Error != nil {
+			return sessions.Error
+		}
+		changes := tx.Delete(&models.EmailChange{}, "expires_at <= ?", now)
+		if changes.Error != nil {
+			return changes.Error
+		}
+		removed = sessions.RowsAffected + changes.RowsAffected
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/cmd/user/repository"
+	apperrors "user-risk-system/pkg/errors"
+	"user-risk-system/pkg/testutil"
+)
+
+func TestUpdateConcurrentUpdateConflict(t *testing.T) {
+	repo := repository.NewUserRepository(testutil.NewSQLiteDB(t, user_models.AutoMigrate))
+	user := &user_models.User{Email: "locked@example.com", OrgID: "default", FirstName: "Lock", LastName: "Ed"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	first, err := repo.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.FirstName = "Profile"
+	if err := repo.Update(first); err != nil {
+		t.Fatalf("first Update() error = %v", err)
+	}
+	second.SetAccountStatus(user_models.AccountStatusSuspendedRisk)
+	if err := repo.Update(second); !errors.Is(err, apperrors.ErrConcurrentUpdate) {
+		t.Fatalf("second Update() error = %v, want ErrConcurrentUpdate", err)
+	}
+
+	stored, err := repo.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.FirstName != "Profile" || stored.Version != first.Version {
+		t.Errorf("stored = %s v%d, want the first update at v%d", stored.FirstName, stored.Version, first.Version)
+	}
+}
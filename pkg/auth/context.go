@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+
+	"user-risk-system/pkg/scontext"
+)
+
+type authContextKey string
+
+const (
+	claimsContextKey authContextKey = "claims"
+	tokenContextKey  authContextKey = "jwt_token"
+)
+
+// ContextWithClaims enriches ctx with the canonical scontext user fields from validated claims.
+// used by both the HTTP middleware and the gRPC interceptor so every downstream component
+// reads the same keys. The raw token is kept for forwarding to downstream services.
+func ContextWithClaims(ctx context.Context, claims *Claims, token string) context.Context {
+	ctx = scontext.New(ctx).
+		WithUserAndRoles(claims.UserID, claims.Email, claims.Roles).
+		WithOrgID(OrgOrDefault(claims.OrgID)).
+		WithSessionID(claims.SessionID).
+		Build()
+
+	ctx = context.WithValue(ctx, claimsContextKey, claims)
+	if token != "" {
+		ctx = context.WithValue(ctx, tokenContextKey, token)
+	}
+	return ctx
+}
+
+// IsAdmin reports whether the caller's roles stored by ContextWithClaims include admin.
+func IsAdmin(ctx context.Context) bool {
+	roles, _ := scontext.Roles(ctx)
+	for _, role := range roles {
+		if role == string(RoleAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimsFromContext returns the validated claims stored by ContextWithClaims.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// TokenFromContext returns the raw JWT stored by ContextWithClaims.
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenContextKey).(string)
+	return token, ok && token != ""
+}
+
+// DefaultOrgID is the organization of self-registered users and of tokens issued before org_id existed.
+const DefaultOrgID = "default"
+
+// OrgOrDefault returns orgID, or DefaultOrgID when it is empty.
+func OrgOrDefault(orgID string) string {
+	if orgID == "" {
+		return DefaultOrgID
+	}
+	return orgID
+}
+
+// OrgID returns the caller's organization stored by ContextWithClaims, DefaultOrgID when there is none.
+func OrgID(ctx context.Context) string {
+	orgID, _ := scontext.OrgID(ctx)
+	return OrgOrDefault(orgID)
+}
@@ -0,0 +1,28 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"user-risk-system/pkg/auth"
+)
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		admin bool
+	}{
+		{"no claims", context.Background(), false},
+		{"user", auth.ContextWithClaims(context.Background(), &auth.Claims{UserID: "u1", Roles: []string{"user"}}, ""), false},
+		{"service", auth.ContextWithClaims(context.Background(), &auth.Claims{UserID: "svc", Roles: []string{"service"}}, ""), false},
+		{"admin among roles", auth.ContextWithClaims(context.Background(), &auth.Claims{UserID: "a1", Roles: []string{"user", "admin"}}, ""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auth.IsAdmin(tt.ctx); got != tt.admin {
+				t.Errorf("IsAdmin() = %v, want %v", got, tt.admin)
+			}
+		})
+	}
+}
@@ -20,8 +20,8 @@ func JWTClientInterceptor() grpc.UnaryClientInterceptor {
 		invoker grpc.UnaryInvoker,
 		opts ...grpc.CallOption,
 	) error {
-		if token := ctx.Value("jwt_token"); token != nil {
-			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token.(string))
+		if token, ok := TokenFromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
 			log.Printf("🔐 Auto-forwarding JWT token for gRPC call: %s", method)
 		}
 
@@ -29,17 +29,3 @@ func JWTClientInterceptor() grpc.UnaryClientInterceptor {
 		return invoker(ctx, method, req, reply, cc, opts...)
 	}
 }
-
-// NewAuthenticatedGRPCConnection establishes a gRPC client connection with JWT authentication interceptor.
-// creates a connection to the target server with automatic JWT token forwarding for all requests.
-func NewAuthenticatedGRPCConnection(target string) (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(
-		target,
-		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(JWTClientInterceptor()),
-	)
-	if err != nil {
-		return nil, err
-	}
-	return conn, nil
-}
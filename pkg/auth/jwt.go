@@ -2,12 +2,15 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"user-risk-system/pkg/clock"
 )
 
 // JWTManager handles JWT token generation, validation, and refresh operations.
@@ -15,6 +18,7 @@ type JWTManager struct {
 	secretKey     string
 	tokenDuration time.Duration
 	issuer        string
+	clock         clock.Clock // Issues and checks expiry against it, the system clock by default
 }
 
 // Claims represents the custom JWT claims structure containing user information.
@@ -24,6 +28,9 @@ type Claims struct {
 	Email    string   `json:"email"`
 	Roles    []string `json:"roles"`
 	IssuedAt int64    `json:"iat"`
+
+	SessionID string `json:"sid,omitempty"`    // Server-side session the token was issued for
+	OrgID     string `json:"org_id,omitempty"` // Organization the user belongs to, DefaultOrgID when absent
 	jwt.RegisteredClaims
 }
 
@@ -38,25 +45,66 @@ const (
 	RoleModerator UserRole = "moderator" // Moderator with elevated permissions
 )
 
+// AllRoles lists every predefined role, for methods open to any authenticated caller.
+var AllRoles = []UserRole{RoleUser, RoleAdmin, RoleService, RoleModerator}
+
+// KnownRole returns true if role is one of the predefined roles.
+func KnownRole(role string) bool {
+	switch UserRole(role) {
+	case RoleUser, RoleAdmin, RoleService, RoleModerator:
+		return true
+	default:
+		return false
+	}
+}
+
 // NewJWTManager creates a new JWT manager instance with the specified configuration.
 func NewJWTManager(secretKey string, tokenDuration time.Duration, issuer string) *JWTManager {
 	return &JWTManager{
 		secretKey:     secretKey,
 		tokenDuration: tokenDuration,
 		issuer:        issuer,
+		clock:         clock.Real{},
 	}
 }
 
+// SetClock makes the manager issue and validate tokens against c instead of the system clock.
+func (manager *JWTManager) SetClock(c clock.Clock) {
+	manager.clock = c
+}
+
 // GenerateToken creates a new JWT token for the specified user with the given roles.
 // The token includes standard claims (issuer, audience, expiration) and custom user data.
 func (manager *JWTManager) GenerateToken(userID, email string, roles []string) (string, error) {
-	now := time.Now()
+	return manager.GenerateSessionToken(userID, email, DefaultOrgID, roles, "")
+}
+
+// ServiceContext returns ctx authenticated as the named service with a new service token,
+// forwarded by outgoing gRPC calls to methods only other services may call.
+func (manager *JWTManager) ServiceContext(ctx context.Context, service string) (context.Context, error) {
+	token, err := manager.GenerateToken(service, "", []string{string(RoleService)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate service token: %w", err)
+	}
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return ContextWithClaims(ctx, claims, token), nil
+}
+
+// GenerateSessionToken creates a new JWT token for a user of orgID bound to a server-side session.
+// the session ID is carried as the "sid" claim so services can tell which session made a call.
+func (manager *JWTManager) GenerateSessionToken(userID, email, orgID string, roles []string, sessionID string) (string, error) {
+	now := manager.clock.Now()
 
 	claims := &Claims{
-		UserID:   userID,
-		Email:    email,
-		Roles:    roles,
-		IssuedAt: now.Unix(),
+		UserID:    userID,
+		Email:     email,
+		Roles:     roles,
+		IssuedAt:  now.Unix(),
+		SessionID: sessionID,
+		OrgID:     orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    manager.issuer,
 			Subject:   userID,
@@ -82,6 +130,7 @@ func (manager *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 			}
 			return []byte(manager.secretKey), nil
 		},
+		jwt.WithTimeFunc(manager.clock.Now),
 	)
 
 	if err != nil {
@@ -104,11 +153,11 @@ func (manager *JWTManager) RefreshToken(tokenString string) (string, error) {
 	}
 
 	// Check if token is close to expiry (within 10 minutes)
-	if time.Until(claims.ExpiresAt.Time) > 10*time.Minute {
+	if claims.ExpiresAt.Time.Sub(manager.clock.Now()) > 10*time.Minute {
 		return "", fmt.Errorf("token is still valid, refresh not needed")
 	}
 
-	return manager.GenerateToken(claims.UserID, claims.Email, claims.Roles)
+	return manager.GenerateSessionToken(claims.UserID, claims.Email, claims.OrgID, claims.Roles, claims.SessionID)
 }
 
 // HasRole checks if the user has the specified role in their claims.
@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/clock"
+)
+
+func TestTokenExpiryFollowsClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	manager := auth.NewJWTManager("test-secret", time.Hour, "test")
+	manager.SetClock(fake)
+
+	token, err := manager.GenerateSessionToken("user-1", "user@example.com", "acme", []string{"user"}, "session-1")
+	if err != nil {
+		t.Fatalf("GenerateSessionToken() error = %v", err)
+	}
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if want := fake.Now().Add(time.Hour); !claims.ExpiresAt.Time.Equal(want) {
+		t.Errorf("expires at %v, want %v", claims.ExpiresAt.Time, want)
+	}
+
+	if _, err := manager.RefreshToken(token); err == nil {
+		t.Error("RefreshToken() refreshed a token an hour from expiry")
+	}
+
+	fake.Advance(55 * time.Minute)
+	refreshed, err := manager.RefreshToken(token)
+	if err != nil {
+		t.Fatalf("RefreshToken() five minutes before expiry error = %v", err)
+	}
+	refreshedClaims, err := manager.ValidateToken(refreshed)
+	if err != nil {
+		t.Fatalf("ValidateToken(refreshed) error = %v", err)
+	}
+	if want := fake.Now().Add(time.Hour); !refreshedClaims.ExpiresAt.Time.Equal(want) || refreshedClaims.SessionID != "session-1" {
+		t.Errorf("refreshed claims expire at %v with session %q, want %v and the same session", refreshedClaims.ExpiresAt.Time, refreshedClaims.SessionID, want)
+	}
+
+	fake.Advance(10 * time.Minute)
+	if _, err := manager.ValidateToken(token); err == nil {
+		t.Error("ValidateToken() accepted a token past its expiry")
+	}
+	if _, err := manager.ValidateToken(refreshed); err != nil {
+		t.Errorf("ValidateToken(refreshed) error = %v, want it still valid", err)
+	}
+}
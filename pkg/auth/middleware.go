@@ -49,13 +49,7 @@ func (a *AuthMiddleware) HTTPMiddleware(next http.Handler) http.Handler {
 		}
 
 		// Add user info to request context
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		ctx = context.WithValue(ctx, "user_email", claims.Email)
-		ctx = context.WithValue(ctx, "user_roles", claims.Roles)
-		ctx = context.WithValue(ctx, "claims", claims)
-		ctx = context.WithValue(ctx, "jwt_token", token)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims, token)))
 	})
 }
 
@@ -64,7 +58,7 @@ func (a *AuthMiddleware) HTTPMiddleware(next http.Handler) http.Handler {
 func (a *AuthMiddleware) RequireRole(roles ...UserRole) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			claims, ok := r.Context().Value("claims").(*Claims)
+			claims, ok := ClaimsFromContext(r.Context())
 			if !ok {
 				a.forbiddenHTTP(w, "Authentication required")
 				return
@@ -93,6 +87,15 @@ func (a *AuthMiddleware) GRPCUnaryInterceptor(
 		return handler(ctx, req)
 	}
 
+	ctx, err := a.authenticateGRPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticateGRPC validates the token of an incoming gRPC call and adds its claims to ctx.
+func (a *AuthMiddleware) authenticateGRPC(ctx context.Context) (context.Context, error) {
 	token, err := a.extractTokenFromGRPC(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "Missing authorization token: %v", err)
@@ -103,13 +106,61 @@ func (a *AuthMiddleware) GRPCUnaryInterceptor(
 		return nil, status.Errorf(codes.Unauthenticated, "Invalid token: %v", err)
 	}
 
-	// Add user info to gRPC context
-	ctx = context.WithValue(ctx, "user_id", claims.UserID)
-	ctx = context.WithValue(ctx, "user_email", claims.Email)
-	ctx = context.WithValue(ctx, "user_roles", claims.Roles)
-	ctx = context.WithValue(ctx, "claims", claims)
+	// Add user info to gRPC context, the token allows forwarding to downstream services
+	return ContextWithClaims(ctx, claims, token), nil
+}
 
-	return handler(ctx, req)
+// GRPCProtectMethods creates a gRPC interceptor that authenticates only the listed methods.
+// each listed method requires a valid token and one of its roles; other methods pass through,
+// so a service can guard admin methods while internal calls stay unauthenticated.
+func (a *AuthMiddleware) GRPCProtectMethods(methodRoles map[string][]UserRole) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		roles, protected := methodRoles[info.FullMethod]
+		if !protected {
+			return handler(ctx, req)
+		}
+
+		requireRole := a.GRPCRequireRole(roles...)
+		return a.GRPCUnaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return requireRole(ctx, req, info, handler)
+		})
+	}
+}
+
+// GRPCProtectStreams is the streaming counterpart of GRPCProtectMethods.
+func (a *AuthMiddleware) GRPCProtectStreams(methodRoles map[string][]UserRole) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		roles, protected := methodRoles[info.FullMethod]
+		if !protected {
+			return handler(srv, ss)
+		}
+
+		ctx, err := a.authenticateGRPC(ss.Context())
+		if err != nil {
+			return err
+		}
+		claims, _ := ClaimsFromContext(ctx)
+		if !claims.HasAnyRole(roles...) {
+			return status.Errorf(codes.PermissionDenied, "Insufficient permissions")
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authStream carries the authenticated context of a server stream.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the authenticated stream context.
+func (s *authStream) Context() context.Context {
+	return s.ctx
 }
 
 // GRPCRequireRole creates a gRPC interceptor that enforces role-based access control.
@@ -120,7 +171,7 @@ func (a *AuthMiddleware) GRPCRequireRole(roles ...UserRole) grpc.UnaryServerInte
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		claims, ok := ctx.Value("claims").(*Claims)
+		claims, ok := ClaimsFromContext(ctx)
 		if !ok {
 			return nil, status.Errorf(codes.Unauthenticated, "Authentication required")
 		}
@@ -193,6 +244,8 @@ func (a *AuthMiddleware) isPublicGRPCMethod(method string) bool {
 		"/grpc.health.v1.Health/Check",
 		"/user.UserService/Login",
 		"/user.UserService/Register",
+		"/user.UserService/ConfirmEmailChange", // Authenticated by the emailed token
+		"/user.UserService/RefreshSession",     // Authenticated by the refresh token
 	}
 
 	for _, publicMethod := range publicMethods {
@@ -0,0 +1,102 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/scontext"
+)
+
+func newSessionToken(t *testing.T) (*auth.JWTManager, string) {
+	t.Helper()
+	manager := auth.NewJWTManager("test-secret", time.Hour, "test")
+	token, err := manager.GenerateSessionToken("user-1", "user@example.com", "acme", []string{"user", "admin"}, "session-1")
+	if err != nil {
+		t.Fatalf("GenerateSessionToken() error = %v", err)
+	}
+	return manager, token
+}
+
+// assertCanonicalFields checks ctx holds the scontext fields of the token from newSessionToken.
+func assertCanonicalFields(t *testing.T, ctx context.Context, token string) {
+	t.Helper()
+	if id, _ := scontext.UserID(ctx); id != "user-1" {
+		t.Errorf("user ID = %q, want user-1", id)
+	}
+	if email, _ := scontext.Email(ctx); email != "user@example.com" {
+		t.Errorf("email = %q, want user@example.com", email)
+	}
+	if org, _ := scontext.OrgID(ctx); org != "acme" {
+		t.Errorf("org ID = %q, want acme", org)
+	}
+	if session, _ := scontext.SessionID(ctx); session != "session-1" {
+		t.Errorf("session ID = %q, want session-1", session)
+	}
+	if roles, _ := scontext.Roles(ctx); !reflect.DeepEqual(roles, []string{"user", "admin"}) {
+		t.Errorf("roles = %v, want [user admin]", roles)
+	}
+	if forwarded, _ := auth.TokenFromContext(ctx); forwarded != token {
+		t.Error("token is not kept for forwarding")
+	}
+	if !auth.IsAdmin(ctx) {
+		t.Error("IsAdmin() = false for an admin token")
+	}
+}
+
+func TestHTTPMiddlewareSetsCanonicalContext(t *testing.T) {
+	manager, token := newSessionToken(t)
+	middleware := auth.NewAuthMiddleware(manager)
+
+	var reached bool
+	handler := middleware.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		assertCanonicalFields(t, r.Context(), token)
+	}))
+
+	r := httptest.NewRequest("GET", "/api/v1/users/me", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if !reached || w.Code != http.StatusOK {
+		t.Fatalf("handler reached = %v, status = %d", reached, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/me", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want 401", w.Code)
+	}
+}
+
+func TestGRPCInterceptorSetsCanonicalContext(t *testing.T) {
+	manager, token := newSessionToken(t)
+	middleware := auth.NewAuthMiddleware(manager)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	_, err := middleware.GRPCUnaryInterceptor(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		assertCanonicalFields(t, ctx, token)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("GRPCUnaryInterceptor() error = %v", err)
+	}
+
+	_, err = middleware.GRPCUnaryInterceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		t.Error("handler called without a token")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("error without token = %v, want Unauthenticated", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package cache provides a key/value store with counters and pub/sub shared by service replicas.
+// Memory keeps everything in the process for single-instance setups, Redis shares it between
+// replicas so an invalidation published by one instance reaches all of them.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache is the store behind the gateway rate limiter and the pub/sub carrying rule cache
+// invalidations between risk engine replicas. The rules themselves stay in each replica's memory.
+type Cache interface {
+	// Get returns the value of key, ok is false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value under key for ttl, 0 keeps it until deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+	// Incr adds one to the counter under key and returns the new count.
+	// a new counter expires ttl after its first increment.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Publish sends message to every subscriber of channel, including this instance's.
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe calls handle with every message published to channel until ctx is cancelled.
+	Subscribe(ctx context.Context, channel string, handle func(message string)) error
+	// Close releases the cache's connections.
+	Close() error
+}
+
+var (
+	_ Cache = (*Memory)(nil)
+	_ Cache = (*Redis)(nil)
+)
+
+// New returns a Redis cache for redisURL, or an in-memory cache when it is empty.
+func New(redisURL string) (Cache, error) {
+	if redisURL == "" {
+		return NewMemory(), nil
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return NewRedis(client), nil
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process Cache, shared only by the goroutines of one instance.
+// expired entries are removed lazily when they are read.
+type Memory struct {
+	mu          sync.Mutex
+	entries     map[string]memoryEntry
+	subscribers map[string]map[int]func(message string) // Channel -> subscription ID -> handler
+	nextID      int
+}
+
+// memoryEntry is a stored value or counter with its expiry, zero when it doesn't expire.
+type memoryEntry struct {
+	value     []byte
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemory creates an empty in-memory cache.
+func NewMemory() *Memory {
+	return &Memory{
+		entries:     make(map[string]memoryEntry),
+		subscribers: make(map[string]map[int]func(message string)),
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.live(key, time.Now())
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := m.live(key, now)
+	if !ok && ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	entry.count++
+	m.entries[key] = entry
+	return entry.count, nil
+}
+
+func (m *Memory) Publish(ctx context.Context, channel, message string) error {
+	m.mu.Lock()
+	handlers := make([]func(string), 0, len(m.subscribers[channel]))
+	for _, handle := range m.subscribers[channel] {
+		handlers = append(handlers, handle)
+	}
+	m.mu.Unlock()
+
+	for _, handle := range handlers {
+		handle(message)
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(ctx context.Context, channel string, handle func(message string)) error {
+	m.mu.Lock()
+	if m.subscribers[channel] == nil {
+		m.subscribers[channel] = make(map[int]func(message string))
+	}
+	m.nextID++
+	id := m.nextID
+	m.subscribers[channel][id] = handle
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.subscribers[channel], id)
+	}()
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+// live returns the entry under key unless it has expired, which also drops it.
+// must be called with mu held.
+func (m *Memory) live(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
@@ -0,0 +1,82 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"user-risk-system/pkg/cache"
+)
+
+func newRedisCache(t *testing.T, server *miniredis.Miniredis) *cache.Redis {
+	t.Helper()
+	c := cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRedisIncrSetsExpiryOnFirstIncrementOnly(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := newRedisCache(t, server)
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := c.Incr(ctx, "ratelimit:client", time.Minute)
+		if err != nil {
+			t.Fatalf("Incr() error = %v", err)
+		}
+		if count != want {
+			t.Errorf("Incr() = %d, want %d", count, want)
+		}
+		if want == 1 {
+			server.FastForward(20 * time.Second)
+		}
+	}
+	if ttl := server.TTL("ratelimit:client"); ttl != 40*time.Second {
+		t.Errorf("TTL = %v, want 40s left of the window started by the first increment", ttl)
+	}
+
+	server.FastForward(40 * time.Second)
+	if count, err := c.Incr(ctx, "ratelimit:client", time.Minute); err != nil || count != 1 {
+		t.Errorf("Incr() after the window = %d, %v, want a new counter", count, err)
+	}
+}
+
+func TestRedisIncrWithoutTTLDoesNotExpire(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := newRedisCache(t, server)
+
+	if _, err := c.Incr(context.Background(), "counter", 0); err != nil {
+		t.Fatalf("Incr() error = %v", err)
+	}
+	if ttl := server.TTL("counter"); ttl != 0 {
+		t.Errorf("TTL = %v, want none", ttl)
+	}
+}
+
+func TestRedisPublishReachesOtherInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	publisher, subscriber := newRedisCache(t, server), newRedisCache(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 1)
+	if err := subscriber.Subscribe(ctx, "invalidations", func(message string) { received <- message }); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := publisher.Publish(ctx, "invalidations", "default"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case message := <-received:
+		if message != "default" {
+			t.Errorf("received %q, want default", message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber received nothing")
+	}
+}
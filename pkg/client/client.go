@@ -0,0 +1,157 @@
+// Package client provides typed gRPC clients for the internal services.
+// connections forward the caller's JWT and request ID, bound calls without a deadline
+// and retry idempotent calls that failed because the service was unavailable.
+package client
+
+import (
+	"context"
+	"time"
+
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/scontext"
+	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultTimeout      = 10 * time.Second       // Deadline of calls made without one
+	DefaultMaxRetries   = 2                      // Retries of an unavailable idempotent call after its first attempt
+	DefaultRetryBackoff = 100 * time.Millisecond // Delay before the first retry, doubled after every attempt
+)
+
+// Config defines the call behaviour of a client, zero fields use the defaults.
+type Config struct {
+	Timeout      time.Duration                 // Deadline of calls made without one
+	MaxRetries   int                           // Retries of an unavailable idempotent call, negative disables retries
+	RetryBackoff time.Duration                 // Delay before the first retry
+	DialOptions  []grpc.DialOption             // Extra options, e.g. a bufconn dialer in tests
+	Interceptors []grpc.UnaryClientInterceptor // Extra unary interceptors, run after the built-in ones
+}
+
+// withDefaults returns cfg with unset fields replaced by the defaults.
+func (cfg Config) withDefaults() Config {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	return cfg
+}
+
+// dial opens a connection to target with the interceptors of cfg.
+func dial(target string, cfg Config) (*grpc.ClientConn, error) {
+	cfg = cfg.withDefaults()
+
+	interceptors := append([]grpc.UnaryClientInterceptor{
+		forwardUnaryInterceptor(),
+		timeoutInterceptor(cfg.Timeout),
+		retryInterceptor(cfg.MaxRetries, cfg.RetryBackoff),
+	}, cfg.Interceptors...)
+
+	opts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+		grpc.WithChainStreamInterceptor(forwardStreamInterceptor()),
+	}, cfg.DialOptions...)
+
+	return grpc.Dial(target, opts...)
+}
+
+// forwardUnaryInterceptor attaches the caller's JWT and request ID to outgoing calls.
+func forwardUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// forwardStreamInterceptor attaches the caller's JWT and request ID to outgoing streams.
+func forwardStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// outgoingContext adds the authorization and request ID metadata of ctx, when present.
+func outgoingContext(ctx context.Context) context.Context {
+	if token, ok := auth.TokenFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	if requestID, ok := scontext.RequestID(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, scontext.RequestIDMetadataKey, requestID)
+	}
+	return ctx
+}
+
+// timeoutInterceptor bounds calls made without a deadline to timeout.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// idempotentMethods are the calls safe to repeat, reads and writes whose repetition has no further effect.
+// CheckRisk isn't one, every check stores its result and may publish a level change.
+var idempotentMethods = map[string]bool{
+	pb_user.UserService_GetUser_FullMethodName:        true,
+	pb_user.UserService_GetUserByEmail_FullMethodName: true,
+	pb_user.UserService_ListUsers_FullMethodName:      true,
+	pb_user.UserService_ListSessions_FullMethodName:   true,
+
+	pb_risk.RiskAdminService_ListRiskRules_FullMethodName:         true,
+	pb_risk.RiskAdminService_GetRiskStats_FullMethodName:          true,
+	pb_risk.RiskAdminService_ListUsersByRiskLevel_FullMethodName:  true,
+	pb_risk.RiskAdminService_ListDisposableDomains_FullMethodName: true,
+	pb_risk.RiskAdminService_GetCacheStats_FullMethodName:         true,
+	pb_risk.RiskAdminService_InvalidateCache_FullMethodName:       true,
+	pb_risk.RiskAdminService_ListFeatureFlags_FullMethodName:      true,
+	pb_risk.RiskAdminService_GetRiskHistory_FullMethodName:        true,
+
+	pb_notification.NotificationService_PreviewTemplate_FullMethodName:       true,
+	pb_notification.NotificationService_ListTemplates_FullMethodName:         true,
+	pb_notification.NotificationService_ListSuppressions_FullMethodName:      true,
+	pb_notification.NotificationService_GetNotificationStatus_FullMethodName: true,
+}
+
+// retryInterceptor retries idempotent calls failing with Unavailable. Unavailable doesn't tell whether
+// the service handled the request before failing, so other calls are never repeated. Requests that
+// never left the client are already retried transparently by gRPC. health checks aren't in
+// idempotentMethods, their callers poll on their own.
+func retryInterceptor(maxRetries int, backoff time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !idempotentMethods[method] {
+			return err
+		}
+
+		delay := backoff
+		for attempt := 0; attempt < maxRetries && status.Code(err) == codes.Unavailable; attempt++ {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return err
+			}
+			delay *= 2
+			err = invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryInterceptorRetriesIdempotentCallsOnly(t *testing.T) {
+	tests := []struct {
+		method    string
+		code      codes.Code
+		wantCalls int
+	}{
+		{pb_user.UserService_GetUser_FullMethodName, codes.Unavailable, 3},
+		{pb_risk.RiskAdminService_ListRiskRules_FullMethodName, codes.Unavailable, 3},
+		{pb_user.UserService_GetUser_FullMethodName, codes.Internal, 1},
+		{pb_user.UserService_Register_FullMethodName, codes.Unavailable, 1},
+		{pb_risk.RiskService_CheckRisk_FullMethodName, codes.Unavailable, 1},
+		{pb_risk.RiskAdminService_CreateRiskRule_FullMethodName, codes.Unavailable, 1},
+		{"/grpc.health.v1.Health/Check", codes.Unavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+"/"+tt.code.String(), func(t *testing.T) {
+			calls := 0
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				calls++
+				return status.Error(tt.code, "down")
+			}
+
+			err := retryInterceptor(2, time.Millisecond)(context.Background(), tt.method, nil, nil, nil, invoker)
+			if status.Code(err) != tt.code {
+				t.Errorf("error code = %v, want %v", status.Code(err), tt.code)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
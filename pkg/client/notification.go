@@ -0,0 +1,35 @@
+package client
+
+import (
+	pb_notification "user-risk-system/proto/notification"
+
+	"google.golang.org/grpc"
+)
+
+// NotificationClient calls the notification service.
+type NotificationClient struct {
+	pb_notification.NotificationServiceClient
+	conn *grpc.ClientConn
+}
+
+// NewNotificationClient connects to the notification service at target.
+func NewNotificationClient(target string, cfg Config) (*NotificationClient, error) {
+	conn, err := dial(target, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationClient{
+		NotificationServiceClient: pb_notification.NewNotificationServiceClient(conn),
+		conn:                      conn,
+	}, nil
+}
+
+// Conn returns the underlying connection, e.g. for health checks.
+func (c *NotificationClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the underlying connection.
+func (c *NotificationClient) Close() error {
+	return c.conn.Close()
+}
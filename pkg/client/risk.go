@@ -0,0 +1,37 @@
+package client
+
+import (
+	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/grpc"
+)
+
+// RiskClient calls the risk engine's risk and admin services over one connection.
+type RiskClient struct {
+	pb_risk.RiskServiceClient
+	Admin pb_risk.RiskAdminServiceClient // Admin methods, they require a forwarded admin JWT
+	conn  *grpc.ClientConn
+}
+
+// NewRiskClient connects to the risk engine at target.
+func NewRiskClient(target string, cfg Config) (*RiskClient, error) {
+	conn, err := dial(target, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RiskClient{
+		RiskServiceClient: pb_risk.NewRiskServiceClient(conn),
+		Admin:             pb_risk.NewRiskAdminServiceClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+// Conn returns the underlying connection, e.g. for health checks.
+func (c *RiskClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the underlying connection.
+func (c *RiskClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc"
+)
+
+// UserClient calls the user service.
+type UserClient struct {
+	pb_user.UserServiceClient
+	conn *grpc.ClientConn
+}
+
+// NewUserClient connects to the user service at target.
+func NewUserClient(target string, cfg Config) (*UserClient, error) {
+	conn, err := dial(target, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &UserClient{
+		UserServiceClient: pb_user.NewUserServiceClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+// GetUserByID returns the user with the given ID.
+func (c *UserClient) GetUserByID(ctx context.Context, userID string) (*pb_user.User, error) {
+	resp, err := c.GetUser(ctx, &pb_user.GetUserRequest{Id: userID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// Conn returns the underlying connection, e.g. for health checks.
+func (c *UserClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the underlying connection.
+func (c *UserClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"user-risk-system/pkg/config"
+)
+
+func setProduction(t *testing.T) {
+	t.Helper()
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+}
+
+func TestLoadGatewayRequiresOnlyItsKeys(t *testing.T) {
+	setProduction(t)
+
+	cfg, err := config.Load(config.Require("JWT_SECRET"))
+	if err != nil {
+		t.Fatalf("load without database or broker URLs: %v", err)
+	}
+	if cfg.RabbitMQURL != "" || cfg.RiskDatabaseURL != "" {
+		t.Errorf("development defaults applied in production: rabbitmq=%q risk_db=%q", cfg.RabbitMQURL, cfg.RiskDatabaseURL)
+	}
+}
+
+func TestLoadReportsMissingRequiredKeys(t *testing.T) {
+	setProduction(t)
+
+	_, err := config.Load(config.Require("JWT_SECRET", "DATABASE_URL", "RABBITMQ_URL"))
+	if err == nil {
+		t.Fatal("load succeeded without DATABASE_URL and RABBITMQ_URL")
+	}
+	for _, key := range []string{"DATABASE_URL", "RABBITMQ_URL"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not name %s", err, key)
+		}
+	}
+}
+
+func TestLoadDevelopmentUsesDefaults(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+
+	cfg, err := config.Load(config.Require("RABBITMQ_URL"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.RabbitMQURL == "" {
+		t.Error("RABBITMQ_URL has no development default")
+	}
+}
+
+func TestLoadRejectsInvalidTrustedProxies(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, proxy.internal")
+
+	_, err := config.Load(config.Require("JWT_SECRET"))
+	if err == nil || !strings.Contains(err.Error(), "TRUSTED_PROXIES") {
+		t.Fatalf("load error = %v, want one naming TRUSTED_PROXIES", err)
+	}
+}
+
+func TestLoadWebhookOrgSecrets(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("WEBHOOK_ORG_SECRETS", "acme=acme-secret, Globex = globex-secret")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.WebhookSecrets["acme"] != "acme-secret" || cfg.WebhookSecrets["Globex"] != "globex-secret" {
+		t.Errorf("WebhookSecrets = %v", cfg.WebhookSecrets)
+	}
+	if masked := fmt.Sprint(cfg.Masked()); strings.Contains(masked, "acme-secret") {
+		t.Errorf("Masked() leaks an organization secret: %s", masked)
+	}
+}
+
+func TestLoadEmailRecipientsByType(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("EMAIL_CC", "critical_risk_alert=security@example.com| soc@example.com,RISK_DETECTED=risk@example.com")
+	t.Setenv("EMAIL_BCC", "CRITICAL_RISK_ALERT=audit@example.com")
+	t.Setenv("EMAIL_REPLY_TO", "CRITICAL_RISK_ALERT=support@example.com")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.EmailCC["CRITICAL_RISK_ALERT"]; !reflect.DeepEqual(got, []string{"security@example.com", "soc@example.com"}) {
+		t.Errorf("EmailCC[CRITICAL_RISK_ALERT] = %v", got)
+	}
+	if got := cfg.EmailCC["RISK_DETECTED"]; !reflect.DeepEqual(got, []string{"risk@example.com"}) {
+		t.Errorf("EmailCC[RISK_DETECTED] = %v", got)
+	}
+	if got := cfg.EmailBCC["CRITICAL_RISK_ALERT"]; !reflect.DeepEqual(got, []string{"audit@example.com"}) {
+		t.Errorf("EmailBCC[CRITICAL_RISK_ALERT] = %v", got)
+	}
+	if got := cfg.EmailReplyTo["CRITICAL_RISK_ALERT"]; got != "support@example.com" {
+		t.Errorf("EmailReplyTo[CRITICAL_RISK_ALERT] = %q", got)
+	}
+}
+
+func TestLoadRejectsMalformedWebhookOrgSecrets(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("WEBHOOK_ORG_SECRETS", "acme=acme-secret,bare-secret")
+
+	_, err := config.Load()
+	if err == nil || !strings.Contains(err.Error(), "WEBHOOK_ORG_SECRETS") {
+		t.Fatalf("load error = %v, want one naming WEBHOOK_ORG_SECRETS", err)
+	}
+}
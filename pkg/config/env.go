@@ -5,17 +5,23 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 // EnvLoader provides methods for loading and parsing environment variables with default values.
-type EnvLoader struct{}
+// values from an optional config file are used when the environment variable is not set.
+type EnvLoader struct {
+	mu         sync.RWMutex // Guards fileValues, replaced by LoadFile on reload while services read settings
+	fileValues map[string]string
+}
 
 var Env = &EnvLoader{}
 
 // String loads a string environment variable with a default value fallback.
 func (e *EnvLoader) String(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		return value
 	}
 	return defaultValue
@@ -23,7 +29,7 @@ func (e *EnvLoader) String(key, defaultValue string) string {
 
 // Int loads an integer environment variable with a default value fallback.
 func (e *EnvLoader) Int(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
@@ -33,7 +39,7 @@ func (e *EnvLoader) Int(key string, defaultValue int) int {
 
 // Int64 loads a 64-bit integer environment variable with a default value fallback.
 func (e *EnvLoader) Int64(key string, defaultValue int64) int64 {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		if int64Value, err := strconv.ParseInt(value, 10, 64); err == nil {
 			return int64Value
 		}
@@ -43,7 +49,7 @@ func (e *EnvLoader) Int64(key string, defaultValue int64) int64 {
 
 // Float64 loads a float64 environment variable with a default value fallback.
 func (e *EnvLoader) Float64(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
 			return floatValue
 		}
@@ -53,7 +59,7 @@ func (e *EnvLoader) Float64(key string, defaultValue float64) float64 {
 
 // Bool loads a boolean environment variable with a default value fallback.
 func (e *EnvLoader) Bool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
 			return boolValue
 		}
@@ -63,7 +69,7 @@ func (e *EnvLoader) Bool(key string, defaultValue bool) bool {
 
 // Duration loads a time.Duration environment variable with a default value fallback.
 func (e *EnvLoader) Duration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
@@ -71,17 +77,59 @@ func (e *EnvLoader) Duration(key string, defaultValue time.Duration) time.Durati
 	return defaultValue
 }
 
+// StringSlice loads a separated list environment variable with a default value fallback.
+// entries are trimmed and empty entries are dropped.
+func (e *EnvLoader) StringSlice(key, sep string, defaultValue []string) []string {
+	value := e.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
+// lookup returns the environment variable for key, falling back to the config file value.
+func (e *EnvLoader) lookup(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.fileValues[key]
+}
+
 // StringRequired loads a required string environment variable, returning an error if not set.
 func (e *EnvLoader) StringRequired(key string) (string, error) {
-	if value := os.Getenv(key); value != "" {
+	if value := e.lookup(key); value != "" {
 		return value, nil
 	}
 	return "", fmt.Errorf("required environment variable %s is not set", key)
 }
 
+// StringRequiredIf loads a string environment variable that is required only when required is true.
+// an unset variable returns an empty string and no error when it is not required.
+func (e *EnvLoader) StringRequiredIf(key string, required bool) (string, error) {
+	if value := e.lookup(key); value != "" {
+		return value, nil
+	}
+	if required {
+		return "", fmt.Errorf("required environment variable %s is not set", key)
+	}
+	return "", nil
+}
+
 // IntRequired loads a required integer environment variable, returning an error if not set or invalid.
 func (e *EnvLoader) IntRequired(key string) (int, error) {
-	value := os.Getenv(key)
+	value := e.lookup(key)
 	if value == "" {
 		return 0, fmt.Errorf("required environment variable %s is not set", key)
 	}
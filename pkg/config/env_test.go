@@ -0,0 +1,34 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"user-risk-system/pkg/config"
+)
+
+func TestStringSliceTrimsAndDropsEmptyEntries(t *testing.T) {
+	defaults := []string{"default"}
+
+	tests := []struct {
+		name  string
+		value string
+		sep   string
+		want  []string
+	}{
+		{"plain list", "a,b", ",", []string{"a", "b"}},
+		{"spaced and empty entries", "a, ,b,", ",", []string{"a", "b"}},
+		{"padded entries", "  a ;\tb  ", ";", []string{"a", "b"}},
+		{"only separators", " , ,", ",", defaults},
+		{"unset", "", ",", defaults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_STRING_SLICE", tt.value)
+
+			if got := config.Env.StringSlice("TEST_STRING_SLICE", tt.sep, defaults); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StringSlice(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
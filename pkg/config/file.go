@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConfigFileEnv names the environment variable pointing at an optional config file.
+const ConfigFileEnv = "CONFIG_FILE"
+
+// LoadFile reads a JSON or YAML config file whose values are used as defaults.
+// keys are environment variable names (case-insensitive), environment variables always take precedence.
+// YAML support is limited to flat "KEY: value" mappings, which is all the settings need.
+func (e *EnvLoader) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		values, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		values, err = parseYAMLConfig(data)
+	default:
+		return fmt.Errorf("unsupported config file format %q, expected .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	e.mu.Lock()
+	e.fileValues = values
+	e.mu.Unlock()
+	return nil
+}
+
+// parseJSONConfig parses a flat JSON object, scalars are converted to their string form.
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[normalizeConfigKey(key)] = v
+		case json.Number, bool:
+			values[normalizeConfigKey(key)] = fmt.Sprint(v)
+		case []interface{}:
+			// Lists map onto comma-separated settings such as ALLOWED_CORS
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[normalizeConfigKey(key)] = strings.Join(items, ",")
+		case nil:
+		default:
+			return nil, fmt.Errorf("unsupported value for %s: nested objects are not allowed", key)
+		}
+	}
+	return values, nil
+}
+
+// parseYAMLConfig parses flat "KEY: value" YAML, comments and quoted values are supported.
+func parseYAMLConfig(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return nil, fmt.Errorf("line %d: nested values are not supported", lineNo)
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("line %d: expected KEY: value", lineNo)
+		}
+
+		value, err := parseYAMLScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		values[normalizeConfigKey(key)] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseYAMLScalar unquotes a YAML scalar and strips trailing comments.
+func parseYAMLScalar(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := strings.LastIndex(value, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strconv.Unquote(value[:end+1])
+	case strings.HasPrefix(value, "'"):
+		end := strings.LastIndex(value, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strings.ReplaceAll(value[1:end], "''", "'"), nil
+	}
+
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	if value == "~" || value == "null" {
+		return "", nil
+	}
+	return value, nil
+}
+
+// normalizeConfigKey maps file keys onto environment variable names.
+func normalizeConfigKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
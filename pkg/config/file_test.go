@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/config"
+)
+
+// useConfigFile writes content to a config file named name and points CONFIG_FILE at it.
+// the values loaded from it are cleared again when t ends.
+func useConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.ConfigFileEnv, path)
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.Env.LoadFile(empty) })
+}
+
+func TestLoadConfigFileWithEnvOverride(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": "# local development\nENVIRONMENT: development\nrate_limit_requests: 42\nRATE_LIMIT_WINDOW: \"30s\"\nALLOWED_CORS: 'http://a.test,http://b.test'\nJWT_ISSUER: from-file # overridden\n",
+		"config.json": `{"ENVIRONMENT": "development", "rate_limit_requests": 42, "RATE_LIMIT_WINDOW": "30s", "ALLOWED_CORS": ["http://a.test", "http://b.test"], "JWT_ISSUER": "from-file"}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			useConfigFile(t, name, content)
+			t.Setenv("JWT_ISSUER", "from-env")
+
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.RateLimitRequests != 42 || cfg.RateLimitWindow != 30*time.Second {
+				t.Errorf("rate limit = %d per %v, want the file's 42 per 30s", cfg.RateLimitRequests, cfg.RateLimitWindow)
+			}
+			if len(cfg.AllowedOrigins) != 2 || cfg.AllowedOrigins[1] != "http://b.test" {
+				t.Errorf("AllowedOrigins = %v, want both origins of the file", cfg.AllowedOrigins)
+			}
+			if cfg.JWTIssuer != "from-env" {
+				t.Errorf("JWTIssuer = %q, want the environment to override the file", cfg.JWTIssuer)
+			}
+		})
+	}
+}
+
+func TestLoadValidatesConfigFileValues(t *testing.T) {
+	useConfigFile(t, "config.yaml", "ENVIRONMENT: development\nRISK_FLAG_FORMAT: emoji\n")
+
+	_, err := config.Load()
+	if err == nil || !strings.Contains(err.Error(), "RISK_FLAG_FORMAT") {
+		t.Fatalf("load error = %v, want the file's invalid RISK_FLAG_FORMAT reported", err)
+	}
+}
+
+func TestLoadRejectsMalformedConfigFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"nested YAML", "config.yaml", "DATABASE:\n  URL: postgres://db\n"},
+		{"YAML without separator", "config.yml", "LOG_LEVEL debug\n"},
+		{"nested JSON", "config.json", `{"DATABASE": {"URL": "postgres://db"}}`},
+		{"unsupported format", "config.toml", "LOG_LEVEL = \"debug\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfigFile(t, tt.file, tt.content)
+			if _, err := config.Load(); err == nil {
+				t.Error("load succeeded, want the config file rejected")
+			}
+		})
+	}
+}
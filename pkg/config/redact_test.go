@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"user-risk-system/pkg/config"
+)
+
+func TestMaskPassword(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"URL with credentials", "postgres://risk:s3cret@db:5432/risk?sslmode=require", "postgres://risk:***@db:5432/risk?sslmode=require"},
+		{"URL without credentials", "redis://redis:6379/0", "redis://redis:6379/0"},
+		{"URL with user only", "amqp://guest@rabbitmq:5672/", "amqp://guest@rabbitmq:5672/"},
+		{"password with reserved characters", "postgres://risk:p@ss/word@db:5432/risk", "postgres://***@db:5432/risk"},
+		{"key=value DSN", "host=db user=risk password=s3cret dbname=risk", "host=db user=risk password=*** dbname=risk"},
+		{"quoted key=value password", "host=db password='s3 cret' dbname=risk", "host=db password=*** dbname=risk"},
+		{"password query parameter", "postgres://db:5432/risk?user=risk&password=s3cret", "postgres://db:5432/risk?user=risk&password=***"},
+		{"SQLite path", "file:risk.db?cache=shared", "file:risk.db?cache=shared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.MaskPassword(tt.dsn); got != tt.want {
+				t.Errorf("MaskPassword(%q) = %q, want %q", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactedConfigHidesSecrets(t *testing.T) {
+	secrets := []string{"jwt-secret-value", "db-pass", "risk-pass", "sendgrid-key", "twilio-token", "webhook-secret", "acme-secret", "https://hooks.slack.com/services/T0/B0/xyz"}
+	cfg := &config.Config{
+		JWTSecret:       secrets[0],
+		DatabaseURL:     "postgres://user:" + secrets[1] + "@db:5432/users",
+		RiskDatabaseURL: "host=db password=" + secrets[2],
+		SendGridAPIKey:  secrets[3],
+		TwilioAuthToken: secrets[4],
+		WebhookSecret:   secrets[5],
+		WebhookSecrets:  map[string]string{"acme": secrets[6]},
+		SlackWebhookURL: secrets[7],
+		JWTIssuer:       "user-risk-system",
+	}
+
+	for name, output := range map[string]string{
+		"Redact": fmt.Sprintf("%+v", *cfg.Redact()),
+		"Masked": fmt.Sprint(cfg.Masked()),
+	} {
+		for _, secret := range secrets {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s output contains secret %q", name, secret)
+			}
+		}
+		if !strings.Contains(output, "user-risk-system") {
+			t.Errorf("%s output lost non-secret settings", name)
+		}
+	}
+	if cfg.JWTSecret != secrets[0] || cfg.WebhookSecrets["acme"] != secrets[6] {
+		t.Error("Redact modified the original configuration")
+	}
+}
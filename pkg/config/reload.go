@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"user-risk-system/pkg/features"
+	"user-risk-system/pkg/logger"
+)
+
+// RiskThresholds holds the minimum total score for each risk level.
+type RiskThresholds struct {
+	Low      int // Score at which risk becomes LOW
+	Medium   int // Score at which risk becomes MEDIUM (risky)
+	High     int // Score at which risk becomes HIGH
+	Critical int // Score at which risk becomes CRITICAL
+}
+
+// RISK_LEVEL_EVENTS values selecting which risk level changes are published.
+const (
+	RiskLevelEventsIncrease = "increase" // Only changes to a higher level
+	RiskLevelEventsAny      = "any"      // Increases and decreases
+	RiskLevelEventsOff      = "off"      // No risk.level_changed events
+)
+
+// Reloadable holds the settings that can change at runtime without a restart.
+// connection-level settings (URLs, credentials, ports) are deliberately excluded.
+type Reloadable struct {
+	LogLevel               string         // Logging level (debug, info, warn, error)
+	RateLimitRequests      int            // Maximum requests per rate limit window
+	RateLimitWindow        time.Duration  // Rate limiting time window
+	RuleCacheTTL           time.Duration  // How long the risk engine caches rules
+	RuleMaxExpiresInDays   int            // Longest expires_in_days a risk rule accepts, 0 (permanent) is always allowed
+	BroadcastRatePerSecond int            // Maximum broadcast notifications enqueued per second
+	RecheckRatePerSecond   int            // Maximum risk checks per second run by RecheckUsers
+	RiskThresholds         RiskThresholds // Score thresholds for risk levels
+	StopOnCriticalMatch    bool           // Stop evaluating a category once one rule alone scores CRITICAL
+	NormalizeNames         bool           // Compare names with diacritics stripped, so "José" matches a "jose" rule
+	CategoryScoreCap       int            // Maximum score one category (EMAIL, NAME, PHONE) can add, 0 is uncapped
+	DedupFlagScores        bool           // Score only the highest rule when several rules of the same category and type match
+	AnalyticsSampleRate    int            // Store 1 in N non-risky checks for analytics, risky checks are always stored
+	RiskLevelEvents        string         // Risk level changes published as risk.level_changed: increase, any or off
+	FeatureFlags           features.Set   // Per-request rollout of new risk logic, see pkg/features
+
+	NotificationThrottleLimit         int           // Notifications of one type a user gets per window, 0 is unlimited
+	NotificationThrottleCriticalLimit int           // Same for notifications with risk_level CRITICAL, 0 exempts them
+	NotificationThrottleWindow        time.Duration // Length of a throttling window
+
+	NotificationFallback map[string][]string // Ordered channels per notification type, each tried only if the previous failed
+
+	featureFlagsErr error // Parse failure of FEATURE_FLAGS, reported by validate
+}
+
+// loadReloadable reads the reloadable settings from the environment and config file.
+func loadReloadable() Reloadable {
+	flags, flagsErr := features.Parse(Env.String("FEATURE_FLAGS", ""))
+	return Reloadable{
+		LogLevel:               Env.String("LOG_LEVEL", "info"),
+		RateLimitRequests:      Env.Int("RATE_LIMIT_REQUESTS", 100),
+		RateLimitWindow:        Env.Duration("RATE_LIMIT_WINDOW", time.Minute),
+		RuleCacheTTL:           Env.Duration("RULE_CACHE_TTL", 5*time.Minute),
+		RuleMaxExpiresInDays:   Env.Int("RULE_MAX_EXPIRES_IN_DAYS", 365),
+		BroadcastRatePerSecond: Env.Int("BROADCAST_RATE_PER_SECOND", 20),
+		RecheckRatePerSecond:   Env.Int("RECHECK_RATE_PER_SECOND", 20),
+		RiskThresholds: RiskThresholds{
+			Low:      Env.Int("RISK_THRESHOLD_LOW", 20),
+			Medium:   Env.Int("RISK_THRESHOLD_MEDIUM", 40),
+			High:     Env.Int("RISK_THRESHOLD_HIGH", 80),
+			Critical: Env.Int("RISK_THRESHOLD_CRITICAL", 100),
+		},
+		StopOnCriticalMatch: Env.Bool("RISK_STOP_ON_CRITICAL_MATCH", false),
+		NormalizeNames:      Env.Bool("RISK_NORMALIZE_NAMES", true),
+		CategoryScoreCap:    Env.Int("RISK_CATEGORY_SCORE_CAP", 0),
+		DedupFlagScores:     Env.Bool("RISK_DEDUP_FLAG_SCORES", false),
+		AnalyticsSampleRate: Env.Int("ANALYTICS_SAMPLE_RATE", 1),
+		RiskLevelEvents:     strings.ToLower(Env.String("RISK_LEVEL_EVENTS", RiskLevelEventsIncrease)),
+		FeatureFlags:        flags,
+
+		NotificationThrottleLimit:         Env.Int("NOTIFICATION_THROTTLE_LIMIT", 5),
+		NotificationThrottleCriticalLimit: Env.Int("NOTIFICATION_THROTTLE_CRITICAL_LIMIT", 0),
+		NotificationThrottleWindow:        Env.Duration("NOTIFICATION_THROTTLE_WINDOW", time.Hour),
+		NotificationFallback:              listsByType(strings.ToUpper(Env.String("NOTIFICATION_FALLBACK", ""))),
+		featureFlagsErr:                   flagsErr,
+	}
+}
+
+// validate records settings that would break components reading them.
+func (r Reloadable) validate(report *ValidationReport) {
+	t := r.RiskThresholds
+	if !(0 < t.Low && t.Low < t.Medium && t.Medium < t.High && t.High < t.Critical) {
+		report.fail("RISK_THRESHOLD_LOW", "risk thresholds must be positive and increasing, got low=%d medium=%d high=%d critical=%d",
+			t.Low, t.Medium, t.High, t.Critical)
+	}
+	if r.CategoryScoreCap < 0 {
+		report.fail("RISK_CATEGORY_SCORE_CAP", "must not be negative, use 0 for uncapped")
+	}
+	if r.featureFlagsErr != nil {
+		report.fail("FEATURE_FLAGS", "%v", r.featureFlagsErr)
+	}
+	if r.NotificationThrottleLimit < 0 || r.NotificationThrottleCriticalLimit < 0 {
+		report.fail("NOTIFICATION_THROTTLE_LIMIT", "throttle limits must not be negative, use 0 for unlimited")
+	}
+	if r.NotificationThrottleWindow <= 0 {
+		report.fail("NOTIFICATION_THROTTLE_WINDOW", "must be positive")
+	}
+	for notificationType, chain := range r.NotificationFallback {
+		if notificationType == "" {
+			report.fail("NOTIFICATION_FALLBACK", "entries must be TYPE=CHANNEL|CHANNEL")
+			continue
+		}
+		for _, channel := range chain {
+			switch channel {
+			case "EMAIL", "SMS", "PUSH", "WEBHOOK", "SLACK":
+			default:
+				report.fail("NOTIFICATION_FALLBACK", "unknown channel %q for %s, expected EMAIL, SMS, PUSH, WEBHOOK or SLACK", channel, notificationType)
+			}
+		}
+	}
+	if r.RuleCacheTTL < 0 {
+		report.fail("RULE_CACHE_TTL", "must not be negative")
+	}
+	if r.RuleMaxExpiresInDays <= 0 {
+		report.fail("RULE_MAX_EXPIRES_IN_DAYS", "must be positive")
+	}
+	if r.AnalyticsSampleRate < 1 {
+		report.fail("ANALYTICS_SAMPLE_RATE", "must be at least 1, use 1 to store every check")
+	}
+	switch r.RiskLevelEvents {
+	case RiskLevelEventsIncrease, RiskLevelEventsAny, RiskLevelEventsOff:
+	default:
+		report.fail("RISK_LEVEL_EVENTS", "must be one of %s, %s or %s", RiskLevelEventsIncrease, RiskLevelEventsAny, RiskLevelEventsOff)
+	}
+	if r.RecheckRatePerSecond <= 0 {
+		report.fail("RECHECK_RATE_PER_SECOND", "must be positive")
+	}
+	if r.RateLimitRequests <= 0 {
+		report.fail("RATE_LIMIT_REQUESTS", "must be positive")
+	}
+	if r.RateLimitWindow <= 0 {
+		report.fail("RATE_LIMIT_WINDOW", "must be positive")
+	}
+}
+
+// Settings gives components access to the current reloadable settings.
+// values are swapped atomically on reload, so components must call Current on every use
+// instead of capturing values at startup.
+type Settings struct {
+	current   atomic.Pointer[Reloadable]
+	mu        sync.Mutex
+	listeners []func(Reloadable)
+}
+
+// NewSettings creates a settings accessor holding the given initial values.
+func NewSettings(initial Reloadable) *Settings {
+	s := &Settings{}
+	s.current.Store(&initial)
+	return s
+}
+
+// Current returns a snapshot of the reloadable settings.
+func (s *Settings) Current() Reloadable {
+	return *s.current.Load()
+}
+
+// OnReload registers a callback invoked with the new settings after every successful reload.
+func (s *Settings) OnReload(fn func(Reloadable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload re-reads the config file (if any) and environment and swaps in the new settings.
+// invalid settings are rejected and the previous values stay in effect.
+func (s *Settings) Reload() (Reloadable, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		if err := Env.LoadFile(path); err != nil {
+			return s.Current(), err
+		}
+	}
+
+	next := loadReloadable()
+	report := &ValidationReport{}
+	next.validate(report)
+	if err := report.Err(); err != nil {
+		return s.Current(), err
+	}
+
+	s.current.Store(&next)
+	for _, fn := range s.listeners {
+		fn(next)
+	}
+	return next, nil
+}
+
+// WatchSignals reloads the settings on SIGHUP until ctx is cancelled.
+// the logger level follows LOG_LEVEL after each reload.
+func (s *Settings) WatchSignals(ctx context.Context, l *logger.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				next, err := s.Reload()
+				if err != nil {
+					l.Error("Failed to reload configuration, keeping previous settings", err)
+					continue
+				}
+				l.SetLevel(next.LogLevel)
+				l.Info("Configuration reloaded",
+					"log_level", next.LogLevel,
+					"rate_limit_requests", next.RateLimitRequests,
+					"rate_limit_window", next.RateLimitWindow.String(),
+					"rule_cache_ttl", next.RuleCacheTTL.String(),
+					"rule_max_expires_in_days", next.RuleMaxExpiresInDays,
+					"broadcast_rate_per_second", next.BroadcastRatePerSecond,
+					"recheck_rate_per_second", next.RecheckRatePerSecond,
+					"stop_on_critical_match", next.StopOnCriticalMatch,
+					"normalize_names", next.NormalizeNames,
+					"category_score_cap", next.CategoryScoreCap,
+					"dedup_flag_scores", next.DedupFlagScores,
+					"analytics_sample_rate", next.AnalyticsSampleRate,
+					"risk_level_events", next.RiskLevelEvents,
+					"feature_flags", len(next.FeatureFlags),
+					"notification_throttle_limit", next.NotificationThrottleLimit,
+					"notification_throttle_critical_limit", next.NotificationThrottleCriticalLimit,
+					"notification_throttle_window", next.NotificationThrottleWindow.String(),
+					"notification_fallback", len(next.NotificationFallback),
+				)
+			}
+		}
+	}()
+}
@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+)
+
+func TestWatchSignalsReloadsChangedSettings(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("RATE_LIMIT_REQUESTS", "100")
+	t.Setenv("NOTIFICATION_FALLBACK", "CRITICAL_RISK_ALERT=EMAIL")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg.Settings().WatchSignals(ctx, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+
+	t.Setenv("RATE_LIMIT_REQUESTS", "7")
+	t.Setenv("NOTIFICATION_FALLBACK", "CRITICAL_RISK_ALERT=SMS|EMAIL")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("send SIGHUP: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for cfg.Settings().Current().RateLimitRequests != 7 {
+		if time.Now().After(deadline) {
+			t.Fatalf("RateLimitRequests = %d after SIGHUP, want 7", cfg.Settings().Current().RateLimitRequests)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if chain := cfg.Settings().Current().NotificationFallback["CRITICAL_RISK_ALERT"]; len(chain) != 2 || chain[0] != "SMS" {
+		t.Errorf("fallback chain after reload = %v, want [SMS EMAIL]", chain)
+	}
+	if cfg.RateLimitRequests != 100 {
+		t.Errorf("startup RateLimitRequests changed to %d", cfg.RateLimitRequests)
+	}
+}
+
+func TestReloadKeepsPreviousSettingsWhenInvalid(t *testing.T) {
+	t.Setenv("NOTIFICATION_FALLBACK", "CRITICAL_RISK_ALERT=EMAIL")
+	cfg := &config.Config{}
+	settings := cfg.Settings()
+
+	t.Setenv("NOTIFICATION_FALLBACK", "CRITICAL_RISK_ALERT=PIGEON")
+	if _, err := settings.Reload(); err == nil {
+		t.Fatal("reload accepted an unknown fallback channel")
+	}
+	if chain := settings.Current().NotificationFallback["CRITICAL_RISK_ALERT"]; len(chain) != 1 || chain[0] != "EMAIL" {
+		t.Errorf("fallback chain = %v, want the previous [EMAIL]", chain)
+	}
+}
+
+func TestReloadWhileReadingSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"RULE_CACHE_TTL": "1m"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.ConfigFileEnv, path)
+	cfg := &config.Config{}
+	if _, err := cfg.Settings().Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if ttl := cfg.Settings().Current().RuleCacheTTL; ttl != time.Minute {
+					t.Errorf("RuleCacheTTL = %v, want 1m", ttl)
+					return
+				}
+				_ = config.Env.String("RULE_CACHE_TTL", "")
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := cfg.Settings().Reload(); err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+	}
+	wg.Wait()
+}
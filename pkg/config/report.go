@@ -0,0 +1,488 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"user-risk-system/pkg/logger"
+)
+
+// Severity classifies a configuration issue.
+type Severity string
+
+const (
+	SeverityWarning Severity = "WARNING" // Likely wrong but the service can start
+	SeverityError   Severity = "ERROR"   // Fatal, the service refuses to start
+)
+
+// ValidationIssue describes a single configuration problem.
+type ValidationIssue struct {
+	Setting  string   // Environment variable the issue refers to
+	Message  string   // Human-readable explanation
+	Severity Severity // Whether the issue is fatal
+}
+
+// ValidationReport collects configuration issues found during startup validation.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// warn records a non-fatal issue.
+func (r *ValidationReport) warn(setting, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{Setting: setting, Message: fmt.Sprintf(format, args...), Severity: SeverityWarning})
+}
+
+// fail records a fatal issue.
+func (r *ValidationReport) fail(setting, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{Setting: setting, Message: fmt.Sprintf(format, args...), Severity: SeverityError})
+}
+
+// hasError returns true if a fatal issue was already recorded for setting.
+func (r *ValidationReport) hasError(setting string) bool {
+	for _, issue := range r.Errors() {
+		if issue.Setting == setting {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns the non-fatal issues.
+func (r *ValidationReport) Warnings() []ValidationIssue {
+	return r.filter(SeverityWarning)
+}
+
+// Errors returns the fatal issues.
+func (r *ValidationReport) Errors() []ValidationIssue {
+	return r.filter(SeverityError)
+}
+
+// HasErrors returns true if any fatal issue was found.
+func (r *ValidationReport) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Err returns a single error combining all fatal issues, or nil if there are none.
+func (r *ValidationReport) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, issue := range errs {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Setting, issue.Message))
+	}
+	return fmt.Errorf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+func (r *ValidationReport) filter(severity Severity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Validate checks the configuration and returns a report of fatal and non-fatal issues.
+// fatal issues cover settings the services cannot run without; warnings cover likely-wrong
+// combinations such as a provider selected without credentials or insecure production settings.
+func (c *Config) Validate() *ValidationReport {
+	report := &ValidationReport{}
+
+	c.validateCore(report)
+	c.validateServiceURLs(report)
+	c.validateProviders(report)
+	if c.IsProduction() {
+		c.validateProduction(report)
+	}
+
+	return report
+}
+
+// validateCore checks settings every service depends on.
+func (c *Config) validateCore(report *ValidationReport) {
+	c.validatePorts(report)
+	if c.RabbitMQURL == "" {
+		if c.requires("RABBITMQ_URL") {
+			report.fail("RABBITMQ_URL", "is required")
+		}
+	} else if u, err := url.Parse(c.RabbitMQURL); err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") {
+		report.fail("RABBITMQ_URL", "must be an amqp:// or amqps:// URL")
+	}
+	switch c.MessagingOfflineMode {
+	case "queue", "drop":
+	default:
+		report.fail("MESSAGING_OFFLINE_MODE", "must be queue or drop")
+	}
+	if c.MessagingReconnectInterval <= 0 {
+		report.fail("MESSAGING_RECONNECT_INTERVAL", "must be positive")
+	}
+	if c.RedisURL != "" {
+		if u, err := url.Parse(c.RedisURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") {
+			report.fail("REDIS_URL", "must be a redis:// or rediss:// URL")
+		}
+	}
+	if c.JWTSecret == "" && !c.IsProduction() {
+		report.warn("JWT_SECRET", "is empty, tokens are signed with an empty key")
+	}
+	if c.JWTDuration <= 0 {
+		report.fail("JWT_DURATION", "must be positive")
+	}
+	if c.SessionTTL <= 0 {
+		report.fail("SESSION_TTL", "must be positive")
+	}
+	if c.MaxSessionsPerUser < 0 {
+		report.fail("MAX_SESSIONS_PER_USER", "must not be negative, use 0 for unlimited")
+	}
+	if c.RequestTimeout < 0 {
+		report.fail("REQUEST_TIMEOUT", "must not be negative, use 0 to disable")
+	}
+	for _, entry := range c.TrustedProxies {
+		if _, err := parseProxy(entry); err != nil {
+			report.fail("TRUSTED_PROXIES", "invalid address or CIDR %q", entry)
+		}
+	}
+	if c.PasswordHistoryDepth < 0 {
+		report.fail("PASSWORD_HISTORY_DEPTH", "must not be negative, use 0 to keep no history")
+	}
+	switch c.RiskFlagFormat {
+	case "type", "rule_id", "rule_name":
+	default:
+		report.fail("RISK_FLAG_FORMAT", "must be one of type, rule_id or rule_name, got %q", c.RiskFlagFormat)
+	}
+	if c.MXCheckEnabled {
+		if c.MXCheckTimeout <= 0 {
+			report.fail("MX_CHECK_TIMEOUT", "must be positive when MX_CHECK_ENABLED is set")
+		}
+		if c.MXCacheTTL <= 0 {
+			report.fail("MX_CACHE_TTL", "must be positive when MX_CHECK_ENABLED is set")
+		}
+		if c.NoMXScore <= 0 {
+			report.warn("NO_MX_SCORE", "is not positive, domains without MX records are flagged but not scored")
+		}
+	}
+	if c.RuleCacheHealthInterval <= 0 {
+		report.fail("RULE_CACHE_HEALTH_INTERVAL", "must be positive")
+	}
+	c.Settings().Current().validate(report)
+	if c.StartupWaitTimeout > 0 && c.StartupWaitInterval <= 0 {
+		report.fail("STARTUP_WAIT_INTERVAL", "must be positive when STARTUP_WAIT_TIMEOUT is set")
+	}
+	switch c.DefaultUserRole {
+	case "user", "moderator":
+	case "admin", "service":
+		report.fail("DEFAULT_USER_ROLE", "must not be %s, anyone registering would get it", c.DefaultUserRole)
+	default:
+		report.fail("DEFAULT_USER_ROLE", "must be user or moderator, got %q", c.DefaultUserRole)
+	}
+	if c.BroadcastRatePerSecond <= 0 {
+		report.warn("BROADCAST_RATE_PER_SECOND", "must be positive, broadcasts will be sent at 1 per second")
+	}
+
+	switch strings.ToLower(c.Environment) {
+	case "development", "staging", "production", "test":
+	default:
+		report.warn("ENVIRONMENT", "unknown environment %q, production checks are not applied", c.Environment)
+	}
+}
+
+// validatePorts checks that every service port is valid and that no two services share one.
+// collisions are fatal in development, where all services usually run on one host.
+func (c *Config) validatePorts(report *ValidationReport) {
+	ports := []struct {
+		setting string
+		value   string
+	}{
+		{"GATEWAY_PORT", c.Ports.Gateway},
+		{"USER_GRPC_PORT", c.Ports.UserGRPC},
+		{"RISK_GRPC_PORT", c.Ports.RiskGRPC},
+		{"NOTIFICATION_GRPC_PORT", c.Ports.NotificationGRPC},
+	}
+
+	seen := make(map[string]string, len(ports))
+	for _, p := range ports {
+		if port, err := strconv.Atoi(p.value); err != nil || port <= 0 || port > 65535 {
+			report.fail(p.setting, "must be a valid port number, got %q", p.value)
+			continue
+		}
+
+		if other, exists := seen[p.value]; exists {
+			if c.IsDevelopment() {
+				report.fail(p.setting, "port %s is already used by %s", p.value, other)
+			} else {
+				report.warn(p.setting, "port %s is also used by %s, services must run on separate hosts", p.value, other)
+			}
+			continue
+		}
+		seen[p.value] = p.setting
+	}
+}
+
+// validateServiceURLs checks that gRPC service endpoints are well-formed host:port pairs.
+func (c *Config) validateServiceURLs(report *ValidationReport) {
+	endpoints := map[string]string{
+		"USER_SERVICE_URL":         c.UserServiceURL,
+		"RISK_SERVICE_URL":         c.RiskServiceURL,
+		"NOTIFICATION_SERVICE_URL": c.NotificationServiceURL,
+	}
+
+	for setting, endpoint := range endpoints {
+		if endpoint == "" {
+			report.warn(setting, "is empty, calls to this service will fail")
+			continue
+		}
+		if _, port, err := net.SplitHostPort(endpoint); err != nil || port == "" {
+			report.warn(setting, "must be in host:port form, got %q", endpoint)
+		}
+	}
+}
+
+// validateProviders checks that selected notification providers have their credentials.
+func (c *Config) validateProviders(report *ValidationReport) {
+	switch c.EmailProvider {
+	case "SENDGRID":
+		if c.SendGridAPIKey == "" {
+			c.providerUnconfigured(report, "SENDGRID_API_KEY", "EMAIL_PROVIDER is SENDGRID but no API key is set", "emails")
+		}
+	case "SIMULATE":
+	default:
+		report.warn("EMAIL_PROVIDER", "unknown provider %q, emails will be simulated", c.EmailProvider)
+	}
+
+	switch c.SMSProvider {
+	case "TWILIO":
+		if c.TwilioAccountSID == "" || c.TwilioAuthToken == "" {
+			c.providerUnconfigured(report, "TWILIO_AUTH_TOKEN", "SMS_PROVIDER is TWILIO but credentials are missing", "SMS")
+		}
+		if c.TwilioFromNumber == "" {
+			report.warn("TWILIO_FROM_NUMBER", "SMS_PROVIDER is TWILIO but no sender number is set")
+		}
+	case "SIMULATE":
+	default:
+		report.warn("SMS_PROVIDER", "unknown provider %q, SMS will be simulated", c.SMSProvider)
+	}
+
+	validateEmailsByType(report, "EMAIL_CC", c.EmailCC)
+	validateEmailsByType(report, "EMAIL_BCC", c.EmailBCC)
+	for notificationType, address := range c.EmailReplyTo {
+		validateEmailsByType(report, "EMAIL_REPLY_TO", map[string][]string{notificationType: {address}})
+	}
+
+	for setting, link := range map[string]string{
+		"TEMPLATE_SUPPORT_URL":      c.TemplateSupportURL,
+		"TEMPLATE_LOGIN_URL":        c.TemplateLoginURL,
+		"TEMPLATE_VERIFY_EMAIL_URL": c.TemplateVerifyEmailURL,
+	} {
+		if u, err := url.Parse(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			report.fail(setting, "must be an absolute http:// or https:// URL")
+		}
+	}
+
+	hasSecret := c.WebhookSecret != "" || len(c.WebhookSecrets) > 0
+	if (c.WebhookURL == "") != !hasSecret {
+		report.warn("WEBHOOK_SECRET", "WEBHOOK_URL and WEBHOOK_SECRET or WEBHOOK_ORG_SECRETS must both be set, webhook channel disabled")
+	}
+	for orgID, secret := range c.WebhookSecrets {
+		if orgID == "" || secret == "" {
+			report.fail("WEBHOOK_ORG_SECRETS", "entries must be ORG_ID=secret")
+		}
+	}
+}
+
+// validateEmailsByType fails setting for entries without a notification type or with an invalid address.
+func validateEmailsByType(report *ValidationReport, setting string, byType map[string][]string) {
+	for notificationType, addresses := range byType {
+		if notificationType == "" {
+			report.fail(setting, "entries must be TYPE=address|address")
+			continue
+		}
+		for _, address := range addresses {
+			if _, err := mail.ParseAddress(address); err != nil {
+				report.fail(setting, "invalid address %q for %s", address, notificationType)
+			}
+		}
+	}
+}
+
+// providerUnconfigured reports a selected provider without credentials, which the notification
+// service replaces with simulation. In production that would drop every message of the channel
+// while looking healthy, so it is fatal there.
+func (c *Config) providerUnconfigured(report *ValidationReport, setting, problem, messages string) {
+	if c.IsProduction() {
+		report.fail(setting, "%s, %s can't be simulated in production", problem, messages)
+		return
+	}
+	report.warn(setting, "%s, %s will be simulated", problem, messages)
+}
+
+// validateProduction checks settings that are required or unsafe in production.
+func (c *Config) validateProduction(report *ValidationReport) {
+	if c.JWTSecret == "" {
+		report.fail("JWT_SECRET", "is required in production")
+	} else if len(c.JWTSecret) < 32 {
+		report.fail("JWT_SECRET", "must be at least 32 characters in production")
+	}
+	if c.DatabaseURL == "" && c.requires("DATABASE_URL") {
+		report.fail("DATABASE_URL", "is required in production")
+	}
+	if IsSQLiteDSN(c.DatabaseURL) {
+		report.warn("DATABASE_URL", "uses SQLite in production, it is meant for local development and tests")
+	}
+	if IsSQLiteDSN(c.RiskDatabaseURL) {
+		report.warn("RISK_DATABASE_URL", "uses SQLite in production, it is meant for local development and tests")
+	}
+	if IsSQLiteDSN(c.NotificationDBURL) {
+		report.warn("NOTIFICATION_DATABASE_URL", "uses SQLite in production, suppressions and notification timelines may not survive restarts")
+	}
+
+	if !c.RequireServiceJWTForwarding {
+		report.warn("REQUIRE_SERVICE_JWT_FORWARDING", "service-to-service JWT authentication is disabled in production")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			report.warn("ALLOWED_CORS", "allows any origin in production")
+			break
+		}
+	}
+	if c.EmailProvider == "SIMULATE" {
+		report.warn("EMAIL_PROVIDER", "emails are simulated in production")
+	}
+	if c.SMSProvider == "SIMULATE" {
+		report.warn("SMS_PROVIDER", "SMS are simulated in production")
+	}
+	for setting, dsn := range map[string]string{
+		"DATABASE_URL":              c.DatabaseURL,
+		"RISK_DATABASE_URL":         c.RiskDatabaseURL,
+		"RISK_DATABASE_REPLICA_URL": c.RiskReplicaURL,
+		"NOTIFICATION_DATABASE_URL": c.NotificationDBURL,
+	} {
+		if strings.Contains(dsn, "sslmode=disable") {
+			report.warn(setting, "database TLS is disabled (sslmode=disable) in production")
+		}
+	}
+	if !c.RateLimitEnabled {
+		report.warn("RATE_LIMIT_ENABLED", "gateway requests are not rate limited in production")
+	}
+	if c.DatabaseAutoMigrate {
+		report.warn("DB_AUTO_MIGRATE", "schemas are changed by gorm AutoMigrate in production, use versioned migrations")
+	}
+	for setting, endpoint := range map[string]string{"WEBHOOK_URL": c.WebhookURL, "SLACK_WEBHOOK_URL": c.SlackWebhookURL} {
+		if endpoint != "" && !strings.HasPrefix(endpoint, "https://") {
+			report.warn(setting, "should use https in production")
+		}
+	}
+}
+
+// LogStartupReport logs configuration warnings and the effective configuration with secrets masked.
+// should be called once the service logger is available.
+func (c *Config) LogStartupReport(l *logger.Logger) {
+	for _, issue := range c.Validate().Warnings() {
+		l.Warn("Configuration warning", "setting", issue.Setting, "issue", issue.Message)
+	}
+	l.Info("Effective configuration", "config", c.Masked())
+}
+
+// Masked returns the effective configuration as a map keyed by environment variable.
+// values come from Redact so secrets never appear in the output.
+func (c *Config) Masked() map[string]interface{} {
+	c = c.Redact()
+	return map[string]interface{}{
+		"SERVICE_NAME":                   c.ServiceName,
+		"GATEWAY_PORT":                   c.Ports.Gateway,
+		"USER_GRPC_PORT":                 c.Ports.UserGRPC,
+		"RISK_GRPC_PORT":                 c.Ports.RiskGRPC,
+		"NOTIFICATION_GRPC_PORT":         c.Ports.NotificationGRPC,
+		"ENVIRONMENT":                    c.Environment,
+		"LOG_LEVEL":                      c.LogLevel,
+		"LOG_MASK_PII":                   c.LogMaskPII,
+		"ALLOWED_CORS":                   c.AllowedOrigins,
+		"TRUSTED_PROXIES":                c.TrustedProxies,
+		"DATABASE_URL":                   c.DatabaseURL,
+		"RISK_DATABASE_URL":              c.RiskDatabaseURL,
+		"RISK_DATABASE_REPLICA_URL":      c.RiskReplicaURL,
+		"NOTIFICATION_DATABASE_URL":      c.NotificationDBURL,
+		"DATABASE_MAX_CONNS":             c.DatabaseMaxConns,
+		"DB_MAX_IDLE":                    c.DatabaseMaxIdleConn,
+		"DATABASE_CONN_LIFETIME":         c.DatabaseConnLiftime.String(),
+		"DB_AUTO_MIGRATE":                c.DatabaseAutoMigrate,
+		"JWT_SECRET":                     c.JWTSecret,
+		"JWT_DURATION":                   c.JWTDuration.String(),
+		"JWT_ISSUER":                     c.JWTIssuer,
+		"SESSION_TTL":                    c.SessionTTL.String(),
+		"MAX_SESSIONS_PER_USER":          c.MaxSessionsPerUser,
+		"PASSWORD_HISTORY_DEPTH":         c.PasswordHistoryDepth,
+		"RISK_FLAG_FORMAT":               c.RiskFlagFormat,
+		"MX_CHECK_ENABLED":               c.MXCheckEnabled,
+		"MX_CHECK_TIMEOUT":               c.MXCheckTimeout.String(),
+		"MX_CACHE_TTL":                   c.MXCacheTTL.String(),
+		"NO_MX_SCORE":                    c.NoMXScore,
+		"RULE_CACHE_HEALTH_INTERVAL":     c.RuleCacheHealthInterval.String(),
+		"USER_SERVICE_URL":               c.UserServiceURL,
+		"RISK_SERVICE_URL":               c.RiskServiceURL,
+		"NOTIFICATION_SERVICE_URL":       c.NotificationServiceURL,
+		"RABBITMQ_URL":                   c.RabbitMQURL,
+		"MESSAGING_REQUIRED":             c.MessagingRequired,
+		"MESSAGING_OFFLINE_MODE":         c.MessagingOfflineMode,
+		"MESSAGING_RECONNECT_INTERVAL":   c.MessagingReconnectInterval.String(),
+		"REDIS_URL":                      c.RedisURL,
+		"STARTUP_WAIT_TIMEOUT":           c.StartupWaitTimeout.String(),
+		"STARTUP_WAIT_INTERVAL":          c.StartupWaitInterval.String(),
+		"OUTBOX_POLL_INTERVAL":           c.OutboxPollInterval.String(),
+		"OUTBOX_RETENTION":               c.OutboxRetention.String(),
+		"EMAIL_PROVIDER":                 c.EmailProvider,
+		"SENDGRID_API_KEY":               c.SendGridAPIKey,
+		"SENDGRID_FROM_EMAIL":            c.SendGridFromEmail,
+		"SENDGRID_FROM_NAME":             c.SendGridFromName,
+		"SENDGRID_WEBHOOK_PUBLIC_KEY":    c.SendGridWebhookPublicKey,
+		"EMAIL_CC":                       c.EmailCC,
+		"EMAIL_BCC":                      c.EmailBCC,
+		"EMAIL_REPLY_TO":                 c.EmailReplyTo,
+		"NOTIFICATION_FALLBACK":          c.NotificationFallback,
+		"SMS_PROVIDER":                   c.SMSProvider,
+		"TWILIO_ACCOUNT_SID":             c.TwilioAccountSID,
+		"TWILIO_AUTH_TOKEN":              c.TwilioAuthToken,
+		"TWILIO_FROM_NUMBER":             c.TwilioFromNumber,
+		"PUSH_PROVIDER":                  c.PushProvider,
+		"WEBHOOK_URL":                    c.WebhookURL,
+		"WEBHOOK_SECRET":                 c.WebhookSecret,
+		"WEBHOOK_ORG_SECRETS":            c.WebhookSecrets,
+		"WEBHOOK_TIMEOUT":                c.WebhookTimeout.String(),
+		"SLACK_WEBHOOK_URL":              c.SlackWebhookURL,
+		"BROADCAST_RATE_PER_SECOND":      c.BroadcastRatePerSecond,
+		"RECHECK_RATE_PER_SECOND":        c.Settings().Current().RecheckRatePerSecond,
+		"RATE_LIMIT_ENABLED":             c.RateLimitEnabled,
+		"RATE_LIMIT_REQUESTS":            c.RateLimitRequests,
+		"RATE_LIMIT_WINDOW":              c.RateLimitWindow.String(),
+		"REQUEST_TIMEOUT":                c.RequestTimeout.String(),
+		"RULE_CACHE_TTL":                 c.Settings().Current().RuleCacheTTL.String(),
+		"RULE_MAX_EXPIRES_IN_DAYS":       c.Settings().Current().RuleMaxExpiresInDays,
+		"RISK_THRESHOLDS":                c.Settings().Current().RiskThresholds,
+		"RISK_STOP_ON_CRITICAL_MATCH":    c.Settings().Current().StopOnCriticalMatch,
+		"RISK_NORMALIZE_NAMES":           c.Settings().Current().NormalizeNames,
+		"RISK_CATEGORY_SCORE_CAP":        c.Settings().Current().CategoryScoreCap,
+		"RISK_DEDUP_FLAG_SCORES":         c.Settings().Current().DedupFlagScores,
+		"ANALYTICS_SAMPLE_RATE":          c.Settings().Current().AnalyticsSampleRate,
+		"RISK_LEVEL_EVENTS":              c.Settings().Current().RiskLevelEvents,
+		"FEATURE_FLAGS":                  c.Settings().Current().FeatureFlags.List(),
+		"METRICS_ENABLED":                c.MetricsEnabled,
+		"TRACING_ENABLED":                c.TracingEnabled,
+		"REQUIRE_SERVICE_JWT_FORWARDING": c.RequireServiceJWTForwarding,
+		"TEMPLATES_PATH":                 c.TemplatesDirectoryPath,
+		"TEMPLATE_COMPANY_NAME":          c.TemplateCompanyName,
+		"TEMPLATE_SUPPORT_URL":           c.TemplateSupportURL,
+		"TEMPLATE_LOGIN_URL":             c.TemplateLoginURL,
+		"TEMPLATE_VERIFY_EMAIL_URL":      c.TemplateVerifyEmailURL,
+		"DEFAULT_USER_ROLE":              c.DefaultUserRole,
+
+		"NOTIFICATION_THROTTLE_LIMIT":          c.Settings().Current().NotificationThrottleLimit,
+		"NOTIFICATION_THROTTLE_CRITICAL_LIMIT": c.Settings().Current().NotificationThrottleCriticalLimit,
+		"NOTIFICATION_THROTTLE_WINDOW":         c.Settings().Current().NotificationThrottleWindow.String(),
+	}
+}
@@ -47,16 +47,27 @@ var (
 	ErrAuthenticationFailed       = &AppError{Code: "AUTHENTICATION_FAILED", Message: "Authentication failed"}
 	ErrMissingRequiredFileds      = &AppError{Code: "MISSING_REQUIRED_FILEDS", Message: "Missing required fileds"}
 	ErrInternalServerError        = &AppError{Code: "INTERNAL_SERVER_ERROR", Message: "Something went wrong"}
+	ErrValidationFailed           = &AppError{Code: "VALIDATION_FAILED", Message: "Validation failed"}
+	ErrUnsupportedMediaType       = &AppError{Code: "UNSUPPORTED_MEDIA_TYPE", Message: "Content-Type must be application/json"}
+	ErrConcurrentUpdate           = &AppError{Code: "CONCURRENT_UPDATE", Message: "Resource was modified concurrently, reload and retry"}
+	ErrEmailChangeExpired         = &AppError{Code: "EMAIL_CHANGE_EXPIRED", Message: "Email change request has expired"}
+	ErrSessionNotFound            = &AppError{Code: "SESSION_NOT_FOUND", Message: "Session not found"}
+	ErrTemplateNotFound           = &AppError{Code: "TEMPLATE_NOT_FOUND", Message: "Template not found"}
+	ErrSuppressionNotFound        = &AppError{Code: "SUPPRESSION_NOT_FOUND", Message: "Recipient is not suppressed"}
+	ErrNotificationNotFound       = &AppError{Code: "NOTIFICATION_NOT_FOUND", Message: "Notification not found"}
+	ErrInvalidStatusTransition    = &AppError{Code: "INVALID_STATUS_TRANSITION", Message: "Account status can't be changed"}
+	ErrPasswordReused             = &AppError{Code: "PASSWORD_REUSED", Message: "Password was used recently, choose a different one"}
+	ErrRequestTimeout             = &AppError{Code: "REQUEST_TIMEOUT", Message: "Request took too long, try again later"}
 )
 
 // HTTPStatus returns the appropriate HTTP status code for the error.
 func (e *AppError) HTTPStatus() int {
 	switch e.Code {
-	case "USER_NOT_FOUND":
+	case "USER_NOT_FOUND", "SESSION_NOT_FOUND", "TEMPLATE_NOT_FOUND", "SUPPRESSION_NOT_FOUND", "NOTIFICATION_NOT_FOUND":
 		return http.StatusNotFound
 	case "INVALID_PASSWORD", "INVALID_TOKEN", "AUTHENTICATION_FAILED":
 		return http.StatusUnauthorized
-	case "EMAIL_EXISTS":
+	case "EMAIL_EXISTS", "CONCURRENT_UPDATE", "INVALID_STATUS_TRANSITION":
 		return http.StatusConflict
 	case "INSUFFICIENT_ROLE":
 		return http.StatusForbidden
@@ -64,12 +75,20 @@ func (e *AppError) HTTPStatus() int {
 		return http.StatusTooManyRequests
 	case "USER_INACTIVE":
 		return http.StatusForbidden
-	case "PASSWORD_HASH_FAILED", "INVALID_JSON", "UNAME_OR_PASS_REQUIRED", "MISSING_REQUIRED_FILEDS":
+	case "PASSWORD_HASH_FAILED", "INVALID_JSON", "UNAME_OR_PASS_REQUIRED", "MISSING_REQUIRED_FILEDS", "VALIDATION_FAILED", "PASSWORD_REUSED":
 		return http.StatusBadRequest
 	case "USER_CREATE_FAILED":
 		return http.StatusInternalServerError
 	case "USER_UPDATE_FAILED":
 		return http.StatusInternalServerError
+	case "UNSUPPORTED_MEDIA_TYPE":
+		return http.StatusUnsupportedMediaType
+	case "EMAIL_CHANGE_EXPIRED":
+		return http.StatusGone
+	case "WEBHOOK_DISABLED", "SUPPRESSIONS_DISABLED":
+		return http.StatusServiceUnavailable
+	case "REQUEST_TIMEOUT":
+		return http.StatusGatewayTimeout
 	default:
 		return http.StatusInternalServerError
 	}
@@ -97,12 +116,24 @@ func (e *AppError) WithDetails(details string) *AppError {
 // maps application error codes to standard gRPC status codes.
 func (e *AppError) GRPCStatus() *status.Status {
 	switch e.Code {
-	case "USER_NOT_FOUND":
+	case "USER_NOT_FOUND", "SESSION_NOT_FOUND", "TEMPLATE_NOT_FOUND", "SUPPRESSION_NOT_FOUND", "NOTIFICATION_NOT_FOUND":
 		return status.New(codes.NotFound, e.Message)
 	case "INVALID_PASSWORD", "INVALID_TOKEN":
 		return status.New(codes.Unauthenticated, e.Message)
-	case "INSUFFICIENT_ROLE":
+	case "INSUFFICIENT_ROLE", "USER_INACTIVE":
 		return status.New(codes.PermissionDenied, e.Message)
+	case "VALIDATION_FAILED", "PASSWORD_REUSED":
+		return status.New(codes.InvalidArgument, e.Message)
+	case "CONCURRENT_UPDATE":
+		return status.New(codes.Aborted, e.Message)
+	case "EMAIL_EXISTS":
+		return status.New(codes.AlreadyExists, e.Message)
+	case "EMAIL_CHANGE_EXPIRED", "INVALID_STATUS_TRANSITION":
+		return status.New(codes.FailedPrecondition, e.Message)
+	case "RATE_LIMIT_EXCEEDED":
+		return status.New(codes.ResourceExhausted, e.Message)
+	case "REQUEST_TIMEOUT":
+		return status.New(codes.DeadlineExceeded, e.Message)
 	default:
 		return status.New(codes.Internal, e.Message)
 	}
@@ -0,0 +1,93 @@
+// Package features evaluates feature flags for gradual rollout of new behavior.
+// a flag is on for a key (usually a user ID) when the key is allowlisted or hashes into the
+// flag's rollout percentage, so the same user lands in the same bucket on every request.
+package features
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flag is the rollout configuration of a single feature.
+type Flag struct {
+	Name       string   // Feature name, as referenced in code
+	Percentage int      // Share of keys the flag is on for, 0-100
+	Allowlist  []string // Keys the flag is always on for, regardless of Percentage
+}
+
+// Enabled returns true if the flag is on for key.
+func (f Flag) Enabled(key string) bool {
+	for _, allowed := range f.Allowlist {
+		if allowed == key {
+			return true
+		}
+	}
+	return Bucket(f.Name, key) < f.Percentage
+}
+
+// Set holds the configured flags keyed by name.
+// a nil Set is valid and has every flag off.
+type Set map[string]Flag
+
+// Enabled returns true if the named flag is configured and on for key.
+func (s Set) Enabled(name, key string) bool {
+	flag, ok := s[name]
+	return ok && flag.Enabled(key)
+}
+
+// List returns the configured flags sorted by name.
+func (s Set) List() []Flag {
+	flags := make([]Flag, 0, len(s))
+	for _, flag := range s {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// Bucket maps key to a stable bucket in [0, 100) for the named flag.
+// the flag name is part of the hash so different flags roll out to different users.
+func Bucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + key))
+	return int(h.Sum32() % 100)
+}
+
+// Parse reads flags from a spec like "name=10,other=100:user-1|user-2".
+// each comma separated entry is a name, a rollout percentage and an optional allowlist after a colon.
+func Parse(spec string) (Set, error) {
+	set := Set{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, rest, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("flag %q must be written as name=percentage", entry)
+		}
+		if _, dup := set[name]; dup {
+			return nil, fmt.Errorf("flag %q is configured more than once", name)
+		}
+
+		percentage, allowlist, _ := strings.Cut(rest, ":")
+		pct, err := strconv.Atoi(strings.TrimSpace(percentage))
+		if err != nil || pct < 0 || pct > 100 {
+			return nil, fmt.Errorf("flag %q percentage must be a number between 0 and 100", name)
+		}
+
+		flag := Flag{Name: name, Percentage: pct}
+		for _, key := range strings.Split(allowlist, "|") {
+			if key = strings.TrimSpace(key); key != "" {
+				flag.Allowlist = append(flag.Allowlist, key)
+			}
+		}
+		set[name] = flag
+	}
+	return set, nil
+}
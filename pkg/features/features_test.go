@@ -0,0 +1,99 @@
+package features_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"user-risk-system/pkg/features"
+)
+
+func TestBucketIsStable(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		bucket := features.Bucket("diminishing_scoring", key)
+		if bucket < 0 || bucket >= 100 {
+			t.Fatalf("Bucket(%q) = %d, want [0, 100)", key, bucket)
+		}
+		for j := 0; j < 3; j++ {
+			if got := features.Bucket("diminishing_scoring", key); got != bucket {
+				t.Fatalf("Bucket(%q) = %d then %d, want the same bucket on every call", key, bucket, got)
+			}
+		}
+	}
+	// Pinned so a change of hash, which would reshuffle every rollout, shows up here
+	if got := features.Bucket("diminishing_scoring", "user-1"); got != 39 {
+		t.Errorf("Bucket(user-1) = %d, want 39", got)
+	}
+	if features.Bucket("diminishing_scoring", "user-1") == features.Bucket("dedup_flag_scores", "user-1") &&
+		features.Bucket("diminishing_scoring", "user-2") == features.Bucket("dedup_flag_scores", "user-2") {
+		t.Error("flags bucket users identically, want the flag name in the hash")
+	}
+}
+
+func TestPercentageRollout(t *testing.T) {
+	const keys = 10000
+	for _, pct := range []int{0, 10, 50, 100} {
+		t.Run(fmt.Sprintf("%d%%", pct), func(t *testing.T) {
+			flag := features.Flag{Name: "diminishing_scoring", Percentage: pct}
+			wider := features.Flag{Name: flag.Name, Percentage: min(pct+10, 100)}
+
+			on := 0
+			for i := 0; i < keys; i++ {
+				key := fmt.Sprintf("user-%d", i)
+				if !flag.Enabled(key) {
+					continue
+				}
+				on++
+				if !wider.Enabled(key) {
+					t.Fatalf("%s is on at %d%% but off at %d%%, want raising the percentage to only add users", key, pct, wider.Percentage)
+				}
+			}
+			if share := on * 100 / keys; share < pct-2 || share > pct+2 {
+				t.Errorf("flag on for %d%% of users, want about %d%%", share, pct)
+			}
+		})
+	}
+}
+
+func TestAllowlistOverridesPercentage(t *testing.T) {
+	flag := features.Flag{Name: "diminishing_scoring", Percentage: 0, Allowlist: []string{"user-1", "user-2"}}
+
+	for _, key := range flag.Allowlist {
+		if !flag.Enabled(key) {
+			t.Errorf("Enabled(%q) = false for an allowlisted user at 0%%", key)
+		}
+	}
+	if flag.Enabled("user-3") {
+		t.Error("Enabled(user-3) = true at 0% for a user not on the allowlist")
+	}
+
+	set := features.Set{flag.Name: flag}
+	if !set.Enabled(flag.Name, "user-1") || set.Enabled("unknown", "user-1") {
+		t.Error("Set.Enabled() should follow the flag and keep unconfigured flags off")
+	}
+	var empty features.Set
+	if empty.Enabled(flag.Name, "user-1") {
+		t.Error("nil Set has a flag on")
+	}
+}
+
+func TestParse(t *testing.T) {
+	set, err := features.Parse(" diminishing_scoring=10 , dedup_flag_scores=0:user-1| user-2 ,")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []features.Flag{
+		{Name: "dedup_flag_scores", Percentage: 0, Allowlist: []string{"user-1", "user-2"}},
+		{Name: "diminishing_scoring", Percentage: 10},
+	}
+	if got := set.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse().List() = %+v, want %+v", got, want)
+	}
+
+	for _, spec := range []string{"diminishing_scoring", "=10", "a=ten", "a=-1", "a=101", "a=10,a=20"} {
+		if _, err := features.Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
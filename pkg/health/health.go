@@ -1,9 +1,14 @@
 package health
 
 import (
+	"context"
+	"time"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"user-risk-system/pkg/logger"
 )
 
 type ServiceHealth struct {
@@ -48,6 +53,42 @@ func (hs *HealthServer) SetOverallStatus(status grpc_health_v1.HealthCheckRespon
 	hs.server.SetServingStatus("", status)
 }
 
+// Monitor runs check every interval until ctx is cancelled, marking services NOT_SERVING while it fails.
+// they return to SERVING once check passes again. Only status changes are logged.
+func (hs *HealthServer) Monitor(ctx context.Context, name string, interval time.Duration, l *logger.Logger, check func(ctx context.Context) error, services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := check(checkCtx)
+		cancel()
+
+		status := grpc_health_v1.HealthCheckResponse_SERVING
+		if err != nil {
+			status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range services {
+			hs.server.SetServingStatus(service, status)
+		}
+
+		switch {
+		case err != nil && healthy:
+			l.Error("Health check failed, marking services not serving", err, "check", name, "services", services)
+		case err == nil && !healthy:
+			l.Info("Health check recovered, marking services serving", "check", name, "services", services)
+		}
+		healthy = err == nil
+	}
+}
+
 // RegisterHealthServiceWithDefaults registers health service with default SERVING status
 func RegisterHealthServiceWithDefaults(server *grpc.Server, serviceName string) *HealthServer {
 	config := Config{
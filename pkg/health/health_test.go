@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"user-risk-system/pkg/logger"
+)
+
+func TestMonitorFollowsCheck(t *testing.T) {
+	hs := RegisterHealthService(grpc.NewServer(), Config{
+		OverallStatus: grpc_health_v1.HealthCheckResponse_SERVING,
+		Services:      []ServiceHealth{{Name: "risk.RiskService", Status: grpc_health_v1.HealthCheckResponse_SERVING}},
+	})
+	var failing atomic.Bool
+	failing.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hs.Monitor(ctx, "rule_cache", 5*time.Millisecond, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}),
+		func(context.Context) error {
+			if failing.Load() {
+				return errors.New("rule cache is stale")
+			}
+			return nil
+		},
+		"risk.RiskService",
+	)
+
+	waitForStatus := func(want grpc_health_v1.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			resp, err := hs.server.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "risk.RiskService"})
+			if err == nil && resp.Status == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("status = %v (%v), want %v", resp.GetStatus(), err, want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitForStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	if resp, _ := hs.server.Check(ctx, &grpc_health_v1.HealthCheckRequest{}); resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("overall status = %v, want unmonitored services left serving", resp.GetStatus())
+	}
+
+	failing.Store(false)
+	waitForStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+)
+
+// Dependency is a named readiness check polled before a service starts serving.
+type Dependency struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// GRPCDependency checks the overall status reported by a gRPC server's health service.
+func GRPCDependency(name string, conn *grpc.ClientConn) Dependency {
+	client := grpc_health_v1.NewHealthClient(conn)
+	return Dependency{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				return err
+			}
+			if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+				return fmt.Errorf("status %s", resp.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// RabbitMQDependency checks that the RabbitMQ broker accepts connections.
+func RabbitMQDependency(url string) Dependency {
+	return Dependency{
+		Name: "rabbitmq",
+		Check: func(ctx context.Context) error {
+			return messaging.Ping(ctx, url)
+		},
+	}
+}
+
+// WaitForDependencies polls every dependency until all are ready or timeout expires.
+// a zero timeout disables the wait. Dependencies that become ready are not checked again.
+func WaitForDependencies(ctx context.Context, timeout, interval time.Duration, l *logger.Logger, deps ...Dependency) error {
+	if timeout <= 0 || len(deps) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	pending := make(map[string]Dependency, len(deps))
+	for _, dep := range deps {
+		pending[dep.Name] = dep
+	}
+	lastErrors := make(map[string]error, len(deps))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name, dep := range pending {
+			checkCtx, cancelCheck := context.WithTimeout(ctx, interval)
+			err := dep.Check(checkCtx)
+			cancelCheck()
+
+			if err != nil {
+				lastErrors[name] = err
+				continue
+			}
+			delete(pending, name)
+			delete(lastErrors, name)
+			l.Info("Dependency ready", "dependency", name, "waited", time.Since(start))
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			failures := make([]string, 0, len(lastErrors))
+			for name, err := range lastErrors {
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			}
+			return fmt.Errorf("dependencies not ready after %s: %s", timeout, strings.Join(failures, "; "))
+		case <-ticker.C:
+			l.Warn("Waiting for dependencies", "pending", len(pending))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package lifecycle logs how long a service takes to start and to shut down.
+// Every dependency connected and every resource closed is logged with its duration,
+// so a slow start or a stuck shutdown shows which step it is waiting on.
+package lifecycle
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"user-risk-system/pkg/logger"
+)
+
+// closer is a resource released on shutdown.
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Lifecycle records the startup steps and shutdown resources of one service.
+type Lifecycle struct {
+	logger  *logger.Logger
+	started time.Time
+	mu      sync.Mutex
+	closers []closer
+}
+
+// New starts timing the startup of a service, typically first thing in main.
+func New(logger *logger.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger, started: time.Now()}
+}
+
+// Connect runs fn to connect the named dependency, logging how long it took and whether it failed.
+// the error of fn is returned as is, the caller decides whether it is fatal.
+func (l *Lifecycle) Connect(name string, fn func() error) error {
+	start := time.Now()
+	if err := fn(); err != nil {
+		l.logger.Error("Failed to connect dependency", err, "dependency", name, "duration", time.Since(start))
+		return err
+	}
+	l.logger.Info("Connected dependency", "dependency", name, "duration", time.Since(start))
+	return nil
+}
+
+// OnShutdown registers fn to release the named resource, resources are closed in reverse order.
+func (l *Lifecycle) OnShutdown(name string, fn func() error) {
+	l.mu.Lock()
+	l.closers = append(l.closers, closer{name: name, close: fn})
+	l.mu.Unlock()
+}
+
+// Started logs the total startup time once the service is ready to serve, with args as extra fields.
+func (l *Lifecycle) Started(args ...any) {
+	l.logger.Info("Service started", append([]any{"startup_duration", time.Since(l.started)}, args...)...)
+}
+
+// Shutdown closes every registered resource, newest first, logging each with its duration.
+// a failing resource doesn't stop the others from closing, all errors are returned joined.
+// resources are released once, later calls do nothing.
+func (l *Lifecycle) Shutdown() error {
+	l.mu.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mu.Unlock()
+
+	if len(closers) == 0 {
+		return nil
+	}
+
+	l.logger.Info("Shutting down", "resources", len(closers))
+	start := time.Now()
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		c := closers[i]
+		closeStart := time.Now()
+		if err := c.close(); err != nil {
+			l.logger.Error("Failed to close resource", err, "resource", c.name, "duration", time.Since(closeStart))
+			errs = append(errs, err)
+			continue
+		}
+		l.logger.Info("Closed resource", "resource", c.name, "duration", time.Since(closeStart))
+	}
+	l.logger.Info("Shutdown complete", "shutdown_duration", time.Since(start), "errors", len(errs))
+	return errors.Join(errs...)
+}
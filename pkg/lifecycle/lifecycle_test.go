@@ -0,0 +1,92 @@
+package lifecycle_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"user-risk-system/pkg/lifecycle"
+	"user-risk-system/pkg/logger"
+)
+
+// events decodes the JSON log entries written to buf into their message and the dependency or resource they name.
+// every entry but the shutdown announcement must carry a duration.
+func events(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var out []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		_, step := entry["duration"]
+		_, startup := entry["startup_duration"]
+		_, shutdown := entry["shutdown_duration"]
+		if !step && !startup && !shutdown && entry["msg"] != "Shutting down" {
+			t.Errorf("log entry %v has no duration", entry)
+		}
+
+		event, _ := entry["msg"].(string)
+		for _, key := range []string{"dependency", "resource"} {
+			if name, ok := entry[key].(string); ok {
+				event += " " + name
+			}
+		}
+		out = append(out, event)
+	}
+	buf.Reset()
+	return out
+}
+
+func TestLifecycleLogsEventsInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	l := lifecycle.New(logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &buf}))
+	errBroker := errors.New("connection refused")
+	errCache := errors.New("flush failed")
+
+	if err := l.Connect("database", func() error { return nil }); err != nil {
+		t.Fatalf("Connect(database) error = %v", err)
+	}
+	if err := l.Connect("broker", func() error { return errBroker }); !errors.Is(err, errBroker) {
+		t.Fatalf("Connect(broker) error = %v, want the connect error", err)
+	}
+	var closed []string
+	for _, name := range []string{"database", "cache", "grpc server"} {
+		l.OnShutdown(name, func() error {
+			closed = append(closed, name)
+			if name == "cache" {
+				return errCache
+			}
+			return nil
+		})
+	}
+	l.Started("port", "50051")
+
+	if err := l.Shutdown(); !errors.Is(err, errCache) {
+		t.Fatalf("Shutdown() error = %v, want the close error", err)
+	}
+	if want := []string{"grpc server", "cache", "database"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("closed %v, want newest first %v", closed, want)
+	}
+
+	want := []string{
+		"Connected dependency database",
+		"Failed to connect dependency broker",
+		"Service started",
+		"Shutting down",
+		"Closed resource grpc server",
+		"Failed to close resource cache",
+		"Closed resource database",
+		"Shutdown complete",
+	}
+	if got := events(t, &buf); !reflect.DeepEqual(got, want) {
+		t.Errorf("events =\n%q\nwant\n%q", got, want)
+	}
+
+	if err := l.Shutdown(); err != nil || buf.Len() != 0 || len(closed) != 3 {
+		t.Errorf("second Shutdown() = %v, logged %q, closed %v, want nothing done", err, buf.String(), closed)
+	}
+}
@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs every unary call with its method, status code and duration.
+// chain it after scontext.UnaryServerInterceptor so the entry carries the request's user and request ID.
+func UnaryServerInterceptor(l *Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.InfoCtx(ctx, "gRPC request",
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"duration", time.Since(start),
+		)
+		return resp, err
+	}
+}
@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/scontext"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+func TestUnaryServerInterceptorLogsCalls(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{"successful call", nil, "OK"},
+		{"failed call", status.Error(codes.NotFound, "user not found"), "NotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			log := logger.New(logger.LogConfig{Level: "info", Format: "json", Output: &out})
+
+			// Chained like the services do, the handler adds no fields of its own
+			info := &grpc.UnaryServerInfo{FullMethod: "/risk.RiskService/CheckRisk"}
+			handler := func(context.Context, interface{}) (interface{}, error) { return nil, tt.err }
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(scontext.RequestIDMetadataKey, "req-1"))
+			_, err := scontext.UnaryServerInterceptor()(ctx, &pb_risk.RiskCheckRequest{UserId: "user-1", Email: "user@example.com"}, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return logger.UnaryServerInterceptor(log)(ctx, req, info, handler)
+				})
+			if err != tt.err {
+				t.Fatalf("interceptor error = %v, want the handler's %v", err, tt.err)
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("log entry is not JSON: %v\n%s", err, out.String())
+			}
+			for key, want := range map[string]any{
+				"msg":        "gRPC request",
+				"method":     info.FullMethod,
+				"code":       tt.wantCode,
+				"user_id":    "user-1",
+				"user_email": "user@example.com",
+				"request_id": "req-1",
+			} {
+				if entry[key] != want {
+					t.Errorf("%s = %v, want %v", key, entry[key], want)
+				}
+			}
+			if duration, ok := entry["duration"].(float64); !ok || duration <= 0 {
+				t.Errorf("duration = %v, want the call's duration", entry["duration"])
+			}
+		})
+	}
+}
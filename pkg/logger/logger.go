@@ -4,57 +4,60 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
+	"user-risk-system/pkg/pii"
 	"user-risk-system/pkg/scontext"
 )
 
 // Logger wraps the standard slog.Logger with additional context-aware logging methods.
 type Logger struct {
 	*slog.Logger
+	level *slog.LevelVar // Shared with the handler so the level can change at runtime
 }
 
 // LogConfig defines the configuration options for creating a new logger instance.
 type LogConfig struct {
-	Level       string // Logging level (debug, info, warn, error)
-	Format      string // Output format (json, text)
-	ServiceName string // Service name to include in log entries
-	Environment string // Environment name to include in log entries
+	Level       string    // Logging level (debug, info, warn, error)
+	Format      string    // Output format (json, text)
+	ServiceName string    // Service name to include in log entries
+	Environment string    // Environment name to include in log entries
+	Output      io.Writer // Destination of log entries, stdout when nil
+	MaskPII     bool      // Mask emails and phone numbers in every entry, see maskPII
 }
 
 // New creates a new Logger instance with the specified configuration.
 func New(config LogConfig) *Logger {
-	var level slog.Level
-	switch config.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
+	level := &slog.LevelVar{}
+	level.Set(parseLevel(config.Level))
 
 	opts := &slog.HandlerOptions{
 		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-			// Add custom formatting here
-			if a.Key == slog.TimeKey {
-				a.Value = slog.StringValue(time.Now().Format(time.RFC3339))
+			// The record time is the only time attribute outside of a group
+			if a.Key == slog.TimeKey && len(groups) == 0 && a.Value.Kind() == slog.KindTime {
+				a.Value = slog.StringValue(a.Value.Time().Format(time.RFC3339))
+			}
+			if config.MaskPII {
+				return maskPII(a)
 			}
 			return a
 		},
 	}
 
+	output := config.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
 	var handler slog.Handler
 	if config.Format == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(output, opts)
 	} else {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(output, opts)
 	}
 
 	logger := slog.New(handler).With(
@@ -62,7 +65,65 @@ func New(config LogConfig) *Logger {
 		"environment", config.Environment,
 	)
 
-	return &Logger{Logger: logger}
+	return &Logger{Logger: logger, level: level}
+}
+
+// maskPII obscures attributes whose key names an email address or phone number,
+// e.g. email, user_email, new_email, phone. Non-string values under such keys are fully redacted.
+func maskPII(a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	switch {
+	case strings.HasSuffix(key, "email"):
+		return slog.String(a.Key, maskValue(a.Value, pii.MaskEmail))
+	case strings.HasSuffix(key, "phone"), strings.HasSuffix(key, "phone_number"):
+		return slog.String(a.Key, maskValue(a.Value, pii.MaskPhone))
+	}
+	return a
+}
+
+// maskValue applies mask to a string value and redacts any other kind.
+func maskValue(v slog.Value, mask func(string) string) string {
+	if v.Kind() == slog.KindString {
+		return mask(v.String())
+	}
+	return pii.Redacted
+}
+
+// parseLevel converts a level name to a slog.Level, defaulting to info.
+func parseLevel(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// SetLevel changes the minimum level of the logger at runtime.
+func (l *Logger) SetLevel(name string) {
+	if l.level != nil {
+		l.level.Set(parseLevel(name))
+	}
+}
+
+// Debug logs a diagnostic message with optional key-value pairs, dropped unless the level is debug.
+func (l *Logger) Debug(msg string, args ...any) {
+	l.Logger.Debug(msg, args...)
+}
+
+// DebugCtx logs a diagnostic message with context-extracted fields and optional key-value pairs.
+func (l *Logger) DebugCtx(ctx context.Context, msg string, args ...any) {
+	if !l.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	allArgs := append(l.extractContextFields(ctx), args...)
+	l.Logger.DebugContext(ctx, msg, allArgs...)
 }
 
 // Info logs an informational message with optional key-value pairs.
@@ -116,28 +177,24 @@ func (l *Logger) FatalfCtx(ctx context.Context, format string, args ...any) {
 func (l *Logger) extractContextFields(ctx context.Context) []any {
 	var fields []any
 
-	if userID := ctx.Value(scontext.UserIDKey); userID != nil {
+	if userID, ok := scontext.UserID(ctx); ok {
 		fields = append(fields, "user_id", userID)
 	}
-	if userEmail := ctx.Value(scontext.UserEmailKey); userEmail != nil {
+	if userEmail, ok := scontext.Email(ctx); ok {
 		fields = append(fields, "user_email", userEmail)
 	}
-
-	if userRole := ctx.Value(scontext.UserRoleKey); userRole != nil {
+	if userRole, ok := scontext.Role(ctx); ok {
 		fields = append(fields, "user_role", userRole)
 	}
-
-	if userRoles := ctx.Value(scontext.UserRolesKey); userRoles != nil {
-		if roles, ok := userRoles.([]string); ok {
-			fields = append(fields, "user_roles", roles)
-		}
+	if userRoles, ok := scontext.Roles(ctx); ok {
+		fields = append(fields, "user_roles", userRoles)
 	}
 
 	// Request fields
-	if requestID := ctx.Value(scontext.RequestIDKey); requestID != nil {
+	if requestID, ok := scontext.RequestID(ctx); ok {
 		fields = append(fields, "request_id", requestID)
 	}
-	if sessionID := ctx.Value(scontext.SessionIDKey); sessionID != nil {
+	if sessionID, ok := scontext.SessionID(ctx); ok {
 		fields = append(fields, "session_id", sessionID)
 	}
 
@@ -0,0 +1,39 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/logger"
+)
+
+func TestJSONTimestampIsRFC3339(t *testing.T) {
+	var out bytes.Buffer
+	log := logger.New(logger.LogConfig{Level: "info", Format: "json", ServiceName: "test", Output: &out})
+
+	before := time.Now().Truncate(time.Second)
+	log.Info("Risk check completed", slog.Group("request", "time", "caller value"))
+	after := time.Now()
+
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not JSON: %v\n%s", err, out.String())
+	}
+	raw, ok := entry["time"].(string)
+	if !ok {
+		t.Fatalf("time = %#v, want a string", entry["time"])
+	}
+	logged, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		t.Fatalf("time %q is not RFC3339: %v", raw, err)
+	}
+	if logged.Before(before) || logged.After(after) {
+		t.Errorf("time = %v, want between %v and %v", logged, before, after)
+	}
+	if request, _ := entry["request"].(map[string]any); request["time"] != "caller value" {
+		t.Errorf("request = %v, want the caller's time field untouched", entry["request"])
+	}
+}
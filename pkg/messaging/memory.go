@@ -0,0 +1,142 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// memoryQueueSize is the number of undelivered messages an in-memory queue holds.
+const memoryQueueSize = 1024
+
+// ErrClosed is returned by InMemory operations after Close.
+var ErrClosed = errors.New("messaging: broker closed")
+
+// InMemory is a process-local broker for tests.
+// every published message is recorded and delivered to the queue's consumers, queues are
+// created on first use so publishing never depends on declaration order.
+type InMemory struct {
+	mu        sync.Mutex
+	queues    map[string]chan []byte
+	options   map[string]QueueOptions      // queue -> options it was first declared with
+	published map[string][]json.RawMessage // queue -> every payload published, in order
+	closed    bool
+}
+
+// NewInMemory creates an empty in-memory broker.
+func NewInMemory() *InMemory {
+	return &InMemory{
+		queues:    make(map[string]chan []byte),
+		options:   make(map[string]QueueOptions),
+		published: make(map[string][]json.RawMessage),
+	}
+}
+
+// DeclareQueue creates the queue if it doesn't exist.
+func (m *InMemory) DeclareQueue(name string) error {
+	return m.DeclareQueueWithOptions(name, QueueOptions{})
+}
+
+// DeclareQueueWithOptions creates the queue if it doesn't exist and checks opts like RabbitMQ does.
+// options are only recorded, messages don't expire or get dead-lettered by them.
+func (m *InMemory) DeclareQueueWithOptions(name string, opts QueueOptions) error {
+	m.mu.Lock()
+	existing, declared := m.options[name]
+	if declared && !opts.compatibleWith(existing) {
+		m.mu.Unlock()
+		return incompatibleQueue(name, opts, nil)
+	}
+	if !declared {
+		m.options[name] = opts
+	}
+	m.mu.Unlock()
+
+	_, err := m.queue(name)
+	return err
+}
+
+// Publish records message and queues it for delivery.
+func (m *InMemory) Publish(queueName string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return m.publish(queueName, body)
+}
+
+// Consume delivers the messages of the queue to handler until ctx is cancelled.
+// rejected messages are published to the dead-letter queue, other handler errors drop the message.
+func (m *InMemory) Consume(ctx context.Context, queueName string, handler func([]byte) error) error {
+	msgs, err := m.queue(queueName)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case body := <-msgs:
+			err := handler(body)
+			var rejectErr *RejectError
+			if errors.As(err, &rejectErr) {
+				if err := m.publish(DeadLetterQueue(queueName), body); err != nil {
+					log.Printf("Failed to dead-letter message: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Close makes further operations fail with ErrClosed.
+func (m *InMemory) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	return nil
+}
+
+// Published returns every payload published to the queue so far, including delivered ones.
+func (m *InMemory) Published(queueName string) []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]json.RawMessage(nil), m.published[queueName]...)
+}
+
+// publish records body and queues it, failing when the queue is full.
+func (m *InMemory) publish(queueName string, body []byte) error {
+	msgs, err := m.queue(queueName)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.published[queueName] = append(m.published[queueName], body)
+	m.mu.Unlock()
+
+	select {
+	case msgs <- body:
+		return nil
+	default:
+		return fmt.Errorf("failed to publish message: queue %s is full", queueName)
+	}
+}
+
+// queue returns the delivery channel of name, creating it on first use.
+func (m *InMemory) queue(name string) (chan []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+	msgs, ok := m.queues[name]
+	if !ok {
+		msgs = make(chan []byte, memoryQueueSize)
+		m.queues[name] = msgs
+	}
+	return msgs, nil
+}
@@ -0,0 +1,111 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	events "user-risk-system/pkg/models"
+)
+
+// consumeN runs a consumer of queue until it has handled n messages, returning their bodies.
+func consumeN(t *testing.T, broker *InMemory, queue string, n int, handler func([]byte) error) [][]byte {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan []byte, n)
+	go broker.Consume(ctx, queue, func(body []byte) error {
+		received <- body
+		return handler(body)
+	})
+
+	var bodies [][]byte
+	for len(bodies) < n {
+		select {
+		case body := <-received:
+			bodies = append(bodies, body)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d messages on %s", len(bodies), n, queue)
+		}
+	}
+	return bodies
+}
+
+func TestInMemoryRecordsAndDeliversUserCreated(t *testing.T) {
+	broker := NewInMemory()
+	var client Messaging = broker
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		event := events.UserCreatedEvent{EventMeta: events.NewEventMeta(), UserID: email, Email: email}
+		if err := client.Publish(events.EventUserCreated, event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	published := broker.Published(events.EventUserCreated)
+	if len(published) != 2 {
+		t.Fatalf("Published() has %d messages, want 2", len(published))
+	}
+
+	bodies := consumeN(t, broker, events.EventUserCreated, 2, func([]byte) error { return nil })
+	for i, want := range []string{"first@example.com", "second@example.com"} {
+		var event events.UserCreatedEvent
+		if err := json.Unmarshal(bodies[i], &event); err != nil {
+			t.Fatalf("malformed user.created payload: %v", err)
+		}
+		if event.Email != want || event.EventID == "" {
+			t.Errorf("message %d = %+v, want the user.created event of %s in publish order", i, event, want)
+		}
+	}
+	if got := len(broker.Published(events.EventUserCreated)); got != 2 {
+		t.Errorf("Published() has %d messages after delivery, want delivered messages kept", got)
+	}
+}
+
+func TestInMemoryDeadLettersRejectedMessages(t *testing.T) {
+	broker := NewInMemory()
+	broker.Publish("work", map[string]string{"id": "bad"})
+	broker.Publish("work", map[string]string{"id": "failed"})
+
+	consumeN(t, broker, "work", 2, func(body []byte) error {
+		if string(body) == `{"id":"bad"}` {
+			return Reject("malformed")
+		}
+		return errors.New("transient")
+	})
+
+	// Consume dead-letters after the handler returns
+	deadline := time.Now().Add(time.Second)
+	for len(broker.Published(DeadLetterQueue("work"))) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	dead := broker.Published(DeadLetterQueue("work"))
+	if len(dead) != 1 || string(dead[0]) != `{"id":"bad"}` {
+		t.Errorf("dead-letter queue = %s, want only the rejected message", dead)
+	}
+}
+
+func TestInMemoryDeclareAndClose(t *testing.T) {
+	broker := NewInMemory()
+	opts := QueueOptions{MessageTTL: time.Minute}
+	if err := broker.DeclareQueueWithOptions("work", opts); err != nil {
+		t.Fatalf("DeclareQueueWithOptions() error = %v", err)
+	}
+	if err := broker.DeclareQueueWithOptions("work", opts); err != nil {
+		t.Errorf("redeclaring with the same options: %v", err)
+	}
+	if err := broker.DeclareQueueWithOptions("work", QueueOptions{MessageTTL: time.Hour}); !errors.Is(err, ErrIncompatibleQueue) {
+		t.Errorf("redeclaring with other options error = %v, want ErrIncompatibleQueue", err)
+	}
+
+	broker.Close()
+	if err := broker.Publish("work", "message"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrClosed", err)
+	}
+	if err := broker.Consume(context.Background(), "work", func([]byte) error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Errorf("Consume() after Close error = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,26 @@
+package messaging
+
+import "context"
+
+// Messaging is the message broker used by the services.
+// RabbitMQ is the production implementation, InMemory runs flows in process for tests.
+type Messaging interface {
+	// DeclareQueue creates the queue if it doesn't exist, accepting it as it is when it does.
+	DeclareQueue(name string) error
+	// DeclareQueueWithOptions creates the queue with opts if it doesn't exist.
+	// fails with ErrIncompatibleQueue when it exists with other arguments.
+	DeclareQueueWithOptions(name string, opts QueueOptions) error
+	// Publish sends message to the queue after JSON marshaling.
+	Publish(queueName string, message interface{}) error
+	// Consume runs handler for every message of the queue until ctx is cancelled,
+	// messages rejected with Reject are moved to the queue's dead-letter queue.
+	Consume(ctx context.Context, queueName string, handler func([]byte) error) error
+	// Close releases the broker connection.
+	Close() error
+}
+
+var (
+	_ Messaging = (*RabbitMQ)(nil)
+	_ Messaging = (*InMemory)(nil)
+	_ Messaging = (*Resilient)(nil)
+)
@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// ErrIncompatibleQueue is returned when a queue already exists with arguments other than the ones declared.
+var ErrIncompatibleQueue = errors.New("messaging: queue exists with different arguments")
+
+// QueueOptions are the arguments a queue is declared with, zero values leave the broker default.
+type QueueOptions struct {
+	MessageTTL           time.Duration // x-message-ttl, messages older than it expire or are dead-lettered
+	DeadLetterExchange   string        // x-dead-letter-exchange, "" with a routing key is the default exchange
+	DeadLetterRoutingKey string        // x-dead-letter-routing-key, e.g. DeadLetterQueue(name)
+	MaxLength            int           // x-max-length, the oldest messages are dropped or dead-lettered beyond it
+}
+
+// IsZero returns true if no argument is set.
+func (o QueueOptions) IsZero() bool {
+	return o == QueueOptions{}
+}
+
+// compatibleWith returns true if declaring a queue with o succeeds while it exists with existing.
+// a declaration without arguments accepts any existing queue.
+func (o QueueOptions) compatibleWith(existing QueueOptions) bool {
+	return o.IsZero() || o == existing
+}
+
+// table returns the options as AMQP queue arguments, nil when none is set.
+func (o QueueOptions) table() amqp.Table {
+	if o.IsZero() {
+		return nil
+	}
+	args := amqp.Table{}
+	if o.MessageTTL > 0 {
+		args["x-message-ttl"] = o.MessageTTL.Milliseconds()
+	}
+	if o.DeadLetterExchange != "" || o.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-exchange"] = o.DeadLetterExchange
+	}
+	if o.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = o.DeadLetterRoutingKey
+	}
+	if o.MaxLength > 0 {
+		args["x-max-length"] = int64(o.MaxLength)
+	}
+	return args
+}
+
+// incompatibleQueue builds the error of a queue that exists with arguments other than opts.
+func incompatibleQueue(name string, opts QueueOptions, cause error) error {
+	if cause != nil {
+		return fmt.Errorf("%w: queue %s can't be declared with %v, delete it or declare it with its current arguments (%v)",
+			ErrIncompatibleQueue, name, opts.table(), cause)
+	}
+	return fmt.Errorf("%w: queue %s can't be declared with %v, delete it or declare it with its current arguments",
+		ErrIncompatibleQueue, name, opts.table())
+}
@@ -0,0 +1,60 @@
+package messaging
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+func TestQueueOptionsTable(t *testing.T) {
+	tests := []struct {
+		name string
+		opts QueueOptions
+		want amqp.Table
+	}{
+		{"no arguments", QueueOptions{}, nil},
+		{"dead letters to the default exchange", QueueOptions{DeadLetterRoutingKey: DeadLetterQueue("work")},
+			amqp.Table{"x-dead-letter-exchange": "", "x-dead-letter-routing-key": "work.dlq"}},
+		{"every argument", QueueOptions{MessageTTL: 30 * time.Second, DeadLetterExchange: "dlx", DeadLetterRoutingKey: "work.dlq", MaxLength: 1000},
+			amqp.Table{"x-message-ttl": int64(30000), "x-dead-letter-exchange": "dlx", "x-dead-letter-routing-key": "work.dlq", "x-max-length": int64(1000)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.table(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("table() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeclareQueueWithDeadLetterArguments(t *testing.T) {
+	broker := NewInMemory()
+	dlx := QueueOptions{MessageTTL: time.Minute, DeadLetterRoutingKey: DeadLetterQueue("user.created")}
+
+	if err := broker.DeclareQueueWithOptions("user.created", dlx); err != nil {
+		t.Fatalf("DeclareQueueWithOptions() error = %v", err)
+	}
+	if err := broker.DeclareQueueWithOptions("user.created", dlx); err != nil {
+		t.Errorf("redeclaring with the same arguments error = %v, want it idempotent", err)
+	}
+	if err := broker.DeclareQueue("user.created"); err != nil {
+		t.Errorf("DeclareQueue() of the existing queue error = %v, want it accepted", err)
+	}
+
+	err := broker.DeclareQueueWithOptions("user.created", QueueOptions{DeadLetterRoutingKey: "other.dlq"})
+	if !errors.Is(err, ErrIncompatibleQueue) {
+		t.Fatalf("redeclaring with another dead-letter queue error = %v, want ErrIncompatibleQueue", err)
+	}
+	if !strings.Contains(err.Error(), "user.created") || !strings.Contains(err.Error(), "other.dlq") {
+		t.Errorf("error = %q, want it to name the queue and the rejected arguments", err)
+	}
+
+	cause := &amqp.Error{Code: amqp.PreconditionFailed, Reason: "PRECONDITION_FAILED - inequivalent arg 'x-dead-letter-routing-key'"}
+	if err := incompatibleQueue("user.created", dlx, cause); !errors.Is(err, ErrIncompatibleQueue) || !strings.Contains(err.Error(), cause.Reason) {
+		t.Errorf("incompatibleQueue() = %v, want ErrIncompatibleQueue with the broker's reason", err)
+	}
+}
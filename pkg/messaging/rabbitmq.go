@@ -1,11 +1,16 @@
-// Package messaging provides RabbitMQ-based message publishing and consumption capabilities.
+// Package messaging provides message publishing and consumption over RabbitMQ, with an in-memory broker for tests.
 package messaging
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"net"
+	"time"
+	"user-risk-system/pkg/logger"
 
+	"github.com/google/uuid"
 	"github.com/streadway/amqp"
 )
 
@@ -14,10 +19,33 @@ import (
 type RabbitMQ struct {
 	conn    *amqp.Connection // RabbitMQ connection
 	channel *amqp.Channel    // RabbitMQ channel for operations
+	logger  *logger.Logger
+}
+
+// RejectError marks a message as permanently unprocessable, e.g. malformed or failing validation.
+// handlers return it via Reject so the consumer routes the message to the dead-letter queue.
+type RejectError struct {
+	Reason string // Why the message was rejected
+}
+
+// Error implements the error interface.
+func (e *RejectError) Error() string {
+	return "message rejected: " + e.Reason
+}
+
+// Reject creates a RejectError with a formatted reason.
+func Reject(format string, args ...interface{}) error {
+	return &RejectError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// DeadLetterQueue returns the name of the dead-letter queue paired with the given queue.
+func DeadLetterQueue(queueName string) string {
+	return queueName + ".dlq"
 }
 
 // NewRabbitMQ creates a new RabbitMQ client instance and establishes connection.
-func NewRabbitMQ(url string) (*RabbitMQ, error) {
+// message bodies are never logged, they carry the users' personal data.
+func NewRabbitMQ(url string, logger *logger.Logger) (*RabbitMQ, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
@@ -31,21 +59,83 @@ func NewRabbitMQ(url string) (*RabbitMQ, error) {
 	return &RabbitMQ{
 		conn:    conn,
 		channel: ch,
+		logger:  logger,
 	}, nil
 }
 
+// Ping checks that the broker at url accepts connections, giving up once ctx is done.
+// the AMQP handshake takes no context, so the socket is closed to abort it.
+func Ping(ctx context.Context, url string) error {
+	var dialer net.Dialer
+	stop := func() bool { return false }
+	conn, err := amqp.DialConfig(url, amqp.Config{
+		Heartbeat: 10 * time.Second,
+		Locale:    "en_US",
+		Dial: func(network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			stop = context.AfterFunc(ctx, func() { conn.Close() })
+			return conn, nil
+		},
+	})
+	stop()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	return conn.Close()
+}
+
 // DeclareQueue creates a durable queue with the specified name if it doesn't exist.
 // The queue is configured to survive broker restarts but not exclusive to this connection.
+// an existing queue is accepted whatever arguments it was declared with.
 func (r *RabbitMQ) DeclareQueue(name string) error {
-	_, err := r.channel.QueueDeclare(
-		name,  // name
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	return err
+	return r.DeclareQueueWithOptions(name, QueueOptions{})
+}
+
+// DeclareQueueWithOptions creates a durable queue with the arguments of opts if it doesn't exist.
+// The broker refuses to redeclare a queue with other arguments and closes the channel doing it,
+// so the declaration runs on a channel of its own and the client stays usable. A refused declaration
+// without options falls back to a passive one accepting the existing queue, one with options
+// fails with ErrIncompatibleQueue.
+func (r *RabbitMQ) DeclareQueueWithOptions(name string, opts QueueOptions) error {
+	err := r.onChannel(func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclare(
+			name,         // name
+			true,         // durable
+			false,        // delete when unused
+			false,        // exclusive
+			false,        // no-wait
+			opts.table(), // arguments
+		)
+		return err
+	})
+
+	var amqpErr *amqp.Error
+	if err == nil || !errors.As(err, &amqpErr) || amqpErr.Code != amqp.PreconditionFailed {
+		return err
+	}
+	if !opts.IsZero() {
+		return incompatibleQueue(name, opts, err)
+	}
+	return r.onChannel(func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclarePassive(name, true, false, false, false, nil)
+		return err
+	})
+}
+
+// onChannel runs fn on a new channel closed afterwards, keeping channel errors away from r.channel.
+func (r *RabbitMQ) onChannel(fn func(ch *amqp.Channel) error) error {
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+	return fn(ch)
 }
 
 // Publish sends a message to the specified queue after JSON marshaling.
@@ -68,40 +158,105 @@ func (r *RabbitMQ) Publish(queueName string, message interface{}) error {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
-	log.Printf("Published message to queue %s: %s", queueName, string(body))
+	r.logger.Debug("Published message", "queue", queueName, "bytes", len(body))
 	return nil
 }
 
-// Consume starts consuming messages from the specified queue with auto-acknowledgment.
-func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
+// Consume starts consuming messages from the specified queue with manual acknowledgment.
+// blocks until ctx is cancelled, then cancels the consumer and drains deliveries already
+// handed to this client so no message is dropped mid-flight.
+// Messages rejected by the handler are moved to the queue's dead-letter queue.
+func (r *RabbitMQ) Consume(ctx context.Context, queueName string, handler func([]byte) error) error {
+	consumerTag := fmt.Sprintf("%s-%s", queueName, uuid.New().String())
+
+	if err := r.DeclareQueue(DeadLetterQueue(queueName)); err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+
 	msgs, err := r.channel.Consume(
-		queueName, // queue
-		"",        // consumer
-		true,      // auto-ack
-		false,     // exclusive
-		false,     // no-local
-		false,     // no-wait
-		nil,       // args
+		queueName,   // queue
+		consumerTag, // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register consumer: %w", err)
 	}
 
-	forever := make(chan bool)
+	r.logger.Info("Waiting for messages", "queue", queueName)
 
-	go func() {
-		for d := range msgs {
-			log.Printf("Received message from queue %s: %s", queueName, string(d.Body))
-			if err := handler(d.Body); err != nil {
-				log.Printf("Error handling message: %v", err)
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping consumer", "queue", queueName)
+			if err := r.channel.Cancel(consumerTag, false); err != nil {
+				return fmt.Errorf("failed to cancel consumer: %w", err)
+			}
+			// The deliveries channel is closed once the broker confirms the cancel
+			for d := range msgs {
+				r.handleDelivery(queueName, d, handler)
 			}
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			r.handleDelivery(queueName, d, handler)
 		}
-	}()
+	}
+}
 
-	log.Printf("Waiting for messages from queue %s. To exit press CTRL+C", queueName)
-	<-forever
+// handleDelivery runs the handler for a single delivery and acknowledges it.
+// rejected messages are republished to the dead-letter queue with the reason in the
+// x-reject-reason header; other handler errors are logged and the message is dropped.
+func (r *RabbitMQ) handleDelivery(queueName string, d amqp.Delivery, handler func([]byte) error) {
+	r.logger.Debug("Received message", "queue", queueName, "bytes", len(d.Body))
 
-	return nil
+	err := handler(d.Body)
+	if err == nil {
+		d.Ack(false)
+		return
+	}
+
+	var rejectErr *RejectError
+	if !errors.As(err, &rejectErr) {
+		r.logger.Error("Error handling message", err, "queue", queueName)
+		d.Ack(false)
+		return
+	}
+
+	r.logger.Warn("Rejecting message", "queue", queueName, "reason", rejectErr.Reason)
+	if err := r.deadLetter(queueName, d, rejectErr.Reason); err != nil {
+		r.logger.Error("Failed to dead-letter message", err, "queue", queueName)
+		d.Nack(false, false)
+		return
+	}
+	d.Ack(false)
+}
+
+// deadLetter republishes a delivery unchanged to the dead-letter queue of queueName.
+func (r *RabbitMQ) deadLetter(queueName string, d amqp.Delivery, reason string) error {
+	return r.channel.Publish(
+		"",                         // exchange
+		DeadLetterQueue(queueName), // routing key
+		false,                      // mandatory
+		false,                      // immediate
+		amqp.Publishing{
+			ContentType: d.ContentType,
+			Body:        d.Body,
+			Headers: amqp.Table{
+				"x-original-queue": queueName,
+				"x-reject-reason":  reason,
+			},
+		})
+}
+
+// NotifyClose registers receiver to be notified once when the connection closes.
+func (r *RabbitMQ) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	return r.conn.NotifyClose(receiver)
 }
 
 // Close properly closes the RabbitMQ channel and connection.
@@ -0,0 +1,54 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// silentBroker accepts TCP connections but never answers the AMQP handshake.
+func silentBroker(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return "amqp://guest:guest@" + lis.Addr().String() + "/"
+}
+
+func TestPingGivesUpAtContextDeadline(t *testing.T) {
+	url := silentBroker(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := Ping(ctx, url)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Ping() error = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Ping() returned after %v, want shortly after the 100ms deadline", elapsed)
+	}
+}
+
+func TestPingReturnsWhenContextCancelled(t *testing.T) {
+	url := silentBroker(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if err := Ping(ctx, url); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Ping() error = %v, want context.Canceled", err)
+	}
+}
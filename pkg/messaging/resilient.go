@@ -0,0 +1,360 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"user-risk-system/pkg/logger"
+)
+
+// OfflineMode selects what Resilient does with messages published while RabbitMQ is unreachable.
+type OfflineMode string
+
+const (
+	OfflineQueue OfflineMode = "queue" // Hold them in memory and publish them once connected
+	OfflineDrop  OfflineMode = "drop"  // Log and drop them
+)
+
+// maxOfflineMessages bounds the messages held in OfflineQueue mode, newer ones are dropped beyond it.
+const maxOfflineMessages = 1000
+
+// ErrDisconnected is returned by the Strict publisher of a Resilient client while RabbitMQ is unreachable.
+var ErrDisconnected = errors.New("messaging: broker disconnected")
+
+// Conn is a broker connection of a Resilient client, *RabbitMQ unless replaced with SetDialer.
+type Conn interface {
+	Messaging
+	// NotifyClose registers receiver to be notified once when the connection closes.
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+}
+
+// Resilient is a RabbitMQ client that keeps the service running while the broker is unreachable.
+// it connects and reconnects in the background, declared queues are declared again on every
+// connection and consumers resume once connected. Messages published meanwhile are handled by
+// the offline mode, held messages are lost if the process stops before the broker returns.
+type Resilient struct {
+	url      string
+	mode     OfflineMode
+	interval time.Duration
+	logger   *logger.Logger
+	dial     func(url string) (Conn, error)
+
+	mu        sync.Mutex
+	broker    Conn             // Current connection, nil while disconnected
+	lost      chan *amqp.Error // Notified when the current connection closes
+	connected chan struct{}    // Closed once connected, replaced on disconnect
+	queues    []declaredQueue  // Declared queues, in declaration order
+	held      []heldMessage    // Messages published while disconnected in OfflineQueue mode
+	dropped   uint64           // Messages dropped while disconnected since startup
+	closed    bool
+}
+
+// declaredQueue is a queue declared again on every connection.
+type declaredQueue struct {
+	name string
+	opts QueueOptions
+}
+
+// heldMessage is a message waiting for the broker to return.
+type heldMessage struct {
+	queue string
+	body  json.RawMessage
+}
+
+// NewResilient creates a client of the broker at url, retrying every interval while it is unreachable.
+// it doesn't connect yet, see Connect and Run.
+func NewResilient(url string, mode OfflineMode, interval time.Duration, logger *logger.Logger) *Resilient {
+	return &Resilient{
+		url:       url,
+		mode:      mode,
+		interval:  interval,
+		logger:    logger,
+		dial:      func(url string) (Conn, error) { return dialRabbitMQ(url, logger) },
+		connected: make(chan struct{}),
+	}
+}
+
+// SetDialer replaces how connections are opened, e.g. with an in-memory broker in tests.
+// must be called before Connect.
+func (r *Resilient) SetDialer(dial func(url string) (Conn, error)) {
+	r.mu.Lock()
+	r.dial = dial
+	r.mu.Unlock()
+}
+
+// dialRabbitMQ opens a RabbitMQ connection, the default dialer.
+func dialRabbitMQ(url string, logger *logger.Logger) (Conn, error) {
+	broker, err := NewRabbitMQ(url, logger)
+	if err != nil {
+		return nil, err
+	}
+	return broker, nil
+}
+
+// Connect makes one attempt to connect if not connected yet, typically at startup.
+// the broker is dialled without holding the lock, so publishers aren't stalled by a slow dial.
+func (r *Resilient) Connect() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	if r.broker != nil {
+		r.mu.Unlock()
+		return nil
+	}
+	queues := append([]declaredQueue(nil), r.queues...)
+	dial := r.dial
+	r.mu.Unlock()
+
+	broker, err := dial(r.url)
+	if err != nil {
+		return err
+	}
+	for _, queue := range queues {
+		if err := broker.DeclareQueueWithOptions(queue.name, queue.opts); err != nil {
+			broker.Close()
+			return fmt.Errorf("failed to declare queue %s: %w", queue.name, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.broker != nil {
+		broker.Close()
+		if r.closed {
+			return ErrClosed
+		}
+		return nil
+	}
+
+	held := r.held
+	r.held = nil
+	for i, msg := range held {
+		if err := broker.Publish(msg.queue, msg.body); err != nil {
+			// Keep what couldn't be published for the next connection
+			r.held = held[i:]
+			broker.Close()
+			return err
+		}
+	}
+	if len(held) > 0 {
+		r.logger.Info("Published messages held while RabbitMQ was unreachable", "messages", len(held))
+	}
+
+	r.broker = broker
+	r.lost = broker.NotifyClose(make(chan *amqp.Error, 1))
+	close(r.connected)
+	return nil
+}
+
+// Run keeps the client connected until ctx is cancelled, reconnecting every interval while the
+// broker is unreachable or after the connection is lost.
+func (r *Resilient) Run(ctx context.Context) {
+	for {
+		if err := r.Connect(); err != nil {
+			if err == ErrClosed {
+				return
+			}
+			r.logger.Warn("RabbitMQ unreachable, retrying", "error", err.Error(), "retry_in", r.interval.String())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.interval):
+			}
+			continue
+		}
+
+		r.mu.Lock()
+		broker, lost := r.broker, r.lost
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-lost:
+			r.disconnect(broker)
+			if err != nil {
+				r.logger.Warn("Lost RabbitMQ connection, reconnecting", "error", err.Error())
+			}
+		}
+	}
+}
+
+// disconnect forgets broker if it is still the current connection.
+func (r *Resilient) disconnect(broker Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.broker != broker || r.broker == nil {
+		return
+	}
+	r.broker.Close()
+	r.broker = nil
+	r.connected = make(chan struct{})
+}
+
+// DeclareQueue declares the queue now when connected and again on every later connection.
+func (r *Resilient) DeclareQueue(name string) error {
+	return r.DeclareQueueWithOptions(name, QueueOptions{})
+}
+
+// DeclareQueueWithOptions declares the queue with opts now when connected and again on every later connection.
+// declaring a known queue with other options fails with ErrIncompatibleQueue, as the broker would refuse it.
+func (r *Resilient) DeclareQueueWithOptions(name string, opts QueueOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+
+	known := false
+	for _, queue := range r.queues {
+		if queue.name == name {
+			if !opts.compatibleWith(queue.opts) {
+				return incompatibleQueue(name, opts, nil)
+			}
+			known = true
+			break
+		}
+	}
+	if !known {
+		r.queues = append(r.queues, declaredQueue{name: name, opts: opts})
+	}
+
+	if r.broker == nil {
+		return nil
+	}
+	return r.broker.DeclareQueueWithOptions(name, opts)
+}
+
+// Publish sends message to the queue, or hands it to the offline mode while disconnected.
+// a publish failing on a live connection returns the error like RabbitMQ does.
+// callers that must know whether the message reached the broker publish through Strict instead.
+func (r *Resilient) Publish(queueName string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	broker := r.broker
+	if broker == nil {
+		r.holdLocked(queueName, body)
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+
+	return broker.Publish(queueName, json.RawMessage(body))
+}
+
+// Strict returns a publisher failing with ErrDisconnected while disconnected instead of applying the offline mode.
+// the outbox relay publishes through it, so events stay unsent in the outbox and are retried once connected.
+func (r *Resilient) Strict() *StrictPublisher {
+	return &StrictPublisher{client: r}
+}
+
+// StrictPublisher publishes through a Resilient client only while it is connected.
+type StrictPublisher struct {
+	client *Resilient
+}
+
+// Publish sends message to the queue, failing with ErrDisconnected while the client is disconnected.
+func (p *StrictPublisher) Publish(queueName string, message interface{}) error {
+	r := p.client
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	broker := r.broker
+	r.mu.Unlock()
+	if broker == nil {
+		return ErrDisconnected
+	}
+	return broker.Publish(queueName, message)
+}
+
+// holdLocked applies the offline mode to a message published while disconnected, r.mu must be held.
+func (r *Resilient) holdLocked(queueName string, body json.RawMessage) {
+	if r.mode == OfflineQueue && len(r.held) < maxOfflineMessages {
+		r.held = append(r.held, heldMessage{queue: queueName, body: body})
+		r.logger.Warn("RabbitMQ unreachable, holding message", "queue", queueName, "held", len(r.held))
+		return
+	}
+	r.dropped++
+	r.logger.Warn("RabbitMQ unreachable, dropped message", "queue", queueName, "messages_dropped", r.dropped)
+}
+
+// Consume runs handler for every message of the queue until ctx is cancelled.
+// it waits while disconnected and resumes on the next connection after the current one is lost.
+func (r *Resilient) Consume(ctx context.Context, queueName string, handler func([]byte) error) error {
+	for {
+		r.mu.Lock()
+		broker, connected, closed := r.broker, r.connected, r.closed
+		r.mu.Unlock()
+		if closed {
+			return ErrClosed
+		}
+
+		if broker == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-connected:
+			}
+			continue
+		}
+
+		if err := broker.Consume(ctx, queueName, handler); err != nil && ctx.Err() == nil {
+			r.logger.Warn("RabbitMQ consumer stopped, resuming after reconnect", "queue", queueName, "error", err.Error())
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		// The connection is usually gone by now, give Run time to notice before trying again
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// Connected returns true if the client currently holds a broker connection.
+func (r *Resilient) Connected() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.broker != nil
+}
+
+// Close closes the current connection, further operations fail with ErrClosed.
+// messages still held are dropped and logged.
+func (r *Resilient) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if len(r.held) > 0 {
+		r.logger.Warn("Dropped messages held for RabbitMQ on close", "messages", len(r.held))
+		r.held = nil
+	}
+	if r.broker == nil {
+		return nil
+	}
+	err := r.broker.Close()
+	r.broker = nil
+	return err
+}
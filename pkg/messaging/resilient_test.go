@@ -0,0 +1,90 @@
+package messaging
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"user-risk-system/pkg/logger"
+)
+
+// memoryConn is a Conn to an InMemory broker.
+type memoryConn struct {
+	*InMemory
+}
+
+func (c memoryConn) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error { return receiver }
+func (c memoryConn) Close() error                                           { return nil }
+
+func newTestResilient(mode OfflineMode) *Resilient {
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	return NewResilient("amqp://unreachable", mode, time.Second, log)
+}
+
+func TestResilientStrictFailsWhileDisconnected(t *testing.T) {
+	r := newTestResilient(OfflineQueue)
+	broker := NewInMemory()
+	down := true
+	r.SetDialer(func(string) (Conn, error) {
+		if down {
+			return nil, errors.New("connection refused")
+		}
+		return memoryConn{broker}, nil
+	})
+
+	if err := r.Connect(); err == nil {
+		t.Fatal("Connect() succeeded against an unreachable broker")
+	}
+	if err := r.Strict().Publish("user.created", map[string]string{"user_id": "1"}); !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Strict().Publish() error = %v, want ErrDisconnected", err)
+	}
+	if err := r.Publish("notifications", map[string]string{"user_id": "2"}); err != nil {
+		t.Fatalf("Publish() error = %v, want the message held", err)
+	}
+
+	down = false
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if err := r.Strict().Publish("user.created", map[string]string{"user_id": "1"}); err != nil {
+		t.Fatalf("Strict().Publish() error = %v", err)
+	}
+	if got := len(broker.Published("user.created")); got != 1 {
+		t.Errorf("user.created published %d times, want 1", got)
+	}
+	if got := len(broker.Published("notifications")); got != 1 {
+		t.Errorf("held notifications published %d times, want 1", got)
+	}
+
+	r.Close()
+	if err := r.Strict().Publish("user.created", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Strict().Publish() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestResilientDropMode(t *testing.T) {
+	r := newTestResilient(OfflineDrop)
+	broker := NewInMemory()
+	down := true
+	r.SetDialer(func(string) (Conn, error) {
+		if down {
+			return nil, errors.New("connection refused")
+		}
+		return memoryConn{broker}, nil
+	})
+
+	r.Connect()
+	if err := r.Publish("notifications", "dropped"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	down = false
+	if err := r.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if got := len(broker.Published("notifications")); got != 0 {
+		t.Errorf("dropped message published %d times", got)
+	}
+}
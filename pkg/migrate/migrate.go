@@ -0,0 +1,242 @@
+// Package migrate applies versioned schema migrations recorded in a schema_migrations table.
+// On PostgreSQL the run holds an advisory lock, so when several replicas start at once only
+// one migrates and the others wait for it and find nothing left to apply.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/pkg/logger"
+)
+
+// Migration is one versioned schema change.
+// Up and Down run in a transaction, Down may be nil for migrations that can't be reverted.
+type Migration struct {
+	ID   string // Unique, ordered identifier, e.g. 0002_add_index
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// schemaMigration records an applied migration.
+type schemaMigration struct {
+	ID        string    `gorm:"primaryKey;type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status reports whether a migration is applied.
+type Status struct {
+	ID        string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the migrations of one service database.
+type Migrator struct {
+	db         *gorm.DB
+	lockKey    int64
+	migrations []Migration
+	logger     *logger.Logger
+}
+
+// New creates a migrator of migrations, in the order they are applied.
+// name identifies the service and derives the advisory lock key.
+func New(db *gorm.DB, name string, migrations []Migration, logger *logger.Logger) *Migrator {
+	h := fnv.New64a()
+	h.Write([]byte("migrate:" + name))
+	return &Migrator{
+		db:         db,
+		lockKey:    int64(h.Sum64()),
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies every pending migration in order, returning the number applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		done, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.ID]; ok {
+				continue
+			}
+			m.logger.Info("Applying migration", "migration", migration.ID)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{ID: migration.ID, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s failed: %w", migration.ID, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, returning the number reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		done, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.ID]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("migration %s can't be reverted", migration.ID)
+			}
+			m.logger.Info("Reverting migration", "migration", migration.ID)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{ID: migration.ID}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("reverting migration %s failed: %w", migration.ID, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns every known migration in order with whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn := m.db.WithContext(ctx)
+	if err := ensureTable(conn); err != nil {
+		return nil, err
+	}
+	done, err := m.applied(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		appliedAt, ok := done[migration.ID]
+		statuses[i] = Status{ID: migration.ID, Applied: ok, AppliedAt: appliedAt}
+	}
+	return statuses, nil
+}
+
+// RunCommand runs the migrate subcommand given its arguments: up, down [steps] or status.
+// up is the default, down reverts one migration unless steps says otherwise.
+func (m *Migrator) RunCommand(ctx context.Context, args []string, out io.Writer) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "applied %d migrations\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "reverted %d migrations\n", reverted)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Fprintf(out, "applied  %s  %s\n", s.ID, s.AppliedAt.Format(time.RFC3339))
+			} else {
+				fmt.Fprintf(out, "pending  %s\n", s.ID)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down [steps] or status", command)
+	}
+	return nil
+}
+
+// locked runs fn on a single connection holding the migration lock.
+// PostgreSQL advisory locks are per session, so the lock and the migrations share the connection.
+// SQLite databases have a single connection already and aren't locked.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(pinned *gorm.DB) error {
+		// A new session per call on the pinned connection, so statements don't leak into each other
+		conn := pinned.Session(&gorm.Session{NewDB: true})
+		if conn.Dialector.Name() == "postgres" {
+			m.logger.Info("Waiting for migration lock")
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", m.lockKey).Error; err != nil {
+				return fmt.Errorf("failed to acquire migration lock: %w", err)
+			}
+			defer func() {
+				// The request context may be done already, the lock must be released regardless
+				if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", m.lockKey).Error; err != nil {
+					m.logger.Error("Failed to release migration lock", err)
+				}
+			}()
+		}
+
+		if err := ensureTable(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// ensureTable creates the schema_migrations table if it doesn't exist yet.
+func ensureTable(conn *gorm.DB) error {
+	if conn.Migrator().HasTable(&schemaMigration{}) {
+		return nil
+	}
+	if err := conn.Migrator().CreateTable(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied returns the applied migrations with the time each was applied.
+func (m *Migrator) applied(conn *gorm.DB) (map[string]time.Time, error) {
+	var rows []schemaMigration
+	if err := conn.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	done := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		done[row.ID] = row.AppliedAt
+	}
+	return done, nil
+}
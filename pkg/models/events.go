@@ -1,32 +1,101 @@
 // Package models defines event structures for inter-service communication and message publishing.
+//
+// Event schema versions:
+//   - v1: original payloads without any envelope fields (no version, event_id or emitted_at)
+//   - v2: adds the version, event_id and emitted_at envelope fields (current)
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Event type constants for identifying different types of system events.
 const (
 	EventUserCreated  = "user.created"  // Fired when a new user account is created
 	EventRiskDetected = "risk.detected" // Fired when risk assessment detects potential issues
+
+	EventRiskLevelChanged = "risk.level_changed" // Fired when a user's risk level differs from their previous check
+)
+
+// Event schema version constants.
+const (
+	EventVersionV1      = 1              // Legacy payloads published before versioning was introduced
+	EventVersionV2      = 2              // Payloads carrying the event envelope fields
+	CurrentEventVersion = EventVersionV2 // Version set by publishers
 )
 
+// EventMeta holds the envelope fields shared by all published events.
+// embedded in every event so consumers can tell payload versions apart.
+type EventMeta struct {
+	Version   int       `json:"version,omitempty"`  // Event schema version, missing on v1 payloads
+	EventID   string    `json:"event_id,omitempty"` // Unique identifier of this event instance
+	EmittedAt time.Time `json:"emitted_at"`         // Timestamp when the event was emitted
+}
+
+// NewEventMeta creates envelope fields for a new event at the current schema version.
+func NewEventMeta() EventMeta {
+	return EventMeta{
+		Version:   CurrentEventVersion,
+		EventID:   uuid.New().String(),
+		EmittedAt: time.Now(),
+	}
+}
+
+// SchemaVersion returns the payload schema version, treating a missing version as v1.
+// returns an error for versions this build does not know how to consume.
+func (m EventMeta) SchemaVersion() (int, error) {
+	switch m.Version {
+	case 0, EventVersionV1:
+		return EventVersionV1, nil
+	case EventVersionV2:
+		return EventVersionV2, nil
+	default:
+		return 0, fmt.Errorf("unsupported event version %d (max supported %d)", m.Version, CurrentEventVersion)
+	}
+}
+
 // UserCreatedEvent represents the event data published when a new user is created.
 // contains essential user information for downstream services like notifications and analytics.
 type UserCreatedEvent struct {
+	EventMeta
 	UserID    string    `json:"user_id"`    // Unique user identifier
 	Email     string    `json:"email"`      // User's email address
 	FirstName string    `json:"first_name"` // User's first name
 	LastName  string    `json:"last_name"`  // User's last name
 	Phone     string    `json:"phone"`      // User's phone number
+	Locale    string    `json:"locale"`     // User's preferred notification language
 	CreatedAt time.Time `json:"created_at"` // Timestamp when user was created
 }
 
 // RiskDetectedEvent represents the event data published when risk assessment identifies potential issues.
 // includes risk level, reasons, and specific flags for automated and manual review processes.
 type RiskDetectedEvent struct {
+	EventMeta
 	UserID     string    `json:"user_id"`     // Unique user identifier associated with the risk
+	OrgID      string    `json:"org_id"`      // Organization of the user, the default one on older payloads
 	Email      string    `json:"email"`       // User's email address for notification purposes
+	FirstName  string    `json:"first_name"`  // User's first name for the alert greeting, empty on older payloads
+	Phone      string    `json:"phone"`       // User's phone number for SMS alerts, empty when unknown
+	Locale     string    `json:"locale"`      // User's preferred notification language, empty on older payloads
 	RiskLevel  string    `json:"risk_level"`  // Risk severity level (low, medium, high, critical)
 	Reason     string    `json:"reason"`      // Primary reason for risk detection
 	Flags      []string  `json:"flags"`       // Specific risk flags that were triggered
 	DetectedAt time.Time `json:"detected_at"` // Timestamp when risk was detected
+
+	CheckID string `json:"check_id,omitempty"` // Risk check that produced this event, missing on older payloads
+}
+
+// RiskLevelChangedEvent represents the event data published when a check moves a user to another risk level.
+// compares against the user's previous stored check, a user's first check never produces one.
+type RiskLevelChangedEvent struct {
+	EventMeta
+	UserID    string    `json:"user_id"`    // User whose level changed
+	OrgID     string    `json:"org_id"`     // Organization whose rules were applied
+	CheckID   string    `json:"check_id"`   // Risk check that produced the new level
+	OldLevel  string    `json:"old_level"`  // Level of the previous stored check
+	NewLevel  string    `json:"new_level"`  // Level of this check
+	ChangedAt time.Time `json:"changed_at"` // Timestamp of the check
 }
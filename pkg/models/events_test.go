@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"user-risk-system/pkg/models"
+)
+
+func TestUnmarshalVersionedEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantVersion int
+		wantEventID string
+		wantErr     bool
+	}{
+		{
+			name:        "v1 payload without envelope",
+			payload:     `{"user_id":"user-1","email":"user@example.com","created_at":"2024-01-02T03:04:05Z"}`,
+			wantVersion: models.EventVersionV1,
+		},
+		{
+			name:        "v2 payload",
+			payload:     `{"version":2,"event_id":"evt-1","emitted_at":"2024-01-02T03:04:06Z","user_id":"user-1","email":"user@example.com","created_at":"2024-01-02T03:04:05Z"}`,
+			wantVersion: models.EventVersionV2,
+			wantEventID: "evt-1",
+		},
+		{
+			name:    "unknown future version",
+			payload: `{"version":9,"user_id":"user-1"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event models.UserCreatedEvent
+			if err := json.Unmarshal([]byte(tt.payload), &event); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if event.UserID != "user-1" {
+				t.Errorf("UserID = %q, want user-1", event.UserID)
+			}
+
+			version, err := event.SchemaVersion()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SchemaVersion() = %d, want an error", version)
+				}
+				return
+			}
+			if err != nil || version != tt.wantVersion {
+				t.Errorf("SchemaVersion() = %d, %v, want %d", version, err, tt.wantVersion)
+			}
+			if event.EventID != tt.wantEventID {
+				t.Errorf("EventID = %q, want %q", event.EventID, tt.wantEventID)
+			}
+		})
+	}
+}
+
+func TestNewEventMetaUsesCurrentVersion(t *testing.T) {
+	a, b := models.NewEventMeta(), models.NewEventMeta()
+	if a.Version != models.CurrentEventVersion {
+		t.Errorf("Version = %d, want %d", a.Version, models.CurrentEventVersion)
+	}
+	if a.EventID == "" || a.EventID == b.EventID {
+		t.Errorf("event IDs %q and %q are not unique", a.EventID, b.EventID)
+	}
+	if a.EmittedAt.IsZero() {
+		t.Error("EmittedAt is not set")
+	}
+}
@@ -0,0 +1,164 @@
+// Package outbox implements the transactional outbox pattern for reliable event publishing.
+// Events are written to an outbox table in the same transaction as the business data and
+// a background relay publishes them, guaranteeing at-least-once delivery.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/scheduler"
+)
+
+// Message represents a pending or published event stored in the outbox table.
+type Message struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Queue     string     `json:"queue" gorm:"type:varchar(255);not null"`
+	Payload   []byte     `json:"payload" gorm:"not null"`
+	Attempts  int        `json:"attempts" gorm:"default:0"`
+	LastError string     `json:"last_error" gorm:"type:text"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+	SentAt    *time.Time `json:"sent_at" gorm:"index"` // NULL until the relay has published it
+}
+
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// Publisher is the subset of the messaging client the relay needs to deliver events.
+type Publisher interface {
+	Publish(queueName string, message interface{}) error
+}
+
+// Enqueue stores an event in the outbox using the given transaction.
+// must be called with the same *gorm.DB transaction that writes the business data.
+func Enqueue(tx *gorm.DB, queue string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+
+	if err := tx.Create(&Message{Queue: queue, Payload: payload}).Error; err != nil {
+		return fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+	return nil
+}
+
+// Relay periodically publishes unsent outbox messages and marks them as sent.
+type Relay struct {
+	db        *gorm.DB
+	publisher Publisher
+	logger    *logger.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewRelay creates a new outbox relay polling at the given interval.
+func NewRelay(db *gorm.DB, publisher Publisher, logger *logger.Logger, interval time.Duration) *Relay {
+	return &Relay{
+		db:        db,
+		publisher: publisher,
+		logger:    logger,
+		interval:  interval,
+		batchSize: 100,
+	}
+}
+
+// Run relays pending messages until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Info("Outbox relay started", "interval", r.interval.String())
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil {
+			r.logger.Error("Failed to relay outbox messages", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of unsent messages and returns how many were sent.
+// rows are locked with SKIP LOCKED so several relay instances can run side by side.
+// A message is only marked sent after a successful publish, so a crash between the
+// two steps results in a redelivery rather than a lost event. The batch stops at the first
+// message failing because the broker is disconnected, the rest wait for the next poll.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	sent := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var messages []Message
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL").
+			Order("id").
+			Limit(r.batchSize).
+			Find(&messages).Error
+		if err != nil {
+			return fmt.Errorf("failed to load outbox messages: %w", err)
+		}
+
+		for _, msg := range messages {
+			if pubErr := r.publisher.Publish(msg.Queue, json.RawMessage(msg.Payload)); pubErr != nil {
+				r.logger.Error("Failed to publish outbox message", pubErr,
+					"outbox_id", msg.ID,
+					"queue", msg.Queue,
+				)
+				if err := tx.Model(&msg).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": pubErr.Error(),
+				}).Error; err != nil {
+					return fmt.Errorf("failed to record outbox failure: %w", err)
+				}
+				if errors.Is(pubErr, messaging.ErrDisconnected) {
+					break
+				}
+				continue
+			}
+
+			if err := tx.Model(&msg).Update("sent_at", time.Now()).Error; err != nil {
+				return fmt.Errorf("failed to mark outbox message sent: %w", err)
+			}
+			sent++
+		}
+
+		return nil
+	})
+
+	return sent, err
+}
+
+// PurgeJob returns a scheduler job deleting messages published more than retention ago.
+// unsent messages are kept however old they are, the relay still has to deliver them.
+func PurgeJob(db *gorm.DB, retention time.Duration, l *logger.Logger) scheduler.Job {
+	return scheduler.Job{
+		Name:      "outbox_purge",
+		Interval:  time.Hour,
+		Jitter:    5 * time.Minute,
+		Singleton: true,
+		Run: func(ctx context.Context) error {
+			result := db.WithContext(ctx).Delete(&Message{}, "sent_at IS NOT NULL AND sent_at < ?", time.Now().Add(-retention))
+			if result.Error != nil {
+				return fmt.Errorf("failed to purge outbox messages: %w", result.Error)
+			}
+			if result.RowsAffected > 0 {
+				l.Info("Purged published outbox messages", "deleted", result.RowsAffected)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/testutil"
+)
+
+// account stands in for the business row written together with the event.
+type account struct {
+	ID    uint `gorm:"primaryKey"`
+	Email string
+}
+
+// flakyPublisher fails the first failures publishes with err and records the rest.
+type flakyPublisher struct {
+	failures  int
+	err       error
+	published []json.RawMessage
+}
+
+func (p *flakyPublisher) Publish(_ string, message interface{}) error {
+	if p.failures > 0 {
+		p.failures--
+		return p.err
+	}
+	p.published = append(p.published, message.(json.RawMessage))
+	return nil
+}
+
+func newOutboxDB(t *testing.T) *gorm.DB {
+	return testutil.NewSQLiteDB(t, func(db *gorm.DB) error {
+		return db.AutoMigrate(&account{}, &outbox.Message{})
+	})
+}
+
+func newRelay(db *gorm.DB, publisher outbox.Publisher) *outbox.Relay {
+	return outbox.NewRelay(db, publisher, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}), time.Hour)
+}
+
+// createAccount writes an account and its creation event in one transaction, failing it when fail is set.
+func createAccount(db *gorm.DB, email string, fail error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&account{Email: email}).Error; err != nil {
+			return err
+		}
+		if err := outbox.Enqueue(tx, "user.created", map[string]string{"email": email}); err != nil {
+			return err
+		}
+		return fail
+	})
+}
+
+func TestRelayDeliversEventsCommittedBeforeACrash(t *testing.T) {
+	db := newOutboxDB(t)
+	if err := createAccount(db, "user@example.com", nil); err != nil {
+		t.Fatalf("createAccount() error = %v", err)
+	}
+	// The process dies here, after the commit and before anything was published.
+
+	publisher := &flakyPublisher{}
+	relay := newRelay(db, publisher)
+	sent, err := relay.RelayPending(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("RelayPending() = %d, %v, want 1 event sent by the restarted relay", sent, err)
+	}
+	if len(publisher.published) != 1 || string(publisher.published[0]) != `{"email":"user@example.com"}` {
+		t.Errorf("published = %s", publisher.published)
+	}
+
+	if sent, err := relay.RelayPending(context.Background()); err != nil || sent != 0 {
+		t.Errorf("second RelayPending() = %d, %v, want the event published once", sent, err)
+	}
+}
+
+func TestEnqueueRollsBackWithTheBusinessWrite(t *testing.T) {
+	db := newOutboxDB(t)
+	if err := createAccount(db, "user@example.com", errors.New("insert failed")); err == nil {
+		t.Fatal("createAccount() succeeded")
+	}
+
+	var pending int64
+	db.Model(&outbox.Message{}).Count(&pending)
+	if pending != 0 {
+		t.Errorf("outbox holds %d events of a rolled back transaction", pending)
+	}
+}
+
+func TestRelayKeepsFailedEventsForTheNextPoll(t *testing.T) {
+	db := newOutboxDB(t)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := createAccount(db, email, nil); err != nil {
+			t.Fatalf("createAccount() error = %v", err)
+		}
+	}
+
+	publisher := &flakyPublisher{failures: 1, err: messaging.ErrDisconnected}
+	relay := newRelay(db, publisher)
+	if sent, err := relay.RelayPending(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("RelayPending() while disconnected = %d, %v, want nothing sent", sent, err)
+	}
+
+	var failed outbox.Message
+	db.Order("id").First(&failed)
+	if failed.SentAt != nil || failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("failed event = %+v, want unsent with one recorded attempt", failed)
+	}
+
+	if sent, err := relay.RelayPending(context.Background()); err != nil || sent != 2 {
+		t.Errorf("RelayPending() after reconnecting = %d, %v, want both events sent", sent, err)
+	}
+}
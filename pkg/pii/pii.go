@@ -0,0 +1,37 @@
+// Package pii masks personal data such as email addresses and phone numbers before it is logged.
+package pii
+
+import "strings"
+
+// Redacted replaces the hidden part of a masked value.
+const Redacted = "***"
+
+// MaskEmail obscures an email address for secure logging, e.g. "jo***@example.com".
+// malformed addresses are fully masked.
+func MaskEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+
+	username, domain, found := strings.Cut(email, "@")
+	if !found || username == "" || domain == "" || strings.Contains(domain, "@") {
+		return Redacted
+	}
+
+	if len(username) <= 2 {
+		return Redacted + "@" + domain
+	}
+	return username[:2] + Redacted + "@" + domain
+}
+
+// MaskPhone obscures a phone number for secure logging, keeping the last two digits, e.g. "***01".
+// short numbers are fully masked.
+func MaskPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+	if len(phone) <= 4 {
+		return Redacted
+	}
+	return Redacted + phone[len(phone)-2:]
+}
@@ -0,0 +1,53 @@
+package pii_test
+
+import (
+	"testing"
+
+	"user-risk-system/pkg/pii"
+)
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"regular address", "john.doe@example.com", "jo***@example.com"},
+		{"short local part", "jo@example.com", "***@example.com"},
+		{"single character local part", "j@example.com", "***@example.com"},
+		{"missing at sign", "john.example.com", "***"},
+		{"duplicate at sign", "john@doe@example.com", "***"},
+		{"empty local part", "@example.com", "***"},
+		{"empty domain", "john@", "***"},
+		{"empty input", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pii.MaskEmail(tt.email); got != tt.want {
+				t.Errorf("MaskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+	}{
+		{"E.164 number", "+15550100", "***00"},
+		{"short number", "1234", "***"},
+		{"five digits", "12345", "***45"},
+		{"empty input", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pii.MaskPhone(tt.phone); got != tt.want {
+				t.Errorf("MaskPhone(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
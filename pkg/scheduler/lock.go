@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"user-risk-system/pkg/logger"
+)
+
+// DefaultLeaseTTL is how long a job lock outlives a crashed holder.
+const DefaultLeaseTTL = time.Minute
+
+// Locker hands out named locks for singleton jobs.
+type Locker interface {
+	// TryLock takes the lock named name without waiting, ok is false when another holder has it.
+	// the returned context is cancelled if the lock is lost, unlock must be called once the job is done.
+	// after unlock the lock stays taken until interval has passed since it was taken, so other
+	// instances skip the rest of the round instead of running the job again right away.
+	TryLock(ctx context.Context, name string, interval time.Duration) (lockCtx context.Context, unlock func(), ok bool, err error)
+}
+
+// jobLock is a lease on a job, held by one scheduler instance until it expires.
+type jobLock struct {
+	Name      string    `gorm:"primaryKey;type:varchar(255)"`
+	Holder    string    `gorm:"type:varchar(255);not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+func (jobLock) TableName() string {
+	return "scheduler_locks"
+}
+
+// LeaseLocker keeps job locks as leases in a scheduler_locks table shared by every replica.
+// a held lease is renewed while the job runs and kept until the job's interval since it was
+// taken has passed. A crashed holder stops renewing, so its lease expires after the TTL and
+// another instance takes over.
+// expiry uses each instance's clock, keep the TTL well above the expected clock skew.
+type LeaseLocker struct {
+	db     *gorm.DB
+	holder string
+	ttl    time.Duration
+	logger *logger.Logger
+
+	mu         sync.Mutex
+	tableReady bool
+}
+
+// NewDBLocker returns a lease Locker on db with a unique holder ID and DefaultLeaseTTL.
+func NewDBLocker(db *gorm.DB, logger *logger.Logger) *LeaseLocker {
+	return NewLeaseLocker(db, uuid.New().String(), DefaultLeaseTTL, logger)
+}
+
+// NewLeaseLocker creates a lease Locker acting as holder, whose leases expire ttl after their last renewal.
+func NewLeaseLocker(db *gorm.DB, holder string, ttl time.Duration, logger *logger.Logger) *LeaseLocker {
+	return &LeaseLocker{db: db, holder: holder, ttl: ttl, logger: logger}
+}
+
+func (l *LeaseLocker) TryLock(ctx context.Context, name string, interval time.Duration) (context.Context, func(), bool, error) {
+	if err := l.ensureTable(ctx); err != nil {
+		return nil, nil, false, err
+	}
+
+	acquiredAt := time.Now()
+	ok, err := l.acquire(ctx, name)
+	if err != nil || !ok {
+		return nil, nil, false, err
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		l.renew(lockCtx, cancel, name)
+	}()
+
+	unlock := func() {
+		cancel()
+		<-renewed
+		// The job's context may be done already, the lease must be released regardless.
+		// it expires at the end of the round, or right away when the run took longer
+		if err := l.db.WithContext(context.Background()).Model(&jobLock{}).
+			Where("name = ? AND holder = ?", name, l.holder).
+			Update("expires_at", acquiredAt.Add(interval)).Error; err != nil {
+			l.logger.Error("Failed to release job lock", err, "job", name)
+		}
+	}
+	return lockCtx, unlock, true, nil
+}
+
+// acquire inserts the lease of name, or takes it over when it belongs to this holder or has expired.
+func (l *LeaseLocker) acquire(ctx context.Context, name string) (bool, error) {
+	now := time.Now()
+	db := l.db.WithContext(ctx)
+
+	inserted := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&jobLock{Name: name, Holder: l.holder, ExpiresAt: now.Add(l.ttl)})
+	if inserted.Error != nil {
+		return false, fmt.Errorf("failed to take job lock: %w", inserted.Error)
+	}
+	if inserted.RowsAffected == 1 {
+		return true, nil
+	}
+
+	takeover := db.Model(&jobLock{}).
+		Where("name = ? AND (expires_at < ? OR holder = ?)", name, now, l.holder).
+		Updates(map[string]interface{}{"holder": l.holder, "expires_at": now.Add(l.ttl)})
+	if takeover.Error != nil {
+		return false, fmt.Errorf("failed to take over job lock: %w", takeover.Error)
+	}
+	return takeover.RowsAffected == 1, nil
+}
+
+// renew extends the lease of name every third of the TTL until ctx ends.
+// cancels the job through lost when the lease can't be renewed, another instance may own it by then.
+func (l *LeaseLocker) renew(ctx context.Context, lost context.CancelFunc, name string) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result := l.db.WithContext(ctx).Model(&jobLock{}).
+			Where("name = ? AND holder = ?", name, l.holder).
+			Update("expires_at", time.Now().Add(l.ttl))
+		if ctx.Err() != nil {
+			return
+		}
+		if result.Error != nil || result.RowsAffected == 0 {
+			l.logger.Error("Lost job lock, cancelling the run", result.Error, "job", name)
+			lost()
+			return
+		}
+	}
+}
+
+// ensureTable creates the scheduler_locks table on first use.
+// another replica may create it concurrently, which is fine as long as the table exists afterwards.
+func (l *LeaseLocker) ensureTable(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tableReady {
+		return nil
+	}
+
+	db := l.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&jobLock{}) {
+		if err := db.Migrator().CreateTable(&jobLock{}); err != nil && !db.Migrator().HasTable(&jobLock{}) {
+			return fmt.Errorf("failed to create scheduler_locks table: %w", err)
+		}
+	}
+	l.tableReady = true
+	return nil
+}
+
+// LocalLocker locks within the process only, for single-instance deployments.
+type LocalLocker struct {
+	mu   sync.Mutex
+	held map[string]time.Time // Lock name -> end of its round, the zero time while the job runs
+}
+
+// NewLocalLocker creates an in-process Locker.
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{held: make(map[string]time.Time)}
+}
+
+func (l *LocalLocker) TryLock(ctx context.Context, name string, interval time.Duration) (context.Context, func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if until, ok := l.held[name]; ok && (until.IsZero() || now.Before(until)) {
+		return nil, nil, false, nil
+	}
+	l.held[name] = time.Time{}
+
+	return ctx, func() {
+		l.mu.Lock()
+		l.held[name] = now.Add(interval)
+		l.mu.Unlock()
+	}, true, nil
+}
@@ -0,0 +1,72 @@
+package scheduler_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/scheduler"
+	"user-risk-system/pkg/testutil"
+)
+
+func TestLeaseLockerContention(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, nil)
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	a := scheduler.NewLeaseLocker(db, "replica-a", time.Minute, log)
+	b := scheduler.NewLeaseLocker(db, "replica-b", time.Minute, log)
+	ctx := context.Background()
+
+	_, unlock, ok, err := a.TryLock(ctx, "job", time.Hour)
+	if err != nil || !ok {
+		t.Fatalf("first TryLock() = %v, %v, want the lock", ok, err)
+	}
+	if _, _, ok, err := b.TryLock(ctx, "job", time.Hour); err != nil || ok {
+		t.Fatalf("TryLock() while held = %v, %v, want contention", ok, err)
+	}
+
+	unlock()
+	if _, _, ok, err := b.TryLock(ctx, "job", time.Hour); err != nil || ok {
+		t.Errorf("TryLock() after the run within its interval = %v, %v, want the round to stay taken", ok, err)
+	}
+	if _, _, ok, err := b.TryLock(ctx, "other-job", time.Hour); err != nil || !ok {
+		t.Errorf("TryLock() of another job = %v, %v, want the lock", ok, err)
+	}
+}
+
+func TestLeaseLockerReleasesAfterInterval(t *testing.T) {
+	db := testutil.NewSQLiteDB(t, nil)
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	a := scheduler.NewLeaseLocker(db, "replica-a", time.Minute, log)
+	b := scheduler.NewLeaseLocker(db, "replica-b", time.Minute, log)
+	ctx := context.Background()
+
+	_, unlock, ok, err := a.TryLock(ctx, "job", 10*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("first TryLock() = %v, %v, want the lock", ok, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	unlock()
+
+	if _, _, ok, err := b.TryLock(ctx, "job", time.Hour); err != nil || !ok {
+		t.Errorf("TryLock() after the interval = %v, %v, want the lock", ok, err)
+	}
+}
+
+func TestLocalLockerKeepsRound(t *testing.T) {
+	l := scheduler.NewLocalLocker()
+	ctx := context.Background()
+
+	_, unlock, ok, _ := l.TryLock(ctx, "job", time.Hour)
+	if !ok {
+		t.Fatal("first TryLock() failed")
+	}
+	if _, _, ok, _ := l.TryLock(ctx, "job", time.Hour); ok {
+		t.Error("TryLock() while held succeeded")
+	}
+	unlock()
+	if _, _, ok, _ := l.TryLock(ctx, "job", time.Hour); ok {
+		t.Error("TryLock() within the interval succeeded")
+	}
+}
@@ -0,0 +1,155 @@
+// Package scheduler runs registered background jobs at fixed intervals.
+// Jobs marked Singleton take a lock before each run, so with several replicas only one runs
+// a given job per interval and the others skip that round. A run is cancelled if its lock is lost.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"user-risk-system/pkg/logger"
+)
+
+// Job is a periodic task.
+type Job struct {
+	Name      string        // Identifies the job in logs and names its lock
+	Interval  time.Duration // Time between the end of one run and the start of the next
+	Jitter    time.Duration // Random extra delay of up to Jitter before every run, spreads replicas apart
+	Timeout   time.Duration // Limit of a single run, defaults to Interval
+	Singleton bool          // Run on one replica at a time through the scheduler's Locker
+	Run       func(ctx context.Context) error
+}
+
+// Scheduler runs jobs until stopped.
+type Scheduler struct {
+	locker Locker
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	jobs    []Job
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// New creates a scheduler taking the locks of singleton jobs from locker.
+func New(locker Locker, logger *logger.Logger) *Scheduler {
+	return &Scheduler{locker: locker, logger: logger}
+}
+
+// Register adds job to the scheduler, jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job needs a name and a run function")
+	}
+	if job.Interval <= 0 {
+		return fmt.Errorf("job %s needs a positive interval", job.Name)
+	}
+	if job.Jitter < 0 {
+		return fmt.Errorf("job %s has a negative jitter", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("job %s registered after the scheduler started", job.Name)
+	}
+	for _, registered := range s.jobs {
+		if registered.Name == job.Name {
+			return fmt.Errorf("job %s is already registered", job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Start runs every registered job in its own goroutine, the first run comes one interval after Start.
+// jobs stop when ctx is cancelled or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	s.logger.Info("Scheduler started", "jobs", len(s.jobs))
+}
+
+// Stop cancels the jobs and waits for runs in progress to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	s.wg.Wait()
+	s.logger.Info("Scheduler stopped")
+}
+
+// loop runs job every interval until ctx is cancelled.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	for {
+		timer := time.NewTimer(job.Interval + jitter(job.Jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runOnce(ctx, job)
+	}
+}
+
+// runOnce runs job a single time, taking its lock first when it is a singleton.
+// failures are logged, the job runs again at its next interval.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	if job.Singleton {
+		lockCtx, unlock, ok, err := s.locker.TryLock(ctx, job.Name, job.Interval)
+		if err != nil {
+			s.logger.Error("Failed to acquire job lock", err, "job", job.Name)
+			return
+		}
+		if !ok {
+			s.logger.Debug("Job skipped, running on another instance", "job", job.Name)
+			return
+		}
+		defer unlock()
+		ctx = lockCtx
+	}
+
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = job.Interval
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		s.logger.Error("Job failed", err, "job", job.Name, "duration", time.Since(started).String())
+		return
+	}
+	s.logger.Debug("Job completed", "job", job.Name, "duration", time.Since(started).String())
+}
+
+// jitter returns a random delay in [0, max).
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
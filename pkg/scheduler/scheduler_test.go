@@ -0,0 +1,125 @@
+package scheduler_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/scheduler"
+	"user-risk-system/pkg/testutil"
+)
+
+func newScheduler(locker scheduler.Locker) *scheduler.Scheduler {
+	return scheduler.New(locker, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+}
+
+func TestJobRunsOnItsInterval(t *testing.T) {
+	s := newScheduler(scheduler.NewLocalLocker())
+	var runs atomic.Int32
+	started := time.Now()
+	var first atomic.Int64
+	err := s.Register(scheduler.Job{Name: "tick", Interval: 50 * time.Millisecond, Run: func(context.Context) error {
+		if runs.Add(1) == 1 {
+			first.Store(int64(time.Since(started)))
+		}
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	s.Start(context.Background())
+	defer s.Stop()
+	testutil.Eventually(t, func() bool { return runs.Load() >= 3 }, "three runs of the job")
+
+	if delay := time.Duration(first.Load()); delay < 50*time.Millisecond {
+		t.Errorf("first run %v after Start, want one interval later", delay)
+	}
+}
+
+func TestStopCancelsRunAndEndsJobs(t *testing.T) {
+	s := newScheduler(scheduler.NewLocalLocker())
+	var runs atomic.Int32
+	running := make(chan struct{})
+	var cancelled atomic.Bool
+	err := s.Register(scheduler.Job{Name: "slow", Interval: 10 * time.Millisecond, Timeout: time.Hour, Run: func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			close(running)
+		}
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	s.Start(context.Background())
+	<-running
+	s.Stop()
+
+	if !cancelled.Load() {
+		t.Fatal("Stop() returned before the run in progress saw its context cancelled")
+	}
+	after := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := runs.Load(); got != after {
+		t.Errorf("job ran %d more times after Stop", got-after)
+	}
+}
+
+func TestSingletonJobSkipsRoundHeldElsewhere(t *testing.T) {
+	locker := scheduler.NewLocalLocker()
+	_, unlock, ok, _ := locker.TryLock(context.Background(), "singleton", time.Hour)
+	if !ok {
+		t.Fatal("failed to take the lock")
+	}
+
+	s := newScheduler(locker)
+	var singletonRuns, otherRuns atomic.Int32
+	s.Register(scheduler.Job{Name: "singleton", Interval: 10 * time.Millisecond, Singleton: true, Run: func(context.Context) error {
+		singletonRuns.Add(1)
+		return nil
+	}})
+	s.Register(scheduler.Job{Name: "other", Interval: 10 * time.Millisecond, Run: func(context.Context) error {
+		otherRuns.Add(1)
+		return nil
+	}})
+
+	s.Start(context.Background())
+	defer s.Stop()
+	testutil.Eventually(t, func() bool { return otherRuns.Load() >= 3 }, "runs of the job without a lock")
+	if got := singletonRuns.Load(); got != 0 {
+		t.Errorf("singleton job ran %d times while another holder had its lock", got)
+	}
+	unlock()
+}
+
+func TestRegisterRejectsInvalidJobs(t *testing.T) {
+	run := func(context.Context) error { return nil }
+	s := newScheduler(scheduler.NewLocalLocker())
+	if err := s.Register(scheduler.Job{Name: "job", Interval: time.Minute, Run: run}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	for name, job := range map[string]scheduler.Job{
+		"no name":         {Interval: time.Minute, Run: run},
+		"no run function": {Name: "no-run", Interval: time.Minute},
+		"no interval":     {Name: "no-interval", Run: run},
+		"negative jitter": {Name: "jitter", Interval: time.Minute, Jitter: -time.Second, Run: run},
+		"duplicate name":  {Name: "job", Interval: time.Minute, Run: run},
+	} {
+		if err := s.Register(job); err == nil {
+			t.Errorf("Register() with %s succeeded", name)
+		}
+	}
+
+	s.Start(context.Background())
+	defer s.Stop()
+	if err := s.Register(scheduler.Job{Name: "late", Interval: time.Minute, Run: run}); err == nil {
+		t.Error("Register() after Start succeeded")
+	}
+}
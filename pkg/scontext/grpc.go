@@ -0,0 +1,79 @@
+package scontext
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Incoming metadata keys read by the server interceptors.
+const (
+	RequestIDMetadataKey = "x-request-id"
+	UserIDMetadataKey    = "x-user-id"
+	UserEmailMetadataKey = "x-user-email"
+)
+
+// userIDGetter and emailGetter match the generated getters of request messages carrying a user.
+type userIDGetter interface{ GetUserId() string }
+type emailGetter interface{ GetEmail() string }
+
+// UnaryServerInterceptor enriches the context of every unary call with its request metadata and message fields.
+// chain it before authentication so the caller's claims take precedence over request supplied values.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(FromMessage(FromMetadata(ctx), req), req)
+	}
+}
+
+// StreamServerInterceptor enriches the context of every stream with its request metadata.
+// messages arrive after the stream starts, handlers add their fields with FromMessage.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: FromMetadata(ss.Context())})
+	}
+}
+
+// FromMetadata adds the request ID, user ID and email of the incoming gRPC metadata to ctx.
+func FromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return New(ctx).
+		WithRequestID(firstValue(md, RequestIDMetadataKey)).
+		WithUserID(firstValue(md, UserIDMetadataKey)).
+		WithUserEmail(firstValue(md, UserEmailMetadataKey)).
+		Build()
+}
+
+// FromMessage adds the user ID and email of a request message to ctx, when it has them.
+func FromMessage(ctx context.Context, msg interface{}) context.Context {
+	b := New(ctx)
+	if m, ok := msg.(userIDGetter); ok {
+		b.WithUserID(m.GetUserId())
+	}
+	if m, ok := msg.(emailGetter); ok {
+		b.WithUserEmail(m.GetEmail())
+	}
+	return b.Build()
+}
+
+// firstValue returns the first value of key in md, empty when it is missing.
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the enriched stream context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
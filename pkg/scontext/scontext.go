@@ -11,10 +11,11 @@ type contextKey string
 const (
 	UserIDKey    contextKey = "user_id"
 	RequestIDKey contextKey = "request_id"
-	UserEmailKey contextKey = "user_mail"
+	UserEmailKey contextKey = "user_email"
 	SessionIDKey contextKey = "session_id"
 	UserRoleKey  contextKey = "user_role"
 	UserRolesKey contextKey = "user_roles"
+	OrgIDKey     contextKey = "org_id"
 )
 
 // Builder provides a fluent interface for building enriched contexts.
@@ -59,6 +60,14 @@ func (b *Builder) WithUserRoles(roles []string) *Builder {
 	return b
 }
 
+// WithOrgID adds the caller's organization ID to the context if the value is not empty.
+func (b *Builder) WithOrgID(orgID string) *Builder {
+	if orgID != "" {
+		b.ctx = context.WithValue(b.ctx, OrgIDKey, orgID)
+	}
+	return b
+}
+
 // WithRequestID adds a request ID to the context for request tracing if the value is not empty.
 func (b *Builder) WithRequestID(requestID string) *Builder {
 	if requestID != "" {
@@ -117,3 +126,76 @@ func WithUser(ctx context.Context, userID, email, role string) *Builder {
 func WithRequest(ctx context.Context, requestID, sessionID string) *Builder {
 	return New(ctx).WithRequest(requestID, sessionID)
 }
+
+// UserID returns the user ID stored in the context.
+func UserID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, UserIDKey)
+}
+
+// Email returns the user email stored in the context.
+func Email(ctx context.Context) (string, bool) {
+	return stringValue(ctx, UserEmailKey)
+}
+
+// Role returns the single user role stored in the context.
+func Role(ctx context.Context) (string, bool) {
+	return stringValue(ctx, UserRoleKey)
+}
+
+// Roles returns the user roles stored in the context.
+func Roles(ctx context.Context) ([]string, bool) {
+	roles, ok := ctx.Value(UserRolesKey).([]string)
+	return roles, ok && len(roles) > 0
+}
+
+// OrgID returns the organization ID stored in the context.
+func OrgID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, OrgIDKey)
+}
+
+// RequestID returns the request ID stored in the context.
+func RequestID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, RequestIDKey)
+}
+
+// SessionID returns the session ID stored in the context.
+func SessionID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, SessionIDKey)
+}
+
+// stringValue reads a non-empty string stored under key.
+func stringValue(ctx context.Context, key contextKey) (string, bool) {
+	value, ok := ctx.Value(key).(string)
+	return value, ok && value != ""
+}
+
+// Detach returns a new background context carrying the known scontext fields of parent.
+// async work started from a request stays correlated in logs without inheriting the
+// parent's cancellation or deadline.
+func Detach(parent context.Context) context.Context {
+	b := New(context.Background())
+
+	if userID, ok := UserID(parent); ok {
+		b.WithUserID(userID)
+	}
+	if email, ok := Email(parent); ok {
+		b.WithUserEmail(email)
+	}
+	if role, ok := Role(parent); ok {
+		b.WithUserRole(role)
+	}
+	if roles, ok := Roles(parent); ok {
+		b.WithUserRoles(roles)
+	}
+	if orgID, ok := OrgID(parent); ok {
+		b.WithOrgID(orgID)
+	}
+	if requestID, ok := RequestID(parent); ok {
+		b.WithRequestID(requestID)
+	}
+	if sessionID, ok := SessionID(parent); ok {
+		b.WithSessionID(sessionID)
+	}
+
+	return b.Build()
+}
@@ -0,0 +1,113 @@
+package scontext_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/scontext"
+)
+
+func TestGettersReturnStoredValues(t *testing.T) {
+	ctx := scontext.New(context.Background()).
+		WithUserAndRoles("user-1", "user@example.com", []string{"user", "admin"}).
+		WithUserRole("user").
+		WithOrgID("acme").
+		WithRequest("req-1", "session-1").
+		Build()
+
+	for name, tt := range map[string]struct {
+		get  func(context.Context) (string, bool)
+		want string
+	}{
+		"UserID":    {scontext.UserID, "user-1"},
+		"Email":     {scontext.Email, "user@example.com"},
+		"Role":      {scontext.Role, "user"},
+		"OrgID":     {scontext.OrgID, "acme"},
+		"RequestID": {scontext.RequestID, "req-1"},
+		"SessionID": {scontext.SessionID, "session-1"},
+	} {
+		if got, ok := tt.get(ctx); !ok || got != tt.want {
+			t.Errorf("%s() = %q, %v, want %q", name, got, ok, tt.want)
+		}
+	}
+	if roles, ok := scontext.Roles(ctx); !ok || !reflect.DeepEqual(roles, []string{"user", "admin"}) {
+		t.Errorf("Roles() = %v, %v, want [user admin]", roles, ok)
+	}
+}
+
+func TestGettersReportAbsentValues(t *testing.T) {
+	contexts := map[string]context.Context{
+		"empty context": context.Background(),
+		"empty values":  scontext.New(context.Background()).WithUserAndRoles("", "", nil).WithRequest("", "").Build(),
+		"bare string keys": context.WithValue(context.WithValue(context.Background(),
+			"user_id", "user-1"), "user_roles", []string{"admin"}),
+		"values of another type": context.WithValue(context.WithValue(context.Background(),
+			scontext.UserIDKey, 42), scontext.UserRolesKey, "admin"),
+	}
+
+	for name, ctx := range contexts {
+		t.Run(name, func(t *testing.T) {
+			for getter, get := range map[string]func(context.Context) (string, bool){
+				"UserID":    scontext.UserID,
+				"Email":     scontext.Email,
+				"Role":      scontext.Role,
+				"OrgID":     scontext.OrgID,
+				"RequestID": scontext.RequestID,
+				"SessionID": scontext.SessionID,
+			} {
+				if got, ok := get(ctx); ok || got != "" {
+					t.Errorf("%s() = %q, %v, want absent", getter, got, ok)
+				}
+			}
+			if roles, ok := scontext.Roles(ctx); ok || roles != nil {
+				t.Errorf("Roles() = %v, %v, want absent", roles, ok)
+			}
+		})
+	}
+}
+
+func TestDetachKeepsFieldsWithoutCancellation(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Hour)
+	parent = scontext.New(parent).
+		WithUserAndRoles("user-1", "user@example.com", []string{"admin"}).
+		WithOrgID("acme").
+		WithRequest("req-1", "session-1").
+		WithCustomField("unrelated", "value").
+		Build()
+
+	detached := scontext.Detach(parent)
+	cancel()
+
+	if parent.Err() == nil {
+		t.Fatal("parent was not cancelled")
+	}
+	if err := detached.Err(); err != nil {
+		t.Errorf("detached context cancelled with its parent: %v", err)
+	}
+	if _, ok := detached.Deadline(); ok {
+		t.Error("detached context inherited the parent's deadline")
+	}
+
+	for name, tt := range map[string]struct {
+		get  func(context.Context) (string, bool)
+		want string
+	}{
+		"UserID":    {scontext.UserID, "user-1"},
+		"Email":     {scontext.Email, "user@example.com"},
+		"OrgID":     {scontext.OrgID, "acme"},
+		"RequestID": {scontext.RequestID, "req-1"},
+		"SessionID": {scontext.SessionID, "session-1"},
+	} {
+		if got, _ := tt.get(detached); got != tt.want {
+			t.Errorf("%s() = %q after Detach, want %q", name, got, tt.want)
+		}
+	}
+	if roles, _ := scontext.Roles(detached); !reflect.DeepEqual(roles, []string{"admin"}) {
+		t.Errorf("Roles() = %v after Detach, want [admin]", roles)
+	}
+	if detached.Value("unrelated") != nil {
+		t.Error("Detach copied a value that isn't a scontext field")
+	}
+}
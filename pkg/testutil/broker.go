@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"user-risk-system/pkg/messaging"
+
+	"github.com/streadway/amqp"
+)
+
+// errBrokerStopped fails connection attempts between StopBroker and StartBroker.
+var errBrokerStopped = errors.New("testutil: broker stopped")
+
+// brokerConn is a connection of the harness's Resilient client to its in-memory broker.
+// closing it only ends the connection, the broker keeps its queues and published messages.
+type brokerConn struct {
+	*messaging.InMemory
+
+	once      sync.Once
+	mu        sync.Mutex
+	lost      chan struct{}
+	receivers []chan *amqp.Error
+}
+
+// newBrokerConn opens a connection to broker.
+func newBrokerConn(broker *messaging.InMemory) *brokerConn {
+	return &brokerConn{InMemory: broker, lost: make(chan struct{})}
+}
+
+// NotifyClose registers receiver to be notified once when the connection is lost.
+func (c *brokerConn) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.mu.Lock()
+	c.receivers = append(c.receivers, receiver)
+	c.mu.Unlock()
+	return receiver
+}
+
+// Consume delivers messages of the queue until ctx is cancelled or the connection is lost.
+func (c *brokerConn) Consume(ctx context.Context, queueName string, handler func([]byte) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.lost:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return c.InMemory.Consume(ctx, queueName, handler)
+}
+
+// Close ends the connection, leaving the broker open.
+func (c *brokerConn) Close() error {
+	c.lose(nil)
+	return nil
+}
+
+// lose ends the connection and notifies the receivers with err, like RabbitMQ on a lost connection.
+func (c *brokerConn) lose(err *amqp.Error) {
+	c.once.Do(func() {
+		close(c.lost)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, receiver := range c.receivers {
+			if err != nil {
+				receiver <- err
+			}
+			close(receiver)
+		}
+	})
+}
+
+// dialBroker connects the Resilient client to Broker unless it is stopped.
+func (h *Harness) dialBroker(string) (messaging.Conn, error) {
+	h.brokerMu.Lock()
+	defer h.brokerMu.Unlock()
+	if h.brokerStopped {
+		return nil, errBrokerStopped
+	}
+	h.brokerConn = newBrokerConn(h.Broker)
+	return h.brokerConn, nil
+}
+
+// StopBroker makes the broker unreachable like a RabbitMQ outage, the services keep running.
+// returns once the services noticed, messages published meanwhile stay in the outbox.
+func (h *Harness) StopBroker(t testing.TB) {
+	t.Helper()
+
+	h.brokerMu.Lock()
+	h.brokerStopped = true
+	conn := h.brokerConn
+	h.brokerMu.Unlock()
+	if conn != nil {
+		conn.lose(&amqp.Error{Code: amqp.ConnectionForced, Reason: "broker stopped"})
+	}
+	Eventually(t, func() bool { return !h.Messaging.Connected() }, "broker disconnect")
+}
+
+// StartBroker makes a stopped broker reachable again and returns once the services reconnected.
+func (h *Harness) StartBroker(t testing.TB) {
+	t.Helper()
+
+	h.brokerMu.Lock()
+	h.brokerStopped = false
+	h.brokerMu.Unlock()
+	Eventually(t, h.Messaging.Connected, "broker reconnect")
+}
@@ -0,0 +1,57 @@
+package testutil_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/testutil"
+	pb_user "user-risk-system/proto/user"
+)
+
+func TestRegisterWithBrokerDown(t *testing.T) {
+	h := testutil.New(t)
+	h.StopBroker(t)
+
+	resp, err := pb_user.NewUserServiceClient(h.Users.Conn()).Register(context.Background(), &pb_user.RegisterRequest{
+		Email:     "offline@example.com",
+		Password:  "password123",
+		FirstName: "Off",
+		LastName:  "Line",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Several relay polls pass while the broker is down, the event must stay unsent
+	time.Sleep(200 * time.Millisecond)
+	var pending int64
+	if err := h.UserDB.Model(&outbox.Message{}).Where("queue = ? AND sent_at IS NULL", "user.created").Count(&pending).Error; err != nil {
+		t.Fatalf("failed to count outbox messages: %v", err)
+	}
+	if pending != 1 {
+		t.Fatalf("unsent user.created outbox messages = %d, want 1", pending)
+	}
+	if got := h.Broker.Published("user.created"); len(got) != 0 {
+		t.Fatalf("published %d user.created messages while the broker was down", len(got))
+	}
+
+	h.StartBroker(t)
+	messages := h.WaitForMessages(t, "user.created", 1)
+	var event struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.Unmarshal(messages[0], &event); err != nil {
+		t.Fatalf("failed to decode user.created: %v", err)
+	}
+	if event.UserID != resp.User.Id {
+		t.Errorf("user.created user_id = %q, want %q", event.UserID, resp.User.Id)
+	}
+	testutil.Eventually(t, func() bool {
+		var unsent int64
+		h.UserDB.Model(&outbox.Message{}).Where("sent_at IS NULL").Count(&unsent)
+		return unsent == 0
+	}, "outbox to be relayed")
+}
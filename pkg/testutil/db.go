@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/utils"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// dbCounter makes the name of every in-memory database unique within the process.
+var dbCounter atomic.Uint64
+
+// NewSQLiteDB opens an empty in-memory SQLite database migrated by migrate, closed when t ends.
+func NewSQLiteDB(t testing.TB, migrate func(*gorm.DB) error) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared&_pragma=foreign_keys(1)", name, dbCounter.Add(1))
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+	db, err := utils.SetupDatabase(dsn, &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)}, &config.Config{}, log)
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sqlite connection: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if migrate != nil {
+		if err := migrate(db); err != nil {
+			t.Fatalf("failed to migrate sqlite database: %v", err)
+		}
+	}
+	return db
+}
@@ -0,0 +1,41 @@
+package testutil_test
+
+import (
+	"context"
+	"testing"
+
+	"user-risk-system/pkg/testutil"
+	pb_notification "user-risk-system/proto/notification"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecordDeliveryEventsRequiresServiceToken(t *testing.T) {
+	h := testutil.New(t)
+	req := &pb_notification.RecordDeliveryEventsRequest{
+		Provider: "SENDGRID",
+		Events: []*pb_notification.DeliveryEvent{
+			{NotificationId: "forged", Event: "bounce", Email: "victim@example.com"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"anonymous", context.Background(), codes.Unauthenticated},
+		{"user", h.AuthContext(t, context.Background(), h.SeedUser(t, "user@example.com", "password123")), codes.PermissionDenied},
+		{"admin", h.AdminContext(t, context.Background()), codes.PermissionDenied},
+		{"service", h.ServiceContext(t, context.Background()), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Notifications.RecordDeliveryEvents(tt.ctx, req)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("RecordDeliveryEvents() code = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Package testutil runs the user, risk and notification handlers in process for tests.
+// services talk over bufconn, store in in-memory SQLite and exchange events through an
+// in-memory broker, so handler flows run without PostgreSQL, RabbitMQ or open ports.
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	notification_handlers "user-risk-system/cmd/notification/handlers"
+	notification_models "user-risk-system/cmd/notification/models"
+	notification_repository "user-risk-system/cmd/notification/repository"
+	"user-risk-system/cmd/notification/templates"
+	risk_handlers "user-risk-system/cmd/risk-engine/handlers"
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	risk_repository "user-risk-system/cmd/risk-engine/repository"
+	"user-risk-system/cmd/risk-engine/services"
+	user_handlers "user-risk-system/cmd/user/handlers"
+	user_models "user-risk-system/cmd/user/models"
+	user_repository "user-risk-system/cmd/user/repository"
+	"user-risk-system/pkg/auth"
+	"user-risk-system/pkg/client"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/messaging"
+	"user-risk-system/pkg/outbox"
+	"user-risk-system/pkg/scontext"
+	pb_notification "user-risk-system/proto/notification"
+	pb_risk "user-risk-system/proto/risk"
+	pb_user "user-risk-system/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+const (
+	WaitTimeout   = 5 * time.Second       // How long the wait helpers poll before failing
+	pollInterval  = 20 * time.Millisecond // Delay between polls of the wait helpers
+	relayInterval = 20 * time.Millisecond // Outbox poll interval of the harness relays
+	bufSize       = 1024 * 1024           // Buffer of every bufconn listener
+	jwtSecret     = "testutil-jwt-secret" // Signs the tokens of Token and AdminContext
+)
+
+// Harness holds the in-process services of a test and clients connected to them.
+type Harness struct {
+	Config    *config.Config
+	Broker    *messaging.InMemory  // Receives the outbox events and feeds the notification consumers
+	Messaging *messaging.Resilient // Client of Broker the services publish and consume through, see StopBroker
+
+	UserDB         *gorm.DB
+	RiskDB         *gorm.DB
+	NotificationDB *gorm.DB // Stores the notification timelines and statuses
+
+	Users         *client.UserClient
+	Risk          *client.RiskClient
+	Notifications *client.NotificationClient
+
+	RiskEngine *services.RiskEngine
+
+	jwt *auth.JWTManager
+
+	brokerMu      sync.Mutex
+	brokerConn    *brokerConn // Current connection of Messaging
+	brokerStopped bool
+}
+
+// New starts the three services for t, they are stopped when t ends.
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	cfg := &config.Config{
+		Environment:        "test",
+		JWTSecret:          jwtSecret,
+		JWTDuration:        time.Hour,
+		JWTIssuer:          "user-risk-system",
+		SessionTTL:         time.Hour,
+		RiskFlagFormat:     "rule_id",
+		EmailProvider:      "SIMULATE",
+		SMSProvider:        "SIMULATE",
+		PushProvider:       "SIMULATE",
+		OutboxPollInterval: relayInterval,
+		DefaultUserRole:    "user",
+
+		PasswordHistoryDepth: 5,
+	}
+	log := logger.New(logger.LogConfig{Level: "error", Format: "text", ServiceName: "testutil", Environment: "test", Output: io.Discard})
+
+	h := &Harness{
+		Config:         cfg,
+		Broker:         messaging.NewInMemory(),
+		UserDB:         NewSQLiteDB(t, user_models.AutoMigrate),
+		RiskDB:         NewSQLiteDB(t, risk_models.AutoMigrate),
+		NotificationDB: NewSQLiteDB(t, notification_models.AutoMigrate),
+		jwt:            auth.NewJWTManager(cfg.JWTSecret, cfg.JWTDuration, cfg.JWTIssuer),
+	}
+	authMiddleware := auth.NewAuthMiddleware(h.jwt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// Connected like the services are, so StopBroker has the effect of a RabbitMQ outage
+	h.Messaging = messaging.NewResilient("inmemory", messaging.OfflineQueue, relayInterval, log)
+	h.Messaging.SetDialer(h.dialBroker)
+	if err := h.Messaging.Connect(); err != nil {
+		t.Fatalf("failed to connect to broker: %v", err)
+	}
+	go h.Messaging.Run(ctx)
+	t.Cleanup(func() { h.Messaging.Close() })
+
+	go outbox.NewRelay(h.UserDB, h.Messaging.Strict(), log, relayInterval).Run(ctx)
+	go outbox.NewRelay(h.RiskDB, h.Messaging.Strict(), log, relayInterval).Run(ctx)
+
+	userLis := bufconn.Listen(bufSize)
+	riskLis := bufconn.Listen(bufSize)
+	notificationLis := bufconn.Listen(bufSize)
+
+	h.Users = dialBuf(t, userLis, client.NewUserClient)
+	h.Risk = dialBuf(t, riskLis, client.NewRiskClient)
+	h.Notifications = dialBuf(t, notificationLis, client.NewNotificationClient)
+
+	// Risk engine
+	riskRepo := risk_repository.NewRiskRepository(h.RiskDB)
+	h.RiskEngine = services.NewRiskEngine(riskRepo, cfg.Settings(), cfg.RiskFlagFormat, log)
+	analytics := services.NewRiskAnalytics(h.RiskDB, nil, cfg.Settings(), log)
+	riskHandler := risk_handlers.NewRiskHandler(h.RiskEngine, analytics, services.NewAnalyticsRetryQueue(analytics, log), log)
+	riskAdminHandler := risk_handlers.NewRiskAdminHandler(riskRepo, log, h.RiskEngine, cfg.Settings(), analytics)
+	riskAdminHandler.EnableRecheck(h.Users, riskHandler)
+
+	protectedMethods := make(map[string][]auth.UserRole)
+	for _, method := range pb_risk.RiskAdminService_ServiceDesc.Methods {
+		protectedMethods["/"+pb_risk.RiskAdminService_ServiceDesc.ServiceName+"/"+method.MethodName] = []auth.UserRole{auth.RoleAdmin}
+	}
+	protectedMethods["/risk.RiskService/CheckRisk"] = auth.AllRoles
+	riskServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), authMiddleware.GRPCProtectMethods(protectedMethods)),
+		grpc.ChainStreamInterceptor(scontext.StreamServerInterceptor(), authMiddleware.GRPCProtectStreams(map[string][]auth.UserRole{
+			"/risk.RiskService/StreamCheckRisk": auth.AllRoles,
+		})),
+	)
+	pb_risk.RegisterRiskServiceServer(riskServer, riskHandler)
+	pb_risk.RegisterRiskAdminServiceServer(riskServer, riskAdminHandler)
+	serve(t, riskServer, riskLis)
+	t.Cleanup(riskHandler.WaitForPendingResults)
+
+	// Notification service
+	notificationHandler := notification_handlers.NewNotificationHandler(h.Messaging, h.Users, h.Risk.Admin, cfg, templates.NewEmailTemplateManager("", templates.BaseDataFromConfig(cfg)), log)
+	notificationHandler.EnableTimeline(notification_repository.NewEventRepository(h.NotificationDB))
+	notificationHandler.EnableStatusTracking(notification_repository.NewNotificationRepository(h.NotificationDB))
+	notificationHandler.StartMessageConsumer(ctx)
+	notificationServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), authMiddleware.GRPCProtectMethods(map[string][]auth.UserRole{
+			"/notification.NotificationService/BroadcastNotification": {auth.RoleAdmin},
+			"/notification.NotificationService/RecordDeliveryEvents":  {auth.RoleService},
+		})),
+	)
+	pb_notification.RegisterNotificationServiceServer(notificationServer, notificationHandler)
+	serve(t, notificationServer, notificationLis)
+
+	// User service
+	userHandler := user_handlers.NewUserHandler(
+		user_repository.NewUserRepository(h.UserDB),
+		h.Risk,
+		h.Notifications,
+		h.Messaging,
+		user_handlers.SessionPolicy{TTL: cfg.SessionTTL},
+		user_handlers.PasswordPolicy{HistoryDepth: cfg.PasswordHistoryDepth},
+		cfg.DefaultUserRole,
+		log,
+	)
+	userHandler.EnableServiceAuth(h.jwt)
+	userServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(scontext.UnaryServerInterceptor(), authMiddleware.GRPCUnaryInterceptor),
+	)
+	pb_user.RegisterUserServiceServer(userServer, userHandler)
+	serve(t, userServer, userLis)
+
+	return h
+}
+
+// dialBuf connects a client of the harness to lis, closed when t ends.
+func dialBuf[C interface{ Close() error }](t testing.TB, lis *bufconn.Listener, connect func(string, client.Config) (C, error)) C {
+	t.Helper()
+
+	c, err := connect("bufnet", client.Config{
+		MaxRetries: -1,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// serve runs s on lis until t ends.
+func serve(t testing.TB, s *grpc.Server, lis *bufconn.Listener) {
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+}
+
+// Token returns a JWT for user, as issued at login.
+func (h *Harness) Token(t testing.TB, user *user_models.User) string {
+	t.Helper()
+
+	token, err := h.jwt.GenerateSessionToken(user.ID, user.Email, auth.OrgOrDefault(user.OrgID), user.Roles, "")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
+
+// AuthContext returns ctx authenticated as user, its token is forwarded on every harness call.
+func (h *Harness) AuthContext(t testing.TB, ctx context.Context, user *user_models.User) context.Context {
+	t.Helper()
+
+	claims, err := h.jwt.ValidateToken(h.Token(t, user))
+	if err != nil {
+		t.Fatalf("failed to validate token: %v", err)
+	}
+	return auth.ContextWithClaims(ctx, claims, h.Token(t, user))
+}
+
+// ServiceContext returns ctx authenticated as another service of the system, as the gateway forwards provider webhooks.
+func (h *Harness) ServiceContext(t testing.TB, ctx context.Context) context.Context {
+	t.Helper()
+	ctx, err := h.jwt.ServiceContext(ctx, "testutil")
+	if err != nil {
+		t.Fatalf("failed to create service context: %v", err)
+	}
+	return ctx
+}
+
+// AdminContext returns ctx authenticated as a newly seeded admin of the default organization.
+func (h *Harness) AdminContext(t testing.TB, ctx context.Context) context.Context {
+	t.Helper()
+	return h.AuthContext(t, ctx, h.SeedUser(t, "admin-"+randomSuffix()+"@example.com", "adminpass123", string(auth.RoleAdmin)))
+}
+
+// WaitForMessages waits until at least n payloads were published on queue and returns them, failing t otherwise.
+func (h *Harness) WaitForMessages(t testing.TB, queue string, n int) []json.RawMessage {
+	t.Helper()
+
+	var messages []json.RawMessage
+	Eventually(t, func() bool {
+		messages = h.Broker.Published(queue)
+		return len(messages) >= n
+	}, "%d messages on %s", n, queue)
+	return messages
+}
+
+// Eventually polls condition until it holds, failing t with the formatted description after WaitTimeout.
+func Eventually(t testing.TB, condition func() bool, format string, args ...interface{}) {
+	t.Helper()
+
+	deadline := time.Now().Add(WaitTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for "+format, args...)
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,92 @@
+package testutil_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/auth"
+	events "user-risk-system/pkg/models"
+	"user-risk-system/pkg/testutil"
+	pb_user "user-risk-system/proto/user"
+)
+
+func TestRegisterRunsRiskCheck(t *testing.T) {
+	h := testutil.New(t)
+	h.SeedRule(t, risk_models.RiskRule{OrgID: auth.DefaultOrgID, Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 50})
+
+	tests := []struct {
+		name      string
+		email     string
+		wantRisky bool
+	}{
+		{"clean email", "clean@example.com", false},
+		{"blocked domain", "someone@blocked.example", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.Users.Register(context.Background(), &pb_user.RegisterRequest{
+				Email:     tt.email,
+				Password:  "correct-horse-battery",
+				FirstName: "Test",
+				LastName:  "User",
+			})
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			userID := resp.User.Id
+
+			var result risk_models.RiskCheckResult
+			testutil.Eventually(t, func() bool {
+				return h.RiskDB.Where("user_id = ?", userID).Limit(1).Find(&result).RowsAffected == 1
+			}, "no risk check stored for registered user %s", userID)
+			if result.OrgID != auth.DefaultOrgID {
+				t.Errorf("risk check org = %q, want %q", result.OrgID, auth.DefaultOrgID)
+			}
+			if result.IsRisky != tt.wantRisky {
+				t.Errorf("risk check risky = %v, want %v", result.IsRisky, tt.wantRisky)
+			}
+		})
+	}
+
+	created := map[string]bool{}
+	for _, payload := range h.WaitForMessages(t, events.EventUserCreated, len(tests)) {
+		var event events.UserCreatedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("malformed user.created payload: %v", err)
+		}
+		created[event.Email] = true
+	}
+	for _, tt := range tests {
+		if !created[tt.email] {
+			t.Errorf("no user.created event for %s", tt.email)
+		}
+	}
+}
+
+func TestRiskAlertStoresEngineCheckID(t *testing.T) {
+	h := testutil.New(t)
+	h.SeedRule(t, risk_models.RiskRule{OrgID: auth.DefaultOrgID, Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 50})
+
+	resp, err := h.Users.Register(context.Background(), &pb_user.RegisterRequest{
+		Email:     "someone@blocked.example",
+		Password:  "correct-horse-battery",
+		FirstName: "Test",
+		LastName:  "User",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	userID := resp.User.Id
+	check := h.WaitForRiskChecks(t, userID, 1)[0]
+
+	var alert notification_models.NotificationRecord
+	testutil.Eventually(t, func() bool {
+		return h.NotificationDB.Where("user_id = ? AND type = ?", userID, notification_models.NotificationTypeRiskDetected).Limit(1).Find(&alert).RowsAffected == 1
+	}, "no risk alert stored for user %s", userID)
+	if alert.CheckID == "" || alert.CheckID != check.CheckID {
+		t.Errorf("stored alert check ID = %q, want the engine's check %q", alert.CheckID, check.CheckID)
+	}
+}
@@ -0,0 +1,143 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/migrate"
+
+	"gorm.io/gorm"
+)
+
+// CheckMigrations runs migrations up, down and up again on a throwaway SQLite database, failing t
+// unless down removes every table and up creates the schema autoMigrate creates from the models.
+func CheckMigrations(t *testing.T, migrations []migrate.Migration, autoMigrate func(*gorm.DB) error) {
+	t.Helper()
+
+	ctx := context.Background()
+	db := NewSQLiteDB(t, nil)
+	migrator := migrate.New(db, "test", migrations, logger.New(logger.LogConfig{Level: "error", Output: io.Discard}))
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating up: %v", err)
+	}
+	migrated := SQLiteSchema(t, db)
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, err := migrator.Down(ctx, 1); err != nil {
+			t.Fatalf("migrating down: %v", err)
+		}
+		if i == 0 {
+			break
+		}
+		// Each Down must restore exactly the schema of the migrations before it
+		want := SQLiteSchema(t, upTo(t, migrations[:i]))
+		if got := SQLiteSchema(t, db); !slices.Equal(got, want) {
+			t.Errorf("schema after reverting %s differs from applying up to %s:\n%s", migrations[i].ID, migrations[i-1].ID, schemaDiff(got, want))
+		}
+	}
+	if left := SQLiteSchema(t, db); len(left) != 0 {
+		t.Errorf("tables left after migrating down:\n%s", strings.Join(left, "\n"))
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating up again: %v", err)
+	}
+	if again := SQLiteSchema(t, db); !slices.Equal(again, migrated) {
+		t.Errorf("schema differs after migrating up again:\n%s", schemaDiff(again, migrated))
+	}
+
+	if want := SQLiteSchema(t, NewSQLiteDB(t, autoMigrate)); !slices.Equal(migrated, want) {
+		t.Errorf("migrated schema differs from the models:\n%s", schemaDiff(migrated, want))
+	}
+}
+
+// upTo returns a new database with migrations applied.
+func upTo(t *testing.T, migrations []migrate.Migration) *gorm.DB {
+	t.Helper()
+
+	db := NewSQLiteDB(t, nil)
+	if _, err := migrate.New(db, "test", migrations, logger.New(logger.LogConfig{Level: "error", Output: io.Discard})).Up(context.Background()); err != nil {
+		t.Fatalf("migrating up: %v", err)
+	}
+	return db
+}
+
+// SQLiteSchema describes the columns, indexes and foreign keys of every table of db except schema_migrations,
+// one sorted line each so schemas can be compared regardless of column order.
+func SQLiteSchema(t testing.TB, db *gorm.DB) []string {
+	t.Helper()
+
+	var tables []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')").Scan(&tables).Error; err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+
+	var schema []string
+	for _, table := range tables {
+		var columns []struct {
+			Name      string
+			Type      string
+			NotNull   bool
+			DfltValue *string
+			Pk        int
+		}
+		db.Raw(fmt.Sprintf("PRAGMA table_info(%q)", table)).Scan(&columns)
+		for _, c := range columns {
+			dflt := "<none>"
+			if c.DfltValue != nil {
+				dflt = *c.DfltValue
+			}
+			schema = append(schema, fmt.Sprintf("%s column %s %s not_null=%t default=%s pk=%d", table, c.Name, c.Type, c.NotNull, dflt, c.Pk))
+		}
+
+		var indexes []struct {
+			Name   string
+			Unique bool
+			Origin string
+		}
+		db.Raw(fmt.Sprintf("PRAGMA index_list(%q)", table)).Scan(&indexes)
+		for _, index := range indexes {
+			var indexColumns []string
+			db.Raw(fmt.Sprintf("SELECT name FROM pragma_index_info(%q) ORDER BY seqno", index.Name)).Scan(&indexColumns)
+			name := index.Name
+			if index.Origin == "pk" || strings.HasPrefix(name, "sqlite_autoindex_") {
+				name = "<auto>"
+			}
+			schema = append(schema, fmt.Sprintf("%s index %s unique=%t (%s)", table, name, index.Unique, strings.Join(indexColumns, ", ")))
+		}
+
+		var keys []struct {
+			Table string
+			From  string
+			To    string
+		}
+		db.Raw(fmt.Sprintf("PRAGMA foreign_key_list(%q)", table)).Scan(&keys)
+		for _, key := range keys {
+			schema = append(schema, fmt.Sprintf("%s foreign_key %s -> %s(%s)", table, key.From, key.Table, key.To))
+		}
+	}
+	slices.Sort(schema)
+	return schema
+}
+
+// schemaDiff lists the lines only got or only want have.
+func schemaDiff(got, want []string) string {
+	var diff []string
+	for _, line := range got {
+		if !slices.Contains(want, line) {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	for _, line := range want {
+		if !slices.Contains(got, line) {
+			diff = append(diff, "- "+line)
+		}
+	}
+	return strings.Join(diff, "\n")
+}
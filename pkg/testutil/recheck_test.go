@@ -0,0 +1,67 @@
+package testutil_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	user_models "user-risk-system/cmd/user/models"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+)
+
+func TestRecheckUsersChecksOnlyTheCohort(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb_risk.RecheckUsersRequest
+		want []string
+	}{
+		{"email domain", &pb_risk.RecheckUsersRequest{EmailDomain: "cohort.example"}, []string{"analyst@cohort.example", "member@cohort.example"}},
+		{"email domain and role", &pb_risk.RecheckUsersRequest{EmailDomain: "Cohort.Example", Role: "analyst"}, []string{"analyst@cohort.example"}},
+		{"inactive users included", &pb_risk.RecheckUsersRequest{EmailDomain: "cohort.example", IncludeInactive: true}, []string{"analyst@cohort.example", "member@cohort.example", "suspended@cohort.example"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := testutil.New(t)
+			// Every seeded domain is blocked, so each rechecked user comes back newly risky and none is missed
+			for _, domain := range []string{"cohort.example", "other.example", "example.com"} {
+				h.SeedRule(t, risk_models.RiskRule{Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: domain, Score: 90})
+			}
+			emails := map[string]string{}
+			for _, user := range []*user_models.User{
+				h.SeedUser(t, "member@cohort.example", "password123"),
+				h.SeedUser(t, "analyst@cohort.example", "password123", "analyst"),
+				h.SeedUser(t, "analyst@other.example", "password123", "analyst"),
+				h.SeedUser(t, "suspended@cohort.example", "password123"),
+			} {
+				emails[user.ID] = user.Email
+				if user.Email == "suspended@cohort.example" {
+					user.SetAccountStatus(user_models.AccountStatusSuspendedRisk)
+					if err := h.UserDB.Model(user).Select("account_status", "is_active").Updates(user).Error; err != nil {
+						t.Fatalf("failed to suspend user: %v", err)
+					}
+				}
+			}
+			ctx := h.AdminContext(t, context.Background())
+
+			resp, err := h.Risk.Admin.RecheckUsers(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("RecheckUsers() error = %v", err)
+			}
+			if !resp.Done || resp.Checked != int32(len(tt.want)) || resp.Failed != 0 {
+				t.Errorf("RecheckUsers() = done %v, %d checked, %d failed, want all %d of the cohort checked", resp.Done, resp.Checked, resp.Failed, len(tt.want))
+			}
+
+			got := []string{}
+			for _, id := range resp.NewlyRiskyUserIds {
+				got = append(got, emails[id])
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rechecked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
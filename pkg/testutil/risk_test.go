@@ -0,0 +1,112 @@
+package testutil_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckRiskUsesCallerOrganization(t *testing.T) {
+	h := testutil.New(t)
+	h.SeedRule(t, risk_models.RiskRule{OrgID: "acme", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 90})
+	user := h.SeedUser(t, "user@example.com", "password123")
+
+	check := func(orgID string) *pb_risk.RiskCheckRequest {
+		return &pb_risk.RiskCheckRequest{
+			UserId:    "checked-user",
+			Email:     "someone@blocked.example",
+			FirstName: "Some",
+			LastName:  "One",
+			OrgId:     orgID,
+			DryRun:    true,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		orgID     string
+		want      codes.Code
+		wantRisky bool
+	}{
+		{"anonymous", context.Background(), "acme", codes.Unauthenticated, false},
+		{"user naming another organization", h.AuthContext(t, context.Background(), user), "acme", codes.PermissionDenied, false},
+		{"user in own organization", h.AuthContext(t, context.Background(), user), "", codes.OK, false},
+		{"service naming the user's organization", h.ServiceContext(t, context.Background()), "acme", codes.OK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.Risk.CheckRisk(tt.ctx, check(tt.orgID))
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("CheckRisk() code = %v, want %v (%v)", got, tt.want, err)
+			}
+			if err == nil && resp.IsRisky != tt.wantRisky {
+				t.Errorf("CheckRisk() risky = %v, want %v", resp.IsRisky, tt.wantRisky)
+			}
+		})
+	}
+}
+
+func TestStreamCheckRiskRequiresToken(t *testing.T) {
+	h := testutil.New(t)
+
+	stream, err := h.Risk.StreamCheckRisk(context.Background())
+	if err != nil {
+		t.Fatalf("StreamCheckRisk() error = %v", err)
+	}
+	_ = stream.Send(&pb_risk.RiskCheckRequest{UserId: "u", Email: "u@example.com", FirstName: "U", LastName: "U"})
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Recv() error = %v, want Unauthenticated", err)
+	}
+}
+
+func TestStreamCheckRiskAnswersEachRequest(t *testing.T) {
+	h := testutil.New(t)
+	h.SeedRule(t, risk_models.RiskRule{OrgID: "acme", Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 90})
+
+	requests := []*pb_risk.RiskCheckRequest{
+		{UserId: "stream-1", Email: "one@example.com", FirstName: "One", LastName: "User", OrgId: "acme"},
+		{UserId: "stream-2", Email: "two@blocked.example", FirstName: "Two", LastName: "User", OrgId: "acme"},
+		{UserId: "stream-3", Email: "three@example.com", FirstName: "Three", LastName: "User", OrgId: "acme"},
+	}
+
+	stream, err := h.Risk.StreamCheckRisk(h.ServiceContext(t, context.Background()))
+	if err != nil {
+		t.Fatalf("StreamCheckRisk() error = %v", err)
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+
+	for _, req := range requests {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		if resp.UserId != req.UserId {
+			t.Fatalf("response for %s, want %s in request order", resp.UserId, req.UserId)
+		}
+		if wantRisky := req.UserId == "stream-2"; resp.IsRisky != wantRisky {
+			t.Errorf("%s risky = %v, want %v", req.UserId, resp.IsRisky, wantRisky)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv() after the last response = %v, want io.EOF", err)
+	}
+
+	for _, req := range requests {
+		h.WaitForRiskChecks(t, req.UserId, 1)
+	}
+}
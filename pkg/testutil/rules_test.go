@@ -0,0 +1,287 @@
+package testutil_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	"user-risk-system/pkg/testutil"
+	pb_risk "user-risk-system/proto/risk"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateRiskRuleRejectsInvalidPattern(t *testing.T) {
+	h := testutil.New(t)
+	ctx := h.AdminContext(t, context.Background())
+
+	tests := []struct {
+		name     string
+		ruleType string
+		value    string
+		want     codes.Code
+	}{
+		{"valid pattern", "PATTERN_MATCH", `^test\d+@`, codes.OK},
+		{"invalid pattern", "PATTERN_MATCH", `[a-z`, codes.InvalidArgument},
+		{"brackets in a plain value", "CONTAINS", `[a-z`, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pb_risk.CreateRiskRuleRequest{Name: tt.name, Type: tt.ruleType, Category: "EMAIL", Value: tt.value, Score: 50, IsActive: true}
+			resp, err := h.Risk.Admin.CreateRiskRule(ctx, req)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("CreateRiskRule() code = %v, want %v (%v)", got, tt.want, err)
+			}
+			if err != nil || tt.ruleType != "PATTERN_MATCH" {
+				return
+			}
+
+			update := &pb_risk.UpdateRiskRuleRequest{RuleId: resp.RuleId, Name: tt.name, Type: "PATTERN_MATCH", Category: "EMAIL", Value: `(unclosed`, Score: 50, IsActive: true}
+			if _, err := h.Risk.Admin.UpdateRiskRule(ctx, update); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("UpdateRiskRule() with an invalid pattern error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestRiskRuleExpiresInDaysBounds(t *testing.T) {
+	t.Setenv("RULE_MAX_EXPIRES_IN_DAYS", "30")
+	h := testutil.New(t)
+	ctx := h.AdminContext(t, context.Background())
+
+	tests := []struct {
+		name       string
+		days       int32
+		want       codes.Code
+		wantExpiry bool
+	}{
+		{"negative", -1, codes.InvalidArgument, false},
+		{"zero is permanent", 0, codes.OK, false},
+		{"in range", 30, codes.OK, true},
+		{"over the maximum", 31, codes.InvalidArgument, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.Risk.Admin.CreateRiskRule(ctx, &pb_risk.CreateRiskRuleRequest{
+				Name: tt.name, Type: "CONTAINS", Category: "EMAIL", Value: "expiry", Score: 10, IsActive: true, ExpiresInDays: tt.days,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("CreateRiskRule() code = %v, want %v (%v)", got, tt.want, err)
+			}
+
+			// Updates are checked against the same bounds, on a rule created without expiry
+			ruleID := ""
+			if err == nil {
+				ruleID = resp.RuleId
+			} else {
+				created, err := h.Risk.Admin.CreateRiskRule(ctx, &pb_risk.CreateRiskRuleRequest{
+					Name: tt.name, Type: "CONTAINS", Category: "EMAIL", Value: "expiry", Score: 10, IsActive: true,
+				})
+				if err != nil {
+					t.Fatalf("CreateRiskRule() error = %v", err)
+				}
+				ruleID = created.RuleId
+			}
+			_, err = h.Risk.Admin.UpdateRiskRule(ctx, &pb_risk.UpdateRiskRuleRequest{
+				RuleId: ruleID, Name: tt.name, Type: "CONTAINS", Category: "EMAIL", Value: "expiry", Score: 10, IsActive: true, ExpiresInDays: tt.days,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("UpdateRiskRule() code = %v, want %v (%v)", got, tt.want, err)
+			}
+
+			var rule risk_models.RiskRule
+			if err := h.RiskDB.First(&rule, "id = ?", ruleID).Error; err != nil {
+				t.Fatalf("failed to load rule: %v", err)
+			}
+			if got := rule.ExpiresAt != nil; got != tt.wantExpiry {
+				t.Errorf("stored rule has expiry = %v, want %v", got, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestCreateRiskRulesBulk(t *testing.T) {
+	valid := func(name string) *pb_risk.CreateRiskRuleRequest {
+		return &pb_risk.CreateRiskRuleRequest{Name: name, Type: "CONTAINS", Category: "EMAIL", Value: name, Score: 10, IsActive: true}
+	}
+	invalid := &pb_risk.CreateRiskRuleRequest{Name: "bad pattern", Type: "PATTERN_MATCH", Category: "EMAIL", Value: `[a-z`, Score: 10, IsActive: true}
+
+	tests := []struct {
+		name        string
+		rules       []*pb_risk.CreateRiskRuleRequest
+		partial     bool
+		wantCode    codes.Code
+		wantSuccess []bool
+		wantStored  int64
+	}{
+		{"all valid", []*pb_risk.CreateRiskRuleRequest{valid("bulk-a"), valid("bulk-b")}, false, codes.OK, []bool{true, true}, 2},
+		{"one invalid rolls back", []*pb_risk.CreateRiskRuleRequest{valid("bulk-a"), invalid, valid("bulk-b")}, false, codes.InvalidArgument, nil, 0},
+		{"partial keeps the valid rules", []*pb_risk.CreateRiskRuleRequest{valid("bulk-a"), invalid, valid("bulk-b")}, true, codes.OK, []bool{true, false, true}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := testutil.New(t)
+			ctx := h.AdminContext(t, context.Background())
+
+			resp, err := h.Risk.Admin.CreateRiskRules(ctx, &pb_risk.CreateRiskRulesRequest{Rules: tt.rules, Partial: tt.partial})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("CreateRiskRules() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+			if err == nil {
+				if len(resp.Results) != len(tt.wantSuccess) {
+					t.Fatalf("got %d results, want %d", len(resp.Results), len(tt.wantSuccess))
+				}
+				for i, result := range resp.Results {
+					if result.Success != tt.wantSuccess[i] || (result.RuleId != "") != tt.wantSuccess[i] || (result.Error == "") != tt.wantSuccess[i] {
+						t.Errorf("results[%d] = success %v, rule ID %q, error %q, want success %v", i, result.Success, result.RuleId, result.Error, tt.wantSuccess[i])
+					}
+				}
+				if int64(resp.Created) != tt.wantStored {
+					t.Errorf("created = %d, want %d", resp.Created, tt.wantStored)
+				}
+			}
+
+			var stored int64
+			if err := h.RiskDB.Model(&risk_models.RiskRule{}).Where("name LIKE ?", "bulk-%").Count(&stored).Error; err != nil {
+				t.Fatalf("failed to count rules: %v", err)
+			}
+			if stored != tt.wantStored {
+				t.Errorf("stored %d rules, want %d", stored, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestCreateRiskRuleChecksExamples(t *testing.T) {
+	h := testutil.New(t)
+	ctx := h.AdminContext(t, context.Background())
+
+	tests := []struct {
+		name           string
+		exampleMatch   string
+		exampleNoMatch string
+		want           codes.Code
+	}{
+		{"no examples", "", "", codes.OK},
+		{"matching examples", "test42@example.com", "tester@example.com", codes.OK},
+		{"match example not matched", "tester@example.com", "", codes.InvalidArgument},
+		{"no match example matched", "", "test42@example.com", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pb_risk.CreateRiskRuleRequest{
+				Name: tt.name, Type: "PATTERN_MATCH", Category: "EMAIL", Value: `^test\d+@`, Score: 50, IsActive: true,
+				ExampleMatch: tt.exampleMatch, ExampleNoMatch: tt.exampleNoMatch,
+			}
+			if _, err := h.Risk.Admin.CreateRiskRule(ctx, req); status.Code(err) != tt.want {
+				t.Fatalf("CreateRiskRule() code = %v, want %v (%v)", status.Code(err), tt.want, err)
+			}
+
+			update := &pb_risk.UpdateRiskRuleRequest{
+				RuleId: h.SeedRule(t, risk_models.RiskRule{Name: tt.name, Type: "CONTAINS", Category: "EMAIL", Value: "seed", Score: 10, IsActive: true}).ID,
+				Name:   tt.name, Type: "PATTERN_MATCH", Category: "EMAIL", Value: `^test\d+@`, Score: 50, IsActive: true,
+				ExampleMatch: tt.exampleMatch, ExampleNoMatch: tt.exampleNoMatch,
+			}
+			if _, err := h.Risk.Admin.UpdateRiskRule(ctx, update); status.Code(err) != tt.want {
+				t.Errorf("UpdateRiskRule() code = %v, want %v (%v)", status.Code(err), tt.want, err)
+			}
+		})
+	}
+}
+
+func TestInvalidateCacheReloadsRules(t *testing.T) {
+	t.Setenv("RULE_CACHE_TTL", "1h")
+	h := testutil.New(t)
+	ctx := h.AdminContext(t, context.Background())
+	flags := func() int {
+		t.Helper()
+		resp, err := h.Risk.CheckRisk(h.ServiceContext(t, context.Background()), &pb_risk.RiskCheckRequest{
+			UserId: "checked-user", Email: "someone@blocked.example", FirstName: "Some", LastName: "One", DryRun: true,
+		})
+		if err != nil {
+			t.Fatalf("CheckRisk() error = %v", err)
+		}
+		return len(resp.Flags)
+	}
+
+	h.SeedRule(t, risk_models.RiskRule{Category: "EMAIL", Type: "DOMAIN_BLACKLIST", Value: "blocked.example", Score: 20})
+	if got := flags(); got != 1 {
+		t.Fatalf("%d flags, want the seeded rule's", got)
+	}
+
+	// Stored behind the engine's back, as another replica of the admin service would
+	added := risk_models.RiskRule{ID: "added", OrgID: "default", Name: "Contains someone", Category: "EMAIL", Type: "CONTAINS", Value: "someone", Score: 30, Confidence: 1, IsActive: true}
+	if err := h.RiskDB.Create(&added).Error; err != nil {
+		t.Fatalf("failed to store rule: %v", err)
+	}
+	if got := flags(); got != 1 {
+		t.Fatalf("%d flags within the cache TTL, want the cached rule's only", got)
+	}
+
+	if _, err := h.Risk.Admin.InvalidateCache(ctx, &pb_risk.InvalidateCacheRequest{}); err != nil {
+		t.Fatalf("InvalidateCache() error = %v", err)
+	}
+	if got := flags(); got != 2 {
+		t.Errorf("%d flags after invalidating the cache, want both rules'", got)
+	}
+	stats, err := h.Risk.Admin.GetCacheStats(ctx, &pb_risk.GetCacheStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetCacheStats() error = %v", err)
+	}
+	if stats.RuleCounts["EMAIL"] != 2 {
+		t.Errorf("cached EMAIL rules = %d, want both rules", stats.RuleCounts["EMAIL"])
+	}
+}
+
+func TestRiskRuleHandlersRejectInvalidRules(t *testing.T) {
+	h := testutil.New(t)
+	ctx := h.AdminContext(t, context.Background())
+	seeded := h.SeedRule(t, risk_models.RiskRule{Category: "EMAIL", Type: "CONTAINS", Value: "seed", Score: 10})
+
+	tests := []struct {
+		name      string
+		ruleType  string
+		category  string
+		value     string
+		score     int32
+		wantField string
+	}{
+		{"bad regex", "PATTERN_MATCH", "EMAIL", `(unclosed`, 50, "value"},
+		{"unknown type", "SOUNDS_LIKE", "EMAIL", "paypal", 50, "type"},
+		{"type of another category", "PREFIX_BLACKLIST", "EMAIL", "+1555", 50, "type"},
+		{"unknown category", "CONTAINS", "ADDRESS", "street", 50, "category"},
+		{"score below range", "CONTAINS", "EMAIL", "test", 0, "score"},
+		{"score above range", "CONTAINS", "EMAIL", "test", 1001, "score"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Risk.Admin.CreateRiskRule(ctx, &pb_risk.CreateRiskRuleRequest{
+				Name: tt.name, Type: tt.ruleType, Category: tt.category, Value: tt.value, Score: tt.score, IsActive: true,
+			})
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), tt.wantField+":") {
+				t.Errorf("CreateRiskRule() error = %v, want InvalidArgument on %s", err, tt.wantField)
+			}
+
+			_, err = h.Risk.Admin.UpdateRiskRule(ctx, &pb_risk.UpdateRiskRuleRequest{
+				RuleId: seeded.ID, Name: tt.name, Type: tt.ruleType, Category: tt.category, Value: tt.value, Score: tt.score, IsActive: true,
+			})
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), tt.wantField+":") {
+				t.Errorf("UpdateRiskRule() error = %v, want InvalidArgument on %s", err, tt.wantField)
+			}
+		})
+	}
+
+	var stored int64
+	if err := h.RiskDB.Model(&risk_models.RiskRule{}).Count(&stored).Error; err != nil {
+		t.Fatalf("failed to count rules: %v", err)
+	}
+	var rule risk_models.RiskRule
+	if err := h.RiskDB.First(&rule, "id = ?", seeded.ID).Error; err != nil {
+		t.Fatalf("failed to load rule: %v", err)
+	}
+	if stored != 1 || rule.Type != "CONTAINS" || rule.Value != "seed" {
+		t.Errorf("stored %d rules with the seeded one now %s %q, want only the unchanged seeded rule", stored, rule.Type, rule.Value)
+	}
+}
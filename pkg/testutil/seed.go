@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	risk_repository "user-risk-system/cmd/risk-engine/repository"
+	user_models "user-risk-system/cmd/user/models"
+	user_repository "user-risk-system/cmd/user/repository"
+	"user-risk-system/pkg/auth"
+
+	"github.com/google/uuid"
+)
+
+// SeedUser stores an active user of the default organization with password and roles, user when none are given.
+func (h *Harness) SeedUser(t testing.TB, email, password string, roles ...string) *user_models.User {
+	t.Helper()
+
+	if len(roles) == 0 {
+		roles = []string{string(auth.RoleUser)}
+	}
+	user := &user_models.User{
+		Email:     email,
+		OrgID:     auth.DefaultOrgID,
+		FirstName: "Test",
+		LastName:  "User",
+		Roles:     roles,
+		Locale:    "en",
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if err := user.SetPassword(password); err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	if err := user_repository.NewUserRepository(h.UserDB).Create(user); err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	return user
+}
+
+// SeedRule stores an active risk rule and drops the engine's cached rules of its organization.
+// unset fields default to a new ID, the default organization, a confidence of 1 and a name derived from the value.
+func (h *Harness) SeedRule(t testing.TB, rule risk_models.RiskRule) *risk_models.RiskRule {
+	t.Helper()
+
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	if rule.OrgID == "" {
+		rule.OrgID = auth.DefaultOrgID
+	}
+	if rule.Name == "" {
+		rule.Name = rule.Type + " " + rule.Value
+	}
+	if rule.Confidence == 0 {
+		rule.Confidence = 1
+	}
+	rule.IsActive = true
+	if err := risk_repository.NewRiskRepository(h.RiskDB).CreateRule(&rule); err != nil {
+		t.Fatalf("failed to seed rule: %v", err)
+	}
+	h.RiskEngine.InvalidateCache(rule.OrgID)
+	return &rule
+}
+
+// WaitForRiskChecks waits until at least n risk checks of userID are stored and returns them, newest first.
+func (h *Harness) WaitForRiskChecks(t testing.TB, userID string, n int) []risk_models.RiskCheckResult {
+	t.Helper()
+
+	var results []risk_models.RiskCheckResult
+	Eventually(t, func() bool {
+		results = nil
+		err := h.RiskDB.Where("user_id = ?", userID).Preload("Flags").Order("checked_at DESC").Find(&results).Error
+		return err == nil && len(results) >= n
+	}, "%d risk checks of user %s", n, userID)
+	return results
+}
+
+// randomSuffix returns a short unique string for seeded names.
+func randomSuffix() string {
+	return uuid.New().String()[:8]
+}
@@ -1,22 +1,30 @@
 package utils
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"user-risk-system/pkg/config"
 	"user-risk-system/pkg/logger"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// setupDatabase initializes the PostgreSQL database connection with optimal settings.
-// configures the connection pool, tests connectivity, and runs auto-migration for risk models.
+// SetupDatabase opens the database at databaseURL, tests connectivity and configures the connection pool.
+// postgres:// URLs and key/value DSNs use PostgreSQL. sqlite://<path>, :memory: and file: DSNs use SQLite
+// for local runs and tests, they keep a single connection as SQLite allows one writer at a time and
+// every connection to :memory: would open its own empty database.
 func SetupDatabase(
 	databaseURL string,
 	gormConfig *gorm.Config,
 	appConfig *config.Config,
 	logger *logger.Logger,
 ) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(databaseURL), gormConfig)
+	dialector, isSQLite := dialectorFor(databaseURL)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -27,10 +35,14 @@ func SetupDatabase(
 		return nil, err
 	}
 
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(appConfig.DatabaseMaxIdleConn)
-	sqlDB.SetMaxOpenConns(appConfig.DatabaseMaxConns)
-	sqlDB.SetConnMaxLifetime(appConfig.DatabaseConnLiftime)
+	if isSQLite {
+		sqlDB.SetMaxOpenConns(1)
+		logger.Warn("Using SQLite database, intended for local development and tests only")
+	} else {
+		sqlDB.SetMaxIdleConns(appConfig.DatabaseMaxIdleConn)
+		sqlDB.SetMaxOpenConns(appConfig.DatabaseMaxConns)
+		sqlDB.SetConnMaxLifetime(appConfig.DatabaseConnLiftime)
+	}
 
 	// Test the connection
 	if err := sqlDB.Ping(); err != nil {
@@ -39,3 +51,40 @@ func SetupDatabase(
 
 	return db, nil
 }
+
+// dialectorFor picks the gorm driver for databaseURL by its scheme, reporting whether it is SQLite.
+func dialectorFor(databaseURL string) (gorm.Dialector, bool) {
+	if !config.IsSQLiteDSN(databaseURL) {
+		return postgres.Open(databaseURL), false
+	}
+	return sqlite.Open(strings.TrimPrefix(databaseURL, "sqlite://")), true
+}
+
+// WithTransaction runs fn inside a database transaction bound to ctx.
+// commits when fn returns nil and rolls back when it returns an error or panics;
+// a panic is re-raised after the rollback.
+func WithTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) (err error) {
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback().Error; rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	notification_models "user-risk-system/cmd/notification/models"
+	risk_models "user-risk-system/cmd/risk-engine/models"
+	user_models "user-risk-system/cmd/user/models"
+	user_repository "user-risk-system/cmd/user/repository"
+	"user-risk-system/pkg/config"
+	"user-risk-system/pkg/logger"
+	"user-risk-system/pkg/testutil"
+	"user-risk-system/pkg/utils"
+)
+
+// entry is a row written inside the transactions under test.
+type entry struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func newEntryDB(t *testing.T) *gorm.DB {
+	return testutil.NewSQLiteDB(t, func(db *gorm.DB) error { return db.AutoMigrate(&entry{}) })
+}
+
+func countEntries(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&entry{}).Count(&count).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return count
+}
+
+func TestWithTransactionCommitsOnSuccess(t *testing.T) {
+	db := newEntryDB(t)
+
+	err := utils.WithTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		if err := tx.Create(&entry{Name: "first"}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entry{Name: "second"}).Error
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+	if got := countEntries(t, db); got != 2 {
+		t.Errorf("entries = %d, want both committed", got)
+	}
+}
+
+func TestWithTransactionRollsBackOnError(t *testing.T) {
+	db := newEntryDB(t)
+	errFailed := errors.New("second write failed")
+
+	err := utils.WithTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		if err := tx.Create(&entry{Name: "first"}).Error; err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("WithTransaction() error = %v, want the error of fn", err)
+	}
+	if got := countEntries(t, db); got != 0 {
+		t.Errorf("entries = %d, want the first write rolled back", got)
+	}
+}
+
+func TestWithTransactionRollsBackAndRepanics(t *testing.T) {
+	db := newEntryDB(t)
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the panic of fn", p)
+			}
+		}()
+		utils.WithTransaction(context.Background(), db, func(tx *gorm.DB) error {
+			tx.Create(&entry{Name: "first"})
+			panic("boom")
+		})
+	}()
+
+	if got := countEntries(t, db); got != 0 {
+		t.Errorf("entries = %d, want the write rolled back after the panic", got)
+	}
+}
+
+func TestSetupDatabaseSQLiteMigratesAndStoresUsers(t *testing.T) {
+	log := logger.New(logger.LogConfig{Level: "error", Output: io.Discard})
+
+	for _, dsn := range []string{":memory:", "sqlite://:memory:", "sqlite://file:" + t.Name() + "?mode=memory"} {
+		t.Run(dsn, func(t *testing.T) {
+			db, err := utils.SetupDatabase(dsn, &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)}, &config.Config{}, log)
+			if err != nil {
+				t.Fatalf("SetupDatabase() error = %v", err)
+			}
+			sqlDB, _ := db.DB()
+			t.Cleanup(func() { sqlDB.Close() })
+			if db.Dialector.Name() != "sqlite" {
+				t.Fatalf("dialector = %s, want sqlite", db.Dialector.Name())
+			}
+
+			for service, migrate := range map[string]func(*gorm.DB) error{
+				"user":         user_models.AutoMigrate,
+				"risk":         risk_models.AutoMigrate,
+				"notification": notification_models.AutoMigrate,
+			} {
+				if err := migrate(db); err != nil {
+					t.Fatalf("%s AutoMigrate() error = %v", service, err)
+				}
+			}
+
+			repo := user_repository.NewUserRepository(db)
+			user := &user_models.User{Email: "sqlite@example.com", OrgID: "default", FirstName: "Sq", LastName: "Lite"}
+			if err := repo.Create(user); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			stored, err := repo.GetByEmail("sqlite@example.com")
+			if err != nil || stored.ID != user.ID {
+				t.Fatalf("GetByEmail() = %v, %v, want the created user", stored, err)
+			}
+
+			stored.FirstName = "Updated"
+			if err := repo.Update(stored); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if reloaded, err := repo.GetByID(user.ID); err != nil || reloaded.FirstName != "Updated" {
+				t.Fatalf("GetByID() = %v, %v, want the updated name", reloaded, err)
+			}
+
+			if err := repo.Delete(user.ID); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := repo.GetByID(user.ID); err == nil {
+				t.Error("GetByID() found the deleted user")
+			}
+		})
+	}
+}
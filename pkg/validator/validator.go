@@ -8,9 +8,23 @@ import (
 	"strings"
 )
 
+// Machine-readable validation error codes, stable for clients to branch on.
+const (
+	CodeRequired      = "REQUIRED"
+	CodeInvalidEmail  = "INVALID_EMAIL"
+	CodeInvalidDomain = "INVALID_DOMAIN"
+	CodeInvalidPhone  = "INVALID_PHONE"
+	CodeInvalidRegex  = "INVALID_REGEX"
+	CodeTooShort      = "TOO_SHORT"
+	CodeTooSmall      = "TOO_SMALL"
+	CodeTooLarge      = "TOO_LARGE"
+	CodeInvalidValue  = "INVALID_VALUE" // Constraints checked outside the validator, e.g. unknown enum values
+)
+
 // ValidationError represents a single validation failure for a specific field.
 type ValidationError struct {
 	Field   string `json:"field"`   // Name of the field that failed validation
+	Code    string `json:"code"`    // Machine-readable rule that failed, one of the Code constants
 	Message string `json:"message"` // Human-readable validation error message
 }
 
@@ -41,6 +55,7 @@ func (v *Validator) Required(field, value string) *Validator {
 	if strings.TrimSpace(value) == "" {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeRequired,
 			Message: "is required",
 		})
 	}
@@ -54,17 +69,33 @@ func (v *Validator) Email(field, value string) *Validator {
 	if value != "" && !emailRegex.MatchString(strings.ToLower(value)) {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeInvalidEmail,
 			Message: "must be a valid email address",
 		})
 	}
 	return v
 }
 
+// Domain validates that a string field contains a valid domain name such as example.com.
+// Skips validation if the value is empty. Returns the validator for method chaining.
+func (v *Validator) Domain(field, value string) *Validator {
+	domainRegex := regexp.MustCompile(`^([a-z0-9]([a-z0-9\-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+	if value != "" && !domainRegex.MatchString(strings.ToLower(value)) {
+		v.errors = append(v.errors, ValidationError{
+			Field:   field,
+			Code:    CodeInvalidDomain,
+			Message: "must be a valid domain name",
+		})
+	}
+	return v
+}
+
 // MinLength validates that a string field meets the minimum length requirement.
 func (v *Validator) MinLength(field, value string, length int) *Validator {
 	if len(value) < length {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeTooShort,
 			Message: fmt.Sprintf("must be at least %d characters", length),
 		})
 	}
@@ -77,17 +108,35 @@ func (v *Validator) Phone(field, value string) *Validator {
 	if value != "" && !phoneRegex.MatchString(value) {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeInvalidPhone,
 			Message: "must be a valid phone number",
 		})
 	}
 	return v
 }
 
+// Regex validates that a string field compiles as a regular expression.
+// Skips validation if the value is empty. Returns the validator for method chaining.
+func (v *Validator) Regex(field, value string) *Validator {
+	if value == "" {
+		return v
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		v.errors = append(v.errors, ValidationError{
+			Field:   field,
+			Code:    CodeInvalidRegex,
+			Message: fmt.Sprintf("must be a valid regular expression: %v", err),
+		})
+	}
+	return v
+}
+
 // Min validates that a numeric field meets the minimum value requirement.
 func (v *Validator) Min(field string, value float64, min float64) *Validator {
 	if value < min {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeTooSmall,
 			Message: fmt.Sprintf("must be greater than or equal to %.0f", min),
 		})
 	}
@@ -99,6 +148,7 @@ func (v *Validator) Max(field string, value float64, max float64) *Validator {
 	if value > max {
 		v.errors = append(v.errors, ValidationError{
 			Field:   field,
+			Code:    CodeTooLarge,
 			Message: fmt.Sprintf("must be less than or equal to %.0f", max),
 		})
 	}
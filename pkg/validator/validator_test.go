@@ -0,0 +1,79 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"user-risk-system/pkg/validator"
+)
+
+func TestRulesSetErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(*validator.Validator) *validator.Validator
+		code string
+	}{
+		{"Required", func(v *validator.Validator) *validator.Validator { return v.Required("f", "  ") }, validator.CodeRequired},
+		{"Email", func(v *validator.Validator) *validator.Validator { return v.Email("f", "not-an-email") }, validator.CodeInvalidEmail},
+		{"Domain", func(v *validator.Validator) *validator.Validator { return v.Domain("f", "no_tld") }, validator.CodeInvalidDomain},
+		{"MinLength", func(v *validator.Validator) *validator.Validator { return v.MinLength("f", "a", 2) }, validator.CodeTooShort},
+		{"Phone", func(v *validator.Validator) *validator.Validator { return v.Phone("f", "12-ab") }, validator.CodeInvalidPhone},
+		{"Min", func(v *validator.Validator) *validator.Validator { return v.Min("f", 0, 1) }, validator.CodeTooSmall},
+		{"Max", func(v *validator.Validator) *validator.Validator { return v.Max("f", 1001, 1000) }, validator.CodeTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.run(validator.New()).Errors()
+			if len(errs) != 1 {
+				t.Fatalf("errors = %v, want one", errs)
+			}
+			if errs[0].Field != "f" || errs[0].Code != tt.code || errs[0].Message == "" {
+				t.Errorf("error = %+v, want field f, code %s and a message", errs[0], tt.code)
+			}
+		})
+	}
+}
+
+func TestRulesAcceptValidValues(t *testing.T) {
+	v := validator.New().
+		Required("f", "value").
+		Email("f", "user@example.com").
+		Domain("f", "mail.example.com").
+		MinLength("f", "ab", 2).
+		Phone("f", "+14155550100").
+		Min("f", 1, 1).
+		Max("f", 1000, 1000).
+		// Optional formats skip empty values
+		Email("f", "").
+		Domain("f", "").
+		Phone("f", "")
+
+	if !v.IsValid() {
+		t.Errorf("errors = %v, want none", v.Errors())
+	}
+}
+
+func TestValidationErrorJSONCarriesCode(t *testing.T) {
+	body, err := json.Marshal(validator.New().Required("email", "").Errors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"field":"email","code":"REQUIRED","message":"is required"}]`; string(body) != want {
+		t.Errorf("JSON = %s, want %s", body, want)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	for _, pattern := range []string{"", `^test\d+@`, `(?i)temp(mail)?`} {
+		if errs := validator.New().Regex("value", pattern).Errors(); len(errs) != 0 {
+			t.Errorf("Regex(%q) errors = %v, want none", pattern, errs)
+		}
+	}
+	for _, pattern := range []string{`[a-z`, `(unclosed`, `*leading`, `a{2,1}`} {
+		errs := validator.New().Regex("value", pattern).Errors()
+		if len(errs) != 1 || errs[0].Code != validator.CodeInvalidRegex {
+			t.Errorf("Regex(%q) errors = %v, want one %s", pattern, errs, validator.CodeInvalidRegex)
+		}
+	}
+}
@@ -9,6 +9,7 @@ package risk
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,7 +28,9 @@ type RiskCheckRequest struct {
 	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
 	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	Phone         string                 `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"` // Optional: add phone support
+	Phone         string                 `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"`                  // Optional: add phone support
+	OrgId         string                 `protobuf:"bytes,6,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`     // Organization whose rules apply, empty is the default organization
+	DryRun        bool                   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Evaluate without storing the result for analytics
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,6 +100,20 @@ func (x *RiskCheckRequest) GetPhone() string {
 	return ""
 }
 
+func (x *RiskCheckRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *RiskCheckRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 type RiskCheckResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -104,6 +121,8 @@ type RiskCheckResponse struct {
 	RiskLevel     string                 `protobuf:"bytes,3,opt,name=risk_level,json=riskLevel,proto3" json:"risk_level,omitempty"` // LOW, MEDIUM, HIGH, CRITICAL
 	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	Flags         []string               `protobuf:"bytes,5,rep,name=flags,proto3" json:"flags,omitempty"`
+	DryRun        bool                   `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`   // The result was not stored
+	CheckId       string                 `protobuf:"bytes,7,opt,name=check_id,json=checkId,proto3" json:"check_id,omitempty"` // Identifies this evaluation, carried by the notifications it triggers
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -173,23 +192,40 @@ func (x *RiskCheckResponse) GetFlags() []string {
 	return nil
 }
 
+func (x *RiskCheckResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RiskCheckResponse) GetCheckId() string {
+	if x != nil {
+		return x.CheckId
+	}
+	return ""
+}
+
 // NEW: Admin API messages
 type RiskRule struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`         // EMAIL_BLACKLIST, NAME_BLACKLIST, PATTERN_MATCH, DOMAIN_BLACKLIST, CONTAINS
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"` // EMAIL, NAME, PHONE
-	Value         string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`       // The actual value or pattern
-	Score         int32                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`      // Risk score to add
-	IsActive      bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	Source        string                 `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`                          // MANUAL, EXTERNAL_API, ML_MODEL
-	Confidence    float64                `protobuf:"fixed64,9,opt,name=confidence,proto3" json:"confidence,omitempty"`                // 0.0 to 1.0
-	CreatedAt     int64                  `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
-	UpdatedAt     int64                  `protobuf:"varint,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix timestamp
-	ExpiresAt     int64                  `protobuf:"varint,12,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix timestamp (0 = never expires)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`         // EMAIL_BLACKLIST, NAME_BLACKLIST, PATTERN_MATCH, DOMAIN_BLACKLIST, CONTAINS, CONFUSABLE_MATCH, DISPOSABLE_EMAIL
+	Category       string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"` // EMAIL, NAME, PHONE
+	Value          string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`       // The actual value or pattern
+	Score          int32                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`      // Risk score to add
+	IsActive       bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Source         string                 `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`                                          // MANUAL, EXTERNAL_API, ML_MODEL
+	Confidence     float64                `protobuf:"fixed64,9,opt,name=confidence,proto3" json:"confidence,omitempty"`                                // 0.0 to 1.0
+	Priority       int32                  `protobuf:"varint,13,opt,name=priority,proto3" json:"priority,omitempty"`                                    // Higher priority rules are evaluated first within a category
+	ExampleMatch   string                 `protobuf:"bytes,14,opt,name=example_match,json=exampleMatch,proto3" json:"example_match,omitempty"`         // Input the rule is expected to match
+	ExampleNoMatch string                 `protobuf:"bytes,15,opt,name=example_no_match,json=exampleNoMatch,proto3" json:"example_no_match,omitempty"` // Input the rule is expected not to match
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unset when the rule never expires
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *RiskRule) Reset() {
@@ -285,39 +321,63 @@ func (x *RiskRule) GetConfidence() float64 {
 	return 0
 }
 
-func (x *RiskRule) GetCreatedAt() int64 {
+func (x *RiskRule) GetPriority() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.Priority
 	}
 	return 0
 }
 
-func (x *RiskRule) GetUpdatedAt() int64 {
+func (x *RiskRule) GetExampleMatch() string {
+	if x != nil {
+		return x.ExampleMatch
+	}
+	return ""
+}
+
+func (x *RiskRule) GetExampleNoMatch() string {
+	if x != nil {
+		return x.ExampleNoMatch
+	}
+	return ""
+}
+
+func (x *RiskRule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *RiskRule) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.UpdatedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *RiskRule) GetExpiresAt() int64 {
+func (x *RiskRule) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.ExpiresAt
 	}
-	return 0
+	return nil
 }
 
 type CreateRiskRuleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
-	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
-	Score         int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
-	IsActive      bool                   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	Confidence    float64                `protobuf:"fixed64,7,opt,name=confidence,proto3" json:"confidence,omitempty"`
-	ExpiresInDays int32                  `protobuf:"varint,8,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"` // 0 = never expires
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Category       string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Value          string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Score          int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	IsActive       bool                   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Confidence     float64                `protobuf:"fixed64,7,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	ExpiresInDays  int32                  `protobuf:"varint,8,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"`    // 0 = never expires
+	Priority       int32                  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`                                     // 0-1000, higher is evaluated first
+	ExampleMatch   string                 `protobuf:"bytes,10,opt,name=example_match,json=exampleMatch,proto3" json:"example_match,omitempty"`         // Optional, the rule must match it to be accepted
+	ExampleNoMatch string                 `protobuf:"bytes,11,opt,name=example_no_match,json=exampleNoMatch,proto3" json:"example_no_match,omitempty"` // Optional, the rule must not match it to be accepted
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateRiskRuleRequest) Reset() {
@@ -406,6 +466,27 @@ func (x *CreateRiskRuleRequest) GetExpiresInDays() int32 {
 	return 0
 }
 
+func (x *CreateRiskRuleRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CreateRiskRuleRequest) GetExampleMatch() string {
+	if x != nil {
+		return x.ExampleMatch
+	}
+	return ""
+}
+
+func (x *CreateRiskRuleRequest) GetExampleNoMatch() string {
+	if x != nil {
+		return x.ExampleNoMatch
+	}
+	return ""
+}
+
 type CreateRiskRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RuleId        string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
@@ -466,24 +547,199 @@ func (x *CreateRiskRuleResponse) GetError() string {
 	return ""
 }
 
-type UpdateRiskRuleRequest struct {
+type CreateRiskRulesRequest struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Rules         []*CreateRiskRuleRequest `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Partial       bool                     `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"` // false = all-or-nothing, true = create what is valid and report the rest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRiskRulesRequest) Reset() {
+	*x = CreateRiskRulesRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRiskRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRiskRulesRequest) ProtoMessage() {}
+
+func (x *CreateRiskRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRiskRulesRequest.ProtoReflect.Descriptor instead.
+func (*CreateRiskRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateRiskRulesRequest) GetRules() []*CreateRiskRuleRequest {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *CreateRiskRulesRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type RiskRuleResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RuleId        string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Value         string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
-	Score         int32                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
-	IsActive      bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	Confidence    float64                `protobuf:"fixed64,8,opt,name=confidence,proto3" json:"confidence,omitempty"`
-	ExpiresInDays int32                  `protobuf:"varint,9,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // position in CreateRiskRulesRequest.rules
+	RuleId        string                 `protobuf:"bytes,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskRuleResult) Reset() {
+	*x = RiskRuleResult{}
+	mi := &file_proto_risk_risk_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskRuleResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskRuleResult) ProtoMessage() {}
+
+func (x *RiskRuleResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskRuleResult.ProtoReflect.Descriptor instead.
+func (*RiskRuleResult) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RiskRuleResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RiskRuleResult) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *RiskRuleResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RiskRuleResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CreateRiskRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*RiskRuleResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *CreateRiskRulesResponse) Reset() {
+	*x = CreateRiskRulesResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRiskRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRiskRulesResponse) ProtoMessage() {}
+
+func (x *CreateRiskRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRiskRulesResponse.ProtoReflect.Descriptor instead.
+func (*CreateRiskRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateRiskRulesResponse) GetResults() []*RiskRuleResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *CreateRiskRulesResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type UpdateRiskRuleRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RuleId         string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Category       string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Value          string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Score          int32                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
+	IsActive       bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Confidence     float64                `protobuf:"fixed64,8,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	ExpiresInDays  int32                  `protobuf:"varint,9,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"`
+	Priority       int32                  `protobuf:"varint,10,opt,name=priority,proto3" json:"priority,omitempty"`
+	ExampleMatch   string                 `protobuf:"bytes,11,opt,name=example_match,json=exampleMatch,proto3" json:"example_match,omitempty"`
+	ExampleNoMatch string                 `protobuf:"bytes,12,opt,name=example_no_match,json=exampleNoMatch,proto3" json:"example_no_match,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *UpdateRiskRuleRequest) Reset() {
 	*x = UpdateRiskRuleRequest{}
-	mi := &file_proto_risk_risk_proto_msgTypes[5]
+	mi := &file_proto_risk_risk_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -495,7 +751,7 @@ func (x *UpdateRiskRuleRequest) String() string {
 func (*UpdateRiskRuleRequest) ProtoMessage() {}
 
 func (x *UpdateRiskRuleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[5]
+	mi := &file_proto_risk_risk_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,7 +764,7 @@ func (x *UpdateRiskRuleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRiskRuleRequest.ProtoReflect.Descriptor instead.
 func (*UpdateRiskRuleRequest) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{5}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateRiskRuleRequest) GetRuleId() string {
@@ -574,6 +830,27 @@ func (x *UpdateRiskRuleRequest) GetExpiresInDays() int32 {
 	return 0
 }
 
+func (x *UpdateRiskRuleRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *UpdateRiskRuleRequest) GetExampleMatch() string {
+	if x != nil {
+		return x.ExampleMatch
+	}
+	return ""
+}
+
+func (x *UpdateRiskRuleRequest) GetExampleNoMatch() string {
+	if x != nil {
+		return x.ExampleNoMatch
+	}
+	return ""
+}
+
 type UpdateRiskRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -584,7 +861,7 @@ type UpdateRiskRuleResponse struct {
 
 func (x *UpdateRiskRuleResponse) Reset() {
 	*x = UpdateRiskRuleResponse{}
-	mi := &file_proto_risk_risk_proto_msgTypes[6]
+	mi := &file_proto_risk_risk_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -596,7 +873,7 @@ func (x *UpdateRiskRuleResponse) String() string {
 func (*UpdateRiskRuleResponse) ProtoMessage() {}
 
 func (x *UpdateRiskRuleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[6]
+	mi := &file_proto_risk_risk_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -609,7 +886,7 @@ func (x *UpdateRiskRuleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRiskRuleResponse.ProtoReflect.Descriptor instead.
 func (*UpdateRiskRuleResponse) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{6}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateRiskRuleResponse) GetSuccess() bool {
@@ -635,7 +912,7 @@ type DeleteRiskRuleRequest struct {
 
 func (x *DeleteRiskRuleRequest) Reset() {
 	*x = DeleteRiskRuleRequest{}
-	mi := &file_proto_risk_risk_proto_msgTypes[7]
+	mi := &file_proto_risk_risk_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +924,7 @@ func (x *DeleteRiskRuleRequest) String() string {
 func (*DeleteRiskRuleRequest) ProtoMessage() {}
 
 func (x *DeleteRiskRuleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[7]
+	mi := &file_proto_risk_risk_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +937,7 @@ func (x *DeleteRiskRuleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRiskRuleRequest.ProtoReflect.Descriptor instead.
 func (*DeleteRiskRuleRequest) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{7}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeleteRiskRuleRequest) GetRuleId() string {
@@ -680,7 +957,7 @@ type DeleteRiskRuleResponse struct {
 
 func (x *DeleteRiskRuleResponse) Reset() {
 	*x = DeleteRiskRuleResponse{}
-	mi := &file_proto_risk_risk_proto_msgTypes[8]
+	mi := &file_proto_risk_risk_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -692,7 +969,7 @@ func (x *DeleteRiskRuleResponse) String() string {
 func (*DeleteRiskRuleResponse) ProtoMessage() {}
 
 func (x *DeleteRiskRuleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[8]
+	mi := &file_proto_risk_risk_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -705,7 +982,7 @@ func (x *DeleteRiskRuleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRiskRuleResponse.ProtoReflect.Descriptor instead.
 func (*DeleteRiskRuleResponse) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{8}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteRiskRuleResponse) GetSuccess() bool {
@@ -735,7 +1012,7 @@ type ListRiskRulesRequest struct {
 
 func (x *ListRiskRulesRequest) Reset() {
 	*x = ListRiskRulesRequest{}
-	mi := &file_proto_risk_risk_proto_msgTypes[9]
+	mi := &file_proto_risk_risk_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -747,7 +1024,7 @@ func (x *ListRiskRulesRequest) String() string {
 func (*ListRiskRulesRequest) ProtoMessage() {}
 
 func (x *ListRiskRulesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[9]
+	mi := &file_proto_risk_risk_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -760,7 +1037,7 @@ func (x *ListRiskRulesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRiskRulesRequest.ProtoReflect.Descriptor instead.
 func (*ListRiskRulesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{9}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListRiskRulesRequest) GetCategory() string {
@@ -810,7 +1087,7 @@ type ListRiskRulesResponse struct {
 
 func (x *ListRiskRulesResponse) Reset() {
 	*x = ListRiskRulesResponse{}
-	mi := &file_proto_risk_risk_proto_msgTypes[10]
+	mi := &file_proto_risk_risk_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -822,7 +1099,7 @@ func (x *ListRiskRulesResponse) String() string {
 func (*ListRiskRulesResponse) ProtoMessage() {}
 
 func (x *ListRiskRulesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[10]
+	mi := &file_proto_risk_risk_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -835,7 +1112,7 @@ func (x *ListRiskRulesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRiskRulesResponse.ProtoReflect.Descriptor instead.
 func (*ListRiskRulesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{10}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListRiskRulesResponse) GetRules() []*RiskRule {
@@ -875,7 +1152,7 @@ type GetRiskStatsRequest struct {
 
 func (x *GetRiskStatsRequest) Reset() {
 	*x = GetRiskStatsRequest{}
-	mi := &file_proto_risk_risk_proto_msgTypes[11]
+	mi := &file_proto_risk_risk_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -887,7 +1164,7 @@ func (x *GetRiskStatsRequest) String() string {
 func (*GetRiskStatsRequest) ProtoMessage() {}
 
 func (x *GetRiskStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[11]
+	mi := &file_proto_risk_risk_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -900,7 +1177,7 @@ func (x *GetRiskStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRiskStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetRiskStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{11}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetRiskStatsRequest) GetDays() int32 {
@@ -924,7 +1201,7 @@ type RiskStats struct {
 
 func (x *RiskStats) Reset() {
 	*x = RiskStats{}
-	mi := &file_proto_risk_risk_proto_msgTypes[12]
+	mi := &file_proto_risk_risk_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -936,7 +1213,7 @@ func (x *RiskStats) String() string {
 func (*RiskStats) ProtoMessage() {}
 
 func (x *RiskStats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[12]
+	mi := &file_proto_risk_risk_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -949,7 +1226,7 @@ func (x *RiskStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RiskStats.ProtoReflect.Descriptor instead.
 func (*RiskStats) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{12}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *RiskStats) GetTotalChecks() int32 {
@@ -1004,7 +1281,7 @@ type FlagCount struct {
 
 func (x *FlagCount) Reset() {
 	*x = FlagCount{}
-	mi := &file_proto_risk_risk_proto_msgTypes[13]
+	mi := &file_proto_risk_risk_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1016,7 +1293,7 @@ func (x *FlagCount) String() string {
 func (*FlagCount) ProtoMessage() {}
 
 func (x *FlagCount) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[13]
+	mi := &file_proto_risk_risk_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1029,7 +1306,7 @@ func (x *FlagCount) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FlagCount.ProtoReflect.Descriptor instead.
 func (*FlagCount) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{13}
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *FlagCount) GetFlag() string {
@@ -1057,19 +1334,775 @@ type TrendPoint struct {
 
 func (x *TrendPoint) Reset() {
 	*x = TrendPoint{}
-	mi := &file_proto_risk_risk_proto_msgTypes[14]
+	mi := &file_proto_risk_risk_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrendPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrendPoint) ProtoMessage() {}
+
+func (x *TrendPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrendPoint.ProtoReflect.Descriptor instead.
+func (*TrendPoint) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TrendPoint) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *TrendPoint) GetRiskCount() int32 {
+	if x != nil {
+		return x.RiskCount
+	}
+	return 0
+}
+
+func (x *TrendPoint) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetRiskStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *RiskStats             `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRiskStatsResponse) Reset() {
+	*x = GetRiskStatsResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRiskStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRiskStatsResponse) ProtoMessage() {}
+
+func (x *GetRiskStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRiskStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetRiskStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetRiskStatsResponse) GetStats() *RiskStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetRiskStatsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRiskStatsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListUsersByRiskLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RiskLevel     string                 `protobuf:"bytes,1,opt,name=risk_level,json=riskLevel,proto3" json:"risk_level,omitempty"` // LOW, MEDIUM, HIGH, CRITICAL - matched against each user's latest check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersByRiskLevelRequest) Reset() {
+	*x = ListUsersByRiskLevelRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersByRiskLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersByRiskLevelRequest) ProtoMessage() {}
+
+func (x *ListUsersByRiskLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersByRiskLevelRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersByRiskLevelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListUsersByRiskLevelRequest) GetRiskLevel() string {
+	if x != nil {
+		return x.RiskLevel
+	}
+	return ""
+}
+
+type ListUsersByRiskLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersByRiskLevelResponse) Reset() {
+	*x = ListUsersByRiskLevelResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersByRiskLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersByRiskLevelResponse) ProtoMessage() {}
+
+func (x *ListUsersByRiskLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersByRiskLevelResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersByRiskLevelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListUsersByRiskLevelResponse) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *ListUsersByRiskLevelResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListUsersByRiskLevelResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListDisposableDomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDisposableDomainsRequest) Reset() {
+	*x = ListDisposableDomainsRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDisposableDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDisposableDomainsRequest) ProtoMessage() {}
+
+func (x *ListDisposableDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDisposableDomainsRequest.ProtoReflect.Descriptor instead.
+func (*ListDisposableDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{21}
+}
+
+type ListDisposableDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,2,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"` // true while no list has been stored and the embedded list applies
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDisposableDomainsResponse) Reset() {
+	*x = ListDisposableDomainsResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDisposableDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDisposableDomainsResponse) ProtoMessage() {}
+
+func (x *ListDisposableDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDisposableDomainsResponse.ProtoReflect.Descriptor instead.
+func (*ListDisposableDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListDisposableDomainsResponse) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *ListDisposableDomainsResponse) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+// UpdateDisposableDomainsRequest edits the disposable domain list, starting from the embedded list
+// the first time. Rules pick up the change immediately.
+type UpdateDisposableDomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Add           []string               `protobuf:"bytes,1,rep,name=add,proto3" json:"add,omitempty"`
+	Remove        []string               `protobuf:"bytes,2,rep,name=remove,proto3" json:"remove,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDisposableDomainsRequest) Reset() {
+	*x = UpdateDisposableDomainsRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDisposableDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDisposableDomainsRequest) ProtoMessage() {}
+
+func (x *UpdateDisposableDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDisposableDomainsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDisposableDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UpdateDisposableDomainsRequest) GetAdd() []string {
+	if x != nil {
+		return x.Add
+	}
+	return nil
+}
+
+func (x *UpdateDisposableDomainsRequest) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+type UpdateDisposableDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Size of the list after the update
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDisposableDomainsResponse) Reset() {
+	*x = UpdateDisposableDomainsResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDisposableDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDisposableDomainsResponse) ProtoMessage() {}
+
+func (x *UpdateDisposableDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDisposableDomainsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDisposableDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdateDisposableDomainsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetCacheStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsRequest) Reset() {
+	*x = GetCacheStatsRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsRequest) ProtoMessage() {}
+
+func (x *GetCacheStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{25}
+}
+
+type GetCacheStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastUpdated   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // Last refresh, unset when not loaded since start or invalidation
+	AgeSeconds    float64                `protobuf:"fixed64,2,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	TtlSeconds    float64                `protobuf:"fixed64,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	RuleCounts    map[string]int32       `protobuf:"bytes,4,rep,name=rule_counts,json=ruleCounts,proto3" json:"rule_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Cached rules per category
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsResponse) Reset() {
+	*x = GetCacheStatsResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsResponse) ProtoMessage() {}
+
+func (x *GetCacheStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetCacheStatsResponse) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *GetCacheStatsResponse) GetAgeSeconds() float64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetTtlSeconds() float64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetRuleCounts() map[string]int32 {
+	if x != nil {
+		return x.RuleCounts
+	}
+	return nil
+}
+
+// InvalidateCacheRequest marks the rule cache stale so the next risk check reloads it
+type InvalidateCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvalidateCacheRequest) Reset() {
+	*x = InvalidateCacheRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvalidateCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidateCacheRequest) ProtoMessage() {}
+
+func (x *InvalidateCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidateCacheRequest.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{27}
+}
+
+type InvalidateCacheResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvalidateCacheResponse) Reset() {
+	*x = InvalidateCacheResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvalidateCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidateCacheResponse) ProtoMessage() {}
+
+func (x *InvalidateCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidateCacheResponse.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *InvalidateCacheResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// ListFeatureFlagsRequest optionally names a user to evaluate every flag for
+type ListFeatureFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsRequest) Reset() {
+	*x = ListFeatureFlagsRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsRequest) ProtoMessage() {}
+
+func (x *ListFeatureFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsRequest.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListFeatureFlagsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type FeatureFlag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Percentage    int32                  `protobuf:"varint,2,opt,name=percentage,proto3" json:"percentage,omitempty"` // Share of users the flag is on for, 0-100
+	Allowlist     []string               `protobuf:"bytes,3,rep,name=allowlist,proto3" json:"allowlist,omitempty"`    // User IDs the flag is always on for
+	Enabled       bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`       // Whether the flag is on for the requested user, false without one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeatureFlag) Reset() {
+	*x = FeatureFlag{}
+	mi := &file_proto_risk_risk_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlag) ProtoMessage() {}
+
+func (x *FeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlag.ProtoReflect.Descriptor instead.
+func (*FeatureFlag) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetPercentage() int32 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetAllowlist() []string {
+	if x != nil {
+		return x.Allowlist
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type ListFeatureFlagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flags         []*FeatureFlag         `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsResponse) Reset() {
+	*x = ListFeatureFlagsResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsResponse) ProtoMessage() {}
+
+func (x *ListFeatureFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsResponse.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListFeatureFlagsResponse) GetFlags() []*FeatureFlag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+// RecheckUsersRequest selects users by paging through the user service listing.
+// A call stops after limit checked users or shortly before its deadline, resume by passing next_offset as offset.
+type RecheckUsersRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EmailDomain     string                 `protobuf:"bytes,1,opt,name=email_domain,json=emailDomain,proto3" json:"email_domain,omitempty"`              // Only users with an email on this domain, empty for all
+	Role            string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`                                               // Only users with this role, empty for all
+	IncludeInactive bool                   `protobuf:"varint,3,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"` // Also recheck deactivated users
+	Limit           int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                            // Users checked per call, defaults to 100, at most 1000
+	Offset          int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`                                          // Position in the user listing to start from
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RecheckUsersRequest) Reset() {
+	*x = RecheckUsersRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TrendPoint) String() string {
+func (x *RecheckUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrendPoint) ProtoMessage() {}
+func (*RecheckUsersRequest) ProtoMessage() {}
 
-func (x *TrendPoint) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[14]
+func (x *RecheckUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1080,56 +2113,167 @@ func (x *TrendPoint) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrendPoint.ProtoReflect.Descriptor instead.
-func (*TrendPoint) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RecheckUsersRequest.ProtoReflect.Descriptor instead.
+func (*RecheckUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *TrendPoint) GetDate() string {
+func (x *RecheckUsersRequest) GetEmailDomain() string {
 	if x != nil {
-		return x.Date
+		return x.EmailDomain
 	}
 	return ""
 }
 
-func (x *TrendPoint) GetRiskCount() int32 {
+func (x *RecheckUsersRequest) GetRole() string {
 	if x != nil {
-		return x.RiskCount
+		return x.Role
+	}
+	return ""
+}
+
+func (x *RecheckUsersRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+func (x *RecheckUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-func (x *TrendPoint) GetTotalCount() int32 {
+func (x *RecheckUsersRequest) GetOffset() int32 {
 	if x != nil {
-		return x.TotalCount
+		return x.Offset
 	}
 	return 0
 }
 
-type GetRiskStatsResponse struct {
+type RecheckUsersResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Checked           int32                  `protobuf:"varint,1,opt,name=checked,proto3" json:"checked,omitempty"`
+	Risky             int32                  `protobuf:"varint,2,opt,name=risky,proto3" json:"risky,omitempty"`
+	NewlyRisky        int32                  `protobuf:"varint,3,opt,name=newly_risky,json=newlyRisky,proto3" json:"newly_risky,omitempty"` // Risky now but not on their previous check, or never checked before
+	NewlyRiskyUserIds []string               `protobuf:"bytes,4,rep,name=newly_risky_user_ids,json=newlyRiskyUserIds,proto3" json:"newly_risky_user_ids,omitempty"`
+	Failed            int32                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`                           // Users whose check failed, they are not retried by resuming
+	NextOffset        int32                  `protobuf:"varint,6,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"` // Offset to resume from
+	Done              bool                   `protobuf:"varint,7,opt,name=done,proto3" json:"done,omitempty"`                               // The listing was exhausted, nothing is left to resume
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RecheckUsersResponse) Reset() {
+	*x = RecheckUsersResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecheckUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecheckUsersResponse) ProtoMessage() {}
+
+func (x *RecheckUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecheckUsersResponse.ProtoReflect.Descriptor instead.
+func (*RecheckUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RecheckUsersResponse) GetChecked() int32 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *RecheckUsersResponse) GetRisky() int32 {
+	if x != nil {
+		return x.Risky
+	}
+	return 0
+}
+
+func (x *RecheckUsersResponse) GetNewlyRisky() int32 {
+	if x != nil {
+		return x.NewlyRisky
+	}
+	return 0
+}
+
+func (x *RecheckUsersResponse) GetNewlyRiskyUserIds() []string {
+	if x != nil {
+		return x.NewlyRiskyUserIds
+	}
+	return nil
+}
+
+func (x *RecheckUsersResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *RecheckUsersResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *RecheckUsersResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+// GetRiskHistoryRequest pages through a user's stored checks, newest first by default.
+type GetRiskHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         *RiskStats             `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                         // Page size, defaults to 20, at most 100
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`                        // next_cursor of the previous page, empty for the first page
+	RiskLevel     string                 `protobuf:"bytes,4,opt,name=risk_level,json=riskLevel,proto3" json:"risk_level,omitempty"` // Only checks at this level, empty for all
+	Since         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`                          // Only checks at or after this time
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`                          // Only checks before this time
+	Sort          string                 `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`                            // newest (default) or oldest
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRiskStatsResponse) Reset() {
-	*x = GetRiskStatsResponse{}
-	mi := &file_proto_risk_risk_proto_msgTypes[15]
+func (x *GetRiskHistoryRequest) Reset() {
+	*x = GetRiskHistoryRequest{}
+	mi := &file_proto_risk_risk_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRiskStatsResponse) String() string {
+func (x *GetRiskHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRiskStatsResponse) ProtoMessage() {}
+func (*GetRiskHistoryRequest) ProtoMessage() {}
 
-func (x *GetRiskStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_risk_risk_proto_msgTypes[15]
+func (x *GetRiskHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1140,28 +2284,208 @@ func (x *GetRiskStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRiskStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetRiskStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_risk_risk_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetRiskHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRiskHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *GetRiskStatsResponse) GetStats() *RiskStats {
+func (x *GetRiskHistoryRequest) GetUserId() string {
 	if x != nil {
-		return x.Stats
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRiskHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetRiskHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetRiskHistoryRequest) GetRiskLevel() string {
+	if x != nil {
+		return x.RiskLevel
+	}
+	return ""
+}
+
+func (x *GetRiskHistoryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
 	}
 	return nil
 }
 
-func (x *GetRiskStatsResponse) GetSuccess() bool {
+func (x *GetRiskHistoryRequest) GetUntil() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Success
+		return x.Until
+	}
+	return nil
+}
+
+func (x *GetRiskHistoryRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type RiskCheckRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckId       string                 `protobuf:"bytes,1,opt,name=check_id,json=checkId,proto3" json:"check_id,omitempty"`
+	IsRisky       bool                   `protobuf:"varint,2,opt,name=is_risky,json=isRisky,proto3" json:"is_risky,omitempty"`
+	RiskLevel     string                 `protobuf:"bytes,3,opt,name=risk_level,json=riskLevel,proto3" json:"risk_level,omitempty"`
+	TotalScore    int32                  `protobuf:"varint,4,opt,name=total_score,json=totalScore,proto3" json:"total_score,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Flags         []string               `protobuf:"bytes,6,rep,name=flags,proto3" json:"flags,omitempty"`
+	CheckedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	SampleWeight  int32                  `protobuf:"varint,8,opt,name=sample_weight,json=sampleWeight,proto3" json:"sample_weight,omitempty"` // Checks this record stands for when analytics sampling is on
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskCheckRecord) Reset() {
+	*x = RiskCheckRecord{}
+	mi := &file_proto_risk_risk_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskCheckRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskCheckRecord) ProtoMessage() {}
+
+func (x *RiskCheckRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskCheckRecord.ProtoReflect.Descriptor instead.
+func (*RiskCheckRecord) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RiskCheckRecord) GetCheckId() string {
+	if x != nil {
+		return x.CheckId
+	}
+	return ""
+}
+
+func (x *RiskCheckRecord) GetIsRisky() bool {
+	if x != nil {
+		return x.IsRisky
 	}
 	return false
 }
 
-func (x *GetRiskStatsResponse) GetError() string {
+func (x *RiskCheckRecord) GetRiskLevel() string {
 	if x != nil {
-		return x.Error
+		return x.RiskLevel
+	}
+	return ""
+}
+
+func (x *RiskCheckRecord) GetTotalScore() int32 {
+	if x != nil {
+		return x.TotalScore
+	}
+	return 0
+}
+
+func (x *RiskCheckRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RiskCheckRecord) GetFlags() []string {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *RiskCheckRecord) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *RiskCheckRecord) GetSampleWeight() int32 {
+	if x != nil {
+		return x.SampleWeight
+	}
+	return 0
+}
+
+type GetRiskHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checks        []*RiskCheckRecord     `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRiskHistoryResponse) Reset() {
+	*x = GetRiskHistoryResponse{}
+	mi := &file_proto_risk_risk_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRiskHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRiskHistoryResponse) ProtoMessage() {}
+
+func (x *GetRiskHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_risk_risk_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRiskHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRiskHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_risk_risk_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetRiskHistoryResponse) GetChecks() []*RiskCheckRecord {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *GetRiskHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
 	return ""
 }
@@ -1170,21 +2494,25 @@ var File_proto_risk_risk_proto protoreflect.FileDescriptor
 
 const file_proto_risk_risk_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/risk/risk.proto\x12\x04risk\"\x93\x01\n" +
+	"\x15proto/risk/risk.proto\x12\x04risk\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc3\x01\n" +
 	"\x10RiskCheckRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
 	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x14\n" +
-	"\x05phone\x18\x05 \x01(\tR\x05phone\"\x94\x01\n" +
+	"\x05phone\x18\x05 \x01(\tR\x05phone\x12\x15\n" +
+	"\x06org_id\x18\x06 \x01(\tR\x05orgId\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\"\xc8\x01\n" +
 	"\x11RiskCheckResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
 	"\bis_risky\x18\x02 \x01(\bR\aisRisky\x12\x1d\n" +
 	"\n" +
 	"risk_level\x18\x03 \x01(\tR\triskLevel\x12\x16\n" +
 	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x14\n" +
-	"\x05flags\x18\x05 \x03(\tR\x05flags\"\xbc\x02\n" +
+	"\x05flags\x18\x05 \x03(\tR\x05flags\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\x12\x19\n" +
+	"\bcheck_id\x18\a \x01(\tR\acheckId\"\x8d\x04\n" +
 	"\bRiskRule\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
@@ -1196,14 +2524,17 @@ const file_proto_risk_risk_proto_rawDesc = "" +
 	"\x06source\x18\b \x01(\tR\x06source\x12\x1e\n" +
 	"\n" +
 	"confidence\x18\t \x01(\x01R\n" +
-	"confidence\x12\x1d\n" +
+	"confidence\x12\x1a\n" +
+	"\bpriority\x18\r \x01(\x05R\bpriority\x12#\n" +
+	"\rexample_match\x18\x0e \x01(\tR\fexampleMatch\x12(\n" +
+	"\x10example_no_match\x18\x0f \x01(\tR\x0eexampleNoMatch\x129\n" +
 	"\n" +
-	"created_at\x18\n" +
-	" \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\x03R\tupdatedAt\x12\x1d\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
 	"\n" +
-	"expires_at\x18\f \x01(\x03R\texpiresAt\"\xec\x01\n" +
+	"expires_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAtJ\x04\b\n" +
+	"\x10\vJ\x04\b\v\x10\fJ\x04\b\f\x10\r\"\xd7\x02\n" +
 	"\x15CreateRiskRuleRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
@@ -1214,11 +2545,26 @@ const file_proto_risk_risk_proto_rawDesc = "" +
 	"\n" +
 	"confidence\x18\a \x01(\x01R\n" +
 	"confidence\x12&\n" +
-	"\x0fexpires_in_days\x18\b \x01(\x05R\rexpiresInDays\"a\n" +
+	"\x0fexpires_in_days\x18\b \x01(\x05R\rexpiresInDays\x12\x1a\n" +
+	"\bpriority\x18\t \x01(\x05R\bpriority\x12#\n" +
+	"\rexample_match\x18\n" +
+	" \x01(\tR\fexampleMatch\x12(\n" +
+	"\x10example_no_match\x18\v \x01(\tR\x0eexampleNoMatch\"a\n" +
 	"\x16CreateRiskRuleResponse\x12\x17\n" +
 	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"\x85\x02\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"e\n" +
+	"\x16CreateRiskRulesRequest\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.risk.CreateRiskRuleRequestR\x05rules\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\"o\n" +
+	"\x0eRiskRuleResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x17\n" +
+	"\arule_id\x18\x02 \x01(\tR\x06ruleId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"c\n" +
+	"\x17CreateRiskRulesResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.risk.RiskRuleResultR\aresults\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\"\xf0\x02\n" +
 	"\x15UpdateRiskRuleRequest\x12\x17\n" +
 	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
@@ -1230,7 +2576,11 @@ const file_proto_risk_risk_proto_rawDesc = "" +
 	"\n" +
 	"confidence\x18\b \x01(\x01R\n" +
 	"confidence\x12&\n" +
-	"\x0fexpires_in_days\x18\t \x01(\x05R\rexpiresInDays\"H\n" +
+	"\x0fexpires_in_days\x18\t \x01(\x05R\rexpiresInDays\x12\x1a\n" +
+	"\bpriority\x18\n" +
+	" \x01(\x05R\bpriority\x12#\n" +
+	"\rexample_match\x18\v \x01(\tR\fexampleMatch\x12(\n" +
+	"\x10example_no_match\x18\f \x01(\tR\x0eexampleNoMatch\"H\n" +
 	"\x16UpdateRiskRuleResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\"0\n" +
@@ -1276,15 +2626,109 @@ const file_proto_risk_risk_proto_rawDesc = "" +
 	"\x14GetRiskStatsResponse\x12%\n" +
 	"\x05stats\x18\x01 \x01(\v2\x0f.risk.RiskStatsR\x05stats\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error2K\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"<\n" +
+	"\x1bListUsersByRiskLevelRequest\x12\x1d\n" +
+	"\n" +
+	"risk_level\x18\x01 \x01(\tR\triskLevel\"i\n" +
+	"\x1cListUsersByRiskLevelResponse\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x1e\n" +
+	"\x1cListDisposableDomainsRequest\"X\n" +
+	"\x1dListDisposableDomainsResponse\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x02 \x01(\bR\tisDefault\"J\n" +
+	"\x1eUpdateDisposableDomainsRequest\x12\x10\n" +
+	"\x03add\x18\x01 \x03(\tR\x03add\x12\x16\n" +
+	"\x06remove\x18\x02 \x03(\tR\x06remove\"7\n" +
+	"\x1fUpdateDisposableDomainsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\"\x16\n" +
+	"\x14GetCacheStatsRequest\"\xab\x02\n" +
+	"\x15GetCacheStatsResponse\x12=\n" +
+	"\flast_updated\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x12\x1f\n" +
+	"\vage_seconds\x18\x02 \x01(\x01R\n" +
+	"ageSeconds\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x01R\n" +
+	"ttlSeconds\x12L\n" +
+	"\vrule_counts\x18\x04 \x03(\v2+.risk.GetCacheStatsResponse.RuleCountsEntryR\n" +
+	"ruleCounts\x1a=\n" +
+	"\x0fRuleCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01J\x04\b\x01\x10\x02\"\x18\n" +
+	"\x16InvalidateCacheRequest\"3\n" +
+	"\x17InvalidateCacheResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"2\n" +
+	"\x17ListFeatureFlagsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"y\n" +
+	"\vFeatureFlag\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x02 \x01(\x05R\n" +
+	"percentage\x12\x1c\n" +
+	"\tallowlist\x18\x03 \x03(\tR\tallowlist\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\"C\n" +
+	"\x18ListFeatureFlagsResponse\x12'\n" +
+	"\x05flags\x18\x01 \x03(\v2\x11.risk.FeatureFlagR\x05flags\"\xa5\x01\n" +
+	"\x13RecheckUsersRequest\x12!\n" +
+	"\femail_domain\x18\x01 \x01(\tR\vemailDomain\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12)\n" +
+	"\x10include_inactive\x18\x03 \x01(\bR\x0fincludeInactive\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"\xe5\x01\n" +
+	"\x14RecheckUsersResponse\x12\x18\n" +
+	"\achecked\x18\x01 \x01(\x05R\achecked\x12\x14\n" +
+	"\x05risky\x18\x02 \x01(\x05R\x05risky\x12\x1f\n" +
+	"\vnewly_risky\x18\x03 \x01(\x05R\n" +
+	"newlyRisky\x12/\n" +
+	"\x14newly_risky_user_ids\x18\x04 \x03(\tR\x11newlyRiskyUserIds\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x05R\x06failed\x12\x1f\n" +
+	"\vnext_offset\x18\x06 \x01(\x05R\n" +
+	"nextOffset\x12\x12\n" +
+	"\x04done\x18\a \x01(\bR\x04done\"\xf5\x01\n" +
+	"\x15GetRiskHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x1d\n" +
+	"\n" +
+	"risk_level\x18\x04 \x01(\tR\triskLevel\x120\n" +
+	"\x05since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\"\x95\x02\n" +
+	"\x0fRiskCheckRecord\x12\x19\n" +
+	"\bcheck_id\x18\x01 \x01(\tR\acheckId\x12\x19\n" +
+	"\bis_risky\x18\x02 \x01(\bR\aisRisky\x12\x1d\n" +
+	"\n" +
+	"risk_level\x18\x03 \x01(\tR\triskLevel\x12\x1f\n" +
+	"\vtotal_score\x18\x04 \x01(\x05R\n" +
+	"totalScore\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05flags\x18\x06 \x03(\tR\x05flags\x129\n" +
+	"\n" +
+	"checked_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12#\n" +
+	"\rsample_weight\x18\b \x01(\x05R\fsampleWeight\"h\n" +
+	"\x16GetRiskHistoryResponse\x12-\n" +
+	"\x06checks\x18\x01 \x03(\v2\x15.risk.RiskCheckRecordR\x06checks\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\x93\x01\n" +
 	"\vRiskService\x12<\n" +
-	"\tCheckRisk\x12\x16.risk.RiskCheckRequest\x1a\x17.risk.RiskCheckResponse2\x8a\x03\n" +
+	"\tCheckRisk\x12\x16.risk.RiskCheckRequest\x1a\x17.risk.RiskCheckResponse\x12F\n" +
+	"\x0fStreamCheckRisk\x12\x16.risk.RiskCheckRequest\x1a\x17.risk.RiskCheckResponse(\x010\x012\x84\t\n" +
 	"\x10RiskAdminService\x12K\n" +
-	"\x0eCreateRiskRule\x12\x1b.risk.CreateRiskRuleRequest\x1a\x1c.risk.CreateRiskRuleResponse\x12K\n" +
+	"\x0eCreateRiskRule\x12\x1b.risk.CreateRiskRuleRequest\x1a\x1c.risk.CreateRiskRuleResponse\x12N\n" +
+	"\x0fCreateRiskRules\x12\x1c.risk.CreateRiskRulesRequest\x1a\x1d.risk.CreateRiskRulesResponse\x12K\n" +
 	"\x0eUpdateRiskRule\x12\x1b.risk.UpdateRiskRuleRequest\x1a\x1c.risk.UpdateRiskRuleResponse\x12K\n" +
 	"\x0eDeleteRiskRule\x12\x1b.risk.DeleteRiskRuleRequest\x1a\x1c.risk.DeleteRiskRuleResponse\x12H\n" +
 	"\rListRiskRules\x12\x1a.risk.ListRiskRulesRequest\x1a\x1b.risk.ListRiskRulesResponse\x12E\n" +
-	"\fGetRiskStats\x12\x19.risk.GetRiskStatsRequest\x1a\x1a.risk.GetRiskStatsResponseB\x1dZ\x1buser-risk-system/proto/riskb\x06proto3"
+	"\fGetRiskStats\x12\x19.risk.GetRiskStatsRequest\x1a\x1a.risk.GetRiskStatsResponse\x12]\n" +
+	"\x14ListUsersByRiskLevel\x12!.risk.ListUsersByRiskLevelRequest\x1a\".risk.ListUsersByRiskLevelResponse\x12`\n" +
+	"\x15ListDisposableDomains\x12\".risk.ListDisposableDomainsRequest\x1a#.risk.ListDisposableDomainsResponse\x12f\n" +
+	"\x17UpdateDisposableDomains\x12$.risk.UpdateDisposableDomainsRequest\x1a%.risk.UpdateDisposableDomainsResponse\x12H\n" +
+	"\rGetCacheStats\x12\x1a.risk.GetCacheStatsRequest\x1a\x1b.risk.GetCacheStatsResponse\x12N\n" +
+	"\x0fInvalidateCache\x12\x1c.risk.InvalidateCacheRequest\x1a\x1d.risk.InvalidateCacheResponse\x12Q\n" +
+	"\x10ListFeatureFlags\x12\x1d.risk.ListFeatureFlagsRequest\x1a\x1e.risk.ListFeatureFlagsResponse\x12E\n" +
+	"\fRecheckUsers\x12\x19.risk.RecheckUsersRequest\x1a\x1a.risk.RecheckUsersResponse\x12K\n" +
+	"\x0eGetRiskHistory\x12\x1b.risk.GetRiskHistoryRequest\x1a\x1c.risk.GetRiskHistoryResponseB\x1dZ\x1buser-risk-system/proto/riskb\x06proto3"
 
 var (
 	file_proto_risk_risk_proto_rawDescOnce sync.Once
@@ -1298,47 +2742,102 @@ func file_proto_risk_risk_proto_rawDescGZIP() []byte {
 	return file_proto_risk_risk_proto_rawDescData
 }
 
-var file_proto_risk_risk_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_risk_risk_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_proto_risk_risk_proto_goTypes = []any{
-	(*RiskCheckRequest)(nil),       // 0: risk.RiskCheckRequest
-	(*RiskCheckResponse)(nil),      // 1: risk.RiskCheckResponse
-	(*RiskRule)(nil),               // 2: risk.RiskRule
-	(*CreateRiskRuleRequest)(nil),  // 3: risk.CreateRiskRuleRequest
-	(*CreateRiskRuleResponse)(nil), // 4: risk.CreateRiskRuleResponse
-	(*UpdateRiskRuleRequest)(nil),  // 5: risk.UpdateRiskRuleRequest
-	(*UpdateRiskRuleResponse)(nil), // 6: risk.UpdateRiskRuleResponse
-	(*DeleteRiskRuleRequest)(nil),  // 7: risk.DeleteRiskRuleRequest
-	(*DeleteRiskRuleResponse)(nil), // 8: risk.DeleteRiskRuleResponse
-	(*ListRiskRulesRequest)(nil),   // 9: risk.ListRiskRulesRequest
-	(*ListRiskRulesResponse)(nil),  // 10: risk.ListRiskRulesResponse
-	(*GetRiskStatsRequest)(nil),    // 11: risk.GetRiskStatsRequest
-	(*RiskStats)(nil),              // 12: risk.RiskStats
-	(*FlagCount)(nil),              // 13: risk.FlagCount
-	(*TrendPoint)(nil),             // 14: risk.TrendPoint
-	(*GetRiskStatsResponse)(nil),   // 15: risk.GetRiskStatsResponse
+	(*RiskCheckRequest)(nil),                // 0: risk.RiskCheckRequest
+	(*RiskCheckResponse)(nil),               // 1: risk.RiskCheckResponse
+	(*RiskRule)(nil),                        // 2: risk.RiskRule
+	(*CreateRiskRuleRequest)(nil),           // 3: risk.CreateRiskRuleRequest
+	(*CreateRiskRuleResponse)(nil),          // 4: risk.CreateRiskRuleResponse
+	(*CreateRiskRulesRequest)(nil),          // 5: risk.CreateRiskRulesRequest
+	(*RiskRuleResult)(nil),                  // 6: risk.RiskRuleResult
+	(*CreateRiskRulesResponse)(nil),         // 7: risk.CreateRiskRulesResponse
+	(*UpdateRiskRuleRequest)(nil),           // 8: risk.UpdateRiskRuleRequest
+	(*UpdateRiskRuleResponse)(nil),          // 9: risk.UpdateRiskRuleResponse
+	(*DeleteRiskRuleRequest)(nil),           // 10: risk.DeleteRiskRuleRequest
+	(*DeleteRiskRuleResponse)(nil),          // 11: risk.DeleteRiskRuleResponse
+	(*ListRiskRulesRequest)(nil),            // 12: risk.ListRiskRulesRequest
+	(*ListRiskRulesResponse)(nil),           // 13: risk.ListRiskRulesResponse
+	(*GetRiskStatsRequest)(nil),             // 14: risk.GetRiskStatsRequest
+	(*RiskStats)(nil),                       // 15: risk.RiskStats
+	(*FlagCount)(nil),                       // 16: risk.FlagCount
+	(*TrendPoint)(nil),                      // 17: risk.TrendPoint
+	(*GetRiskStatsResponse)(nil),            // 18: risk.GetRiskStatsResponse
+	(*ListUsersByRiskLevelRequest)(nil),     // 19: risk.ListUsersByRiskLevelRequest
+	(*ListUsersByRiskLevelResponse)(nil),    // 20: risk.ListUsersByRiskLevelResponse
+	(*ListDisposableDomainsRequest)(nil),    // 21: risk.ListDisposableDomainsRequest
+	(*ListDisposableDomainsResponse)(nil),   // 22: risk.ListDisposableDomainsResponse
+	(*UpdateDisposableDomainsRequest)(nil),  // 23: risk.UpdateDisposableDomainsRequest
+	(*UpdateDisposableDomainsResponse)(nil), // 24: risk.UpdateDisposableDomainsResponse
+	(*GetCacheStatsRequest)(nil),            // 25: risk.GetCacheStatsRequest
+	(*GetCacheStatsResponse)(nil),           // 26: risk.GetCacheStatsResponse
+	(*InvalidateCacheRequest)(nil),          // 27: risk.InvalidateCacheRequest
+	(*InvalidateCacheResponse)(nil),         // 28: risk.InvalidateCacheResponse
+	(*ListFeatureFlagsRequest)(nil),         // 29: risk.ListFeatureFlagsRequest
+	(*FeatureFlag)(nil),                     // 30: risk.FeatureFlag
+	(*ListFeatureFlagsResponse)(nil),        // 31: risk.ListFeatureFlagsResponse
+	(*RecheckUsersRequest)(nil),             // 32: risk.RecheckUsersRequest
+	(*RecheckUsersResponse)(nil),            // 33: risk.RecheckUsersResponse
+	(*GetRiskHistoryRequest)(nil),           // 34: risk.GetRiskHistoryRequest
+	(*RiskCheckRecord)(nil),                 // 35: risk.RiskCheckRecord
+	(*GetRiskHistoryResponse)(nil),          // 36: risk.GetRiskHistoryResponse
+	nil,                                     // 37: risk.GetCacheStatsResponse.RuleCountsEntry
+	(*timestamppb.Timestamp)(nil),           // 38: google.protobuf.Timestamp
 }
 var file_proto_risk_risk_proto_depIdxs = []int32{
-	2,  // 0: risk.ListRiskRulesResponse.rules:type_name -> risk.RiskRule
-	13, // 1: risk.RiskStats.top_flags:type_name -> risk.FlagCount
-	14, // 2: risk.RiskStats.trend_data:type_name -> risk.TrendPoint
-	12, // 3: risk.GetRiskStatsResponse.stats:type_name -> risk.RiskStats
-	0,  // 4: risk.RiskService.CheckRisk:input_type -> risk.RiskCheckRequest
-	3,  // 5: risk.RiskAdminService.CreateRiskRule:input_type -> risk.CreateRiskRuleRequest
-	5,  // 6: risk.RiskAdminService.UpdateRiskRule:input_type -> risk.UpdateRiskRuleRequest
-	7,  // 7: risk.RiskAdminService.DeleteRiskRule:input_type -> risk.DeleteRiskRuleRequest
-	9,  // 8: risk.RiskAdminService.ListRiskRules:input_type -> risk.ListRiskRulesRequest
-	11, // 9: risk.RiskAdminService.GetRiskStats:input_type -> risk.GetRiskStatsRequest
-	1,  // 10: risk.RiskService.CheckRisk:output_type -> risk.RiskCheckResponse
-	4,  // 11: risk.RiskAdminService.CreateRiskRule:output_type -> risk.CreateRiskRuleResponse
-	6,  // 12: risk.RiskAdminService.UpdateRiskRule:output_type -> risk.UpdateRiskRuleResponse
-	8,  // 13: risk.RiskAdminService.DeleteRiskRule:output_type -> risk.DeleteRiskRuleResponse
-	10, // 14: risk.RiskAdminService.ListRiskRules:output_type -> risk.ListRiskRulesResponse
-	15, // 15: risk.RiskAdminService.GetRiskStats:output_type -> risk.GetRiskStatsResponse
-	10, // [10:16] is the sub-list for method output_type
-	4,  // [4:10] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	38, // 0: risk.RiskRule.created_at:type_name -> google.protobuf.Timestamp
+	38, // 1: risk.RiskRule.updated_at:type_name -> google.protobuf.Timestamp
+	38, // 2: risk.RiskRule.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 3: risk.CreateRiskRulesRequest.rules:type_name -> risk.CreateRiskRuleRequest
+	6,  // 4: risk.CreateRiskRulesResponse.results:type_name -> risk.RiskRuleResult
+	2,  // 5: risk.ListRiskRulesResponse.rules:type_name -> risk.RiskRule
+	16, // 6: risk.RiskStats.top_flags:type_name -> risk.FlagCount
+	17, // 7: risk.RiskStats.trend_data:type_name -> risk.TrendPoint
+	15, // 8: risk.GetRiskStatsResponse.stats:type_name -> risk.RiskStats
+	38, // 9: risk.GetCacheStatsResponse.last_updated:type_name -> google.protobuf.Timestamp
+	37, // 10: risk.GetCacheStatsResponse.rule_counts:type_name -> risk.GetCacheStatsResponse.RuleCountsEntry
+	30, // 11: risk.ListFeatureFlagsResponse.flags:type_name -> risk.FeatureFlag
+	38, // 12: risk.GetRiskHistoryRequest.since:type_name -> google.protobuf.Timestamp
+	38, // 13: risk.GetRiskHistoryRequest.until:type_name -> google.protobuf.Timestamp
+	38, // 14: risk.RiskCheckRecord.checked_at:type_name -> google.protobuf.Timestamp
+	35, // 15: risk.GetRiskHistoryResponse.checks:type_name -> risk.RiskCheckRecord
+	0,  // 16: risk.RiskService.CheckRisk:input_type -> risk.RiskCheckRequest
+	0,  // 17: risk.RiskService.StreamCheckRisk:input_type -> risk.RiskCheckRequest
+	3,  // 18: risk.RiskAdminService.CreateRiskRule:input_type -> risk.CreateRiskRuleRequest
+	5,  // 19: risk.RiskAdminService.CreateRiskRules:input_type -> risk.CreateRiskRulesRequest
+	8,  // 20: risk.RiskAdminService.UpdateRiskRule:input_type -> risk.UpdateRiskRuleRequest
+	10, // 21: risk.RiskAdminService.DeleteRiskRule:input_type -> risk.DeleteRiskRuleRequest
+	12, // 22: risk.RiskAdminService.ListRiskRules:input_type -> risk.ListRiskRulesRequest
+	14, // 23: risk.RiskAdminService.GetRiskStats:input_type -> risk.GetRiskStatsRequest
+	19, // 24: risk.RiskAdminService.ListUsersByRiskLevel:input_type -> risk.ListUsersByRiskLevelRequest
+	21, // 25: risk.RiskAdminService.ListDisposableDomains:input_type -> risk.ListDisposableDomainsRequest
+	23, // 26: risk.RiskAdminService.UpdateDisposableDomains:input_type -> risk.UpdateDisposableDomainsRequest
+	25, // 27: risk.RiskAdminService.GetCacheStats:input_type -> risk.GetCacheStatsRequest
+	27, // 28: risk.RiskAdminService.InvalidateCache:input_type -> risk.InvalidateCacheRequest
+	29, // 29: risk.RiskAdminService.ListFeatureFlags:input_type -> risk.ListFeatureFlagsRequest
+	32, // 30: risk.RiskAdminService.RecheckUsers:input_type -> risk.RecheckUsersRequest
+	34, // 31: risk.RiskAdminService.GetRiskHistory:input_type -> risk.GetRiskHistoryRequest
+	1,  // 32: risk.RiskService.CheckRisk:output_type -> risk.RiskCheckResponse
+	1,  // 33: risk.RiskService.StreamCheckRisk:output_type -> risk.RiskCheckResponse
+	4,  // 34: risk.RiskAdminService.CreateRiskRule:output_type -> risk.CreateRiskRuleResponse
+	7,  // 35: risk.RiskAdminService.CreateRiskRules:output_type -> risk.CreateRiskRulesResponse
+	9,  // 36: risk.RiskAdminService.UpdateRiskRule:output_type -> risk.UpdateRiskRuleResponse
+	11, // 37: risk.RiskAdminService.DeleteRiskRule:output_type -> risk.DeleteRiskRuleResponse
+	13, // 38: risk.RiskAdminService.ListRiskRules:output_type -> risk.ListRiskRulesResponse
+	18, // 39: risk.RiskAdminService.GetRiskStats:output_type -> risk.GetRiskStatsResponse
+	20, // 40: risk.RiskAdminService.ListUsersByRiskLevel:output_type -> risk.ListUsersByRiskLevelResponse
+	22, // 41: risk.RiskAdminService.ListDisposableDomains:output_type -> risk.ListDisposableDomainsResponse
+	24, // 42: risk.RiskAdminService.UpdateDisposableDomains:output_type -> risk.UpdateDisposableDomainsResponse
+	26, // 43: risk.RiskAdminService.GetCacheStats:output_type -> risk.GetCacheStatsResponse
+	28, // 44: risk.RiskAdminService.InvalidateCache:output_type -> risk.InvalidateCacheResponse
+	31, // 45: risk.RiskAdminService.ListFeatureFlags:output_type -> risk.ListFeatureFlagsResponse
+	33, // 46: risk.RiskAdminService.RecheckUsers:output_type -> risk.RecheckUsersResponse
+	36, // 47: risk.RiskAdminService.GetRiskHistory:output_type -> risk.GetRiskHistoryResponse
+	32, // [32:48] is the sub-list for method output_type
+	16, // [16:32] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_risk_risk_proto_init() }
@@ -1352,7 +2851,7 @@ func file_proto_risk_risk_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_risk_risk_proto_rawDesc), len(file_proto_risk_risk_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RiskService_CheckRisk_FullMethodName = "/risk.RiskService/CheckRisk"
+	RiskService_CheckRisk_FullMethodName       = "/risk.RiskService/CheckRisk"
+	RiskService_StreamCheckRisk_FullMethodName = "/risk.RiskService/StreamCheckRisk"
 )
 
 // RiskServiceClient is the client API for RiskService service.
@@ -27,6 +28,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RiskServiceClient interface {
 	CheckRisk(ctx context.Context, in *RiskCheckRequest, opts ...grpc.CallOption) (*RiskCheckResponse, error)
+	// One response per request, in request order.
+	StreamCheckRisk(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RiskCheckRequest, RiskCheckResponse], error)
 }
 
 type riskServiceClient struct {
@@ -47,11 +50,26 @@ func (c *riskServiceClient) CheckRisk(ctx context.Context, in *RiskCheckRequest,
 	return out, nil
 }
 
+func (c *riskServiceClient) StreamCheckRisk(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RiskCheckRequest, RiskCheckResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RiskService_ServiceDesc.Streams[0], RiskService_StreamCheckRisk_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RiskCheckRequest, RiskCheckResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RiskService_StreamCheckRiskClient = grpc.BidiStreamingClient[RiskCheckRequest, RiskCheckResponse]
+
 // RiskServiceServer is the server API for RiskService service.
 // All implementations must embed UnimplementedRiskServiceServer
 // for forward compatibility.
 type RiskServiceServer interface {
 	CheckRisk(context.Context, *RiskCheckRequest) (*RiskCheckResponse, error)
+	// One response per request, in request order.
+	StreamCheckRisk(grpc.BidiStreamingServer[RiskCheckRequest, RiskCheckResponse]) error
 	mustEmbedUnimplementedRiskServiceServer()
 }
 
@@ -65,6 +83,9 @@ type UnimplementedRiskServiceServer struct{}
 func (UnimplementedRiskServiceServer) CheckRisk(context.Context, *RiskCheckRequest) (*RiskCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckRisk not implemented")
 }
+func (UnimplementedRiskServiceServer) StreamCheckRisk(grpc.BidiStreamingServer[RiskCheckRequest, RiskCheckResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCheckRisk not implemented")
+}
 func (UnimplementedRiskServiceServer) mustEmbedUnimplementedRiskServiceServer() {}
 func (UnimplementedRiskServiceServer) testEmbeddedByValue()                     {}
 
@@ -104,6 +125,13 @@ func _RiskService_CheckRisk_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RiskService_StreamCheckRisk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RiskServiceServer).StreamCheckRisk(&grpc.GenericServerStream[RiskCheckRequest, RiskCheckResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RiskService_StreamCheckRiskServer = grpc.BidiStreamingServer[RiskCheckRequest, RiskCheckResponse]
+
 // RiskService_ServiceDesc is the grpc.ServiceDesc for RiskService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,16 +144,32 @@ var RiskService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _RiskService_CheckRisk_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCheckRisk",
+			Handler:       _RiskService_StreamCheckRisk_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "proto/risk/risk.proto",
 }
 
 const (
-	RiskAdminService_CreateRiskRule_FullMethodName = "/risk.RiskAdminService/CreateRiskRule"
-	RiskAdminService_UpdateRiskRule_FullMethodName = "/risk.RiskAdminService/UpdateRiskRule"
-	RiskAdminService_DeleteRiskRule_FullMethodName = "/risk.RiskAdminService/DeleteRiskRule"
-	RiskAdminService_ListRiskRules_FullMethodName  = "/risk.RiskAdminService/ListRiskRules"
-	RiskAdminService_GetRiskStats_FullMethodName   = "/risk.RiskAdminService/GetRiskStats"
+	RiskAdminService_CreateRiskRule_FullMethodName          = "/risk.RiskAdminService/CreateRiskRule"
+	RiskAdminService_CreateRiskRules_FullMethodName         = "/risk.RiskAdminService/CreateRiskRules"
+	RiskAdminService_UpdateRiskRule_FullMethodName          = "/risk.RiskAdminService/UpdateRiskRule"
+	RiskAdminService_DeleteRiskRule_FullMethodName          = "/risk.RiskAdminService/DeleteRiskRule"
+	RiskAdminService_ListRiskRules_FullMethodName           = "/risk.RiskAdminService/ListRiskRules"
+	RiskAdminService_GetRiskStats_FullMethodName            = "/risk.RiskAdminService/GetRiskStats"
+	RiskAdminService_ListUsersByRiskLevel_FullMethodName    = "/risk.RiskAdminService/ListUsersByRiskLevel"
+	RiskAdminService_ListDisposableDomains_FullMethodName   = "/risk.RiskAdminService/ListDisposableDomains"
+	RiskAdminService_UpdateDisposableDomains_FullMethodName = "/risk.RiskAdminService/UpdateDisposableDomains"
+	RiskAdminService_GetCacheStats_FullMethodName           = "/risk.RiskAdminService/GetCacheStats"
+	RiskAdminService_InvalidateCache_FullMethodName         = "/risk.RiskAdminService/InvalidateCache"
+	RiskAdminService_ListFeatureFlags_FullMethodName        = "/risk.RiskAdminService/ListFeatureFlags"
+	RiskAdminService_RecheckUsers_FullMethodName            = "/risk.RiskAdminService/RecheckUsers"
+	RiskAdminService_GetRiskHistory_FullMethodName          = "/risk.RiskAdminService/GetRiskHistory"
 )
 
 // RiskAdminServiceClient is the client API for RiskAdminService service.
@@ -135,10 +179,20 @@ const (
 // NEW: Admin service for managing rules
 type RiskAdminServiceClient interface {
 	CreateRiskRule(ctx context.Context, in *CreateRiskRuleRequest, opts ...grpc.CallOption) (*CreateRiskRuleResponse, error)
+	CreateRiskRules(ctx context.Context, in *CreateRiskRulesRequest, opts ...grpc.CallOption) (*CreateRiskRulesResponse, error)
 	UpdateRiskRule(ctx context.Context, in *UpdateRiskRuleRequest, opts ...grpc.CallOption) (*UpdateRiskRuleResponse, error)
 	DeleteRiskRule(ctx context.Context, in *DeleteRiskRuleRequest, opts ...grpc.CallOption) (*DeleteRiskRuleResponse, error)
 	ListRiskRules(ctx context.Context, in *ListRiskRulesRequest, opts ...grpc.CallOption) (*ListRiskRulesResponse, error)
 	GetRiskStats(ctx context.Context, in *GetRiskStatsRequest, opts ...grpc.CallOption) (*GetRiskStatsResponse, error)
+	ListUsersByRiskLevel(ctx context.Context, in *ListUsersByRiskLevelRequest, opts ...grpc.CallOption) (*ListUsersByRiskLevelResponse, error)
+	ListDisposableDomains(ctx context.Context, in *ListDisposableDomainsRequest, opts ...grpc.CallOption) (*ListDisposableDomainsResponse, error)
+	UpdateDisposableDomains(ctx context.Context, in *UpdateDisposableDomainsRequest, opts ...grpc.CallOption) (*UpdateDisposableDomainsResponse, error)
+	GetCacheStats(ctx context.Context, in *GetCacheStatsRequest, opts ...grpc.CallOption) (*GetCacheStatsResponse, error)
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error)
+	ListFeatureFlags(ctx context.Context, in *ListFeatureFlagsRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error)
+	// Re-runs risk checks for the organization's users matching a filter, e.g. after adding rules.
+	RecheckUsers(ctx context.Context, in *RecheckUsersRequest, opts ...grpc.CallOption) (*RecheckUsersResponse, error)
+	GetRiskHistory(ctx context.Context, in *GetRiskHistoryRequest, opts ...grpc.CallOption) (*GetRiskHistoryResponse, error)
 }
 
 type riskAdminServiceClient struct {
@@ -159,6 +213,16 @@ func (c *riskAdminServiceClient) CreateRiskRule(ctx context.Context, in *CreateR
 	return out, nil
 }
 
+func (c *riskAdminServiceClient) CreateRiskRules(ctx context.Context, in *CreateRiskRulesRequest, opts ...grpc.CallOption) (*CreateRiskRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRiskRulesResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_CreateRiskRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *riskAdminServiceClient) UpdateRiskRule(ctx context.Context, in *UpdateRiskRuleRequest, opts ...grpc.CallOption) (*UpdateRiskRuleResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateRiskRuleResponse)
@@ -199,6 +263,86 @@ func (c *riskAdminServiceClient) GetRiskStats(ctx context.Context, in *GetRiskSt
 	return out, nil
 }
 
+func (c *riskAdminServiceClient) ListUsersByRiskLevel(ctx context.Context, in *ListUsersByRiskLevelRequest, opts ...grpc.CallOption) (*ListUsersByRiskLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersByRiskLevelResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_ListUsersByRiskLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) ListDisposableDomains(ctx context.Context, in *ListDisposableDomainsRequest, opts ...grpc.CallOption) (*ListDisposableDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDisposableDomainsResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_ListDisposableDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) UpdateDisposableDomains(ctx context.Context, in *UpdateDisposableDomainsRequest, opts ...grpc.CallOption) (*UpdateDisposableDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateDisposableDomainsResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_UpdateDisposableDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) GetCacheStats(ctx context.Context, in *GetCacheStatsRequest, opts ...grpc.CallOption) (*GetCacheStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCacheStatsResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_GetCacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvalidateCacheResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_InvalidateCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) ListFeatureFlags(ctx context.Context, in *ListFeatureFlagsRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFeatureFlagsResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_ListFeatureFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) RecheckUsers(ctx context.Context, in *RecheckUsersRequest, opts ...grpc.CallOption) (*RecheckUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecheckUsersResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_RecheckUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *riskAdminServiceClient) GetRiskHistory(ctx context.Context, in *GetRiskHistoryRequest, opts ...grpc.CallOption) (*GetRiskHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRiskHistoryResponse)
+	err := c.cc.Invoke(ctx, RiskAdminService_GetRiskHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RiskAdminServiceServer is the server API for RiskAdminService service.
 // All implementations must embed UnimplementedRiskAdminServiceServer
 // for forward compatibility.
@@ -206,10 +350,20 @@ func (c *riskAdminServiceClient) GetRiskStats(ctx context.Context, in *GetRiskSt
 // NEW: Admin service for managing rules
 type RiskAdminServiceServer interface {
 	CreateRiskRule(context.Context, *CreateRiskRuleRequest) (*CreateRiskRuleResponse, error)
+	CreateRiskRules(context.Context, *CreateRiskRulesRequest) (*CreateRiskRulesResponse, error)
 	UpdateRiskRule(context.Context, *UpdateRiskRuleRequest) (*UpdateRiskRuleResponse, error)
 	DeleteRiskRule(context.Context, *DeleteRiskRuleRequest) (*DeleteRiskRuleResponse, error)
 	ListRiskRules(context.Context, *ListRiskRulesRequest) (*ListRiskRulesResponse, error)
 	GetRiskStats(context.Context, *GetRiskStatsRequest) (*GetRiskStatsResponse, error)
+	ListUsersByRiskLevel(context.Context, *ListUsersByRiskLevelRequest) (*ListUsersByRiskLevelResponse, error)
+	ListDisposableDomains(context.Context, *ListDisposableDomainsRequest) (*ListDisposableDomainsResponse, error)
+	UpdateDisposableDomains(context.Context, *UpdateDisposableDomainsRequest) (*UpdateDisposableDomainsResponse, error)
+	GetCacheStats(context.Context, *GetCacheStatsRequest) (*GetCacheStatsResponse, error)
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error)
+	ListFeatureFlags(context.Context, *ListFeatureFlagsRequest) (*ListFeatureFlagsResponse, error)
+	// Re-runs risk checks for the organization's users matching a filter, e.g. after adding rules.
+	RecheckUsers(context.Context, *RecheckUsersRequest) (*RecheckUsersResponse, error)
+	GetRiskHistory(context.Context, *GetRiskHistoryRequest) (*GetRiskHistoryResponse, error)
 	mustEmbedUnimplementedRiskAdminServiceServer()
 }
 
@@ -223,6 +377,9 @@ type UnimplementedRiskAdminServiceServer struct{}
 func (UnimplementedRiskAdminServiceServer) CreateRiskRule(context.Context, *CreateRiskRuleRequest) (*CreateRiskRuleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateRiskRule not implemented")
 }
+func (UnimplementedRiskAdminServiceServer) CreateRiskRules(context.Context, *CreateRiskRulesRequest) (*CreateRiskRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRiskRules not implemented")
+}
 func (UnimplementedRiskAdminServiceServer) UpdateRiskRule(context.Context, *UpdateRiskRuleRequest) (*UpdateRiskRuleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateRiskRule not implemented")
 }
@@ -235,6 +392,30 @@ func (UnimplementedRiskAdminServiceServer) ListRiskRules(context.Context, *ListR
 func (UnimplementedRiskAdminServiceServer) GetRiskStats(context.Context, *GetRiskStatsRequest) (*GetRiskStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRiskStats not implemented")
 }
+func (UnimplementedRiskAdminServiceServer) ListUsersByRiskLevel(context.Context, *ListUsersByRiskLevelRequest) (*ListUsersByRiskLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsersByRiskLevel not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) ListDisposableDomains(context.Context, *ListDisposableDomainsRequest) (*ListDisposableDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDisposableDomains not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) UpdateDisposableDomains(context.Context, *UpdateDisposableDomainsRequest) (*UpdateDisposableDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDisposableDomains not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) GetCacheStats(context.Context, *GetCacheStatsRequest) (*GetCacheStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCacheStats not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) ListFeatureFlags(context.Context, *ListFeatureFlagsRequest) (*ListFeatureFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeatureFlags not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) RecheckUsers(context.Context, *RecheckUsersRequest) (*RecheckUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecheckUsers not implemented")
+}
+func (UnimplementedRiskAdminServiceServer) GetRiskHistory(context.Context, *GetRiskHistoryRequest) (*GetRiskHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRiskHistory not implemented")
+}
 func (UnimplementedRiskAdminServiceServer) mustEmbedUnimplementedRiskAdminServiceServer() {}
 func (UnimplementedRiskAdminServiceServer) testEmbeddedByValue()                          {}
 
@@ -274,6 +455,24 @@ func _RiskAdminService_CreateRiskRule_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RiskAdminService_CreateRiskRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRiskRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).CreateRiskRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_CreateRiskRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).CreateRiskRules(ctx, req.(*CreateRiskRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RiskAdminService_UpdateRiskRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateRiskRuleRequest)
 	if err := dec(in); err != nil {
@@ -346,6 +545,150 @@ func _RiskAdminService_GetRiskStats_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RiskAdminService_ListUsersByRiskLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersByRiskLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).ListUsersByRiskLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_ListUsersByRiskLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).ListUsersByRiskLevel(ctx, req.(*ListUsersByRiskLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_ListDisposableDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDisposableDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).ListDisposableDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_ListDisposableDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).ListDisposableDomains(ctx, req.(*ListDisposableDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_UpdateDisposableDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDisposableDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).UpdateDisposableDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_UpdateDisposableDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).UpdateDisposableDomains(ctx, req.(*UpdateDisposableDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_GetCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCacheStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).GetCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_GetCacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).GetCacheStats(ctx, req.(*GetCacheStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_InvalidateCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_ListFeatureFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeatureFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).ListFeatureFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_ListFeatureFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).ListFeatureFlags(ctx, req.(*ListFeatureFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_RecheckUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecheckUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).RecheckUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_RecheckUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).RecheckUsers(ctx, req.(*RecheckUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RiskAdminService_GetRiskHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRiskHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskAdminServiceServer).GetRiskHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskAdminService_GetRiskHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskAdminServiceServer).GetRiskHistory(ctx, req.(*GetRiskHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RiskAdminService_ServiceDesc is the grpc.ServiceDesc for RiskAdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -357,6 +700,10 @@ var RiskAdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateRiskRule",
 			Handler:    _RiskAdminService_CreateRiskRule_Handler,
 		},
+		{
+			MethodName: "CreateRiskRules",
+			Handler:    _RiskAdminService_CreateRiskRules_Handler,
+		},
 		{
 			MethodName: "UpdateRiskRule",
 			Handler:    _RiskAdminService_UpdateRiskRule_Handler,
@@ -373,6 +720,38 @@ var RiskAdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRiskStats",
 			Handler:    _RiskAdminService_GetRiskStats_Handler,
 		},
+		{
+			MethodName: "ListUsersByRiskLevel",
+			Handler:    _RiskAdminService_ListUsersByRiskLevel_Handler,
+		},
+		{
+			MethodName: "ListDisposableDomains",
+			Handler:    _RiskAdminService_ListDisposableDomains_Handler,
+		},
+		{
+			MethodName: "UpdateDisposableDomains",
+			Handler:    _RiskAdminService_UpdateDisposableDomains_Handler,
+		},
+		{
+			MethodName: "GetCacheStats",
+			Handler:    _RiskAdminService_GetCacheStats_Handler,
+		},
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _RiskAdminService_InvalidateCache_Handler,
+		},
+		{
+			MethodName: "ListFeatureFlags",
+			Handler:    _RiskAdminService_ListFeatureFlags_Handler,
+		},
+		{
+			MethodName: "RecheckUsers",
+			Handler:    _RiskAdminService_RecheckUsers_Handler,
+		},
+		{
+			MethodName: "GetRiskHistory",
+			Handler:    _RiskAdminService_GetRiskHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/risk/risk.proto",
@@ -1,14 +1,20 @@
 CREATE DATABASE users;
 CREATE DATABASE risk_analytics;
+CREATE DATABASE notifications;
 
 CREATE USER risk_admin WITH PASSWORD 'risky_password';
 CREATE USER app_admin WITH PASSWORD 'app_password';
+CREATE USER notification_admin WITH PASSWORD 'notification_password';
 
 GRANT ALL PRIVILEGES ON DATABASE risk_analytics TO risk_admin;
 GRANT ALL PRIVILEGES ON DATABASE users TO app_admin;
+GRANT ALL PRIVILEGES ON DATABASE notifications TO notification_admin;
 
 \c risk_analytics;
 GRANT ALL ON SCHEMA public TO risk_admin;
 
 \c users;
 GRANT ALL ON SCHEMA public TO app_admin;
+
+\c notifications;
+GRANT ALL ON SCHEMA public TO notification_admin;
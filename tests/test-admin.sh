@@ -69,6 +69,31 @@ else
 fi
 echo ""
 
+echo "2b. Testing admin user lookup by email..."
+LOOKUP_RESPONSE=$(curl -s -X GET "http://localhost:8080/api/v1/users?email=$ADMIN_EMAIL" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+
+echo "Lookup Response: $LOOKUP_RESPONSE"
+LOOKUP_ID=$(echo "$LOOKUP_RESPONSE" | jq -r '.user.id')
+if [ "$LOOKUP_ID" = "$ADMIN_USER_ID" ]; then
+    echo "✅ User found by email"
+else
+    echo "❌ User lookup by email failed"
+    exit 1
+fi
+
+MISSING_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X GET "http://localhost:8080/api/v1/users?email=missing${TIMESTAMP}@example.com" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+
+echo "Missing User Status: $MISSING_STATUS"
+if [ "$MISSING_STATUS" = "404" ]; then
+    echo "✅ Unknown email returned 404"
+else
+    echo "❌ Unknown email lookup did not return 404"
+    exit 1
+fi
+echo ""
+
 echo "3. Testing risk rule creation (admin access)..."
 CREATE_RULE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
     -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
@@ -138,6 +163,106 @@ else
 fi
 echo ""
 
+echo "5b. Testing bulk risk rule creation..."
+BULK_RESPONSE=$(curl -s -w "\n%{http_code}" -X POST http://localhost:8080/api/v1/risk/rules/bulk \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"rules\":[
+        {\"name\":\"Bulk A ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"bulk-a-${TIMESTAMP}.com\",\"score\":50,\"is_active\":true},
+        {\"name\":\"Bulk B ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"bulk-b-${TIMESTAMP}.com\",\"score\":60,\"is_active\":true}
+    ]}")
+BULK_STATUS=$(echo "$BULK_RESPONSE" | tail -n1)
+BULK_BODY=$(echo "$BULK_RESPONSE" | sed '$d')
+
+echo "Bulk Create Response: $BULK_BODY"
+if [ "$BULK_STATUS" = "201" ] && [ "$(echo "$BULK_BODY" | jq -r '.created')" = "2" ]; then
+    echo "✅ Bulk rule creation successful"
+    BULK_RULE_IDS=$(echo "$BULK_BODY" | jq -r '.results[].rule_id')
+else
+    echo "❌ Bulk rule creation failed (status $BULK_STATUS)"
+    exit 1
+fi
+echo ""
+
+echo "5c. Testing all-or-nothing bulk creation with one invalid rule..."
+ATOMIC_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/risk/rules/bulk \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"rules\":[
+        {\"name\":\"Bulk Atomic ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"bulk-atomic-${TIMESTAMP}.com\",\"score\":50,\"is_active\":true},
+        {\"name\":\"Bulk Invalid ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"\",\"score\":5000,\"is_active\":true}
+    ]}")
+ATOMIC_LIST=$(curl -s -X GET http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+
+if [ "$ATOMIC_STATUS" = "400" ] && ! echo "$ATOMIC_LIST" | grep -q "Bulk Atomic ${TIMESTAMP}"; then
+    echo "✅ Invalid batch rejected and nothing was created"
+else
+    echo "❌ All-or-nothing bulk creation did not roll back (status $ATOMIC_STATUS)"
+    exit 1
+fi
+echo ""
+
+echo "5d. Testing partial bulk creation with one invalid rule..."
+PARTIAL_RESPONSE=$(curl -s -w "\n%{http_code}" -X POST http://localhost:8080/api/v1/risk/rules/bulk \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"partial\":true,\"rules\":[
+        {\"name\":\"Bulk Partial ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"bulk-partial-${TIMESTAMP}.com\",\"score\":50,\"is_active\":true},
+        {\"name\":\"\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"bulk-missing-name.com\",\"score\":50,\"is_active\":true}
+    ]}")
+PARTIAL_STATUS=$(echo "$PARTIAL_RESPONSE" | tail -n1)
+PARTIAL_BODY=$(echo "$PARTIAL_RESPONSE" | sed '$d')
+
+echo "Partial Create Response: $PARTIAL_BODY"
+if [ "$PARTIAL_STATUS" = "207" ] && [ "$(echo "$PARTIAL_BODY" | jq -r '.created')" = "1" ] \
+    && [ "$(echo "$PARTIAL_BODY" | jq -r '.results[1].success')" = "false" ]; then
+    echo "✅ Partial bulk creation reported per-rule results"
+    BULK_RULE_IDS="$BULK_RULE_IDS $(echo "$PARTIAL_BODY" | jq -r '.results[0].rule_id')"
+else
+    echo "❌ Partial bulk creation failed (status $PARTIAL_STATUS)"
+    exit 1
+fi
+
+for BULK_RULE_ID in $BULK_RULE_IDS; do
+    curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$BULK_RULE_ID \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+done
+echo ""
+
+echo "5e. Testing rule examples are checked on creation..."
+EXAMPLE_RESPONSE=$(curl -s -w "\n%{http_code}" -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"name\":\"Example Rule ${TIMESTAMP}\",\"type\":\"PATTERN_MATCH\",\"category\":\"EMAIL\",\"value\":\"^probe[0-9]+@\",\"score\":40,\"is_active\":true,\"example_match\":\"probe42@example.com\",\"example_no_match\":\"alice@example.com\"}")
+EXAMPLE_STATUS=$(echo "$EXAMPLE_RESPONSE" | tail -n1)
+EXAMPLE_BODY=$(echo "$EXAMPLE_RESPONSE" | sed '$d')
+
+if [ "$EXAMPLE_STATUS" = "201" ]; then
+    echo "✅ Rule with consistent examples accepted"
+    curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$(echo "$EXAMPLE_BODY" | jq -r '.rule_id') \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+else
+    echo "❌ Rule with consistent examples rejected (status $EXAMPLE_STATUS): $EXAMPLE_BODY"
+    exit 1
+fi
+
+CONTRADICT_RESPONSE=$(curl -s -w "\n%{http_code}" -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"name\":\"Broken Example Rule ${TIMESTAMP}\",\"type\":\"PATTERN_MATCH\",\"category\":\"EMAIL\",\"value\":\"^probe[0-9]+@\",\"score\":40,\"is_active\":true,\"example_match\":\"probe-x@example.com\"}")
+CONTRADICT_STATUS=$(echo "$CONTRADICT_RESPONSE" | tail -n1)
+CONTRADICT_BODY=$(echo "$CONTRADICT_RESPONSE" | sed '$d')
+
+echo "Contradicting Example Response: $CONTRADICT_BODY"
+if [ "$CONTRADICT_STATUS" = "400" ] && echo "$CONTRADICT_BODY" | grep -q "example_match"; then
+    echo "✅ Rule whose examples contradict its pattern rejected"
+else
+    echo "❌ Rule with contradicting examples was not rejected (status $CONTRADICT_STATUS)"
+    exit 1
+fi
+echo ""
+
 echo "6. Creating regular user for access control testing..."
 USER_REGISTER_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
     -H "Content-Type: application/json" \
@@ -192,9 +317,11 @@ echo ""
 echo "📊 Test Summary:"
 echo "   ✅ Admin user creation and authentication"
 echo "   ✅ Admin user creation endpoint"
+echo "   ✅ User lookup by email"
 echo "   ✅ Risk rule creation (admin only)"
 echo "   ✅ Risk rules listing"
 echo "   ✅ Risk rule updates"
+echo "   ✅ Bulk rule creation (all-or-nothing and partial)"
 echo "   ✅ Access control (non-admin blocked)"
 echo "   ✅ Risk rule deletion"
 echo ""
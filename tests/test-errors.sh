@@ -96,4 +96,98 @@ else
 fi
 echo ""
 
+# Test 6: Non-JSON Content-Type
+echo "6. Testing non-JSON content type on login..."
+CONTENT_TYPE_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/auth/login \
+    -H "Content-Type: application/x-www-form-urlencoded" \
+    -d "email=$CHECKER_EMAIL&password=checkpass123")
+
+echo "Non-JSON Content-Type Status: $CONTENT_TYPE_STATUS"
+if [ "$CONTENT_TYPE_STATUS" = "415" ]; then
+    echo "✅ Non-JSON content type properly rejected"
+else
+    echo "❌ Non-JSON content type not properly handled"
+    exit 1
+fi
+echo ""
+
+# Test 7: Login Failure Statuses
+echo "7. Testing login with a wrong password..."
+WRONG_PASSWORD_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/auth/login \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"$CHECKER_EMAIL\",\"password\":\"wrongpass123\"}")
+
+echo "Wrong Password Status: $WRONG_PASSWORD_STATUS"
+if [ "$WRONG_PASSWORD_STATUS" = "401" ]; then
+    echo "✅ Wrong password returned 401"
+else
+    echo "❌ Wrong password not properly handled"
+    exit 1
+fi
+echo ""
+
+echo "7b. Testing login to a deactivated account..."
+CHECKER_ID=$(echo "$REGISTER_RESPONSE" | jq -r '.user.id')
+PGPASSWORD="app_password" psql -h localhost -U app_admin -d users -c "
+UPDATE users SET is_active = false WHERE id = '$CHECKER_ID';
+" > /dev/null 2>&1
+
+INACTIVE_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/auth/login \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"$CHECKER_EMAIL\",\"password\":\"checkpass123\"}")
+
+echo "Inactive Account Status: $INACTIVE_STATUS"
+if [ "$INACTIVE_STATUS" = "403" ]; then
+    echo "✅ Deactivated account returned 403"
+else
+    echo "❌ Deactivated account not properly handled"
+    exit 1
+fi
+echo ""
+
+echo "8. Testing validation errors carry machine-readable codes..."
+# expect_validation_code RESPONSE FIELD CODE checks FIELD failed with CODE
+expect_validation_code() {
+    local code
+    code=$(echo "$1" | jq -r --arg field "$2" '[.validation_errors[]? | select(.field == $field) | .code] | first // empty')
+    if [ "$code" = "$3" ]; then
+        echo "✅ $2 reported $3"
+    else
+        echo "❌ Expected $2 to report $3, got '${code}': $1"
+        exit 1
+    fi
+}
+
+REQUIRED_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"required${TIMESTAMP}@example.com\",\"first_name\":\"No\",\"last_name\":\"Password\"}")
+expect_validation_code "$REQUIRED_RESPONSE" "password" "REQUIRED"
+
+INVALID_EMAIL_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d '{"email":"not-an-email","password":"pass12345678","first_name":"Bad","last_name":"Email"}')
+expect_validation_code "$INVALID_EMAIL_RESPONSE" "email" "INVALID_EMAIL"
+
+expect_validation_code "$WEAK_PASSWORD_RESPONSE" "password" "TOO_SHORT"
+
+CODES_REGISTER=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"codes${TIMESTAMP}@example.com\",\"password\":\"codespass123\",\"first_name\":\"Code\",\"last_name\":\"Checker\"}")
+CODES_TOKEN=$(echo "$CODES_REGISTER" | jq -r '.access_token')
+CODES_USER_ID=$(echo "$CODES_REGISTER" | jq -r '.user.id')
+
+INVALID_PHONE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $CODES_TOKEN" \
+    -d "{\"user_id\":\"$CODES_USER_ID\",\"email\":\"codes${TIMESTAMP}@example.com\",\"first_name\":\"Code\",\"last_name\":\"Checker\",\"phone\":\"not-a-phone\"}")
+expect_validation_code "$INVALID_PHONE_RESPONSE" "phone" "INVALID_PHONE"
+
+if [ "$(echo "$INVALID_PHONE_RESPONSE" | jq -r '.validation_errors[0].message')" = "must be a valid phone number" ]; then
+    echo "✅ Human-readable message kept alongside the code"
+else
+    echo "❌ Validation message missing: $INVALID_PHONE_RESPONSE"
+    exit 1
+fi
+echo ""
+
 echo "🚫 Error handling tests completed successfully!"
@@ -38,6 +38,37 @@ else
 fi
 echo ""
 
+# Test 3: Notification throttling
+echo "3. Sending repeated risk alerts for one user..."
+THROTTLE_USER="throttle-${TIMESTAMP}"
+THROTTLE_SENDS=8
+for i in $(seq 1 $THROTTLE_SENDS); do
+    EVENT="{\"version\":2,\"event_id\":\"throttle-${TIMESTAMP}-${i}\",\"user_id\":\"$THROTTLE_USER\",\"email\":\"throttle${TIMESTAMP}@example.com\",\"risk_level\":\"HIGH\",\"reason\":\"Repeated failed logins\",\"flags\":[\"LOGIN_FAILURES\"]}"
+    curl -s -o /dev/null -u guest:guest -X POST http://localhost:15672/api/exchanges/%2F/amq.default/publish \
+        -H "Content-Type: application/json" \
+        -d "{\"properties\":{},\"routing_key\":\"risk.detected\",\"payload\":$(echo "$EVENT" | jq -Rs .),\"payload_encoding\":\"string\"}"
+done
+
+# Consumers process the queue asynchronously
+sleep 3
+if command -v docker > /dev/null 2>&1 && docker inspect notification-service > /dev/null 2>&1; then
+    THROTTLE_LIMIT=$(docker exec notification-service printenv NOTIFICATION_THROTTLE_LIMIT 2>/dev/null || echo "5")
+    THROTTLED=$(docker logs notification-service 2>&1 | grep "Notification throttled" | grep -c "$THROTTLE_USER" || true)
+    echo "Throttled $THROTTLED of $THROTTLE_SENDS alerts (limit $THROTTLE_LIMIT)"
+
+    if [ "$THROTTLE_LIMIT" = "0" ]; then
+        echo "⚠️ Notification throttling is disabled, skipping throttle check"
+    elif [ "$THROTTLED" = "$((THROTTLE_SENDS - THROTTLE_LIMIT))" ]; then
+        echo "✅ Alerts beyond the per-user limit were throttled"
+    else
+        echo "❌ Expected $((THROTTLE_SENDS - THROTTLE_LIMIT)) throttled alerts"
+        exit 1
+    fi
+else
+    echo "⚠️ notification-service container not available, skipping throttle check"
+fi
+echo ""
+
 echo "🚨 Risk detection tests completed successfully!"
 echo ""
 echo "💡 Note: To verify risk detection is working properly:"
@@ -218,6 +218,984 @@ else
 fi
 echo ""
 
+echo "8b. Testing that two pattern rules produce distinct flags..."
+PATTERN_RULE_IDS=""
+for PATTERN in "^pattern-a" "pattern-.*@"; do
+    PATTERN_RULE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{
+            \"name\": \"Pattern ${PATTERN} ${TIMESTAMP}\",
+            \"type\": \"PATTERN_MATCH\",
+            \"category\": \"EMAIL\",
+            \"value\": \"${PATTERN}\",
+            \"score\": 10,
+            \"is_active\": true,
+            \"confidence\": 1.0
+        }")
+    PATTERN_RULE_IDS="$PATTERN_RULE_IDS $(echo "$PATTERN_RULE_RESPONSE" | jq -r '.rule_id')"
+done
+
+PATTERN_CHECK_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{
+        \"user_id\": \"pattern-user-${TIMESTAMP}\",
+        \"email\": \"pattern-a${TIMESTAMP}@example.com\",
+        \"first_name\": \"Pattern\",
+        \"last_name\": \"User\"
+    }")
+
+echo "Pattern Check Response: $PATTERN_CHECK_RESPONSE"
+PATTERN_FLAGS=$(echo "$PATTERN_CHECK_RESPONSE" | jq '[.flags[] | select(startswith("EMAIL_PATTERN_MATCH"))] | unique | length')
+if [ "$PATTERN_FLAGS" = "2" ]; then
+    echo "✅ Each pattern rule produced its own flag"
+else
+    echo "❌ Expected 2 distinct pattern flags, got $PATTERN_FLAGS"
+    exit 1
+fi
+
+for PATTERN_RULE_ID in $PATTERN_RULE_IDS; do
+    curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$PATTERN_RULE_ID \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+done
+echo ""
+
+echo "8c. Testing accent-insensitive name matching..."
+ACCENT_RULE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{
+        \"name\": \"Accent Test ${TIMESTAMP}\",
+        \"type\": \"LAST_NAME_BLACKLIST\",
+        \"category\": \"NAME\",
+        \"value\": \"garcia${TIMESTAMP}\",
+        \"score\": 10,
+        \"is_active\": true,
+        \"confidence\": 1.0
+    }")
+ACCENT_RULE_ID=$(echo "$ACCENT_RULE_RESPONSE" | jq -r '.rule_id')
+
+for LAST_NAME in "García${TIMESTAMP}" "ＧＡＲＣＩＡ${TIMESTAMP}"; do
+    ACCENT_CHECK_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+        -H "Authorization: Bearer $USER_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{
+            \"user_id\": \"accent-user-${TIMESTAMP}\",
+            \"email\": \"accent${TIMESTAMP}@example.com\",
+            \"first_name\": \"José\",
+            \"last_name\": \"${LAST_NAME}\"
+        }")
+    if echo "$ACCENT_CHECK_RESPONSE" | jq -e '.flags | any(startswith("NAME_LAST_NAME_BLACKLIST"))' > /dev/null; then
+        echo "✅ $LAST_NAME matched the unaccented rule"
+    else
+        echo "❌ $LAST_NAME did not match the unaccented rule: $ACCENT_CHECK_RESPONSE"
+        exit 1
+    fi
+done
+
+# Cyrillic "а" is a different letter, not an accented one, so normalization must not fold it
+HOMOGLYPH_CHECK_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{
+        \"user_id\": \"accent-user-${TIMESTAMP}\",
+        \"email\": \"accent${TIMESTAMP}@example.com\",
+        \"first_name\": \"José\",
+        \"last_name\": \"gаrcia${TIMESTAMP}\"
+    }")
+if echo "$HOMOGLYPH_CHECK_RESPONSE" | jq -e '.flags | any(startswith("NAME_LAST_NAME_BLACKLIST"))' > /dev/null; then
+    echo "❌ Cyrillic homoglyph was folded by accent normalization"
+    exit 1
+else
+    echo "✅ Cyrillic homoglyph left distinct by accent normalization"
+fi
+
+curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$ACCENT_RULE_ID \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+echo ""
+
+echo "8d. Testing confusable (homoglyph) matching..."
+CONFUSABLE_RULE_IDS=""
+for CATEGORY_VALUE in "EMAIL:paypal${TIMESTAMP}" "NAME:smith${TIMESTAMP}"; do
+    CONFUSABLE_RULE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{
+            \"name\": \"Confusable ${CATEGORY_VALUE}\",
+            \"type\": \"CONFUSABLE_MATCH\",
+            \"category\": \"${CATEGORY_VALUE%%:*}\",
+            \"value\": \"${CATEGORY_VALUE#*:}\",
+            \"score\": 10,
+            \"is_active\": true,
+            \"confidence\": 1.0
+        }")
+    CONFUSABLE_RULE_IDS="$CONFUSABLE_RULE_IDS $(echo "$CONFUSABLE_RULE_RESPONSE" | jq -r '.rule_id')"
+done
+
+# Emails must be ASCII to pass gateway validation, so the email uses "1" for "l";
+# the last name uses Cyrillic "ѕ" and Greek "ι"
+check_confusable() {
+    curl -s -X POST http://localhost:8080/api/v1/risk/check \
+        -H "Authorization: Bearer $USER_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{
+            \"user_id\": \"confusable-user-${TIMESTAMP}\",
+            \"email\": \"$1\",
+            \"first_name\": \"Test\",
+            \"last_name\": \"$2\"
+        }" | jq -r '[.flags[] | select(startswith("EMAIL_CONFUSABLE_MATCH") or startswith("NAME_CONFUSABLE_MATCH"))] | length'
+}
+
+LOOKALIKE_FLAGS=$(check_confusable "support@paypa1${TIMESTAMP}.com" "ѕmιth${TIMESTAMP}")
+PLAIN_FLAGS=$(check_confusable "support@paypal${TIMESTAMP}.com" "smith${TIMESTAMP}")
+if [ "$LOOKALIKE_FLAGS" = "2" ] && [ "$PLAIN_FLAGS" = "0" ]; then
+    echo "✅ Lookalike email and name flagged, plain spellings left to CONTAINS rules"
+else
+    echo "❌ Confusable matching failed (lookalike flags: $LOOKALIKE_FLAGS, plain flags: $PLAIN_FLAGS)"
+    exit 1
+fi
+
+for CONFUSABLE_RULE_ID in $CONFUSABLE_RULE_IDS; do
+    curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$CONFUSABLE_RULE_ID \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+done
+echo ""
+
+echo "8e. Testing disposable email domain detection..."
+DISPOSABLE_RULE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{
+        \"name\": \"Disposable Email ${TIMESTAMP}\",
+        \"type\": \"DISPOSABLE_EMAIL\",
+        \"category\": \"EMAIL\",
+        \"score\": 10,
+        \"is_active\": true,
+        \"confidence\": 1.0
+    }")
+DISPOSABLE_RULE_ID=$(echo "$DISPOSABLE_RULE_RESPONSE" | jq -r '.rule_id')
+
+check_disposable() {
+    curl -s -X POST http://localhost:8080/api/v1/risk/check \
+        -H "Authorization: Bearer $USER_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{
+            \"user_id\": \"disposable-user-${TIMESTAMP}\",
+            \"email\": \"$1\",
+            \"first_name\": \"Test\",
+            \"last_name\": \"User\"
+        }" | jq -r '[.flags[] | select(startswith("EMAIL_DISPOSABLE_EMAIL"))] | length'
+}
+
+if [ "$(check_disposable "throwaway${TIMESTAMP}@mailinator.com")" = "1" ] \
+    && [ "$(check_disposable "real${TIMESTAMP}@example.com")" = "0" ]; then
+    echo "✅ Disposable domain flagged, legitimate domain not flagged"
+else
+    echo "❌ Disposable domain detection failed"
+    exit 1
+fi
+
+CUSTOM_DOMAIN="throwaway${TIMESTAMP}.example"
+curl -s -o /dev/null -X PATCH http://localhost:8080/api/v1/risk/disposable-domains \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"add\": [\"$CUSTOM_DOMAIN\"]}"
+
+if [ "$(check_disposable "user@$CUSTOM_DOMAIN")" = "1" ]; then
+    echo "✅ Domain added via admin endpoint is flagged"
+else
+    echo "❌ Domain added via admin endpoint was not flagged"
+    exit 1
+fi
+
+curl -s -o /dev/null -X PATCH http://localhost:8080/api/v1/risk/disposable-domains \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"remove\": [\"$CUSTOM_DOMAIN\"]}"
+curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$DISPOSABLE_RULE_ID \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+echo ""
+
+echo "8f. Testing rule cache invalidation forces a reload on the next check..."
+curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/cache/invalidate \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+INVALIDATED_STATS=$(curl -s -X GET http://localhost:8080/api/v1/risk/cache \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+echo "Stats after invalidate: $INVALIDATED_STATS"
+
+curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"cache-user-${TIMESTAMP}\", \"email\": \"cacheuser${TIMESTAMP}@example.com\", \"first_name\": \"Cache\", \"last_name\": \"User\"}"
+RELOADED_STATS=$(curl -s -X GET http://localhost:8080/api/v1/risk/cache \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+echo "Stats after check: $RELOADED_STATS"
+
+if [ "$(echo "$INVALIDATED_STATS" | jq -r '.last_updated')" = "null" ] \
+    && [ "$(echo "$RELOADED_STATS" | jq -r '.last_updated')" != "null" ] \
+    && [ "$(echo "$RELOADED_STATS" | jq -r '.rule_counts.EMAIL // 0')" -gt 0 ]; then
+    echo "✅ Invalidated cache reloaded on the next check"
+else
+    echo "❌ Rule cache was not reloaded after invalidation"
+    exit 1
+fi
+
+CACHE_FORBIDDEN=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/risk/cache/invalidate \
+    -H "Authorization: Bearer $USER_JWT_TOKEN")
+if [ "$CACHE_FORBIDDEN" = "403" ]; then
+    echo "✅ Cache endpoints are admin-only"
+else
+    echo "❌ Regular user reached cache invalidation (status $CACHE_FORBIDDEN)"
+    exit 1
+fi
+echo ""
+
+echo "8g. Testing rules are isolated between organizations..."
+ORG_B_EMAIL="orgbadmin${TIMESTAMP}@example.com"
+ORG_B_ID=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"$ORG_B_EMAIL\",\"password\":\"adminpass123\",\"first_name\":\"OrgB\",\"last_name\":\"Admin\",\"phone\":\"+1555000009\"}" | jq -r '.user.id')
+
+PGPASSWORD="app_password" psql -h localhost -U app_admin -d users -c "
+UPDATE users SET roles = '[\"admin\"]', org_id = 'org-b-${TIMESTAMP}' WHERE id = '$ORG_B_ID';
+" > /dev/null 2>&1
+
+ORG_B_TOKEN=$(curl -s -X POST http://localhost:8080/api/v1/auth/login \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"$ORG_B_EMAIL\",\"password\":\"adminpass123\"}" | jq -r '.access_token')
+
+ORG_B_RULE_ID=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ORG_B_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"name\":\"Org B Domain ${TIMESTAMP}\",\"type\":\"DOMAIN_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"org-b-${TIMESTAMP}.com\",\"score\":90,\"is_active\":true}" | jq -r '.rule_id')
+
+ORG_A_RULES=$(curl -s -X GET http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+ORG_A_DELETE=$(curl -s -o /dev/null -w "%{http_code}" -X DELETE http://localhost:8080/api/v1/risk/rules/$ORG_B_RULE_ID \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+ORG_A_CHECK=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"org-a-${TIMESTAMP}\", \"email\": \"someone@org-b-${TIMESTAMP}.com\", \"first_name\": \"Org\", \"last_name\": \"A\"}")
+ORG_B_CHECK=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $ORG_B_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"org-b-${TIMESTAMP}\", \"email\": \"someone@org-b-${TIMESTAMP}.com\", \"first_name\": \"Org\", \"last_name\": \"B\"}")
+echo "Org A check: $ORG_A_CHECK"
+echo "Org B check: $ORG_B_CHECK"
+
+if [ "$ORG_B_RULE_ID" != "null" ] && [ -n "$ORG_B_RULE_ID" ] \
+    && ! echo "$ORG_A_RULES" | grep -q "Org B Domain ${TIMESTAMP}" \
+    && [ "${ORG_A_DELETE:0:1}" != "2" ] \
+    && [ "$(echo "$ORG_A_CHECK" | jq -r '.is_risky')" = "false" ] \
+    && [ "$(echo "$ORG_B_CHECK" | jq -r '.is_risky')" = "true" ]; then
+    echo "✅ Org B's rule is invisible to and not applied in org A"
+else
+    echo "❌ Rules leaked across organizations (delete status $ORG_A_DELETE)"
+    exit 1
+fi
+
+curl -s -o /dev/null -X DELETE http://localhost:8080/api/v1/risk/rules/$ORG_B_RULE_ID \
+    -H "Authorization: Bearer $ORG_B_TOKEN"
+echo ""
+
+echo "8h. Testing feature flag bucketing and allowlist override..."
+ALLOWLISTED_FLAGS=$(curl -s -X GET "http://localhost:8080/api/v1/risk/features?user_id=rollout-preview-user" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+BUCKETED_FIRST=$(curl -s -X GET "http://localhost:8080/api/v1/risk/features?user_id=$USER_ID" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+BUCKETED_SECOND=$(curl -s -X GET "http://localhost:8080/api/v1/risk/features?user_id=$USER_ID" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+echo "Feature flags for allowlisted user: $ALLOWLISTED_FLAGS"
+
+FLAG_FILTER='.flags[] | select(.name == "diminishing_scoring") | .enabled'
+if [ "$(echo "$ALLOWLISTED_FLAGS" | jq -r "$FLAG_FILTER")" = "true" ] \
+    && [ "$(echo "$BUCKETED_FIRST" | jq -r "$FLAG_FILTER")" = "false" ] \
+    && [ "$(echo "$BUCKETED_FIRST" | jq -c '.flags')" = "$(echo "$BUCKETED_SECOND" | jq -c '.flags')" ]; then
+    echo "✅ Allowlist overrides the 0% rollout and bucketing is stable per user"
+else
+    echo "❌ Feature flag evaluation is wrong or unstable"
+    exit 1
+fi
+
+FEATURES_FORBIDDEN=$(curl -s -o /dev/null -w "%{http_code}" -X GET http://localhost:8080/api/v1/risk/features \
+    -H "Authorization: Bearer $USER_JWT_TOKEN")
+if [ "$FEATURES_FORBIDDEN" = "403" ]; then
+    echo "✅ Feature flags are admin-only"
+else
+    echo "❌ Regular user read feature flags (status $FEATURES_FORBIDDEN)"
+    exit 1
+fi
+echo ""
+
+echo "8i. Testing dry-run risk checks are not stored..."
+DRY_RUN_USER="dry-run-${TIMESTAMP}"
+STORED_USER="stored-run-${TIMESTAMP}"
+DRY_RUN_RESPONSE=$(curl -s -X POST "http://localhost:8080/api/v1/risk/check?dry_run=true" \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"$DRY_RUN_USER\", \"email\": \"dryrun${TIMESTAMP}@suspicious-domain.com\", \"first_name\": \"Dry\", \"last_name\": \"Run\"}")
+curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"$STORED_USER\", \"email\": \"storedrun${TIMESTAMP}@suspicious-domain.com\", \"first_name\": \"Stored\", \"last_name\": \"Run\"}"
+echo "Dry Run Response: $DRY_RUN_RESPONSE"
+
+# Results are stored asynchronously, give the write time to land
+sleep 2
+count_results() {
+    PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+        "SELECT COUNT(*) FROM risk_check_results WHERE user_id = '$1';" 2>/dev/null
+}
+DRY_RUN_ROWS=$(count_results "$DRY_RUN_USER")
+STORED_ROWS=$(count_results "$STORED_USER")
+
+if [ "$(echo "$DRY_RUN_RESPONSE" | jq -r '.dry_run')" = "true" ] \
+    && [ "$(echo "$DRY_RUN_RESPONSE" | jq -r '.is_risky')" = "true" ] \
+    && [ "$DRY_RUN_ROWS" = "0" ] && [ "$STORED_ROWS" = "1" ]; then
+    echo "✅ Dry run returned a result without writing analytics"
+else
+    echo "❌ Dry run wrote analytics or was not reported (dry run rows: $DRY_RUN_ROWS, stored rows: $STORED_ROWS)"
+    exit 1
+fi
+
+DRY_RUN_INVALID=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:8080/api/v1/risk/check?dry_run=maybe" \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"user_id\": \"$DRY_RUN_USER\", \"email\": \"dryrun${TIMESTAMP}@example.com\", \"first_name\": \"Dry\", \"last_name\": \"Run\"}")
+if [ "$DRY_RUN_INVALID" = "400" ]; then
+    echo "✅ Invalid dry_run value rejected"
+else
+    echo "❌ Invalid dry_run value accepted (status $DRY_RUN_INVALID)"
+    exit 1
+fi
+echo ""
+
+echo "8j. Testing matched rule details stay out of info level logs..."
+if command -v docker > /dev/null 2>&1 && docker inspect risk-engine > /dev/null 2>&1; then
+    LOG_SINCE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+    curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/check \
+        -H "Authorization: Bearer $USER_JWT_TOKEN" \
+        -H "Content-Type: application/json" \
+        -d "{\"user_id\": \"log-level-${TIMESTAMP}\", \"email\": \"loglevel${TIMESTAMP}@suspicious-domain.com\", \"first_name\": \"Log\", \"last_name\": \"Level\"}"
+    sleep 1
+    ENGINE_LOGS=$(docker logs --since "$LOG_SINCE" risk-engine 2>&1)
+    ENGINE_LOG_LEVEL=$(docker exec risk-engine printenv LOG_LEVEL 2>/dev/null || echo "info")
+
+    if [ "$ENGINE_LOG_LEVEL" = "debug" ]; then
+        echo "⚠️ Risk engine runs at debug level, skipping info level log check"
+    elif echo "$ENGINE_LOGS" | grep -q "matched_categories" && ! echo "$ENGINE_LOGS" | grep -q "Risk rule matched"; then
+        echo "✅ Info logs carry the per-check summary without matched rule details"
+    else
+        echo "❌ Matched rule details were logged at info level"
+        exit 1
+    fi
+else
+    echo "⚠️ risk-engine container not available, skipping log level check"
+fi
+echo ""
+
+echo "8k. Testing risk notifications carry the triggering check ID..."
+CORRELATION_REGISTER=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"correlation${TIMESTAMP}@suspicious-domain.com\",\"password\":\"userpass123\",\"first_name\":\"Corr\",\"last_name\":\"Elation\"}")
+CORRELATION_USER_ID=$(echo "$CORRELATION_REGISTER" | jq -r '.user.id')
+
+# Risk check and notification run after registration returns
+sleep 3
+CORRELATION_CHECK_ID=$(PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+    "SELECT check_id FROM risk_check_results WHERE user_id = '$CORRELATION_USER_ID' ORDER BY checked_at DESC LIMIT 1;" 2>/dev/null)
+echo "Engine check ID: $CORRELATION_CHECK_ID"
+
+if [ -z "$CORRELATION_CHECK_ID" ]; then
+    echo "❌ No stored risk check found for user $CORRELATION_USER_ID"
+    exit 1
+fi
+if command -v docker > /dev/null 2>&1 && docker inspect notification-service > /dev/null 2>&1; then
+    if docker logs notification-service 2>&1 | grep "RISK_DETECTED" | grep -q "$CORRELATION_CHECK_ID"; then
+        echo "✅ RISK_DETECTED notification references the engine's check ID"
+    else
+        echo "❌ Check ID did not reach the notification service"
+        exit 1
+    fi
+else
+    echo "⚠️ notification-service container not available, skipping log correlation check"
+fi
+echo ""
+
+echo "8l. Testing synchronous check-and-notify..."
+RISKY_NOTIFY_REGISTER=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"checknotify${TIMESTAMP}@suspicious-domain.com\",\"password\":\"userpass123\",\"first_name\":\"Check\",\"last_name\":\"Notify\"}")
+RISKY_NOTIFY_USER_ID=$(echo "$RISKY_NOTIFY_REGISTER" | jq -r '.user.id')
+
+RISKY_NOTIFY_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/users/$RISKY_NOTIFY_USER_ID/check-and-notify \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+echo "Risky Check And Notify Response: $RISKY_NOTIFY_RESPONSE"
+
+if [ "$(echo "$RISKY_NOTIFY_RESPONSE" | jq -r '.is_risky')" = "true" ] && \
+   [ "$(echo "$RISKY_NOTIFY_RESPONSE" | jq '.notifications | length')" -gt 0 ]; then
+    echo "✅ Risky user was notified synchronously with per-channel results"
+else
+    echo "❌ Risky user did not get a reported notification"
+    exit 1
+fi
+
+CLEAN_NOTIFY_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/users/$USER_ID/check-and-notify \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+echo "Clean Check And Notify Response: $CLEAN_NOTIFY_RESPONSE"
+
+if [ "$(echo "$CLEAN_NOTIFY_RESPONSE" | jq -r '.is_risky')" = "false" ] && \
+   [ "$(echo "$CLEAN_NOTIFY_RESPONSE" | jq -r '.notified')" = "false" ] && \
+   [ "$(echo "$CLEAN_NOTIFY_RESPONSE" | jq '.notifications | length')" = "0" ]; then
+    echo "✅ Clean user was checked without sending a notification"
+else
+    echo "❌ Clean user should not be notified"
+    exit 1
+fi
+
+NON_ADMIN_NOTIFY_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/users/$USER_ID/check-and-notify \
+    -H "Authorization: Bearer $USER_JWT_TOKEN")
+if [ "$NON_ADMIN_NOTIFY_STATUS" = "403" ]; then
+    echo "✅ Check-and-notify requires admin access"
+else
+    echo "❌ Expected 403 for a regular user, got $NON_ADMIN_NOTIFY_STATUS"
+    exit 1
+fi
+echo ""
+
+echo "8m. Testing rule validation errors carry range codes..."
+RANGE_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -d '{"name":"Out Of Range","type":"DOMAIN_BLACKLIST","category":"EMAIL","value":"range.example","score":0,"priority":5000}')
+echo "Out Of Range Rule Response: $RANGE_RESPONSE"
+
+SCORE_CODE=$(echo "$RANGE_RESPONSE" | jq -r '[.validation_errors[]? | select(.field == "score") | .code] | first // empty')
+PRIORITY_CODE=$(echo "$RANGE_RESPONSE" | jq -r '[.validation_errors[]? | select(.field == "priority") | .code] | first // empty')
+if [ "$SCORE_CODE" = "TOO_SMALL" ] && [ "$PRIORITY_CODE" = "TOO_LARGE" ]; then
+    echo "✅ Score below range reported TOO_SMALL, priority above range reported TOO_LARGE"
+else
+    echo "❌ Expected TOO_SMALL/TOO_LARGE, got '${SCORE_CODE}'/'${PRIORITY_CODE}'"
+    exit 1
+fi
+echo ""
+
+echo "8n. Testing PATTERN_MATCH values must be valid regular expressions..."
+INVALID_PATTERN_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -d '{"name":"Broken Pattern","type":"PATTERN_MATCH","category":"EMAIL","value":"([a-z","score":20}')
+echo "Invalid Pattern Response: $INVALID_PATTERN_RESPONSE"
+
+if [ "$(echo "$INVALID_PATTERN_RESPONSE" | jq -r '[.validation_errors[]? | select(.field == "value") | .code] | first // empty')" = "INVALID_REGEX" ]; then
+    echo "✅ Invalid pattern rejected at create time"
+else
+    echo "❌ Invalid pattern was not rejected"
+    exit 1
+fi
+
+VALID_PATTERN_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -d "{\"name\":\"Valid Pattern ${TIMESTAMP}\",\"type\":\"PATTERN_MATCH\",\"category\":\"EMAIL\",\"value\":\"^pattern-${TIMESTAMP}-[0-9]+@\",\"score\":20}")
+PATTERN_RULE_ID=$(echo "$VALID_PATTERN_RESPONSE" | jq -r '.rule_id // empty')
+
+if [ -n "$PATTERN_RULE_ID" ]; then
+    echo "✅ Valid pattern accepted"
+else
+    echo "❌ Valid pattern was rejected: $VALID_PATTERN_RESPONSE"
+    exit 1
+fi
+
+INVALID_UPDATE_RESPONSE=$(curl -s -X PUT http://localhost:8080/api/v1/risk/rules/$PATTERN_RULE_ID \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -d '{"name":"Valid Pattern","type":"PATTERN_MATCH","category":"EMAIL","value":"a{2,1}","score":20,"is_active":true}')
+
+if [ "$(echo "$INVALID_UPDATE_RESPONSE" | jq -r '[.validation_errors[]? | select(.field == "value") | .code] | first // empty')" = "INVALID_REGEX" ]; then
+    echo "✅ Invalid pattern rejected on update"
+else
+    echo "❌ Update with an invalid pattern was not rejected: $INVALID_UPDATE_RESPONSE"
+    exit 1
+fi
+
+curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$PATTERN_RULE_ID \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" > /dev/null
+echo ""
+
+echo "8o. Testing expires_in_days range validation..."
+# create_expiring_rule DAYS creates a contains rule expiring in DAYS and prints the response
+create_expiring_rule() {
+    curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+        -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+        -d "{\"name\":\"Expiry $1 ${TIMESTAMP}\",\"type\":\"CONTAINS\",\"category\":\"EMAIL\",\"value\":\"expiry-$1-${TIMESTAMP}\",\"score\":20,\"is_active\":true,\"expires_in_days\":$1}"
+}
+
+NEGATIVE_EXPIRY_RESPONSE=$(create_expiring_rule -1)
+if [ "$(echo "$NEGATIVE_EXPIRY_RESPONSE" | jq -r '[.validation_errors[]? | select(.field == "expires_in_days") | .code] | first // empty')" = "TOO_SMALL" ]; then
+    echo "✅ Negative expires_in_days rejected"
+else
+    echo "❌ Negative expires_in_days was accepted: $NEGATIVE_EXPIRY_RESPONSE"
+    exit 1
+fi
+
+OVER_MAX_EXPIRY_RESPONSE=$(create_expiring_rule 100000)
+if echo "$OVER_MAX_EXPIRY_RESPONSE" | jq -r '.error' | grep -q "expires_in_days"; then
+    echo "✅ expires_in_days above the maximum rejected"
+else
+    echo "❌ expires_in_days above the maximum was accepted: $OVER_MAX_EXPIRY_RESPONSE"
+    exit 1
+fi
+
+PERMANENT_RULE_ID=$(create_expiring_rule 0 | jq -r '.rule_id // empty')
+EXPIRING_RULE_ID=$(create_expiring_rule 30 | jq -r '.rule_id // empty')
+if [ -z "$PERMANENT_RULE_ID" ] || [ -z "$EXPIRING_RULE_ID" ]; then
+    echo "❌ Rules with expires_in_days 0 and 30 should be created"
+    exit 1
+fi
+
+EXPIRY_RULES=$(curl -s -X GET http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+PERMANENT_EXPIRES_AT=$(echo "$EXPIRY_RULES" | jq -r --arg id "$PERMANENT_RULE_ID" '.rules[] | select(.id == $id) | .expires_at')
+EXPIRING_EXPIRES_AT=$(echo "$EXPIRY_RULES" | jq -r --arg id "$EXPIRING_RULE_ID" '.rules[] | select(.id == $id) | .expires_at')
+if [ "$PERMANENT_EXPIRES_AT" = "null" ] && [ "$EXPIRING_EXPIRES_AT" != "null" ]; then
+    echo "✅ expires_in_days 0 is permanent and 30 sets an expiry"
+else
+    echo "❌ Unexpected expiry, permanent=$PERMANENT_EXPIRES_AT expiring=$EXPIRING_EXPIRES_AT"
+    exit 1
+fi
+
+for EXPIRY_RULE_ID in $PERMANENT_RULE_ID $EXPIRING_RULE_ID; do
+    curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$EXPIRY_RULE_ID \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN" > /dev/null
+done
+echo ""
+
+echo "8p. Testing timestamps are RFC3339 across endpoints..."
+RFC3339_PATTERN='^[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})$'
+
+RULES_FOR_TIMESTAMPS=$(curl -s -X GET http://localhost:8080/api/v1/risk/rules \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+PROFILE_FOR_TIMESTAMPS=$(curl -s -X GET http://localhost:8080/api/v1/profile \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+curl -s -X POST http://localhost:8080/api/v1/risk/check \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -d "{\"user_id\":\"$USER_ID\",\"email\":\"regularuser${TIMESTAMP}@example.com\",\"first_name\":\"Regular\",\"last_name\":\"User\"}" > /dev/null
+CACHE_FOR_TIMESTAMPS=$(curl -s -X GET http://localhost:8080/api/v1/risk/cache \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+
+for TIMESTAMP_FIELD in \
+    "rule created_at=$(echo "$RULES_FOR_TIMESTAMPS" | jq -r '.rules[0].created_at')" \
+    "rule updated_at=$(echo "$RULES_FOR_TIMESTAMPS" | jq -r '.rules[0].updated_at')" \
+    "user created_at=$(echo "$PROFILE_FOR_TIMESTAMPS" | jq -r '.user.created_at // .created_at')" \
+    "cache last_updated=$(echo "$CACHE_FOR_TIMESTAMPS" | jq -r '.last_updated')"; do
+    if echo "${TIMESTAMP_FIELD#*=}" | grep -Eq "$RFC3339_PATTERN"; then
+        echo "✅ ${TIMESTAMP_FIELD%%=*} is RFC3339: ${TIMESTAMP_FIELD#*=}"
+    else
+        echo "❌ ${TIMESTAMP_FIELD%%=*} is not RFC3339: ${TIMESTAMP_FIELD#*=}"
+        exit 1
+    fi
+done
+echo ""
+
+echo "8q. Testing a failed rule cache refresh degrades the risk service health..."
+if command -v docker > /dev/null 2>&1 && docker inspect risk-engine > /dev/null 2>&1; then
+    # risk_service_serving exits 0 while risk.RiskService reports SERVING
+    risk_service_serving() {
+        docker exec risk-engine grpc_health_probe -addr=:50052 -service=risk.RiskService > /dev/null 2>&1
+    }
+    # wait_for_risk_service WANT polls the health probe until it reports WANT (serving or not_serving)
+    wait_for_risk_service() {
+        for _ in $(seq 1 15); do
+            if risk_service_serving; then STATE=serving; else STATE=not_serving; fi
+            [ "$STATE" = "$1" ] && return 0
+            sleep 2
+        done
+        return 1
+    }
+    restore_risk_rules() {
+        PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+            "ALTER TABLE IF EXISTS risk_rules_unavailable RENAME TO risk_rules;" > /dev/null 2>&1
+    }
+    trigger_risk_check() {
+        curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/check \
+            -H "Content-Type: application/json" \
+            -H "Authorization: Bearer $USER_JWT_TOKEN" \
+            -d "{\"user_id\":\"$USER_ID\",\"email\":\"regularuser${TIMESTAMP}@example.com\",\"first_name\":\"Regular\",\"last_name\":\"User\"}"
+    }
+
+    # Hide the rules table so the next refresh fails, then force one
+    PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+        "ALTER TABLE risk_rules RENAME TO risk_rules_unavailable;" > /dev/null
+    curl -s -o /dev/null -X POST http://localhost:8080/api/v1/risk/cache/invalidate \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+    trigger_risk_check
+
+    if wait_for_risk_service not_serving; then
+        echo "✅ Failed refresh marked risk.RiskService NOT_SERVING"
+    else
+        restore_risk_rules
+        echo "❌ Risk service stayed SERVING after a failed rule refresh"
+        exit 1
+    fi
+
+    restore_risk_rules
+    trigger_risk_check
+
+    if wait_for_risk_service serving; then
+        echo "✅ Successful refresh restored risk.RiskService to SERVING"
+    else
+        echo "❌ Risk service did not recover after the rules table came back"
+        exit 1
+    fi
+else
+    echo "⚠️ risk-engine container not available, skipping health status check"
+fi
+echo ""
+
+echo "8r. Testing a failed analytics write is retried until it persists..."
+if command -v psql > /dev/null 2>&1; then
+    risk_db() {
+        PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc "$1"
+    }
+    restore_check_results() {
+        risk_db "ALTER TABLE IF EXISTS risk_check_results_unavailable RENAME TO risk_check_results;" > /dev/null 2>&1
+    }
+
+    # Hide the results table so the first write of the next check fails
+    risk_db "ALTER TABLE risk_check_results RENAME TO risk_check_results_unavailable;" > /dev/null
+    RETRY_CHECK_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/risk/check \
+      -H "Content-Type: application/json" \
+      -H "Authorization: Bearer $USER_JWT_TOKEN" \
+      -d "{\"user_id\":\"$USER_ID\",\"email\":\"regularuser${TIMESTAMP}@example.com\",\"first_name\":\"Regular\",\"last_name\":\"User\"}")
+    RETRY_CHECK_ID=$(echo "$RETRY_CHECK_RESPONSE" | jq -r '.check_id // empty')
+    sleep 2
+    restore_check_results
+
+    if [ -z "$RETRY_CHECK_ID" ]; then
+        echo "❌ Risk check during the analytics outage failed: $RETRY_CHECK_RESPONSE"
+        exit 1
+    fi
+
+    STORED=""
+    for _ in $(seq 1 15); do
+        STORED=$(risk_db "SELECT count(*) FROM risk_check_results WHERE check_id = '$RETRY_CHECK_ID' AND user_id = '$USER_ID';")
+        [ "$STORED" = "1" ] && break
+        sleep 2
+    done
+
+    if [ "$STORED" = "1" ]; then
+        echo "✅ Check $RETRY_CHECK_ID was stored once the results table came back"
+    else
+        echo "❌ Check $RETRY_CHECK_ID was never stored after the analytics outage"
+        exit 1
+    fi
+else
+    echo "⚠️ psql not available, skipping analytics retry check"
+fi
+echo ""
+
+echo "8s. Testing analytics sampling keeps risky checks and 1 in N clean checks..."
+if command -v psql > /dev/null 2>&1; then
+    SAMPLE_RATE=1
+    if command -v docker > /dev/null 2>&1 && docker inspect risk-engine > /dev/null 2>&1; then
+        SAMPLE_RATE=$(docker exec risk-engine printenv ANALYTICS_SAMPLE_RATE 2>/dev/null || echo 1)
+    fi
+    SAMPLED_USER_ID="sampled-user-${TIMESTAMP}"
+    sampled_check() {
+        curl -s -X POST http://localhost:8080/api/v1/risk/check \
+          -H "Content-Type: application/json" \
+          -H "Authorization: Bearer $USER_JWT_TOKEN" \
+          -d "{\"user_id\":\"$SAMPLED_USER_ID\",\"email\":\"$1\",\"first_name\":\"Sampled\",\"last_name\":\"User\"}"
+    }
+    sampled_rows() {
+        PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+            "SELECT count(*) || ':' || COALESCE(sum(sample_weight), 0) FROM risk_check_results WHERE user_id = '$SAMPLED_USER_ID' AND is_risky = $1;"
+    }
+
+    if [ "$SAMPLE_RATE" -gt 20 ]; then
+        echo "⚠️ ANALYTICS_SAMPLE_RATE=$SAMPLE_RATE needs too many checks for the rate limit, skipping sampling check"
+    else
+        # Two full sampling periods of clean checks keep exactly two results, each standing for SAMPLE_RATE checks
+        for _ in $(seq 1 $((2 * SAMPLE_RATE))); do
+            sampled_check "sampled${TIMESTAMP}@example.com" > /dev/null
+        done
+        RISKY_SAMPLED=$(sampled_check "$TEST_USER_EMAIL" | jq -r '.is_risky')
+        sleep 2
+
+        CLEAN_ROWS=$(sampled_rows false)
+        if [ "$CLEAN_ROWS" = "2:$((2 * SAMPLE_RATE))" ]; then
+            echo "✅ $((2 * SAMPLE_RATE)) clean checks at rate $SAMPLE_RATE stored as 2 results weighted $SAMPLE_RATE"
+        else
+            echo "❌ Clean checks at rate $SAMPLE_RATE stored as count:weight $CLEAN_ROWS"
+            exit 1
+        fi
+
+        if [ "$RISKY_SAMPLED" = "true" ]; then
+            if [ "$(sampled_rows true)" = "1:1" ]; then
+                echo "✅ Risky check stored regardless of sampling"
+            else
+                echo "❌ Risky check was not stored once with weight 1: $(sampled_rows true)"
+                exit 1
+            fi
+        else
+            echo "⚠️ $TEST_USER_EMAIL not flagged as risky, skipping risky sampling check"
+        fi
+    fi
+else
+    echo "⚠️ psql not available, skipping analytics sampling check"
+fi
+echo ""
+
+echo "8t. Testing a cohort recheck reports users flagged by a new rule..."
+COHORT_DOMAIN="cohort${TIMESTAMP}.example.com"
+COHORT_IDS=()
+for COHORT_MEMBER in 1 2 3; do
+    COHORT_REGISTER=$(curl -s -X POST http://localhost:8080/api/v1/auth/register \
+        -H "Content-Type: application/json" \
+        -d "{\"email\":\"member${COHORT_MEMBER}@${COHORT_DOMAIN}\",\"password\":\"userpass123\",\"first_name\":\"Cohort\",\"last_name\":\"Member\"}")
+    COHORT_IDS+=("$(echo "$COHORT_REGISTER" | jq -r '.user.id')")
+done
+# Registration checks are stored in the background
+sleep 3
+
+COHORT_RULE_ID=$(curl -s -X POST http://localhost:8080/api/v1/risk/rules \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+    -d "{\"name\":\"Cohort Blacklist\",\"type\":\"EMAIL_BLACKLIST\",\"category\":\"EMAIL\",\"value\":\"member2@${COHORT_DOMAIN}\",\"score\":100,\"is_active\":true}" | jq -r '.rule_id')
+
+# recheck_cohort LIMIT OFFSET rechecks the cohort domain
+recheck_cohort() {
+    curl -s -X POST http://localhost:8080/api/v1/risk/recheck \
+        -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_JWT_TOKEN" \
+        -d "{\"email_domain\":\"$COHORT_DOMAIN\",\"limit\":$1,\"offset\":$2}"
+}
+
+# Two users per call, so the cohort of three needs a resumed second call
+FIRST_RECHECK=$(recheck_cohort 2 0)
+echo "First Recheck Response: $FIRST_RECHECK"
+SECOND_RECHECK=$(recheck_cohort 2 "$(echo "$FIRST_RECHECK" | jq -r '.next_offset')")
+echo "Second Recheck Response: $SECOND_RECHECK"
+
+RECHECKED=$(( $(echo "$FIRST_RECHECK" | jq -r '.checked') + $(echo "$SECOND_RECHECK" | jq -r '.checked') ))
+NEWLY_RISKY=$(echo "$FIRST_RECHECK $SECOND_RECHECK" | jq -rs '[.[].newly_risky_user_ids[]] | join(",")')
+if [ "$(echo "$FIRST_RECHECK" | jq -r '.done')" = "false" ] && \
+   [ "$(echo "$SECOND_RECHECK" | jq -r '.done')" = "true" ] && \
+   [ "$RECHECKED" = "3" ] && [ "$NEWLY_RISKY" = "${COHORT_IDS[1]}" ]; then
+    echo "✅ Resumed recheck covered the cohort and reported only the blacklisted user as newly risky"
+else
+    echo "❌ Expected 3 rechecked users with only ${COHORT_IDS[1]} newly risky, got $RECHECKED and '$NEWLY_RISKY'"
+    curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$COHORT_RULE_ID -H "Authorization: Bearer $ADMIN_JWT_TOKEN" > /dev/null
+    exit 1
+fi
+
+# The rechecks were stored, so the flagged user is no longer new
+sleep 2
+REPEAT_RECHECK=$(recheck_cohort 100 0)
+if [ "$(echo "$REPEAT_RECHECK" | jq -r '.risky')" = "1" ] && [ "$(echo "$REPEAT_RECHECK" | jq -r '.newly_risky')" = "0" ]; then
+    echo "✅ Repeated recheck no longer reports the user as newly risky"
+else
+    echo "❌ Repeated recheck response: $REPEAT_RECHECK"
+    curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$COHORT_RULE_ID -H "Authorization: Bearer $ADMIN_JWT_TOKEN" > /dev/null
+    exit 1
+fi
+
+NON_ADMIN_RECHECK_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/risk/recheck \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $USER_JWT_TOKEN" \
+    -d '{}')
+curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$COHORT_RULE_ID -H "Authorization: Bearer $ADMIN_JWT_TOKEN" > /dev/null
+if [ "$NON_ADMIN_RECHECK_STATUS" = "403" ]; then
+    echo "✅ Recheck requires admin access"
+else
+    echo "❌ Expected 403 for a regular user, got $NON_ADMIN_RECHECK_STATUS"
+    exit 1
+fi
+echo ""
+
+echo "8u. Testing a risk level increase publishes risk.level_changed..."
+if command -v psql > /dev/null 2>&1; then
+    LEVEL_USER_ID="level-user-${TIMESTAMP}"
+    # level_check EMAIL checks the level test user with the given email and prints the risk level
+    level_check() {
+        curl -s -X POST http://localhost:8080/api/v1/risk/check \
+          -H "Content-Type: application/json" \
+          -H "Authorization: Bearer $USER_JWT_TOKEN" \
+          -d "{\"user_id\":\"$LEVEL_USER_ID\",\"email\":\"$1\",\"first_name\":\"Level\",\"last_name\":\"User\"}" | jq -r '.risk_level'
+    }
+    # level_events prints old->new for every stored event of the level test user
+    level_events() {
+        PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tAc \
+            "SELECT string_agg((convert_from(payload, 'UTF8')::jsonb->>'old_level') || '->' || (convert_from(payload, 'UTF8')::jsonb->>'new_level'), ',' ORDER BY id)
+             FROM outbox WHERE queue = 'risk.level_changed' AND convert_from(payload, 'UTF8')::jsonb->>'user_id' = '$LEVEL_USER_ID';"
+    }
+
+    CLEAN_LEVEL=$(level_check "level${TIMESTAMP}@example.com")
+    sleep 1
+    RISKY_LEVEL=$(level_check "$TEST_USER_EMAIL")
+    sleep 1
+    # A decrease is not published by default
+    level_check "level${TIMESTAMP}@example.com" > /dev/null
+    sleep 3
+
+    LEVEL_EVENTS=$(level_events)
+    if [ "$CLEAN_LEVEL" = "$RISKY_LEVEL" ]; then
+        echo "⚠️ $TEST_USER_EMAIL did not raise the risk level, skipping level change check"
+    elif [ "$LEVEL_EVENTS" = "${CLEAN_LEVEL}->${RISKY_LEVEL}" ]; then
+        echo "✅ Raising the level from $CLEAN_LEVEL to $RISKY_LEVEL published one risk.level_changed event"
+    else
+        echo "❌ Expected a single ${CLEAN_LEVEL}->${RISKY_LEVEL} event, got '$LEVEL_EVENTS'"
+        exit 1
+    fi
+
+    if command -v docker > /dev/null 2>&1 && docker inspect notification-service > /dev/null 2>&1 && [ -n "$LEVEL_EVENTS" ]; then
+        if docker logs notification-service 2>&1 | grep "Processing risk level changed event" | grep -q "$LEVEL_USER_ID"; then
+            echo "✅ Notification service received the risk.level_changed event"
+        else
+            echo "❌ risk.level_changed event did not reach the notification service"
+            exit 1
+        fi
+    fi
+else
+    echo "⚠️ psql not available, skipping risk level change check"
+fi
+echo ""
+
+echo "8v. Testing risk history filtering and paging..."
+HISTORY_USER_ID="history-user-${TIMESTAMP}"
+# history_check EMAIL checks the history test user with the given email and prints the check ID
+history_check() {
+    curl -s -X POST http://localhost:8080/api/v1/risk/check \
+      -H "Content-Type: application/json" \
+      -H "Authorization: Bearer $USER_JWT_TOKEN" \
+      -d "{\"user_id\":\"$HISTORY_USER_ID\",\"email\":\"$1\",\"first_name\":\"History\",\"last_name\":\"User\"}" | jq -r '.check_id'
+}
+# history QUERY prints the history page of the test user
+history() {
+    curl -s "http://localhost:8080/api/v1/risk/history/${HISTORY_USER_ID}?$1" \
+      -H "Authorization: Bearer $ADMIN_JWT_TOKEN"
+}
+
+FIRST_HISTORY_CHECK=$(history_check "history${TIMESTAMP}@example.com")
+sleep 1
+history_check "$TEST_USER_EMAIL" > /dev/null
+sleep 1
+LAST_HISTORY_CHECK=$(history_check "history${TIMESTAMP}@example.com")
+# Checks are stored in the background
+sleep 2
+
+HISTORY=$(history "")
+if [ "$(echo "$HISTORY" | jq -r '.checks | length')" = "3" ] && \
+   [ "$(echo "$HISTORY" | jq -r '.checks[0].check_id')" = "$LAST_HISTORY_CHECK" ] && \
+   [ "$(echo "$HISTORY" | jq -r '.next_cursor')" = "" ]; then
+    echo "✅ History lists all three checks, newest first"
+else
+    echo "❌ Unexpected history: $HISTORY"
+    exit 1
+fi
+
+RISKY_HISTORY_LEVEL=$(echo "$HISTORY" | jq -r '.checks[1].risk_level')
+if [ "$RISKY_HISTORY_LEVEL" = "$(echo "$HISTORY" | jq -r '.checks[0].risk_level')" ]; then
+    echo "⚠️ $TEST_USER_EMAIL did not change the risk level, skipping level filter check"
+else
+    LEVEL_HISTORY=$(history "risk_level=${RISKY_HISTORY_LEVEL}")
+    if [ "$(echo "$LEVEL_HISTORY" | jq -r '.checks | length')" = "1" ] && \
+       [ "$(echo "$LEVEL_HISTORY" | jq -r '.checks[0].risk_level')" = "$RISKY_HISTORY_LEVEL" ]; then
+        echo "✅ Filtering by $RISKY_HISTORY_LEVEL returned only the risky check"
+    else
+        echo "❌ Expected one $RISKY_HISTORY_LEVEL check, got: $LEVEL_HISTORY"
+        exit 1
+    fi
+fi
+
+FIRST_PAGE=$(history "limit=2")
+NEXT_CURSOR=$(echo "$FIRST_PAGE" | jq -r '.next_cursor')
+SECOND_PAGE=$(history "limit=2&cursor=${NEXT_CURSOR}")
+if [ "$(echo "$FIRST_PAGE" | jq -r '.checks | length')" = "2" ] && [ -n "$NEXT_CURSOR" ] && \
+   [ "$(echo "$SECOND_PAGE" | jq -r '.checks | length')" = "1" ] && \
+   [ "$(echo "$SECOND_PAGE" | jq -r '.checks[0].check_id')" = "$FIRST_HISTORY_CHECK" ] && \
+   [ "$(echo "$SECOND_PAGE" | jq -r '.next_cursor')" = "" ]; then
+    echo "✅ Paging with limit=2 returned 2 checks, then the remaining one"
+else
+    echo "❌ Unexpected pages: $FIRST_PAGE / $SECOND_PAGE"
+    exit 1
+fi
+
+OLDEST_HISTORY=$(history "sort=oldest&limit=1")
+if [ "$(echo "$OLDEST_HISTORY" | jq -r '.checks[0].check_id')" = "$FIRST_HISTORY_CHECK" ]; then
+    echo "✅ sort=oldest starts with the first check"
+else
+    echo "❌ Unexpected oldest-first history: $OLDEST_HISTORY"
+    exit 1
+fi
+
+INVALID_CURSOR_STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:8080/api/v1/risk/history/${HISTORY_USER_ID}?cursor=not-a-cursor" \
+    -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
+NON_ADMIN_HISTORY_STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:8080/api/v1/risk/history/${HISTORY_USER_ID}" \
+    -H "Authorization: Bearer $USER_JWT_TOKEN")
+if [ "$INVALID_CURSOR_STATUS" = "400" ] && [ "$NON_ADMIN_HISTORY_STATUS" = "403" ]; then
+    echo "✅ Invalid cursors are rejected and history requires admin access"
+else
+    echo "❌ Expected 400 and 403, got $INVALID_CURSOR_STATUS and $NON_ADMIN_HISTORY_STATUS"
+    exit 1
+fi
+echo ""
+
+echo "8w. Testing request fields reach the logs through the gRPC interceptor..."
+if command -v docker > /dev/null 2>&1 && docker inspect risk-engine > /dev/null 2>&1; then
+    CONTEXT_USER_ID="context-user-${TIMESTAMP}"
+    curl -s -X POST http://localhost:8080/api/v1/risk/check \
+      -H "Content-Type: application/json" \
+      -H "Authorization: Bearer $USER_JWT_TOKEN" \
+      -d "{\"user_id\":\"$CONTEXT_USER_ID\",\"email\":\"context${TIMESTAMP}@example.com\",\"first_name\":\"Context\",\"last_name\":\"User\"}" > /dev/null
+    sleep 1
+
+    # The context fields come from the interceptor, handlers no longer add them
+    if docker logs risk-engine 2>&1 | grep "Checking risk for user" | grep "$CONTEXT_USER_ID" | grep -q "user_email"; then
+        echo "✅ Risk engine logs carry the request's user_email from the interceptor"
+    else
+        echo "❌ Risk engine log for $CONTEXT_USER_ID is missing user_email"
+        exit 1
+    fi
+
+    if docker logs user-service 2>&1 | grep "Registration attempt for email" | grep -q "user_email"; then
+        echo "✅ User service registration logs carry user_email from the interceptor"
+    else
+        echo "❌ User service registration log is missing user_email"
+        exit 1
+    fi
+else
+    echo "⚠️ Docker or the risk-engine container not available, skipping interceptor log check"
+fi
+echo ""
+
+echo "8x. Testing analytics queries use the composite indexes..."
+if command -v psql > /dev/null 2>&1; then
+    # Test datasets are small enough for sequential scans, disable them to see which index the planner picks
+    STATS_PLAN=$(PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tA \
+        -c "SET enable_seqscan = off" \
+        -c "EXPLAIN SELECT DATE(checked_at), SUM(sample_weight) FROM risk_check_results WHERE org_id = 'default' AND checked_at >= now() - interval '7 days' GROUP BY DATE(checked_at)")
+    FLAGS_PLAN=$(PGPASSWORD="risky_password" psql -h localhost -U risk_admin -d risk_analytics -tA \
+        -c "SET enable_seqscan = off" \
+        -c "EXPLAIN SELECT rcf.flag, COUNT(*) FROM risk_check_flags rcf JOIN risk_check_results rcr ON rcf.check_id = rcr.check_id WHERE rcr.org_id = 'default' AND rcr.checked_at >= now() - interval '7 days' GROUP BY rcf.flag")
+    if echo "$STATS_PLAN" | grep -q "idx_risk_check_results_org_checked" && echo "$FLAGS_PLAN" | grep -q "idx_risk_check_flags_check_id"; then
+        echo "✅ Trend and flag queries use the org_id, checked_at and check_id indexes"
+    else
+        echo "❌ Analytics query plans don't use the expected indexes"
+        echo "$STATS_PLAN"
+        echo "$FLAGS_PLAN"
+        exit 1
+    fi
+else
+    echo "⚠️ psql not available, skipping analytics index check"
+fi
+echo ""
+
 echo "9. Cleaning up test rules..."
 DELETE_RESPONSE=$(curl -s -X DELETE http://localhost:8080/api/v1/risk/rules/$RULE_ID \
     -H "Authorization: Bearer $ADMIN_JWT_TOKEN")
@@ -253,6 +1231,10 @@ echo "   ✅ Risk checking for clean users"
 echo "   ✅ Risk checking for suspicious users"
 echo "   ✅ Multiple risk rule types"
 echo "   ✅ Multi-factor risk detection"
+echo "   ✅ Distinct flags per pattern rule"
+echo "   ✅ Accent-insensitive name matching"
+echo "   ✅ Confusable (homoglyph) matching"
+echo "   ✅ Disposable email domain detection"
 echo "   ✅ Test rule cleanup"
 echo ""
 echo "💡 Note: This test focuses on risk detection functionality."
@@ -36,6 +36,24 @@ else
 fi
 echo ""
 
+# Test 2b: Conditional Profile Request
+echo "2b. Testing conditional profile request with ETag..."
+PROFILE_ETAG=$(curl -s -o /dev/null -D - -X GET http://localhost:8080/api/v1/profile \
+    -H "Authorization: Bearer $JWT_TOKEN" | grep -i "^etag:" | cut -d' ' -f2 | tr -d '\r')
+
+CONDITIONAL_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X GET http://localhost:8080/api/v1/profile \
+    -H "Authorization: Bearer $JWT_TOKEN" \
+    -H "If-None-Match: $PROFILE_ETAG")
+
+echo "ETag: $PROFILE_ETAG, Conditional Status: $CONDITIONAL_STATUS"
+if [ -n "$PROFILE_ETAG" ] && [ "$CONDITIONAL_STATUS" = "304" ]; then
+    echo "✅ Unchanged profile returned 304 Not Modified"
+else
+    echo "❌ Conditional profile request failed"
+    exit 1
+fi
+echo ""
+
 # Test 3: User Data Retrieval
 echo "3. Testing user data retrieval..."
 USER_RESPONSE=$(curl -s -X GET http://localhost:8080/api/v1/users/$USER_ID \
@@ -66,4 +84,140 @@ else
 fi
 echo ""
 
+# Test 5: Partial Update
+echo "5. Testing partial update that clears the phone..."
+PATCH_RESPONSE=$(curl -s -X PATCH http://localhost:8080/api/v1/users/$USER_ID \
+    -H "Authorization: Bearer $JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d '{"phone":""}')
+
+echo "Patch Response: $PATCH_RESPONSE"
+if echo "$PATCH_RESPONSE" | grep -q "Updated" && ! echo "$PATCH_RESPONSE" | grep -q "+1555999888"; then
+    echo "✅ Partial update kept the name and cleared the phone"
+else
+    echo "❌ Partial update failed"
+    exit 1
+fi
+echo ""
+
+# Test 6: Email Change
+echo "6. Testing email change request..."
+NEW_EMAIL="usertest${TIMESTAMP}.new@example.com"
+CHANGE_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/users/$USER_ID/email \
+    -H "Authorization: Bearer $JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"new_email\":\"$NEW_EMAIL\"}")
+
+PROFILE_EMAIL=$(curl -s -X GET http://localhost:8080/api/v1/profile \
+    -H "Authorization: Bearer $JWT_TOKEN" | jq -r '.email')
+
+echo "Change Status: $CHANGE_STATUS, Current Email: $PROFILE_EMAIL"
+if [ "$CHANGE_STATUS" = "202" ] && [ "$PROFILE_EMAIL" = "$TEST_EMAIL" ]; then
+    echo "✅ Email change pending, old email still active"
+else
+    echo "❌ Email change request failed"
+    exit 1
+fi
+echo ""
+
+echo "6b. Testing email change to an address already in use..."
+TAKEN_EMAIL="usertest${TIMESTAMP}.taken@example.com"
+curl -s -X POST http://localhost:8080/api/v1/auth/register \
+    -H "Content-Type: application/json" \
+    -d "{\"email\":\"$TAKEN_EMAIL\",\"password\":\"userpass123\",\"first_name\":\"Taken\",\"last_name\":\"User\"}" > /dev/null
+
+DUPLICATE_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/users/$USER_ID/email \
+    -H "Authorization: Bearer $JWT_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d "{\"new_email\":\"$TAKEN_EMAIL\"}")
+
+echo "Duplicate Status: $DUPLICATE_STATUS"
+if [ "$DUPLICATE_STATUS" = "409" ]; then
+    echo "✅ Email change to a taken address rejected"
+else
+    echo "❌ Email change to a taken address not rejected"
+    exit 1
+fi
+echo ""
+
+echo "6c. Testing email change confirmation with an invalid token..."
+CONFIRM_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/auth/email/confirm \
+    -H "Content-Type: application/json" \
+    -d '{"token":"not-a-real-token"}')
+
+echo "Confirm Status: $CONFIRM_STATUS"
+if [ "$CONFIRM_STATUS" = "401" ]; then
+    echo "✅ Invalid confirmation token rejected"
+else
+    echo "❌ Invalid confirmation token not rejected"
+    exit 1
+fi
+echo ""
+
+# Test 7: Sessions
+echo "7. Testing session listing..."
+LOGIN_RESPONSE=$(curl -s -X POST http://localhost:8080/api/v1/auth/login \
+    -H "Content-Type: application/json" \
+    -H "User-Agent: session-test-device" \
+    -d "{\"email\":\"$TEST_EMAIL\",\"password\":\"userpass123\"}")
+
+SESSIONS_RESPONSE=$(curl -s -X GET http://localhost:8080/api/v1/profile/sessions \
+    -H "Authorization: Bearer $JWT_TOKEN")
+
+echo "Sessions Response: $SESSIONS_RESPONSE"
+SESSION_COUNT=$(echo "$SESSIONS_RESPONSE" | jq '.sessions | length')
+SECOND_SESSION_ID=$(echo "$SESSIONS_RESPONSE" | jq -r '.sessions[] | select(.user_agent == "session-test-device") | .id')
+if [ "$SESSION_COUNT" -ge 2 ] && [ -n "$SECOND_SESSION_ID" ]; then
+    echo "✅ Sessions listed for both logins"
+else
+    echo "❌ Session listing failed"
+    exit 1
+fi
+echo ""
+
+echo "7b. Testing session revocation..."
+REFRESH_TOKEN=$(echo "$LOGIN_RESPONSE" | jq -r '.refresh_token')
+REVOKE_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X DELETE http://localhost:8080/api/v1/profile/sessions/$SECOND_SESSION_ID \
+    -H "Authorization: Bearer $JWT_TOKEN")
+
+REFRESH_STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST http://localhost:8080/api/v1/auth/refresh \
+    -H "Content-Type: application/json" \
+    -d "{\"refresh_token\":\"$REFRESH_TOKEN\"}")
+
+echo "Revoke Status: $REVOKE_STATUS, Refresh After Revoke Status: $REFRESH_STATUS"
+if [ "$REVOKE_STATUS" = "204" ] && [ "$REFRESH_STATUS" = "401" ]; then
+    echo "✅ Revoked session can no longer refresh"
+else
+    echo "❌ Session revocation failed"
+    exit 1
+fi
+echo ""
+
+echo "8. Testing emails are masked in service logs when PII masking is on..."
+if command -v docker > /dev/null 2>&1 && docker inspect user-service > /dev/null 2>&1; then
+    MASK_SETTING=$(docker exec user-service printenv LOG_MASK_PII 2>/dev/null || true)
+    MASK_ENVIRONMENT=$(docker exec user-service printenv ENVIRONMENT 2>/dev/null || echo "development")
+    if [ "$MASK_SETTING" = "true" ] || { [ -z "$MASK_SETTING" ] && [ "$MASK_ENVIRONMENT" != "development" ]; }; then
+        LOG_SINCE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+        MASKED_EMAIL="maskcheck${TIMESTAMP}@example.com"
+        curl -s -o /dev/null -X POST http://localhost:8080/api/v1/auth/register \
+            -H "Content-Type: application/json" \
+            -d "{\"email\":\"$MASKED_EMAIL\",\"password\":\"password123\",\"first_name\":\"Mask\",\"last_name\":\"Check\"}"
+        sleep 1
+        SERVICE_LOGS=$(docker logs --since "$LOG_SINCE" user-service 2>&1; docker logs --since "$LOG_SINCE" risk-engine 2>&1)
+
+        if echo "$SERVICE_LOGS" | grep -q "ma\*\*\*@example.com" && ! echo "$SERVICE_LOGS" | grep -q "$MASKED_EMAIL"; then
+            echo "✅ Emails are masked in service logs"
+        else
+            echo "❌ Raw email found in service logs"
+            exit 1
+        fi
+    else
+        echo "⚠️ PII masking is off (development), skipping log masking check"
+    fi
+else
+    echo "⚠️ user-service container not available, skipping log masking check"
+fi
+echo ""
+
 echo "👤 User management tests completed successfully!"